@@ -0,0 +1,5 @@
+-- 062_users_soft_delete.down.sql
+DROP INDEX IF EXISTS users_deleted_at_idx;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS deleted_email,
+    DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,18 @@
+-- 062_users_soft_delete.up.sql
+-- Track when a user was deleted and keep the original email so an admin can
+-- reactivate the account later.
+--
+-- Deleting a user already only flips is_active and anonymises the email (other
+-- tables reference users, so rows are never removed).
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ NULL,
+    ADD COLUMN IF NOT EXISTS deleted_email TEXT NULL;
+
+-- Backfill previously deleted accounts; their original email is already lost.
+UPDATE users
+SET deleted_at = COALESCE(updated_at, NOW())
+WHERE deleted_at IS NULL
+  AND email LIKE 'deleted-user-%@deleted.local';
+
+CREATE INDEX IF NOT EXISTS users_deleted_at_idx ON users(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUsersSoftDeleteMigrationIsIdempotent(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("062_users_soft_delete.up.sql"))
+	if err != nil {
+		t.Fatalf("read migration: %v", err)
+	}
+	s := string(b)
+
+	checks := []string{
+		"ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ NULL",
+		"ADD COLUMN IF NOT EXISTS deleted_email TEXT NULL",
+		"WHERE deleted_at IS NULL",
+		"CREATE INDEX IF NOT EXISTS users_deleted_at_idx",
+	}
+	for _, check := range checks {
+		if !strings.Contains(s, check) {
+			t.Fatalf("migration missing fragment %q", check)
+		}
+	}
+}
@@ -0,0 +1,2 @@
+-- 063_user_role_events.down.sql
+DROP TABLE IF EXISTS user_role_events;
@@ -0,0 +1,13 @@
+-- 063_user_role_events.up.sql
+-- Audit trail for role changes made through POST /users/:id/role.
+
+CREATE TABLE IF NOT EXISTS user_role_events (
+    id          BIGSERIAL PRIMARY KEY,
+    user_id     INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    actor_id    INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    old_role_id INT NOT NULL,
+    new_role_id INT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS user_role_events_user_idx ON user_role_events(user_id, created_at DESC);
@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUserRoleEventsMigrationCreatesAuditTable(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("063_user_role_events.up.sql"))
+	if err != nil {
+		t.Fatalf("read migration: %v", err)
+	}
+	s := string(b)
+
+	checks := []string{
+		"CREATE TABLE IF NOT EXISTS user_role_events",
+		"actor_id    INT NULL REFERENCES users(id) ON DELETE SET NULL",
+		"old_role_id INT NOT NULL",
+		"new_role_id INT NOT NULL",
+		"CREATE INDEX IF NOT EXISTS user_role_events_user_idx",
+	}
+	for _, check := range checks {
+		if !strings.Contains(s, check) {
+			t.Fatalf("migration missing fragment %q", check)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+-- 064_email_verifications.down.sql
+DROP TABLE IF EXISTS email_verifications;
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
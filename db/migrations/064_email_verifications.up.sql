@@ -0,0 +1,16 @@
+-- 064_email_verifications.up.sql
+-- One-time email confirmation links sent on registration, in addition to the
+-- SMS/OTP flow. Only the sha256 hash of each token is stored.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ NULL;
+
+CREATE TABLE IF NOT EXISTS email_verifications (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash TEXT NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at    TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS email_verifications_user_idx ON email_verifications(user_id, created_at DESC);
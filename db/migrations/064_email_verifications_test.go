@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEmailVerificationsMigrationIsIdempotent(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("064_email_verifications.up.sql"))
+	if err != nil {
+		t.Fatalf("read migration: %v", err)
+	}
+	s := string(b)
+
+	checks := []string{
+		"ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ NULL",
+		"CREATE TABLE IF NOT EXISTS email_verifications",
+		"token_hash TEXT NOT NULL UNIQUE",
+		"used_at    TIMESTAMPTZ NULL",
+		"CREATE INDEX IF NOT EXISTS email_verifications_user_idx",
+	}
+	for _, check := range checks {
+		if !strings.Contains(s, check) {
+			t.Fatalf("migration missing fragment %q", check)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+-- 065_users_locked_until.down.sql
+DROP INDEX IF EXISTS user_verifications_user_sent_idx;
+ALTER TABLE users DROP COLUMN IF EXISTS locked_until;
@@ -0,0 +1,7 @@
+-- 065_users_locked_until.up.sql
+-- Temporary lockout for users who keep burning registration codes. Set by
+-- the verification service once too many codes expire from failed attempts.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ NULL;
+
+CREATE INDEX IF NOT EXISTS user_verifications_user_sent_idx ON user_verifications(user_id, sent_at DESC);
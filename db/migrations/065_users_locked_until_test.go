@@ -0,0 +1,26 @@
+package migrations
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUsersLockedUntilMigrationIsIdempotent(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("065_users_locked_until.up.sql"))
+	if err != nil {
+		t.Fatalf("read migration: %v", err)
+	}
+	s := string(b)
+
+	checks := []string{
+		"ALTER TABLE users ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ NULL",
+		"CREATE INDEX IF NOT EXISTS user_verifications_user_sent_idx",
+	}
+	for _, check := range checks {
+		if !strings.Contains(s, check) {
+			t.Fatalf("migration missing fragment %q", check)
+		}
+	}
+}
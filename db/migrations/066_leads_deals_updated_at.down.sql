@@ -0,0 +1,5 @@
+-- 066_leads_deals_updated_at.down.sql
+DROP INDEX IF EXISTS deals_updated_at_idx;
+DROP INDEX IF EXISTS leads_updated_at_idx;
+ALTER TABLE deals DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE leads DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,16 @@
+-- 066_leads_deals_updated_at.up.sql
+-- Track the last modification time of leads and deals so lists can be sorted
+-- by recency after an edit. Existing rows start with updated_at = created_at.
+
+ALTER TABLE leads ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NULL;
+UPDATE leads SET updated_at = created_at WHERE updated_at IS NULL;
+ALTER TABLE leads ALTER COLUMN updated_at SET DEFAULT NOW();
+ALTER TABLE leads ALTER COLUMN updated_at SET NOT NULL;
+
+ALTER TABLE deals ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NULL;
+UPDATE deals SET updated_at = created_at WHERE updated_at IS NULL;
+ALTER TABLE deals ALTER COLUMN updated_at SET DEFAULT NOW();
+ALTER TABLE deals ALTER COLUMN updated_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS leads_updated_at_idx ON leads(updated_at DESC);
+CREATE INDEX IF NOT EXISTS deals_updated_at_idx ON deals(updated_at DESC);
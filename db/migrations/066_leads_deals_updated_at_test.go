@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLeadsDealsUpdatedAtMigrationIsIdempotent(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("066_leads_deals_updated_at.up.sql"))
+	if err != nil {
+		t.Fatalf("read migration: %v", err)
+	}
+	s := string(b)
+
+	checks := []string{
+		"ALTER TABLE leads ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ",
+		"UPDATE leads SET updated_at = created_at WHERE updated_at IS NULL",
+		"ALTER TABLE deals ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ",
+		"UPDATE deals SET updated_at = created_at WHERE updated_at IS NULL",
+		"CREATE INDEX IF NOT EXISTS leads_updated_at_idx",
+		"CREATE INDEX IF NOT EXISTS deals_updated_at_idx",
+	}
+	for _, check := range checks {
+		if !strings.Contains(s, check) {
+			t.Fatalf("migration missing fragment %q", check)
+		}
+	}
+}
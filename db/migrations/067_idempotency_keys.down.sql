@@ -0,0 +1,3 @@
+-- 067_idempotency_keys.down.sql
+DROP INDEX IF EXISTS idempotency_keys_expires_idx;
+DROP TABLE IF EXISTS idempotency_keys;
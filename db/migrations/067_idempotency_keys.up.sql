@@ -0,0 +1,19 @@
+-- 067_idempotency_keys.up.sql
+-- Idempotency-Key support for create endpoints (POST /tasks, POST /deals).
+-- A key is scoped to the user and the route; the stored response is replayed
+-- for retries until the key expires. response_status stays NULL while the
+-- original request is still in flight.
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    id              BIGSERIAL PRIMARY KEY,
+    user_id         INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    scope           TEXT NOT NULL,
+    idem_key        TEXT NOT NULL,
+    response_status INT NULL,
+    response_body   BYTEA NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at      TIMESTAMPTZ NOT NULL,
+    UNIQUE (user_id, scope, idem_key)
+);
+
+CREATE INDEX IF NOT EXISTS idempotency_keys_expires_idx ON idempotency_keys(expires_at);
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIdempotencyKeysMigrationIsIdempotent(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("067_idempotency_keys.up.sql"))
+	if err != nil {
+		t.Fatalf("read migration: %v", err)
+	}
+	s := string(b)
+
+	checks := []string{
+		"CREATE TABLE IF NOT EXISTS idempotency_keys",
+		"UNIQUE (user_id, scope, idem_key)",
+		"expires_at      TIMESTAMPTZ NOT NULL",
+		"CREATE INDEX IF NOT EXISTS idempotency_keys_expires_idx",
+	}
+	for _, check := range checks {
+		if !strings.Contains(s, check) {
+			t.Fatalf("migration missing fragment %q", check)
+		}
+	}
+}
@@ -0,0 +1,2 @@
+-- 068_tasks_version.down.sql
+ALTER TABLE tasks DROP COLUMN IF EXISTS version;
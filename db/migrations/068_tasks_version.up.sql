@@ -0,0 +1,5 @@
+-- 068_tasks_version.up.sql
+-- Optimistic concurrency for tasks: every write bumps version, and
+-- PUT /tasks/:id only applies when the caller's version still matches.
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
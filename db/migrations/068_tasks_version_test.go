@@ -0,0 +1,18 @@
+package migrations
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTasksVersionMigrationIsIdempotent(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("068_tasks_version.up.sql"))
+	if err != nil {
+		t.Fatalf("read migration: %v", err)
+	}
+	if !strings.Contains(string(b), "ALTER TABLE tasks ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1") {
+		t.Fatalf("migration must add tasks.version idempotently")
+	}
+}
@@ -0,0 +1,2 @@
+-- 069_tasks_reminder_offset.down.sql
+ALTER TABLE tasks DROP COLUMN IF EXISTS reminder_offset_seconds;
@@ -0,0 +1,5 @@
+-- 069_tasks_reminder_offset.up.sql
+-- "Remind me N before due": when reminder_offset_seconds is set, the API
+-- derives reminder_at = due_date - offset and keeps it in step with due_date.
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS reminder_offset_seconds BIGINT;
@@ -0,0 +1,18 @@
+package migrations
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTasksReminderOffsetMigrationIsIdempotent(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("069_tasks_reminder_offset.up.sql"))
+	if err != nil {
+		t.Fatalf("read migration: %v", err)
+	}
+	if !strings.Contains(string(b), "ALTER TABLE tasks ADD COLUMN IF NOT EXISTS reminder_offset_seconds BIGINT") {
+		t.Fatalf("migration must add tasks.reminder_offset_seconds idempotently")
+	}
+}
@@ -0,0 +1,3 @@
+-- 070_webhooks.down.sql
+DROP TABLE IF EXISTS webhook_dead_letters;
+DROP TABLE IF EXISTS webhook_subscriptions;
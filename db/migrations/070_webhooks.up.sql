@@ -0,0 +1,28 @@
+-- 070_webhooks.up.sql
+-- Outbound webhooks: integrators subscribe a URL to task events and receive
+-- signed JSON POSTs. Deliveries that still fail after retries are kept in
+-- webhook_dead_letters for inspection and manual replay.
+-- An empty event_types array subscribes to every event.
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id          BIGSERIAL PRIMARY KEY,
+    url         TEXT NOT NULL,
+    secret      TEXT NOT NULL,
+    event_types TEXT[] NOT NULL DEFAULT '{}',
+    is_active   BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by  INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+    id              BIGSERIAL PRIMARY KEY,
+    subscription_id BIGINT NULL REFERENCES webhook_subscriptions(id) ON DELETE SET NULL,
+    event_type      TEXT NOT NULL,
+    payload         JSONB NOT NULL,
+    attempts        INT NOT NULL,
+    last_error      TEXT NOT NULL DEFAULT '',
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS webhook_dead_letters_subscription_idx
+    ON webhook_dead_letters(subscription_id, created_at DESC);
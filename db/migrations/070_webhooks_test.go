@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWebhooksMigrationIsIdempotent(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("070_webhooks.up.sql"))
+	if err != nil {
+		t.Fatalf("read migration: %v", err)
+	}
+	s := string(b)
+
+	checks := []string{
+		"CREATE TABLE IF NOT EXISTS webhook_subscriptions",
+		"event_types TEXT[] NOT NULL DEFAULT '{}'",
+		"CREATE TABLE IF NOT EXISTS webhook_dead_letters",
+		"CREATE INDEX IF NOT EXISTS webhook_dead_letters_subscription_idx",
+	}
+	for _, check := range checks {
+		if !strings.Contains(s, check) {
+			t.Fatalf("migration missing fragment %q", check)
+		}
+	}
+}
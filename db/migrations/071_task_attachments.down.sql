@@ -0,0 +1,2 @@
+-- 071_task_attachments.down.sql
+DROP TABLE IF EXISTS task_attachments;
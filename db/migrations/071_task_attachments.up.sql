@@ -0,0 +1,17 @@
+-- 071_task_attachments.up.sql
+-- Files attached to tasks (specs, scans). The bytes live in storage under
+-- tasks/<task_id>/; this table keeps the metadata and the storage key.
+
+CREATE TABLE IF NOT EXISTS task_attachments (
+    id          BIGSERIAL PRIMARY KEY,
+    task_id     INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    file_name   TEXT NOT NULL,
+    mime_type   TEXT NOT NULL,
+    size_bytes  BIGINT NOT NULL,
+    storage_key TEXT NOT NULL,
+    uploaded_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS task_attachments_task_idx
+    ON task_attachments(task_id, created_at);
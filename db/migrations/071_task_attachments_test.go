@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTaskAttachmentsMigrationIsIdempotent(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("071_task_attachments.up.sql"))
+	if err != nil {
+		t.Fatalf("read migration: %v", err)
+	}
+	s := string(b)
+
+	checks := []string{
+		"CREATE TABLE IF NOT EXISTS task_attachments",
+		"task_id     INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE",
+		"CREATE INDEX IF NOT EXISTS task_attachments_task_idx",
+	}
+	for _, check := range checks {
+		if !strings.Contains(s, check) {
+			t.Fatalf("migration missing fragment %q", check)
+		}
+	}
+}
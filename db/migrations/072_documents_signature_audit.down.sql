@@ -0,0 +1,3 @@
+-- 072_documents_signature_audit.down.sql
+ALTER TABLE documents DROP COLUMN IF EXISTS signature_hash;
+ALTER TABLE documents DROP COLUMN IF EXISTS signed_by_user_id;
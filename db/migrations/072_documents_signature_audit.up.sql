@@ -0,0 +1,10 @@
+-- 072_documents_signature_audit.up.sql
+-- Compliance details of a manual signature: the user who marked the document
+-- signed and the sha256 of the file at that moment. Shown only to
+-- quality_control (audit), management and admin on GET /documents/:id.
+
+ALTER TABLE documents
+    ADD COLUMN IF NOT EXISTS signed_by_user_id INT NULL REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE documents
+    ADD COLUMN IF NOT EXISTS signature_hash TEXT NULL;
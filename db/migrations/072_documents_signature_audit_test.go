@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDocumentsSignatureAuditMigrationIsIdempotent(t *testing.T) {
+	b, err := os.ReadFile("072_documents_signature_audit.up.sql")
+	if err != nil {
+		t.Fatalf("read migration: %v", err)
+	}
+	s := string(b)
+
+	checks := []string{
+		"ADD COLUMN IF NOT EXISTS signed_by_user_id INT NULL REFERENCES users(id) ON DELETE SET NULL",
+		"ADD COLUMN IF NOT EXISTS signature_hash TEXT NULL",
+	}
+	for _, check := range checks {
+		if !strings.Contains(s, check) {
+			t.Fatalf("migration missing fragment %q", check)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+-- 073_documents_reviewed_by.down.sql
+ALTER TABLE documents DROP COLUMN IF EXISTS reviewed_at;
+ALTER TABLE documents DROP COLUMN IF EXISTS reviewed_by_user_id;
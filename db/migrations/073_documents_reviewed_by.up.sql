@@ -0,0 +1,10 @@
+-- 073_documents_reviewed_by.up.sql
+-- Who approved or returned a document on review, and when. Together with
+-- signed_by_user_id / signed_at (072) this records every manager decision in
+-- the approval workflow.
+
+ALTER TABLE documents
+    ADD COLUMN IF NOT EXISTS reviewed_by_user_id INT NULL REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE documents
+    ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMPTZ NULL;
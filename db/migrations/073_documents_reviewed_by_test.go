@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDocumentsReviewedByMigrationIsIdempotent(t *testing.T) {
+	b, err := os.ReadFile("073_documents_reviewed_by.up.sql")
+	if err != nil {
+		t.Fatalf("read migration: %v", err)
+	}
+	s := string(b)
+
+	checks := []string{
+		"ADD COLUMN IF NOT EXISTS reviewed_by_user_id INT NULL REFERENCES users(id) ON DELETE SET NULL",
+		"ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMPTZ NULL",
+	}
+	for _, check := range checks {
+		if !strings.Contains(s, check) {
+			t.Fatalf("migration missing fragment %q", check)
+		}
+	}
+}
@@ -0,0 +1,2 @@
+-- 074_tasks_last_modified_by.down.sql
+ALTER TABLE tasks DROP COLUMN IF EXISTS last_modified_by;
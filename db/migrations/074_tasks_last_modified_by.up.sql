@@ -0,0 +1,6 @@
+-- 074_tasks_last_modified_by.up.sql
+-- Who last changed a task (edit, status or assignee). creator_id keeps the
+-- author; this is the quick-view counterpart for the latest change.
+
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS last_modified_by INT NULL REFERENCES users(id) ON DELETE SET NULL;
@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestTasksLastModifiedByMigrationIsIdempotent(t *testing.T) {
+	b, err := os.ReadFile("074_tasks_last_modified_by.up.sql")
+	if err != nil {
+		t.Fatalf("read migration: %v", err)
+	}
+	s := string(b)
+
+	check := "ADD COLUMN IF NOT EXISTS last_modified_by INT NULL REFERENCES users(id) ON DELETE SET NULL"
+	if !strings.Contains(s, check) {
+		t.Fatalf("migration missing fragment %q", check)
+	}
+}
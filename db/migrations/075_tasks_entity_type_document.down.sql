@@ -0,0 +1,9 @@
+-- 075_tasks_entity_type_document.down.sql
+-- NOT VALID keeps tasks already linked to documents instead of failing the
+-- rollback; new rows are checked against the old list again.
+ALTER TABLE tasks
+    DROP CONSTRAINT IF EXISTS tasks_entity_type_chk;
+
+ALTER TABLE tasks
+    ADD CONSTRAINT tasks_entity_type_chk
+        CHECK (entity_type IN ('deal', 'lead', 'client')) NOT VALID;
@@ -0,0 +1,10 @@
+-- 075_tasks_entity_type_document.up.sql
+-- Tasks may be linked to a document ("review this contract"), next to deals,
+-- leads and clients: tasks_entity_type_chk is recreated with 'document' added.
+
+ALTER TABLE tasks
+    DROP CONSTRAINT IF EXISTS tasks_entity_type_chk;
+
+ALTER TABLE tasks
+    ADD CONSTRAINT tasks_entity_type_chk
+        CHECK (entity_type IN ('deal', 'lead', 'client', 'document'));
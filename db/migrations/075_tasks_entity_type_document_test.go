@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestTasksEntityTypeDocumentMigrationIsIdempotent(t *testing.T) {
+	b, err := os.ReadFile("075_tasks_entity_type_document.up.sql")
+	if err != nil {
+		t.Fatalf("read migration: %v", err)
+	}
+	s := string(b)
+
+	for _, fragment := range []string{
+		"DROP CONSTRAINT IF EXISTS tasks_entity_type_chk",
+		"CHECK (entity_type IN ('deal', 'lead', 'client', 'document'))",
+	} {
+		if !strings.Contains(s, fragment) {
+			t.Fatalf("migration missing fragment %q", fragment)
+		}
+	}
+}
@@ -0,0 +1,2 @@
+-- 076_users_notify_tasks_email.down.sql
+ALTER TABLE users DROP COLUMN IF EXISTS notify_tasks_email;
@@ -0,0 +1,6 @@
+-- 076_users_notify_tasks_email.up.sql
+-- Opt-in for task notifications by email, used for users without a linked
+-- Telegram chat. Off by default so nobody starts getting mail unasked.
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS notify_tasks_email BOOLEAN NOT NULL DEFAULT FALSE;
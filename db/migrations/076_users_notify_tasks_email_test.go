@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestUsersNotifyTasksEmailMigrationIsIdempotent(t *testing.T) {
+	b, err := os.ReadFile("076_users_notify_tasks_email.up.sql")
+	if err != nil {
+		t.Fatalf("read migration: %v", err)
+	}
+	s := string(b)
+
+	check := "ADD COLUMN IF NOT EXISTS notify_tasks_email BOOLEAN NOT NULL DEFAULT FALSE"
+	if !strings.Contains(s, check) {
+		t.Fatalf("migration missing fragment %q", check)
+	}
+}
@@ -0,0 +1,5 @@
+-- 077_leads_source_unknown.down.sql
+-- The "unknown" backfill is kept: it cannot be told apart from leads that
+-- were explicitly saved with that source.
+DROP INDEX IF EXISTS leads_source_idx;
+ALTER TABLE leads ALTER COLUMN source DROP DEFAULT;
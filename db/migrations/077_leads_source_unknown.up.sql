@@ -0,0 +1,9 @@
+-- 077_leads_source_unknown.up.sql
+-- Lead source attribution: leads created before the source was tracked (or
+-- without one) are marked "unknown", which is also the column default now.
+
+UPDATE leads SET source = 'unknown' WHERE source IS NULL OR source = '';
+
+ALTER TABLE leads ALTER COLUMN source SET DEFAULT 'unknown';
+
+CREATE INDEX IF NOT EXISTS leads_source_idx ON leads(source);
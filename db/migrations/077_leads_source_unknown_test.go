@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLeadsSourceUnknownMigrationIsIdempotent(t *testing.T) {
+	b, err := os.ReadFile("077_leads_source_unknown.up.sql")
+	if err != nil {
+		t.Fatalf("read migration: %v", err)
+	}
+	s := string(b)
+
+	for _, check := range []string{
+		"UPDATE leads SET source = 'unknown' WHERE source IS NULL OR source = ''",
+		"ALTER COLUMN source SET DEFAULT 'unknown'",
+		"CREATE INDEX IF NOT EXISTS leads_source_idx",
+	} {
+		if !strings.Contains(s, check) {
+			t.Fatalf("migration missing fragment %q", check)
+		}
+	}
+}
@@ -0,0 +1,4 @@
+-- 078_leads_score.down.sql
+DROP INDEX IF EXISTS leads_score_idx;
+ALTER TABLE leads DROP CONSTRAINT IF EXISTS leads_score_chk;
+ALTER TABLE leads DROP COLUMN IF EXISTS score;
@@ -0,0 +1,15 @@
+-- 078_leads_score.up.sql
+-- Lead scoring: an optional 0–100 "hotness" score set by sales, used to sort
+-- lead lists hottest-first. NULL means not scored yet.
+
+ALTER TABLE leads
+    ADD COLUMN IF NOT EXISTS score SMALLINT NULL;
+
+ALTER TABLE leads
+    DROP CONSTRAINT IF EXISTS leads_score_chk;
+
+ALTER TABLE leads
+    ADD CONSTRAINT leads_score_chk
+        CHECK (score IS NULL OR score BETWEEN 0 AND 100);
+
+CREATE INDEX IF NOT EXISTS leads_score_idx ON leads(score);
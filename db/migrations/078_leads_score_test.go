@@ -0,0 +1,26 @@
+package migrations
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLeadsScoreMigrationIsIdempotent(t *testing.T) {
+	b, err := os.ReadFile("078_leads_score.up.sql")
+	if err != nil {
+		t.Fatalf("read migration: %v", err)
+	}
+	s := string(b)
+
+	for _, check := range []string{
+		"ADD COLUMN IF NOT EXISTS score SMALLINT NULL",
+		"DROP CONSTRAINT IF EXISTS leads_score_chk",
+		"CHECK (score IS NULL OR score BETWEEN 0 AND 100)",
+		"CREATE INDEX IF NOT EXISTS leads_score_idx",
+	} {
+		if !strings.Contains(s, check) {
+			t.Fatalf("migration missing fragment %q", check)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+// Package migrations holds the versioned SQL schema. The up files are embedded
+// so the web binary can apply them itself (see internal/migrate).
+package migrations
+
+import "embed"
+
+//go:embed *.up.sql
+var Files embed.FS
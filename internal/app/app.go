@@ -2,12 +2,17 @@ package app
 
 import (
 	"context"
+	"crypto/rsa"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 	"turcompany/internal/docx"
 	binotelclient "turcompany/internal/integrations/binotel"
@@ -17,10 +22,12 @@ import (
 	"github.com/gin-gonic/gin"
 	_ "github.com/lib/pq"
 
+	"turcompany/db/migrations"
 	"turcompany/internal/audit"
 	"turcompany/internal/config"
 	"turcompany/internal/handlers"
 	"turcompany/internal/middleware"
+	"turcompany/internal/migrate"
 	"turcompany/internal/pdf"
 	"turcompany/internal/realtime"
 	"turcompany/internal/repositories"
@@ -43,7 +50,7 @@ func Run() {
 		log.Fatalf("[BOOT] failed to load config: %v", err)
 	}
 	if err := cfg.Validate(); err != nil {
-		log.Fatalf("[BOOT] invalid config: %v", err)
+		log.Fatalf("[BOOT] invalid config:\n%v", err)
 	}
 	log.Printf("[BOOT] build.commit=%s build.time=%s", BuildCommit, BuildTime)
 	log.Printf("[BOOT] starting backend...")
@@ -54,6 +61,7 @@ func Run() {
 		log.Printf("[BOOT] config: telegram.webhook_url is empty")
 	}
 	log.Printf("[BOOT] config: db=%s", utils.MaskDSN(cfg.Database.DSN))
+	utils.SetAllowedRedirectOrigins(cfg.RedirectAllowlist())
 	// timezone for signing flow and debug output
 	var serverTZ *time.Location
 	if tz := strings.TrimSpace(cfg.Server.TZ); tz != "" {
@@ -116,9 +124,9 @@ func Run() {
 	if err != nil {
 		log.Fatal("[BOOT] Ошибка подключения к БД: ", err)
 	}
-	db.SetMaxOpenConns(20)
-	db.SetMaxIdleConns(10)
-	db.SetConnMaxLifetime(30 * time.Minute)
+	db.SetMaxOpenConns(cfg.Database.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.Database.MaxIdleConns)
+	db.SetConnMaxLifetime(time.Duration(cfg.Database.ConnMaxLifetimeMinutes) * time.Minute)
 
 	{
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -132,6 +140,13 @@ func Run() {
 			log.Printf("[BOOT] Ошибка закрытия БД: %v", err)
 		}
 	}()
+	if cfg.Database.AutoMigrate {
+		applied, err := migrate.Apply(context.Background(), db, migrations.Files)
+		if err != nil {
+			log.Fatalf("[BOOT] migrations failed: %v", err)
+		}
+		log.Printf("[BOOT] migrations: applied=%d", len(applied))
+	}
 
 	// === Repos ===
 	roleRepo := repositories.NewRoleRepository(db)
@@ -162,7 +177,18 @@ func Run() {
 	// === Services (общие) ===
 	accessTokenTTL := readDurationEnv("ACCESS_TOKEN_TTL", 2*time.Hour)
 	log.Printf("[BOOT] auth.access_token_ttl=%s (env ACCESS_TOKEN_TTL)", accessTokenTTL)
-	authService := services.NewAuthService(jwtSecret, nil, accessTokenTTL, 30*24*time.Hour, nil)
+	authService := services.NewAuthService(jwtSecret, nil, accessTokenTTL, 30*24*time.Hour, cfg.Auth.BcryptCost, nil)
+	authService.SetIssuer(cfg.Security.JWTIssuer)
+	var jwtPublicKey *rsa.PublicKey
+	if cfg.Security.JWTAlgorithm == "RS256" {
+		privKey, pubKey, err := services.LoadRSAKeyPair(cfg.Security.JWTPrivateKeyFile, cfg.Security.JWTPublicKeyFile)
+		if err != nil {
+			log.Fatalf("[BOOT] JWT RS256 keys: %v", err)
+		}
+		authService.UseRS256(privKey)
+		jwtPublicKey = pubKey
+	}
+	log.Printf("[BOOT] auth.jwt_algorithm=%s", cfg.Security.JWTAlgorithm)
 	emailService := services.NewEmailService(
 		cfg.Email.SMTPHost,
 		cfg.Email.SMTPPort,
@@ -172,13 +198,15 @@ func Run() {
 		cfg.Email.FromName,
 	)
 	smsSender := services.NewMobizonSMSClient(services.MobizonSMSConfig{
-		Enabled: cfg.Mobizon.Enabled,
-		APIKey:  cfg.Mobizon.APIKey,
-		BaseURL: cfg.Mobizon.BaseURL,
-		From:    cfg.Mobizon.From,
-		Timeout: time.Duration(cfg.Mobizon.TimeoutSeconds) * time.Second,
-		Retries: cfg.Mobizon.Retries,
-		DryRun:  cfg.Mobizon.DryRun,
+		Enabled:    cfg.Mobizon.Enabled,
+		APIKey:     cfg.Mobizon.APIKey,
+		BaseURL:    cfg.Mobizon.BaseURL,
+		From:       cfg.Mobizon.From,
+		TextPrefix: cfg.Mobizon.TextPrefix,
+		Timeout:    time.Duration(cfg.Mobizon.TimeoutSeconds) * time.Second,
+		Retries:    cfg.Mobizon.Retries,
+		RetryDelay: time.Duration(cfg.Mobizon.RetryDelayMS) * time.Millisecond,
+		DryRun:     cfg.Mobizon.DryRun,
 	})
 	log.Printf(
 		"[BOOT] config: mobizon.enabled=%v api_url=%s from_set=%v api_key_set=%v timeout_s=%d retries=%d dry_run=%v",
@@ -229,6 +257,11 @@ func Run() {
 	if cfg.Telegram.Enable && cfg.Telegram.BotToken != "" {
 		log.Printf("[BOOT] Telegram enabled: true (token len=%d)", len(cfg.Telegram.BotToken))
 		tgSvc = services.NewTelegramService(cfg.Telegram.BotToken, teleLinkRepo, userRepo, nil, cfg.Frontend.Host)
+		tgSvc.SetHTTPOptions(
+			time.Duration(cfg.Telegram.RequestTimeoutSec)*time.Second,
+			cfg.Telegram.RetryCount,
+			time.Duration(cfg.Telegram.RetryDelayMS)*time.Millisecond,
+		)
 
 		if cfg.Telegram.WebhookURL != "" {
 			log.Printf("[BOOT] setting Telegram webhook -> %s", cfg.Telegram.WebhookURL)
@@ -246,7 +279,7 @@ func Run() {
 
 	orgService := services.NewOrganizationService(orgRepo)
 
-	roleService := services.NewRoleService(roleRepo)
+	roleService := services.NewRoleService(roleRepo, userRepo)
 	permissionService := services.NewPermissionService(permissionRepo)
 	funnelService := services.NewFunnelService(funnelRepo, permissionRepo)
 	funnelStageService := services.NewFunnelStageService(funnelStageRepo, funnelRepo, permissionRepo)
@@ -266,10 +299,15 @@ func Run() {
 	dealService.SetScopeDeps(leadRepo, userRepo)
 	dealService.SetStageRepo(funnelStageRepo)
 	dealService.SetTransitionRuleRepo(funnelTransitionRuleRepo)
+	dealService.SetAllowedCurrencies(cfg.Deals.Currencies)
+	leadService.SetAllowedCurrencies(cfg.Deals.Currencies)
 	chatService := services.NewChatService(chatRepo, cfg.Files.RootDir, userRepo, fileStore)
-	passwordResetService := services.NewPasswordResetService(userRepo, passwordResetRepo, emailService, smsSender, authService, cfg.Frontend.Host)
+	passwordResetService := services.NewPasswordResetService(userRepo, passwordResetRepo, emailService, smsSender, authService, cfg.Frontend.Host, time.Duration(cfg.Auth.PasswordResetTTLMinutes)*time.Minute)
 
-	pdfGen := pdf.NewDocumentGenerator(cfg.Files.RootDir, cfg.Templates.TxtDir, "assets/fonts/DejaVuSans.ttf")
+	pdfGen := pdf.NewDocumentGenerator(cfg.Files.RootDir, cfg.Templates.TxtDir, pdf.FontConfig{
+		RegularPath: cfg.PDF.FontPath,
+		BoldPath:    cfg.PDF.BoldFontPath,
+	})
 
 	docxGen := docx.NewDocxGenerator(
 		cfg.Files.RootDir,
@@ -296,14 +334,16 @@ func Run() {
 	documentService.SetUserRepo(userRepo)
 	documentService.SetTimeProvider(nowProvider, serverTZ)
 	documentService.SetStore(fileStore)
+	documentService.SetSMSSignatureRequirement(signatureConfirmRepo, cfg.Documents.RequireSMSSignature)
 
 	clientAvatarHandler := handlers.NewClientAvatarHandler(clientService, clientRepo, cfg.Files.RootDir, fileStore)
 	clientDocsHandler := handlers.NewClientDocumentsHandler(documentService, clientRepo, documentRepo)
 
 	documentVersionRepo := repositories.NewDocumentVersionRepository(db)
+	documentService.SetVersionRepo(documentVersionRepo)
 	docVersionHandler := handlers.NewDocumentVersionHandler(documentRepo, documentVersionRepo, documentService, cfg.Files.RootDir, fileStore)
 
-	taskService := services.NewTaskService(taskRepo, userRepo, tgSvc)
+	taskService := services.NewTaskService(taskRepo, userRepo)
 	if tgSvc != nil {
 		tgSvc.SetTaskService(taskService)
 	}
@@ -366,13 +406,25 @@ func Run() {
 
 	// Reports
 	reportService := services.NewReportService(leadRepo, dealRepo, userRepo)
-
+	reportService.SetSummaryCacheTTL(time.Duration(cfg.Reports.SummaryCacheTTLSeconds) * time.Second)
+
+	realtime.SetAllowedOrigins(cfg.CORS.AllowOrigins)
+	realtime.SetMaxFrameSize(int64(cfg.Chat.MaxFrameBytes))
+	realtime.SetConnOptions(realtime.ConnOptions{
+		HandshakeTimeout: time.Duration(cfg.Chat.HandshakeTimeoutSeconds) * time.Second,
+		ReadTimeout:      time.Duration(cfg.Chat.ReadTimeoutSeconds) * time.Second,
+		ReadBufferSize:   cfg.Chat.ReadBufferBytes,
+	})
 	chatHub := realtime.NewChatHub(chatRepo)
+	chatHub.SetConnectionLimits(cfg.Chat.MaxConnectionsPerUser, cfg.Chat.MaxConnectionsTotal)
 	go chatHub.Run()
 	defer chatHub.Stop()
 
 	// === Handlers ===
 	authHandler := handlers.NewAuthHandler(userService, authService, passwordResetService)
+	tokenRevocations := services.NewTokenRevocationList()
+	authHandler.SetTokenRevocations(tokenRevocations)
+	authHandler.SetRoleLookup(roleService)
 	roleHandler := handlers.NewRoleHandler(roleService)
 	permissionHandler := handlers.NewPermissionHandler(permissionService)
 	funnelHandler := handlers.NewFunnelHandler(funnelService)
@@ -386,7 +438,9 @@ func Run() {
 	leadHandler := handlers.NewLeadHandler(leadService)
 	dealHandler := handlers.NewDealHandler(dealService)
 	documentHandler := handlers.NewDocumentHandler(documentService, fileStore)
+	documentHandler.SetPublicBaseURL(cfg.PublicBaseURL)
 	chatHandler := handlers.NewChatHandler(chatService, chatHub)
+	chatHandler.SetStreamTickets(realtime.NewTicketStore(time.Duration(cfg.Chat.WSTicketTTLSeconds) * time.Second))
 	signConfirmHandler := handlers.NewDocumentSigningConfirmationHandler(
 		signConfirmService,
 		documentService,
@@ -396,8 +450,21 @@ func Run() {
 	telegramSignHandler := handlers.NewTelegramSignWebhookHandler(tgSvc, signConfirmService)
 
 	taskHandler := handlers.NewTaskHandler(taskService, tgSvc, userRepo)
+	taskHandler.SetEntityResolvers(leadService, dealService, clientService)
+	taskHandler.SetDocumentResolver(documentService)
+	userNotifications := repositories.NewUserNotificationRepository(db)
+	taskHandler.SetEmailNotifier(emailService, userNotifications)
+	taskHandler.SetTimezone(serverTZ)
+	taskHandler.SetAttachmentService(services.NewTaskAttachmentService(repositories.NewTaskAttachmentRepository(db), fileStore))
+
+	// Исходящие вебхуки о событиях задач (подписки управляются админом)
+	webhookSvc := services.NewWebhookService(repositories.NewWebhookRepository(db), nil)
+	taskHandler.SetEventPublisher(webhookSvc)
+	webhookHandler := handlers.NewWebhookHandler(webhookSvc)
 
 	verifyHandler := handlers.NewVerifyHandler(userVerificationService)
+	emailVerificationService := services.NewEmailVerificationService(repositories.NewEmailVerificationRepository(db), emailService, cfg.PublicBaseURL, nowProvider)
+	verifyHandler.SetEmailVerificationService(emailVerificationService)
 	signHandler := handlers.NewSignSessionHandler(signSessionService)
 	publicSignHandler := handlers.NewPublicDocumentSigningHandler(publicSignService)
 	docPublicLinkHandler := handlers.NewDocumentPublicLinkHandler(publicSignService)
@@ -408,6 +475,7 @@ func Run() {
 	reportHandler := handlers.NewReportHandler(reportService)
 	orgHandler := handlers.NewOrganizationHandler(orgService)
 	signHistoryHandler := handlers.NewDocumentSignHistoryHandler(documentService, signSessionRepo, signatureConfirmRepo)
+	signHistoryHandler.SetSMSStatusChecker(smsSender)
 	if cfg.Wazzup.Enable {
 		wazzupClient := wazzupintegration.NewHTTPClient(
 			cfg.Wazzup.APIBaseURL,
@@ -446,7 +514,7 @@ func Run() {
 
 	// === Gin ===
 	router := gin.New()
-	router.Use(gin.Logger(), gin.Recovery(), corsMiddleware(cfg))
+	router.Use(gin.Logger(), gin.Recovery(), corsMiddleware(cfg), middleware.MaxBodyBytes(int64(cfg.Server.MaxBodyBytes), int64(cfg.Server.MaxUploadBytes)), middleware.RequestTimeout(time.Duration(cfg.Server.RequestTimeoutSeconds)*time.Second))
 
 	auditRepo := repositories.NewAuditRepository(db)
 	if ok, err := auditRepo.AuditTableExists(context.Background()); err != nil {
@@ -462,11 +530,29 @@ func Run() {
 	approvalSvc := services.NewUserApprovalService(userApprovalRepo, userService, authService, auditSvc)
 	approvalHandler := handlers.NewUserApprovalHandler(approvalSvc)
 	userHandler.SetApprovalService(approvalSvc)
+	userHandler.SetRoleService(services.NewUserRoleService(repositories.NewUserRoleEventRepository(db)))
+	userHandler.SetEmailVerificationService(emailVerificationService)
+	userHandler.SetWelcomeEmailService(services.NewWelcomeEmailService(userRepo, emailService, nowProvider))
+	userHandler.SetNotificationSettings(userNotifications)
 
 	feedEventRepo := repositories.NewFeedEventRepository(db)
 	feedEventSvc := services.NewFeedEventService(feedEventRepo, userRepo, clientService, leadService, dealService, documentService)
 	feedEventHandler := handlers.NewFeedEventHandler(feedEventSvc)
 
+	idempotencySvc := services.NewIdempotencyService(repositories.NewIdempotencyRepository(db), nil)
+	go func(svc *services.IdempotencyService) {
+		for {
+			time.Sleep(time.Hour)
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			if n, err := svc.PurgeExpired(ctx); err != nil {
+				log.Printf("[idempotency] purge expired keys error: %v", err)
+			} else if n > 0 {
+				log.Printf("[idempotency] purged %d expired keys", n)
+			}
+			cancel()
+		}
+	}(idempotencySvc)
+
 	// === Routes ===
 	log.Printf("[BOOT] mounting routes...")
 	routes.SetupRoutes(
@@ -506,17 +592,50 @@ func Run() {
 		feedHandler,
 		approvalHandler,
 		feedEventHandler,
-		middleware.NewAuthMiddleware(jwtSecret),
+		webhookHandler,
+		middleware.Idempotency(idempotencySvc),
+		middleware.MaxUploadBytes(int64(cfg.Server.MaxUploadBytes)),
+		routes.RateLimits{
+			SMS:       middleware.RateLimit(cfg.RateLimit.SMS.Limit, time.Duration(cfg.RateLimit.SMS.WindowSeconds)*time.Second),
+			Documents: middleware.RateLimit(cfg.RateLimit.Documents.Limit, time.Duration(cfg.RateLimit.Documents.WindowSeconds)*time.Second),
+		},
+		middleware.NewAuthMiddlewareWithOptions(jwtSecret, middleware.AuthOptions{
+			Issuer:      cfg.Security.JWTIssuer,
+			Revocations: tokenRevocations,
+			PublicKey:   jwtPublicKey,
+		}),
 	)
 	log.Printf("[BOOT] routes mounted. Starting server...")
 
 	addr := fmt.Sprintf(":%d", cfg.Server.Port)
 	log.Printf("[BOOT] HTTP listen on %s", addr)
-	if err := router.Run(addr); err != nil {
-		log.Fatal("[BOOT] Ошибка запуска сервера: ", err)
+	srv := &http.Server{Addr: addr, Handler: router.Handler()}
+	stopCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("[BOOT] Ошибка запуска сервера: ", err)
+		}
+	}()
+	<-stopCtx.Done()
+
+	// Сначала перестаём принимать запросы, затем дожидаемся доставки
+	// уже опубликованных вебхуков.
+	log.Printf("[SHUTDOWN] signal received, stopping HTTP server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("[SHUTDOWN] HTTP server: %v", err)
+	}
+	if err := webhookSvc.Shutdown(shutdownCtx); err != nil {
+		log.Printf("[SHUTDOWN] webhook deliveries still in flight: %v", err)
 	}
 }
 
+// shutdownTimeout bounds how long SIGTERM waits for in-flight requests and
+// webhook deliveries.
+const shutdownTimeout = 30 * time.Second
+
 func readDurationEnv(name string, fallback time.Duration) time.Duration {
 	raw := strings.TrimSpace(os.Getenv(name))
 	if raw == "" {
@@ -1,17 +1,28 @@
 package audit
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"turcompany/internal/services"
 )
 
+// auditWriteTimeout bounds the audit insert, which runs detached from the
+// request context: requests that hit the request timeout or whose client went
+// away still get their audit row.
+const auditWriteTimeout = 5 * time.Second
+
 func AuditMiddleware(svc *services.AuditService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
+		body := captureJSONBody(c)
 
 		c.Next()
 
@@ -34,7 +45,9 @@ func AuditMiddleware(svc *services.AuditService) gin.HandlerFunc {
 		ip := c.ClientIP()
 		ua := c.GetHeader("User-Agent")
 
-		svc.Log(c.Request.Context(), services.AuditEvent{
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), auditWriteTimeout)
+		defer cancel()
+		svc.Log(ctx, services.AuditEvent{
 			ActorUserID: actorID,
 			ActorRoleID: actorRoleID,
 			Action:      fmt.Sprintf("http.%s %s", c.Request.Method, path),
@@ -42,14 +55,113 @@ func AuditMiddleware(svc *services.AuditService) gin.HandlerFunc {
 			EntityID:    "",
 			IP:          &ip,
 			UserAgent:   &ua,
-			Meta: map[string]any{
-				"status":     c.Writer.Status(),
-				"durationMs": time.Since(start).Milliseconds(),
-			},
+			Meta:        requestMeta(c.Writer.Status(), time.Since(start), body),
 		})
 	}
 }
 
+// auditBodyLimit caps how much of a request body is kept for the summary.
+const auditBodyLimit = 8 << 10
+
+// auditSummaryMaxString caps string values stored in the body summary.
+const auditSummaryMaxString = 200
+
+// sensitiveKeyParts mark body fields whose values are never stored. "code"
+// and "pin" cover the one-time codes of /register/confirm and the email/SMS
+// signing confirmations; over-redacting e.g. a country_code is acceptable.
+var sensitiveKeyParts = []string{"password", "token", "secret", "api_key", "apikey", "otp", "code", "pin"}
+
+// capturedBody is the prefix of a JSON request body read before the handler.
+type capturedBody struct {
+	data      []byte
+	truncated bool
+}
+
+// captureJSONBody reads up to auditBodyLimit bytes of a JSON body of a
+// mutating request and puts them back in front of the rest of the body, so
+// the handler still sees the full stream.
+func captureJSONBody(c *gin.Context) *capturedBody {
+	switch c.Request.Method {
+	case "GET", "HEAD", "OPTIONS":
+		return nil
+	}
+	if c.Request.Body == nil || !strings.Contains(strings.ToLower(c.ContentType()), "json") {
+		return nil
+	}
+	prefix, err := io.ReadAll(io.LimitReader(c.Request.Body, auditBodyLimit+1))
+	c.Request.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(prefix), c.Request.Body), Closer: c.Request.Body}
+	if err != nil {
+		return nil
+	}
+	if len(prefix) > auditBodyLimit {
+		return &capturedBody{truncated: true}
+	}
+	return &capturedBody{data: prefix}
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func requestMeta(status int, elapsed time.Duration, body *capturedBody) map[string]any {
+	meta := map[string]any{
+		"status":     status,
+		"durationMs": elapsed.Milliseconds(),
+	}
+	switch {
+	case body == nil:
+	case body.truncated:
+		meta["body_truncated"] = true
+	case len(bytes.TrimSpace(body.data)) > 0:
+		var parsed any
+		if err := json.Unmarshal(body.data, &parsed); err == nil {
+			meta["body"] = redactBody(parsed)
+		}
+	}
+	return meta
+}
+
+// redactBody replaces the values of sensitive fields with "[REDACTED]" and
+// shortens long strings, recursing into objects and arrays.
+func redactBody(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, val := range t {
+			if isSensitiveKey(k) {
+				out[k] = "[REDACTED]"
+				continue
+			}
+			out[k] = redactBody(val)
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, val := range t {
+			out[i] = redactBody(val)
+		}
+		return out
+	case string:
+		if r := []rune(t); len(r) > auditSummaryMaxString {
+			return string(r[:auditSummaryMaxString]) + "…"
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+func isSensitiveKey(key string) bool {
+	k := strings.ToLower(key)
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(k, part) {
+			return true
+		}
+	}
+	return false
+}
+
 func actorFromContext(c *gin.Context) *int {
 	keys := []string{"user_id", "userID", "userId", "uid"}
 	for _, k := range keys {
@@ -0,0 +1,209 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/repositories"
+	"turcompany/internal/services"
+)
+
+type auditInsert struct {
+	actor  *int
+	action string
+	meta   map[string]any
+	ctxErr error
+}
+
+type recordingAuditStore struct {
+	rows []auditInsert
+}
+
+func (s *recordingAuditStore) Insert(ctx context.Context, actor *int, action, _, _ string, _, _ *string, metaJSON string, _ bool) error {
+	var meta map[string]any
+	_ = json.Unmarshal([]byte(metaJSON), &meta)
+	s.rows = append(s.rows, auditInsert{actor: actor, action: action, meta: meta, ctxErr: ctx.Err()})
+	return nil
+}
+
+func (s *recordingAuditStore) List(context.Context, int, int, repositories.AuditListFilter) ([]*repositories.AuditLogEntry, error) {
+	return nil, nil
+}
+
+func newAuditedTaskRouter(store *recordingAuditStore, gotTitle *string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(AuditMiddleware(services.NewAuditService(store)))
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", 42)
+		c.Set("role_id", authz.RoleManagement)
+		c.Next()
+	})
+	r.POST("/tasks", func(c *gin.Context) {
+		var body struct {
+			Title string `json:"title"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		*gotTitle = body.Title
+		c.Status(http.StatusCreated)
+	})
+	r.GET("/tasks", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func TestAuditMiddleware_TaskCreationRecordsActorPathAndRedactedBody(t *testing.T) {
+	store := &recordingAuditStore{}
+	var gotTitle string
+	r := newAuditedTaskRouter(store, &gotTitle)
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(`{"title":"Call client","access_token":"abc","meta":{"password":"p4ss"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", w.Code)
+	}
+	if gotTitle != "Call client" {
+		t.Fatalf("handler saw title %q; the middleware must leave the body readable", gotTitle)
+	}
+	if len(store.rows) != 1 {
+		t.Fatalf("expected 1 audit row, got %d", len(store.rows))
+	}
+	row := store.rows[0]
+	if row.actor == nil || *row.actor != 42 {
+		t.Fatalf("actor = %v, want 42", row.actor)
+	}
+	if row.action != "http.POST /tasks" {
+		t.Fatalf("action = %q, want %q", row.action, "http.POST /tasks")
+	}
+	if row.meta["status"] != float64(http.StatusCreated) {
+		t.Fatalf("meta.status = %v, want 201", row.meta["status"])
+	}
+	body, _ := row.meta["body"].(map[string]any)
+	if body["title"] != "Call client" || body["access_token"] != "[REDACTED]" {
+		t.Fatalf("unexpected body summary: %v", row.meta["body"])
+	}
+	if nested, _ := body["meta"].(map[string]any); nested["password"] != "[REDACTED]" {
+		t.Fatalf("nested password not redacted: %v", body["meta"])
+	}
+}
+
+func TestAuditMiddleware_RedactsOneTimeCodes(t *testing.T) {
+	store := &recordingAuditStore{}
+	var gotTitle string
+	r := newAuditedTaskRouter(store, &gotTitle)
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(`{"code":"123456","sms_code":"4321","pin":"0000","title":"x"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	if len(store.rows) != 1 {
+		t.Fatalf("expected 1 audit row, got %d", len(store.rows))
+	}
+	body, _ := store.rows[0].meta["body"].(map[string]any)
+	for _, key := range []string{"code", "sms_code", "pin"} {
+		if body[key] != "[REDACTED]" {
+			t.Fatalf("%s not redacted: %v", key, body)
+		}
+	}
+	if body["title"] != "x" {
+		t.Fatalf("title should be kept: %v", body)
+	}
+}
+
+func TestAuditMiddleware_SkipsReadsAndNonJSONBodies(t *testing.T) {
+	store := &recordingAuditStore{}
+	var gotTitle string
+	r := newAuditedTaskRouter(store, &gotTitle)
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/tasks", nil))
+	if len(store.rows) != 0 {
+		t.Fatalf("GET must not be audited, got %d rows", len(store.rows))
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader("title=x"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	if len(store.rows) != 1 {
+		t.Fatalf("expected 1 audit row, got %d", len(store.rows))
+	}
+	if _, ok := store.rows[0].meta["body"]; ok {
+		t.Fatalf("non-JSON body must not be summarized: %v", store.rows[0].meta)
+	}
+}
+
+func TestCaptureJSONBody_LargeBodyStaysIntact(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	payload := `{"text":"` + strings.Repeat("x", auditBodyLimit) + `"}`
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(payload))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	captured := captureJSONBody(c)
+	if captured == nil || !captured.truncated {
+		t.Fatalf("expected truncated capture, got %+v", captured)
+	}
+	rest, err := io.ReadAll(c.Request.Body)
+	if err != nil || string(rest) != payload {
+		t.Fatalf("body changed after capture (len %d, err %v)", len(rest), err)
+	}
+}
+
+func TestAuditMiddleware_WritesRowAfterRequestContextIsCancelled(t *testing.T) {
+	store := &recordingAuditStore{}
+	var gotTitle string
+	r := newAuditedTaskRouter(store, &gotTitle)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(`{"title":"Call client"}`)).WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	if len(store.rows) != 1 {
+		t.Fatalf("expected one audit row, got %d", len(store.rows))
+	}
+	if err := store.rows[0].ctxErr; err != nil {
+		t.Fatalf("audit insert must not inherit the request cancellation, got %v", err)
+	}
+}
+
+func TestAuditMiddleware_ConfirmationCodeNeverReachesMeta(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := &recordingAuditStore{}
+	r := gin.New()
+	r.Use(AuditMiddleware(services.NewAuditService(store)))
+	r.POST("/register/confirm", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, payload := range []string{`{"code":"123456"}`, `{"confirmation":{"code":"123456"}}`, `[{"code":"123456"}]`} {
+		req := httptest.NewRequest(http.MethodPost, "/register/confirm", strings.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	if len(store.rows) != 3 {
+		t.Fatalf("expected 3 audit rows, got %d", len(store.rows))
+	}
+	for _, row := range store.rows {
+		raw, _ := json.Marshal(row.meta)
+		if strings.Contains(string(raw), "123456") {
+			t.Fatalf("confirmation code stored in audit meta: %s", raw)
+		}
+		if !strings.Contains(string(raw), "[REDACTED]") {
+			t.Fatalf("expected a redacted body in audit meta: %s", raw)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package authz
+
+import "slices"
+
+// Capability names something a role may do regardless of the resource
+// scope. It complements the action/scope permissions in permissions.go for
+// the checks handlers used to spell out as role comparisons.
+type Capability string
+
+const (
+	CapAccessTasks           Capability = "tasks.access"
+	CapAssignTasksToOthers   Capability = "tasks.assign_others"
+	CapViewOthersAgenda      Capability = "tasks.view_others_agenda"
+	CapReopenTasks           Capability = "tasks.reopen"
+	CapDeleteDocument        Capability = "documents.delete"
+	CapViewHiddenDocuments   Capability = "documents.view_hidden"
+	CapUploadScopedDocuments Capability = "documents.upload_scoped"
+	CapViewSigningMetadata   Capability = "documents.view_signing_metadata"
+	CapManageFunnels         Capability = "funnels.manage"
+	CapAssignRoles           Capability = "roles.assign"
+)
+
+// capabilityRoles is the single place to change who may do what. A
+// capability missing from the table is granted to nobody.
+var capabilityRoles = map[Capability][]int{
+	CapAccessTasks: {RoleSales, RoleControl, RoleManagement, RoleSystemAdmin, RoleVisa, RolePartner, RoleHR, RoleLegal},
+	// Sales may only assign tasks to themselves.
+	CapAssignTasksToOthers: {RoleControl, RoleManagement, RoleSystemAdmin, RoleVisa, RolePartner, RoleHR, RoleLegal},
+	CapViewOthersAgenda:    {RoleManagement, RoleSystemAdmin},
+	// Moving a done or cancelled task back to in_progress.
+	CapReopenTasks:    {RoleManagement, RoleSystemAdmin},
+	CapDeleteDocument: {RoleSystemAdmin},
+	// Documents with restricted visibility are listed in full only for admins.
+	CapViewHiddenDocuments:   {RoleSystemAdmin},
+	CapUploadScopedDocuments: {RoleHR, RoleLegal, RoleManagement, RoleSystemAdmin},
+	// quality_control (audit), management and admin.
+	CapViewSigningMetadata: {RoleControl, RoleManagement, RoleSystemAdmin},
+	// Management may view funnels but not edit, add, delete or reorder them.
+	CapManageFunnels: {RoleSystemAdmin},
+	CapAssignRoles:   {RoleSystemAdmin},
+}
+
+// HasCapability reports whether roleID is granted capability c.
+func HasCapability(roleID int, c Capability) bool {
+	return slices.Contains(capabilityRoles[c], roleID)
+}
+
+// RolesWithCapability lists the roles granted c, e.g. for RequireRoles.
+func RolesWithCapability(c Capability) []int {
+	return slices.Clone(capabilityRoles[c])
+}
@@ -0,0 +1,54 @@
+package authz
+
+import "testing"
+
+func TestHasCapability(t *testing.T) {
+	cases := []struct {
+		roleID int
+		cap    Capability
+		want   bool
+	}{
+		{RoleSystemAdmin, CapDeleteDocument, true},
+		{RoleManagement, CapDeleteDocument, false},
+		{RoleSales, CapAssignTasksToOthers, false},
+		{RoleVisa, CapAssignTasksToOthers, true},
+		{RoleManagement, CapViewOthersAgenda, true},
+		{RoleControl, CapViewOthersAgenda, false},
+		{RoleManagement, CapReopenTasks, true},
+		{RoleVisa, CapReopenTasks, false},
+		{RoleControl, CapViewSigningMetadata, true},
+		{RoleSales, CapViewSigningMetadata, false},
+		{RoleLegal, CapUploadScopedDocuments, true},
+		{RolePartner, CapUploadScopedDocuments, false},
+		{RoleManagement, CapManageFunnels, false},
+		{999, CapAccessTasks, false},
+		{RoleSystemAdmin, Capability("unknown"), false},
+	}
+	for _, tc := range cases {
+		if got := HasCapability(tc.roleID, tc.cap); got != tc.want {
+			t.Errorf("HasCapability(%d, %q) = %v, want %v", tc.roleID, tc.cap, got, tc.want)
+		}
+	}
+}
+
+func TestCapabilityHelpersFollowTable(t *testing.T) {
+	for roleID := range Roles {
+		if CanAccessTasks(roleID) != HasCapability(roleID, CapAccessTasks) {
+			t.Errorf("CanAccessTasks(%d) disagrees with the table", roleID)
+		}
+		if CanViewSigningMetadata(roleID) != IsElevated(roleID) {
+			t.Errorf("signing metadata must stay with elevated roles, role %d differs", roleID)
+		}
+	}
+}
+
+func TestRolesWithCapabilityReturnsCopy(t *testing.T) {
+	roles := RolesWithCapability(CapManageFunnels)
+	if len(roles) != 1 || roles[0] != RoleSystemAdmin {
+		t.Fatalf("unexpected roles: %v", roles)
+	}
+	roles[0] = RoleSales
+	if HasCapability(RoleSales, CapManageFunnels) {
+		t.Fatal("mutating the result must not change the table")
+	}
+}
@@ -126,7 +126,7 @@ func CanManageSystem(roleID int) bool {
 }
 
 func CanAssignRoles(roleID int) bool {
-	return roleID == RoleSystemAdmin
+	return HasCapability(roleID, CapAssignRoles)
 }
 
 func CanAccessLogs(roleID int) bool {
@@ -195,12 +195,7 @@ func CanWorkWithLeads(roleID int) bool {
 }
 
 func CanAccessTasks(roleID int) bool {
-	switch roleID {
-	case RoleManagement, RoleControl, RoleSales, RoleSystemAdmin, RoleVisa, RolePartner, RoleHR, RoleLegal:
-		return true
-	default:
-		return false
-	}
+	return HasCapability(roleID, CapAccessTasks)
 }
 
 func CanUseChat(roleID int) bool {
@@ -242,5 +237,12 @@ func CanViewChatParticipantProfile(roleID int) bool {
 // funnel transition rules. Only system admins can do this — per the role matrix
 // "Руководство" (management) may NOT edit/add/delete/reorder funnels (view only).
 func CanManageFunnels(roleID int) bool {
-	return roleID == RoleSystemAdmin
+	return HasCapability(roleID, CapManageFunnels)
+}
+
+// CanViewSigningMetadata reports whether the role sees the compliance details
+// of a signature (IP, user agent, signing user, file hash) on a document:
+// quality_control (audit), management and admin.
+func CanViewSigningMetadata(roleID int) bool {
+	return HasCapability(roleID, CapViewSigningMetadata)
 }
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/url"
@@ -33,15 +34,25 @@ type TemplatesConfig struct {
 	TxtDir  string `yaml:"txt_dir"`
 }
 
+// PDFConfig lists the TTF fonts used by the built-in PDF generator. When
+// BoldFontPath does not exist, bold text is drawn with the regular face.
+type PDFConfig struct {
+	FontPath     string `yaml:"font_path"`
+	BoldFontPath string `yaml:"bold_font_path"`
+}
+
 type LibreOfficeConfig struct {
 	Enable bool   `yaml:"enable"`
 	Binary string `yaml:"binary"`
 }
 
 type TelegramConfig struct {
-	Enable     bool   `yaml:"enable"`
-	BotToken   string `yaml:"bot_token"`
-	WebhookURL string `yaml:"webhook_url"`
+	Enable            bool   `yaml:"enable"`
+	BotToken          string `yaml:"bot_token"`
+	WebhookURL        string `yaml:"webhook_url"`
+	RequestTimeoutSec int    `yaml:"request_timeout_sec"`
+	RetryCount        int    `yaml:"retry_count"`
+	RetryDelayMS      int    `yaml:"retry_delay_ms"`
 }
 
 type BinotelConfig struct {
@@ -65,6 +76,27 @@ type WazzupConfig struct {
 
 type SecurityConfig struct {
 	JWTSecret string `yaml:"jwt_secret"`
+	// JWTIssuer is written to the iss claim of access tokens and required by
+	// the auth middleware.
+	JWTIssuer string `yaml:"jwt_issuer"`
+	// JWTAlgorithm is HS256 (jwt_secret signs and verifies, the default) or
+	// RS256 (JWTPrivateKeyFile signs, JWTPublicKeyFile verifies).
+	JWTAlgorithm      string `yaml:"jwt_algorithm"`
+	JWTPrivateKeyFile string `yaml:"jwt_private_key_file"`
+	JWTPublicKeyFile  string `yaml:"jwt_public_key_file"`
+	// AllowedRedirectOrigins lists origins that reset links and return URLs may
+	// point to. When empty it is derived from frontend.host, public_base_url
+	// and cors.allow_origins.
+	AllowedRedirectOrigins []string `yaml:"allowed_redirect_origins"`
+}
+
+type AuthConfig struct {
+	// BcryptCost is the work factor for password hashes. 0 means
+	// bcrypt.DefaultCost; valid values are 4..31.
+	BcryptCost int `yaml:"bcrypt_cost"`
+	// PasswordResetTTLMinutes is how long a password-reset link stays valid.
+	// 0 or less falls back to 60 minutes.
+	PasswordResetTTLMinutes int `yaml:"password_reset_ttl_minutes"`
 }
 
 type CORSConfig struct {
@@ -80,16 +112,85 @@ type FrontendConfig struct {
 
 type DocumentsConfig struct {
 	StrictPlaceholders bool `yaml:"strict_placeholders"`
+	// RequireSMSSignature lists doc types ("*" for all) that can be signed
+	// manually only after the SMS signature was confirmed.
+	RequireSMSSignature []string `yaml:"require_sms_signature"`
+}
+
+type DealsConfig struct {
+	// Currencies is the ISO 4217 whitelist for deal amounts. When empty the
+	// services fall back to KZT, USD, EUR and RUB.
+	Currencies []string `yaml:"currencies"`
+}
+
+type ReportsConfig struct {
+	// SummaryCacheTTLSeconds controls how long /reports/summary is served from
+	// memory. 0 means the default (30s); a negative value disables the cache.
+	SummaryCacheTTLSeconds int `yaml:"summary_cache_ttl_seconds"`
+}
+
+type ChatConfig struct {
+	// MaxConnectionsPerUser and MaxConnectionsTotal cap open chat WebSocket
+	// streams. 0 means the default; a negative value removes the limit.
+	MaxConnectionsPerUser int `yaml:"max_connections_per_user"`
+	MaxConnectionsTotal   int `yaml:"max_connections_total"`
+	// MaxFrameBytes is the largest WebSocket frame payload accepted from a
+	// client. 0 means the default (1 MiB).
+	MaxFrameBytes int `yaml:"max_frame_bytes"`
+	// HandshakeTimeoutSeconds bounds the WebSocket upgrade, ReadTimeoutSeconds
+	// closes streams that send no frame (pongs to the server's keepalive pings
+	// count) for longer (negative disables it) and ReadBufferBytes sizes the
+	// socket read buffer. 0 means the default.
+	HandshakeTimeoutSeconds int `yaml:"handshake_timeout_seconds"`
+	ReadTimeoutSeconds      int `yaml:"read_timeout_seconds"`
+	ReadBufferBytes         int `yaml:"read_buffer_bytes"`
+	// WSTicketTTLSeconds is how long a one-time ticket from
+	// GET /chats/ws-ticket can be redeemed. 0 means the default (30s).
+	WSTicketTTLSeconds int `yaml:"ws_ticket_ttl_seconds"`
+}
+
+// RateLimitRule allows Limit requests per WindowSeconds for each user (or
+// client IP on public routes) and route.
+type RateLimitRule struct {
+	Limit         int `yaml:"limit"`
+	WindowSeconds int `yaml:"window_seconds"`
+}
+
+// RateLimitConfig holds the per-group limits applied in routes.go. A zero
+// field means the default; a negative Limit disables the group's limiter.
+type RateLimitConfig struct {
+	SMS       RateLimitRule `yaml:"sms"`
+	Documents RateLimitRule `yaml:"documents"`
 }
+
 type Config struct {
 	Server struct {
 		Port int    `yaml:"port"`
 		TZ   string `yaml:"tz"`
+		// MaxBodyBytes caps JSON and form bodies; MaxUploadBytes caps
+		// multipart bodies on the file upload routes.
+		MaxBodyBytes   int `yaml:"max_body_bytes"`
+		MaxUploadBytes int `yaml:"max_upload_bytes"`
+		// RequestTimeoutSeconds bounds how long a request's database work may
+		// run; a negative value disables the timeout.
+		RequestTimeoutSeconds int `yaml:"request_timeout_seconds"`
 	} `yaml:"server"`
 
 	Database struct {
 		DSN string `yaml:"dsn"`
 		URL string `yaml:"url"`
+		// AutoMigrate applies pending db/migrations on startup and records
+		// them in schema_migrations.
+		AutoMigrate bool `yaml:"auto_migrate"`
+		// Connection pool limits; 0 picks the defaults (20 open, 10 idle,
+		// 30 minute lifetime), negative values are rejected at startup.
+		MaxOpenConns           int `yaml:"max_open_conns"`
+		MaxIdleConns           int `yaml:"max_idle_conns"`
+		ConnMaxLifetimeMinutes int `yaml:"conn_max_lifetime_minutes"`
+		// StatementTimeoutSeconds is sent to Postgres as the session
+		// statement_timeout so runaway queries are cancelled server-side.
+		// 0 means 60 seconds, a negative value disables it.
+		StatementTimeoutSeconds int `yaml:"statement_timeout_seconds"`
 	} `yaml:"database"`
 
 	DB struct {
@@ -109,14 +210,20 @@ type Config struct {
 	S3          S3Config          `yaml:"s3"`
 	Templates   TemplatesConfig   `yaml:"templates"`
 	LibreOffice LibreOfficeConfig `yaml:"libreoffice"`
+	PDF         PDFConfig         `yaml:"pdf"`
 
 	Telegram  TelegramConfig  `yaml:"telegram"`
 	Wazzup    WazzupConfig    `yaml:"wazzup"`
 	Binotel   BinotelConfig   `yaml:"binotel"`
 	Frontend  FrontendConfig  `yaml:"frontend"`
 	Documents DocumentsConfig `yaml:"documents"`
+	Deals     DealsConfig     `yaml:"deals"`
+	Reports   ReportsConfig   `yaml:"reports"`
+	Chat      ChatConfig      `yaml:"chat"`
 	CORS      CORSConfig      `yaml:"cors"`
 	Security  SecurityConfig  `yaml:"security"`
+	Auth      AuthConfig      `yaml:"auth"`
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
 
 	SignBaseURL            string `yaml:"sign_base_url"`
 	PublicBaseURL          string `yaml:"public_base_url"`
@@ -133,8 +240,10 @@ type Config struct {
 		APIKey         string `yaml:"api_key"`
 		BaseURL        string `yaml:"base_url"`
 		From           string `yaml:"from"`
+		TextPrefix     string `yaml:"text_prefix"`
 		TimeoutSeconds int    `yaml:"timeout_seconds"`
 		Retries        int    `yaml:"retries"`
+		RetryDelayMS   int    `yaml:"retry_delay_ms"`
 		DryRun         bool   `yaml:"dry_run"`
 	} `yaml:"mobizon"`
 }
@@ -205,62 +314,88 @@ func normalizeConfig(cfg *Config) {
 	}
 }
 
+// Validate checks the loaded config and fills mode-dependent defaults. It
+// reports every problem it finds at once, joined into a single error, so a
+// broken deploy can be fixed in one pass.
 func (cfg *Config) Validate() error {
-	if cfg.Server.Port <= 0 {
-		return fmt.Errorf("invalid server.port: %d", cfg.Server.Port)
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
 	}
-	if cfg.Database.DSN == "" {
-		return fmt.Errorf("database.dsn is required")
+
+	if cfg.Server.Port <= 0 || cfg.Server.Port > 65535 {
+		fail("invalid server.port: %d (must be 1..65535)", cfg.Server.Port)
 	}
 
 	mode := configMode()
-	normalizedDSN, err := normalizeDSN(cfg.Database.DSN, mode)
-	if err != nil {
-		return err
+	if cfg.Database.DSN == "" {
+		fail("database.dsn is required")
+	} else if normalizedDSN, err := normalizeDSN(cfg.Database.DSN, mode); err != nil {
+		errs = append(errs, err)
+	} else {
+		cfg.Database.DSN = withStatementTimeout(normalizedDSN, cfg.Database.StatementTimeoutSeconds)
+	}
+	if cfg.Database.MaxOpenConns < 0 {
+		fail("invalid database.max_open_conns: %d (must be positive)", cfg.Database.MaxOpenConns)
+	}
+	if cfg.Database.MaxIdleConns < 0 || (cfg.Database.MaxOpenConns > 0 && cfg.Database.MaxIdleConns > cfg.Database.MaxOpenConns) {
+		fail("invalid database.max_idle_conns: %d (must be 0..max_open_conns)", cfg.Database.MaxIdleConns)
+	}
+	if cfg.Database.ConnMaxLifetimeMinutes < 0 {
+		fail("invalid database.conn_max_lifetime_minutes: %d (must be positive)", cfg.Database.ConnMaxLifetimeMinutes)
 	}
-	cfg.Database.DSN = normalizedDSN
 
 	if len(cfg.CORS.AllowOrigins) == 0 {
 		if mode == "release" {
-			return fmt.Errorf("cors.allow_origins is required in release mode")
-		}
-		cfg.CORS.AllowOrigins = []string{
-			"http://localhost:3000",
-			"http://127.0.0.1:3000",
-			"http://localhost:5173",
-			"http://127.0.0.1:5173",
+			fail("cors.allow_origins is required in release mode")
+		} else {
+			cfg.CORS.AllowOrigins = []string{
+				"http://localhost:3000",
+				"http://127.0.0.1:3000",
+				"http://localhost:5173",
+				"http://127.0.0.1:5173",
+			}
 		}
 	}
 	if mode == "release" {
 		for _, origin := range cfg.CORS.AllowOrigins {
 			if origin == "*" {
-				return fmt.Errorf("cors.allow_origins cannot include '*' in release mode")
+				fail("cors.allow_origins cannot include '*' in release mode")
+				break
 			}
 		}
 	}
 	switch cfg.SignConfirmPolicy {
 	case "ANY", "BOTH":
 	default:
-		return fmt.Errorf("invalid sign_confirm_policy: %s", cfg.SignConfirmPolicy)
+		fail("invalid sign_confirm_policy: %s", cfg.SignConfirmPolicy)
 	}
-	if mode == "release" {
-		if err := validatePublicURL("frontend.host", cfg.Frontend.Host); err != nil {
-			return err
-		}
-		if err := validatePublicURL("public_base_url", cfg.PublicBaseURL); err != nil {
-			return err
-		}
-		if err := validatePublicURL("sign_base_url", cfg.SignBaseURL); err != nil {
-			return err
+	switch cfg.Security.JWTAlgorithm {
+	case "", "HS256":
+	case "RS256":
+		if strings.TrimSpace(cfg.Security.JWTPrivateKeyFile) == "" || strings.TrimSpace(cfg.Security.JWTPublicKeyFile) == "" {
+			fail("security.jwt_private_key_file and security.jwt_public_key_file are required for RS256")
 		}
-		if err := validatePublicURL("sign_email_verify_base_url", cfg.SignEmailVerifyBaseURL); err != nil {
-			return err
-		}
-		if err := validatePublicURL("sign_sms_verify_base_url", cfg.SignSMSVerifyBaseURL); err != nil {
-			return err
+	default:
+		fail("invalid security.jwt_algorithm: %s (must be HS256 or RS256)", cfg.Security.JWTAlgorithm)
+	}
+	if c := cfg.Auth.BcryptCost; c != 0 && (c < 4 || c > 31) {
+		fail("invalid auth.bcrypt_cost: %d (must be 4..31)", c)
+	}
+	if mode == "release" {
+		for _, u := range []struct{ field, value string }{
+			{"frontend.host", cfg.Frontend.Host},
+			{"public_base_url", cfg.PublicBaseURL},
+			{"sign_base_url", cfg.SignBaseURL},
+			{"sign_email_verify_base_url", cfg.SignEmailVerifyBaseURL},
+			{"sign_sms_verify_base_url", cfg.SignSMSVerifyBaseURL},
+		} {
+			if err := validatePublicURL(u.field, u.value); err != nil {
+				errs = append(errs, err)
+			}
 		}
 		if strings.TrimSpace(cfg.Security.JWTSecret) == "" {
-			return fmt.Errorf("security.jwt_secret is required in release mode")
+			fail("security.jwt_secret is required in release mode")
 		}
 		missing := []string{}
 		if strings.TrimSpace(cfg.Email.SMTPHost) == "" {
@@ -279,19 +414,49 @@ func (cfg *Config) Validate() error {
 			missing = append(missing, "sign_email_token_pepper")
 		}
 		if len(missing) > 0 {
-			return fmt.Errorf("email settings required in release mode: %s", strings.Join(missing, ", "))
+			fail("email settings required in release mode: %s", strings.Join(missing, ", "))
+		}
+	}
+	if cfg.Telegram.Enable {
+		if strings.TrimSpace(cfg.Telegram.BotToken) == "" {
+			fail("telegram.bot_token is required when telegram.enable=true")
+		}
+		if mode == "release" {
+			if err := validatePublicURL("telegram.webhook_url", cfg.Telegram.WebhookURL); err != nil {
+				errs = append(errs, err)
+			}
 		}
 	}
 	if cfg.Wazzup.Enable {
 		if strings.TrimSpace(cfg.Wazzup.APIToken) == "" {
-			return fmt.Errorf("wazzup.api_token is required when wazzup.enable=true")
+			fail("wazzup.api_token is required when wazzup.enable=true")
 		}
 		if strings.TrimSpace(cfg.Wazzup.APIBaseURL) == "" {
-			return fmt.Errorf("wazzup.api_base_url is required when wazzup.enable=true")
+			fail("wazzup.api_base_url is required when wazzup.enable=true")
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
+}
+
+// RedirectAllowlist returns the origins permitted for outbound links and
+// client-supplied return URLs.
+func (cfg *Config) RedirectAllowlist() []string {
+	if len(cfg.Security.AllowedRedirectOrigins) > 0 {
+		return cfg.Security.AllowedRedirectOrigins
+	}
+	out := make([]string, 0, len(cfg.CORS.AllowOrigins)+2)
+	for _, origin := range []string{cfg.Frontend.Host, cfg.PublicBaseURL} {
+		if strings.TrimSpace(origin) != "" {
+			out = append(out, origin)
+		}
+	}
+	for _, origin := range cfg.CORS.AllowOrigins {
+		if origin != "*" {
+			out = append(out, origin)
+		}
+	}
+	return out
 }
 
 func maskConfigPath(path string) string {
@@ -336,6 +501,27 @@ func normalizeDSN(dsn string, mode string) (string, error) {
 	return parsed.String(), nil
 }
 
+// withStatementTimeout adds statement_timeout (in ms) to a URL DSN; lib/pq
+// passes unknown keys to Postgres as session parameters. An explicit
+// statement_timeout already in the DSN wins, and seconds <= 0 leaves the DSN
+// untouched.
+func withStatementTimeout(dsn string, seconds int) string {
+	if seconds <= 0 {
+		return dsn
+	}
+	parsed, err := url.Parse(dsn)
+	if err != nil {
+		return dsn
+	}
+	values := parsed.Query()
+	if values.Get("statement_timeout") != "" {
+		return dsn
+	}
+	values.Set("statement_timeout", strconv.Itoa(seconds*1000))
+	parsed.RawQuery = values.Encode()
+	return parsed.String()
+}
+
 func applyDefaults(cfg *Config) {
 	// defaults
 	if cfg.Files.RootDir == "" {
@@ -356,6 +542,19 @@ func applyDefaults(cfg *Config) {
 	if cfg.Frontend.Host == "" && configMode() != "release" {
 		cfg.Frontend.Host = "http://localhost:3000"
 	}
+	if cfg.Telegram.RequestTimeoutSec <= 0 {
+		cfg.Telegram.RequestTimeoutSec = 10
+	}
+	// retry_count: 0 (unset) means the default of 2, a negative value
+	// disables retries.
+	if cfg.Telegram.RetryCount == 0 {
+		cfg.Telegram.RetryCount = 2
+	} else if cfg.Telegram.RetryCount < 0 {
+		cfg.Telegram.RetryCount = 0
+	}
+	if cfg.Telegram.RetryDelayMS <= 0 {
+		cfg.Telegram.RetryDelayMS = 500
+	}
 	if strings.TrimSpace(cfg.Wazzup.APIBaseURL) == "" {
 		cfg.Wazzup.APIBaseURL = "https://api.wazzup24.com"
 	}
@@ -380,6 +579,16 @@ func applyDefaults(cfg *Config) {
 	if envSecret := os.Getenv("JWT_SECRET"); envSecret != "" {
 		cfg.Security.JWTSecret = envSecret
 	}
+	if issuer := strings.TrimSpace(os.Getenv("JWT_ISSUER")); issuer != "" {
+		cfg.Security.JWTIssuer = issuer
+	}
+	if strings.TrimSpace(cfg.Security.JWTIssuer) == "" {
+		cfg.Security.JWTIssuer = "kub-api"
+	}
+	cfg.Security.JWTAlgorithm = strings.ToUpper(strings.TrimSpace(cfg.Security.JWTAlgorithm))
+	if cfg.Security.JWTAlgorithm == "" {
+		cfg.Security.JWTAlgorithm = "HS256"
+	}
 	if dbURL := os.Getenv("DATABASE_URL"); dbURL != "" {
 		cfg.Database.DSN = dbURL
 	}
@@ -410,6 +619,9 @@ func applyDefaults(cfg *Config) {
 	if strings.TrimSpace(cfg.SignPublicTokenPepper) == "" {
 		cfg.SignPublicTokenPepper = cfg.SignEmailTokenPepper
 	}
+	if cfg.Auth.PasswordResetTTLMinutes <= 0 {
+		cfg.Auth.PasswordResetTTLMinutes = 60
+	}
 	if cfg.SignEmailTTLMinutes <= 0 {
 		cfg.SignEmailTTLMinutes = 30
 	}
@@ -428,11 +640,76 @@ func applyDefaults(cfg *Config) {
 	if cfg.Mobizon.Retries < 0 {
 		cfg.Mobizon.Retries = 0
 	}
+	if cfg.Mobizon.RetryDelayMS <= 0 {
+		cfg.Mobizon.RetryDelayMS = 300
+	}
+	if cfg.Reports.SummaryCacheTTLSeconds == 0 {
+		cfg.Reports.SummaryCacheTTLSeconds = 30
+	}
+	if cfg.Chat.MaxConnectionsPerUser == 0 {
+		cfg.Chat.MaxConnectionsPerUser = 10
+	}
+	if cfg.Chat.MaxConnectionsTotal == 0 {
+		cfg.Chat.MaxConnectionsTotal = 2000
+	}
+	if cfg.Server.MaxBodyBytes <= 0 {
+		cfg.Server.MaxBodyBytes = 1 << 20
+	}
+	if cfg.Server.MaxUploadBytes <= 0 {
+		cfg.Server.MaxUploadBytes = 50 << 20
+	}
+	if cfg.Server.RequestTimeoutSeconds == 0 {
+		cfg.Server.RequestTimeoutSeconds = 30
+	}
+	if cfg.Database.MaxOpenConns == 0 {
+		cfg.Database.MaxOpenConns = 20
+	}
+	if cfg.Database.MaxIdleConns == 0 && cfg.Database.MaxOpenConns > 0 {
+		cfg.Database.MaxIdleConns = min(10, cfg.Database.MaxOpenConns)
+	}
+	if cfg.Database.ConnMaxLifetimeMinutes == 0 {
+		cfg.Database.ConnMaxLifetimeMinutes = 30
+	}
+	if cfg.Database.StatementTimeoutSeconds == 0 {
+		cfg.Database.StatementTimeoutSeconds = 60
+	}
+	if cfg.Chat.MaxFrameBytes <= 0 {
+		cfg.Chat.MaxFrameBytes = 1 << 20
+	}
+	if cfg.Chat.HandshakeTimeoutSeconds <= 0 {
+		cfg.Chat.HandshakeTimeoutSeconds = 10
+	}
+	if cfg.Chat.ReadTimeoutSeconds == 0 {
+		cfg.Chat.ReadTimeoutSeconds = 600
+	}
+	if cfg.Chat.ReadBufferBytes <= 0 {
+		cfg.Chat.ReadBufferBytes = 4096
+	}
+	if cfg.Chat.WSTicketTTLSeconds <= 0 {
+		cfg.Chat.WSTicketTTLSeconds = 30
+	}
+	if strings.TrimSpace(cfg.PDF.FontPath) == "" {
+		cfg.PDF.FontPath = "assets/fonts/DejaVuSans.ttf"
+	}
+	if strings.TrimSpace(cfg.PDF.BoldFontPath) == "" {
+		cfg.PDF.BoldFontPath = "assets/fonts/DejaVuSans-Bold.ttf"
+	}
+	applyRateLimitDefaults(&cfg.RateLimit.SMS, 5, 60)
+	applyRateLimitDefaults(&cfg.RateLimit.Documents, 60, 60)
 	if !cfg.Documents.StrictPlaceholders && configMode() != "release" {
 		cfg.Documents.StrictPlaceholders = true
 	}
 }
 
+func applyRateLimitDefaults(rule *RateLimitRule, limit, windowSeconds int) {
+	if rule.Limit == 0 {
+		rule.Limit = limit
+	}
+	if rule.WindowSeconds <= 0 {
+		rule.WindowSeconds = windowSeconds
+	}
+}
+
 func applyEnvOverrides(cfg *Config) {
 	setString := func(value string, target *string) {
 		if strings.TrimSpace(value) != "" {
@@ -483,13 +760,18 @@ func applyEnvOverrides(cfg *Config) {
 	setString(os.Getenv("SIGN_PUBLIC_TOKEN_PEPPER"), &cfg.SignPublicTokenPepper)
 	setString(os.Getenv("SIGN_EMAIL_VERIFY_BASE_URL"), &cfg.SignEmailVerifyBaseURL)
 	setString(os.Getenv("SIGN_SMS_VERIFY_BASE_URL"), &cfg.SignSMSVerifyBaseURL)
+	setString(os.Getenv("JWT_ALGORITHM"), &cfg.Security.JWTAlgorithm)
+	setString(os.Getenv("JWT_PRIVATE_KEY_FILE"), &cfg.Security.JWTPrivateKeyFile)
+	setString(os.Getenv("JWT_PUBLIC_KEY_FILE"), &cfg.Security.JWTPublicKeyFile)
 	mobizonAPIKeyEnv := os.Getenv("MOBIZON_API_KEY")
 	setString(mobizonAPIKeyEnv, &cfg.Mobizon.APIKey)
 	setString(os.Getenv("MOBIZON_BASE_URL"), &cfg.Mobizon.BaseURL)
 	setString(os.Getenv("MOBIZON_API_URL"), &cfg.Mobizon.BaseURL)
 	setString(os.Getenv("MOBIZON_FROM"), &cfg.Mobizon.From)
+	setString(os.Getenv("MOBIZON_TEXT_PREFIX"), &cfg.Mobizon.TextPrefix)
 	setInt(os.Getenv("MOBIZON_TIMEOUT_SECONDS"), &cfg.Mobizon.TimeoutSeconds)
 	setInt(os.Getenv("MOBIZON_RETRIES"), &cfg.Mobizon.Retries)
+	setInt(os.Getenv("MOBIZON_RETRY_DELAY_MS"), &cfg.Mobizon.RetryDelayMS)
 	setString(os.Getenv("BINOTEL_WEBHOOK_SECRET"), &cfg.Binotel.WebhookSecret)
 	setString(os.Getenv("BINOTEL_API_KEY"), &cfg.Binotel.APIKey)
 	setString(os.Getenv("BINOTEL_API_SECRET"), &cfg.Binotel.APISecret)
@@ -498,6 +780,9 @@ func applyEnvOverrides(cfg *Config) {
 	}
 	setString(os.Getenv("TELEGRAM_APITOKEN"), &cfg.Telegram.BotToken)
 	setString(os.Getenv("TELEGRAM_WEBHOOK_URL"), &cfg.Telegram.WebhookURL)
+	setInt(os.Getenv("TELEGRAM_REQUEST_TIMEOUT_SEC"), &cfg.Telegram.RequestTimeoutSec)
+	setInt(os.Getenv("TELEGRAM_RETRY_COUNT"), &cfg.Telegram.RetryCount)
+	setInt(os.Getenv("TELEGRAM_RETRY_DELAY_MS"), &cfg.Telegram.RetryDelayMS)
 	setString(os.Getenv("WAZZUP_API_BASE_URL"), &cfg.Wazzup.APIBaseURL)
 	setString(os.Getenv("WAZZUP_API_TOKEN"), &cfg.Wazzup.APIToken)
 	setString(os.Getenv("WAZZUP_CHANNEL_ID"), &cfg.Wazzup.ChannelID)
@@ -509,6 +794,9 @@ func applyEnvOverrides(cfg *Config) {
 	if val := strings.TrimSpace(os.Getenv("WAZZUP_ENABLE")); val != "" {
 		cfg.Wazzup.Enable = parseBoolEnvValue(val)
 	}
+	if val := strings.TrimSpace(os.Getenv("DB_AUTO_MIGRATE")); val != "" {
+		cfg.Database.AutoMigrate = parseBoolEnvValue(val)
+	}
 	if val := strings.TrimSpace(os.Getenv("DOCUMENTS_STRICT_PLACEHOLDERS")); val != "" {
 		cfg.Documents.StrictPlaceholders = parseBoolEnvValue(val)
 	}
@@ -543,6 +831,37 @@ func applyEnvOverrides(cfg *Config) {
 		cfg.Mobizon.DryRun = false
 	}
 	setInt(os.Getenv("SIGN_SESSION_TTL_MINUTES"), &cfg.SignSessionTTLMinutes)
+	setInt(os.Getenv("REPORTS_SUMMARY_CACHE_TTL_SECONDS"), &cfg.Reports.SummaryCacheTTLSeconds)
+	if val := strings.TrimSpace(os.Getenv("DEALS_CURRENCIES")); val != "" {
+		cfg.Deals.Currencies = strings.Split(val, ",")
+	}
+	if val := strings.TrimSpace(os.Getenv("DOCUMENTS_REQUIRE_SMS_SIGNATURE")); val != "" {
+		cfg.Documents.RequireSMSSignature = strings.Split(val, ",")
+	}
+	setInt(os.Getenv("CHAT_MAX_CONNECTIONS_PER_USER"), &cfg.Chat.MaxConnectionsPerUser)
+	setInt(os.Getenv("CHAT_MAX_CONNECTIONS_TOTAL"), &cfg.Chat.MaxConnectionsTotal)
+	setInt(os.Getenv("CHAT_MAX_FRAME_BYTES"), &cfg.Chat.MaxFrameBytes)
+	setInt(os.Getenv("AUTH_BCRYPT_COST"), &cfg.Auth.BcryptCost)
+	if minutes, err := strconv.Atoi(strings.TrimSpace(os.Getenv("AUTH_PASSWORD_RESET_TTL_MINUTES"))); err == nil && minutes > 0 {
+		cfg.Auth.PasswordResetTTLMinutes = minutes
+	}
+	setInt(os.Getenv("CHAT_HANDSHAKE_TIMEOUT_SECONDS"), &cfg.Chat.HandshakeTimeoutSeconds)
+	setInt(os.Getenv("CHAT_READ_TIMEOUT_SECONDS"), &cfg.Chat.ReadTimeoutSeconds)
+	setInt(os.Getenv("CHAT_READ_BUFFER_BYTES"), &cfg.Chat.ReadBufferBytes)
+	setInt(os.Getenv("CHAT_WS_TICKET_TTL_SECONDS"), &cfg.Chat.WSTicketTTLSeconds)
+	setInt(os.Getenv("SERVER_MAX_BODY_BYTES"), &cfg.Server.MaxBodyBytes)
+	setInt(os.Getenv("SERVER_MAX_UPLOAD_BYTES"), &cfg.Server.MaxUploadBytes)
+	setInt(os.Getenv("SERVER_REQUEST_TIMEOUT_SECONDS"), &cfg.Server.RequestTimeoutSeconds)
+	setInt(os.Getenv("DB_MAX_OPEN_CONNS"), &cfg.Database.MaxOpenConns)
+	setInt(os.Getenv("DB_MAX_IDLE_CONNS"), &cfg.Database.MaxIdleConns)
+	setInt(os.Getenv("DB_CONN_MAX_LIFETIME_MINUTES"), &cfg.Database.ConnMaxLifetimeMinutes)
+	setInt(os.Getenv("DB_STATEMENT_TIMEOUT_SECONDS"), &cfg.Database.StatementTimeoutSeconds)
+	setString(os.Getenv("PDF_FONT_PATH"), &cfg.PDF.FontPath)
+	setString(os.Getenv("PDF_BOLD_FONT_PATH"), &cfg.PDF.BoldFontPath)
+	setInt(os.Getenv("RATE_LIMIT_SMS_LIMIT"), &cfg.RateLimit.SMS.Limit)
+	setInt(os.Getenv("RATE_LIMIT_SMS_WINDOW_SECONDS"), &cfg.RateLimit.SMS.WindowSeconds)
+	setInt(os.Getenv("RATE_LIMIT_DOCUMENTS_LIMIT"), &cfg.RateLimit.Documents.Limit)
+	setInt(os.Getenv("RATE_LIMIT_DOCUMENTS_WINDOW_SECONDS"), &cfg.RateLimit.Documents.WindowSeconds)
 }
 
 func validatePublicURL(fieldName, raw string) error {
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func loadDatabaseTestConfig(t *testing.T, databaseYAML string) *Config {
+	t.Helper()
+	t.Setenv("GIN_MODE", "debug")
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := []byte("server:\n  port: 4000\ndatabase:\n  dsn: \"postgres://u:p@localhost:5432/db?sslmode=disable\"\n" + databaseYAML)
+	if err := os.WriteFile(cfgPath, content, 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("CONFIG_PATH", cfgPath)
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	return cfg
+}
+
+func TestDatabasePoolDefaults(t *testing.T) {
+	cfg := loadDatabaseTestConfig(t, "")
+	db := cfg.Database
+	if db.MaxOpenConns != 20 || db.MaxIdleConns != 10 || db.ConnMaxLifetimeMinutes != 30 || db.StatementTimeoutSeconds != 60 {
+		t.Fatalf("unexpected defaults: %+v", db)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if !strings.Contains(cfg.Database.DSN, "statement_timeout=60000") {
+		t.Fatalf("default statement timeout missing from dsn %q", cfg.Database.DSN)
+	}
+}
+
+func TestDatabasePoolFromYAMLAndEnv(t *testing.T) {
+	t.Setenv("DB_MAX_OPEN_CONNS", "5")
+	cfg := loadDatabaseTestConfig(t, "  conn_max_lifetime_minutes: 12\n  statement_timeout_seconds: 15\n")
+	db := cfg.Database
+	if db.MaxOpenConns != 5 || db.MaxIdleConns != 5 || db.ConnMaxLifetimeMinutes != 12 || db.StatementTimeoutSeconds != 15 {
+		t.Fatalf("unexpected pool settings: %+v", db)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if !strings.Contains(cfg.Database.DSN, "statement_timeout=15000") {
+		t.Fatalf("statement timeout missing from dsn %q", cfg.Database.DSN)
+	}
+}
+
+func TestValidateRejectsBadDatabasePool(t *testing.T) {
+	cases := map[string]func(*Config){
+		"max_open_conns":            func(c *Config) { c.Database.MaxOpenConns = -1 },
+		"max_idle_conns":            func(c *Config) { c.Database.MaxOpenConns, c.Database.MaxIdleConns = 4, 8 },
+		"conn_max_lifetime_minutes": func(c *Config) { c.Database.ConnMaxLifetimeMinutes = -5 },
+	}
+	for field, mutate := range cases {
+		t.Run(field, func(t *testing.T) {
+			cfg := &Config{}
+			cfg.Server.Port = 4000
+			cfg.Database.DSN = "postgres://u:p@localhost:5432/db?sslmode=disable"
+			cfg.SignConfirmPolicy = "ANY"
+			mutate(cfg)
+			if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "database."+field) {
+				t.Fatalf("expected database.%s error, got %v", field, err)
+			}
+		})
+	}
+}
+
+func TestWithStatementTimeout(t *testing.T) {
+	base := "postgres://u:p@localhost:5432/db?sslmode=disable"
+	if got := withStatementTimeout(base, -1); got != base {
+		t.Fatalf("negative timeout must leave dsn untouched, got %q", got)
+	}
+	explicit := base + "&statement_timeout=5000"
+	if got := withStatementTimeout(explicit, 60); got != explicit {
+		t.Fatalf("explicit statement_timeout must win, got %q", got)
+	}
+	got := withStatementTimeout(base, 30)
+	if !strings.Contains(got, "statement_timeout=30000") || !strings.Contains(got, "sslmode=disable") {
+		t.Fatalf("unexpected dsn %q", got)
+	}
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJWTAlgorithmEnvOverrides(t *testing.T) {
+	t.Setenv("GIN_MODE", "debug")
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	content := []byte(`server:
+  port: 4000
+database:
+  dsn: "postgres://u:p@localhost:5432/db?sslmode=disable"
+security:
+  jwt_private_key_file: "/etc/kub/yaml-private.pem"
+`)
+	if err := os.WriteFile(cfgPath, content, 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("CONFIG_PATH", cfgPath)
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Security.JWTAlgorithm != "HS256" {
+		t.Fatalf("expected HS256 by default, got %q", cfg.Security.JWTAlgorithm)
+	}
+
+	t.Setenv("JWT_ALGORITHM", " rs256 ")
+	t.Setenv("JWT_PRIVATE_KEY_FILE", "/run/secrets/jwt-private.pem")
+	t.Setenv("JWT_PUBLIC_KEY_FILE", "/run/secrets/jwt-public.pem")
+	cfg, err = LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Security.JWTAlgorithm != "RS256" ||
+		cfg.Security.JWTPrivateKeyFile != "/run/secrets/jwt-private.pem" ||
+		cfg.Security.JWTPublicKeyFile != "/run/secrets/jwt-public.pem" {
+		t.Fatalf("env overrides not applied: %+v", cfg.Security)
+	}
+}
@@ -6,6 +6,7 @@ func TestApplyEnvOverridesReadsMobizonAPIURL(t *testing.T) {
 	t.Setenv("MOBIZON_API_URL", "https://api.mobizon.kz/service")
 	t.Setenv("MOBIZON_API_KEY", "secret")
 	t.Setenv("MOBIZON_FROM", "KUB")
+	t.Setenv("MOBIZON_TEXT_PREFIX", "TurCompany:")
 
 	cfg := &Config{}
 	applyEnvOverrides(cfg)
@@ -19,6 +20,9 @@ func TestApplyEnvOverridesReadsMobizonAPIURL(t *testing.T) {
 	if cfg.Mobizon.From != "KUB" {
 		t.Fatalf("Mobizon.From = %q", cfg.Mobizon.From)
 	}
+	if cfg.Mobizon.TextPrefix != "TurCompany:" {
+		t.Fatalf("Mobizon.TextPrefix = %q", cfg.Mobizon.TextPrefix)
+	}
 	if !cfg.Mobizon.Enabled {
 		t.Fatal("Mobizon.Enabled should be true when MOBIZON_API_KEY is set")
 	}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRateLimitDefaultsAndOverrides(t *testing.T) {
+	t.Setenv("GIN_MODE", "debug")
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	content := []byte(`server:
+  port: 4000
+database:
+  dsn: "postgres://u:p@localhost:5432/db?sslmode=disable"
+rate_limit:
+  documents:
+    limit: -1
+`)
+	if err := os.WriteFile(cfgPath, content, 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("CONFIG_PATH", cfgPath)
+	t.Setenv("RATE_LIMIT_SMS_WINDOW_SECONDS", "300")
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.RateLimit.SMS.Limit != 5 || cfg.RateLimit.SMS.WindowSeconds != 300 {
+		t.Fatalf("unexpected sms rate limit: %+v", cfg.RateLimit.SMS)
+	}
+	if cfg.RateLimit.Documents.Limit != -1 || cfg.RateLimit.Documents.WindowSeconds != 60 {
+		t.Fatalf("unexpected documents rate limit: %+v", cfg.RateLimit.Documents)
+	}
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedirectAllowlistDerivesFromFrontendAndCORS(t *testing.T) {
+	cfg := &Config{}
+	cfg.Frontend.Host = "https://kubcrm.kz"
+	cfg.PublicBaseURL = "https://sign.kubcrm.kz"
+	cfg.CORS.AllowOrigins = []string{"https://admin.kubcrm.kz", "*"}
+
+	got := cfg.RedirectAllowlist()
+	want := []string{"https://kubcrm.kz", "https://sign.kubcrm.kz", "https://admin.kubcrm.kz"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("RedirectAllowlist() = %v, want %v", got, want)
+	}
+}
+
+func TestRedirectAllowlistPrefersExplicitList(t *testing.T) {
+	cfg := &Config{}
+	cfg.Frontend.Host = "https://kubcrm.kz"
+	cfg.Security.AllowedRedirectOrigins = []string{"https://only.kubcrm.kz"}
+
+	got := cfg.RedirectAllowlist()
+	if len(got) != 1 || got[0] != "https://only.kubcrm.kz" {
+		t.Fatalf("RedirectAllowlist() = %v", got)
+	}
+}
@@ -28,3 +28,34 @@ sign_email_ttl_minutes: 45
 		t.Fatalf("unexpected sign session ttl: got=%d want=45", cfg.SignSessionTTLMinutes)
 	}
 }
+
+func TestPasswordResetTTLDefaultsAndEnvOverride(t *testing.T) {
+	t.Setenv("GIN_MODE", "debug")
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	content := []byte(`server:
+  port: 4000
+database:
+  dsn: "postgres://u:p@localhost:5432/db?sslmode=disable"
+`)
+	if err := os.WriteFile(cfgPath, content, 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("CONFIG_PATH", cfgPath)
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Auth.PasswordResetTTLMinutes != 60 {
+		t.Fatalf("unexpected default password reset ttl: got=%d want=60", cfg.Auth.PasswordResetTTLMinutes)
+	}
+
+	t.Setenv("AUTH_PASSWORD_RESET_TTL_MINUTES", "20")
+	cfg, err = LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Auth.PasswordResetTTLMinutes != 20 {
+		t.Fatalf("unexpected password reset ttl override: got=%d want=20", cfg.Auth.PasswordResetTTLMinutes)
+	}
+}
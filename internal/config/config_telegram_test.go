@@ -0,0 +1,39 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTelegramRetryCountDefaultsAndOverrides(t *testing.T) {
+	t.Setenv("GIN_MODE", "debug")
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	content := []byte(`server:
+  port: 4000
+database:
+  dsn: "postgres://u:p@localhost:5432/db?sslmode=disable"
+`)
+	if err := os.WriteFile(cfgPath, content, 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("CONFIG_PATH", cfgPath)
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Telegram.RetryCount != 2 || cfg.Telegram.RetryDelayMS != 500 || cfg.Telegram.RequestTimeoutSec != 10 {
+		t.Fatalf("unexpected telegram defaults: %+v", cfg.Telegram)
+	}
+
+	t.Setenv("TELEGRAM_RETRY_COUNT", "-1")
+	cfg, err = LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Telegram.RetryCount != 0 {
+		t.Fatalf("negative retry_count must disable retries, got %d", cfg.Telegram.RetryCount)
+	}
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateReportsAllMissingFieldsAtOnce(t *testing.T) {
+	cfg := &Config{}
+	cfg.SignConfirmPolicy = "ANY"
+	cfg.Telegram.Enable = true
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	msg := err.Error()
+	for _, want := range []string{"server.port", "database.dsn is required", "telegram.bot_token"} {
+		if !strings.Contains(msg, want) {
+			t.Fatalf("expected error to mention %q, got:\n%s", want, msg)
+		}
+	}
+}
+
+func TestValidateRejectsPortOutOfRange(t *testing.T) {
+	cfg := &Config{}
+	cfg.Server.Port = 70000
+	cfg.Database.DSN = "postgres://u:p@localhost:5432/db?sslmode=disable"
+	cfg.SignConfirmPolicy = "ANY"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "server.port") {
+		t.Fatalf("expected server.port error, got %v", err)
+	}
+}
+
+func TestValidateJWTAlgorithm(t *testing.T) {
+	cfg := &Config{}
+	cfg.Security.JWTAlgorithm = "RS256"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "jwt_private_key_file") {
+		t.Fatalf("expected RS256 key files error, got %v", err)
+	}
+	cfg.Security.JWTAlgorithm = "none"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "security.jwt_algorithm") {
+		t.Fatalf("expected jwt_algorithm error, got %v", err)
+	}
+}
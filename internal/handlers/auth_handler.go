@@ -1,12 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"turcompany/internal/authz"
 	"turcompany/internal/models"
 	"turcompany/internal/services"
 )
@@ -15,12 +17,24 @@ type AuthHandler struct {
 	userService          services.UserService
 	authService          services.AuthService
 	passwordResetService services.PasswordResetService
+	revocations          *services.TokenRevocationList
+	roles                authRoleLookup
+}
+
+// authRoleLookup resolves a role id to its stored record for GET /auth/me.
+type authRoleLookup interface {
+	GetRoleByID(id int) (*models.Role, error)
 }
 
 func NewAuthHandler(userService services.UserService, authService services.AuthService, passwordResetService services.PasswordResetService) *AuthHandler {
 	return &AuthHandler{userService: userService, authService: authService, passwordResetService: passwordResetService}
 }
 
+// SetTokenRevocations enables revoking the current access token on logout.
+func (h *AuthHandler) SetTokenRevocations(l *services.TokenRevocationList) {
+	h.revocations = l
+}
+
 func (h *AuthHandler) Login(c *gin.Context) {
 	start := time.Now()
 
@@ -36,19 +50,19 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	user, err := h.userService.GetAuthUserByEmail(email)
 	if err != nil || user == nil {
 		log.Printf("[auth][login] user not found by email=%q: err=%v", email, err)
-		unauthorized(c, "Invalid email or password")
+		writeError(c, http.StatusUnauthorized, InvalidCredentialsCode, "Invalid email or password")
 		return
 	}
 	if !user.IsActive {
 		log.Printf("[auth][login] inactive user id=%d", user.ID)
-		forbidden(c, "Пользователь отключен")
+		writeError(c, http.StatusForbidden, UserDisabledCode, "Пользователь отключен")
 		return
 	}
 
 	// Блокируем логин, если телефон не подтверждён
 	if !user.IsVerified {
 		log.Printf("[auth][login] user not verified id=%d", user.ID)
-		forbidden(c, "Phone not verified")
+		writeError(c, http.StatusForbidden, UserNotVerifiedCode, "Phone not verified")
 		return
 	}
 
@@ -58,14 +72,14 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 	if ph == "" {
 		log.Printf("[auth][login] empty password_hash in DB for userID=%d email=%q", user.ID, email)
-		unauthorized(c, "Invalid email or password")
+		writeError(c, http.StatusUnauthorized, InvalidCredentialsCode, "Invalid email or password")
 		return
 	}
 
 	pw := strings.TrimSpace(req.Password)
 	if !h.authService.VerifyPassword(ph, pw) {
 		log.Printf("[auth][login] bcrypt mismatch for userID=%d email=%q", user.ID, email)
-		unauthorized(c, "Invalid email or password")
+		writeError(c, http.StatusUnauthorized, InvalidCredentialsCode, "Invalid email or password")
 		return
 	}
 	log.Printf("[auth][login] password OK for userID=%d", user.ID)
@@ -115,11 +129,11 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	old := strings.TrimSpace(req.RefreshToken)
 	user, err := h.userService.GetByRefreshToken(old)
 	if err != nil || user == nil || user.RefreshExpiresAt == nil || user.RefreshRevoked || !user.IsActive {
-		unauthorized(c, "Invalid refresh token")
+		writeError(c, http.StatusUnauthorized, InvalidRefreshTokenCode, "Invalid refresh token")
 		return
 	}
 	if time.Now().After(*user.RefreshExpiresAt) {
-		unauthorized(c, "Refresh token expired")
+		writeError(c, http.StatusUnauthorized, RefreshTokenExpiredCode, "Refresh token expired")
 		return
 	}
 
@@ -130,7 +144,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	}
 	rotatedUser, err := h.userService.RotateRefresh(old, newRT, newExp)
 	if err != nil || rotatedUser == nil || !rotatedUser.IsActive {
-		unauthorized(c, "Invalid refresh token")
+		writeError(c, http.StatusUnauthorized, InvalidRefreshTokenCode, "Invalid refresh token")
 		return
 	}
 
@@ -145,6 +159,72 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		"refresh_token": newRT,
 	})
 }
+
+// Logout revokes the caller's refresh token and, when a revocation list is
+// configured, the access token the request was made with.
+func (h *AuthHandler) Logout(c *gin.Context) {
+	userID, _ := getUserAndRole(c)
+	if userID == 0 {
+		unauthorized(c, "unauthorized")
+		return
+	}
+	if err := h.userService.ClearRefresh(userID); err != nil {
+		log.Printf("[auth][logout] clear refresh token failed for userID=%d: err=%v", userID, err)
+		internalError(c, "Failed to log out")
+		return
+	}
+	if h.revocations != nil {
+		jti := c.GetString("token_id")
+		exp, _ := c.Get("token_expires_at")
+		if expiresAt, ok := exp.(time.Time); ok && jti != "" {
+			h.revocations.Revoke(jti, expiresAt)
+		}
+	}
+	log.Printf("[auth][logout] success userID=%d", userID)
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out"})
+}
+
+// SetRoleLookup lets GET /auth/me return the stored role name. Without it the
+// built-in role code is used.
+func (h *AuthHandler) SetRoleLookup(roles authRoleLookup) {
+	h.roles = roles
+}
+
+// Me describes the caller's session: who they are, which role the token
+// carries, when it expires and how authz interprets that role, so clients do
+// not have to hard-code role ids.
+func (h *AuthHandler) Me(c *gin.Context) {
+	userID, roleID := getUserAndRole(c)
+	if userID == 0 {
+		unauthorized(c, "unauthorized")
+		return
+	}
+	roleName := authz.RoleCodeByID(roleID)
+	if h.roles != nil {
+		if role, err := h.roles.GetRoleByID(roleID); err == nil && role != nil && role.Name != "" {
+			roleName = role.Name
+		} else if err != nil {
+			log.Printf("[auth][me] role lookup failed for roleID=%d: err=%v", roleID, err)
+		}
+	}
+	resp := gin.H{
+		"user_id":   userID,
+		"role_id":   roleID,
+		"role_code": authz.RoleCodeByID(roleID),
+		"role_name": roleName,
+		"permissions": gin.H{
+			"is_read_only": authz.IsReadOnly(roleID),
+			"is_elevated":  authz.IsElevated(roleID),
+		},
+	}
+	if exp, ok := c.Get("token_expires_at"); ok {
+		if expiresAt, ok := exp.(time.Time); ok {
+			resp["expires_at"] = expiresAt
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
 func (h *AuthHandler) ForgotPassword(c *gin.Context) {
 	var req struct {
 		Email string `json:"email" binding:"required"`
@@ -153,8 +233,8 @@ func (h *AuthHandler) ForgotPassword(c *gin.Context) {
 		badRequest(c, "Invalid forgot password payload")
 		return
 	}
-	if err := h.passwordResetService.RequestReset(req.Email); err != nil {
-		badRequest(c, err.Error())
+	if err := h.passwordResetService.RequestReset(c.Request.Context(), req.Email); err != nil {
+		badRequestWithCode(c, ValidationFailed, err.Error())
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "If the account exists, password reset instructions were sent"})
@@ -169,8 +249,19 @@ func (h *AuthHandler) ResetPassword(c *gin.Context) {
 		badRequest(c, "Invalid reset password payload")
 		return
 	}
-	if err := h.passwordResetService.ResetPassword(req.Token, req.Password); err != nil {
-		badRequest(c, err.Error())
+	if err := h.passwordResetService.ResetPassword(c.Request.Context(), req.Token, req.Password); err != nil {
+		switch {
+		case errors.Is(err, services.ErrResetTokenNotFound):
+			badRequestWithCode(c, ResetTokenInvalidCode, err.Error())
+		case errors.Is(err, services.ErrResetTokenExpired):
+			badRequestWithCode(c, ResetTokenExpiredCode, err.Error())
+		case errors.Is(err, services.ErrResetTokenUsed):
+			badRequestWithCode(c, ResetTokenUsedCode, err.Error())
+		case errors.Is(err, services.ErrWeakPassword):
+			badRequestWithCode(c, WeakPasswordCode, err.Error())
+		default:
+			badRequest(c, err.Error())
+		}
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Password updated"})
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/middleware"
+	"turcompany/internal/services"
+)
+
+type logoutUserService struct {
+	*stubUserService
+	cleared []int
+}
+
+func (s *logoutUserService) ClearRefresh(userID int) error {
+	s.cleared = append(s.cleared, userID)
+	return nil
+}
+
+func TestAuthHandler_Logout_RevokesAccessToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	secret := []byte("01234567890123456789012345678901")
+	authSvc := services.NewAuthService(secret, nil, 0, 0, 0, nil)
+	users := &logoutUserService{stubUserService: &stubUserService{}}
+	revocations := services.NewTokenRevocationList()
+
+	h := NewAuthHandler(users, authSvc, nil)
+	h.SetTokenRevocations(revocations)
+	r := gin.New()
+	r.Use(middleware.NewAuthMiddlewareWithOptions(secret, middleware.AuthOptions{Revocations: revocations}))
+	r.POST("/auth/logout", h.Logout)
+	r.GET("/me", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	token, _, err := authSvc.GenerateAccessToken(7, 2)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+	other, _, err := authSvc.GenerateAccessToken(7, 2)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+	do := func(method, path, bearer string) int {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+bearer)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := do(http.MethodGet, "/me", token); code != http.StatusOK {
+		t.Fatalf("token must work before logout, got %d", code)
+	}
+	if code := do(http.MethodPost, "/auth/logout", token); code != http.StatusOK {
+		t.Fatalf("logout: expected 200, got %d", code)
+	}
+	if len(users.cleared) != 1 || users.cleared[0] != 7 {
+		t.Fatalf("logout must clear the refresh token of user 7, got %v", users.cleared)
+	}
+	if code := do(http.MethodGet, "/me", token); code != http.StatusUnauthorized {
+		t.Fatalf("revoked token must be rejected, got %d", code)
+	}
+	if code := do(http.MethodGet, "/me", other); code != http.StatusOK {
+		t.Fatalf("other sessions keep working, got %d", code)
+	}
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/middleware"
+	"turcompany/internal/models"
+	"turcompany/internal/services"
+)
+
+type authRoleLookupStub map[int]string
+
+func (s authRoleLookupStub) GetRoleByID(id int) (*models.Role, error) {
+	name, ok := s[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return &models.Role{ID: id, Name: name}, nil
+}
+
+type authMeResponse struct {
+	UserID      int       `json:"user_id"`
+	RoleID      int       `json:"role_id"`
+	RoleCode    string    `json:"role_code"`
+	RoleName    string    `json:"role_name"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	Permissions struct {
+		IsReadOnly bool `json:"is_read_only"`
+		IsElevated bool `json:"is_elevated"`
+	} `json:"permissions"`
+}
+
+func TestAuthHandler_Me_FlagsFollowRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	secret := []byte("01234567890123456789012345678901")
+	authSvc := services.NewAuthService(secret, nil, 0, 0, 0, nil)
+
+	h := NewAuthHandler(&stubUserService{}, authSvc, nil)
+	h.SetRoleLookup(authRoleLookupStub{authz.RoleSales: "Менеджер продаж"})
+	r := gin.New()
+	r.Use(middleware.NewAuthMiddlewareWithOptions(secret, middleware.AuthOptions{}))
+	r.GET("/auth/me", h.Me)
+
+	me := func(userID, roleID int) authMeResponse {
+		t.Helper()
+		token, _, err := authSvc.GenerateAccessToken(userID, roleID)
+		if err != nil {
+			t.Fatalf("GenerateAccessToken: %v", err)
+		}
+		req := httptest.NewRequest(http.MethodGet, "/auth/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+		}
+		var resp authMeResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return resp
+	}
+
+	sales := me(7, authz.RoleSales)
+	if sales.UserID != 7 || sales.RoleID != authz.RoleSales || sales.RoleName != "Менеджер продаж" {
+		t.Fatalf("unexpected sales identity: %+v", sales)
+	}
+	if sales.Permissions.IsElevated || sales.Permissions.IsReadOnly {
+		t.Fatalf("sales must be neither elevated nor read-only: %+v", sales.Permissions)
+	}
+	if !sales.ExpiresAt.After(time.Now()) {
+		t.Fatalf("expected a future expiry, got %v", sales.ExpiresAt)
+	}
+
+	admin := me(1, authz.RoleSystemAdmin)
+	if !admin.Permissions.IsElevated || admin.Permissions.IsReadOnly {
+		t.Fatalf("admin must be elevated and writable: %+v", admin.Permissions)
+	}
+	// No stored name: the built-in role code is used.
+	if admin.RoleName != authz.RoleCodeByID(authz.RoleSystemAdmin) || admin.RoleCode != admin.RoleName {
+		t.Fatalf("expected role code fallback, got %+v", admin)
+	}
+}
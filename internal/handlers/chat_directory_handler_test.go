@@ -311,6 +311,11 @@ func (r *chatTestUserRepo) GetByID(id int) (*models.User, error) {
 }
 func (r *chatTestUserRepo) Update(*models.User) error                   { return nil }
 func (r *chatTestUserRepo) Delete(int) error                            { return nil }
+func (r *chatTestUserRepo) Reactivate(int) error { return nil }
+func (r *chatTestUserRepo) Search(repositories.UserSearchFilter, int, int) ([]*models.User, error) {
+	return nil, nil
+}
+func (r *chatTestUserRepo) CountSearch(repositories.UserSearchFilter) (int, error) { return 0, nil }
 func (r *chatTestUserRepo) List(int, int) ([]*models.User, error)       { return nil, nil }
 func (r *chatTestUserRepo) GetByEmail(string) (*models.User, error)     { return nil, nil }
 func (r *chatTestUserRepo) GetAuthByEmail(string) (*models.User, error) { return nil, nil }
@@ -21,6 +21,7 @@ import (
 type ChatHandler struct {
 	service *services.ChatService
 	hub     *realtime.ChatHub
+	tickets *realtime.TicketStore
 }
 
 var attachmentUUIDPattern = regexp.MustCompile(`(?i)^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
@@ -59,6 +60,11 @@ func NewChatHandler(service *services.ChatService, hub *realtime.ChatHub) *ChatH
 	return &ChatHandler{service: service, hub: hub}
 }
 
+// SetStreamTickets enables one-time ?ticket= authentication for Stream.
+func (h *ChatHandler) SetStreamTickets(store *realtime.TicketStore) {
+	h.tickets = store
+}
+
 func ensureCanUseChat(c *gin.Context, roleID int) bool {
 	if !authz.CanUseChat(roleID) {
 		forbidden(c, "Chat is not allowed for this role")
@@ -508,6 +514,24 @@ func (h *ChatHandler) GetUserStatus(c *gin.Context) {
 	})
 }
 
+// GET /chats/:id/presence — участники, у которых сейчас открыт поток чата.
+func (h *ChatHandler) Presence(c *gin.Context) {
+	userID, roleID := getUserAndRole(c)
+	if !ensureCanUseChat(c, roleID) {
+		return
+	}
+	chatID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "Invalid chat id")
+		return
+	}
+	if err := h.service.EnsureMember(chatID, userID); err != nil {
+		writeChatError(c, err, "Failed to load presence")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"chat_id": chatID, "online": h.hub.OnlineMembers(chatID)})
+}
+
 func (h *ChatHandler) ListUnread(c *gin.Context) {
 	userID, roleID := getUserAndRole(c)
 	if !ensureCanUseChat(c, roleID) {
@@ -827,6 +851,46 @@ func buildMessagesWithAttachmentsResponse(messages []*models.ChatMessage, attach
 	return resp
 }
 
+// GET /chats/ws-ticket — одноразовый билет для WebSocket: браузерный
+// WebSocket не умеет слать Authorization, поэтому клиент берёт билет обычным
+// запросом и открывает /chats/:id/ws?ticket=<ticket>.
+func (h *ChatHandler) IssueStreamTicket(c *gin.Context) {
+	userID, roleID := getUserAndRole(c)
+	if h.tickets == nil {
+		notFound(c, NotFoundCode, "WebSocket tickets are disabled")
+		return
+	}
+	ticket, expiresAt, err := h.tickets.Issue(userID, roleID)
+	if err != nil {
+		log.Printf("[chat_stream] issue ticket for user %d: %v", userID, err)
+		internalError(c, "Failed to issue ticket")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ticket": ticket, "expires_at": expiresAt})
+}
+
+// StreamTicketAuth authenticates a Stream request by its ?ticket=, consuming
+// the ticket; requests without one go through fallback (the JWT middleware).
+func (h *ChatHandler) StreamTicketAuth(fallback gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw := strings.TrimSpace(c.Query("ticket"))
+		if raw == "" || h.tickets == nil {
+			fallback(c)
+			return
+		}
+		ticket, ok := h.tickets.Redeem(raw)
+		if !ok {
+			log.Printf("[chat_stream] rejected ticket: unknown, used or expired path=%s", c.Request.URL.Path)
+			unauthorized(c, "Invalid or expired ticket")
+			c.Abort()
+			return
+		}
+		c.Set("user_id", ticket.UserID)
+		c.Set("role_id", ticket.RoleID)
+		c.Next()
+	}
+}
+
 func (h *ChatHandler) Stream(c *gin.Context) {
 	userID, roleID := getUserAndRole(c)
 
@@ -854,6 +918,12 @@ func (h *ChatHandler) Stream(c *gin.Context) {
 		}
 	}
 
+	if !realtime.OriginAllowed(c.Request) {
+		log.Printf("[chat_stream] rejected origin %q for chat %d user %d", c.GetHeader("Origin"), chatID, userID)
+		forbidden(c, "Origin not allowed")
+		return
+	}
+
 	conn, err := realtime.Upgrade(c.Writer, c.Request)
 	if err != nil {
 		log.Printf("[chat_stream] websocket upgrade failed for chat %d user %d: %v", chatID, userID, err)
@@ -861,7 +931,11 @@ func (h *ChatHandler) Stream(c *gin.Context) {
 		return
 	}
 
-	h.hub.Register(chatID, userID, conn)
+	if err := h.hub.Register(chatID, userID, conn); err != nil {
+		log.Printf("[chat_stream] rejecting connection for chat %d user %d: %v", chatID, userID, err)
+		_ = conn.CloseWithReason(realtime.CloseTryAgainLater, "connection limit reached")
+		return
+	}
 	defer h.hub.Unregister(chatID, userID, conn)
 
 	for {
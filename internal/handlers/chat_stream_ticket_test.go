@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/realtime"
+)
+
+// newTicketStreamServer serves /chats/:id/ws behind StreamTicketAuth; the
+// final handler upgrades and reports the authenticated user.
+func newTicketStreamServer(t *testing.T, h *ChatHandler) (*httptest.Server, chan int) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	upgradedAs := make(chan int, 4)
+	jwtOnly := func(c *gin.Context) {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid Authorization header"})
+	}
+	r := gin.New()
+	r.GET("/chats/:id/ws", h.StreamTicketAuth(jwtOnly), func(c *gin.Context) {
+		userID, _ := getUserAndRole(c)
+		conn, err := realtime.Upgrade(c.Writer, c.Request)
+		if err != nil {
+			t.Errorf("upgrade: %v", err)
+			return
+		}
+		upgradedAs <- userID
+		_ = conn.Close()
+	})
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return srv, upgradedAs
+}
+
+func dialChatStream(t *testing.T, srv *httptest.Server, ticket string) int {
+	t.Helper()
+	client, err := net.Dial("tcp", strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer client.Close()
+	handshake := "GET /chats/5/ws?ticket=" + ticket + " HTTP/1.1\r\nHost: example.com\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n"
+	if _, err := client.Write([]byte(handshake)); err != nil {
+		t.Fatalf("write handshake: %v", err)
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(client), nil)
+	if err != nil {
+		t.Fatalf("read handshake response: %v", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestChatHandler_IssueStreamTicket(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := realtime.NewTicketStore(time.Minute)
+	h := &ChatHandler{}
+	h.SetStreamTickets(store)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/chats/ws-ticket", nil)
+	c.Set("user_id", 7)
+	c.Set("role_id", authz.RoleSales)
+	h.IssueStreamTicket(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Ticket    string    `json:"ticket"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := store.Redeem(resp.Ticket); !ok || got.UserID != 7 || got.RoleID != authz.RoleSales {
+		t.Fatalf("issued ticket must identify the caller, got %+v ok=%v", got, ok)
+	}
+}
+
+func TestChatHandler_StreamTicketUpgradesOnce(t *testing.T) {
+	store := realtime.NewTicketStore(time.Minute)
+	h := &ChatHandler{}
+	h.SetStreamTickets(store)
+	srv, upgradedAs := newTicketStreamServer(t, h)
+
+	ticket, _, err := store.Issue(7, authz.RoleSales)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code := dialChatStream(t, srv, ticket); code != http.StatusSwitchingProtocols {
+		t.Fatalf("valid ticket must upgrade, got %d", code)
+	}
+	select {
+	case uid := <-upgradedAs:
+		if uid != 7 {
+			t.Fatalf("stream authenticated as user %d, want 7", uid)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("upgrade handler was not reached")
+	}
+
+	if code := dialChatStream(t, srv, ticket); code != http.StatusUnauthorized {
+		t.Fatalf("reused ticket must be rejected, got %d", code)
+	}
+}
+
+func TestChatHandler_StreamTicketExpired(t *testing.T) {
+	store := realtime.NewTicketStore(time.Millisecond)
+	h := &ChatHandler{}
+	h.SetStreamTickets(store)
+	srv, upgradedAs := newTicketStreamServer(t, h)
+
+	ticket, _, err := store.Issue(7, authz.RoleSales)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if code := dialChatStream(t, srv, ticket); code != http.StatusUnauthorized {
+		t.Fatalf("expired ticket must be rejected, got %d", code)
+	}
+	if len(upgradedAs) != 0 {
+		t.Fatal("expired ticket must not reach the stream")
+	}
+}
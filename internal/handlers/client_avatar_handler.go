@@ -71,7 +71,7 @@ func (h *ClientAvatarHandler) Upload(c *gin.Context) {
 	}
 
 	// Get current client to check access and get old avatar path
-	current, err := h.service.GetByID(clientID, userID, roleID)
+	current, err := h.service.GetByID(c.Request.Context(), clientID, userID, roleID)
 	if err != nil {
 		if errors.Is(err, services.ErrForbidden) {
 			forbidden(c, "У вас нет доступа к этому клиенту")
@@ -102,7 +102,7 @@ func (h *ClientAvatarHandler) Upload(c *gin.Context) {
 	}
 
 	avatarURL := "/clients/" + strconv.Itoa(clientID) + "/avatar/content"
-	if err := h.clientRepo.UpdateAvatar(clientID, avatarURL, key); err != nil {
+	if err := h.clientRepo.UpdateAvatar(c.Request.Context(), clientID, avatarURL, key); err != nil {
 		_ = h.store.Delete(c.Request.Context(), key)
 		internalError(c, "Не удалось обновить аватар")
 		return
@@ -114,7 +114,7 @@ func (h *ClientAvatarHandler) Upload(c *gin.Context) {
 	}
 
 	// Re-fetch client for response
-	updated, err := h.service.GetByID(clientID, userID, roleID)
+	updated, err := h.service.GetByID(c.Request.Context(), clientID, userID, roleID)
 	if err != nil {
 		internalError(c, "Не удалось загрузить данные клиента")
 		return
@@ -138,7 +138,7 @@ func (h *ClientAvatarHandler) UpdateCrop(c *gin.Context) {
 	}
 
 	// Validate access
-	if _, err := h.service.GetByID(clientID, userID, roleID); err != nil {
+	if _, err := h.service.GetByID(c.Request.Context(), clientID, userID, roleID); err != nil {
 		if errors.Is(err, services.ErrForbidden) {
 			forbidden(c, "У вас нет доступа к этому клиенту")
 		} else {
@@ -156,12 +156,12 @@ func (h *ClientAvatarHandler) UpdateCrop(c *gin.Context) {
 		return
 	}
 
-	if err := h.clientRepo.UpdateAvatarCrop(clientID, req.CropX, req.CropY, req.CropScale, req.CropSize); err != nil {
+	if err := h.clientRepo.UpdateAvatarCrop(c.Request.Context(), clientID, req.CropX, req.CropY, req.CropScale, req.CropSize); err != nil {
 		internalError(c, "Не удалось обновить кроп")
 		return
 	}
 
-	updated, err := h.service.GetByID(clientID, userID, roleID)
+	updated, err := h.service.GetByID(c.Request.Context(), clientID, userID, roleID)
 	if err != nil {
 		internalError(c, "Не удалось загрузить данные клиента")
 		return
@@ -179,7 +179,7 @@ func (h *ClientAvatarHandler) Delete(c *gin.Context) {
 	userID, roleID := getUserAndRole(c)
 
 	// Get current client to check access and get old avatar path
-	current, err := h.service.GetByID(clientID, userID, roleID)
+	current, err := h.service.GetByID(c.Request.Context(), clientID, userID, roleID)
 	if err != nil {
 		if errors.Is(err, services.ErrForbidden) {
 			forbidden(c, "У вас нет доступа к этому клиенту")
@@ -197,7 +197,7 @@ func (h *ClientAvatarHandler) Delete(c *gin.Context) {
 		oldAvatarPath = *current.AvatarPath
 	}
 
-	if err := h.clientRepo.DeleteAvatar(clientID); err != nil {
+	if err := h.clientRepo.DeleteAvatar(c.Request.Context(), clientID); err != nil {
 		internalError(c, "Не удалось удалить аватар")
 		return
 	}
@@ -206,7 +206,7 @@ func (h *ClientAvatarHandler) Delete(c *gin.Context) {
 		_ = h.store.Delete(c.Request.Context(), oldAvatarPath)
 	}
 
-	updated, err := h.service.GetByID(clientID, userID, roleID)
+	updated, err := h.service.GetByID(c.Request.Context(), clientID, userID, roleID)
 	if err != nil {
 		internalError(c, "Не удалось загрузить данные клиента")
 		return
@@ -223,7 +223,7 @@ func (h *ClientAvatarHandler) Serve(c *gin.Context) {
 	}
 	userID, roleID := getUserAndRole(c)
 
-	client, err := h.service.GetByID(clientID, userID, roleID)
+	client, err := h.service.GetByID(c.Request.Context(), clientID, userID, roleID)
 	if err != nil || client == nil {
 		if errors.Is(err, services.ErrForbidden) {
 			forbidden(c, "У вас нет доступа к этому клиенту")
@@ -32,7 +32,7 @@ func NewClientDocumentsHandler(
 }
 
 func (h *ClientDocumentsHandler) ensureClientAccess(c *gin.Context, clientID int) (*models.Client, bool) {
-	client, err := h.clientRepo.GetByID(clientID)
+	client, err := h.clientRepo.GetByID(c.Request.Context(), clientID)
 	if err != nil || client == nil {
 		if errors.Is(err, services.ErrForbidden) {
 			forbidden(c, "У вас нет доступа к этому клиенту")
@@ -65,7 +65,7 @@ func (h *ClientDocumentsHandler) ListDocuments(c *gin.Context) {
 	}
 
 	clientID64 := int64(clientID)
-	userID, _ := getUserAndRole(c)
+	userID, roleID := getUserAndRole(c)
 
 	filter := repositories.DocumentListFilter{
 		ClientID: &clientID64,
@@ -90,13 +90,13 @@ func (h *ClientDocumentsHandler) ListDocuments(c *gin.Context) {
 	hiddenUserID := userID
 	filter.HiddenVisibilityUserID = &hiddenUserID
 
-	total, err := h.docRepo.CountDocumentsWithFilterAndArchiveScope(filter, archiveScope)
+	total, err := h.docRepo.CountDocumentsWithFilterAndArchiveScope(c.Request.Context(), filter, archiveScope)
 	if err != nil {
 		internalError(c, "Не удалось получить количество документов")
 		return
 	}
 
-	docs, err := h.docRepo.ListDocumentsWithFilterAndArchiveScope(size, offset, filter, archiveScope)
+	docs, err := h.docRepo.ListDocumentsWithFilterAndArchiveScope(c.Request.Context(), size, offset, filter, archiveScope)
 	if err != nil {
 		internalError(c, "Не удалось загрузить документы")
 		return
@@ -106,7 +106,7 @@ func (h *ClientDocumentsHandler) ListDocuments(c *gin.Context) {
 	}
 
 	c.JSON(200, gin.H{
-		"items": docs,
+		"items": documentsViewForRole(docs, roleID),
 		"total": total,
 		"page":  page,
 		"size":  size,
@@ -152,6 +152,7 @@ func (h *ClientDocumentsHandler) CreateDocument(c *gin.Context) {
 	}
 
 	doc, err := h.docService.CreateDocumentFromClient(
+		c.Request.Context(),
 		clientID,
 		clientType,
 		dealID,
@@ -168,7 +169,7 @@ func (h *ClientDocumentsHandler) CreateDocument(c *gin.Context) {
 	// Set client_id on the newly created document
 	if doc != nil {
 		clientID64 := int64(clientID)
-		_ = h.docRepo.SetClientID(doc.ID, clientID64)
+		_ = h.docRepo.SetClientID(c.Request.Context(), doc.ID, clientID64)
 		doc.ClientID = &clientID64
 	}
 
@@ -20,27 +20,37 @@ import (
 )
 
 type clientService interface {
-	Create(c *models.Client, userID, roleID int) (int64, error)
-	Update(c *models.Client, userID, roleID int) error
-	Delete(id int, userID, roleID int) error
-	ArchiveClient(id, userID, roleID int, reason string) error
-	UnarchiveClient(id, userID, roleID int) error
-	Patch(id int, updates map[string]any, userID, roleID int) (*models.Client, error)
-	GetByID(id int, userID, roleID int) (*models.Client, error)
-	GetByIDWithArchiveScope(id int, userID, roleID int, scope repositories.ArchiveScope) (*models.Client, error)
-	ListForRole(userID, roleID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, error)
-	ListMineWithArchiveScope(userID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, error)
-	ListIndividualsForRole(userID, roleID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, error)
-	ListCompaniesForRole(userID, roleID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, error)
+	Create(ctx context.Context, c *models.Client, userID, roleID int) (int64, error)
+	Update(ctx context.Context, c *models.Client, userID, roleID int) error
+	Delete(ctx context.Context, id int, userID, roleID int) error
+	ArchiveClient(ctx context.Context, id, userID, roleID int, reason string) error
+	UnarchiveClient(ctx context.Context, id, userID, roleID int) error
+	Patch(ctx context.Context, id int, updates map[string]any, userID, roleID int) (*models.Client, error)
+	GetByID(ctx context.Context, id int, userID, roleID int) (*models.Client, error)
+	GetByIDWithArchiveScope(ctx context.Context, id int, userID, roleID int, scope repositories.ArchiveScope) (*models.Client, error)
+	ListForRole(ctx context.Context, userID, roleID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, error)
+	ListMineWithArchiveScope(ctx context.Context, userID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, error)
+	ListIndividualsForRole(ctx context.Context, userID, roleID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, error)
+	ListCompaniesForRole(ctx context.Context, userID, roleID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, error)
 	GetMissingYellow(ctx context.Context, clientID, userID, roleID int) ([]string, error)
 	GetProfile(ctx context.Context, clientID, userID, roleID int) (*services.ClientProfilePayload, error)
 }
 
 type clientPaginationService interface {
-	ListForRoleWithTotal(userID, roleID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, int, error)
-	ListMineWithArchiveScopeAndTotal(userID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, int, error)
-	ListIndividualsForRoleWithTotal(userID, roleID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, int, error)
-	ListCompaniesForRoleWithTotal(userID, roleID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, int, error)
+	ListForRoleWithTotal(ctx context.Context, userID, roleID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, int, error)
+	ListMineWithArchiveScopeAndTotal(ctx context.Context, userID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, int, error)
+	ListIndividualsForRoleWithTotal(ctx context.Context, userID, roleID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, int, error)
+	ListCompaniesForRoleWithTotal(ctx context.Context, userID, roleID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, int, error)
+}
+
+// clientSearcher is implemented by services that support GET /clients/search.
+type clientSearcher interface {
+	SearchForRole(ctx context.Context, userID, roleID int, q string, limit, offset int) ([]*models.Client, int, error)
+}
+
+// clientMerger is implemented by services that support POST /clients/merge.
+type clientMerger interface {
+	Merge(ctx context.Context, primaryID, duplicateID, userID, roleID int) (int64, error)
 }
 
 type ClientHandler struct {
@@ -317,7 +327,7 @@ func (h *ClientHandler) Create(c *gin.Context) {
 
 	var req createClientRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		badRequest(c, "Некорректные данные клиента")
+		bindError(c, err, "Некорректные данные клиента")
 		return
 	}
 
@@ -355,7 +365,7 @@ func (h *ClientHandler) Create(c *gin.Context) {
 	}
 
 	client := buildClientFromCreateRequest(req, userID, birthDate, passportIssueDate, passportExpireDate, driverLicenseIssueDate, driverLicenseExpireDate)
-	id, err := h.Service.Create(client, userID, roleID)
+	id, err := h.Service.Create(c.Request.Context(), client, userID, roleID)
 	if err != nil {
 		h.writeCreateClientError(c, err, userID, roleID)
 		return
@@ -444,7 +454,7 @@ func (h *ClientHandler) Update(c *gin.Context) {
 		badRequest(c, "Некорректный ID клиента")
 		return
 	}
-	current, err := h.Service.GetByID(id, userID, roleID)
+	current, err := h.Service.GetByID(c.Request.Context(), id, userID, roleID)
 	if err != nil || current == nil {
 		if errors.Is(err, services.ErrForbidden) {
 			forbidden(c, "У вас нет доступа к этому клиенту")
@@ -455,7 +465,7 @@ func (h *ClientHandler) Update(c *gin.Context) {
 	}
 	var req updateClientRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		badRequest(c, "Некорректные данные клиента")
+		bindError(c, err, "Некорректные данные клиента")
 		return
 	}
 	birthDate, err := parseDateField("birth_date", req.BirthDate, false)
@@ -595,7 +605,7 @@ func (h *ClientHandler) Update(c *gin.Context) {
 	if req.LegalProfile != nil {
 		current.LegalProfile = req.LegalProfile
 	}
-	if err := h.Service.Update(current, userID, roleID); err != nil {
+	if err := h.Service.Update(c.Request.Context(), current, userID, roleID); err != nil {
 		if errors.Is(err, services.ErrClientAlreadyExists) {
 			conflict(c, ClientAlreadyExists, "Клиент с таким БИН/ИИН уже существует")
 			return
@@ -666,7 +676,7 @@ func (h *ClientHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	err = h.Service.Delete(id, userID, roleID)
+	err = h.Service.Delete(c.Request.Context(), id, userID, roleID)
 	if err != nil {
 		if errors.Is(err, services.ErrForbidden) || errors.Is(err, services.ErrReadOnly) {
 			forbidden(c, "У вас нет права удалить этого клиента")
@@ -687,6 +697,57 @@ func (h *ClientHandler) Delete(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+type mergeClientsRequest struct {
+	PrimaryID   int `json:"primary_id" binding:"required"`
+	DuplicateID int `json:"duplicate_id" binding:"required"`
+}
+
+// Merge consolidates a duplicate client into the primary one: its deals and
+// other links move to the primary and the duplicate is deleted.
+func (h *ClientHandler) Merge(c *gin.Context) {
+	userID, roleID := getUserAndRole(c)
+	if !authz.CanHardDeleteBusinessEntity(roleID) {
+		forbidden(c, "У вас нет права объединять клиентов")
+		return
+	}
+	var req mergeClientsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, "Укажите primary_id и duplicate_id")
+		return
+	}
+	if req.PrimaryID == req.DuplicateID {
+		badRequest(c, "Нельзя объединить клиента с самим собой")
+		return
+	}
+	merger, ok := h.Service.(clientMerger)
+	if !ok {
+		internalError(c, "Объединение клиентов не поддерживается")
+		return
+	}
+	moved, err := merger.Merge(c.Request.Context(), req.PrimaryID, req.DuplicateID, userID, roleID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrForbidden):
+			forbidden(c, "У вас нет права объединять клиентов")
+		case errors.Is(err, services.ErrClientNotFound):
+			notFound(c, ClientNotFoundCode, "Клиент не найден")
+		case errors.Is(err, services.ErrClientMergeSameClient):
+			badRequest(c, "Нельзя объединить клиента с самим собой")
+		case errors.Is(err, services.ErrClientIDRequired):
+			badRequest(c, "Укажите primary_id и duplicate_id")
+		default:
+			log.Printf("ClientHandler.Merge error: primary_id=%d duplicate_id=%d err=%v", req.PrimaryID, req.DuplicateID, err)
+			internalError(c, "Не удалось объединить клиентов")
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"primary_id":   req.PrimaryID,
+		"duplicate_id": req.DuplicateID,
+		"moved_deals":  moved,
+	})
+}
+
 type archiveClientRequest struct {
 	Reason string `json:"reason"`
 }
@@ -703,7 +764,7 @@ func (h *ClientHandler) Archive(c *gin.Context) {
 		return
 	}
 	userID, roleID := getUserAndRole(c)
-	if err := h.Service.ArchiveClient(id, userID, roleID, req.Reason); err != nil {
+	if err := h.Service.ArchiveClient(c.Request.Context(), id, userID, roleID, req.Reason); err != nil {
 		if errors.Is(err, services.ErrForbidden) || errors.Is(err, services.ErrReadOnly) {
 			forbidden(c, "У вас нет права архивировать этого клиента")
 			return
@@ -715,7 +776,7 @@ func (h *ClientHandler) Archive(c *gin.Context) {
 		internalError(c, "Не удалось архивировать клиента")
 		return
 	}
-	client, _ := h.Service.GetByIDWithArchiveScope(id, userID, roleID, repositories.ArchiveScopeAll)
+	client, _ := h.Service.GetByIDWithArchiveScope(c.Request.Context(), id, userID, roleID, repositories.ArchiveScopeAll)
 	c.JSON(http.StatusOK, client)
 }
 
@@ -726,7 +787,7 @@ func (h *ClientHandler) Unarchive(c *gin.Context) {
 		return
 	}
 	userID, roleID := getUserAndRole(c)
-	if err := h.Service.UnarchiveClient(id, userID, roleID); err != nil {
+	if err := h.Service.UnarchiveClient(c.Request.Context(), id, userID, roleID); err != nil {
 		if errors.Is(err, services.ErrForbidden) || errors.Is(err, services.ErrReadOnly) {
 			forbidden(c, "У вас нет права разархивировать этого клиента")
 			return
@@ -742,7 +803,7 @@ func (h *ClientHandler) Unarchive(c *gin.Context) {
 		internalError(c, "Не удалось разархивировать клиента")
 		return
 	}
-	client, _ := h.Service.GetByID(id, userID, roleID)
+	client, _ := h.Service.GetByID(c.Request.Context(), id, userID, roleID)
 	c.JSON(http.StatusOK, client)
 }
 
@@ -827,7 +888,7 @@ func (h *ClientHandler) Patch(c *gin.Context) {
 		}
 		updates[field] = t
 	}
-	updated, err := h.Service.Patch(id, updates, userID, roleID)
+	updated, err := h.Service.Patch(c.Request.Context(), id, updates, userID, roleID)
 	if err != nil {
 		if errors.Is(err, services.ErrClientNotFound) {
 			notFound(c, ClientNotFoundCode, "Клиент не найден")
@@ -907,7 +968,7 @@ func (h *ClientHandler) GetByID(c *gin.Context) {
 		return
 	}
 	userID, roleID := getUserAndRole(c)
-	client, err := h.Service.GetByID(id, userID, roleID)
+	client, err := h.Service.GetByID(c.Request.Context(), id, userID, roleID)
 	if err != nil || client == nil {
 		if errors.Is(err, services.ErrForbidden) {
 			forbidden(c, "У вас нет доступа к этому клиенту")
@@ -974,13 +1035,13 @@ func (h *ClientHandler) listByPresetType(c *gin.Context, kind string) {
 				return
 			}
 			var total int
-			clients, total, err = pSvc.ListIndividualsForRoleWithTotal(userID, roleID, size, offset, filter, scope)
+			clients, total, err = pSvc.ListIndividualsForRoleWithTotal(c.Request.Context(), userID, roleID, size, offset, filter, scope)
 			if err == nil {
-				c.JSON(http.StatusOK, models.PaginatedResponse[*models.Client]{Items: clients, Pagination: buildPaginationMeta(page, size, total)})
+				writePaginated(c, clients, page, size, total)
 				return
 			}
 		} else {
-			clients, err = h.Service.ListIndividualsForRole(userID, roleID, size, offset, filter, scope)
+			clients, err = h.Service.ListIndividualsForRole(c.Request.Context(), userID, roleID, size, offset, filter, scope)
 		}
 	case "company":
 		if paginate {
@@ -990,13 +1051,13 @@ func (h *ClientHandler) listByPresetType(c *gin.Context, kind string) {
 				return
 			}
 			var total int
-			clients, total, err = pSvc.ListCompaniesForRoleWithTotal(userID, roleID, size, offset, filter, scope)
+			clients, total, err = pSvc.ListCompaniesForRoleWithTotal(c.Request.Context(), userID, roleID, size, offset, filter, scope)
 			if err == nil {
-				c.JSON(http.StatusOK, models.PaginatedResponse[*models.Client]{Items: clients, Pagination: buildPaginationMeta(page, size, total)})
+				writePaginated(c, clients, page, size, total)
 				return
 			}
 		} else {
-			clients, err = h.Service.ListCompaniesForRole(userID, roleID, size, offset, filter, scope)
+			clients, err = h.Service.ListCompaniesForRole(c.Request.Context(), userID, roleID, size, offset, filter, scope)
 		}
 	default:
 		badRequest(c, "Некорректный тип списка клиентов")
@@ -1051,32 +1112,32 @@ func (h *ClientHandler) List(c *gin.Context) {
 			internalError(c, "Постраничная загрузка клиентов не поддерживается")
 			return
 		}
-		clients, total, err := pSvc.ListForRoleWithTotal(userID, roleID, size, offset, filter, scope)
+		clients, total, err := pSvc.ListForRoleWithTotal(c.Request.Context(), userID, roleID, size, offset, filter, scope)
 		if err != nil {
 			log.Printf("ClientHandler.List error: user_id=%d role_id=%d page=%d size=%d filter=%+v err=%v", userID, roleID, page, size, filter, err)
 			if errors.Is(err, services.ErrForbidden) {
 				forbidden(c, "У вас нет доступа к списку клиентов")
 				return
 			}
-			if strings.Contains(err.Error(), "invalid client_type") {
+			if errors.Is(err, services.ErrInvalidClientType) {
 				badRequest(c, "Некорректный тип клиента: выберите физическое или юридическое лицо")
 				return
 			}
 			internalError(c, "Не удалось загрузить список клиентов")
 			return
 		}
-		c.JSON(http.StatusOK, models.PaginatedResponse[*models.Client]{Items: clients, Pagination: buildPaginationMeta(page, size, total)})
+		writePaginated(c, clients, page, size, total)
 		return
 	}
 
-	clients, err := h.Service.ListForRole(userID, roleID, size, offset, filter, scope)
+	clients, err := h.Service.ListForRole(c.Request.Context(), userID, roleID, size, offset, filter, scope)
 	if err != nil {
 		log.Printf("ClientHandler.List error: user_id=%d role_id=%d page=%d size=%d filter=%+v err=%v", userID, roleID, page, size, filter, err)
 		if errors.Is(err, services.ErrForbidden) {
 			forbidden(c, "У вас нет доступа к списку клиентов")
 			return
 		}
-		if strings.Contains(err.Error(), "invalid client_type") {
+		if errors.Is(err, services.ErrInvalidClientType) {
 			badRequest(c, "Некорректный тип клиента: выберите физическое или юридическое лицо")
 			return
 		}
@@ -1086,6 +1147,35 @@ func (h *ClientHandler) List(c *gin.Context) {
 	c.JSON(http.StatusOK, clients)
 }
 
+// Search finds clients by exact BIN or name substring for a quick lookup
+// before converting a lead. Results are scoped like the client list and
+// always paginated.
+func (h *ClientHandler) Search(c *gin.Context) {
+	userID, roleID := getUserAndRole(c)
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		badRequest(c, "Укажите строку поиска q")
+		return
+	}
+	searcher, ok := h.Service.(clientSearcher)
+	if !ok {
+		internalError(c, "Поиск клиентов не поддерживается")
+		return
+	}
+	page, size := normalizedPageAndSize(c)
+	clients, total, err := searcher.SearchForRole(c.Request.Context(), userID, roleID, q, size, offsetFromPage(page, size))
+	if err != nil {
+		log.Printf("ClientHandler.Search error: user_id=%d role_id=%d q=%q err=%v", userID, roleID, q, err)
+		if errors.Is(err, services.ErrForbidden) {
+			forbidden(c, "У вас нет доступа к списку клиентов")
+			return
+		}
+		internalError(c, "Не удалось выполнить поиск клиентов")
+		return
+	}
+	writePaginated(c, clients, page, size, total)
+}
+
 func (h *ClientHandler) ListMy(c *gin.Context) {
 	userID, roleID := getUserAndRole(c)
 	paginate := isPaginatedMode(c)
@@ -1120,32 +1210,32 @@ func (h *ClientHandler) ListMy(c *gin.Context) {
 			internalError(c, "Постраничная загрузка клиентов не поддерживается")
 			return
 		}
-		clients, total, err := pSvc.ListMineWithArchiveScopeAndTotal(userID, size, offset, filter, scope)
+		clients, total, err := pSvc.ListMineWithArchiveScopeAndTotal(c.Request.Context(), userID, size, offset, filter, scope)
 		if err != nil {
 			log.Printf("ClientHandler.ListMy error: user_id=%d role_id=%d page=%d size=%d filter=%+v err=%v", userID, roleID, page, size, filter, err)
 			if errors.Is(err, services.ErrForbidden) {
 				forbidden(c, "У вас нет доступа к списку клиентов")
 				return
 			}
-			if strings.Contains(err.Error(), "invalid client_type") {
+			if errors.Is(err, services.ErrInvalidClientType) {
 				badRequest(c, "Некорректный тип клиента: выберите физическое или юридическое лицо")
 				return
 			}
 			internalError(c, "Не удалось загрузить список клиентов")
 			return
 		}
-		c.JSON(http.StatusOK, models.PaginatedResponse[*models.Client]{Items: clients, Pagination: buildPaginationMeta(page, size, total)})
+		writePaginated(c, clients, page, size, total)
 		return
 	}
 
-	clients, err := h.Service.ListMineWithArchiveScope(userID, size, offset, filter, scope)
+	clients, err := h.Service.ListMineWithArchiveScope(c.Request.Context(), userID, size, offset, filter, scope)
 	if err != nil {
 		log.Printf("ClientHandler.ListMy error: user_id=%d role_id=%d page=%d size=%d filter=%+v err=%v", userID, roleID, page, size, filter, err)
 		if errors.Is(err, services.ErrForbidden) {
 			forbidden(c, "У вас нет доступа к списку клиентов")
 			return
 		}
-		if strings.Contains(err.Error(), "invalid client_type") {
+		if errors.Is(err, services.ErrInvalidClientType) {
 			badRequest(c, "Некорректный тип клиента: выберите физическое или юридическое лицо")
 			return
 		}
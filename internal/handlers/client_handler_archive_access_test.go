@@ -22,36 +22,45 @@ type stubClientArchiveService struct {
 	deleted      bool
 }
 
-func (s *stubClientArchiveService) Create(*models.Client, int, int) (int64, error) { return 1, nil }
-func (s *stubClientArchiveService) Update(*models.Client, int, int) error          { return nil }
-func (s *stubClientArchiveService) Delete(int, int, int) error                     { s.deleted = true; return nil }
-func (s *stubClientArchiveService) ArchiveClient(int, int, int, string) error      { return nil }
-func (s *stubClientArchiveService) UnarchiveClient(int, int, int) error            { return nil }
-func (s *stubClientArchiveService) Patch(int, map[string]any, int, int) (*models.Client, error) {
+func (s *stubClientArchiveService) Create(context.Context, *models.Client, int, int) (int64, error) {
+	return 1, nil
+}
+func (s *stubClientArchiveService) Update(context.Context, *models.Client, int, int) error {
+	return nil
+}
+func (s *stubClientArchiveService) Delete(context.Context, int, int, int) error {
+	s.deleted = true
+	return nil
+}
+func (s *stubClientArchiveService) ArchiveClient(context.Context, int, int, int, string) error {
+	return nil
+}
+func (s *stubClientArchiveService) UnarchiveClient(context.Context, int, int, int) error { return nil }
+func (s *stubClientArchiveService) Patch(context.Context, int, map[string]any, int, int) (*models.Client, error) {
 	return nil, nil
 }
-func (s *stubClientArchiveService) GetByID(id int, userID, roleID int) (*models.Client, error) {
+func (s *stubClientArchiveService) GetByID(_ context.Context, id int, userID, roleID int) (*models.Client, error) {
 	return &models.Client{ID: id, OwnerID: userID}, nil
 }
-func (s *stubClientArchiveService) GetByIDWithArchiveScope(id int, userID, roleID int, scope repositories.ArchiveScope) (*models.Client, error) {
+func (s *stubClientArchiveService) GetByIDWithArchiveScope(_ context.Context, id int, userID, roleID int, scope repositories.ArchiveScope) (*models.Client, error) {
 	return &models.Client{ID: id, OwnerID: userID, IsArchived: scope == repositories.ArchiveScopeArchivedOnly}, nil
 }
-func (s *stubClientArchiveService) ListForRole(userID, roleID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, error) {
+func (s *stubClientArchiveService) ListForRole(_ context.Context, userID, roleID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, error) {
 	s.archiveScope = scope
 	s.lastFilter = filter
 	return []*models.Client{}, nil
 }
-func (s *stubClientArchiveService) ListMineWithArchiveScope(userID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, error) {
+func (s *stubClientArchiveService) ListMineWithArchiveScope(_ context.Context, userID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, error) {
 	s.archiveScope = scope
 	s.lastFilter = filter
 	return []*models.Client{}, nil
 }
-func (s *stubClientArchiveService) ListIndividualsForRole(userID, roleID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, error) {
+func (s *stubClientArchiveService) ListIndividualsForRole(_ context.Context, userID, roleID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, error) {
 	s.archiveScope = scope
 	s.lastFilter = filter
 	return []*models.Client{}, nil
 }
-func (s *stubClientArchiveService) ListCompaniesForRole(userID, roleID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, error) {
+func (s *stubClientArchiveService) ListCompaniesForRole(_ context.Context, userID, roleID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, error) {
 	s.archiveScope = scope
 	s.lastFilter = filter
 	return []*models.Client{}, nil
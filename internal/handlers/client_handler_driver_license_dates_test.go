@@ -24,40 +24,44 @@ type driverDateStubClientService struct {
 	createErr error
 }
 
-func (s *driverDateStubClientService) Create(c *models.Client, userID, roleID int) (int64, error) {
+func (s *driverDateStubClientService) Create(_ context.Context, c *models.Client, userID, roleID int) (int64, error) {
 	s.created = c
 	if s.createErr != nil {
 		return 0, s.createErr
 	}
 	return 1, nil
 }
-func (s *driverDateStubClientService) Update(c *models.Client, userID, roleID int) error {
+func (s *driverDateStubClientService) Update(_ context.Context, c *models.Client, userID, roleID int) error {
 	s.updated = c
 	return nil
 }
-func (s *driverDateStubClientService) Delete(int, int, int) error                { return nil }
-func (s *driverDateStubClientService) ArchiveClient(int, int, int, string) error { return nil }
-func (s *driverDateStubClientService) UnarchiveClient(int, int, int) error       { return nil }
-func (s *driverDateStubClientService) Patch(id int, updates map[string]any, userID, roleID int) (*models.Client, error) {
+func (s *driverDateStubClientService) Delete(context.Context, int, int, int) error { return nil }
+func (s *driverDateStubClientService) ArchiveClient(context.Context, int, int, int, string) error {
+	return nil
+}
+func (s *driverDateStubClientService) UnarchiveClient(context.Context, int, int, int) error {
+	return nil
+}
+func (s *driverDateStubClientService) Patch(_ context.Context, id int, updates map[string]any, userID, roleID int) (*models.Client, error) {
 	s.patched = updates
 	return &models.Client{ID: id}, nil
 }
-func (s *driverDateStubClientService) GetByID(id int, userID, roleID int) (*models.Client, error) {
+func (s *driverDateStubClientService) GetByID(_ context.Context, id int, userID, roleID int) (*models.Client, error) {
 	return &models.Client{ID: id, OwnerID: userID}, nil
 }
-func (s *driverDateStubClientService) GetByIDWithArchiveScope(id int, userID, roleID int, scope repositories.ArchiveScope) (*models.Client, error) {
+func (s *driverDateStubClientService) GetByIDWithArchiveScope(_ context.Context, id int, userID, roleID int, scope repositories.ArchiveScope) (*models.Client, error) {
 	return &models.Client{ID: id}, nil
 }
-func (s *driverDateStubClientService) ListForRole(userID, roleID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, error) {
+func (s *driverDateStubClientService) ListForRole(_ context.Context, userID, roleID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, error) {
 	return nil, nil
 }
-func (s *driverDateStubClientService) ListMineWithArchiveScope(userID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, error) {
+func (s *driverDateStubClientService) ListMineWithArchiveScope(_ context.Context, userID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, error) {
 	return nil, nil
 }
-func (s *driverDateStubClientService) ListIndividualsForRole(userID, roleID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, error) {
+func (s *driverDateStubClientService) ListIndividualsForRole(_ context.Context, userID, roleID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, error) {
 	return nil, nil
 }
-func (s *driverDateStubClientService) ListCompaniesForRole(userID, roleID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, error) {
+func (s *driverDateStubClientService) ListCompaniesForRole(_ context.Context, userID, roleID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, error) {
 	return nil, nil
 }
 func (s *driverDateStubClientService) GetMissingYellow(context.Context, int, int, int) ([]string, error) {
@@ -23,39 +23,41 @@ type stubClientListMyService struct {
 	total      int
 }
 
-func (s *stubClientListMyService) Create(*models.Client, int, int) (int64, error) {
+func (s *stubClientListMyService) Create(context.Context, *models.Client, int, int) (int64, error) {
 	return 0, errors.New("not implemented")
 }
-func (s *stubClientListMyService) Update(*models.Client, int, int) error {
+func (s *stubClientListMyService) Update(context.Context, *models.Client, int, int) error {
 	return errors.New("not implemented")
 }
-func (s *stubClientListMyService) Delete(int, int, int) error { return errors.New("not implemented") }
-func (s *stubClientListMyService) ArchiveClient(int, int, int, string) error {
+func (s *stubClientListMyService) Delete(context.Context, int, int, int) error {
 	return errors.New("not implemented")
 }
-func (s *stubClientListMyService) UnarchiveClient(int, int, int) error {
+func (s *stubClientListMyService) ArchiveClient(context.Context, int, int, int, string) error {
 	return errors.New("not implemented")
 }
-func (s *stubClientListMyService) Patch(int, map[string]any, int, int) (*models.Client, error) {
+func (s *stubClientListMyService) UnarchiveClient(context.Context, int, int, int) error {
+	return errors.New("not implemented")
+}
+func (s *stubClientListMyService) Patch(context.Context, int, map[string]any, int, int) (*models.Client, error) {
 	return nil, errors.New("not implemented")
 }
-func (s *stubClientListMyService) GetByID(int, int, int) (*models.Client, error) {
+func (s *stubClientListMyService) GetByID(context.Context, int, int, int) (*models.Client, error) {
 	return nil, errors.New("not implemented")
 }
-func (s *stubClientListMyService) GetByIDWithArchiveScope(int, int, int, repositories.ArchiveScope) (*models.Client, error) {
+func (s *stubClientListMyService) GetByIDWithArchiveScope(context.Context, int, int, int, repositories.ArchiveScope) (*models.Client, error) {
 	return nil, errors.New("not implemented")
 }
-func (s *stubClientListMyService) ListForRole(int, int, int, int, repositories.ClientListFilter, repositories.ArchiveScope) ([]*models.Client, error) {
+func (s *stubClientListMyService) ListForRole(context.Context, int, int, int, int, repositories.ClientListFilter, repositories.ArchiveScope) ([]*models.Client, error) {
 	return nil, errors.New("not implemented")
 }
-func (s *stubClientListMyService) ListMineWithArchiveScope(_ int, _ int, _ int, filter repositories.ClientListFilter, _ repositories.ArchiveScope) ([]*models.Client, error) {
+func (s *stubClientListMyService) ListMineWithArchiveScope(_ context.Context, _ int, _ int, _ int, filter repositories.ClientListFilter, _ repositories.ArchiveScope) ([]*models.Client, error) {
 	s.lastFilter = filter
 	return s.clients, s.err
 }
-func (s *stubClientListMyService) ListIndividualsForRole(int, int, int, int, repositories.ClientListFilter, repositories.ArchiveScope) ([]*models.Client, error) {
+func (s *stubClientListMyService) ListIndividualsForRole(context.Context, int, int, int, int, repositories.ClientListFilter, repositories.ArchiveScope) ([]*models.Client, error) {
 	return nil, errors.New("not implemented")
 }
-func (s *stubClientListMyService) ListCompaniesForRole(int, int, int, int, repositories.ClientListFilter, repositories.ArchiveScope) ([]*models.Client, error) {
+func (s *stubClientListMyService) ListCompaniesForRole(context.Context, int, int, int, int, repositories.ClientListFilter, repositories.ArchiveScope) ([]*models.Client, error) {
 	return nil, errors.New("not implemented")
 }
 func (s *stubClientListMyService) GetMissingYellow(_ context.Context, _, _, _ int) ([]string, error) {
@@ -64,17 +66,17 @@ func (s *stubClientListMyService) GetMissingYellow(_ context.Context, _, _, _ in
 func (s *stubClientListMyService) GetProfile(_ context.Context, _, _, _ int) (*services.ClientProfilePayload, error) {
 	return nil, errors.New("not implemented")
 }
-func (s *stubClientListMyService) ListMineWithArchiveScopeAndTotal(_ int, _ int, _ int, filter repositories.ClientListFilter, _ repositories.ArchiveScope) ([]*models.Client, int, error) {
+func (s *stubClientListMyService) ListMineWithArchiveScopeAndTotal(_ context.Context, _ int, _ int, _ int, filter repositories.ClientListFilter, _ repositories.ArchiveScope) ([]*models.Client, int, error) {
 	s.lastFilter = filter
 	return s.clients, s.total, s.err
 }
-func (s *stubClientListMyService) ListForRoleWithTotal(int, int, int, int, repositories.ClientListFilter, repositories.ArchiveScope) ([]*models.Client, int, error) {
+func (s *stubClientListMyService) ListForRoleWithTotal(context.Context, int, int, int, int, repositories.ClientListFilter, repositories.ArchiveScope) ([]*models.Client, int, error) {
 	return nil, 0, errors.New("not implemented")
 }
-func (s *stubClientListMyService) ListIndividualsForRoleWithTotal(int, int, int, int, repositories.ClientListFilter, repositories.ArchiveScope) ([]*models.Client, int, error) {
+func (s *stubClientListMyService) ListIndividualsForRoleWithTotal(context.Context, int, int, int, int, repositories.ClientListFilter, repositories.ArchiveScope) ([]*models.Client, int, error) {
 	return nil, 0, errors.New("not implemented")
 }
-func (s *stubClientListMyService) ListCompaniesForRoleWithTotal(int, int, int, int, repositories.ClientListFilter, repositories.ArchiveScope) ([]*models.Client, int, error) {
+func (s *stubClientListMyService) ListCompaniesForRoleWithTotal(context.Context, int, int, int, int, repositories.ClientListFilter, repositories.ArchiveScope) ([]*models.Client, int, error) {
 	return nil, 0, errors.New("not implemented")
 }
 
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/services"
+)
+
+type stubClientMergeService struct {
+	stubClientListMyService
+	calls       int
+	primaryID   int
+	duplicateID int
+	moved       int64
+	mergeErr    error
+}
+
+func (s *stubClientMergeService) Merge(_ context.Context, primaryID, duplicateID, _, _ int) (int64, error) {
+	s.calls++
+	s.primaryID, s.duplicateID = primaryID, duplicateID
+	return s.moved, s.mergeErr
+}
+
+func performClientMerge(t *testing.T, svc *stubClientMergeService, roleID int, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	h := &ClientHandler{Service: svc}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/clients/merge", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("user_id", 1)
+	c.Set("role_id", roleID)
+	h.Merge(c)
+	return w
+}
+
+func TestClientHandler_Merge_ReportsMovedDeals(t *testing.T) {
+	svc := &stubClientMergeService{moved: 2}
+	w := performClientMerge(t, svc, authz.RoleSystemAdmin, `{"primary_id":5,"duplicate_id":9}`)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if svc.primaryID != 5 || svc.duplicateID != 9 {
+		t.Fatalf("unexpected merge args primary=%d duplicate=%d", svc.primaryID, svc.duplicateID)
+	}
+	var resp struct {
+		MovedDeals int64 `json:"moved_deals"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.MovedDeals != 2 {
+		t.Fatalf("unexpected response %s (err=%v)", w.Body.String(), err)
+	}
+}
+
+func TestClientHandler_Merge_Rejections(t *testing.T) {
+	cases := []struct {
+		name     string
+		roleID   int
+		body     string
+		mergeErr error
+		want     int
+		wantCode string
+		called   bool
+	}{
+		{"non admin", authz.RoleManagement, `{"primary_id":5,"duplicate_id":9}`, nil, http.StatusForbidden, ForbiddenCode, false},
+		{"same ids", authz.RoleSystemAdmin, `{"primary_id":5,"duplicate_id":5}`, nil, http.StatusBadRequest, BadRequestCode, false},
+		{"missing id", authz.RoleSystemAdmin, `{"primary_id":5}`, nil, http.StatusBadRequest, BadRequestCode, false},
+		{"client missing", authz.RoleSystemAdmin, `{"primary_id":5,"duplicate_id":9}`, services.ErrClientNotFound, http.StatusNotFound, ClientNotFoundCode, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &stubClientMergeService{mergeErr: tc.mergeErr}
+			w := performClientMerge(t, svc, tc.roleID, tc.body)
+			if w.Code != tc.want {
+				t.Fatalf("expected %d, got %d: %s", tc.want, w.Code, w.Body.String())
+			}
+			var apiErr APIError
+			if err := json.Unmarshal(w.Body.Bytes(), &apiErr); err != nil || apiErr.ErrorCode != tc.wantCode {
+				t.Fatalf("expected code %s, got %s", tc.wantCode, w.Body.String())
+			}
+			if (svc.calls > 0) != tc.called {
+				t.Fatalf("service called=%v, want %v", svc.calls > 0, tc.called)
+			}
+		})
+	}
+}
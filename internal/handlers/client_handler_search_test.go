@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/models"
+)
+
+type stubClientSearchService struct {
+	stubClientListMyService
+	lastQ      string
+	lastLimit  int
+	lastOffset int
+}
+
+func (s *stubClientSearchService) SearchForRole(_ context.Context, _, _ int, q string, limit, offset int) ([]*models.Client, int, error) {
+	s.lastQ, s.lastLimit, s.lastOffset = q, limit, offset
+	return s.clients, s.total, s.err
+}
+
+func TestClientHandler_Search_ReturnsPaginatedMatches(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := &stubClientSearchService{}
+	svc.total = 3
+	svc.clients = []*models.Client{{ID: 7, Name: "Alpha Travel", BinIin: "123456789012"}}
+	h := &ClientHandler{Service: svc}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/clients/search?q=%20alpha%20&page=2&size=1", nil)
+	c.Set("user_id", 101)
+	c.Set("role_id", 10)
+
+	h.Search(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if svc.lastQ != "alpha" || svc.lastLimit != 1 || svc.lastOffset != 1 {
+		t.Fatalf("unexpected search args q=%q limit=%d offset=%d", svc.lastQ, svc.lastLimit, svc.lastOffset)
+	}
+	var resp models.PaginatedResponse[models.Client]
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Items) != 1 || resp.Items[0].ID != 7 || resp.Pagination.Total != 3 {
+		t.Fatalf("unexpected response: %s", w.Body.String())
+	}
+}
+
+func TestClientHandler_Search_RequiresQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := &ClientHandler{Service: &stubClientSearchService{}}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/clients/search?q=+", nil)
+	c.Set("user_id", 101)
+	c.Set("role_id", 10)
+
+	h.Search(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"io"
 	"log"
@@ -22,23 +23,23 @@ type DealHandler struct {
 }
 
 type dealService interface {
-	Create(deal *models.Deals, userID, roleID int) (int64, error)
-	Update(deal *models.Deals, userID, roleID int) error
-	GetByID(id int, userID, roleID int) (*models.Deals, error)
-	Delete(id, userID, roleID int) error
-	ListForRole(userID, roleID, limit, offset int, scope repositories.ArchiveScope, filter repositories.DealListFilter) ([]*models.Deals, error)
-	ListMyWithFilterAndArchiveScope(ownerID, limit, offset int, scope repositories.ArchiveScope, filter repositories.DealListFilter) ([]*models.Deals, error)
-	UpdateStatus(id int, to string, userID, roleID int) error
-	ArchiveDeal(id, userID, roleID int, reason string) error
-	UnarchiveDeal(id, userID, roleID int) error
-	GetByIDWithArchiveScope(id int, userID, roleID int, scope repositories.ArchiveScope) (*models.Deals, error)
-	MoveStage(dealID, stageID int, comment string, userID, roleID int) error
-	GetHistory(dealID, userID, roleID int) ([]*models.DealStageHistory, error)
+	Create(ctx context.Context, deal *models.Deals, userID, roleID int) (int64, error)
+	Update(ctx context.Context, deal *models.Deals, userID, roleID int) error
+	GetByID(ctx context.Context, id int, userID, roleID int) (*models.Deals, error)
+	Delete(ctx context.Context, id, userID, roleID int) error
+	ListForRole(ctx context.Context, userID, roleID, limit, offset int, scope repositories.ArchiveScope, filter repositories.DealListFilter) ([]*models.Deals, error)
+	ListMyWithFilterAndArchiveScope(ctx context.Context, ownerID, limit, offset int, scope repositories.ArchiveScope, filter repositories.DealListFilter) ([]*models.Deals, error)
+	UpdateStatus(ctx context.Context, id int, to string, userID, roleID int) error
+	ArchiveDeal(ctx context.Context, id, userID, roleID int, reason string) error
+	UnarchiveDeal(ctx context.Context, id, userID, roleID int) error
+	GetByIDWithArchiveScope(ctx context.Context, id int, userID, roleID int, scope repositories.ArchiveScope) (*models.Deals, error)
+	MoveStage(ctx context.Context, dealID, stageID int, comment string, userID, roleID int) error
+	GetHistory(ctx context.Context, dealID, userID, roleID int) ([]*models.DealStageHistory, error)
 }
 
 type dealPaginationService interface {
-	ListForRoleWithTotal(userID, roleID, limit, offset int, scope repositories.ArchiveScope, filter repositories.DealListFilter) ([]*models.Deals, int, error)
-	ListMyWithFilterAndArchiveScopeAndTotal(ownerID, limit, offset int, scope repositories.ArchiveScope, filter repositories.DealListFilter) ([]*models.Deals, int, error)
+	ListForRoleWithTotal(ctx context.Context, userID, roleID, limit, offset int, scope repositories.ArchiveScope, filter repositories.DealListFilter) ([]*models.Deals, int, error)
+	ListMyWithFilterAndArchiveScopeAndTotal(ctx context.Context, ownerID, limit, offset int, scope repositories.ArchiveScope, filter repositories.DealListFilter) ([]*models.Deals, int, error)
 }
 
 func NewDealHandler(service *services.DealService) *DealHandler {
@@ -48,7 +49,7 @@ func NewDealHandler(service *services.DealService) *DealHandler {
 func (h *DealHandler) Create(c *gin.Context) {
 	var deal models.Deals
 	if err := c.ShouldBindJSON(&deal); err != nil {
-		badRequest(c, "Invalid payload")
+		bindError(c, err, "Invalid payload")
 		return
 	}
 
@@ -75,7 +76,7 @@ func (h *DealHandler) Create(c *gin.Context) {
 		deal.CreatedAt = time.Now()
 	}
 
-	id, err := h.Service.Create(&deal, userID, roleID)
+	id, err := h.Service.Create(c.Request.Context(), &deal, userID, roleID)
 	if err != nil {
 		if errors.Is(err, services.ErrLeadIDRequired) {
 			badRequest(c, "Lead ID is required")
@@ -109,6 +110,10 @@ func (h *DealHandler) Create(c *gin.Context) {
 			badRequest(c, "Amount must be greater than 0")
 			return
 		}
+		if errors.Is(err, services.ErrCurrencyRequired) || errors.Is(err, services.ErrInvalidCurrency) {
+			badRequest(c, err.Error())
+			return
+		}
 		var dealConflict *services.DealAlreadyExistsError
 		if errors.As(err, &dealConflict) {
 			details := gin.H{"resource": "deal", "field": "lead_id", "value": dealConflict.LeadID}
@@ -147,7 +152,7 @@ func (h *DealHandler) Update(c *gin.Context) {
 		return
 	}
 
-	current, err := h.Service.GetByID(id, userID, roleID)
+	current, err := h.Service.GetByID(c.Request.Context(), id, userID, roleID)
 	if err != nil || current == nil {
 		if errors.Is(err, services.ErrForbidden) {
 			forbidden(c, "Forbidden")
@@ -159,7 +164,7 @@ func (h *DealHandler) Update(c *gin.Context) {
 
 	var body models.Deals
 	if err := c.ShouldBindJSON(&body); err != nil {
-		badRequest(c, "Invalid payload")
+		bindError(c, err, "Invalid payload")
 		return
 	}
 	if body.ClientID <= 0 {
@@ -171,7 +176,7 @@ func (h *DealHandler) Update(c *gin.Context) {
 		return
 	}
 	body.ID = id
-	if err := h.Service.Update(&body, userID, roleID); err != nil {
+	if err := h.Service.Update(c.Request.Context(), &body, userID, roleID); err != nil {
 		if errors.Is(err, services.ErrClientIDRequired) {
 			badRequest(c, "Client ID is required")
 			return
@@ -192,6 +197,10 @@ func (h *DealHandler) Update(c *gin.Context) {
 			badRequest(c, "Amount must be greater than 0")
 			return
 		}
+		if errors.Is(err, services.ErrCurrencyRequired) || errors.Is(err, services.ErrInvalidCurrency) {
+			badRequest(c, err.Error())
+			return
+		}
 		var dealConflict *services.DealAlreadyExistsError
 		if errors.As(err, &dealConflict) {
 			details := gin.H{"resource": "deal", "field": "lead_id", "value": dealConflict.LeadID}
@@ -220,7 +229,7 @@ func (h *DealHandler) Update(c *gin.Context) {
 		internalError(c, "Failed to update deal")
 		return
 	}
-	updated, _ := h.Service.GetByID(id, userID, roleID)
+	updated, _ := h.Service.GetByID(c.Request.Context(), id, userID, roleID)
 	c.JSON(http.StatusOK, updated)
 }
 
@@ -232,7 +241,7 @@ func (h *DealHandler) GetByID(c *gin.Context) {
 	}
 
 	userID, roleID := getUserAndRole(c)
-	deal, err := h.Service.GetByID(id, userID, roleID)
+	deal, err := h.Service.GetByID(c.Request.Context(), id, userID, roleID)
 	if err != nil || deal == nil {
 		if errors.Is(err, services.ErrForbidden) {
 			forbidden(c, "Forbidden")
@@ -256,7 +265,7 @@ func (h *DealHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	deal, err := h.Service.GetByIDWithArchiveScope(id, userID, roleID, repositories.ArchiveScopeAll)
+	deal, err := h.Service.GetByIDWithArchiveScope(c.Request.Context(), id, userID, roleID, repositories.ArchiveScopeAll)
 	if err != nil || deal == nil {
 		if errors.Is(err, services.ErrForbidden) {
 			forbidden(c, "Forbidden")
@@ -266,7 +275,7 @@ func (h *DealHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.Service.Delete(id, userID, roleID); err != nil {
+	if err := h.Service.Delete(c.Request.Context(), id, userID, roleID); err != nil {
 		if errors.Is(err, services.ErrForbidden) || errors.Is(err, services.ErrReadOnly) {
 			forbidden(c, err.Error())
 			return
@@ -293,7 +302,7 @@ func (h *DealHandler) Archive(c *gin.Context) {
 		return
 	}
 	userID, roleID := getUserAndRole(c)
-	if err := h.Service.ArchiveDeal(id, userID, roleID, req.Reason); err != nil {
+	if err := h.Service.ArchiveDeal(c.Request.Context(), id, userID, roleID, req.Reason); err != nil {
 		if errors.Is(err, services.ErrForbidden) || errors.Is(err, services.ErrReadOnly) {
 			forbidden(c, err.Error())
 			return
@@ -305,7 +314,7 @@ func (h *DealHandler) Archive(c *gin.Context) {
 		internalError(c, "Failed to archive deal")
 		return
 	}
-	updated, _ := h.Service.GetByIDWithArchiveScope(id, userID, roleID, repositories.ArchiveScopeAll)
+	updated, _ := h.Service.GetByIDWithArchiveScope(c.Request.Context(), id, userID, roleID, repositories.ArchiveScopeAll)
 	c.JSON(http.StatusOK, updated)
 }
 
@@ -316,7 +325,7 @@ func (h *DealHandler) Unarchive(c *gin.Context) {
 		return
 	}
 	userID, roleID := getUserAndRole(c)
-	if err := h.Service.UnarchiveDeal(id, userID, roleID); err != nil {
+	if err := h.Service.UnarchiveDeal(c.Request.Context(), id, userID, roleID); err != nil {
 		if errors.Is(err, services.ErrForbidden) || errors.Is(err, services.ErrReadOnly) {
 			forbidden(c, err.Error())
 			return
@@ -332,7 +341,7 @@ func (h *DealHandler) Unarchive(c *gin.Context) {
 		internalError(c, "Failed to unarchive deal")
 		return
 	}
-	updated, _ := h.Service.GetByID(id, userID, roleID)
+	updated, _ := h.Service.GetByID(c.Request.Context(), id, userID, roleID)
 	c.JSON(http.StatusOK, updated)
 }
 
@@ -361,7 +370,7 @@ func (h *DealHandler) UpdateStatus(c *gin.Context) {
 		return
 	}
 
-	current, err := h.Service.GetByID(id, userID, roleID)
+	current, err := h.Service.GetByID(c.Request.Context(), id, userID, roleID)
 	if err != nil || current == nil {
 		if errors.Is(err, services.ErrForbidden) {
 			forbidden(c, "Forbidden")
@@ -371,7 +380,7 @@ func (h *DealHandler) UpdateStatus(c *gin.Context) {
 		return
 	}
 
-	if err := h.Service.UpdateStatus(id, req.To, userID, roleID); err != nil {
+	if err := h.Service.UpdateStatus(c.Request.Context(), id, req.To, userID, roleID); err != nil {
 		if errors.Is(err, services.ErrForbidden) || errors.Is(err, services.ErrReadOnly) {
 			forbidden(c, err.Error())
 			return
@@ -380,16 +389,12 @@ func (h *DealHandler) UpdateStatus(c *gin.Context) {
 		return
 	}
 
-	updated, _ := h.Service.GetByID(id, userID, roleID)
+	updated, _ := h.Service.GetByID(c.Request.Context(), id, userID, roleID)
 	c.JSON(http.StatusOK, updated)
 }
 
 func (h *DealHandler) List(c *gin.Context) {
 	userID, roleID := getUserAndRole(c)
-	if roleID == authz.RoleSales {
-		forbidden(c, "sales cannot access full list")
-		return
-	}
 
 	paginate := isPaginatedMode(c)
 	page := 1
@@ -418,6 +423,31 @@ func (h *DealHandler) List(c *gin.Context) {
 		badRequest(c, err.Error())
 		return
 	}
+	after, cursorMode, err := cursorFromQuery(c)
+	if err != nil {
+		badRequest(c, "Invalid cursor")
+		return
+	}
+	if cursorMode {
+		if filter.SortBy != "" && filter.SortBy != "created_at" {
+			badRequest(c, "Cursor pagination is ordered by created_at")
+			return
+		}
+		filter.After = after
+		_, size = normalizedPageAndSize(c)
+		// лишняя строка показывает, есть ли следующая страница
+		deals, err := h.Service.ListForRole(c.Request.Context(), userID, roleID, size+1, 0, scope, filter)
+		if err != nil {
+			if errors.Is(err, services.ErrForbidden) {
+				forbidden(c, "Forbidden")
+				return
+			}
+			internalError(c, "Failed to retrieve deals")
+			return
+		}
+		writeCursorPage(c, deals, size, dealCursor)
+		return
+	}
 
 	if paginate {
 		pSvc, ok := h.Service.(dealPaginationService)
@@ -425,7 +455,7 @@ func (h *DealHandler) List(c *gin.Context) {
 			internalError(c, "Pagination is not supported")
 			return
 		}
-		deals, total, err := pSvc.ListForRoleWithTotal(userID, roleID, size, offset, scope, filter)
+		deals, total, err := pSvc.ListForRoleWithTotal(c.Request.Context(), userID, roleID, size, offset, scope, filter)
 		if err != nil {
 			if errors.Is(err, services.ErrForbidden) {
 				forbidden(c, "Forbidden")
@@ -434,11 +464,11 @@ func (h *DealHandler) List(c *gin.Context) {
 			internalError(c, "Failed to retrieve deals")
 			return
 		}
-		c.JSON(http.StatusOK, models.PaginatedResponse[*models.Deals]{Items: deals, Pagination: buildPaginationMeta(page, size, total)})
+		writePaginated(c, deals, page, size, total)
 		return
 	}
 
-	deals, err := h.Service.ListForRole(userID, roleID, size, offset, scope, filter)
+	deals, err := h.Service.ListForRole(c.Request.Context(), userID, roleID, size, offset, scope, filter)
 	if err != nil {
 		if errors.Is(err, services.ErrForbidden) {
 			forbidden(c, "Forbidden")
@@ -488,7 +518,7 @@ func (h *DealHandler) ListMy(c *gin.Context) {
 			internalError(c, "Pagination is not supported")
 			return
 		}
-		deals, total, err := pSvc.ListMyWithFilterAndArchiveScopeAndTotal(userID, size, offset, scope, filter)
+		deals, total, err := pSvc.ListMyWithFilterAndArchiveScopeAndTotal(c.Request.Context(), userID, size, offset, scope, filter)
 		if err != nil {
 			if errors.Is(err, services.ErrForbidden) {
 				forbidden(c, "Forbidden")
@@ -497,11 +527,11 @@ func (h *DealHandler) ListMy(c *gin.Context) {
 			internalError(c, "Failed to retrieve deals")
 			return
 		}
-		c.JSON(http.StatusOK, models.PaginatedResponse[*models.Deals]{Items: deals, Pagination: buildPaginationMeta(page, size, total)})
+		writePaginated(c, deals, page, size, total)
 		return
 	}
 
-	deals, err := h.Service.ListMyWithFilterAndArchiveScope(userID, size, offset, scope, filter)
+	deals, err := h.Service.ListMyWithFilterAndArchiveScope(c.Request.Context(), userID, size, offset, scope, filter)
 	if err != nil {
 		if errors.Is(err, services.ErrForbidden) {
 			forbidden(c, "Forbidden")
@@ -538,7 +568,7 @@ func (h *DealHandler) Move(c *gin.Context) {
 		return
 	}
 
-	if err := h.Service.MoveStage(id, req.StageID, req.Comment, userID, roleID); err != nil {
+	if err := h.Service.MoveStage(c.Request.Context(), id, req.StageID, req.Comment, userID, roleID); err != nil {
 		if errors.Is(err, services.ErrForbidden) || errors.Is(err, services.ErrReadOnly) {
 			forbidden(c, err.Error())
 			return
@@ -555,7 +585,7 @@ func (h *DealHandler) Move(c *gin.Context) {
 		return
 	}
 
-	updated, _ := h.Service.GetByID(id, userID, roleID)
+	updated, _ := h.Service.GetByID(c.Request.Context(), id, userID, roleID)
 	c.JSON(http.StatusOK, updated)
 }
 
@@ -567,7 +597,7 @@ func (h *DealHandler) GetHistory(c *gin.Context) {
 		return
 	}
 	userID, roleID := getUserAndRole(c)
-	history, err := h.Service.GetHistory(id, userID, roleID)
+	history, err := h.Service.GetHistory(c.Request.Context(), id, userID, roleID)
 	if err != nil {
 		if errors.Is(err, services.ErrForbidden) {
 			forbidden(c, "Forbidden")
@@ -621,7 +651,7 @@ func dealListFilterFromQuery(c *gin.Context) (repositories.DealListFilter, error
 	}
 	filter.Currency = strings.ToUpper(strings.TrimSpace(c.Query("currency")))
 	filter.SortBy = strings.ToLower(strings.TrimSpace(c.Query("sort_by")))
-	if filter.SortBy != "" && filter.SortBy != "created_at" && filter.SortBy != "amount" && filter.SortBy != "status" && filter.SortBy != "client_name" {
+	if filter.SortBy != "" && filter.SortBy != "created_at" && filter.SortBy != "amount" && filter.SortBy != "status" && filter.SortBy != "client_name" && filter.SortBy != "updated_at" {
 		return repositories.DealListFilter{}, errors.New("Invalid sort_by")
 	}
 	filter.Order = strings.ToLower(strings.TrimSpace(c.Query("order")))
@@ -646,3 +676,8 @@ func isAllowedDealStatus(status string) bool {
 		return false
 	}
 }
+
+// dealCursor is the keyset position of a deal in ?cursor= listings.
+func dealCursor(v *models.Deals) repositories.ListCursor {
+	return repositories.ListCursor{CreatedAt: v.CreatedAt, ID: v.ID}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -20,30 +21,36 @@ type stubDealService struct {
 	createFn func(deal *models.Deals, userID, roleID int) (int64, error)
 }
 
-func (s *stubDealService) Create(deal *models.Deals, userID, roleID int) (int64, error) {
+func (s *stubDealService) Create(_ context.Context, deal *models.Deals, userID, roleID int) (int64, error) {
 	return s.createFn(deal, userID, roleID)
 }
-func (s *stubDealService) Update(deal *models.Deals, userID, roleID int) error { return nil }
-func (s *stubDealService) GetByID(id int, userID, roleID int) (*models.Deals, error) {
+func (s *stubDealService) Update(_ context.Context, deal *models.Deals, userID, roleID int) error {
+	return nil
+}
+func (s *stubDealService) GetByID(_ context.Context, id int, userID, roleID int) (*models.Deals, error) {
 	return nil, nil
 }
-func (s *stubDealService) Delete(id, userID, roleID int) error { return nil }
-func (s *stubDealService) ListForRole(userID, roleID, limit, offset int, scope repositories.ArchiveScope, filter repositories.DealListFilter) ([]*models.Deals, error) {
+func (s *stubDealService) Delete(_ context.Context, id, userID, roleID int) error { return nil }
+func (s *stubDealService) ListForRole(_ context.Context, userID, roleID, limit, offset int, scope repositories.ArchiveScope, filter repositories.DealListFilter) ([]*models.Deals, error) {
 	return nil, nil
 }
-func (s *stubDealService) ListMyWithFilterAndArchiveScope(ownerID, limit, offset int, scope repositories.ArchiveScope, filter repositories.DealListFilter) ([]*models.Deals, error) {
+func (s *stubDealService) ListMyWithFilterAndArchiveScope(_ context.Context, ownerID, limit, offset int, scope repositories.ArchiveScope, filter repositories.DealListFilter) ([]*models.Deals, error) {
 	return nil, nil
 }
-func (s *stubDealService) UpdateStatus(id int, to string, userID, roleID int) error { return nil }
-func (s *stubDealService) ArchiveDeal(id, userID, roleID int, reason string) error  { return nil }
-func (s *stubDealService) UnarchiveDeal(id, userID, roleID int) error               { return nil }
-func (s *stubDealService) GetByIDWithArchiveScope(id int, userID, roleID int, scope repositories.ArchiveScope) (*models.Deals, error) {
+func (s *stubDealService) UpdateStatus(_ context.Context, id int, to string, userID, roleID int) error {
+	return nil
+}
+func (s *stubDealService) ArchiveDeal(_ context.Context, id, userID, roleID int, reason string) error {
+	return nil
+}
+func (s *stubDealService) UnarchiveDeal(_ context.Context, id, userID, roleID int) error { return nil }
+func (s *stubDealService) GetByIDWithArchiveScope(_ context.Context, id int, userID, roleID int, scope repositories.ArchiveScope) (*models.Deals, error) {
 	return nil, nil
 }
-func (s *stubDealService) MoveStage(dealID, stageID int, comment string, userID, roleID int) error {
+func (s *stubDealService) MoveStage(_ context.Context, dealID, stageID int, comment string, userID, roleID int) error {
 	return nil
 }
-func (s *stubDealService) GetHistory(dealID, userID, roleID int) ([]*models.DealStageHistory, error) {
+func (s *stubDealService) GetHistory(_ context.Context, dealID, userID, roleID int) ([]*models.DealStageHistory, error) {
 	return nil, nil
 }
 
@@ -26,6 +26,9 @@ import (
 type DocumentHandler struct {
 	Service *services.DocumentService
 	store   storage.Storage
+
+	// Базовый URL для абсолютных ссылок на файлы (пусто — берётся из запроса)
+	publicBaseURL string
 }
 
 // createFromClientRequest — схема payload для POST /documents/create-from-client.
@@ -84,6 +87,23 @@ func NewDocumentHandler(service *services.DocumentService, store storage.Storage
 	return &DocumentHandler{Service: service, store: store}
 }
 
+// SetPublicBaseURL sets the origin used for file_url/download_url in
+// GET /deals/:id/documents.
+func (h *DocumentHandler) SetPublicBaseURL(base string) {
+	h.publicBaseURL = strings.TrimRight(strings.TrimSpace(base), "/")
+}
+
+// documentWriteForbidden answers a refused document write. The read-only
+// role gets READ_ONLY_ROLE like the task handlers; anyone else is refused
+// with FORBIDDEN.
+func documentWriteForbidden(c *gin.Context, roleID int, err error) {
+	if errors.Is(err, services.ErrReadOnly) || authz.IsReadOnly(roleID) {
+		writeError(c, http.StatusForbidden, ReadOnlyRoleCode, "Read-only role")
+		return
+	}
+	forbidden(c, "Forbidden")
+}
+
 // ===== CRUD =====
 
 // POST /documents
@@ -95,27 +115,24 @@ func (h *DocumentHandler) CreateDocument(c *gin.Context) {
 	}
 	userID, roleID := getUserAndRole(c)
 	if roleID == authz.RoleHR {
-		c.JSON(403, gin.H{"error": "document_generation_unavailable", "message": "Генерация документов для HR в разработке"})
+		writeError(c, http.StatusForbidden, DocumentGenerationUnavailableCode, "Генерация документов для HR в разработке")
 		return
 	}
-	id, err := h.Service.CreateDocument(&doc, userID, roleID)
+	id, err := h.Service.CreateDocument(c.Request.Context(), &doc, userID, roleID)
 	if err != nil {
-		switch err.Error() {
-		case "read-only role", "forbidden":
-			forbidden(c, "Read-only role")
+		switch {
+		case errors.Is(err, services.ErrReadOnly), errors.Is(err, services.ErrForbidden):
+			documentWriteForbidden(c, roleID, err)
 			return
-		case "deal not found":
+		case errors.Is(err, services.ErrDealNotFound):
 			notFound(c, DealNotFoundCode, "Deal not found")
 			return
-		case "lead not found":
+		case errors.Is(err, services.ErrLeadNotFound):
 			notFound(c, LeadNotFoundCode, "Lead not found")
 			return
-		case "unsupported doc_type":
+		case errors.Is(err, services.ErrUnsupportedDocType):
 			writeError(c, http.StatusBadRequest, UnsupportedDocType, "Unsupported document type")
 			return
-		case "pdf generator not configured":
-			internalError(c, "Failed to create document")
-			return
 		}
 		internalError(c, "Failed to create document")
 		return
@@ -137,19 +154,19 @@ func (h *DocumentHandler) Upload(c *gin.Context) {
 		badRequest(c, "File is required")
 		return
 	}
-	doc, saveErr := h.Service.UploadDocument(dealID, docType, file, userID, roleID)
+	doc, saveErr := h.Service.UploadDocument(c.Request.Context(), dealID, docType, file, userID, roleID)
 	if saveErr != nil {
-		switch saveErr.Error() {
-		case "forbidden", "read-only role":
-			forbidden(c, "Read-only role")
+		switch {
+		case errors.Is(saveErr, services.ErrReadOnly), errors.Is(saveErr, services.ErrForbidden):
+			documentWriteForbidden(c, roleID, saveErr)
 			return
-		case "deal not found":
+		case errors.Is(saveErr, services.ErrDealNotFound):
 			notFound(c, DealNotFoundCode, "Deal not found")
 			return
-		case "doc_type is required", "invalid filename":
+		case errors.Is(saveErr, services.ErrDocTypeRequired), errors.Is(saveErr, services.ErrInvalidFilename):
 			badRequest(c, "Invalid payload")
 			return
-		case "unsupported doc_type":
+		case errors.Is(saveErr, services.ErrUnsupportedDocType):
 			writeError(c, http.StatusBadRequest, UnsupportedDocType, "Unsupported document type")
 			return
 		}
@@ -167,16 +184,44 @@ func (h *DocumentHandler) GetDocument(c *gin.Context) {
 		return
 	}
 	userID, roleID := getUserAndRole(c)
-	doc, err := h.Service.GetDocument(id, userID, roleID)
+	doc, err := h.Service.GetDocument(c.Request.Context(), id, userID, roleID)
 	if err != nil || doc == nil {
-		if err != nil && err.Error() == "forbidden" {
+		if errors.Is(err, services.ErrForbidden) {
 			forbidden(c, "Forbidden")
 			return
 		}
 		notFound(c, DocumentNotFound, "Document not found")
 		return
 	}
-	c.JSON(http.StatusOK, doc)
+	c.JSON(http.StatusOK, documentViewForRole(doc, roleID))
+}
+
+// documentViewForRole drops the signature compliance details for roles that
+// only get the minimal document view; the stored document is not modified.
+func documentViewForRole(doc *models.Document, roleID int) *models.Document {
+	if authz.CanViewSigningMetadata(roleID) {
+		return doc
+	}
+	view := *doc
+	view.SignIP = ""
+	view.SignUserAgent = ""
+	view.SignMetadata = ""
+	view.SignedByUserID = nil
+	view.SignatureHash = ""
+	return &view
+}
+
+// documentsViewForRole applies documentViewForRole to every document of a
+// list response.
+func documentsViewForRole(docs []*models.Document, roleID int) []*models.Document {
+	if authz.CanViewSigningMetadata(roleID) {
+		return docs
+	}
+	views := make([]*models.Document, 0, len(docs))
+	for _, doc := range docs {
+		views = append(views, documentViewForRole(doc, roleID))
+	}
+	return views
 }
 
 // GET /documents/deal/:dealid
@@ -200,32 +245,104 @@ func (h *DocumentHandler) ListDocumentsByDeal(c *gin.Context) {
 	if isPaginatedMode(c) {
 		page, size := normalizedPageAndSize(c)
 		offset := offsetFromPage(page, size)
-		docs, total, err := h.Service.ListDocumentsByDealWithFilterAndTotal(dealID, userID, roleID, size, offset, filter, scope)
+		docs, total, err := h.Service.ListDocumentsByDealWithFilterAndTotal(c.Request.Context(), dealID, userID, roleID, size, offset, filter, scope)
 		if err != nil {
-			if err.Error() == "forbidden" {
+			if errors.Is(err, services.ErrForbidden) {
 				forbidden(c, "Forbidden")
 				return
 			}
 			internalError(c, "Could not fetch documents")
 			return
 		}
-		if docs == nil {
-			docs = make([]*models.Document, 0)
+		writePaginated(c, documentsViewForRole(docs, roleID), page, size, total)
+		return
+	}
+	if limit, offset, bounded, ok := limitOffsetFromQuery(c); !ok {
+		badRequest(c, "Invalid limit or offset")
+		return
+	} else if bounded {
+		// Same plain array as the legacy response, just one window of it.
+		docs, err := h.Service.ListDocumentsByDealWithFilterPaginated(c.Request.Context(), dealID, userID, roleID, limit, offset, filter, scope)
+		if err != nil {
+			if errors.Is(err, services.ErrForbidden) {
+				forbidden(c, "Forbidden")
+				return
+			}
+			internalError(c, "Could not fetch documents")
+			return
 		}
-		c.JSON(http.StatusOK, models.PaginatedResponse[*models.Document]{Items: docs, Pagination: buildPaginationMeta(page, size, total)})
+		c.JSON(http.StatusOK, documentsViewForRole(docs, roleID))
 		return
 	}
 
-	docs, err := h.Service.ListDocumentsByDealWithFilter(dealID, userID, roleID, filter, scope)
+	docs, err := h.Service.ListDocumentsByDealWithFilter(c.Request.Context(), dealID, userID, roleID, filter, scope)
 	if err != nil {
-		if err.Error() == "forbidden" {
+		if errors.Is(err, services.ErrForbidden) {
 			forbidden(c, "Forbidden")
 			return
 		}
 		internalError(c, "Could not fetch documents")
 		return
 	}
-	c.JSON(http.StatusOK, docs)
+	c.JSON(http.StatusOK, documentsViewForRole(docs, roleID))
+}
+
+// dealDocumentItem is a document with ready-to-use absolute links. The URLs
+// are only set when the file exists, so clients can hide broken links.
+type dealDocumentItem struct {
+	*models.Document
+	FileURL     string `json:"file_url,omitempty"`
+	DownloadURL string `json:"download_url,omitempty"`
+	Signed      bool   `json:"signed"`
+}
+
+// GET /deals/:id/documents — как /documents/deal/:dealid, но с file_url,
+// download_url и признаком подписи.
+func (h *DocumentHandler) ListDealDocuments(c *gin.Context) {
+	dealID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || dealID <= 0 {
+		badRequest(c, "Invalid deal id")
+		return
+	}
+	userID, roleID := getUserAndRole(c)
+	scope, ok := archiveScopeFromQuery(c)
+	if !ok {
+		badRequest(c, "Invalid archive filter")
+		return
+	}
+	filter, err := documentListFilterFromQuery(c)
+	if err != nil {
+		badRequest(c, err.Error())
+		return
+	}
+
+	docs, err := h.Service.ListDocumentsByDealWithFilter(c.Request.Context(), dealID, userID, roleID, filter, scope)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrForbidden):
+			forbidden(c, "Forbidden")
+		case errors.Is(err, services.ErrNotFound):
+			notFound(c, DealNotFoundCode, "Deal not found")
+		default:
+			internalError(c, "Could not fetch documents")
+		}
+		return
+	}
+
+	base := h.publicBaseURL
+	if base == "" {
+		base = requestOrigin(c)
+	}
+	items := make([]dealDocumentItem, 0, len(docs))
+	for _, doc := range docs {
+		item := dealDocumentItem{Document: documentViewForRole(doc, roleID), Signed: doc.Status == "signed" || doc.SignedAt != nil}
+		if h.Service.DocumentFileExists(c.Request.Context(), doc) {
+			item.FileURL = fmt.Sprintf("%s/documents/%d/file", base, doc.ID)
+			item.DownloadURL = fmt.Sprintf("%s/documents/%d/download", base, doc.ID)
+		}
+		items = append(items, item)
+	}
+	c.JSON(http.StatusOK, items)
 }
 
 // DELETE /documents/:id
@@ -240,12 +357,12 @@ func (h *DocumentHandler) DeleteDocument(c *gin.Context) {
 		forbidden(c, "Forbidden")
 		return
 	}
-	if err := h.Service.DeleteDocument(id, userID, roleID); err != nil {
-		switch err.Error() {
-		case "read-only role", "forbidden":
-			forbidden(c, "Read-only role")
+	if err := h.Service.DeleteDocument(c.Request.Context(), id, userID, roleID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrReadOnly), errors.Is(err, services.ErrForbidden):
+			documentWriteForbidden(c, roleID, err)
 			return
-		case "not found":
+		case errors.Is(err, services.ErrNotFound):
 			notFound(c, DocumentNotFound, "Document not found")
 			return
 		}
@@ -306,27 +423,27 @@ func (h *DocumentHandler) ListDocuments(c *gin.Context) {
 		filter.Scope = "hr"
 	}
 
-	if roleID != authz.RoleSystemAdmin {
+	if !authz.HasCapability(roleID, authz.CapViewHiddenDocuments) {
 		uid := userID
 		filter.HiddenVisibilityUserID = &uid
 	}
 
 	if paginate {
-		docs, total, err := h.Service.ListDocumentsWithFilterAndArchiveScopeAndTotal(size, offset, filter, scope)
+		docs, total, err := h.Service.ListDocumentsWithFilterAndArchiveScopeAndTotal(c.Request.Context(), size, offset, filter, scope)
 		if err != nil {
 			internalError(c, "Could not fetch documents")
 			return
 		}
-		c.JSON(http.StatusOK, models.PaginatedResponse[*models.Document]{Items: docs, Pagination: buildPaginationMeta(page, size, total)})
+		writePaginated(c, documentsViewForRole(docs, roleID), page, size, total)
 		return
 	}
 
-	docs, err := h.Service.ListDocumentsWithFilterAndArchiveScope(size, offset, filter, scope)
+	docs, err := h.Service.ListDocumentsWithFilterAndArchiveScope(c.Request.Context(), size, offset, filter, scope)
 	if err != nil {
 		internalError(c, "Could not fetch documents")
 		return
 	}
-	c.JSON(http.StatusOK, docs)
+	c.JSON(http.StatusOK, documentsViewForRole(docs, roleID))
 }
 
 func documentListFilterFromQuery(c *gin.Context) (repositories.DocumentListFilter, error) {
@@ -407,20 +524,20 @@ func (h *DocumentHandler) CreateDocumentFromLead(c *gin.Context) {
 	}
 	userID, roleID := getUserAndRole(c)
 
-	doc, err := h.Service.CreateDocumentFromLead(req.LeadID, req.DocType, userID, roleID)
+	doc, err := h.Service.CreateDocumentFromLead(c.Request.Context(), req.LeadID, req.DocType, userID, roleID)
 	if err != nil {
-		switch err.Error() {
-		case "lead not found":
+		switch {
+		case errors.Is(err, services.ErrLeadNotFound):
 			notFound(c, LeadNotFoundCode, "Lead not found")
 			return
-		case "deal not found":
+		case errors.Is(err, services.ErrDealNotFound):
 			notFound(c, DealNotFoundCode, "Deal not found")
 			return
-		case "unsupported_doc_type_for_lead_use_create_from_client":
+		case errors.Is(err, services.ErrUnsupportedDocTypeForLead):
 			writeError(c, http.StatusBadRequest, UnsupportedDocType, "Unsupported doc_type for lead path; use /documents/create-from-client for legal/templated contracts")
 			return
-		case "forbidden", "read-only role":
-			forbidden(c, "Read-only role")
+		case errors.Is(err, services.ErrReadOnly), errors.Is(err, services.ErrForbidden):
+			documentWriteForbidden(c, roleID, err)
 			return
 		}
 		internalError(c, "Failed to create document")
@@ -433,6 +550,85 @@ func (h *DocumentHandler) CreateDocumentFromLead(c *gin.Context) {
 	})
 }
 
+// POST /documents/preview
+// Тот же PDF, что и create-from-lead, но без записи в documents: файл
+// отдаётся inline и сразу удаляется.
+func (h *DocumentHandler) PreviewDocumentFromLead(c *gin.Context) {
+	var req struct {
+		LeadID  int    `json:"lead_id"  binding:"required"`
+		DocType string `json:"doc_type" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err, "Invalid payload")
+		return
+	}
+	userID, roleID := getUserAndRole(c)
+
+	body, err := h.Service.PreviewDocumentFromLead(c.Request.Context(), req.LeadID, req.DocType, userID, roleID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrLeadNotFound):
+			notFound(c, LeadNotFoundCode, "Lead not found")
+			return
+		case errors.Is(err, services.ErrDealNotFound):
+			notFound(c, DealNotFoundCode, "Deal not found")
+			return
+		case errors.Is(err, services.ErrUnsupportedDocTypeForLead):
+			writeError(c, http.StatusBadRequest, UnsupportedDocType, "Unsupported doc_type for lead path; use /documents/create-from-client for legal/templated contracts")
+			return
+		case errors.Is(err, services.ErrReadOnly), errors.Is(err, services.ErrForbidden):
+			documentWriteForbidden(c, roleID, err)
+			return
+		}
+		internalError(c, "Failed to generate preview")
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="preview.pdf"`)
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/pdf", body)
+}
+
+// POST /documents/:id/regenerate
+// Перегенерация договора/счёта из лида с актуальными данными сделки.
+func (h *DocumentHandler) RegenerateDocument(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		badRequest(c, "Invalid id")
+		return
+	}
+	userID, roleID := getUserAndRole(c)
+
+	doc, err := h.Service.RegenerateDocument(c.Request.Context(), id, userID, roleID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotFound):
+			notFound(c, DocumentNotFound, "Document not found")
+			return
+		case errors.Is(err, services.ErrLeadNotFound):
+			notFound(c, LeadNotFoundCode, "Lead not found")
+			return
+		case errors.Is(err, services.ErrDocumentAlreadySigned):
+			conflict(c, InvalidStatusCode, "Signed document cannot be regenerated")
+			return
+		case errors.Is(err, services.ErrDocumentNotRegenerable):
+			writeError(c, http.StatusBadRequest, UnsupportedDocType, err.Error())
+			return
+		case errors.Is(err, services.ErrForbidden), errors.Is(err, services.ErrReadOnly):
+			forbidden(c, "Forbidden")
+			return
+		}
+		log.Printf("[documents][regenerate] doc=%d: %v", id, err)
+		internalError(c, "Failed to regenerate document")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Документ перегенерирован",
+		"document": doc,
+	})
+}
+
 // POST /documents/create-from-client
 func (h *DocumentHandler) CreateDocumentFromClient(c *gin.Context) {
 	var req createFromClientRequest
@@ -445,17 +641,18 @@ func (h *DocumentHandler) CreateDocumentFromClient(c *gin.Context) {
 			log.Printf("[docs:create-from-client] bind error: %s", err.Error())
 			log.Printf("[docs:create-from-client] raw body (max %d bytes): %s", docsCreateFromClientDebugMaxBody, truncateForDebugLog(rawBody, docsCreateFromClientDebugMaxBody))
 		}
-		badRequest(c, "Invalid payload")
+		bindError(c, err, "Invalid payload")
 		return
 	}
 
 	userID, roleID := getUserAndRole(c)
 	if roleID == authz.RoleHR {
-		c.JSON(403, gin.H{"error": "document_generation_unavailable", "message": "Генерация документов для HR в разработке"})
+		writeError(c, http.StatusForbidden, DocumentGenerationUnavailableCode, "Генерация документов для HR в разработке")
 		return
 	}
 
 	doc, err := h.Service.CreateDocumentFromClient(
+		c.Request.Context(),
 		req.ClientID,
 		req.ClientType,
 		req.DealID,
@@ -482,40 +679,37 @@ func (h *DocumentHandler) CreateDocumentFromClient(c *gin.Context) {
 			})
 			return
 		}
-		switch err.Error() {
-		case "client not found":
+		switch {
+		case errors.Is(err, services.ErrClientNotFound):
 			notFound(c, ClientNotFoundCode, "Client not found")
 			return
-		case "client_type is required", "client_type does not match stored client type":
+		case errors.Is(err, services.ErrClientTypeRequired), errors.Is(err, services.ErrClientTypeMismatch):
 			badRequest(c, err.Error())
 			return
-		case "invalid client_type: allowed values are individual, legal":
+		case errors.Is(err, services.ErrInvalidClientType):
 			badRequest(c, err.Error())
 			return
-		case "deal not found":
+		case errors.Is(err, services.ErrDealNotFound):
 			notFound(c, DealNotFoundCode, "Deal not found")
 			return
-		case "deal does not belong to client":
+		case errors.Is(err, services.ErrDealClientMismatch):
 			badRequest(c, err.Error())
 			return
-		case "unsupported doc_type":
+		case errors.Is(err, services.ErrUnsupportedDocType):
 			writeError(c, http.StatusBadRequest, UnsupportedDocType, "Unsupported document type")
 			return
-		case "forbidden", "read-only role":
-			forbidden(c, "Read-only role")
+		case errors.Is(err, services.ErrReadOnly), errors.Is(err, services.ErrForbidden):
+			documentWriteForbidden(c, roleID, err)
 			return
-		case "template_not_found":
+		case errors.Is(err, services.ErrTemplateNotFound):
 			writeError(c, http.StatusBadRequest, "template_not_found", "Template not found")
 			return
-		case "pdf_conversion_disabled":
+		case errors.Is(err, services.ErrPDFConversionDisabled):
 			writeError(c, http.StatusBadRequest, "pdf_conversion_disabled", "PDF conversion is disabled")
 			return
-		case "pdf_conversion_failed":
+		case errors.Is(err, services.ErrPDFConversionFailed):
 			writeError(c, http.StatusInternalServerError, "pdf_conversion_failed", "PDF conversion failed")
 			return
-		case "pdf generator not configured":
-			internalError(c, "Failed to create document")
-			return
 		}
 		internalError(c, "Failed to create document")
 		return
@@ -535,18 +729,15 @@ func (h *DocumentHandler) Submit(c *gin.Context) {
 		return
 	}
 	userID, roleID := getUserAndRole(c)
-	if err := h.Service.Submit(id, userID, roleID); err != nil {
-		switch err.Error() {
-		case "read-only role":
-			forbidden(c, "Read-only role")
+	if err := h.Service.Submit(c.Request.Context(), id, userID, roleID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrReadOnly), errors.Is(err, services.ErrForbidden):
+			documentWriteForbidden(c, roleID, err)
 			return
-		case "forbidden":
-			forbidden(c, "Forbidden")
-			return
-		case "not found":
+		case errors.Is(err, services.ErrNotFound):
 			notFound(c, DocumentNotFound, "Document not found")
 			return
-		case "invalid status":
+		case errors.Is(err, services.ErrInvalidStatus):
 			writeError(c, http.StatusBadRequest, InvalidStatusCode, "Invalid status")
 			return
 		}
@@ -572,15 +763,15 @@ func (h *DocumentHandler) Review(c *gin.Context) {
 		return
 	}
 	userID, roleID := getUserAndRole(c)
-	if err := h.Service.Review(id, body.Action, userID, roleID); err != nil {
-		switch err.Error() {
-		case "forbidden":
+	if err := h.Service.Review(c.Request.Context(), id, body.Action, userID, roleID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrForbidden):
 			forbidden(c, "Forbidden")
 			return
-		case "not found":
+		case errors.Is(err, services.ErrNotFound):
 			notFound(c, DocumentNotFound, "Document not found")
 			return
-		case "invalid status", "bad action":
+		case errors.Is(err, services.ErrInvalidStatus), errors.Is(err, services.ErrBadAction):
 			writeError(c, http.StatusBadRequest, InvalidStatusCode, "Invalid status")
 			return
 		}
@@ -616,17 +807,20 @@ func (h *DocumentHandler) Sign(c *gin.Context) {
 		}
 		signedAt = &t
 	}
-	if err := h.Service.MarkDocumentSigned(id, body.SignedBy, signedAt, userID, roleID); err != nil {
-		switch err.Error() {
-		case "forbidden":
+	if err := h.Service.MarkDocumentSigned(c.Request.Context(), id, body.SignedBy, signedAt, userID, roleID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrForbidden):
 			forbidden(c, "Forbidden")
 			return
-		case "not found":
+		case errors.Is(err, services.ErrNotFound):
 			notFound(c, DocumentNotFound, "Document not found")
 			return
-		case "invalid status":
+		case errors.Is(err, services.ErrInvalidStatus):
 			writeError(c, http.StatusBadRequest, InvalidStatusCode, "Invalid status")
 			return
+		case errors.Is(err, services.ErrSMSSignatureRequired):
+			conflict(c, SMSSignatureRequiredCode, "Document must be confirmed by SMS before it is signed")
+			return
 		}
 		internalError(c, "Failed to sign document")
 		return
@@ -650,19 +844,19 @@ func (h *DocumentHandler) ArchiveDocument(c *gin.Context) {
 		return
 	}
 	userID, roleID := getUserAndRole(c)
-	if err := h.Service.ArchiveDocument(id, userID, roleID, req.Reason); err != nil {
-		switch err.Error() {
-		case "forbidden", "read-only role":
+	if err := h.Service.ArchiveDocument(c.Request.Context(), id, userID, roleID, req.Reason); err != nil {
+		switch {
+		case errors.Is(err, services.ErrForbidden), errors.Is(err, services.ErrReadOnly):
 			forbidden(c, "Forbidden")
 			return
-		case "not found":
+		case errors.Is(err, services.ErrNotFound):
 			notFound(c, DocumentNotFound, "Document not found")
 			return
 		}
 		internalError(c, "Failed to archive document")
 		return
 	}
-	doc, _ := h.Service.GetDocumentWithArchiveScope(id, userID, roleID, repositories.ArchiveScopeAll)
+	doc, _ := h.Service.GetDocumentWithArchiveScope(c.Request.Context(), id, userID, roleID, repositories.ArchiveScopeAll)
 	c.JSON(http.StatusOK, doc)
 }
 
@@ -673,22 +867,22 @@ func (h *DocumentHandler) UnarchiveDocument(c *gin.Context) {
 		return
 	}
 	userID, roleID := getUserAndRole(c)
-	if err := h.Service.UnarchiveDocument(id, userID, roleID); err != nil {
-		switch err.Error() {
-		case "forbidden", "read-only role":
+	if err := h.Service.UnarchiveDocument(c.Request.Context(), id, userID, roleID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrForbidden), errors.Is(err, services.ErrReadOnly):
 			forbidden(c, "Forbidden")
 			return
-		case "not found":
+		case errors.Is(err, services.ErrNotFound):
 			notFound(c, DocumentNotFound, "Document not found")
 			return
-		case "not archived":
+		case errors.Is(err, services.ErrNotArchived):
 			badRequest(c, "Document is not archived")
 			return
 		}
 		internalError(c, "Failed to unarchive document")
 		return
 	}
-	doc, _ := h.Service.GetDocument(id, userID, roleID)
+	doc, _ := h.Service.GetDocument(c.Request.Context(), id, userID, roleID)
 	c.JSON(http.StatusOK, doc)
 }
 
@@ -700,15 +894,15 @@ func (h *DocumentHandler) SendForSignature(c *gin.Context) {
 		return
 	}
 	userID, roleID := getUserAndRole(c)
-	if err := h.Service.PrepareForSignature(id, userID, roleID); err != nil {
-		switch err.Error() {
-		case "forbidden", "read-only role":
+	if err := h.Service.PrepareForSignature(c.Request.Context(), id, userID, roleID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrForbidden), errors.Is(err, services.ErrReadOnly):
 			forbidden(c, "Forbidden")
 			return
-		case "not found":
+		case errors.Is(err, services.ErrNotFound):
 			notFound(c, DocumentNotFound, "Document not found")
 			return
-		case "document must be approved before signature":
+		case errors.Is(err, services.ErrDocumentNotApproved):
 			writeError(c, http.StatusBadRequest, InvalidStatusCode, "Invalid status")
 			return
 		}
@@ -729,16 +923,16 @@ func (h *DocumentHandler) ServeFile(c *gin.Context) {
 	}
 	userID, roleID := getUserAndRole(c)
 
-	abs, name, err := h.Service.ResolveFileForHTTP(id, userID, roleID, "original")
+	abs, name, err := h.Service.ResolveFileForHTTP(c.Request.Context(), id, userID, roleID, "original")
 	if err != nil {
-		switch err.Error() {
-		case "not found", "file not found":
+		switch {
+		case errors.Is(err, services.ErrNotFound), errors.Is(err, services.ErrFileNotFound):
 			notFound(c, DocumentNotFound, "Document not found")
 			return
-		case "forbidden":
+		case errors.Is(err, services.ErrForbidden):
 			forbidden(c, "Forbidden")
 			return
-		case "bad filepath":
+		case errors.Is(err, services.ErrBadFilePath):
 			badRequest(c, "Invalid file path")
 			return
 		}
@@ -782,16 +976,16 @@ func (h *DocumentHandler) Download(c *gin.Context) {
 		return
 	}
 
-	abs, name, err := h.Service.ResolveFileForHTTP(id, userID, roleID, variant)
+	abs, name, err := h.Service.ResolveFileForHTTP(c.Request.Context(), id, userID, roleID, variant)
 	if err != nil {
-		switch err.Error() {
-		case "not found", "file not found":
+		switch {
+		case errors.Is(err, services.ErrNotFound), errors.Is(err, services.ErrFileNotFound):
 			notFound(c, DocumentNotFound, "Document not found")
 			return
-		case "forbidden":
+		case errors.Is(err, services.ErrForbidden):
 			forbidden(c, "Forbidden")
 			return
-		case "bad filepath":
+		case errors.Is(err, services.ErrBadFilePath):
 			badRequest(c, "Invalid file path")
 			return
 		}
@@ -822,7 +1016,7 @@ func (h *DocumentHandler) UploadWithMeta(c *gin.Context) {
 		forbidden(c, "Forbidden scope for your role")
 		return
 	}
-	if roleID != authz.RoleHR && roleID != authz.RoleLegal && roleID != authz.RoleSystemAdmin && roleID != authz.RoleManagement {
+	if !authz.HasCapability(roleID, authz.CapUploadScopedDocuments) {
 		forbidden(c, "Forbidden")
 		return
 	}
@@ -856,7 +1050,7 @@ func (h *DocumentHandler) UploadWithMeta(c *gin.Context) {
 		badRequest(c, "File too large. Max 50MB")
 		return
 	}
-	doc, saveErr := h.Service.UploadDocumentWithMeta(scope, title, description, targetUserID, file, userID, roleID)
+	doc, saveErr := h.Service.UploadDocumentWithMeta(c.Request.Context(), scope, title, description, targetUserID, file, userID, roleID)
 	if saveErr != nil {
 		internalError(c, "Failed to upload document")
 		return
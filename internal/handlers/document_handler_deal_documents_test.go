@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+	"turcompany/internal/services"
+)
+
+func newDealDocumentsRouter(t *testing.T, docs []*models.Document, userID, roleID int) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "documents"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "documents", "contract.docx"), []byte("docx"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewDocumentHandler(&services.DocumentService{
+		DocRepo:   &documentDealPaginationRepoStub{dealItems: docs},
+		DealRepo:  &documentDealPaginationDealRepoStub{},
+		FilesRoot: root,
+	}, nil)
+	h.SetPublicBaseURL("https://crm.example.kz/")
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Set("role_id", roleID)
+		c.Next()
+	})
+	r.GET("/deals/:id/documents", h.ListDealDocuments)
+	return r
+}
+
+func TestListDealDocuments_URLsOnlyForExistingFiles(t *testing.T) {
+	signedAt := time.Now()
+	docs := []*models.Document{
+		{ID: 1, DealID: 12, Status: "signed", SignedAt: &signedAt, FilePath: "files/documents/contract.pdf", FilePathDocx: "documents/contract.docx"},
+		{ID: 2, DealID: 12, Status: "draft", FilePath: "documents/missing.pdf"},
+	}
+	r := newDealDocumentsRouter(t, docs, 999, authz.RoleManagement)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/deals/12/documents", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+
+	var got []struct {
+		ID          int64      `json:"id"`
+		FileURL     string     `json:"file_url"`
+		DownloadURL string     `json:"download_url"`
+		Signed      bool       `json:"signed"`
+		SignedAt    *time.Time `json:"signed_at"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v body=%s", err, w.Body.String())
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 documents, got %d", len(got))
+	}
+
+	for _, tc := range []struct{ raw, path string }{
+		{got[0].FileURL, "/documents/1/file"},
+		{got[0].DownloadURL, "/documents/1/download"},
+	} {
+		u, err := url.Parse(tc.raw)
+		if err != nil || !u.IsAbs() || u.Scheme != "https" || u.Host != "crm.example.kz" || u.Path != tc.path {
+			t.Fatalf("malformed url %q (want path %s): %v", tc.raw, tc.path, err)
+		}
+	}
+	if !got[0].Signed || got[0].SignedAt == nil {
+		t.Fatalf("expected signed document with signed_at, got %+v", got[0])
+	}
+
+	if got[1].FileURL != "" || got[1].DownloadURL != "" {
+		t.Fatalf("missing file must not get URLs, got %+v", got[1])
+	}
+	if got[1].Signed {
+		t.Fatal("draft must not be reported as signed")
+	}
+}
+
+func TestListDealDocuments_SalesNonOwnerForbidden(t *testing.T) {
+	// the stub deal is owned by user 999
+	r := newDealDocumentsRouter(t, nil, 5, authz.RoleSales)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/deals/12/documents", nil))
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d body=%s", w.Code, w.Body.String())
+	}
+}
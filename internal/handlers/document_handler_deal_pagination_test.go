@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -19,71 +20,77 @@ import (
 type documentDealPaginationRepoStub struct {
 	dealItems []*models.Document
 	dealTotal int
+	counted   int
 }
 
-func (s *documentDealPaginationRepoStub) Create(*models.Document) (int64, error) {
+func (s *documentDealPaginationRepoStub) Create(context.Context, *models.Document) (int64, error) {
 	return 0, errors.New("not implemented")
 }
-func (s *documentDealPaginationRepoStub) GetByID(int64) (*models.Document, error) {
+func (s *documentDealPaginationRepoStub) GetByID(context.Context, int64) (*models.Document, error) {
 	return nil, errors.New("not implemented")
 }
-func (s *documentDealPaginationRepoStub) GetByIDWithArchiveScope(int64, repositories.ArchiveScope) (*models.Document, error) {
+func (s *documentDealPaginationRepoStub) GetByIDWithArchiveScope(context.Context, int64, repositories.ArchiveScope) (*models.Document, error) {
 	return nil, errors.New("not implemented")
 }
-func (s *documentDealPaginationRepoStub) ListDocuments(int, int) ([]*models.Document, error) {
+func (s *documentDealPaginationRepoStub) ListDocuments(context.Context, int, int) ([]*models.Document, error) {
 	return nil, errors.New("not implemented")
 }
-func (s *documentDealPaginationRepoStub) ListDocumentsWithArchiveScope(int, int, repositories.ArchiveScope) ([]*models.Document, error) {
+func (s *documentDealPaginationRepoStub) ListDocumentsWithArchiveScope(context.Context, int, int, repositories.ArchiveScope) ([]*models.Document, error) {
 	return nil, errors.New("not implemented")
 }
-func (s *documentDealPaginationRepoStub) ListDocumentsByDeal(int64) ([]*models.Document, error) {
+func (s *documentDealPaginationRepoStub) ListDocumentsByDeal(context.Context, int64) ([]*models.Document, error) {
 	return s.dealItems, nil
 }
-func (s *documentDealPaginationRepoStub) ListDocumentsByDealWithArchiveScope(int64, repositories.ArchiveScope) ([]*models.Document, error) {
+func (s *documentDealPaginationRepoStub) ListDocumentsByDealWithArchiveScope(context.Context, int64, repositories.ArchiveScope) ([]*models.Document, error) {
 	return s.dealItems, nil
 }
-func (s *documentDealPaginationRepoStub) Delete(int64) error { return errors.New("not implemented") }
-func (s *documentDealPaginationRepoStub) Archive(int64, int, string) error {
+func (s *documentDealPaginationRepoStub) Delete(context.Context, int64) error {
 	return errors.New("not implemented")
 }
-func (s *documentDealPaginationRepoStub) Unarchive(int64) error { return errors.New("not implemented") }
-func (s *documentDealPaginationRepoStub) UpdateStatus(int64, string) error {
+func (s *documentDealPaginationRepoStub) Archive(context.Context, int64, int, string) error {
 	return errors.New("not implemented")
 }
-func (s *documentDealPaginationRepoStub) MarkSigned(int64, string, time.Time) error {
+func (s *documentDealPaginationRepoStub) Unarchive(context.Context, int64) error {
 	return errors.New("not implemented")
 }
-func (s *documentDealPaginationRepoStub) Update(*models.Document) error {
+func (s *documentDealPaginationRepoStub) UpdateStatus(context.Context, int64, string) error {
 	return errors.New("not implemented")
 }
-func (s *documentDealPaginationRepoStub) UpdateSigningMeta(int64, string, string, string, string) error {
+func (s *documentDealPaginationRepoStub) MarkSigned(context.Context, int64, string, time.Time) error {
 	return errors.New("not implemented")
 }
-func (s *documentDealPaginationRepoStub) ListDocumentsWithFilterAndArchiveScope(int, int, repositories.DocumentListFilter, repositories.ArchiveScope) ([]*models.Document, error) {
+func (s *documentDealPaginationRepoStub) Update(context.Context, *models.Document) error {
+	return errors.New("not implemented")
+}
+func (s *documentDealPaginationRepoStub) UpdateSigningMeta(context.Context, int64, string, string, string, string) error {
+	return errors.New("not implemented")
+}
+func (s *documentDealPaginationRepoStub) ListDocumentsWithFilterAndArchiveScope(context.Context, int, int, repositories.DocumentListFilter, repositories.ArchiveScope) ([]*models.Document, error) {
 	return nil, errors.New("not implemented")
 }
-func (s *documentDealPaginationRepoStub) ListDocumentsByDealWithFilterAndArchiveScope(int64, repositories.DocumentListFilter, repositories.ArchiveScope) ([]*models.Document, error) {
+func (s *documentDealPaginationRepoStub) ListDocumentsByDealWithFilterAndArchiveScope(context.Context, int64, repositories.DocumentListFilter, repositories.ArchiveScope) ([]*models.Document, error) {
 	return s.dealItems, nil
 }
-func (s *documentDealPaginationRepoStub) CountDocumentsWithFilterAndArchiveScope(repositories.DocumentListFilter, repositories.ArchiveScope) (int, error) {
+func (s *documentDealPaginationRepoStub) CountDocumentsWithFilterAndArchiveScope(context.Context, repositories.DocumentListFilter, repositories.ArchiveScope) (int, error) {
+	s.counted++
 	return s.dealTotal, nil
 }
-func (s *documentDealPaginationRepoStub) ListDocumentsByDealWithFilterAndArchiveScopePaginated(int64, int, int, repositories.DocumentListFilter, repositories.ArchiveScope) ([]*models.Document, error) {
+func (s *documentDealPaginationRepoStub) ListDocumentsByDealWithFilterAndArchiveScopePaginated(context.Context, int64, int, int, repositories.DocumentListFilter, repositories.ArchiveScope) ([]*models.Document, error) {
 	return s.dealItems, nil
 }
 
 type documentDealPaginationDealRepoStub struct{}
 
-func (s *documentDealPaginationDealRepoStub) GetByID(id int) (*models.Deals, error) {
+func (s *documentDealPaginationDealRepoStub) GetByID(_ context.Context, id int) (*models.Deals, error) {
 	return &models.Deals{ID: id, OwnerID: 999}, nil
 }
-func (s *documentDealPaginationDealRepoStub) GetByLeadID(int) (*models.Deals, error) {
+func (s *documentDealPaginationDealRepoStub) GetByLeadID(context.Context, int) (*models.Deals, error) {
 	return nil, errors.New("not implemented")
 }
-func (s *documentDealPaginationDealRepoStub) GetLatestByClientID(int) (*models.Deals, error) {
+func (s *documentDealPaginationDealRepoStub) GetLatestByClientID(context.Context, int) (*models.Deals, error) {
 	return nil, errors.New("not implemented")
 }
-func (s *documentDealPaginationDealRepoStub) GetLatestByClientRef(int, string) (*models.Deals, error) {
+func (s *documentDealPaginationDealRepoStub) GetLatestByClientRef(context.Context, int, string) (*models.Deals, error) {
 	return nil, errors.New("not implemented")
 }
 
@@ -160,3 +167,59 @@ func TestListDocumentsByDeal_LegacyModeStaysPlainArray(t *testing.T) {
 		t.Fatalf("expected empty plain array, got %d items", len(arr))
 	}
 }
+
+type documentDealFilteringRepoStub struct {
+	documentDealPaginationRepoStub
+	gotLimit, gotOffset int
+}
+
+func (s *documentDealFilteringRepoStub) ListDocumentsByDealWithFilterAndArchiveScopePaginated(_ context.Context, _ int64, limit, offset int, filter repositories.DocumentListFilter, _ repositories.ArchiveScope) ([]*models.Document, error) {
+	s.gotLimit, s.gotOffset = limit, offset
+	var out []*models.Document
+	for _, d := range s.dealItems {
+		if filter.Status == "" || d.Status == filter.Status {
+			out = append(out, d)
+		}
+	}
+	if offset >= len(out) {
+		return nil, nil
+	}
+	return out[offset:min(offset+limit, len(out))], nil
+}
+
+func TestListDocumentsByDeal_LimitOffsetWithStatusFilter(t *testing.T) {
+	repo := &documentDealFilteringRepoStub{documentDealPaginationRepoStub: documentDealPaginationRepoStub{dealItems: []*models.Document{
+		{ID: 1, Status: "signed"},
+		{ID: 2, Status: "draft"},
+		{ID: 3, Status: "signed"},
+		{ID: 4, Status: "signed"},
+	}}}
+	r := newDocumentDealPaginationRouter(repo)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/documents/deal/12?status=signed&limit=2&offset=1", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	var docs []models.Document
+	if err := json.Unmarshal(w.Body.Bytes(), &docs); err != nil {
+		t.Fatalf("expected plain array, got body=%s err=%v", w.Body.String(), err)
+	}
+	if len(docs) != 2 || docs[0].ID != 3 || docs[1].ID != 4 {
+		t.Fatalf("expected signed documents 3 and 4, got %+v", docs)
+	}
+	if repo.gotLimit != 2 || repo.gotOffset != 1 {
+		t.Fatalf("expected limit=2 offset=1, got %d/%d", repo.gotLimit, repo.gotOffset)
+	}
+	if repo.counted != 0 {
+		t.Fatalf("limit/offset window must not count documents, counted %d times", repo.counted)
+	}
+
+	for _, q := range []string{"limit=0", "limit=x", "offset=-1"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/documents/deal/12?"+q, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d", q, w.Code)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+	"turcompany/internal/repositories"
+	"turcompany/internal/services"
+)
+
+type documentErrorsRepoStub struct {
+	documentDealPaginationRepoStub
+	docs map[int64]*models.Document
+}
+
+func (s *documentErrorsRepoStub) GetByID(_ context.Context, id int64) (*models.Document, error) {
+	return s.docs[id], nil
+}
+
+func (s *documentErrorsRepoStub) GetByIDWithArchiveScope(_ context.Context, id int64, _ repositories.ArchiveScope) (*models.Document, error) {
+	return s.docs[id], nil
+}
+
+func newDocumentErrorsRouter(roleID int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	repo := &documentErrorsRepoStub{docs: map[int64]*models.Document{
+		1: {ID: 1, DealID: 5, Status: "signed"},
+		2: {ID: 2, DealID: 5, Status: "under_review"},
+		3: {ID: 3, DealID: 5, Status: "draft"},
+	}}
+	h := NewDocumentHandler(&services.DocumentService{
+		DocRepo:  repo,
+		DealRepo: &documentDealPaginationDealRepoStub{},
+	}, nil)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", 999)
+		c.Set("role_id", roleID)
+		c.Next()
+	})
+	r.POST("/documents/:id/submit", h.Submit)
+	r.POST("/documents/:id/review", h.Review)
+	r.POST("/documents/:id/unarchive", h.UnarchiveDocument)
+	return r
+}
+
+func TestDocumentHandlerMapsServiceSentinelsToStatus(t *testing.T) {
+	cases := []struct {
+		name     string
+		roleID   int
+		path     string
+		body     string
+		wantCode int
+		wantErr  string
+	}{
+		{name: "forbidden", roleID: authz.RolePartner, path: "/documents/3/submit", wantCode: http.StatusForbidden, wantErr: ForbiddenCode},
+		{name: "not found", roleID: authz.RoleManagement, path: "/documents/404/submit", wantCode: http.StatusNotFound, wantErr: DocumentNotFound},
+		{name: "invalid status", roleID: authz.RoleManagement, path: "/documents/1/submit", wantCode: http.StatusBadRequest, wantErr: InvalidStatusCode},
+		{name: "bad action", roleID: authz.RoleManagement, path: "/documents/2/review", body: `{"action":"shred"}`, wantCode: http.StatusBadRequest, wantErr: InvalidStatusCode},
+		{name: "not archived", roleID: authz.RoleManagement, path: "/documents/3/unarchive", wantCode: http.StatusBadRequest, wantErr: BadRequestCode},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newDocumentErrorsRouter(tc.roleID)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body)))
+			if w.Code != tc.wantCode {
+				t.Fatalf("expected %d, got %d body=%s", tc.wantCode, w.Code, w.Body.String())
+			}
+			var apiErr APIError
+			if err := json.Unmarshal(w.Body.Bytes(), &apiErr); err != nil {
+				t.Fatalf("decode: %v body=%s", err, w.Body.String())
+			}
+			if apiErr.ErrorCode != tc.wantErr {
+				t.Fatalf("expected error_code %q, got %q", tc.wantErr, apiErr.ErrorCode)
+			}
+		})
+	}
+}
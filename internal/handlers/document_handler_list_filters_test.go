@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
 )
 
 func TestDocumentListFilterFromQuery_ParsesExpectedFields(t *testing.T) {
@@ -51,3 +54,17 @@ func TestDocumentListFilterFromQuery_InvalidParams(t *testing.T) {
 		}
 	}
 }
+
+func TestListDocuments_SalesBlockedEvenWithFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/documents?status=under_review&doc_type=contract", nil)
+	c.Set("user_id", 7)
+	c.Set("role_id", authz.RoleSales)
+
+	(&DocumentHandler{}).ListDocuments(c)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for sales, got %d", w.Code)
+	}
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+	"turcompany/internal/pdf"
+	"turcompany/internal/services"
+)
+
+type previewDocRepoStub struct {
+	documentDealPaginationRepoStub
+	created int
+}
+
+func (s *previewDocRepoStub) Create(context.Context, *models.Document) (int64, error) {
+	s.created++
+	return int64(s.created), nil
+}
+
+type previewDealRepoStub struct {
+	documentDealPaginationDealRepoStub
+	ownerID int
+}
+
+func (s *previewDealRepoStub) GetByLeadID(_ context.Context, leadID int) (*models.Deals, error) {
+	return &models.Deals{ID: 12, LeadID: leadID, OwnerID: s.ownerID, Amount: 250000, Currency: "KZT", CreatedAt: time.Now()}, nil
+}
+
+func TestPreviewDocumentFromLead_ReturnsPDFWithoutRecord(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	root := t.TempDir()
+	docRepo := &previewDocRepoStub{}
+	svc := &services.DocumentService{
+		DocRepo:   docRepo,
+		DealRepo:  &previewDealRepoStub{ownerID: 7},
+		LeadRepo:  regenerateLeadRepoStub{},
+		FilesRoot: root,
+		PDFGen: pdf.NewDocumentGenerator(root, t.TempDir(), pdf.FontConfig{
+			RegularPath: "../../assets/fonts/DejaVuSans.ttf",
+			BoldPath:    "../../assets/fonts/DejaVuSans-Bold.ttf",
+		}),
+	}
+	h := NewDocumentHandler(svc, nil)
+
+	preview := func(userID, roleID int, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/documents/preview", strings.NewReader(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set("user_id", userID)
+		c.Set("role_id", roleID)
+		h.PreviewDocumentFromLead(c)
+		return w
+	}
+
+	w := preview(7, authz.RoleManagement, `{"lead_id":5,"doc_type":"contract"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/pdf" {
+		t.Fatalf("expected application/pdf, got %q", ct)
+	}
+	if !bytes.HasPrefix(w.Body.Bytes(), []byte("%PDF")) {
+		t.Fatalf("expected a PDF body, got %q", w.Body.Bytes()[:min(16, w.Body.Len())])
+	}
+	if docRepo.created != 0 {
+		t.Fatalf("preview must not create a document record, got %d", docRepo.created)
+	}
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			t.Errorf("preview left a file behind: %s", path)
+		}
+		return nil
+	})
+
+	// Same ownership check as creation: another sales user's deal.
+	if w := preview(8, authz.RoleSales, `{"lead_id":5,"doc_type":"contract"}`); w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for foreign deal, got %d", w.Code)
+	}
+	if w := preview(7, authz.RoleManagement, `{"lead_id":5,"doc_type":"act"}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unsupported type, got %d", w.Code)
+	}
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+	"turcompany/internal/pdf"
+	"turcompany/internal/services"
+)
+
+type regenerateDocRepoStub struct {
+	documentDealPaginationRepoStub
+	doc     *models.Document
+	updated int
+}
+
+func (s *regenerateDocRepoStub) GetByID(context.Context, int64) (*models.Document, error) {
+	return s.doc, nil
+}
+func (s *regenerateDocRepoStub) Update(_ context.Context, doc *models.Document) error {
+	s.updated++
+	s.doc = doc
+	return nil
+}
+
+type regenerateDealRepoStub struct {
+	documentDealPaginationDealRepoStub
+	amount float64
+}
+
+func (s *regenerateDealRepoStub) GetByID(_ context.Context, id int) (*models.Deals, error) {
+	return &models.Deals{ID: id, LeadID: 5, OwnerID: 999, Amount: s.amount, Currency: "KZT", CreatedAt: time.Now()}, nil
+}
+
+type regenerateLeadRepoStub struct{}
+
+func (regenerateLeadRepoStub) GetByID(_ context.Context, id int) (*models.Leads, error) {
+	return &models.Leads{ID: id, Title: "ТОО Ромашка"}, nil
+}
+
+type regenerateVersionRepoStub struct{ versions []*models.DocumentVersion }
+
+func (s *regenerateVersionRepoStub) GetLatestVersion(context.Context, int64) (int, error) {
+	latest := 0
+	for _, v := range s.versions {
+		if v.Version > latest {
+			latest = v.Version
+		}
+	}
+	return latest, nil
+}
+
+func (s *regenerateVersionRepoStub) CreateVersion(_ context.Context, v *models.DocumentVersion) (int64, error) {
+	s.versions = append(s.versions, v)
+	return int64(len(s.versions)), nil
+}
+
+func newRegenerateRouter(t *testing.T, doc *models.Document) (*gin.Engine, *regenerateDocRepoStub, *regenerateVersionRepoStub, string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	root := t.TempDir()
+	gen := pdf.NewDocumentGenerator(root, t.TempDir(), pdf.FontConfig{
+		RegularPath: "../../assets/fonts/DejaVuSans.ttf",
+		BoldPath:    "../../assets/fonts/DejaVuSans-Bold.ttf",
+	})
+	docRepo := &regenerateDocRepoStub{doc: doc}
+	versions := &regenerateVersionRepoStub{}
+	svc := &services.DocumentService{
+		DocRepo:   docRepo,
+		DealRepo:  &regenerateDealRepoStub{amount: 250000},
+		LeadRepo:  regenerateLeadRepoStub{},
+		FilesRoot: root,
+		PDFGen:    gen,
+	}
+	svc.SetVersionRepo(versions)
+	h := NewDocumentHandler(svc, nil)
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", 999)
+		c.Set("role_id", authz.RoleManagement)
+		c.Next()
+	})
+	r.POST("/documents/:id/regenerate", h.RegenerateDocument)
+	return r, docRepo, versions, root
+}
+
+func TestRegenerateDocument_UpdatesFileAndVersion(t *testing.T) {
+	doc := &models.Document{ID: 3, DealID: 12, DocType: "contract", Status: "draft", FilePath: "/deals/12/contract_deal_12.pdf", FilePathPdf: "/deals/12/contract_deal_12.pdf"}
+	r, docRepo, versions, root := newRegenerateRouter(t, doc)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/documents/3/regenerate", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Document models.Document `json:"document"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	const want = "/deals/12/contract_deal_12_v2.pdf"
+	if resp.Document.FilePath != want || resp.Document.FilePathPdf != want {
+		t.Fatalf("expected file path %q, got %+v", want, resp.Document)
+	}
+	if docRepo.updated != 1 {
+		t.Fatalf("expected document to be updated once, got %d", docRepo.updated)
+	}
+	if _, err := os.Stat(filepath.Join(root, "deals", "12", "contract_deal_12_v2.pdf")); err != nil {
+		t.Fatalf("regenerated file missing: %v", err)
+	}
+
+	if len(versions.versions) != 2 {
+		t.Fatalf("expected previous and new versions to be recorded, got %d", len(versions.versions))
+	}
+	if v := versions.versions[0]; v.Version != 1 || v.FilePath != "/deals/12/contract_deal_12.pdf" {
+		t.Fatalf("unexpected previous version %+v", v)
+	}
+	if v := versions.versions[1]; v.Version != 2 || v.FilePath != want {
+		t.Fatalf("unexpected new version %+v", v)
+	}
+}
+
+func TestRegenerateDocument_SignedIsConflict(t *testing.T) {
+	doc := &models.Document{ID: 3, DealID: 12, DocType: "contract", Status: "signed", FilePath: "/deals/12/contract_deal_12.pdf"}
+	r, docRepo, versions, _ := newRegenerateRouter(t, doc)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/documents/3/regenerate", nil))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d body=%s", w.Code, w.Body.String())
+	}
+	if docRepo.updated != 0 || len(versions.versions) != 0 {
+		t.Fatalf("signed document must stay untouched: updated=%d versions=%d", docRepo.updated, len(versions.versions))
+	}
+}
+
+func TestRegenerateDocument_UnsupportedType(t *testing.T) {
+	doc := &models.Document{ID: 3, DealID: 12, DocType: "contract_full", Status: "draft", FilePath: "/docx/contract_full.docx"}
+	r, _, _, _ := newRegenerateRouter(t, doc)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/documents/3/regenerate", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d body=%s", w.Code, w.Body.String())
+	}
+}
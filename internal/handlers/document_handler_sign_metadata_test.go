@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+	"turcompany/internal/services"
+)
+
+type signedDocumentRepoStub struct {
+	documentDealPaginationRepoStub
+	doc *models.Document
+}
+
+func (s *signedDocumentRepoStub) GetByID(context.Context, int64) (*models.Document, error) {
+	copied := *s.doc
+	return &copied, nil
+}
+
+// signedDocumentDealRepoStub returns deals owned by user 999 in branch 1.
+type signedDocumentDealRepoStub struct {
+	documentDealPaginationDealRepoStub
+}
+
+func (s *signedDocumentDealRepoStub) GetByID(_ context.Context, id int) (*models.Deals, error) {
+	return &models.Deals{ID: id, OwnerID: 999, BranchID: ptrInt(1)}, nil
+}
+
+func TestGetDocument_SigningMetadataOnlyForAuditAndManagement(t *testing.T) {
+	signedAt := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	signerID := int64(7)
+	doc := &models.Document{
+		ID:             5,
+		DealID:         12,
+		Status:         "signed",
+		SignedAt:       &signedAt,
+		SignedBy:       "Иван Иванов",
+		SignMethod:     "manual",
+		SignIP:         "10.0.0.1",
+		SignUserAgent:  "Mozilla/5.0",
+		SignMetadata:   `{"signed_pdf_path":"/pdf/5_signed.pdf"}`,
+		SignedByUserID: &signerID,
+		SignatureHash:  "sha256:" + "ab12cd34ab12cd34ab12cd34ab12cd34ab12cd34ab12cd34ab12cd34ab12cd34",
+	}
+
+	cases := []struct {
+		name     string
+		roleID   int
+		wantMeta bool
+	}{
+		{name: "audit", roleID: authz.RoleControl, wantMeta: true},
+		{name: "management", roleID: authz.RoleManagement, wantMeta: true},
+		{name: "sales owner", roleID: authz.RoleSales, wantMeta: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			h := NewDocumentHandler(&services.DocumentService{
+				DocRepo:  &signedDocumentRepoStub{doc: doc},
+				DealRepo: &signedDocumentDealRepoStub{},
+				UserRepo: &taskBranchUserRepoStub{users: map[int]*models.User{999: {ID: 999, BranchID: ptrInt(1)}}},
+			}, nil)
+			r.Use(func(c *gin.Context) {
+				c.Set("user_id", 999)
+				c.Set("role_id", tc.roleID)
+				c.Next()
+			})
+			r.GET("/documents/:id", h.GetDocument)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/documents/5", nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+			}
+			var body map[string]any
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			if body["signed_at"] == nil || body["status"] != "signed" {
+				t.Fatalf("signed state missing from response: %v", body)
+			}
+			for _, key := range []string{"signed_by_user_id", "signature_hash", "sign_ip", "sign_user_agent", "sign_metadata"} {
+				_, present := body[key]
+				if present != tc.wantMeta {
+					t.Fatalf("%s present=%v, want %v (body=%v)", key, present, tc.wantMeta, body)
+				}
+			}
+			if tc.wantMeta && body["signed_by_user_id"] != float64(7) {
+				t.Fatalf("signed_by_user_id = %v, want 7", body["signed_by_user_id"])
+			}
+		})
+	}
+}
+
+func TestListDocumentsByDeal_SigningMetadataOnlyForAuditAndManagement(t *testing.T) {
+	signedAt := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	signerID := int64(7)
+	docs := []*models.Document{{
+		ID:             5,
+		DealID:         12,
+		Status:         "signed",
+		SignedAt:       &signedAt,
+		SignIP:         "10.0.0.1",
+		SignUserAgent:  "Mozilla/5.0",
+		SignMetadata:   `{"signed_pdf_path":"/pdf/5_signed.pdf"}`,
+		SignedByUserID: &signerID,
+		SignatureHash:  "sha256:ab12",
+	}}
+
+	cases := []struct {
+		name     string
+		roleID   int
+		query    string
+		wantMeta bool
+	}{
+		{name: "management legacy", roleID: authz.RoleManagement, query: "", wantMeta: true},
+		{name: "sales legacy", roleID: authz.RoleSales, query: "", wantMeta: false},
+		{name: "sales paginated", roleID: authz.RoleSales, query: "?paginate=true&page=1&size=10", wantMeta: false},
+		{name: "sales limit offset", roleID: authz.RoleSales, query: "?limit=10&offset=0", wantMeta: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			h := NewDocumentHandler(&services.DocumentService{
+				DocRepo:  &documentDealPaginationRepoStub{dealItems: docs, dealTotal: 1},
+				DealRepo: &signedDocumentDealRepoStub{},
+				UserRepo: &taskBranchUserRepoStub{users: map[int]*models.User{999: {ID: 999, BranchID: ptrInt(1)}}},
+			}, nil)
+			r.Use(func(c *gin.Context) {
+				c.Set("user_id", 999)
+				c.Set("role_id", tc.roleID)
+				c.Next()
+			})
+			r.GET("/documents/deal/:dealid", h.ListDocumentsByDeal)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/documents/deal/12"+tc.query, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+			}
+			var items []map[string]any
+			if tc.query == "?paginate=true&page=1&size=10" {
+				var page struct {
+					Items []map[string]any `json:"items"`
+				}
+				if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+					t.Fatalf("invalid JSON: %v", err)
+				}
+				items = page.Items
+			} else if err := json.Unmarshal(w.Body.Bytes(), &items); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			if len(items) != 1 || items[0]["status"] != "signed" {
+				t.Fatalf("unexpected items: %v", items)
+			}
+			for _, key := range []string{"signed_by_user_id", "signature_hash", "sign_ip", "sign_user_agent", "sign_metadata"} {
+				_, present := items[0][key]
+				if present != tc.wantMeta {
+					t.Fatalf("%s present=%v, want %v (item=%v)", key, present, tc.wantMeta, items[0])
+				}
+			}
+			if docs[0].SignatureHash == "" {
+				t.Fatalf("projection must not modify the stored document")
+			}
+		})
+	}
+}
+
+type unconfirmedSMSChecker struct{}
+
+func (unconfirmedSMSChecker) HasApprovedForDocument(context.Context, int64, string, time.Time) (bool, error) {
+	return false, nil
+}
+
+func TestSign_SMSSignatureRequiredReturnsConflict(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := &services.DocumentService{
+		DocRepo: &signedDocumentRepoStub{doc: &models.Document{ID: 5, DealID: 12, DocType: "contract", Status: "approved"}},
+	}
+	svc.SetSMSSignatureRequirement(unconfirmedSMSChecker{}, []string{"contract"})
+	h := NewDocumentHandler(svc, nil)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", 1)
+		c.Set("role_id", authz.RoleManagement)
+		c.Next()
+	})
+	r.POST("/documents/:id/sign", h.Sign)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/documents/5/sign", nil))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d body=%s", w.Code, w.Body.String())
+	}
+	var body APIError
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if body.ErrorCode != SMSSignatureRequiredCode {
+		t.Fatalf("error_code = %q, want %q", body.ErrorCode, SMSSignatureRequiredCode)
+	}
+}
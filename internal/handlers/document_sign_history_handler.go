@@ -1,12 +1,17 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"turcompany/internal/models"
 	"turcompany/internal/repositories"
 	"turcompany/internal/services"
 )
@@ -28,6 +33,7 @@ type DocumentSignHistoryHandler struct {
 	DocSvc        *services.DocumentService
 	Sessions      *repositories.SignSessionRepository
 	Confirmations *repositories.SignatureConfirmationRepository
+	SMSStatus     services.SMSStatusChecker
 }
 
 func NewDocumentSignHistoryHandler(
@@ -42,6 +48,11 @@ func NewDocumentSignHistoryHandler(
 	}
 }
 
+// SetSMSStatusChecker enables GET /sms/status/:document_id.
+func (h *DocumentSignHistoryHandler) SetSMSStatusChecker(checker services.SMSStatusChecker) {
+	h.SMSStatus = checker
+}
+
 // GetSignHistory assembles a chronological timeline of signing events for a document.
 // Access is gated by the same document-level check as GET /documents/:id.
 func (h *DocumentSignHistoryHandler) GetSignHistory(c *gin.Context) {
@@ -51,10 +62,10 @@ func (h *DocumentSignHistoryHandler) GetSignHistory(c *gin.Context) {
 		return
 	}
 	userID, roleID := getUserAndRole(c)
-	doc, err := h.DocSvc.GetDocument(docID, userID, roleID)
+	doc, err := h.DocSvc.GetDocument(c.Request.Context(), docID, userID, roleID)
 	if err != nil {
-		switch err.Error() {
-		case "forbidden":
+		switch {
+		case errors.Is(err, services.ErrForbidden):
 			forbidden(c, "Forbidden")
 		default:
 			internalError(c, "Failed to fetch document")
@@ -167,3 +178,93 @@ func (h *DocumentSignHistoryHandler) GetSignHistory(c *gin.Context) {
 	})
 }
 
+// GetSMSStatus reports the provider delivery status of the latest signing SMS
+// sent for a document. Access is gated like GetSignHistory.
+func (h *DocumentSignHistoryHandler) GetSMSStatus(c *gin.Context) {
+	docID, err := strconv.ParseInt(c.Param("document_id"), 10, 64)
+	if err != nil {
+		badRequest(c, "Invalid document_id")
+		return
+	}
+	userID, roleID := getUserAndRole(c)
+	doc, err := h.DocSvc.GetDocument(c.Request.Context(), docID, userID, roleID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrForbidden):
+			forbidden(c, "Forbidden")
+		default:
+			internalError(c, "Failed to fetch document")
+		}
+		return
+	}
+	if doc == nil {
+		notFound(c, DocumentNotFound, "Document not found")
+		return
+	}
+	if h.SMSStatus == nil {
+		writeError(c, http.StatusServiceUnavailable, InternalErrorCode, "SMS provider is not configured")
+		return
+	}
+
+	confs, err := h.Confirmations.ListByDocumentID(c.Request.Context(), docID)
+	if err != nil {
+		internalError(c, "Failed to load confirmations")
+		return
+	}
+	conf, messageID := latestSMSConfirmation(confs)
+	if conf == nil {
+		notFound(c, NotFoundCode, "No SMS was sent for this document")
+		return
+	}
+	resp := gin.H{
+		"document_id":     docID,
+		"confirmation_id": conf.ID,
+		"sent_at":         conf.CreatedAt,
+	}
+	if messageID == "" {
+		resp["delivery"] = services.SMSDeliveryStatus{Status: services.SMSStatusUnknown}
+		c.JSON(http.StatusOK, resp)
+		return
+	}
+	status, err := h.SMSStatus.GetStatus(c.Request.Context(), messageID)
+	if err != nil {
+		log.Printf("[sms][status] document_id=%d confirmation_id=%s err=%v", docID, conf.ID, err)
+		switch {
+		case errors.Is(err, services.ErrSMSStatusNotFound):
+			status = &services.SMSDeliveryStatus{ProviderMessageID: messageID, Status: services.SMSStatusUnknown}
+		case errors.Is(err, services.ErrSMSSendDisabled):
+			writeError(c, http.StatusServiceUnavailable, InternalErrorCode, "SMS delivery is disabled")
+			return
+		case errors.Is(err, services.ErrSMSAPIKeyMissing):
+			writeError(c, http.StatusServiceUnavailable, InternalErrorCode, "SMS provider is not configured")
+			return
+		case errors.Is(err, services.ErrSMSTimeout):
+			writeError(c, http.StatusGatewayTimeout, InternalErrorCode, "SMS provider timeout")
+			return
+		default:
+			writeError(c, http.StatusBadGateway, InternalErrorCode, "SMS provider error")
+			return
+		}
+	}
+	resp["delivery"] = status
+	c.JSON(http.StatusOK, resp)
+}
+
+// latestSMSConfirmation returns the newest SMS confirmation among confs
+// (ordered by created_at) together with its stored provider message id.
+func latestSMSConfirmation(confs []*models.SignatureConfirmation) (*models.SignatureConfirmation, string) {
+	for i := len(confs) - 1; i >= 0; i-- {
+		conf := confs[i]
+		if conf == nil || conf.Channel != "sms" {
+			continue
+		}
+		var meta struct {
+			ProviderMessageID string `json:"provider_message_id"`
+		}
+		if len(conf.Meta) > 0 {
+			_ = json.Unmarshal(conf.Meta, &meta)
+		}
+		return conf, strings.TrimSpace(meta.ProviderMessageID)
+	}
+	return nil, ""
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"turcompany/internal/models"
+	"turcompany/internal/services"
 )
 
 // buildSignHistoryRouter returns a gin router wired with the sign-history logic
@@ -31,7 +32,7 @@ func buildSignHistoryRouter(
 
 	r.GET("/documents/:id/sign/history", func(c *gin.Context) {
 		if docErr != nil {
-			if docErr.Error() == "forbidden" {
+			if errors.Is(docErr, services.ErrForbidden) {
 				c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
 				return
 			}
@@ -155,7 +156,7 @@ func TestSignHistory_SortedChronologically(t *testing.T) {
 }
 
 func TestSignHistory_ForbiddenDoc(t *testing.T) {
-	r := buildSignHistoryRouter(nil, errors.New("forbidden"), nil, nil)
+	r := buildSignHistoryRouter(nil, services.ErrForbidden, nil, nil)
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/documents/99/sign/history", nil))
 	if w.Code != http.StatusForbidden {
@@ -199,3 +200,20 @@ func TestSignHistory_DocumentSignedFields(t *testing.T) {
 		t.Errorf("expected ip=10.0.0.1, got %v", e["ip"])
 	}
 }
+
+func TestLatestSMSConfirmation_PicksNewestSMSRow(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	confs := []*models.SignatureConfirmation{
+		{ID: "s1", Channel: "sms", CreatedAt: t0, Meta: []byte(`{"provider_message_id":"111"}`)},
+		{ID: "s2", Channel: "sms", CreatedAt: t0.Add(time.Minute), Meta: []byte(`{"provider_message_id":" 222 "}`)},
+		{ID: "e1", Channel: "email", CreatedAt: t0.Add(2 * time.Minute)},
+	}
+	conf, messageID := latestSMSConfirmation(confs)
+	if conf == nil || conf.ID != "s2" || messageID != "222" {
+		t.Fatalf("latestSMSConfirmation = %v, %q; want s2, 222", conf, messageID)
+	}
+
+	if conf, _ := latestSMSConfirmation(confs[2:]); conf != nil {
+		t.Fatalf("expected no SMS confirmation, got %v", conf)
+	}
+}
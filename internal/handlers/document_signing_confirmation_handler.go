@@ -80,16 +80,16 @@ func (h *DocumentSigningConfirmationHandler) StartSigning(c *gin.Context) {
 	var signer services.ResolvedSigner
 	switch channel {
 	case "sms":
-		signer, err = h.DocumentSvc.ResolveSignerForSMS(documentID, userID, roleID, overrides)
+		signer, err = h.DocumentSvc.ResolveSignerForSMS(c.Request.Context(), documentID, userID, roleID, overrides)
 	case "email":
-		signer, err = h.DocumentSvc.ResolveSignerForEmail(documentID, userID, roleID, overrides)
+		signer, err = h.DocumentSvc.ResolveSignerForEmail(c.Request.Context(), documentID, userID, roleID, overrides)
 	}
 	if err != nil {
-		switch err.Error() {
-		case "forbidden":
+		switch {
+		case errors.Is(err, services.ErrForbidden):
 			forbidden(c, "Forbidden")
 			return
-		case "not found":
+		case errors.Is(err, services.ErrNotFound):
 			notFound(c, DocumentNotFound, "Document not found")
 			return
 		default:
@@ -126,12 +126,12 @@ func (h *DocumentSigningConfirmationHandler) StartSigningEmail(c *gin.Context) {
 		return
 	}
 	userID, roleID := getUserAndRole(c)
-	if _, err := h.DocumentSvc.GetDocument(documentID, userID, roleID); err != nil {
-		switch err.Error() {
-		case "forbidden":
+	if _, err := h.DocumentSvc.GetDocument(c.Request.Context(), documentID, userID, roleID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrForbidden):
 			forbidden(c, "Forbidden")
 			return
-		case "not found":
+		case errors.Is(err, services.ErrNotFound):
 			notFound(c, DocumentNotFound, "Document not found")
 			return
 		}
@@ -139,18 +139,18 @@ func (h *DocumentSigningConfirmationHandler) StartSigningEmail(c *gin.Context) {
 		return
 	}
 
-	signer, err := h.DocumentSvc.ResolveSignerForEmail(documentID, userID, roleID, services.SignerOverrides{
+	signer, err := h.DocumentSvc.ResolveSignerForEmail(c.Request.Context(), documentID, userID, roleID, services.SignerOverrides{
 		Email:    body.Email,
 		FullName: body.SignerFullName,
 		Position: body.SignerPosition,
 		Phone:    body.SignerPhone,
 	})
 	if err != nil {
-		switch err.Error() {
-		case "forbidden":
+		switch {
+		case errors.Is(err, services.ErrForbidden):
 			forbidden(c, "Forbidden")
 			return
-		case "not found":
+		case errors.Is(err, services.ErrNotFound):
 			notFound(c, DocumentNotFound, "Document not found")
 			return
 		default:
@@ -195,7 +195,7 @@ func (h *DocumentSigningConfirmationHandler) StartSigningSMS(c *gin.Context) {
 		return
 	}
 	userID, roleID := getUserAndRole(c)
-	signer, err := h.DocumentSvc.ResolveSignerForSMS(documentID, userID, roleID, services.SignerOverrides{
+	signer, err := h.DocumentSvc.ResolveSignerForSMS(c.Request.Context(), documentID, userID, roleID, services.SignerOverrides{
 		Email:    body.SignerEmail,
 		FullName: body.SignerFullName,
 		Position: body.SignerPosition,
@@ -224,13 +224,13 @@ func (h *DocumentSigningConfirmationHandler) ContactOptions(c *gin.Context) {
 		return
 	}
 	userID, roleID := getUserAndRole(c)
-	options, err := h.DocumentSvc.GetSigningContactOptions(documentID, userID, roleID)
+	options, err := h.DocumentSvc.GetSigningContactOptions(c.Request.Context(), documentID, userID, roleID)
 	if err != nil {
-		switch err.Error() {
-		case "forbidden":
+		switch {
+		case errors.Is(err, services.ErrForbidden):
 			forbidden(c, "Forbidden")
 			return
-		case "not found":
+		case errors.Is(err, services.ErrNotFound):
 			notFound(c, DocumentNotFound, "Document not found")
 			return
 		default:
@@ -370,6 +370,17 @@ func (h *DocumentSigningConfirmationHandler) ConfirmByEmailCode(c *gin.Context)
 }
 
 func buildSignSessionURL(c *gin.Context, sessionID int64, token string) string {
+	origin := requestOrigin(c)
+	if origin == "" {
+		return ""
+	}
+	queryToken := url.QueryEscape(token)
+	return fmt.Sprintf("%s/api/v1/sign/sessions/id/%d/page?token=%s", origin, sessionID, queryToken)
+}
+
+// requestOrigin returns "scheme://host" of the incoming request, honouring
+// X-Forwarded-Proto from the reverse proxy. Empty when the host is unknown.
+func requestOrigin(c *gin.Context) string {
 	if c == nil {
 		return ""
 	}
@@ -384,8 +395,7 @@ func buildSignSessionURL(c *gin.Context, sessionID int64, token string) string {
 	if host == "" {
 		return ""
 	}
-	queryToken := url.QueryEscape(token)
-	return fmt.Sprintf("%s://%s/api/v1/sign/sessions/id/%d/page?token=%s", scheme, host, sessionID, queryToken)
+	return scheme + "://" + host
 }
 
 func handleSignSessionCreateError(c *gin.Context, err error) {
@@ -620,13 +630,13 @@ func (h *DocumentSigningConfirmationHandler) Status(c *gin.Context) {
 		return
 	}
 	userID, roleID := getUserAndRole(c)
-	doc, err := h.DocumentSvc.GetDocument(documentID, userID, roleID)
+	doc, err := h.DocumentSvc.GetDocument(c.Request.Context(), documentID, userID, roleID)
 	if err != nil {
-		switch err.Error() {
-		case "forbidden":
+		switch {
+		case errors.Is(err, services.ErrForbidden):
 			forbidden(c, "Forbidden")
 			return
-		case "not found":
+		case errors.Is(err, services.ErrNotFound):
 			notFound(c, DocumentNotFound, "Document not found")
 			return
 		}
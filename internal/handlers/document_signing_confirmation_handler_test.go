@@ -81,7 +81,7 @@ func (s *verifyRepoStub) UpdateMeta(_ context.Context, _ string, metaUpdate []by
 
 type verifyDocStub struct{ doc *models.Document }
 
-func (s *verifyDocStub) GetByID(id int64) (*models.Document, error) {
+func (s *verifyDocStub) GetByID(_ context.Context, id int64) (*models.Document, error) {
 	if s.doc != nil && s.doc.ID == id {
 		return s.doc, nil
 	}
@@ -92,33 +92,35 @@ type statusDocRepoStub struct {
 	doc *models.Document
 }
 
-func (s *statusDocRepoStub) Create(*models.Document) (int64, error) { return 0, nil }
-func (s *statusDocRepoStub) GetByID(id int64) (*models.Document, error) {
+func (s *statusDocRepoStub) Create(context.Context, *models.Document) (int64, error) { return 0, nil }
+func (s *statusDocRepoStub) GetByID(_ context.Context, id int64) (*models.Document, error) {
 	if s.doc != nil && s.doc.ID == id {
 		return s.doc, nil
 	}
 	return nil, nil
 }
-func (s *statusDocRepoStub) GetByIDWithArchiveScope(id int64, scope repositories.ArchiveScope) (*models.Document, error) {
-	return s.GetByID(id)
+func (s *statusDocRepoStub) GetByIDWithArchiveScope(_ context.Context, id int64, scope repositories.ArchiveScope) (*models.Document, error) {
+	return s.GetByID(context.Background(), id)
 }
-func (s *statusDocRepoStub) ListDocuments(int, int) ([]*models.Document, error) { return nil, nil }
-func (s *statusDocRepoStub) ListDocumentsWithArchiveScope(int, int, repositories.ArchiveScope) ([]*models.Document, error) {
+func (s *statusDocRepoStub) ListDocuments(context.Context, int, int) ([]*models.Document, error) {
 	return nil, nil
 }
-func (s *statusDocRepoStub) ListDocumentsByDeal(int64) ([]*models.Document, error) {
+func (s *statusDocRepoStub) ListDocumentsWithArchiveScope(context.Context, int, int, repositories.ArchiveScope) ([]*models.Document, error) {
 	return nil, nil
 }
-func (s *statusDocRepoStub) ListDocumentsByDealWithArchiveScope(int64, repositories.ArchiveScope) ([]*models.Document, error) {
+func (s *statusDocRepoStub) ListDocumentsByDeal(context.Context, int64) ([]*models.Document, error) {
 	return nil, nil
 }
-func (s *statusDocRepoStub) Delete(int64) error                        { return nil }
-func (s *statusDocRepoStub) Archive(int64, int, string) error          { return nil }
-func (s *statusDocRepoStub) Unarchive(int64) error                     { return nil }
-func (s *statusDocRepoStub) UpdateStatus(int64, string) error          { return nil }
-func (s *statusDocRepoStub) MarkSigned(int64, string, time.Time) error { return nil }
-func (s *statusDocRepoStub) Update(*models.Document) error             { return nil }
-func (s *statusDocRepoStub) UpdateSigningMeta(int64, string, string, string, string) error {
+func (s *statusDocRepoStub) ListDocumentsByDealWithArchiveScope(context.Context, int64, repositories.ArchiveScope) ([]*models.Document, error) {
+	return nil, nil
+}
+func (s *statusDocRepoStub) Delete(context.Context, int64) error                        { return nil }
+func (s *statusDocRepoStub) Archive(context.Context, int64, int, string) error          { return nil }
+func (s *statusDocRepoStub) Unarchive(context.Context, int64) error                     { return nil }
+func (s *statusDocRepoStub) UpdateStatus(context.Context, int64, string) error          { return nil }
+func (s *statusDocRepoStub) MarkSigned(context.Context, int64, string, time.Time) error { return nil }
+func (s *statusDocRepoStub) Update(context.Context, *models.Document) error             { return nil }
+func (s *statusDocRepoStub) UpdateSigningMeta(context.Context, int64, string, string, string, string) error {
 	return nil
 }
 
@@ -474,7 +476,7 @@ func (s *confirmRepoStub) UpdateMeta(_ context.Context, _ string, metaUpdate []b
 
 type confirmDocRepoStub struct{ doc *models.Document }
 
-func (s *confirmDocRepoStub) GetByID(id int64) (*models.Document, error) {
+func (s *confirmDocRepoStub) GetByID(_ context.Context, id int64) (*models.Document, error) {
 	if s.doc != nil && s.doc.ID == id {
 		return s.doc, nil
 	}
@@ -513,7 +515,7 @@ func (r *confirmSignSessionRepoStub) IncrementAttempts(context.Context, int64) (
 
 type confirmDocSignerStub struct{}
 
-func (s *confirmDocSignerStub) FinalizeSigning(int64) error { return nil }
+func (s *confirmDocSignerStub) FinalizeSigning(context.Context, int64) error { return nil }
 
 func TestConfirmByEmailCodeRequiresAgreementFlags(t *testing.T) {
 	gin.SetMode(gin.TestMode)
@@ -50,9 +50,9 @@ func (h *DocumentVersionHandler) ListVersions(c *gin.Context) {
 		return
 	}
 
-	doc, err := h.docRepo.GetByID(docID)
+	doc, err := h.docRepo.GetByID(c.Request.Context(), docID)
 	if err != nil || doc == nil {
-		notFound(c, NotFoundCode, "Документ не найден")
+		notFound(c, DocumentNotFound, "Документ не найден")
 		return
 	}
 
@@ -78,9 +78,9 @@ func (h *DocumentVersionHandler) UploadVersion(c *gin.Context) {
 		return
 	}
 
-	doc, err := h.docRepo.GetByID(docID)
+	doc, err := h.docRepo.GetByID(c.Request.Context(), docID)
 	if err != nil || doc == nil {
-		notFound(c, NotFoundCode, "Документ не найден")
+		notFound(c, DocumentNotFound, "Документ не найден")
 		return
 	}
 
@@ -149,7 +149,7 @@ func (h *DocumentVersionHandler) UploadVersion(c *gin.Context) {
 		doc.FilePathPdf = ""
 		doc.FilePathDocx = ""
 	}
-	if err := h.docRepo.Update(doc); err != nil {
+	if err := h.docRepo.Update(c.Request.Context(), doc); err != nil {
 		h.removeStoredFile(c.Request.Context(), key)
 		internalError(c, "Не удалось обновить документ")
 		return
@@ -236,9 +236,9 @@ func (h *DocumentVersionHandler) RestoreVersion(c *gin.Context) {
 		return
 	}
 
-	doc, err := h.docRepo.GetByID(docID)
+	doc, err := h.docRepo.GetByID(c.Request.Context(), docID)
 	if err != nil || doc == nil {
-		notFound(c, NotFoundCode, "Документ не найден")
+		notFound(c, DocumentNotFound, "Документ не найден")
 		return
 	}
 
@@ -284,7 +284,7 @@ func (h *DocumentVersionHandler) RestoreVersion(c *gin.Context) {
 	doc.FilePath = version.FilePath
 	doc.FilePathPdf = version.FilePathPdf
 	doc.FilePathDocx = version.FilePathDocx
-	if err := h.docRepo.Update(doc); err != nil {
+	if err := h.docRepo.Update(c.Request.Context(), doc); err != nil {
 		internalError(c, "Не удалось восстановить документ")
 		return
 	}
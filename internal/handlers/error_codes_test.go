@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+	"turcompany/internal/repositories"
+	"turcompany/internal/services"
+)
+
+type missingTaskRepo struct {
+	repositories.TaskRepository
+}
+
+func (missingTaskRepo) FindByID(context.Context, int64) (*models.Task, error) { return nil, nil }
+
+type stubPasswordResetService struct {
+	resetErr error
+}
+
+func (stubPasswordResetService) RequestReset(context.Context, string) error { return nil }
+func (s stubPasswordResetService) ResetPassword(context.Context, string, string) error {
+	return s.resetErr
+}
+
+func assertErrorCode(t *testing.T, w *httptest.ResponseRecorder, status int, code string) {
+	t.Helper()
+	if w.Code != status {
+		t.Fatalf("expected %d, got %d: %s", status, w.Code, w.Body.String())
+	}
+	var apiErr APIError
+	if err := json.Unmarshal(w.Body.Bytes(), &apiErr); err != nil {
+		t.Fatalf("decode error body %q: %v", w.Body.String(), err)
+	}
+	if apiErr.ErrorCode != code || apiErr.Message == "" {
+		t.Fatalf("expected error_code %s with a message, got %s", code, w.Body.String())
+	}
+}
+
+func TestErrorCodes_TaskNotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewTaskHandler(services.NewTaskService(missingTaskRepo{}, nil), nil, nil)
+	for name, call := range map[string]func(*gin.Context){"get": h.GetByID, "status": h.ChangeStatus} {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/tasks/404", strings.NewReader(`{"to":"done"}`))
+			c.Params = gin.Params{{Key: "id", Value: "404"}}
+			c.Set("user_id", 10)
+			c.Set("role_id", authz.RoleManagement)
+			call(c)
+			assertErrorCode(t, w, http.StatusNotFound, TaskNotFoundCode)
+		})
+	}
+}
+
+func TestErrorCodes_TaskIllegalTransition(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	branch := int64(1)
+	repo := &lastModifiedTaskRepo{versionedTaskRepo{task: models.Task{ID: 7, CreatorID: 10, AssigneeID: 10, BranchID: &branch, Status: models.StatusDone, Version: 1}}}
+	users := &taskBranchUserRepoStub{users: map[int]*models.User{10: {ID: 10, BranchID: ptrInt(1)}}}
+	h := NewTaskHandler(services.NewTaskService(repo, nil), nil, users)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/tasks/7/status", strings.NewReader(`{"to":"in_progress"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: "7"}}
+	c.Set("user_id", 10)
+	c.Set("role_id", authz.RoleSales)
+	h.ChangeStatus(c)
+
+	assertErrorCode(t, w, http.StatusConflict, TaskTransitionNotAllowedCode)
+}
+
+func TestErrorCodes_AuthLoginAndReset(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authSvc := services.NewAuthService([]byte("01234567890123456789012345678901"), nil, 0, 0, 0, nil)
+
+	t.Run("invalid credentials", func(t *testing.T) {
+		h := NewAuthHandler(&stubUserService{}, authSvc, nil)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(`{"email":"nobody@example.com","password":"Passw0rd!"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		h.Login(c)
+		assertErrorCode(t, w, http.StatusUnauthorized, InvalidCredentialsCode)
+	})
+
+	cases := []struct {
+		err  error
+		code string
+	}{
+		{services.ErrResetTokenNotFound, ResetTokenInvalidCode},
+		{services.ErrResetTokenExpired, ResetTokenExpiredCode},
+		{services.ErrResetTokenUsed, ResetTokenUsedCode},
+		{&services.PasswordPolicyError{Reason: "too short"}, WeakPasswordCode},
+	}
+	for _, tc := range cases {
+		t.Run(tc.code, func(t *testing.T) {
+			h := NewAuthHandler(&stubUserService{}, authSvc, stubPasswordResetService{resetErr: tc.err})
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/auth/reset-password", strings.NewReader(`{"token":"t","password":"Passw0rd!"}`))
+			c.Request.Header.Set("Content-Type", "application/json")
+			h.ResetPassword(c)
+			assertErrorCode(t, w, http.StatusBadRequest, tc.code)
+		})
+	}
+}
+
+func TestErrorCodes_DocumentGenerationUnavailableForHR(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := &DocumentHandler{}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/documents", strings.NewReader(`{"deal_id":1,"doc_type":"contract"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("user_id", 3)
+	c.Set("role_id", authz.RoleHR)
+	h.CreateDocument(c)
+
+	assertErrorCode(t, w, http.StatusForbidden, DocumentGenerationUnavailableCode)
+}
+
+func TestErrorCodes_DocumentWriteByReadOnlyRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewDocumentHandler(services.NewDocumentService(nil, nil, nil, nil, "", "", nil, nil, nil), nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/documents", strings.NewReader(`{"deal_id":1,"doc_type":"contract"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("user_id", 3)
+	c.Set("role_id", authz.RoleControl)
+	h.CreateDocument(c)
+
+	assertErrorCode(t, w, http.StatusForbidden, ReadOnlyRoleCode)
+}
+
+// emptyRowsConnector opens connections whose every query returns no rows.
+type emptyRowsConnector struct{}
+
+func (emptyRowsConnector) Connect(context.Context) (driver.Conn, error) { return emptyRowsConn{}, nil }
+func (emptyRowsConnector) Driver() driver.Driver                        { return nil }
+
+type emptyRowsConn struct{}
+
+func (emptyRowsConn) Prepare(string) (driver.Stmt, error) { return emptyRowsStmt{}, nil }
+func (emptyRowsConn) Close() error                        { return nil }
+func (emptyRowsConn) Begin() (driver.Tx, error)           { return nil, driver.ErrSkip }
+
+type emptyRowsStmt struct{}
+
+func (emptyRowsStmt) Close() error                               { return nil }
+func (emptyRowsStmt) NumInput() int                              { return -1 }
+func (emptyRowsStmt) Exec([]driver.Value) (driver.Result, error) { return driver.RowsAffected(0), nil }
+func (emptyRowsStmt) Query([]driver.Value) (driver.Rows, error)  { return emptyRows{}, nil }
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string         { return nil }
+func (emptyRows) Close() error              { return nil }
+func (emptyRows) Next([]driver.Value) error { return io.EOF }
+
+func TestErrorCodes_DocumentVersionsOfMissingDocument(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := sql.OpenDB(emptyRowsConnector{})
+	t.Cleanup(func() { _ = db.Close() })
+	h := NewDocumentVersionHandler(repositories.NewDocumentRepository(db), nil, nil, "", nil)
+
+	for name, call := range map[string]func(*gin.Context){"list": h.ListVersions, "upload": h.UploadVersion, "restore": h.RestoreVersion} {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/documents/404/versions/1", nil)
+			c.Params = gin.Params{{Key: "id", Value: "404"}, {Key: "vid", Value: "1"}}
+			c.Set("user_id", 1)
+			c.Set("role_id", authz.RoleManagement)
+			call(c)
+			assertErrorCode(t, w, http.StatusNotFound, DocumentNotFound)
+		})
+	}
+}
@@ -20,30 +20,46 @@ const (
 	ConflictCode      = "CONFLICT"
 	InternalErrorCode = "INTERNAL_ERROR"
 
-	DealNotFoundCode       = "DEAL_NOT_FOUND"
-	LeadNotFoundCode       = "LEAD_NOT_FOUND"
-	DocumentNotFound       = "DOCUMENT_NOT_FOUND"
-	ClientNotFoundCode     = "CLIENT_NOT_FOUND"
-	ReadOnlyRoleCode       = "READ_ONLY_ROLE"
-	UserBranchRequiredCode = "USER_BRANCH_REQUIRED"
-	InvalidEmailCode       = "INVALID_EMAIL"
-	InvalidDateFormatCode  = "INVALID_DATE_FORMAT"
-	EmailAlreadyUsedCode   = "EMAIL_ALREADY_USED"
-	UnsupportedDocType     = "UNSUPPORTED_DOC_TYPE"
-	InvalidStatusCode      = "INVALID_STATUS"
-	ValidationFailed       = "VALIDATION_FAILED"
-	ExpiredCode            = "EXPIRED"
-	DealAlreadyExistsCode  = "DEAL_ALREADY_EXISTS_FOR_LEAD"
-	ClientAlreadyExists    = "CLIENT_ALREADY_EXISTS"
-	ClientInUseCode        = "CLIENT_IN_USE"
-	ChatNotFoundCode       = "CHAT_NOT_FOUND"
-	ChatNotMemberCode      = "CHAT_NOT_MEMBER"
-	ChatForbiddenCode      = "CHAT_FORBIDDEN"
-	ChatUserNotFoundCode   = "CHAT_USER_NOT_FOUND"
-	ChatUserInactiveCode   = "CHAT_USER_INACTIVE"
-	DirectChatWithSelfCode = "DIRECT_CHAT_WITH_SELF"
-	ChatInvalidPayloadCode = "CHAT_INVALID_PAYLOAD"
-	ChatConflictCode       = "CHAT_CONFLICT"
+	DealNotFoundCode         = "DEAL_NOT_FOUND"
+	LeadNotFoundCode         = "LEAD_NOT_FOUND"
+	DocumentNotFound         = "DOCUMENT_NOT_FOUND"
+	ClientNotFoundCode       = "CLIENT_NOT_FOUND"
+	ReadOnlyRoleCode         = "READ_ONLY_ROLE"
+	UserBranchRequiredCode   = "USER_BRANCH_REQUIRED"
+	InvalidEmailCode         = "INVALID_EMAIL"
+	InvalidDateFormatCode    = "INVALID_DATE_FORMAT"
+	EmailAlreadyUsedCode     = "EMAIL_ALREADY_USED"
+	UnsupportedDocType       = "UNSUPPORTED_DOC_TYPE"
+	InvalidStatusCode        = "INVALID_STATUS"
+	ValidationFailed         = "VALIDATION_FAILED"
+	ExpiredCode              = "EXPIRED"
+	AccountLockedCode        = "ACCOUNT_LOCKED"
+	DealAlreadyExistsCode    = "DEAL_ALREADY_EXISTS_FOR_LEAD"
+	ClientAlreadyExists      = "CLIENT_ALREADY_EXISTS"
+	ClientInUseCode          = "CLIENT_IN_USE"
+	ChatNotFoundCode         = "CHAT_NOT_FOUND"
+	ChatNotMemberCode        = "CHAT_NOT_MEMBER"
+	ChatForbiddenCode        = "CHAT_FORBIDDEN"
+	ChatUserNotFoundCode     = "CHAT_USER_NOT_FOUND"
+	ChatUserInactiveCode     = "CHAT_USER_INACTIVE"
+	DirectChatWithSelfCode   = "DIRECT_CHAT_WITH_SELF"
+	ChatInvalidPayloadCode   = "CHAT_INVALID_PAYLOAD"
+	ChatConflictCode         = "CHAT_CONFLICT"
+	TaskVersionConflictCode  = "TASK_VERSION_CONFLICT"
+	SMSSignatureRequiredCode = "SMS_SIGNATURE_REQUIRED"
+
+	TaskNotFoundCode                  = "TASK_NOT_FOUND"
+	TaskTransitionNotAllowedCode      = "TASK_TRANSITION_NOT_ALLOWED"
+	DocumentGenerationUnavailableCode = "DOCUMENT_GENERATION_UNAVAILABLE"
+	InvalidCredentialsCode            = "INVALID_CREDENTIALS"
+	UserDisabledCode                  = "USER_DISABLED"
+	UserNotVerifiedCode               = "USER_NOT_VERIFIED"
+	InvalidRefreshTokenCode           = "INVALID_REFRESH_TOKEN"
+	RefreshTokenExpiredCode           = "REFRESH_TOKEN_EXPIRED"
+	ResetTokenInvalidCode             = "RESET_TOKEN_INVALID"
+	ResetTokenExpiredCode             = "RESET_TOKEN_EXPIRED"
+	ResetTokenUsedCode                = "RESET_TOKEN_USED"
+	WeakPasswordCode                  = "WEAK_PASSWORD"
 )
 
 func writeError(c *gin.Context, status int, code string, msg string) {
@@ -3,6 +3,9 @@ package handlers
 import (
 	"errors"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"turcompany/internal/services"
@@ -36,3 +39,61 @@ func (h *FeedHandler) List(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, gin.H{"data": entries})
 }
+
+// AuditLog serves GET /audit: the audit trail of mutating requests for admin
+// and quality_control. Filters: user_id, from and to (YYYY-MM-DD, both
+// inclusive), plus page/size.
+func (h *FeedHandler) AuditLog(c *gin.Context) {
+	_, roleID := getUserAndRole(c)
+	var q services.AuditLogQuery
+	if raw := strings.TrimSpace(c.Query("user_id")); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			badRequest(c, "invalid user_id")
+			return
+		}
+		q.ActorUserID = &id
+	}
+	var ok bool
+	if q.From, ok = parseOptionalDay(c, "from"); !ok {
+		return
+	}
+	if q.To, ok = parseOptionalDay(c, "to"); !ok {
+		return
+	}
+	if q.To != nil {
+		// "to" is inclusive: stop at the start of the next day
+		next := q.To.AddDate(0, 0, 1)
+		q.To = &next
+	}
+	page, size := normalizedPageAndSize(c)
+
+	entries, err := h.audit.ListAuditLog(c.Request.Context(), roleID, q, size, offsetFromPage(page, size))
+	if err != nil {
+		if errors.Is(err, services.ErrForbidden) {
+			forbidden(c, "forbidden")
+			return
+		}
+		internalError(c, "failed to load audit log")
+		return
+	}
+	if entries == nil {
+		entries = []*services.AuditLogEntry{}
+	}
+	c.JSON(http.StatusOK, gin.H{"data": entries})
+}
+
+// parseOptionalDay parses a YYYY-MM-DD query parameter; an empty value yields
+// nil. On a malformed value it writes 400 and returns false.
+func parseOptionalDay(c *gin.Context, key string) (*time.Time, bool) {
+	raw := strings.TrimSpace(c.Query(key))
+	if raw == "" {
+		return nil, true
+	}
+	t, err := time.Parse(dateLayout, raw)
+	if err != nil {
+		badRequestWithCode(c, InvalidDateFormatCode, "invalid "+key+", expected YYYY-MM-DD")
+		return nil, false
+	}
+	return &t, true
+}
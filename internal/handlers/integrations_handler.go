@@ -8,7 +8,9 @@ import (
 	"errors"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -27,6 +29,9 @@ type IntegrationsHandler struct {
 	ConfigSource string
 	DBDSNMasked  string
 	FrontendHost string
+
+	updatesOnce sync.Once
+	updates     *telegramUpdateQueue
 }
 
 func toInt(v interface{}) int {
@@ -59,11 +64,30 @@ func (h *IntegrationsHandler) Webhook(c *gin.Context) {
 		log.Printf("[TG:WEBHOOK] failed to bind update: %v", err)
 		return
 	}
-	if err := h.TG.HandleUpdate(&up); err != nil {
-		log.Printf("[TG:WEBHOOK] handle error: %v", err)
+	// Telegram redelivers an update when our reply is slow or fails.
+	if up.UpdateID != 0 && dropIfDuplicate("tg:"+strconv.Itoa(up.UpdateID), 10*time.Minute) {
+		log.Printf("[TG:WEBHOOK] duplicate update_id=%d dropped", up.UpdateID)
+		return
+	}
+	// Ответ боту (с ретраями и их паузами) уходит вне запроса, чтобы
+	// медленный Bot API не задерживал подтверждение вебхука.
+	if !h.updateQueue().Enqueue(c.Request.Context(), &up) {
+		log.Printf("[TG:WEBHOOK] queue full, update_id=%d dropped", up.UpdateID)
 	}
 }
 
+// updateQueue starts the Telegram update workers on first use.
+func (h *IntegrationsHandler) updateQueue() *telegramUpdateQueue {
+	h.updatesOnce.Do(func() {
+		h.updates = newTelegramUpdateQueue(telegramUpdateWorkers, telegramUpdateQueueDepth, func(up *services.TelegramUpdate) {
+			if err := h.TG.HandleUpdate(up); err != nil {
+				log.Printf("[TG:WEBHOOK] handle error: %v", err)
+			}
+		})
+	})
+	return h.updates
+}
+
 // GET /integrations/telegram/link?code=...
 func (h *IntegrationsHandler) ConfirmLink(c *gin.Context) {
 	code := strings.TrimSpace(c.Query("code"))
@@ -169,11 +193,10 @@ func (h *IntegrationsHandler) ConfirmLink(c *gin.Context) {
 			log.Printf("[TG:LINK] load tasks failed userID=%d: %v", userID, err)
 		}
 
-		msg := "✅ <b>Аккаунт успешно привязан к CRM</b>\n\n" +
-			"Теперь вы будете получать уведомления о задачах.\n\n" +
-			h.TG.FormatTasksList(tasks)
+		prefix := "✅ <b>Аккаунт успешно привязан к CRM</b>\n\n" +
+			"Теперь вы будете получать уведомления о задачах.\n\n"
 
-		if err := h.TG.SendMessage(chatID, msg); err != nil {
+		if err := h.TG.SendTasksDigest(chatID, prefix, tasks); err != nil {
 			log.Printf("[TG:LINK] send welcome msg failed: %v", err)
 		}
 	}
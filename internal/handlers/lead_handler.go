@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"io"
 	"log"
@@ -21,25 +22,30 @@ type LeadHandler struct {
 }
 
 type leadService interface {
-	Create(lead *models.Leads, userID, roleID int) (int64, error)
-	Update(lead *models.Leads, userID, roleID int) error
-	GetByID(id int, userID, roleID int) (*models.Leads, error)
-	GetByIDWithArchiveScope(id int, userID, roleID int, scope repositories.ArchiveScope) (*models.Leads, error)
-	Delete(id int, userID, roleID int) error
-	ListForRole(userID, roleID, limit, offset int, scope repositories.ArchiveScope, filter repositories.LeadListFilter) ([]*models.Leads, error)
-	ListMyWithArchiveScope(ownerID, limit, offset int, scope repositories.ArchiveScope) ([]*models.Leads, error)
-	ListMyWithFilterAndArchiveScope(ownerID, limit, offset int, scope repositories.ArchiveScope, filter repositories.LeadListFilter) ([]*models.Leads, error)
-	AssignOwner(id, assigneeID, userID, roleID int) error
-	UpdateStatus(id int, to string, userID, roleID int) error
-	ArchiveLead(id, userID, roleID int, reason string) error
-	UnarchiveLead(id, userID, roleID int) error
-	ConvertLeadToDeal(leadID int, amount float64, currency string, ownerID, userID, roleID int, clientID int, clientType string) (*models.Deals, error)
-	ConvertLeadToDealWithClientData(leadID int, amount float64, currency string, ownerID, userID, roleID int, clientData *models.Client) (*models.Deals, error)
+	Create(ctx context.Context, lead *models.Leads, userID, roleID int) (int64, error)
+	Update(ctx context.Context, lead *models.Leads, userID, roleID int) error
+	GetByID(ctx context.Context, id int, userID, roleID int) (*models.Leads, error)
+	GetByIDWithArchiveScope(ctx context.Context, id int, userID, roleID int, scope repositories.ArchiveScope) (*models.Leads, error)
+	Delete(ctx context.Context, id int, userID, roleID int) error
+	ListForRole(ctx context.Context, userID, roleID, limit, offset int, scope repositories.ArchiveScope, filter repositories.LeadListFilter) ([]*models.Leads, error)
+	ListMyWithArchiveScope(ctx context.Context, ownerID, limit, offset int, scope repositories.ArchiveScope) ([]*models.Leads, error)
+	ListMyWithFilterAndArchiveScope(ctx context.Context, ownerID, limit, offset int, scope repositories.ArchiveScope, filter repositories.LeadListFilter) ([]*models.Leads, error)
+	AssignOwner(ctx context.Context, id, assigneeID, userID, roleID int) error
+	UpdateStatus(ctx context.Context, id int, to string, userID, roleID int) error
+	ArchiveLead(ctx context.Context, id, userID, roleID int, reason string) error
+	UnarchiveLead(ctx context.Context, id, userID, roleID int) error
+	ConvertLeadToDeal(ctx context.Context, leadID int, amount float64, currency string, ownerID, userID, roleID int, clientID int, clientType string) (*models.Deals, error)
+	ConvertLeadToDealWithClientData(ctx context.Context, leadID int, amount float64, currency string, ownerID, userID, roleID int, clientData *models.Client) (*models.Deals, error)
 }
 
 type leadPaginationService interface {
-	ListForRoleWithTotal(userID, roleID, limit, offset int, scope repositories.ArchiveScope, filter repositories.LeadListFilter) ([]*models.Leads, int, error)
-	ListMyWithFilterAndArchiveScopeAndTotal(ownerID, limit, offset int, scope repositories.ArchiveScope, filter repositories.LeadListFilter) ([]*models.Leads, int, error)
+	ListForRoleWithTotal(ctx context.Context, userID, roleID, limit, offset int, scope repositories.ArchiveScope, filter repositories.LeadListFilter) ([]*models.Leads, int, error)
+	ListMyWithFilterAndArchiveScopeAndTotal(ctx context.Context, ownerID, limit, offset int, scope repositories.ArchiveScope, filter repositories.LeadListFilter) ([]*models.Leads, int, error)
+}
+
+// leadScorer is implemented by LeadService; POST /leads/:id/score needs it.
+type leadScorer interface {
+	SetScore(ctx context.Context, id, score, userID, roleID int) error
 }
 
 func NewLeadHandler(service *services.LeadService) *LeadHandler {
@@ -49,7 +55,7 @@ func NewLeadHandler(service *services.LeadService) *LeadHandler {
 func (h *LeadHandler) Create(c *gin.Context) {
 	var lead models.Leads
 	if err := c.ShouldBindJSON(&lead); err != nil {
-		badRequest(c, "Invalid payload")
+		bindError(c, err, "Invalid payload")
 		return
 	}
 
@@ -64,12 +70,20 @@ func (h *LeadHandler) Create(c *gin.Context) {
 		lead.Status = "new"
 	}
 
-	id, err := h.Service.Create(&lead, userID, roleID)
+	id, err := h.Service.Create(c.Request.Context(), &lead, userID, roleID)
 	if err != nil {
 		if errors.Is(err, services.ErrForbidden) || errors.Is(err, services.ErrReadOnly) {
 			forbidden(c, err.Error())
 			return
 		}
+		if errors.Is(err, services.ErrInvalidLeadSource) {
+			badRequest(c, "Invalid source")
+			return
+		}
+		if errors.Is(err, services.ErrInvalidLeadScore) {
+			badRequest(c, err.Error())
+			return
+		}
 		internalError(c, "Failed to create lead")
 		return
 	}
@@ -90,7 +104,7 @@ func (h *LeadHandler) Update(c *gin.Context) {
 		return
 	}
 
-	current, err := h.Service.GetByID(id, userID, roleID)
+	current, err := h.Service.GetByID(c.Request.Context(), id, userID, roleID)
 	if err != nil || current == nil {
 		if errors.Is(err, services.ErrForbidden) {
 			forbidden(c, "Forbidden")
@@ -102,19 +116,27 @@ func (h *LeadHandler) Update(c *gin.Context) {
 
 	var body models.Leads
 	if err := c.ShouldBindJSON(&body); err != nil {
-		badRequest(c, "Invalid payload")
+		bindError(c, err, "Invalid payload")
 		return
 	}
 	body.ID = id
-	if err := h.Service.Update(&body, userID, roleID); err != nil {
+	if err := h.Service.Update(c.Request.Context(), &body, userID, roleID); err != nil {
 		if errors.Is(err, services.ErrForbidden) || errors.Is(err, services.ErrReadOnly) {
 			forbidden(c, err.Error())
 			return
 		}
+		if errors.Is(err, services.ErrInvalidLeadSource) {
+			badRequest(c, "Invalid source")
+			return
+		}
+		if errors.Is(err, services.ErrInvalidLeadScore) {
+			badRequest(c, err.Error())
+			return
+		}
 		internalError(c, "Failed to update lead")
 		return
 	}
-	updated, _ := h.Service.GetByID(id, userID, roleID)
+	updated, _ := h.Service.GetByID(c.Request.Context(), id, userID, roleID)
 	c.JSON(200, updated)
 }
 
@@ -126,7 +148,7 @@ func (h *LeadHandler) GetByID(c *gin.Context) {
 	}
 
 	userID, roleID := getUserAndRole(c)
-	lead, err := h.Service.GetByID(id, userID, roleID)
+	lead, err := h.Service.GetByID(c.Request.Context(), id, userID, roleID)
 	if err != nil || lead == nil {
 		if errors.Is(err, services.ErrForbidden) {
 			forbidden(c, "Forbidden")
@@ -151,7 +173,7 @@ func (h *LeadHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	lead, err := h.Service.GetByIDWithArchiveScope(id, userID, roleID, repositories.ArchiveScopeAll)
+	lead, err := h.Service.GetByIDWithArchiveScope(c.Request.Context(), id, userID, roleID, repositories.ArchiveScopeAll)
 	if err != nil || lead == nil {
 		if errors.Is(err, services.ErrForbidden) {
 			forbidden(c, "Forbidden")
@@ -161,7 +183,7 @@ func (h *LeadHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.Service.Delete(id, userID, roleID); err != nil {
+	if err := h.Service.Delete(c.Request.Context(), id, userID, roleID); err != nil {
 		if errors.Is(err, services.ErrForbidden) || errors.Is(err, services.ErrReadOnly) {
 			forbidden(c, err.Error())
 			return
@@ -188,7 +210,7 @@ func (h *LeadHandler) Archive(c *gin.Context) {
 		return
 	}
 	userID, roleID := getUserAndRole(c)
-	if err := h.Service.ArchiveLead(id, userID, roleID, req.Reason); err != nil {
+	if err := h.Service.ArchiveLead(c.Request.Context(), id, userID, roleID, req.Reason); err != nil {
 		if errors.Is(err, services.ErrForbidden) || errors.Is(err, services.ErrReadOnly) {
 			forbidden(c, err.Error())
 			return
@@ -200,7 +222,7 @@ func (h *LeadHandler) Archive(c *gin.Context) {
 		internalError(c, "Failed to archive lead")
 		return
 	}
-	updated, _ := h.Service.GetByIDWithArchiveScope(id, userID, roleID, repositories.ArchiveScopeAll)
+	updated, _ := h.Service.GetByIDWithArchiveScope(c.Request.Context(), id, userID, roleID, repositories.ArchiveScopeAll)
 	c.JSON(http.StatusOK, updated)
 }
 
@@ -211,7 +233,7 @@ func (h *LeadHandler) Unarchive(c *gin.Context) {
 		return
 	}
 	userID, roleID := getUserAndRole(c)
-	if err := h.Service.UnarchiveLead(id, userID, roleID); err != nil {
+	if err := h.Service.UnarchiveLead(c.Request.Context(), id, userID, roleID); err != nil {
 		if errors.Is(err, services.ErrForbidden) || errors.Is(err, services.ErrReadOnly) {
 			forbidden(c, err.Error())
 			return
@@ -227,7 +249,7 @@ func (h *LeadHandler) Unarchive(c *gin.Context) {
 		internalError(c, "Failed to unarchive lead")
 		return
 	}
-	updated, _ := h.Service.GetByID(id, userID, roleID)
+	updated, _ := h.Service.GetByID(c.Request.Context(), id, userID, roleID)
 	c.JSON(http.StatusOK, updated)
 }
 
@@ -256,7 +278,7 @@ func (h *LeadHandler) Assign(c *gin.Context) {
 		return
 	}
 
-	lead, err := h.Service.GetByID(id, actorID, roleID)
+	lead, err := h.Service.GetByID(c.Request.Context(), id, actorID, roleID)
 	if err != nil || lead == nil {
 		if errors.Is(err, services.ErrForbidden) {
 			forbidden(c, "Forbidden")
@@ -266,7 +288,7 @@ func (h *LeadHandler) Assign(c *gin.Context) {
 		return
 	}
 
-	if err := h.Service.AssignOwner(id, req.AssigneeID, actorID, roleID); err != nil {
+	if err := h.Service.AssignOwner(c.Request.Context(), id, req.AssigneeID, actorID, roleID); err != nil {
 		if errors.Is(err, services.ErrForbidden) {
 			forbidden(c, "Forbidden")
 			return
@@ -274,7 +296,7 @@ func (h *LeadHandler) Assign(c *gin.Context) {
 		internalError(c, "Failed to assign lead")
 		return
 	}
-	updated, _ := h.Service.GetByID(id, actorID, roleID)
+	updated, _ := h.Service.GetByID(c.Request.Context(), id, actorID, roleID)
 	c.JSON(http.StatusOK, updated)
 }
 
@@ -303,7 +325,7 @@ func (h *LeadHandler) UpdateStatus(c *gin.Context) {
 		return
 	}
 
-	lead, err := h.Service.GetByID(id, userID, roleID)
+	lead, err := h.Service.GetByID(c.Request.Context(), id, userID, roleID)
 	if err != nil || lead == nil {
 		if errors.Is(err, services.ErrForbidden) {
 			forbidden(c, "Forbidden")
@@ -318,7 +340,7 @@ func (h *LeadHandler) UpdateStatus(c *gin.Context) {
 		return
 	}
 
-	if err := h.Service.UpdateStatus(id, req.To, userID, roleID); err != nil {
+	if err := h.Service.UpdateStatus(c.Request.Context(), id, req.To, userID, roleID); err != nil {
 		if errors.Is(err, services.ErrForbidden) || errors.Is(err, services.ErrReadOnly) {
 			forbidden(c, err.Error())
 			return
@@ -327,7 +349,55 @@ func (h *LeadHandler) UpdateStatus(c *gin.Context) {
 		return
 	}
 
-	updated, _ := h.Service.GetByID(id, userID, roleID)
+	updated, _ := h.Service.GetByID(c.Request.Context(), id, userID, roleID)
+	c.JSON(http.StatusOK, updated)
+}
+
+// --- Score ---
+type setLeadScoreRequest struct {
+	Score *int `json:"score" binding:"required"`
+}
+
+// SetScore handles POST /leads/:id/score: rates a lead 0–100 and returns it.
+func (h *LeadHandler) SetScore(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		badRequest(c, "Invalid id")
+		return
+	}
+
+	var req setLeadScoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err, "Invalid payload")
+		return
+	}
+
+	userID, roleID := getUserAndRole(c)
+	if authz.IsReadOnly(roleID) {
+		forbidden(c, "Read-only role")
+		return
+	}
+	scorer, ok := h.Service.(leadScorer)
+	if !ok {
+		internalError(c, "Lead scoring is not available")
+		return
+	}
+
+	if err := scorer.SetScore(c.Request.Context(), id, *req.Score, userID, roleID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidLeadScore):
+			badRequest(c, err.Error())
+		case errors.Is(err, services.ErrForbidden), errors.Is(err, services.ErrReadOnly):
+			forbidden(c, err.Error())
+		case errors.Is(err, services.ErrLeadNotFound):
+			notFound(c, LeadNotFoundCode, "Lead not found")
+		default:
+			internalError(c, "Failed to update lead score")
+		}
+		return
+	}
+
+	updated, _ := h.Service.GetByID(c.Request.Context(), id, userID, roleID)
 	c.JSON(http.StatusOK, updated)
 }
 
@@ -370,7 +440,7 @@ func (h *LeadHandler) ConvertToDeal(c *gin.Context) {
 		forbidden(c, "Read-only role")
 		return
 	}
-	lead, err := h.Service.GetByID(id, userID, roleID)
+	lead, err := h.Service.GetByID(c.Request.Context(), id, userID, roleID)
 	if err != nil || lead == nil {
 		if errors.Is(err, services.ErrForbidden) {
 			forbidden(c, "Forbidden")
@@ -382,7 +452,7 @@ func (h *LeadHandler) ConvertToDeal(c *gin.Context) {
 
 	var req ConvertLeadByIDRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		badRequest(c, "Invalid payload")
+		bindError(c, err, "Invalid payload")
 		return
 	}
 	if req.Amount <= 0 || strings.TrimSpace(req.Currency) == "" || req.ClientID <= 0 || strings.TrimSpace(req.ClientType) == "" {
@@ -390,12 +460,16 @@ func (h *LeadHandler) ConvertToDeal(c *gin.Context) {
 		return
 	}
 
-	deal, convErr := h.Service.ConvertLeadToDeal(id, req.Amount, req.Currency, lead.OwnerID, userID, roleID, req.ClientID, req.ClientType)
+	deal, convErr := h.Service.ConvertLeadToDeal(c.Request.Context(), id, req.Amount, req.Currency, lead.OwnerID, userID, roleID, req.ClientID, req.ClientType)
 	if convErr != nil {
 		log.Printf(
 			"lead conversion failed: lead_id=%d user_id=%d role_id=%d client_id=%d client_type=%q err=%v",
 			id, userID, roleID, req.ClientID, req.ClientType, convErr,
 		)
+		if errors.Is(convErr, services.ErrLeadNotFound) {
+			notFound(c, LeadNotFoundCode, "Lead not found")
+			return
+		}
 		if errors.Is(convErr, services.ErrClientNotFound) {
 			notFound(c, ClientNotFoundCode, "Client not found")
 			return
@@ -426,7 +500,7 @@ func (h *LeadHandler) ConvertToDealWithClient(c *gin.Context) {
 		forbidden(c, "Read-only role")
 		return
 	}
-	lead, err := h.Service.GetByID(id, userID, roleID)
+	lead, err := h.Service.GetByID(c.Request.Context(), id, userID, roleID)
 	if err != nil || lead == nil {
 		if errors.Is(err, services.ErrForbidden) {
 			forbidden(c, "Forbidden")
@@ -438,7 +512,7 @@ func (h *LeadHandler) ConvertToDealWithClient(c *gin.Context) {
 
 	var req ConvertLeadWithClientRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		badRequest(c, "Invalid payload")
+		bindError(c, err, "Invalid payload")
 		return
 	}
 	if req.Amount <= 0 || strings.TrimSpace(req.Currency) == "" || strings.TrimSpace(req.ClientType) == "" {
@@ -450,12 +524,16 @@ func (h *LeadHandler) ConvertToDealWithClient(c *gin.Context) {
 		badRequest(c, "Invalid payload")
 		return
 	}
-	deal, convErr := h.Service.ConvertLeadToDealWithClientData(id, req.Amount, req.Currency, lead.OwnerID, userID, roleID, client)
+	deal, convErr := h.Service.ConvertLeadToDealWithClientData(c.Request.Context(), id, req.Amount, req.Currency, lead.OwnerID, userID, roleID, client)
 	if convErr != nil {
 		log.Printf(
 			"lead conversion with client failed: lead_id=%d user_id=%d role_id=%d client_id=%d client_type=%q err=%v",
 			id, userID, roleID, 0, req.ClientType, convErr,
 		)
+		if errors.Is(convErr, services.ErrLeadNotFound) {
+			notFound(c, LeadNotFoundCode, "Lead not found")
+			return
+		}
 		if isLeadConversionBadRequestError(convErr) {
 			badRequest(c, convErr.Error())
 			return
@@ -474,14 +552,12 @@ func isLeadConversionBadRequestError(err error) bool {
 	if err == nil {
 		return false
 	}
-	if errors.Is(err, services.ErrClientTypeRequired) || errors.Is(err, services.ErrClientTypeMismatch) {
-		return true
-	}
-	errText := err.Error()
-	return strings.Contains(errText, "invalid client_type") ||
-		strings.Contains(errText, "lead is not in a convertible status") ||
-		strings.Contains(errText, "client_type is required") ||
-		strings.Contains(errText, "client_type does not match")
+	return errors.Is(err, services.ErrClientTypeRequired) ||
+		errors.Is(err, services.ErrClientTypeMismatch) ||
+		errors.Is(err, services.ErrInvalidClientType) ||
+		errors.Is(err, services.ErrCurrencyRequired) ||
+		errors.Is(err, services.ErrInvalidCurrency) ||
+		errors.Is(err, services.ErrLeadNotConvertible)
 }
 
 func firstNonEmpty(values ...string) string {
@@ -563,6 +639,32 @@ func (h *LeadHandler) List(c *gin.Context) {
 		badRequest(c, err.Error())
 		return
 	}
+	after, cursorMode, err := cursorFromQuery(c)
+	if err != nil {
+		badRequest(c, "Invalid cursor")
+		return
+	}
+	if cursorMode {
+		if filter.SortBy != "" && filter.SortBy != "created_at" {
+			badRequest(c, "Cursor pagination is ordered by created_at")
+			return
+		}
+		filter.After = after
+		_, size = normalizedPageAndSize(c)
+		// лишняя строка показывает, есть ли следующая страница
+		leads, err := h.Service.ListForRole(c.Request.Context(), userID, roleID, size+1, 0, scope, filter)
+		if err != nil {
+			if errors.Is(err, services.ErrForbidden) {
+				forbidden(c, "Forbidden")
+				return
+			}
+			log.Printf("lead list failed: user_id=%d role_id=%d scope=%s filter=%+v err=%v", userID, roleID, scope, filter, err)
+			internalError(c, "Failed to list leads")
+			return
+		}
+		writeCursorPage(c, leads, size, leadCursor)
+		return
+	}
 
 	if paginate {
 		pSvc, ok := h.Service.(leadPaginationService)
@@ -570,7 +672,7 @@ func (h *LeadHandler) List(c *gin.Context) {
 			internalError(c, "Pagination is not supported")
 			return
 		}
-		leads, total, err := pSvc.ListForRoleWithTotal(userID, roleID, size, offset, scope, filter)
+		leads, total, err := pSvc.ListForRoleWithTotal(c.Request.Context(), userID, roleID, size, offset, scope, filter)
 		if err != nil {
 			if errors.Is(err, services.ErrForbidden) {
 				forbidden(c, "Forbidden")
@@ -580,11 +682,11 @@ func (h *LeadHandler) List(c *gin.Context) {
 			internalError(c, "Failed to list leads")
 			return
 		}
-		c.JSON(http.StatusOK, models.PaginatedResponse[*models.Leads]{Items: leads, Pagination: buildPaginationMeta(page, size, total)})
+		writePaginated(c, leads, page, size, total)
 		return
 	}
 
-	leads, err := h.Service.ListForRole(userID, roleID, size, offset, scope, filter)
+	leads, err := h.Service.ListForRole(c.Request.Context(), userID, roleID, size, offset, scope, filter)
 	if err != nil {
 		if errors.Is(err, services.ErrForbidden) {
 			forbidden(c, "Forbidden")
@@ -635,7 +737,7 @@ func (h *LeadHandler) ListMy(c *gin.Context) {
 			internalError(c, "Pagination is not supported")
 			return
 		}
-		leads, total, err := pSvc.ListMyWithFilterAndArchiveScopeAndTotal(userID, size, offset, scope, filter)
+		leads, total, err := pSvc.ListMyWithFilterAndArchiveScopeAndTotal(c.Request.Context(), userID, size, offset, scope, filter)
 		if err != nil {
 			if errors.Is(err, services.ErrForbidden) {
 				forbidden(c, "Forbidden")
@@ -645,11 +747,11 @@ func (h *LeadHandler) ListMy(c *gin.Context) {
 			internalError(c, "Failed to list leads")
 			return
 		}
-		c.JSON(http.StatusOK, models.PaginatedResponse[*models.Leads]{Items: leads, Pagination: buildPaginationMeta(page, size, total)})
+		writePaginated(c, leads, page, size, total)
 		return
 	}
 
-	leads, err := h.Service.ListMyWithFilterAndArchiveScope(userID, size, offset, scope, filter)
+	leads, err := h.Service.ListMyWithFilterAndArchiveScope(c.Request.Context(), userID, size, offset, scope, filter)
 	if err != nil {
 		if errors.Is(err, services.ErrForbidden) {
 			forbidden(c, "Forbidden")
@@ -674,15 +776,11 @@ func leadListFilterFromQuery(c *gin.Context) (repositories.LeadListFilter, error
 		return repositories.LeadListFilter{}, errors.New("Invalid status_group")
 	}
 	filter.Source = strings.ToLower(strings.TrimSpace(c.Query("source")))
-	if filter.Source != "" {
-		switch filter.Source {
-		case "whatsapp", "telegram", "instagram", "web", "phone", "manual":
-		default:
-			return repositories.LeadListFilter{}, errors.New("Invalid source")
-		}
+	if filter.Source != "" && !models.IsKnownLeadSource(filter.Source) {
+		return repositories.LeadListFilter{}, errors.New("Invalid source")
 	}
 	filter.SortBy = strings.ToLower(strings.TrimSpace(c.Query("sort_by")))
-	if filter.SortBy != "" && filter.SortBy != "created_at" && filter.SortBy != "status" && filter.SortBy != "title" {
+	if filter.SortBy != "" && filter.SortBy != "created_at" && filter.SortBy != "status" && filter.SortBy != "title" && filter.SortBy != "updated_at" && filter.SortBy != "score" {
 		return repositories.LeadListFilter{}, errors.New("Invalid sort_by")
 	}
 	filter.Order = strings.ToLower(strings.TrimSpace(c.Query("order")))
@@ -707,3 +805,8 @@ func isAllowedLeadStatus(status string) bool {
 		return false
 	}
 }
+
+// leadCursor is the keyset position of a lead in ?cursor= listings.
+func leadCursor(v *models.Leads) repositories.ListCursor {
+	return repositories.ListCursor{CreatedAt: v.CreatedAt, ID: v.ID}
+}
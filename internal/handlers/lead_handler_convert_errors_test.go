@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"turcompany/internal/services"
@@ -16,19 +17,19 @@ func TestIsLeadConversionBadRequestError_BySentinel(t *testing.T) {
 	}
 }
 
-func TestIsLeadConversionBadRequestError_ByMessage(t *testing.T) {
-	cases := []string{
-		"invalid client_type",
-		"lead is not in a convertible status",
-		"client_type is required",
-		"client_type does not match",
-	}
-
-	for _, message := range cases {
-		if !isLeadConversionBadRequestError(errors.New(message)) {
-			t.Fatalf("expected %q to be treated as bad request", message)
+func TestIsLeadConversionBadRequestError_WrappedSentinels(t *testing.T) {
+	for _, err := range []error{
+		fmt.Errorf("convert: %w", services.ErrInvalidClientType),
+		fmt.Errorf("convert: %w", services.ErrLeadNotConvertible),
+	} {
+		if !isLeadConversionBadRequestError(err) {
+			t.Fatalf("expected %v to be treated as bad request", err)
 		}
 	}
+	// Message text alone no longer matters.
+	if isLeadConversionBadRequestError(errors.New("lead is not in a convertible status")) {
+		t.Fatal("did not expect an untyped error to be treated as bad request")
+	}
 }
 
 func TestIsLeadConversionBadRequestError_OtherError(t *testing.T) {
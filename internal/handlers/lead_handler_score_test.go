@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+	"turcompany/internal/services"
+)
+
+// scoringLeadStub adds SetScore, with the service's range check, to the lead stub.
+type scoringLeadStub struct {
+	leadHandlerStubService
+	lead  *models.Leads
+	calls int
+}
+
+func (s *scoringLeadStub) GetByID(context.Context, int, int, int) (*models.Leads, error) {
+	return s.lead, nil
+}
+
+func (s *scoringLeadStub) SetScore(_ context.Context, id, score, _, _ int) error {
+	if score < models.LeadScoreMin || score > models.LeadScoreMax {
+		return services.ErrInvalidLeadScore
+	}
+	if s.lead == nil || s.lead.ID != id {
+		return services.ErrLeadNotFound
+	}
+	s.calls++
+	s.lead.Score = &score
+	return nil
+}
+
+func postLeadScore(h *LeadHandler, id, body string, roleID int) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/leads/"+id+"/score", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: id}}
+	c.Set("user_id", 1)
+	c.Set("role_id", roleID)
+	h.SetScore(c)
+	return w
+}
+
+func TestLeadHandler_SetScore(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := &scoringLeadStub{lead: &models.Leads{ID: 5, Title: "Hot"}}
+	h := &LeadHandler{Service: s}
+
+	w := postLeadScore(h, "5", `{"score":85}`, authz.RoleManagement)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	var lead models.Leads
+	if err := json.Unmarshal(w.Body.Bytes(), &lead); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if lead.Score == nil || *lead.Score != 85 {
+		t.Fatalf("expected score 85 in response, got %+v", lead.Score)
+	}
+
+	for _, body := range []string{`{"score":101}`, `{"score":-5}`, `{}`, `{"score":"hot"}`} {
+		if w := postLeadScore(h, "5", body, authz.RoleManagement); w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d body=%s", body, w.Code, w.Body.String())
+		}
+	}
+	if w := postLeadScore(h, "9", `{"score":10}`, authz.RoleManagement); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for missing lead, got %d", w.Code)
+	}
+	if w := postLeadScore(h, "5", `{"score":10}`, authz.RoleControl); w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for read-only role, got %d", w.Code)
+	}
+	if s.calls != 1 {
+		t.Fatalf("expected exactly one stored score, got %d", s.calls)
+	}
+}
+
+func TestLeadList_SortByScore(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := &leadHandlerStubService{}
+	h := &LeadHandler{Service: s}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/leads?sort_by=score", nil)
+	c.Set("user_id", 100)
+	c.Set("role_id", authz.RoleManagement)
+	h.List(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	if s.listFilter.SortBy != "score" {
+		t.Fatalf("expected sort_by=score to reach the service, got %+v", s.listFilter)
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -25,47 +26,53 @@ type leadHandlerStubService struct {
 	deleteErr     error
 }
 
-func (s *leadHandlerStubService) Create(lead *models.Leads, userID, roleID int) (int64, error) {
+func (s *leadHandlerStubService) Create(_ context.Context, lead *models.Leads, userID, roleID int) (int64, error) {
 	return 1, nil
 }
-func (s *leadHandlerStubService) Update(lead *models.Leads, userID, roleID int) error { return nil }
-func (s *leadHandlerStubService) GetByID(id int, userID, roleID int) (*models.Leads, error) {
+func (s *leadHandlerStubService) Update(_ context.Context, lead *models.Leads, userID, roleID int) error {
+	return nil
+}
+func (s *leadHandlerStubService) GetByID(_ context.Context, id int, userID, roleID int) (*models.Leads, error) {
 	return &models.Leads{ID: id, OwnerID: userID}, nil
 }
-func (s *leadHandlerStubService) GetByIDWithArchiveScope(id int, userID, roleID int, scope repositories.ArchiveScope) (*models.Leads, error) {
+func (s *leadHandlerStubService) GetByIDWithArchiveScope(_ context.Context, id int, userID, roleID int, scope repositories.ArchiveScope) (*models.Leads, error) {
 	return &models.Leads{ID: id, OwnerID: userID, IsArchived: scope == repositories.ArchiveScopeArchivedOnly}, nil
 }
-func (s *leadHandlerStubService) Delete(id int, userID, roleID int) error {
+func (s *leadHandlerStubService) Delete(_ context.Context, id int, userID, roleID int) error {
 	s.deleteCalled = true
 	return s.deleteErr
 }
-func (s *leadHandlerStubService) ListForRole(userID, roleID, limit, offset int, scope repositories.ArchiveScope, filter repositories.LeadListFilter) ([]*models.Leads, error) {
+func (s *leadHandlerStubService) ListForRole(_ context.Context, userID, roleID, limit, offset int, scope repositories.ArchiveScope, filter repositories.LeadListFilter) ([]*models.Leads, error) {
 	s.listScope = scope
 	s.listFilter = filter
 	return []*models.Leads{}, nil
 }
-func (s *leadHandlerStubService) ListMyWithArchiveScope(ownerID, limit, offset int, scope repositories.ArchiveScope) ([]*models.Leads, error) {
+func (s *leadHandlerStubService) ListMyWithArchiveScope(_ context.Context, ownerID, limit, offset int, scope repositories.ArchiveScope) ([]*models.Leads, error) {
 	s.listMyScope = scope
 	return []*models.Leads{}, nil
 }
-func (s *leadHandlerStubService) ListMyWithFilterAndArchiveScope(ownerID, limit, offset int, scope repositories.ArchiveScope, filter repositories.LeadListFilter) ([]*models.Leads, error) {
+func (s *leadHandlerStubService) ListMyWithFilterAndArchiveScope(_ context.Context, ownerID, limit, offset int, scope repositories.ArchiveScope, filter repositories.LeadListFilter) ([]*models.Leads, error) {
 	s.listMyScope = scope
 	s.listMyFilter = filter
 	return []*models.Leads{}, nil
 }
-func (s *leadHandlerStubService) AssignOwner(id, assigneeID, userID, roleID int) error { return nil }
-func (s *leadHandlerStubService) UpdateStatus(id int, to string, userID, roleID int) error {
+func (s *leadHandlerStubService) AssignOwner(_ context.Context, id, assigneeID, userID, roleID int) error {
 	return nil
 }
-func (s *leadHandlerStubService) ArchiveLead(id, userID, roleID int, reason string) error {
+func (s *leadHandlerStubService) UpdateStatus(_ context.Context, id int, to string, userID, roleID int) error {
+	return nil
+}
+func (s *leadHandlerStubService) ArchiveLead(_ context.Context, id, userID, roleID int, reason string) error {
 	s.archiveCalled = true
 	return s.archiveErr
 }
-func (s *leadHandlerStubService) UnarchiveLead(id, userID, roleID int) error { return nil }
-func (s *leadHandlerStubService) ConvertLeadToDeal(leadID int, amount float64, currency string, ownerID, userID, roleID int, clientID int, clientType string) (*models.Deals, error) {
+func (s *leadHandlerStubService) UnarchiveLead(_ context.Context, id, userID, roleID int) error {
+	return nil
+}
+func (s *leadHandlerStubService) ConvertLeadToDeal(_ context.Context, leadID int, amount float64, currency string, ownerID, userID, roleID int, clientID int, clientType string) (*models.Deals, error) {
 	return &models.Deals{ID: 1}, nil
 }
-func (s *leadHandlerStubService) ConvertLeadToDealWithClientData(leadID int, amount float64, currency string, ownerID, userID, roleID int, clientData *models.Client) (*models.Deals, error) {
+func (s *leadHandlerStubService) ConvertLeadToDealWithClientData(_ context.Context, leadID int, amount float64, currency string, ownerID, userID, roleID int, clientData *models.Client) (*models.Deals, error) {
 	return &models.Deals{ID: 1}, nil
 }
 
@@ -79,28 +86,32 @@ type dealHandlerStubService struct {
 	deleteErr    error
 }
 
-func (s *dealHandlerStubService) ListForRole(userID, roleID, limit, offset int, scope repositories.ArchiveScope, filter repositories.DealListFilter) ([]*models.Deals, error) {
+func (s *dealHandlerStubService) ListForRole(_ context.Context, userID, roleID, limit, offset int, scope repositories.ArchiveScope, filter repositories.DealListFilter) ([]*models.Deals, error) {
 	s.listScope = scope
 	s.listFilter = filter
 	return []*models.Deals{}, nil
 }
-func (s *dealHandlerStubService) ListMyWithFilterAndArchiveScope(ownerID, limit, offset int, scope repositories.ArchiveScope, filter repositories.DealListFilter) ([]*models.Deals, error) {
+func (s *dealHandlerStubService) ListMyWithFilterAndArchiveScope(_ context.Context, ownerID, limit, offset int, scope repositories.ArchiveScope, filter repositories.DealListFilter) ([]*models.Deals, error) {
 	s.listMyScope = scope
 	s.listMyFilter = filter
 	return []*models.Deals{}, nil
 }
-func (s *dealHandlerStubService) Delete(id, userID, roleID int) error { return s.deleteErr }
-func (s *dealHandlerStubService) GetByID(id int, userID, roleID int) (*models.Deals, error) {
+func (s *dealHandlerStubService) Delete(_ context.Context, id, userID, roleID int) error {
+	return s.deleteErr
+}
+func (s *dealHandlerStubService) GetByID(_ context.Context, id int, userID, roleID int) (*models.Deals, error) {
 	return &models.Deals{ID: id, OwnerID: userID}, nil
 }
-func (s *dealHandlerStubService) GetByIDWithArchiveScope(id int, userID, roleID int, scope repositories.ArchiveScope) (*models.Deals, error) {
+func (s *dealHandlerStubService) GetByIDWithArchiveScope(_ context.Context, id int, userID, roleID int, scope repositories.ArchiveScope) (*models.Deals, error) {
 	return &models.Deals{ID: id, OwnerID: userID, IsArchived: scope == repositories.ArchiveScopeArchivedOnly}, nil
 }
-func (s *dealHandlerStubService) ArchiveDeal(id, userID, roleID int, reason string) error {
+func (s *dealHandlerStubService) ArchiveDeal(_ context.Context, id, userID, roleID int, reason string) error {
 	s.archived = true
 	return nil
 }
-func (s *dealHandlerStubService) UnarchiveDeal(id, userID, roleID int) error { return nil }
+func (s *dealHandlerStubService) UnarchiveDeal(_ context.Context, id, userID, roleID int) error {
+	return nil
+}
 
 func ctx(method, path, body string, role int) (*gin.Context, *httptest.ResponseRecorder) {
 	w := httptest.NewRecorder()
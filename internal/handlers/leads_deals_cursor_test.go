@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+	"turcompany/internal/repositories"
+)
+
+// keysetDealService keeps deals in memory and pages them like the
+// repository does: newest first by (created_at, id), rows after filter.After.
+type keysetDealService struct {
+	stubDealPaginationService
+	deals []*models.Deals
+}
+
+func (s *keysetDealService) ListForRole(_ context.Context, _, _, limit, offset int, _ repositories.ArchiveScope, filter repositories.DealListFilter) ([]*models.Deals, error) {
+	rows := append([]*models.Deals(nil), s.deals...)
+	sort.Slice(rows, func(i, j int) bool {
+		if !rows[i].CreatedAt.Equal(rows[j].CreatedAt) {
+			return rows[i].CreatedAt.After(rows[j].CreatedAt)
+		}
+		return rows[i].ID > rows[j].ID
+	})
+	var out []*models.Deals
+	for _, d := range rows {
+		if a := filter.After; a != nil && !(d.CreatedAt.Before(a.CreatedAt) || d.CreatedAt.Equal(a.CreatedAt) && d.ID < a.ID) {
+			continue
+		}
+		out = append(out, d)
+	}
+	if offset > len(out) {
+		offset = len(out)
+	}
+	out = out[offset:]
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func (s *keysetDealService) insert(id int, at time.Time) {
+	s.deals = append(s.deals, &models.Deals{ID: id, CreatedAt: at})
+}
+
+func getDealCursorPage(t *testing.T, r *gin.Engine, cursor string) models.CursorPage[*models.Deals] {
+	t.Helper()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/deals?size=3&cursor="+url.QueryEscape(cursor), nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	var page models.CursorPage[*models.Deals]
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatal(err)
+	}
+	return page
+}
+
+func TestDealHandler_List_CursorWalkSeesEveryRowOnce(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	base := time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)
+	svc := &keysetDealService{}
+	for i := 1; i <= 10; i++ {
+		// пары строк с одинаковым created_at проверяют разрешение по id
+		svc.insert(i, base.Add(time.Duration((i+1)/2)*time.Minute))
+	}
+	h := &DealHandler{Service: svc}
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", 1)
+		c.Set("role_id", authz.RoleSystemAdmin)
+		c.Next()
+	})
+	r.GET("/deals", h.List)
+
+	seen := map[int]int{}
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 10 {
+			t.Fatal("cursor walk does not terminate")
+		}
+		page := getDealCursorPage(t, r, cursor)
+		for _, d := range page.Items {
+			seen[d.ID]++
+		}
+		if pages == 1 {
+			// новые сделки появляются посреди обхода
+			svc.insert(100, base.Add(time.Hour))
+			svc.insert(101, base.Add(2*time.Hour))
+		}
+		if !page.HasNext {
+			if page.NextCursor != "" {
+				t.Fatalf("last page must not carry a cursor, got %q", page.NextCursor)
+			}
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	for id := 1; id <= 10; id++ {
+		if seen[id] != 1 {
+			t.Fatalf("deal %d seen %d times, want exactly once (seen=%v)", id, seen[id], seen)
+		}
+	}
+	if len(seen) != 10 {
+		t.Fatalf("rows inserted mid-walk ahead of the cursor must not appear, seen=%v", seen)
+	}
+}
+
+func TestDealHandler_List_CursorValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := &DealHandler{Service: &keysetDealService{}}
+	for _, target := range []string{"/deals?cursor=bm90LWEtY3Vyc29y", "/deals?cursor=&sort_by=amount"} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+		c.Set("user_id", 1)
+		c.Set("role_id", authz.RoleSystemAdmin)
+		h.List(c)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d body=%s", target, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestLeadHandler_List_CursorEnvelope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := &LeadHandler{Service: &stubLeadPaginationService{}}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/leads?cursor=", nil)
+	c.Set("user_id", 1)
+	c.Set("role_id", authz.RoleSystemAdmin)
+	h.List(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d body=%s", w.Code, w.Body.String())
+	}
+	if got := w.Body.String(); got != `{"items":[],"has_next":false}` {
+		t.Fatalf("unexpected cursor envelope %s", got)
+	}
+}
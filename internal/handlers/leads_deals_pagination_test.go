@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -8,44 +9,55 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"turcompany/internal/authz"
 	"turcompany/internal/models"
 	"turcompany/internal/repositories"
 )
 
 type stubLeadPaginationService struct{}
 
-func (s *stubLeadPaginationService) Create(*models.Leads, int, int) (int64, error) { return 0, nil }
-func (s *stubLeadPaginationService) Update(*models.Leads, int, int) error          { return nil }
-func (s *stubLeadPaginationService) GetByID(int, int, int) (*models.Leads, error)  { return nil, nil }
-func (s *stubLeadPaginationService) GetByIDWithArchiveScope(int, int, int, repositories.ArchiveScope) (*models.Leads, error) {
+func (s *stubLeadPaginationService) Create(context.Context, *models.Leads, int, int) (int64, error) {
+	return 0, nil
+}
+func (s *stubLeadPaginationService) Update(context.Context, *models.Leads, int, int) error {
+	return nil
+}
+func (s *stubLeadPaginationService) GetByID(context.Context, int, int, int) (*models.Leads, error) {
+	return nil, nil
+}
+func (s *stubLeadPaginationService) GetByIDWithArchiveScope(context.Context, int, int, int, repositories.ArchiveScope) (*models.Leads, error) {
 	return nil, nil
 }
-func (s *stubLeadPaginationService) Delete(int, int, int) error { return nil }
-func (s *stubLeadPaginationService) ListForRole(int, int, int, int, repositories.ArchiveScope, repositories.LeadListFilter) ([]*models.Leads, error) {
+func (s *stubLeadPaginationService) Delete(context.Context, int, int, int) error { return nil }
+func (s *stubLeadPaginationService) ListForRole(context.Context, int, int, int, int, repositories.ArchiveScope, repositories.LeadListFilter) ([]*models.Leads, error) {
 	return []*models.Leads{}, nil
 }
-func (s *stubLeadPaginationService) ListMyWithArchiveScope(int, int, int, repositories.ArchiveScope) ([]*models.Leads, error) {
+func (s *stubLeadPaginationService) ListMyWithArchiveScope(context.Context, int, int, int, repositories.ArchiveScope) ([]*models.Leads, error) {
 	return []*models.Leads{}, nil
 }
-func (s *stubLeadPaginationService) ListMyWithFilterAndArchiveScope(int, int, int, repositories.ArchiveScope, repositories.LeadListFilter) ([]*models.Leads, error) {
+func (s *stubLeadPaginationService) ListMyWithFilterAndArchiveScope(context.Context, int, int, int, repositories.ArchiveScope, repositories.LeadListFilter) ([]*models.Leads, error) {
 	return []*models.Leads{}, nil
 }
-func (s *stubLeadPaginationService) AssignOwner(int, int, int, int) error { return nil }
-func (s *stubLeadPaginationService) UpdateStatus(int, string, int, int) error {
+func (s *stubLeadPaginationService) AssignOwner(context.Context, int, int, int, int) error {
+	return nil
+}
+func (s *stubLeadPaginationService) UpdateStatus(context.Context, int, string, int, int) error {
 	return nil
 }
-func (s *stubLeadPaginationService) ArchiveLead(int, int, int, string) error { return nil }
-func (s *stubLeadPaginationService) UnarchiveLead(int, int, int) error       { return nil }
-func (s *stubLeadPaginationService) ConvertLeadToDeal(int, float64, string, int, int, int, int, string) (*models.Deals, error) {
+func (s *stubLeadPaginationService) ArchiveLead(context.Context, int, int, int, string) error {
+	return nil
+}
+func (s *stubLeadPaginationService) UnarchiveLead(context.Context, int, int, int) error { return nil }
+func (s *stubLeadPaginationService) ConvertLeadToDeal(context.Context, int, float64, string, int, int, int, int, string) (*models.Deals, error) {
 	return nil, nil
 }
-func (s *stubLeadPaginationService) ConvertLeadToDealWithClientData(int, float64, string, int, int, int, *models.Client) (*models.Deals, error) {
+func (s *stubLeadPaginationService) ConvertLeadToDealWithClientData(context.Context, int, float64, string, int, int, int, *models.Client) (*models.Deals, error) {
 	return nil, nil
 }
-func (s *stubLeadPaginationService) ListForRoleWithTotal(int, int, int, int, repositories.ArchiveScope, repositories.LeadListFilter) ([]*models.Leads, int, error) {
+func (s *stubLeadPaginationService) ListForRoleWithTotal(context.Context, int, int, int, int, repositories.ArchiveScope, repositories.LeadListFilter) ([]*models.Leads, int, error) {
 	return []*models.Leads{}, 20, nil
 }
-func (s *stubLeadPaginationService) ListMyWithFilterAndArchiveScopeAndTotal(int, int, int, repositories.ArchiveScope, repositories.LeadListFilter) ([]*models.Leads, int, error) {
+func (s *stubLeadPaginationService) ListMyWithFilterAndArchiveScopeAndTotal(context.Context, int, int, int, repositories.ArchiveScope, repositories.LeadListFilter) ([]*models.Leads, int, error) {
 	return []*models.Leads{}, 20, nil
 }
 
@@ -66,34 +78,48 @@ func TestLeadHandler_List_PaginatedEnvelope(t *testing.T) {
 	}
 }
 
-type stubDealPaginationService struct{}
+type stubDealPaginationService struct {
+	listFilter repositories.DealListFilter
+	listRole   int
+}
 
-func (s *stubDealPaginationService) Create(*models.Deals, int, int) (int64, error) { return 0, nil }
-func (s *stubDealPaginationService) Update(*models.Deals, int, int) error          { return nil }
-func (s *stubDealPaginationService) GetByID(int, int, int) (*models.Deals, error)  { return nil, nil }
-func (s *stubDealPaginationService) Delete(int, int, int) error                    { return nil }
-func (s *stubDealPaginationService) ListForRole(int, int, int, int, repositories.ArchiveScope, repositories.DealListFilter) ([]*models.Deals, error) {
+func (s *stubDealPaginationService) Create(context.Context, *models.Deals, int, int) (int64, error) {
+	return 0, nil
+}
+func (s *stubDealPaginationService) Update(context.Context, *models.Deals, int, int) error {
+	return nil
+}
+func (s *stubDealPaginationService) GetByID(context.Context, int, int, int) (*models.Deals, error) {
+	return nil, nil
+}
+func (s *stubDealPaginationService) Delete(context.Context, int, int, int) error { return nil }
+func (s *stubDealPaginationService) ListForRole(_ context.Context, _, roleID, _, _ int, _ repositories.ArchiveScope, filter repositories.DealListFilter) ([]*models.Deals, error) {
+	s.listRole, s.listFilter = roleID, filter
 	return []*models.Deals{}, nil
 }
-func (s *stubDealPaginationService) ListMyWithFilterAndArchiveScope(int, int, int, repositories.ArchiveScope, repositories.DealListFilter) ([]*models.Deals, error) {
+func (s *stubDealPaginationService) ListMyWithFilterAndArchiveScope(context.Context, int, int, int, repositories.ArchiveScope, repositories.DealListFilter) ([]*models.Deals, error) {
 	return []*models.Deals{}, nil
 }
-func (s *stubDealPaginationService) UpdateStatus(int, string, int, int) error { return nil }
-func (s *stubDealPaginationService) ArchiveDeal(int, int, int, string) error  { return nil }
-func (s *stubDealPaginationService) UnarchiveDeal(int, int, int) error        { return nil }
-func (s *stubDealPaginationService) GetByIDWithArchiveScope(int, int, int, repositories.ArchiveScope) (*models.Deals, error) {
+func (s *stubDealPaginationService) UpdateStatus(context.Context, int, string, int, int) error {
+	return nil
+}
+func (s *stubDealPaginationService) ArchiveDeal(context.Context, int, int, int, string) error {
+	return nil
+}
+func (s *stubDealPaginationService) UnarchiveDeal(context.Context, int, int, int) error { return nil }
+func (s *stubDealPaginationService) GetByIDWithArchiveScope(context.Context, int, int, int, repositories.ArchiveScope) (*models.Deals, error) {
 	return nil, nil
 }
-func (s *stubDealPaginationService) MoveStage(dealID, stageID int, comment string, userID, roleID int) error {
+func (s *stubDealPaginationService) MoveStage(_ context.Context, dealID, stageID int, comment string, userID, roleID int) error {
 	return nil
 }
-func (s *stubDealPaginationService) GetHistory(dealID, userID, roleID int) ([]*models.DealStageHistory, error) {
+func (s *stubDealPaginationService) GetHistory(_ context.Context, dealID, userID, roleID int) ([]*models.DealStageHistory, error) {
 	return nil, nil
 }
-func (s *stubDealPaginationService) ListForRoleWithTotal(int, int, int, int, repositories.ArchiveScope, repositories.DealListFilter) ([]*models.Deals, int, error) {
+func (s *stubDealPaginationService) ListForRoleWithTotal(context.Context, int, int, int, int, repositories.ArchiveScope, repositories.DealListFilter) ([]*models.Deals, int, error) {
 	return []*models.Deals{}, 11, nil
 }
-func (s *stubDealPaginationService) ListMyWithFilterAndArchiveScopeAndTotal(int, int, int, repositories.ArchiveScope, repositories.DealListFilter) ([]*models.Deals, int, error) {
+func (s *stubDealPaginationService) ListMyWithFilterAndArchiveScopeAndTotal(context.Context, int, int, int, repositories.ArchiveScope, repositories.DealListFilter) ([]*models.Deals, int, error) {
 	return []*models.Deals{}, 11, nil
 }
 
@@ -113,3 +139,21 @@ func TestDealHandler_List_LegacyArray(t *testing.T) {
 		t.Fatalf("expected legacy array response, got %s", w.Body.String())
 	}
 }
+
+func TestDealHandler_List_SalesCanFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := &stubDealPaginationService{}
+	h := &DealHandler{Service: svc}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/deals?status=won&amount_min=1000", nil)
+	c.Set("user_id", 10)
+	c.Set("role_id", authz.RoleSales)
+	h.List(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d body=%s", w.Code, w.Body.String())
+	}
+	if svc.listRole != authz.RoleSales || svc.listFilter.Status != "won" || svc.listFilter.AmountMin == nil || *svc.listFilter.AmountMin != 1000 {
+		t.Fatalf("filter must reach the service for sales, got role=%d filter=%+v", svc.listRole, svc.listFilter)
+	}
+}
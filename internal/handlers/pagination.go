@@ -2,12 +2,14 @@ package handlers
 
 import (
 	"math"
+	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 
 	"turcompany/internal/models"
+	"turcompany/internal/repositories"
 )
 
 const (
@@ -36,6 +38,34 @@ func normalizedPageAndSize(c *gin.Context) (int, int) {
 	return page, size
 }
 
+// limitOffsetFromQuery reads the optional ?limit=&offset= window used by
+// endpoints that return a plain array. bounded is false when neither is set;
+// ok is false for non-numeric or out-of-range values. limit defaults to and
+// is capped at paginationMaxSize.
+func limitOffsetFromQuery(c *gin.Context) (limit, offset int, bounded, ok bool) {
+	rawLimit := strings.TrimSpace(c.Query("limit"))
+	rawOffset := strings.TrimSpace(c.Query("offset"))
+	if rawLimit == "" && rawOffset == "" {
+		return 0, 0, false, true
+	}
+	limit = paginationMaxSize
+	if rawLimit != "" {
+		v, err := strconv.Atoi(rawLimit)
+		if err != nil || v < paginationMinSize {
+			return 0, 0, false, false
+		}
+		limit = min(v, paginationMaxSize)
+	}
+	if rawOffset != "" {
+		v, err := strconv.Atoi(rawOffset)
+		if err != nil || v < 0 {
+			return 0, 0, false, false
+		}
+		offset = v
+	}
+	return limit, offset, true, true
+}
+
 func offsetFromPage(page, size int) int {
 	return (page - 1) * size
 }
@@ -54,3 +84,39 @@ func buildPaginationMeta(page, size, total int) models.PaginationMeta {
 		HasPrev:    page > 1,
 	}
 }
+
+// writePaginated sends the shared {items, pagination} envelope used by every
+// list endpoint in ?paginate=true mode.
+func writePaginated[T any](c *gin.Context, items []T, page, size, total int) {
+	if items == nil {
+		items = []T{}
+	}
+	c.JSON(http.StatusOK, models.PaginatedResponse[T]{Items: items, Pagination: buildPaginationMeta(page, size, total)})
+}
+
+// cursorFromQuery reports whether the request uses keyset paging (?cursor=,
+// empty on the first page) and decodes the cursor.
+func cursorFromQuery(c *gin.Context) (*repositories.ListCursor, bool, error) {
+	raw, ok := c.GetQuery("cursor")
+	if !ok {
+		return nil, false, nil
+	}
+	cursor, err := repositories.DecodeListCursor(raw)
+	return cursor, true, err
+}
+
+// writeCursorPage sends the {items, next_cursor} envelope of ?cursor= mode.
+// items is fetched with size+1 rows: the extra row only signals that another
+// page exists and is not returned.
+func writeCursorPage[T any](c *gin.Context, items []T, size int, position func(T) repositories.ListCursor) {
+	page := models.CursorPage[T]{Items: items}
+	if len(items) > size {
+		page.Items = items[:size]
+		page.HasNext = true
+		page.NextCursor = position(page.Items[size-1]).Encode()
+	}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	c.JSON(http.StatusOK, page)
+}
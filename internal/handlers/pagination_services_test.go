@@ -0,0 +1,12 @@
+package handlers
+
+import "turcompany/internal/services"
+
+// Handlers reach the paginated lists through optional interfaces, so a
+// signature drift in the services only shows up as a runtime 500. Keep the
+// real services pinned to them at compile time.
+var (
+	_ dealPaginationService   = (*services.DealService)(nil)
+	_ leadPaginationService   = (*services.LeadService)(nil)
+	_ clientPaginationService = (*services.ClientService)(nil)
+)
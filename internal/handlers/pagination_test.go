@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -24,3 +25,18 @@ func TestBuildPaginationMeta(t *testing.T) {
 		t.Fatalf("unexpected meta: %+v", meta)
 	}
 }
+
+func TestWritePaginated_NilItemsEncodeAsEmptyList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	var items []int
+	writePaginated(c, items, 1, 15, 0)
+	if !strings.Contains(w.Body.String(), `"items":[]`) {
+		t.Fatalf("expected empty items list, got %s", w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"total":0`) {
+		t.Fatalf("expected total in envelope, got %s", w.Body.String())
+	}
+}
@@ -108,6 +108,68 @@ func (h *ReportHandler) GetLeadsSummary(c *gin.Context) {
 	c.JSON(http.StatusOK, report)
 }
 
+// GetLeadsBySource returns lead counts and conversion rates per lead source.
+func (h *ReportHandler) GetLeadsBySource(c *gin.Context) {
+	from, ok := parseDateParam(c, "from")
+	if !ok {
+		return
+	}
+
+	to, ok := parseDateParam(c, "to")
+	if !ok {
+		return
+	}
+
+	userID, roleID := getUserAndRole(c)
+	requestedBranchID, ok := parseOptionalBranchID(c)
+	if !ok {
+		return
+	}
+	report, err := h.Service.GetLeadsBySource(c.Request.Context(), from, to, userID, roleID, requestedBranchID)
+	if err != nil {
+		if errors.Is(err, services.ErrForbidden) {
+			forbidden(c, "forbidden")
+			return
+		}
+		internalError(c, "failed to build leads by source report")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetSummary returns the dashboard KPIs. Results are cached briefly per filter
+// set; pass ?fresh=true to force a recompute.
+func (h *ReportHandler) GetSummary(c *gin.Context) {
+	from, ok := parseDateParam(c, "from")
+	if !ok {
+		return
+	}
+
+	to, ok := parseDateParam(c, "to")
+	if !ok {
+		return
+	}
+
+	userID, roleID := getUserAndRole(c)
+	requestedBranchID, ok := parseOptionalBranchID(c)
+	if !ok {
+		return
+	}
+	fresh := strings.EqualFold(strings.TrimSpace(c.Query("fresh")), "true")
+	report, err := h.Service.GetSummary(c.Request.Context(), from, to, userID, roleID, requestedBranchID, fresh)
+	if err != nil {
+		if errors.Is(err, services.ErrForbidden) {
+			forbidden(c, "forbidden")
+			return
+		}
+		internalError(c, "failed to build summary report")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
 func (h *ReportHandler) GetRevenue(c *gin.Context) {
 	from, ok := parseDateParam(c, "from")
 	if !ok {
@@ -132,6 +194,15 @@ func (h *ReportHandler) GetRevenue(c *gin.Context) {
 	if !ok {
 		return
 	}
+	switch groupBy := strings.TrimSpace(c.Query("group_by")); groupBy {
+	case "":
+	case "owner":
+		h.getRevenueByOwner(c, from, to, userID, roleID, requestedBranchID)
+		return
+	default:
+		badRequest(c, "invalid group_by value")
+		return
+	}
 	report, err := h.Service.GetRevenueStats(c.Request.Context(), from, to, userID, roleID, period, requestedBranchID)
 	if err != nil {
 		if errors.Is(err, services.ErrForbidden) {
@@ -145,6 +216,20 @@ func (h *ReportHandler) GetRevenue(c *gin.Context) {
 	c.JSON(http.StatusOK, report)
 }
 
+func (h *ReportHandler) getRevenueByOwner(c *gin.Context, from, to time.Time, userID, roleID int, requestedBranchID *int) {
+	report, err := h.Service.GetRevenueByOwner(c.Request.Context(), from, to, userID, roleID, requestedBranchID)
+	if err != nil {
+		if errors.Is(err, services.ErrForbidden) {
+			forbidden(c, "forbidden")
+			return
+		}
+		internalError(c, "failed to build revenue by owner report")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
 func (h *ReportHandler) ExportRevenue(c *gin.Context) {
 	from, ok := parseDateParam(c, "from")
 	if !ok {
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
@@ -117,6 +118,12 @@ func (h *RoleHandler) DeleteRole(c *gin.Context) {
 			notFound(c, ValidationFailed, "Role not found")
 			return
 		}
+		var inUse *services.RoleInUseError
+		if errors.As(err, &inUse) {
+			writeErrorWithDetails(c, http.StatusConflict, ConflictCode,
+				fmt.Sprintf("Role is assigned to %d user(s)", inUse.Users), gin.H{"users": inUse.Users})
+			return
+		}
 		if errors.Is(err, services.ErrRoleInUse) {
 			conflict(c, ConflictCode, "Role is in use")
 			return
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+	"turcompany/internal/services"
+)
+
+// taskAttachmentStore is the part of services.TaskAttachmentService used by
+// the task attachment routes.
+type taskAttachmentStore interface {
+	Upload(ctx context.Context, taskID int64, userID int, file *multipart.FileHeader) (*models.TaskAttachment, error)
+	List(ctx context.Context, taskID int64) ([]models.TaskAttachment, error)
+	Open(ctx context.Context, taskID, attachmentID int64) (*models.TaskAttachment, io.ReadSeekCloser, error)
+}
+
+// SetAttachmentService enables /tasks/:id/attachments.
+func (h *TaskHandler) SetAttachmentService(s taskAttachmentStore) {
+	h.attachments = s
+}
+
+// POST /tasks/:id/attachments (multipart, поле file)
+func (h *TaskHandler) UploadAttachment(c *gin.Context) {
+	userID, roleID := getUserAndRole(c)
+	task, ok := h.attachmentTask(c, "upload")
+	if !ok {
+		return
+	}
+	if authz.IsReadOnly(roleID) {
+		log.Printf("[task][attach][upload][deny] read-only role=%d", roleID)
+		forbidden(c, "Read-only role")
+		return
+	}
+
+	// небольшой запас сверху на multipart-заголовки
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, services.MaxTaskAttachmentSize+1<<20)
+	file, err := c.FormFile("file")
+	if err != nil {
+		badRequest(c, "File is required")
+		return
+	}
+
+	att, err := h.attachments.Upload(c.Request.Context(), task.ID, userID, file)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrTaskAttachmentTooLarge),
+			errors.Is(err, services.ErrTaskAttachmentTypeNotAllowed),
+			errors.Is(err, services.ErrInvalidFilename),
+			errors.Is(err, services.ErrFileRequired):
+			badRequest(c, err.Error())
+		default:
+			log.Printf("[task][attach][upload][err] task=%d: %v", task.ID, err)
+			internalError(c, "Failed to upload attachment")
+		}
+		return
+	}
+	log.Printf("[task][attach][upload][ok] task=%d attachment=%d size=%d", task.ID, att.ID, att.SizeBytes)
+	c.JSON(http.StatusCreated, att)
+}
+
+// GET /tasks/:id/attachments
+func (h *TaskHandler) ListAttachments(c *gin.Context) {
+	task, ok := h.attachmentTask(c, "list")
+	if !ok {
+		return
+	}
+	items, err := h.attachments.List(c.Request.Context(), task.ID)
+	if err != nil {
+		log.Printf("[task][attach][list][err] task=%d: %v", task.ID, err)
+		internalError(c, "Failed to list attachments")
+		return
+	}
+	if items == nil {
+		items = []models.TaskAttachment{}
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// GET /tasks/:id/attachments/:attachment_id/download
+func (h *TaskHandler) DownloadAttachment(c *gin.Context) {
+	attachmentID, err := strconv.ParseInt(c.Param("attachment_id"), 10, 64)
+	if err != nil || attachmentID <= 0 {
+		badRequest(c, "Invalid attachment id")
+		return
+	}
+	task, ok := h.attachmentTask(c, "download")
+	if !ok {
+		return
+	}
+
+	att, reader, err := h.attachments.Open(c.Request.Context(), task.ID, attachmentID)
+	if err != nil {
+		if errors.Is(err, services.ErrFileNotFound) {
+			notFound(c, NotFoundCode, "Attachment not found")
+			return
+		}
+		log.Printf("[task][attach][download][err] task=%d attachment=%d: %v", task.ID, attachmentID, err)
+		internalError(c, "Failed to download attachment")
+		return
+	}
+	defer reader.Close()
+	c.Header("Content-Type", att.MimeType)
+	c.Header("Content-Disposition", "attachment; filename=\""+att.FileName+"\"")
+	http.ServeContent(c.Writer, c.Request, att.FileName, att.CreatedAt, reader)
+}
+
+// attachmentTask loads the task from :id and applies the same checks as
+// GET /tasks/:id. It writes the error response itself when ok is false.
+func (h *TaskHandler) attachmentTask(c *gin.Context, op string) (*models.Task, bool) {
+	userID, roleID := getUserAndRole(c)
+	if h.attachments == nil {
+		notFound(c, NotFoundCode, "Attachments are not enabled")
+		return nil, false
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		badRequest(c, "Invalid id")
+		return nil, false
+	}
+	task, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		log.Printf("[task][attach][%s][err] get task id=%d: %v", op, id, err)
+		internalError(c, "Failed to get task")
+		return nil, false
+	}
+	if task == nil {
+		notFound(c, TaskNotFoundCode, "Task not found")
+		return nil, false
+	}
+	if !canViewTask(roleID, int64(userID), task) || !h.hasTaskBranchAccess(roleID, int64(userID), task) {
+		log.Printf("[task][attach][%s][deny] uid=%d role=%d task=%d", op, userID, roleID, id)
+		forbidden(c, "Forbidden")
+		return nil, false
+	}
+	return task, true
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+	"turcompany/internal/services"
+	"turcompany/internal/storage"
+)
+
+type taskAttachmentRepoStub struct {
+	mu    sync.Mutex
+	items []models.TaskAttachment
+}
+
+func (r *taskAttachmentRepoStub) Create(_ context.Context, att *models.TaskAttachment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	att.ID = int64(len(r.items) + 1)
+	att.CreatedAt = time.Now()
+	r.items = append(r.items, *att)
+	return nil
+}
+
+func (r *taskAttachmentRepoStub) ListByTask(_ context.Context, taskID int64) ([]models.TaskAttachment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []models.TaskAttachment
+	for _, a := range r.items {
+		if a.TaskID == taskID {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+func (r *taskAttachmentRepoStub) GetByID(_ context.Context, id int64) (*models.TaskAttachment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, a := range r.items {
+		if a.ID == id {
+			cp := a
+			return &cp, nil
+		}
+	}
+	return nil, nil
+}
+
+func newAttachmentTestHandler(t *testing.T) (*TaskHandler, *taskAttachmentRepoStub) {
+	t.Helper()
+	branch := int64(1)
+	svc := &taskBranchServiceStub{task: &models.Task{ID: 7, CreatorID: 10, AssigneeID: 10, BranchID: &branch}}
+	users := &taskBranchUserRepoStub{users: map[int]*models.User{
+		10: {ID: 10, BranchID: ptrInt(1)},
+		11: {ID: 11, BranchID: ptrInt(1)},
+	}}
+	repo := &taskAttachmentRepoStub{}
+	h := NewTaskHandler(svc, nil, users)
+	h.SetAttachmentService(services.NewTaskAttachmentService(repo, storage.NewLocalStorage(t.TempDir())))
+	return h, repo
+}
+
+func attachmentRequest(t *testing.T, h *TaskHandler, userID, roleID int, fileName string, content []byte) *httptest.ResponseRecorder {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile("file", fileName)
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	_, _ = fw.Write(content)
+	_ = mw.Close()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/tasks/7/attachments", &body)
+	c.Request.Header.Set("Content-Type", mw.FormDataContentType())
+	c.Params = gin.Params{{Key: "id", Value: "7"}}
+	c.Set("user_id", userID)
+	c.Set("role_id", roleID)
+	h.UploadAttachment(c)
+	return w
+}
+
+func listAttachments(h *TaskHandler, userID, roleID int) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/tasks/7/attachments", nil)
+	c.Params = gin.Params{{Key: "id", Value: "7"}}
+	c.Set("user_id", userID)
+	c.Set("role_id", roleID)
+	h.ListAttachments(c)
+	return w
+}
+
+func TestTaskHandler_Attachments_UploadListDownload(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h, _ := newAttachmentTestHandler(t)
+	content := []byte("%PDF-1.4 spec")
+
+	w := attachmentRequest(t, h, 10, authz.RoleSales, "../Spec v1.pdf", content)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("upload: expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+	var att models.TaskAttachment
+	if err := json.Unmarshal(w.Body.Bytes(), &att); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if att.FileName != "Spec_v1.pdf" || att.MimeType != "application/pdf" || att.SizeBytes != int64(len(content)) {
+		t.Fatalf("unexpected attachment: %+v", att)
+	}
+
+	w = listAttachments(h, 10, authz.RoleSales)
+	var items []models.TaskAttachment
+	if err := json.Unmarshal(w.Body.Bytes(), &items); err != nil || len(items) != 1 || items[0].ID != att.ID {
+		t.Fatalf("list: code=%d body=%s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/tasks/7/attachments/1/download", nil)
+	c.Params = gin.Params{{Key: "id", Value: "7"}, {Key: "attachment_id", Value: "1"}}
+	c.Set("user_id", 10)
+	c.Set("role_id", authz.RoleSales)
+	h.DownloadAttachment(c)
+	if w.Code != http.StatusOK || !bytes.Equal(w.Body.Bytes(), content) {
+		t.Fatalf("download: code=%d body=%q", w.Code, w.Body.String())
+	}
+}
+
+func TestTaskHandler_Attachments_RejectsDisallowedType(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h, repo := newAttachmentTestHandler(t)
+
+	w := attachmentRequest(t, h, 10, authz.RoleSales, "run.exe", []byte("MZ"))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d body=%s", w.Code, w.Body.String())
+	}
+	if len(repo.items) != 0 {
+		t.Fatalf("nothing must be stored, got %d", len(repo.items))
+	}
+}
+
+func TestTaskHandler_Attachments_ForbiddenForForeignTask(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h, repo := newAttachmentTestHandler(t)
+
+	// user 11 (sales) is neither creator nor assignee of task 7
+	if w := attachmentRequest(t, h, 11, authz.RoleSales, "spec.pdf", []byte("x")); w.Code != http.StatusForbidden {
+		t.Fatalf("upload: expected 403, got %d", w.Code)
+	}
+	if w := listAttachments(h, 11, authz.RoleSales); w.Code != http.StatusForbidden {
+		t.Fatalf("list: expected 403, got %d", w.Code)
+	}
+	if len(repo.items) != 0 {
+		t.Fatalf("nothing must be stored, got %d", len(repo.items))
+	}
+}
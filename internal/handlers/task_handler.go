@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"log"
 	"net/http"
@@ -20,12 +21,119 @@ type TaskHandler struct {
 	service services.TaskService
 
 	// ↓↓↓ Телеграм-уведомления
-	tg    *services.TelegramService
+	tg    taskNotifier
 	users repositories.UserRepository
+	// dispatch запускает отправку уведомлений; по умолчанию — в отдельной
+	// горутине, чтобы ретраи Telegram и почта не держали запрос.
+	dispatch func(func())
+
+	// GET /tasks/:id?expand=entity — резолверы связанных сущностей (могут быть nil)
+	leads   taskLeadGetter
+	deals   taskDealGetter
+	clients taskClientGetter
+	// документы — отдельно (SetDocumentResolver), может быть nil
+	documents taskDocumentGetter
+
+	// Email-уведомления для тех, у кого нет Telegram (могут быть nil)
+	mail          taskMailer
+	emailSettings taskEmailSettings
+
+	// Исходящие вебхуки о событиях задач (может быть nil)
+	events taskEventPublisher
+
+	// Часовой пояс сервера для вычисления is_overdue (nil — локальное время)
+	loc *time.Location
+
+	// Вложения задач (может быть nil — маршруты отвечают 404)
+	attachments taskAttachmentStore
+}
+
+// taskLeadGetter / taskDealGetter / taskClientGetter are the scoped lookups
+// used to expand a task's linked entity; they enforce the viewer's access.
+type taskLeadGetter interface {
+	GetByID(ctx context.Context, id int, userID, roleID int) (*models.Leads, error)
+}
+
+type taskDealGetter interface {
+	GetByID(ctx context.Context, id int, userID, roleID int) (*models.Deals, error)
+}
+
+type taskClientGetter interface {
+	GetByID(ctx context.Context, id int, userID, roleID int) (*models.Client, error)
+}
+
+// taskDocumentGetter is the document counterpart (*services.DocumentService);
+// it applies document RBAC and hidden-document visibility.
+type taskDocumentGetter interface {
+	GetDocument(ctx context.Context, id int64, userID, roleID int) (*models.Document, error)
+}
+
+// taskNotifier is the Telegram side of task notifications
+// (*services.TelegramService).
+type taskNotifier interface {
+	SendMessage(chatID int64, text string) error
+	FormatTaskNotification(task *models.Task) string
+	FormatTaskDeletedNotification(task *models.Task) string
+	FormatTasksReassignedNotification(count int, fromName string) string
+}
+
+// taskMailer sends the email form of a task notification
+// (services.EmailService).
+type taskMailer interface {
+	SendTaskEmail(email string, task *models.Task, headline string) error
+}
+
+// taskEmailSettings reports a user's email and task email opt-in
+// (*repositories.UserNotificationRepository).
+type taskEmailSettings interface {
+	GetTaskEmailSettings(ctx context.Context, userID int64) (string, bool, error)
+}
+
+// taskEventPublisher delivers task lifecycle events (services.TaskEvent*) to
+// outbound webhook subscribers. Publish must not block the request.
+type taskEventPublisher interface {
+	Publish(event string, data any)
 }
 
 func NewTaskHandler(service services.TaskService, tg *services.TelegramService, users repositories.UserRepository) *TaskHandler {
-	return &TaskHandler{service: service, tg: tg, users: users}
+	h := &TaskHandler{service: service, users: users, dispatch: func(f func()) { go f() }}
+	// NewTelegramService returns nil without a bot token; keep h.tg a nil
+	// interface then, so the notify helpers skip it.
+	if tg != nil {
+		h.tg = tg
+	}
+	return h
+}
+
+// SetEntityResolvers enables ?expand=entity on GET /tasks/:id.
+func (h *TaskHandler) SetEntityResolvers(leads taskLeadGetter, deals taskDealGetter, clients taskClientGetter) {
+	h.leads = leads
+	h.deals = deals
+	h.clients = clients
+}
+
+// SetDocumentResolver enables document links: ?expand=entity on tasks with
+// entity_type "document", GET /documents/:id/tasks and the documents list of
+// GET /deals/:id/detail.
+func (h *TaskHandler) SetDocumentResolver(documents taskDocumentGetter) {
+	h.documents = documents
+}
+
+// SetEmailNotifier enables email task notifications for users who opted in
+// and get nothing over Telegram.
+func (h *TaskHandler) SetEmailNotifier(mail taskMailer, settings taskEmailSettings) {
+	h.mail = mail
+	h.emailSettings = settings
+}
+
+// SetTimezone sets the server timezone used to compute is_overdue.
+func (h *TaskHandler) SetTimezone(loc *time.Location) {
+	h.loc = loc
+}
+
+// SetEventPublisher enables outbound webhooks for task changes.
+func (h *TaskHandler) SetEventPublisher(p taskEventPublisher) {
+	h.events = p
 }
 
 // POST /tasks
@@ -37,9 +145,10 @@ func (h *TaskHandler) Create(c *gin.Context) {
 		EntityType  string              `json:"entity_type"`
 		Title       string              `json:"title" binding:"required"`
 		Description string              `json:"description"`
-		DueDate     string              `json:"due_date"`    // RFC3339
-		ReminderAt  string              `json:"reminder_at"` // RFC3339
-		Priority    models.TaskPriority `json:"priority"`    // low|normal|high|urgent
+		DueDate     string              `json:"due_date"`        // RFC3339
+		ReminderAt  string              `json:"reminder_at"`     // RFC3339
+		ReminderOff string              `json:"reminder_offset"` // Go duration before due_date, e.g. "24h"; reminder_at wins
+		Priority    models.TaskPriority `json:"priority"`        // low|normal|high|urgent
 	}
 
 	userID, roleID := getUserAndRole(c)
@@ -47,7 +156,7 @@ func (h *TaskHandler) Create(c *gin.Context) {
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		log.Printf("[task][create][bind][err] %v", err)
-		badRequest(c, "Invalid payload")
+		bindError(c, err, "Invalid payload")
 		return
 	}
 	log.Printf("[task][create] payload assignee_id=%d entity_type=%q entity_id=%d title=%q due=%q remind=%q priority=%q",
@@ -61,7 +170,7 @@ func (h *TaskHandler) Create(c *gin.Context) {
 	}
 	if authz.IsReadOnly(roleID) {
 		log.Printf("[task][create][deny] read-only role=%d", roleID)
-		forbidden(c, "Read-only role")
+		writeError(c, http.StatusForbidden, ReadOnlyRoleCode, "Read-only role")
 		return
 	}
 
@@ -71,7 +180,7 @@ func (h *TaskHandler) Create(c *gin.Context) {
 		assignees = []int64{uid}
 	}
 	for _, aid := range assignees {
-		if roleID == authz.RoleSales && aid != uid {
+		if aid != uid && !authz.HasCapability(roleID, authz.CapAssignTasksToOthers) {
 			log.Printf("[task][create][deny] staff=%d tried assign to %d", uid, aid)
 			forbidden(c, "Staff can assign only to self")
 			return
@@ -103,31 +212,54 @@ func (h *TaskHandler) Create(c *gin.Context) {
 		}
 		rem = &t
 	}
+	var remOffset *int64
+	if rem == nil && strings.TrimSpace(req.ReminderOff) != "" {
+		secs, ok := parseReminderOffset(req.ReminderOff)
+		if !ok {
+			log.Printf("[task][create][err] invalid reminder_offset=%q", req.ReminderOff)
+			badRequest(c, "Invalid reminder offset")
+			return
+		}
+		remOffset = &secs
+	}
 	if req.Priority == "" {
 		req.Priority = models.PriorityNormal
 	}
 
 	task := &models.Task{
-		CreatorID:   uid,
-		AssigneeID:  assignees[0],
-		AssigneeIDs: assignees,
-		EntityID:    req.EntityID,
-		EntityType:  req.EntityType,
-		Title:       req.Title,
-		Description: req.Description,
-		DueDate:     due,
-		ReminderAt:  rem,
-		Priority:    req.Priority,
+		CreatorID:      uid,
+		AssigneeID:     assignees[0],
+		AssigneeIDs:    assignees,
+		EntityID:       req.EntityID,
+		EntityType:     req.EntityType,
+		Title:          req.Title,
+		Description:    req.Description,
+		DueDate:        due,
+		ReminderAt:     rem,
+		Priority:       req.Priority,
+		ReminderOffset: remOffset,
 	}
 
 	createdTask, err := h.service.Create(c.Request.Context(), task)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidTaskEntityType) || errors.Is(err, services.ErrTaskEntityIDRequired) {
+			log.Printf("[task][create][err] invalid entity entity_type=%q entity_id=%d: %v", req.EntityType, req.EntityID, err)
+			badRequest(c, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrInvalidTaskAssignee) {
+			log.Printf("[task][create][err] invalid assignees=%v: %v", assignees, err)
+			badRequest(c, err.Error())
+			return
+		}
 		log.Printf("[task][create][err] %v", err)
 		internalError(c, "Failed to create task")
 		return
 	}
 	log.Printf("[task][create][ok] id=%d assignee_id=%d title=%q", createdTask.ID, createdTask.AssigneeID, createdTask.Title)
+	h.markOverdue(createdTask)
 	c.JSON(http.StatusCreated, createdTask)
+	h.publishEvent(services.TaskEventCreated, createdTask)
 
 	// === TG: уведомление исполнителю ===
 	h.notifyAssignee(c, createdTask, "📌 Новая задача")
@@ -153,7 +285,7 @@ func (h *TaskHandler) GetByID(c *gin.Context) {
 	}
 	if task == nil {
 		log.Printf("[task][getByID][404] id=%d", id)
-		notFound(c, ValidationFailed, "Task not found")
+		notFound(c, TaskNotFoundCode, "Task not found")
 		return
 	}
 	if !canViewTask(roleID, int64(userID), task) || !h.hasTaskBranchAccess(roleID, int64(userID), task) {
@@ -162,9 +294,46 @@ func (h *TaskHandler) GetByID(c *gin.Context) {
 		return
 	}
 	log.Printf("[task][getByID][ok] id=%d", id)
+	c.Header("ETag", taskETag(task))
+	h.markOverdue(task)
+	task.AllowedTransitions = h.allowedTaskTransitions(roleID, int64(userID), task)
+	if strings.EqualFold(strings.TrimSpace(c.Query("expand")), "entity") {
+		c.JSON(http.StatusOK, taskWithEntity{Task: task, Entity: h.resolveTaskEntity(c.Request.Context(), task, userID, roleID)})
+		return
+	}
 	c.JSON(http.StatusOK, task)
 }
 
+// GET /tasks/:id/transitions
+// Статусы, в которые вызывающий может перевести задачу сейчас.
+func (h *TaskHandler) Transitions(c *gin.Context) {
+	userID, roleID := getUserAndRole(c)
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		badRequest(c, "Invalid id")
+		return
+	}
+	task, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		log.Printf("[task][transitions][err] id=%d: %v", id, err)
+		internalError(c, "Failed to get task")
+		return
+	}
+	if task == nil {
+		notFound(c, TaskNotFoundCode, "Task not found")
+		return
+	}
+	uid := int64(userID)
+	if !canViewTask(roleID, uid, task) || !h.hasTaskBranchAccess(roleID, uid, task) {
+		forbidden(c, "Forbidden")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":              task.Status,
+		"allowed_transitions": h.allowedTaskTransitions(roleID, uid, task),
+	})
+}
+
 // GET /tasks
 func (h *TaskHandler) GetAll(c *gin.Context) {
 	userID, roleID := getUserAndRole(c)
@@ -181,49 +350,413 @@ func (h *TaskHandler) GetAll(c *gin.Context) {
 		badRequest(c, err.Error())
 		return
 	}
+	if err := applyMineFilter(c, &filter, userID); err != nil {
+		badRequest(c, err.Error())
+		return
+	}
+	if err := applyCreatorFilter(c, &filter, userID); err != nil {
+		badRequest(c, err.Error())
+		return
+	}
+
+	if !h.applyTaskListScope(&filter, userID, roleID) {
+		log.Printf("[task][list][deny] uid=%d role=%d has no branch", userID, roleID)
+		forbidden(c, "Forbidden")
+		return
+	}
+
+	if isPaginatedMode(c) {
+		page, size := normalizedPageAndSize(c)
+		offset := offsetFromPage(page, size)
+		items, total, err := h.service.GetAllPaginated(c.Request.Context(), filter, size, offset)
+		if err != nil {
+			log.Printf("[task][list][err] %v", err)
+			internalError(c, "Failed to retrieve tasks")
+			return
+		}
+		log.Printf("[task][list][ok] count=%d total=%d", len(items), total)
+		h.markOverdueAll(items)
+		writePaginated(c, items, page, size, total)
+		return
+	}
+	tasks, err := h.service.GetAll(c.Request.Context(), filter)
+	if err != nil {
+		log.Printf("[task][list][err] %v", err)
+		internalError(c, "Failed to retrieve tasks")
+		return
+	}
+	log.Printf("[task][list][ok] count=%d", len(tasks))
+	h.markOverdueAll(tasks)
+	c.JSON(http.StatusOK, tasks)
+}
+
+// GET /tasks/agenda
+// Agenda returns the caller's open tasks grouped into overdue, today,
+// this_week and later in the server timezone. Management and system admins
+// may pass assignee_id to see someone else's agenda.
+func (h *TaskHandler) Agenda(c *gin.Context) {
+	userID, roleID := getUserAndRole(c)
+	if !authz.CanAccessTasks(roleID) {
+		forbidden(c, "Forbidden")
+		return
+	}
+
+	assignee := int64(userID)
+	if raw := strings.TrimSpace(c.Query("assignee_id")); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id <= 0 {
+			badRequest(c, "Invalid assignee_id")
+			return
+		}
+		if id != assignee && !authz.HasCapability(roleID, authz.CapViewOthersAgenda) {
+			forbidden(c, "Forbidden")
+			return
+		}
+		assignee = id
+	}
 
+	filter := models.TaskFilter{AssigneeID: &assignee, StatusGroup: "active", SortBy: "due_date", Order: "asc"}
+	if !h.applyTaskListScope(&filter, userID, roleID) {
+		forbidden(c, "Forbidden")
+		return
+	}
+	tasks, err := h.service.GetAll(c.Request.Context(), filter)
+	if err != nil {
+		log.Printf("[task][agenda][err] uid=%d assignee=%d: %v", userID, assignee, err)
+		internalError(c, "Failed to retrieve tasks")
+		return
+	}
+	h.markOverdueAll(tasks)
+	now := time.Now()
+	if h.loc != nil {
+		now = now.In(h.loc)
+	}
+	c.JSON(http.StatusOK, services.BuildTaskAgenda(tasks, now))
+}
+
+const (
+	taskSummaryDefaultLimit = 5
+	taskSummaryMaxLimit     = 20
+)
+
+// GET /users/me/tasks/summary?limit=
+// MySummary returns the caller's open task counts by status plus the limit
+// (default 5, at most 20) soonest-due open tasks, with due dates in the
+// server timezone.
+func (h *TaskHandler) MySummary(c *gin.Context) {
+	userID, roleID := getUserAndRole(c)
+	if !authz.CanAccessTasks(roleID) {
+		forbidden(c, "Forbidden")
+		return
+	}
+	limit := taskSummaryDefaultLimit
+	if raw := strings.TrimSpace(c.Query("limit")); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			badRequest(c, "Invalid limit")
+			return
+		}
+		limit = min(n, taskSummaryMaxLimit)
+	}
+
+	assignee := int64(userID)
+	filter := models.TaskFilter{AssigneeID: &assignee, StatusGroup: "active", SortBy: "due_date", Order: "asc"}
+	if !h.applyTaskListScope(&filter, userID, roleID) {
+		forbidden(c, "Forbidden")
+		return
+	}
+	tasks, err := h.service.GetAll(c.Request.Context(), filter)
+	if err != nil {
+		log.Printf("[task][summary][err] uid=%d: %v", userID, err)
+		internalError(c, "Failed to retrieve tasks")
+		return
+	}
+	h.markOverdueAll(tasks)
+	now := time.Now()
+	if h.loc != nil {
+		now = now.In(h.loc)
+	}
+	c.JSON(http.StatusOK, services.BuildTaskSummary(tasks, now, limit))
+}
+
+// applyMineFilter resolves ?mine=true to the caller's own assignments, for
+// every role. An assignee_id naming someone else alongside it is rejected.
+func applyMineFilter(c *gin.Context, filter *models.TaskFilter, userID int) error {
+	raw := strings.TrimSpace(c.Query("mine"))
+	if raw == "" {
+		return nil
+	}
+	mine, err := strconv.ParseBool(raw)
+	if err != nil {
+		return errors.New("Invalid mine")
+	}
+	if !mine {
+		return nil
+	}
+	uid := int64(userID)
+	if filter.AssigneeID != nil && *filter.AssigneeID != uid {
+		return errors.New("mine=true conflicts with assignee_id")
+	}
+	filter.AssigneeID = &uid
+	return nil
+}
+
+// applyCreatorFilter resolves ?creator=me to the tasks the caller created,
+// whoever they are assigned to. A creator_id naming someone else alongside it
+// is rejected.
+func applyCreatorFilter(c *gin.Context, filter *models.TaskFilter, userID int) error {
+	raw := strings.ToLower(strings.TrimSpace(c.Query("creator")))
+	if raw == "" {
+		return nil
+	}
+	if raw != "me" {
+		return errors.New("Invalid creator")
+	}
+	uid := int64(userID)
+	if filter.CreatorID != nil && *filter.CreatorID != uid {
+		return errors.New("creator=me conflicts with creator_id")
+	}
+	filter.CreatorID = &uid
+	return nil
+}
+
+// applyTaskListScope narrows a task list filter to what the role may see.
+// It returns false when a branch-bound role has no branch.
+func (h *TaskHandler) applyTaskListScope(filter *models.TaskFilter, userID, roleID int) bool {
 	switch roleID {
 	case authz.RoleSales:
 		branchID, ok := h.taskUserBranchID(userID)
 		if !ok {
-			log.Printf("[task][list][deny] uid=%d role=%d has no branch", userID, roleID)
-			forbidden(c, "Forbidden")
-			return
+			return false
 		}
 		filter.BranchID = &branchID
+		// Sales only sees its own work, whatever assignee/creator filter was sent.
+		uid := int64(userID)
+		filter.ParticipantID = &uid
 	case authz.RoleVisa, authz.RoleControl:
 		branchID, ok := h.taskUserBranchID(userID)
 		if !ok {
-			log.Printf("[task][list][deny] uid=%d role=%d has no branch", userID, roleID)
-			forbidden(c, "Forbidden")
-			return
+			return false
 		}
 		filter.BranchID = &branchID
 	case authz.RoleManagement, authz.RoleSystemAdmin:
 		// full or supervisory visibility — keep requested filter
 	}
+	return true
+}
 
-	if isPaginatedMode(c) {
-		page, size := normalizedPageAndSize(c)
-		offset := offsetFromPage(page, size)
-		items, total, err := h.service.GetAllPaginated(c.Request.Context(), filter, size, offset)
-		if err != nil {
-			log.Printf("[task][list][err] %v", err)
-			internalError(c, "Failed to retrieve tasks")
+// GET /deals/:id/tasks
+func (h *TaskHandler) ListForDeal(c *gin.Context) {
+	h.listForEntity(c, "deal", func(id, userID, roleID int) (bool, error) {
+		if h.deals == nil {
+			return false, nil
+		}
+		deal, err := h.deals.GetByID(c.Request.Context(), id, userID, roleID)
+		return deal != nil, err
+	})
+}
+
+// GET /leads/:id/tasks
+func (h *TaskHandler) ListForLead(c *gin.Context) {
+	h.listForEntity(c, "lead", func(id, userID, roleID int) (bool, error) {
+		if h.leads == nil {
+			return false, nil
+		}
+		lead, err := h.leads.GetByID(c.Request.Context(), id, userID, roleID)
+		return lead != nil, err
+	})
+}
+
+// GET /documents/:id/tasks
+func (h *TaskHandler) ListForDocument(c *gin.Context) {
+	h.listForEntity(c, "document", func(id, userID, roleID int) (bool, error) {
+		if h.documents == nil {
+			return false, nil
+		}
+		doc, err := h.documents.GetDocument(c.Request.Context(), int64(id), userID, roleID)
+		if errors.Is(err, services.ErrNotFound) {
+			return false, nil
+		}
+		return doc != nil, err
+	})
+}
+
+// listForEntity returns the tasks linked to one deal, lead or document, with
+// open/total counts. The entity itself is loaded through its scoped service
+// first, so a viewer who cannot open it gets 403/404 rather than its tasks.
+func (h *TaskHandler) listForEntity(c *gin.Context, entityType string, load func(id, userID, roleID int) (bool, error)) {
+	userID, roleID := getUserAndRole(c)
+	log.Printf("[task][by_entity] call by userID=%d role=%d entity=%s id_param=%s", userID, roleID, entityType, c.Param("id"))
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		badRequest(c, "Invalid id")
+		return
+	}
+	if !authz.CanAccessTasks(roleID) {
+		log.Printf("[task][by_entity][deny] role=%d", roleID)
+		forbidden(c, "Forbidden")
+		return
+	}
+	found, err := load(id, userID, roleID)
+	if err != nil {
+		if errors.Is(err, services.ErrForbidden) {
+			log.Printf("[task][by_entity][deny] uid=%d role=%d %s=%d", userID, roleID, entityType, id)
+			forbidden(c, "Forbidden")
 			return
 		}
-		log.Printf("[task][list][ok] count=%d total=%d", len(items), total)
-		c.JSON(http.StatusOK, models.PaginatedResponse[models.Task]{Items: items, Pagination: buildPaginationMeta(page, size, total)})
+		log.Printf("[task][by_entity][err] %s=%d: %v", entityType, id, err)
+		internalError(c, "Failed to retrieve tasks")
+		return
+	}
+	if !found {
+		switch entityType {
+		case "deal":
+			notFound(c, DealNotFoundCode, "Deal not found")
+		case "document":
+			notFound(c, DocumentNotFound, "Document not found")
+		default:
+			notFound(c, LeadNotFoundCode, "Lead not found")
+		}
+		return
+	}
+
+	tasks, ok, err := h.scopedEntityTasks(c.Request.Context(), entityType, int64(id), userID, roleID)
+	if !ok {
+		log.Printf("[task][by_entity][deny] uid=%d role=%d has no branch", userID, roleID)
+		forbidden(c, "Forbidden")
 		return
 	}
-	tasks, err := h.service.GetAll(c.Request.Context(), filter)
 	if err != nil {
-		log.Printf("[task][list][err] %v", err)
+		log.Printf("[task][by_entity][err] %s=%d: %v", entityType, id, err)
 		internalError(c, "Failed to retrieve tasks")
 		return
 	}
-	log.Printf("[task][list][ok] count=%d", len(tasks))
-	c.JSON(http.StatusOK, tasks)
+	open := countOpenTasks(tasks)
+	log.Printf("[task][by_entity][ok] %s=%d total=%d open=%d", entityType, id, len(tasks), open)
+	h.markOverdueAll(tasks)
+	c.JSON(http.StatusOK, gin.H{"items": tasks, "open": open, "total": len(tasks)})
+}
+
+// scopedEntityTasks lists the tasks linked to one entity that the viewer may
+// see (never nil on success). ok is false when the viewer's role is
+// branch-scoped but the user has no branch.
+func (h *TaskHandler) scopedEntityTasks(ctx context.Context, entityType string, id int64, userID, roleID int) ([]models.Task, bool, error) {
+	filter := models.TaskFilter{EntityType: &entityType, EntityID: &id, StatusGroup: "all"}
+	if !h.applyTaskListScope(&filter, userID, roleID) {
+		return nil, false, nil
+	}
+	tasks, err := h.service.GetAll(ctx, filter)
+	if err != nil {
+		return nil, true, err
+	}
+	if tasks == nil {
+		tasks = []models.Task{}
+	}
+	return tasks, true, nil
+}
+
+func countOpenTasks(tasks []models.Task) int {
+	open := 0
+	for _, t := range tasks {
+		if t.Status != models.StatusDone && t.Status != models.StatusCancelled {
+			open++
+		}
+	}
+	return open
+}
+
+// dealDocumentLister is the part of DocumentService that GET /deals/:id/detail
+// needs; it is detected on the document resolver set by SetDocumentResolver.
+type dealDocumentLister interface {
+	ListDocumentsByDealWithFilter(ctx context.Context, dealID int64, userID, roleID int, filter repositories.DocumentListFilter, scope repositories.ArchiveScope) ([]*models.Document, error)
+}
+
+// dealDetailResponse is the GET /deals/:id/detail payload.
+type dealDetailResponse struct {
+	Deal      *models.Deals      `json:"deal"`
+	Documents []*models.Document `json:"documents"`
+	Tasks     []models.Task      `json:"tasks"`
+	OpenTasks int                `json:"open_tasks"`
+}
+
+// GET /deals/:id/detail — the deal, its active documents and its linked tasks
+// in one response for the deal page. The deal is loaded through the scoped
+// deal service (403/404 as GET /deals/:id); documents and tasks are then
+// filtered exactly as GET /deals/:id/documents and GET /deals/:id/tasks, and a
+// role without access to one of them gets an empty list instead of an error.
+func (h *TaskHandler) DealDetail(c *gin.Context) {
+	userID, roleID := getUserAndRole(c)
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		badRequest(c, "Invalid id")
+		return
+	}
+	if h.deals == nil {
+		notFound(c, DealNotFoundCode, "Deal not found")
+		return
+	}
+	ctx := c.Request.Context()
+	deal, err := h.deals.GetByID(ctx, id, userID, roleID)
+	if err != nil {
+		if errors.Is(err, services.ErrForbidden) {
+			log.Printf("[task][deal_detail][deny] uid=%d role=%d deal=%d", userID, roleID, id)
+			forbidden(c, "Forbidden")
+			return
+		}
+		log.Printf("[task][deal_detail][err] deal=%d: %v", id, err)
+		internalError(c, "Failed to retrieve deal")
+		return
+	}
+	if deal == nil {
+		notFound(c, DealNotFoundCode, "Deal not found")
+		return
+	}
+
+	resp := dealDetailResponse{Deal: deal, Documents: []*models.Document{}, Tasks: []models.Task{}}
+
+	docs, _ := h.documents.(dealDocumentLister)
+	if docs != nil && authz.Can(authz.UserContext{UserID: userID, RoleID: roleID}, "documents.view", "document") {
+		items, err := docs.ListDocumentsByDealWithFilter(ctx, int64(id), userID, roleID, repositories.DocumentListFilter{}, repositories.ArchiveScopeActiveOnly)
+		switch {
+		case err == nil:
+			if items != nil {
+				resp.Documents = documentsViewForRole(items, roleID)
+			}
+		case errors.Is(err, services.ErrForbidden), errors.Is(err, services.ErrNotFound):
+			// the document scope is narrower than the deal scope — show none
+		default:
+			log.Printf("[task][deal_detail][err] deal=%d documents: %v", id, err)
+			internalError(c, "Failed to retrieve documents")
+			return
+		}
+	}
+
+	if authz.CanAccessTasks(roleID) {
+		tasks, ok, err := h.scopedEntityTasks(ctx, "deal", int64(id), userID, roleID)
+		if err != nil {
+			log.Printf("[task][deal_detail][err] deal=%d tasks: %v", id, err)
+			internalError(c, "Failed to retrieve tasks")
+			return
+		}
+		if ok {
+			h.markOverdueAll(tasks)
+			resp.Tasks = tasks
+			resp.OpenTasks = countOpenTasks(tasks)
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// parseReminderOffset parses a positive Go duration ("24h", "90m") into
+// whole seconds.
+func parseReminderOffset(raw string) (int64, bool) {
+	d, err := time.ParseDuration(strings.TrimSpace(raw))
+	if err != nil || d < time.Second {
+		return 0, false
+	}
+	return int64(d / time.Second), true
 }
 
 func taskFilterFromQuery(c *gin.Context) (models.TaskFilter, error) {
@@ -266,16 +799,30 @@ func taskFilterFromQuery(c *gin.Context) (models.TaskFilter, error) {
 		filter.EntityType = &v
 	}
 	if v := strings.ToLower(strings.TrimSpace(c.Query("status"))); v != "" {
-		st := models.TaskStatus(v)
-		if !isAllowedTaskStatus(st) {
-			return models.TaskFilter{}, errors.New("Invalid status")
+		statuses, err := parseTaskStatuses(v)
+		if err != nil {
+			return models.TaskFilter{}, err
 		}
-		filter.Status = &st
+		filter.Statuses = statuses
+	}
+	if v := strings.ToLower(strings.TrimSpace(c.Query("priority"))); v != "" {
+		pr := models.TaskPriority(v)
+		if !isAllowedTaskPriority(pr) {
+			return models.TaskFilter{}, errors.New("Invalid priority")
+		}
+		filter.Priority = &pr
+	}
+	if raw := strings.TrimSpace(c.Query("overdue")); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return models.TaskFilter{}, errors.New("Invalid overdue")
+		}
+		filter.Overdue = &v
 	}
 	if filter.StatusGroup != "" && filter.StatusGroup != "active" && filter.StatusGroup != "closed" && filter.StatusGroup != "all" {
 		return models.TaskFilter{}, errors.New("Invalid status_group")
 	}
-	if filter.SortBy != "" && filter.SortBy != "created_at" && filter.SortBy != "due_date" && filter.SortBy != "priority" && filter.SortBy != "status" && filter.SortBy != "title" {
+	if filter.SortBy != "" && !repositories.IsTaskSortField(filter.SortBy) {
 		return models.TaskFilter{}, errors.New("Invalid sort_by")
 	}
 	if filter.Order != "" && filter.Order != "asc" && filter.Order != "desc" {
@@ -299,7 +846,7 @@ func (h *TaskHandler) Update(c *gin.Context) {
 	uid := int64(userID)
 	if authz.IsReadOnly(roleID) {
 		log.Printf("[task][update][deny] read-only role=%d", roleID)
-		forbidden(c, "Read-only role")
+		writeError(c, http.StatusForbidden, ReadOnlyRoleCode, "Read-only role")
 		return
 	}
 
@@ -311,7 +858,7 @@ func (h *TaskHandler) Update(c *gin.Context) {
 	}
 	if current == nil {
 		log.Printf("[task][update][404] id=%d", id)
-		notFound(c, ValidationFailed, "Task not found")
+		notFound(c, TaskNotFoundCode, "Task not found")
 		return
 	}
 
@@ -326,18 +873,38 @@ func (h *TaskHandler) Update(c *gin.Context) {
 		AssigneeIDs *[]int64             `json:"assignee_ids"`
 		Title       *string              `json:"title"`
 		Description *string              `json:"description"`
-		DueDate     *string              `json:"due_date"`    // RFC3339
-		ReminderAt  *string              `json:"reminder_at"` // RFC3339
+		DueDate     *string              `json:"due_date"`        // RFC3339
+		ReminderAt  *string              `json:"reminder_at"`     // RFC3339
+		ReminderOff *string              `json:"reminder_offset"` // "" stops deriving and clears the reminder
 		Priority    *models.TaskPriority `json:"priority"`
 		Status      *models.TaskStatus   `json:"status"`
+		EntityID    *int64               `json:"entity_id"`
+		EntityType  *string              `json:"entity_type"`
+		Version     *int                 `json:"version"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		log.Printf("[task][update][bind][err] %v", err)
-		badRequest(c, "Invalid payload")
+		bindError(c, err, "Invalid payload")
 		return
 	}
 
 	update := *current
+	update.LastModifiedBy = &uid
+	// Optimistic locking: If-Match (the ETag from GET) takes precedence over
+	// the payload version. Without either, the version read above is used.
+	update.Version = 0
+	if req.Version != nil {
+		update.Version = *req.Version
+	}
+	if ifMatch := strings.TrimSpace(c.GetHeader("If-Match")); ifMatch != "" {
+		v, ok := parseTaskETag(ifMatch)
+		if !ok {
+			log.Printf("[task][update][err] invalid If-Match=%q", ifMatch)
+			badRequest(c, "Invalid If-Match header")
+			return
+		}
+		update.Version = v
+	}
 
 	if req.AssigneeIDs != nil || req.AssigneeID != nil {
 		var incoming []int64
@@ -355,7 +922,7 @@ func (h *TaskHandler) Update(c *gin.Context) {
 			return
 		}
 		for _, aid := range assignees {
-			if roleID == authz.RoleSales && aid != uid {
+			if aid != uid && !authz.HasCapability(roleID, authz.CapAssignTasksToOthers) {
 				log.Printf("[task][update][deny] staff uid=%d set assignee=%d", uid, aid)
 				forbidden(c, "Staff can assign only to self")
 				return
@@ -389,6 +956,8 @@ func (h *TaskHandler) Update(c *gin.Context) {
 		}
 	}
 	if req.ReminderAt != nil {
+		// An explicit reminder_at wins over any offset and stops deriving.
+		update.ReminderOffset = nil
 		if *req.ReminderAt == "" {
 			update.ReminderAt = nil
 		} else {
@@ -401,13 +970,33 @@ func (h *TaskHandler) Update(c *gin.Context) {
 			update.ReminderAt = &t
 		}
 	}
+	if req.ReminderAt == nil && req.ReminderOff != nil {
+		if strings.TrimSpace(*req.ReminderOff) == "" {
+			update.ReminderOffset = nil
+			update.ReminderAt = nil
+		} else {
+			secs, ok := parseReminderOffset(*req.ReminderOff)
+			if !ok {
+				log.Printf("[task][update][err] invalid reminder_offset=%q", *req.ReminderOff)
+				badRequest(c, "Invalid reminder offset")
+				return
+			}
+			update.ReminderOffset = &secs
+		}
+	}
 	if req.Priority != nil {
 		update.Priority = *req.Priority
 	}
+	if req.EntityType != nil {
+		update.EntityType = *req.EntityType
+	}
+	if req.EntityID != nil {
+		update.EntityID = *req.EntityID
+	}
 	if req.Status != nil {
-		if !isAllowedTaskStatus(*req.Status) || !isTransitionAllowed(current.Status, *req.Status) {
+		if !isAllowedTaskStatus(*req.Status) || !isTransitionAllowed(current.Status, *req.Status, roleID) {
 			log.Printf("[task][update][deny] illegal status transition: from=%q to=%q", current.Status, *req.Status)
-			conflict(c, ValidationFailed, "Illegal status transition")
+			conflict(c, TaskTransitionNotAllowedCode, "Illegal status transition")
 			return
 		}
 		update.Status = *req.Status
@@ -417,12 +1006,35 @@ func (h *TaskHandler) Update(c *gin.Context) {
 
 	updatedTask, err := h.service.Update(c.Request.Context(), id, &update)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidTaskEntityType) || errors.Is(err, services.ErrTaskEntityIDRequired) {
+			log.Printf("[task][update][err] invalid entity entity_type=%q entity_id=%d: %v", update.EntityType, update.EntityID, err)
+			badRequest(c, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrInvalidTaskAssignee) {
+			log.Printf("[task][update][err] invalid assignees=%v: %v", update.AssigneeIDs, err)
+			badRequest(c, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrTaskVersionConflict) {
+			log.Printf("[task][update][409] stale version id=%d version=%d", id, update.Version)
+			conflict(c, TaskVersionConflictCode, "Task was modified by someone else; reload and retry")
+			return
+		}
 		log.Printf("[task][update][err] save id=%d: %v", id, err)
 		internalError(c, "Failed to update task")
 		return
 	}
 	log.Printf("[task][update][ok] id=%d", id)
+	c.Header("ETag", taskETag(updatedTask))
+	h.markOverdue(updatedTask)
 	c.JSON(http.StatusOK, updatedTask)
+	if updatedTask.Status != current.Status {
+		h.publishEvent(services.TaskEventStatusChanged, updatedTask)
+	}
+	if !sameTaskAssignees(current, updatedTask) {
+		h.publishEvent(services.TaskEventAssigned, updatedTask)
+	}
 
 	// === TG: уведомление об обновлении ===
 	h.notifyAssignee(c, updatedTask, "✏️ Задача обновлена")
@@ -444,7 +1056,7 @@ func (h *TaskHandler) Delete(c *gin.Context) {
 	uid := int64(userID)
 	if authz.IsReadOnly(roleID) {
 		log.Printf("[task][delete][deny] read-only role=%d", roleID)
-		forbidden(c, "Read-only role")
+		writeError(c, http.StatusForbidden, ReadOnlyRoleCode, "Read-only role")
 		return
 	}
 
@@ -456,7 +1068,7 @@ func (h *TaskHandler) Delete(c *gin.Context) {
 	}
 	if current == nil {
 		log.Printf("[task][delete][404] id=%d", id)
-		notFound(c, ValidationFailed, "Task not found")
+		notFound(c, TaskNotFoundCode, "Task not found")
 		return
 	}
 
@@ -477,15 +1089,20 @@ func (h *TaskHandler) Delete(c *gin.Context) {
 			forbidden(c, "Forbidden")
 			return
 		}
+		if errors.Is(err, services.ErrNotFound) {
+			notFound(c, TaskNotFoundCode, "Task not found")
+			return
+		}
 		log.Printf("[task][delete][err] id=%d: %v", id, err)
 		internalError(c, "Failed to delete task")
 		return
 	}
 
 	log.Printf("[task][delete][ok] id=%d", id)
+	h.publishEvent(services.TaskEventDeleted, current)
 
 	// Телеграм-уведомление об удалении
-	h.notifyAssignee(c, current, "🗑️ Задача удалена")
+	h.notifyAssigneeDeleted(c, current)
 
 	c.Status(http.StatusNoContent)
 }
@@ -502,7 +1119,7 @@ func (h *TaskHandler) Archive(c *gin.Context) {
 		return
 	}
 	if authz.IsReadOnly(roleID) {
-		forbidden(c, "Read-only role")
+		writeError(c, http.StatusForbidden, ReadOnlyRoleCode, "Read-only role")
 		return
 	}
 	current, err := h.service.GetByIDWithArchiveScope(c.Request.Context(), id, repositories.ArchiveScopeAll)
@@ -511,7 +1128,7 @@ func (h *TaskHandler) Archive(c *gin.Context) {
 		return
 	}
 	if current == nil {
-		notFound(c, ValidationFailed, "Task not found")
+		notFound(c, TaskNotFoundCode, "Task not found")
 		return
 	}
 	uid := int64(userID)
@@ -531,9 +1148,14 @@ func (h *TaskHandler) Archive(c *gin.Context) {
 			forbidden(c, "Forbidden")
 			return
 		}
+		if errors.Is(err, services.ErrNotFound) {
+			notFound(c, TaskNotFoundCode, "Task not found")
+			return
+		}
 		internalError(c, "Failed to archive task")
 		return
 	}
+	h.markOverdue(updated)
 	c.JSON(http.StatusOK, updated)
 }
 
@@ -545,7 +1167,7 @@ func (h *TaskHandler) Unarchive(c *gin.Context) {
 		return
 	}
 	if authz.IsReadOnly(roleID) {
-		forbidden(c, "Read-only role")
+		writeError(c, http.StatusForbidden, ReadOnlyRoleCode, "Read-only role")
 		return
 	}
 	current, err := h.service.GetByIDWithArchiveScope(c.Request.Context(), id, repositories.ArchiveScopeAll)
@@ -554,7 +1176,7 @@ func (h *TaskHandler) Unarchive(c *gin.Context) {
 		return
 	}
 	if current == nil {
-		notFound(c, ValidationFailed, "Task not found")
+		notFound(c, TaskNotFoundCode, "Task not found")
 		return
 	}
 	uid := int64(userID)
@@ -568,6 +1190,10 @@ func (h *TaskHandler) Unarchive(c *gin.Context) {
 			forbidden(c, "Forbidden")
 			return
 		}
+		if errors.Is(err, services.ErrNotFound) {
+			notFound(c, TaskNotFoundCode, "Task not found")
+			return
+		}
 		if err == services.ErrNotArchived {
 			badRequest(c, "Task is not archived")
 			return
@@ -575,6 +1201,7 @@ func (h *TaskHandler) Unarchive(c *gin.Context) {
 		internalError(c, "Failed to unarchive task")
 		return
 	}
+	h.markOverdue(updated)
 	c.JSON(http.StatusOK, updated)
 }
 
@@ -593,7 +1220,7 @@ func (h *TaskHandler) ChangeStatus(c *gin.Context) {
 	uid := int64(userID)
 	if authz.IsReadOnly(roleID) {
 		log.Printf("[task][status][deny] read-only role=%d", roleID)
-		forbidden(c, "Read-only role")
+		writeError(c, http.StatusForbidden, ReadOnlyRoleCode, "Read-only role")
 		return
 	}
 
@@ -605,7 +1232,7 @@ func (h *TaskHandler) ChangeStatus(c *gin.Context) {
 	}
 	if current == nil {
 		log.Printf("[task][status][404] id=%d", id)
-		notFound(c, ValidationFailed, "Task not found")
+		notFound(c, TaskNotFoundCode, "Task not found")
 		return
 	}
 
@@ -624,24 +1251,151 @@ func (h *TaskHandler) ChangeStatus(c *gin.Context) {
 		badRequest(c, "Invalid payload")
 		return
 	}
-	if !isAllowedTaskStatus(body.To) || !isTransitionAllowed(current.Status, body.To) {
+	if !isAllowedTaskStatus(body.To) || !isTransitionAllowed(current.Status, body.To, roleID) {
 		log.Printf("[task][status][deny] illegal transition from=%q to=%q", current.Status, body.To)
-		conflict(c, ValidationFailed, "Illegal status")
+		conflict(c, TaskTransitionNotAllowedCode, "Illegal status")
 		return
 	}
 
-	updated, err := h.service.UpdateStatus(c.Request.Context(), id, body.To)
+	updated, err := h.service.UpdateStatus(c.Request.Context(), id, body.To, uid)
 	if err != nil {
 		log.Printf("[task][status][err] save id=%d: %v", id, err)
 		internalError(c, "Failed to update task status")
 		return
 	}
 	log.Printf("[task][status][ok] id=%d new=%q", id, body.To)
+	h.markOverdue(updated)
 	c.JSON(http.StatusOK, updated)
+	if body.To != current.Status {
+		h.publishEvent(services.TaskEventStatusChanged, updated)
+	}
 
 	// === TG: уведомление о смене статуса ===
-	if body.To != models.StatusDone {
-		h.notifyAssignee(c, updated, "🔁 Статус изменён на "+string(body.To))
+	h.notifyStatusChanged(c, updated, body.To)
+}
+
+// maxTaskBatchSize caps POST /tasks/batch-status.
+const maxTaskBatchSize = 100
+
+// taskBatchResult is one entry of the POST /tasks/batch-status response.
+type taskBatchResult struct {
+	ID     int64             `json:"id"`
+	OK     bool              `json:"ok"`
+	Status models.TaskStatus `json:"status,omitempty"`
+	Reason string            `json:"reason,omitempty"`
+}
+
+// POST /tasks/batch-status { "ids": [1,2], "to": "done", "comment": "..." }
+// Каждая задача проверяется отдельно (RBAC, переход); допустимые переводятся
+// в одной транзакции, по каждой возвращается результат.
+func (h *TaskHandler) BatchStatus(c *gin.Context) {
+	userID, roleID := getUserAndRole(c)
+	log.Printf("[task][batch_status] call by userID=%d role=%d", userID, roleID)
+
+	uid := int64(userID)
+	if authz.IsReadOnly(roleID) {
+		log.Printf("[task][batch_status][deny] read-only role=%d", roleID)
+		writeError(c, http.StatusForbidden, ReadOnlyRoleCode, "Read-only role")
+		return
+	}
+
+	var body struct {
+		IDs     []int64           `json:"ids"`
+		To      models.TaskStatus `json:"to"`
+		Comment string            `json:"comment"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		log.Printf("[task][batch_status][bind][err] %v", err)
+		badRequest(c, "Invalid payload")
+		return
+	}
+	if len(body.IDs) == 0 {
+		badRequest(c, "ids are required")
+		return
+	}
+	if len(body.IDs) > maxTaskBatchSize {
+		badRequest(c, "Too many ids")
+		return
+	}
+	if !isAllowedTaskStatus(body.To) {
+		badRequest(c, "Invalid status")
+		return
+	}
+
+	results := make([]taskBatchResult, 0, len(body.IDs))
+	index := make(map[int64]int, len(body.IDs))
+	var changes []repositories.TaskStatusChange
+	for _, id := range body.IDs {
+		if _, dup := index[id]; dup {
+			continue
+		}
+		index[id] = len(results)
+		res := taskBatchResult{ID: id}
+
+		current, err := h.service.GetByID(c.Request.Context(), id)
+		switch {
+		case err != nil:
+			log.Printf("[task][batch_status][err] get id=%d: %v", id, err)
+			res.Reason = "error"
+		case current == nil:
+			res.Reason = "not_found"
+		case !canModifyTask(roleID, uid, current) || !h.hasTaskBranchAccess(roleID, uid, current):
+			res.Reason = "forbidden"
+		case !isTransitionAllowed(current.Status, body.To, roleID):
+			res.Reason = "illegal_transition"
+			res.Status = current.Status
+		default:
+			changes = append(changes, repositories.TaskStatusChange{ID: id, From: current.Status})
+		}
+		results = append(results, res)
+	}
+
+	var updated []*models.Task
+	if len(changes) > 0 {
+		var failed map[int64]error
+		var err error
+		updated, failed, err = h.service.UpdateStatusBatch(c.Request.Context(), changes, body.To, uid)
+		if err != nil {
+			log.Printf("[task][batch_status][err] save: %v", err)
+			internalError(c, "Failed to update task statuses")
+			return
+		}
+		for _, ch := range changes {
+			r := &results[index[ch.ID]]
+			if ferr := failed[ch.ID]; ferr != nil {
+				if errors.Is(ferr, repositories.ErrTaskVersionConflict) {
+					r.Reason = "conflict"
+				} else {
+					log.Printf("[task][batch_status][err] save id=%d: %v", ch.ID, ferr)
+					r.Reason = "error"
+				}
+				continue
+			}
+			r.OK = true
+			r.Status = body.To
+		}
+	}
+
+	ok := 0
+	for _, r := range results {
+		if r.OK {
+			ok++
+		}
+	}
+	log.Printf("[task][batch_status][ok] to=%q updated=%d rejected=%d", body.To, ok, len(results)-ok)
+	c.JSON(http.StatusOK, gin.H{"results": results, "updated": ok, "rejected": len(results) - ok})
+
+	// === после коммита: вебхуки и TG ===
+	from := make(map[int64]models.TaskStatus, len(changes))
+	for _, ch := range changes {
+		from[ch.ID] = ch.From
+	}
+	for _, t := range updated {
+		if from[t.ID] == body.To {
+			continue
+		}
+		h.publishEvent(services.TaskEventStatusChanged, t)
+		h.notifyStatusChanged(c, t, body.To)
 	}
 }
 
@@ -659,7 +1413,7 @@ func (h *TaskHandler) Complete(c *gin.Context) {
 	uid := int64(userID)
 	if authz.IsReadOnly(roleID) {
 		log.Printf("[task][complete][deny] read-only role=%d", roleID)
-		forbidden(c, "Read-only role")
+		writeError(c, http.StatusForbidden, ReadOnlyRoleCode, "Read-only role")
 		return
 	}
 
@@ -671,7 +1425,7 @@ func (h *TaskHandler) Complete(c *gin.Context) {
 	}
 	if current == nil {
 		log.Printf("[task][complete][404] id=%d", id)
-		notFound(c, ValidationFailed, "Task not found")
+		notFound(c, TaskNotFoundCode, "Task not found")
 		return
 	}
 	if !canModifyTask(roleID, uid, current) || !h.hasTaskBranchAccess(roleID, uid, current) {
@@ -680,15 +1434,19 @@ func (h *TaskHandler) Complete(c *gin.Context) {
 		return
 	}
 
-	updated, err := h.service.UpdateStatus(c.Request.Context(), id, models.StatusDone)
+	updated, err := h.service.UpdateStatus(c.Request.Context(), id, models.StatusDone, uid)
 	if err != nil {
 		log.Printf("[task][complete][err] save id=%d: %v", id, err)
 		internalError(c, "Failed to complete task")
 		return
 	}
 	log.Printf("[task][complete][ok] id=%d", id)
+	h.markOverdue(updated)
 	c.JSON(http.StatusOK, updated)
-
+	if current.Status != models.StatusDone {
+		h.publishEvent(services.TaskEventStatusChanged, updated)
+		h.notifyStatusChanged(c, updated, models.StatusDone)
+	}
 }
 
 // POST /tasks/:id/remind-later
@@ -705,7 +1463,7 @@ func (h *TaskHandler) RemindLater(c *gin.Context) {
 	uid := int64(userID)
 	if authz.IsReadOnly(roleID) {
 		log.Printf("[task][remind][deny] read-only role=%d", roleID)
-		forbidden(c, "Read-only role")
+		writeError(c, http.StatusForbidden, ReadOnlyRoleCode, "Read-only role")
 		return
 	}
 
@@ -717,7 +1475,7 @@ func (h *TaskHandler) RemindLater(c *gin.Context) {
 	}
 	if current == nil {
 		log.Printf("[task][remind][404] id=%d", id)
-		notFound(c, ValidationFailed, "Task not found")
+		notFound(c, TaskNotFoundCode, "Task not found")
 		return
 	}
 	if !canModifyTask(roleID, uid, current) || !h.hasTaskBranchAccess(roleID, uid, current) {
@@ -754,6 +1512,7 @@ func (h *TaskHandler) RemindLater(c *gin.Context) {
 	}
 
 	update := *current
+	update.LastModifiedBy = &uid
 	update.ReminderAt = &newReminder
 	update.UpdatedAt = time.Now()
 
@@ -764,6 +1523,7 @@ func (h *TaskHandler) RemindLater(c *gin.Context) {
 		return
 	}
 	log.Printf("[task][remind][ok] id=%d new=%s", id, newReminder.String())
+	h.markOverdue(updated)
 	c.JSON(http.StatusOK, updated)
 }
 
@@ -782,7 +1542,7 @@ func (h *TaskHandler) Assign(c *gin.Context) {
 	uid := int64(userID)
 	if authz.IsReadOnly(roleID) {
 		log.Printf("[task][assign][deny] read-only role=%d", roleID)
-		forbidden(c, "Read-only role")
+		writeError(c, http.StatusForbidden, ReadOnlyRoleCode, "Read-only role")
 		return
 	}
 
@@ -794,7 +1554,7 @@ func (h *TaskHandler) Assign(c *gin.Context) {
 	}
 	if current == nil {
 		log.Printf("[task][assign][404] id=%d", id)
-		notFound(c, ValidationFailed, "Task not found")
+		notFound(c, TaskNotFoundCode, "Task not found")
 		return
 	}
 
@@ -814,7 +1574,7 @@ func (h *TaskHandler) Assign(c *gin.Context) {
 		forbidden(c, "Forbidden")
 		return
 	}
-	if roleID == authz.RoleSales && body.AssigneeID != uid {
+	if body.AssigneeID != uid && !authz.HasCapability(roleID, authz.CapAssignTasksToOthers) {
 		log.Printf("[task][assign][deny] staff uid=%d -> %d", uid, body.AssigneeID)
 		forbidden(c, "Staff can assign only to self")
 		return
@@ -825,19 +1585,62 @@ func (h *TaskHandler) Assign(c *gin.Context) {
 		return
 	}
 
-	updated, err := h.service.UpdateAssignee(c.Request.Context(), id, body.AssigneeID)
+	updated, err := h.service.UpdateAssignee(c.Request.Context(), id, body.AssigneeID, uid)
 	if err != nil {
 		log.Printf("[task][assign][err] save id=%d -> assignee=%d: %v", id, body.AssigneeID, err)
+		if errors.Is(err, services.ErrInvalidTaskAssignee) {
+			badRequest(c, err.Error())
+			return
+		}
 		internalError(c, "Failed to update assignee")
 		return
 	}
 	log.Printf("[task][assign][ok] id=%d assignee=%d", id, body.AssigneeID)
+	h.markOverdue(updated)
 	c.JSON(http.StatusOK, updated)
+	h.publishEvent(services.TaskEventAssigned, updated)
 
 	// === TG: уведомление новому исполнителю ===
 	h.notifyAssignee(c, updated, "👤 Вам назначена задача")
 }
 
+// POST /tasks/reassign — передать все открытые задачи сотрудника другому
+// (например, при увольнении). Доступ ограничен на уровне маршрута.
+func (h *TaskHandler) Reassign(c *gin.Context) {
+	userID, roleID := getUserAndRole(c)
+	log.Printf("[task][reassign] call by userID=%d role=%d", userID, roleID)
+
+	var body struct {
+		FromUser int64 `json:"from_user" binding:"required"`
+		ToUser   int64 `json:"to_user" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		log.Printf("[task][reassign][bind][err] %v", err)
+		badRequest(c, "Invalid payload")
+		return
+	}
+	if body.FromUser <= 0 || body.ToUser <= 0 || body.FromUser == body.ToUser {
+		badRequest(c, "from_user and to_user must be two different users")
+		return
+	}
+	moved, err := h.service.ReassignOpen(c.Request.Context(), body.FromUser, body.ToUser)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTaskAssignee) {
+			badRequest(c, "to_user must be an active user")
+			return
+		}
+		log.Printf("[task][reassign][err] %d -> %d: %v", body.FromUser, body.ToUser, err)
+		internalError(c, "Failed to reassign tasks")
+		return
+	}
+	log.Printf("[task][reassign][ok] %d -> %d moved=%d", body.FromUser, body.ToUser, len(moved))
+	c.JSON(http.StatusOK, gin.H{"moved": len(moved)})
+	h.publishReassigned(c, moved)
+
+	// === TG: одно сводное сообщение новому исполнителю ===
+	h.notifyTasksReassigned(c, body.FromUser, body.ToUser, len(moved))
+}
+
 // ---- helpers ----
 func isAllowedTaskStatus(s models.TaskStatus) bool {
 	switch s {
@@ -847,7 +1650,37 @@ func isAllowedTaskStatus(s models.TaskStatus) bool {
 	return false
 }
 
-func isTransitionAllowed(from, to models.TaskStatus) bool {
+// parseTaskStatuses parses a comma-separated status list ("new,in_progress"),
+// dropping empty items and repeats. Any unknown status rejects the list.
+func parseTaskStatuses(raw string) ([]models.TaskStatus, error) {
+	var statuses []models.TaskStatus
+	seen := map[models.TaskStatus]bool{}
+	for _, part := range strings.Split(raw, ",") {
+		st := models.TaskStatus(strings.TrimSpace(part))
+		if st == "" || seen[st] {
+			continue
+		}
+		if !isAllowedTaskStatus(st) {
+			return nil, errors.New("Invalid status")
+		}
+		seen[st] = true
+		statuses = append(statuses, st)
+	}
+	if len(statuses) == 0 {
+		return nil, errors.New("Invalid status")
+	}
+	return statuses, nil
+}
+
+func isAllowedTaskPriority(p models.TaskPriority) bool {
+	switch p {
+	case models.PriorityLow, models.PriorityNormal, models.PriorityHigh, models.PriorityUrgent:
+		return true
+	}
+	return false
+}
+
+func isTransitionAllowed(from, to models.TaskStatus, roleID int) bool {
 	if from == to {
 		return true
 	}
@@ -857,11 +1690,30 @@ func isTransitionAllowed(from, to models.TaskStatus) bool {
 	case models.StatusInProgress:
 		return to == models.StatusDone || to == models.StatusCancelled
 	case models.StatusDone, models.StatusCancelled:
-		return false
+		return to == models.StatusInProgress && authz.HasCapability(roleID, authz.CapReopenTasks)
 	}
 	return false
 }
 
+// taskStatusOrder is the order allowed transitions are listed in.
+var taskStatusOrder = []models.TaskStatus{models.StatusNew, models.StatusInProgress, models.StatusDone, models.StatusCancelled}
+
+// allowedTaskTransitions lists the statuses the caller may move t to, using
+// the same rules as the status endpoints. It is empty when the caller may
+// not modify t at all.
+func (h *TaskHandler) allowedTaskTransitions(roleID int, uid int64, t *models.Task) []models.TaskStatus {
+	out := []models.TaskStatus{}
+	if !canModifyTask(roleID, uid, t) || !h.hasTaskBranchAccess(roleID, uid, t) {
+		return out
+	}
+	for _, to := range taskStatusOrder {
+		if to != t.Status && isTransitionAllowed(t.Status, to, roleID) {
+			out = append(out, to)
+		}
+	}
+	return out
+}
+
 func canViewTask(roleID int, uid int64, t *models.Task) bool {
 	switch roleID {
 	case authz.RoleManagement, authz.RoleVisa, authz.RoleControl, authz.RoleSystemAdmin:
@@ -974,25 +1826,164 @@ func (h *TaskHandler) canAssignTaskWithinBranch(roleID int, actorID, assigneeID
 	}
 }
 
+// markOverdue fills the computed is_overdue flag of a task about to be
+// returned, using the server timezone.
+func (h *TaskHandler) markOverdue(t *models.Task) {
+	if t == nil {
+		return
+	}
+	now := time.Now()
+	if h.loc != nil {
+		now = now.In(h.loc)
+	}
+	t.IsOverdue = t.Overdue(now)
+}
+
+func (h *TaskHandler) markOverdueAll(tasks []models.Task) {
+	for i := range tasks {
+		h.markOverdue(&tasks[i])
+	}
+}
+
+// publishEvent hands a task event to the webhook dispatcher, if configured.
+func (h *TaskHandler) publishEvent(event string, t *models.Task) {
+	if h.events == nil || t == nil {
+		return
+	}
+	h.events.Publish(event, t)
+}
+
+// publishReassigned sends task.assigned for every task moved by a bulk
+// reassignment, loading each one after the response has been written.
+func (h *TaskHandler) publishReassigned(c *gin.Context, ids []int64) {
+	if h.events == nil || len(ids) == 0 {
+		return
+	}
+	ctx := context.WithoutCancel(c.Request.Context())
+	h.dispatch(func() {
+		for _, id := range ids {
+			t, err := h.service.GetByID(ctx, id)
+			if err != nil || t == nil {
+				log.Printf("[task][reassign][event] load id=%d: %v", id, err)
+				continue
+			}
+			h.publishEvent(services.TaskEventAssigned, t)
+		}
+	})
+}
+
+// sameTaskAssignees reports whether both tasks have the same assignee set.
+func sameTaskAssignees(a, b *models.Task) bool {
+	x, y := taskAssigneeRecipients(a), taskAssigneeRecipients(b)
+	if len(x) != len(y) {
+		return false
+	}
+	seen := make(map[int64]bool, len(x))
+	for _, id := range x {
+		seen[id] = true
+	}
+	for _, id := range y {
+		if !seen[id] {
+			return false
+		}
+	}
+	return true
+}
+
 // === TG helpers ===
 func (h *TaskHandler) notifyAssignee(c *gin.Context, t *models.Task, prefix string) {
-	if h.tg == nil || h.users == nil || t == nil {
+	if !h.canNotify() || t == nil {
 		return
 	}
-	msg := prefix + "\n" + h.tg.FormatTaskNotification(t)
-	for _, assigneeID := range taskAssigneeRecipients(t) {
-		chatID, allow, err := h.users.GetTelegramSettings(c.Request.Context(), assigneeID)
-		if err != nil {
-			log.Printf("[task][notify] get telegram settings failed: assignee=%d err=%v", assigneeID, err)
-			continue
+	h.inBackground(c, t, func(ctx context.Context, t *models.Task) {
+		h.sendToAssignees(ctx, t, prefix, h.telegramTaskMessage(prefix, t))
+	})
+}
+
+// inBackground runs send off the request path with a copy of t and a context
+// that outlives the request, so a slow Bot API (retries and Retry-After
+// pauses) cannot hold a write that has already committed.
+func (h *TaskHandler) inBackground(c *gin.Context, t *models.Task, send func(ctx context.Context, t *models.Task)) {
+	ctx := context.WithoutCancel(c.Request.Context())
+	task := *t
+	h.dispatch(func() { send(ctx, &task) })
+}
+
+// notifyStatusChanged tells the assignees about a status change. When the
+// task is closed (done/cancelled) the creator who delegated it is told as
+// well, unless they are one of the assignees.
+func (h *TaskHandler) notifyStatusChanged(c *gin.Context, t *models.Task, to models.TaskStatus) {
+	if !h.canNotify() || t == nil {
+		return
+	}
+	h.inBackground(c, t, func(ctx context.Context, t *models.Task) {
+		headline := "🔁 Статус изменён на " + string(to)
+		msg := h.telegramTaskMessage(headline, t)
+		recipients := taskAssigneeRecipients(t)
+		h.sendToAssignees(ctx, t, headline, msg)
+		if (to != models.StatusDone && to != models.StatusCancelled) || t.CreatorID == 0 {
+			return
 		}
-		if !allow || chatID == 0 {
-			log.Printf("[task][notify] skip: assignee=%d allow=%v chatID=%d", assigneeID, allow, chatID)
-			continue
+		for _, id := range recipients {
+			if id == t.CreatorID {
+				return
+			}
 		}
+		h.notifyUser(ctx, t.CreatorID, t, headline, msg)
+	})
+}
+
+// canNotify reports whether any notification channel is configured.
+func (h *TaskHandler) canNotify() bool {
+	return h.users != nil && (h.tg != nil || h.mail != nil)
+}
+
+func (h *TaskHandler) telegramTaskMessage(headline string, t *models.Task) string {
+	if h.tg == nil {
+		return ""
+	}
+	return headline + "\n" + h.tg.FormatTaskNotification(t)
+}
+
+// sendToAssignees notifies every assignee; see notifyUser for the channel.
+// An empty headline keeps the notification Telegram-only.
+func (h *TaskHandler) sendToAssignees(ctx context.Context, t *models.Task, headline, msg string) {
+	for _, assigneeID := range taskAssigneeRecipients(t) {
+		h.notifyUser(ctx, assigneeID, t, headline, msg)
+	}
+}
+
+// notifyUser sends msg to the user's linked Telegram chat if they have task
+// notifications enabled there. Otherwise, when headline is set, it falls
+// back to email for users who opted in.
+func (h *TaskHandler) notifyUser(ctx context.Context, userID int64, t *models.Task, headline, msg string) {
+	chatID, allow, err := h.users.GetTelegramSettings(ctx, userID)
+	if err != nil {
+		log.Printf("[task][notify] get telegram settings failed: user=%d err=%v", userID, err)
+		return
+	}
+	if h.tg != nil && allow && chatID != 0 {
 		if err := h.tg.SendMessage(chatID, msg); err != nil {
 			log.Printf("[task][notify] send error: %v", err)
 		}
+		return
+	}
+	if headline == "" || h.mail == nil || h.emailSettings == nil {
+		log.Printf("[task][notify] skip: user=%d allow=%v chatID=%d", userID, allow, chatID)
+		return
+	}
+	email, optedIn, err := h.emailSettings.GetTaskEmailSettings(ctx, userID)
+	if err != nil {
+		log.Printf("[task][notify][email] get settings failed: user=%d err=%v", userID, err)
+		return
+	}
+	email = strings.TrimSpace(email)
+	if !optedIn || !validEmail(email) {
+		log.Printf("[task][notify] skip: user=%d telegram=%v email_opt_in=%v", userID, chatID != 0 && allow, optedIn)
+		return
+	}
+	if err := h.mail.SendTaskEmail(email, t, headline); err != nil {
+		log.Printf("[task][notify][email] send error: user=%d err=%v", userID, err)
 	}
 }
 
@@ -1010,12 +2001,117 @@ func (h *TaskHandler) notifyAssigneeDeleted(c *gin.Context, t *models.Task) {
 	if h.tg == nil || h.users == nil || t == nil {
 		return
 	}
-	chatID, allow, err := h.users.GetTelegramSettings(c.Request.Context(), t.AssigneeID)
-	if err != nil || !allow || chatID == 0 {
+	h.inBackground(c, t, func(ctx context.Context, t *models.Task) {
+		h.sendToAssignees(ctx, t, "", h.tg.FormatTaskDeletedNotification(t))
+	})
+}
+
+// notifyTasksReassigned sends the new assignee one summary instead of a
+// message per moved task.
+func (h *TaskHandler) notifyTasksReassigned(c *gin.Context, fromUser, toUser int64, moved int) {
+	if h.tg == nil || h.users == nil || moved == 0 {
 		return
 	}
-	msg := "🗑️ Задача удалена\n" + h.tg.FormatTaskNotification(t)
-	if err := h.tg.SendMessage(chatID, msg); err != nil {
-		log.Printf("[task][notify][delete] send error: %v", err)
+	ctx := context.WithoutCancel(c.Request.Context())
+	h.dispatch(func() {
+		chatID, allow, err := h.users.GetTelegramSettings(ctx, toUser)
+		if err != nil {
+			log.Printf("[task][notify] get telegram settings failed: assignee=%d err=%v", toUser, err)
+			return
+		}
+		if !allow || chatID == 0 {
+			return
+		}
+		fromName := ""
+		if from, err := h.users.GetByID(int(fromUser)); err == nil && from != nil {
+			fromName = userFullName(from)
+		}
+		if err := h.tg.SendMessage(chatID, h.tg.FormatTasksReassignedNotification(moved, fromName)); err != nil {
+			log.Printf("[task][notify] send error: %v", err)
+		}
+	})
+}
+
+// taskETag renders the task version as a strong ETag, e.g. "3".
+func taskETag(t *models.Task) string {
+	return strconv.Quote(strconv.Itoa(t.Version))
+}
+
+// parseTaskETag accepts an If-Match value produced by taskETag (quotes and a
+// weak W/ prefix are tolerated) and returns the version.
+func parseTaskETag(raw string) (int, bool) {
+	raw = strings.TrimPrefix(strings.TrimSpace(raw), "W/")
+	raw = strings.Trim(raw, `"`)
+	v, err := strconv.Atoi(raw)
+	if err != nil || v <= 0 {
+		return 0, false
+	}
+	return v, true
+}
+
+// taskWithEntity is the GET /tasks/:id?expand=entity response: the task plus
+// a short summary of the lead/deal/client it is linked to.
+type taskWithEntity struct {
+	*models.Task
+	Entity *taskEntitySummary `json:"entity,omitempty"`
+}
+
+type taskEntitySummary struct {
+	Type     string   `json:"type"`
+	ID       int64    `json:"id"`
+	Title    string   `json:"title,omitempty"`
+	Status   string   `json:"status,omitempty"`
+	Amount   *float64 `json:"amount,omitempty"`
+	Currency string   `json:"currency,omitempty"`
+}
+
+// resolveTaskEntity loads the task's linked entity through the scoped
+// services. Unknown types, missing entities and entities the viewer may not
+// see all yield nil, so the task is returned without expansion.
+func (h *TaskHandler) resolveTaskEntity(ctx context.Context, task *models.Task, userID, roleID int) *taskEntitySummary {
+	if task.EntityID <= 0 {
+		return nil
+	}
+	id := int(task.EntityID)
+	switch strings.ToLower(strings.TrimSpace(task.EntityType)) {
+	case "lead", "leads":
+		if h.leads == nil {
+			return nil
+		}
+		lead, err := h.leads.GetByID(ctx, id, userID, roleID)
+		if err != nil || lead == nil {
+			return nil
+		}
+		return &taskEntitySummary{Type: "lead", ID: task.EntityID, Title: lead.Title, Status: lead.Status}
+	case "deal", "deals":
+		if h.deals == nil {
+			return nil
+		}
+		deal, err := h.deals.GetByID(ctx, id, userID, roleID)
+		if err != nil || deal == nil {
+			return nil
+		}
+		amount := deal.Amount
+		return &taskEntitySummary{Type: "deal", ID: task.EntityID, Status: deal.Status, Amount: &amount, Currency: deal.Currency}
+	case "client", "clients":
+		if h.clients == nil {
+			return nil
+		}
+		client, err := h.clients.GetByID(ctx, id, userID, roleID)
+		if err != nil || client == nil {
+			return nil
+		}
+		return &taskEntitySummary{Type: "client", ID: task.EntityID, Title: client.Name}
+	case "document", "documents":
+		if h.documents == nil {
+			return nil
+		}
+		doc, err := h.documents.GetDocument(ctx, task.EntityID, userID, roleID)
+		if err != nil || doc == nil {
+			return nil
+		}
+		return &taskEntitySummary{Type: "document", ID: task.EntityID, Title: doc.DocType, Status: doc.Status}
+	default:
+		return nil
 	}
 }
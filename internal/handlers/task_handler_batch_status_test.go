@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+	"turcompany/internal/repositories"
+	"turcompany/internal/services"
+)
+
+// taskBatchServiceStub serves tasks by id and applies batch changes, failing
+// the ids listed in conflicts the way the repository reports a lost race.
+type taskBatchServiceStub struct {
+	taskBranchServiceStub
+	tasks     map[int64]*models.Task
+	conflicts map[int64]bool
+	applied   []repositories.TaskStatusChange
+}
+
+func (s *taskBatchServiceStub) GetByID(_ context.Context, id int64) (*models.Task, error) {
+	if t, ok := s.tasks[id]; ok {
+		cp := *t
+		return &cp, nil
+	}
+	return nil, nil
+}
+
+func (s *taskBatchServiceStub) UpdateStatusBatch(_ context.Context, changes []repositories.TaskStatusChange, to models.TaskStatus, _ int64) ([]*models.Task, map[int64]error, error) {
+	s.applied = append(s.applied, changes...)
+	failed := map[int64]error{}
+	var updated []*models.Task
+	for _, ch := range changes {
+		if s.conflicts[ch.ID] {
+			failed[ch.ID] = repositories.ErrTaskVersionConflict
+			continue
+		}
+		s.tasks[ch.ID].Status = to
+		cp := *s.tasks[ch.ID]
+		updated = append(updated, &cp)
+	}
+	return updated, failed, nil
+}
+
+type taskEventRecorder struct{ events []string }
+
+func (r *taskEventRecorder) Publish(event string, data any) {
+	r.events = append(r.events, event)
+}
+
+func TestTaskHandler_BatchStatus_ReportsPerItem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	branch := int64(1)
+	task := func(id, owner int64, st models.TaskStatus) *models.Task {
+		return &models.Task{ID: id, CreatorID: owner, AssigneeID: owner, BranchID: &branch, Status: st}
+	}
+	svc := &taskBatchServiceStub{
+		tasks: map[int64]*models.Task{
+			1: task(1, 10, models.StatusInProgress), // legal
+			2: task(2, 10, models.StatusNew),        // new -> done is illegal
+			3: task(3, 11, models.StatusInProgress), // someone else's task
+			5: task(5, 10, models.StatusInProgress), // loses a race on save
+			6: task(6, 10, models.StatusDone),       // already done: no-op success
+		},
+		conflicts: map[int64]bool{5: true},
+	}
+	users := &taskBranchUserRepoStub{users: map[int]*models.User{10: {ID: 10, BranchID: ptrInt(1)}}}
+	events := &taskEventRecorder{}
+	h := NewTaskHandler(svc, nil, users)
+	h.SetEventPublisher(events)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/tasks/batch-status",
+		strings.NewReader(`{"ids":[1,2,3,4,5,6,1],"to":"done","comment":"sprint closed"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("user_id", 10)
+	c.Set("role_id", authz.RoleSales)
+
+	h.BatchStatus(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Results  []taskBatchResult `json:"results"`
+		Updated  int               `json:"updated"`
+		Rejected int               `json:"rejected"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	want := []taskBatchResult{
+		{ID: 1, OK: true, Status: models.StatusDone},
+		{ID: 2, Reason: "illegal_transition", Status: models.StatusNew},
+		{ID: 3, Reason: "forbidden"},
+		{ID: 4, Reason: "not_found"},
+		{ID: 5, Reason: "conflict"},
+		{ID: 6, OK: true, Status: models.StatusDone},
+	}
+	if len(resp.Results) != len(want) {
+		t.Fatalf("expected %d results, got %+v", len(want), resp.Results)
+	}
+	for i := range want {
+		if resp.Results[i] != want[i] {
+			t.Fatalf("result %d: got %+v want %+v", i, resp.Results[i], want[i])
+		}
+	}
+	if resp.Updated != 2 || resp.Rejected != 4 {
+		t.Fatalf("expected updated=2 rejected=4, got %d/%d", resp.Updated, resp.Rejected)
+	}
+
+	// Only validated transitions reach the store, each once.
+	if len(svc.applied) != 3 || svc.applied[0].ID != 1 || svc.applied[1].ID != 5 || svc.applied[2].ID != 6 {
+		t.Fatalf("unexpected applied changes: %+v", svc.applied)
+	}
+	if svc.tasks[2].Status != models.StatusNew || svc.tasks[3].Status != models.StatusInProgress {
+		t.Fatal("rejected tasks must keep their status")
+	}
+	// Events fire after the save, only for tasks whose status actually changed.
+	if len(events.events) != 1 {
+		t.Fatalf("expected one status event, got %v", events.events)
+	}
+}
+
+func TestTaskHandler_BatchStatus_ValidatesPayload(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewTaskHandler(&taskBatchServiceStub{tasks: map[int64]*models.Task{}}, nil, &taskBranchUserRepoStub{})
+
+	for _, body := range []string{`{"ids":[],"to":"done"}`, `{"ids":[1],"to":"archived"}`, `not json`} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/tasks/batch-status", strings.NewReader(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set("user_id", 10)
+		c.Set("role_id", authz.RoleManagement)
+		h.BatchStatus(c)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("body %q: expected 400, got %d", body, w.Code)
+		}
+	}
+}
+
+// batchTaskRepo is a task store keyed by id that applies batch changes.
+type batchTaskRepo struct {
+	repositories.TaskRepository
+	tasks map[int64]models.Task
+}
+
+func (r *batchTaskRepo) FindByID(_ context.Context, id int64) (*models.Task, error) {
+	if t, ok := r.tasks[id]; ok {
+		return &t, nil
+	}
+	return nil, nil
+}
+
+func (r *batchTaskRepo) UpdateStatusBatch(_ context.Context, changes []repositories.TaskStatusChange, to models.TaskStatus, _ int64) (map[int64]error, error) {
+	for _, ch := range changes {
+		t := r.tasks[ch.ID]
+		t.Status = to
+		r.tasks[ch.ID] = t
+	}
+	return map[int64]error{}, nil
+}
+
+func TestTaskHandler_BatchStatus_NotifiesEachAssigneeOncePerTask(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &batchTaskRepo{tasks: map[int64]models.Task{
+		1: {ID: 1, CreatorID: 10, AssigneeID: 10, AssigneeIDs: []int64{10, 11}, Status: models.StatusInProgress},
+		2: {ID: 2, CreatorID: 10, AssigneeID: 10, AssigneeIDs: []int64{10, 12}, Status: models.StatusInProgress},
+	}}
+	tg := &recordingNotifier{}
+	h := NewTaskHandler(services.NewTaskService(repo, nil), nil, &telegramSettingsUserRepo{})
+	h.tg = tg
+	h.dispatch = func(f func()) { f() }
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/tasks/batch-status", strings.NewReader(`{"ids":[1,2],"to":"done"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("user_id", 10)
+	c.Set("role_id", authz.RoleManagement)
+	h.BatchStatus(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	got := map[int64]int{}
+	for _, chat := range tg.chats {
+		got[chat]++
+	}
+	want := map[int64]int{1010: 2, 1011: 1, 1012: 1}
+	if len(got) != len(want) {
+		t.Fatalf("expected notifications %v, got %v", want, got)
+	}
+	for chat, n := range want {
+		if got[chat] != n {
+			t.Fatalf("expected notifications %v, got %v", want, got)
+		}
+	}
+}
@@ -45,13 +45,17 @@ func (s *taskBranchServiceStub) ArchiveTask(context.Context, int64, int64, int,
 func (s *taskBranchServiceStub) UnarchiveTask(context.Context, int64, int64, int) (*models.Task, error) {
 	return s.task, nil
 }
-func (s *taskBranchServiceStub) UpdateStatus(context.Context, int64, models.TaskStatus) (*models.Task, error) {
+func (s *taskBranchServiceStub) UpdateStatus(context.Context, int64, models.TaskStatus, int64) (*models.Task, error) {
 	s.updateStatusCall++
 	return s.task, nil
 }
-func (s *taskBranchServiceStub) UpdateAssignee(context.Context, int64, int64) (*models.Task, error) {
+func (s *taskBranchServiceStub) UpdateStatusBatch(context.Context, []repositories.TaskStatusChange, models.TaskStatus, int64) ([]*models.Task, map[int64]error, error) {
+	return nil, nil, nil
+}
+func (s *taskBranchServiceStub) UpdateAssignee(context.Context, int64, int64, int64) (*models.Task, error) {
 	return s.task, nil
 }
+func (s *taskBranchServiceStub) ReassignOpen(context.Context, int64, int64) ([]int64, error) { return nil, nil }
 
 type taskBranchUserRepoStub struct {
 	users map[int]*models.User
@@ -70,6 +74,11 @@ func (r *taskBranchUserRepoStub) GetByID(id int) (*models.User, error) {
 }
 func (r *taskBranchUserRepoStub) Update(*models.User) error                   { return nil }
 func (r *taskBranchUserRepoStub) Delete(int) error                            { return nil }
+func (r *taskBranchUserRepoStub) Reactivate(int) error { return nil }
+func (r *taskBranchUserRepoStub) Search(repositories.UserSearchFilter, int, int) ([]*models.User, error) {
+	return nil, nil
+}
+func (r *taskBranchUserRepoStub) CountSearch(repositories.UserSearchFilter) (int, error) { return 0, nil }
 func (r *taskBranchUserRepoStub) List(int, int) ([]*models.User, error)       { return nil, nil }
 func (r *taskBranchUserRepoStub) GetByEmail(string) (*models.User, error)     { return nil, nil }
 func (r *taskBranchUserRepoStub) GetAuthByEmail(string) (*models.User, error) { return nil, nil }
@@ -146,3 +155,36 @@ func TestTaskHandler_ChangeStatus_VisaAllowedForOwnBranch(t *testing.T) {
 }
 
 func ptrInt(v int) *int { return &v }
+
+func TestTaskHandler_GetByID_SalesOnlyOwnTasks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	branch := int64(1)
+	users := &taskBranchUserRepoStub{users: map[int]*models.User{10: {ID: 10, BranchID: ptrInt(1)}}}
+
+	cases := []struct {
+		name string
+		task *models.Task
+		want int
+	}{
+		{"other rep's task", &models.Task{ID: 1, CreatorID: 11, AssigneeID: 11, BranchID: &branch}, http.StatusForbidden},
+		{"created by self", &models.Task{ID: 2, CreatorID: 10, AssigneeID: 11, BranchID: &branch}, http.StatusOK},
+		{"co-assignee", &models.Task{ID: 3, CreatorID: 11, AssigneeID: 11, AssigneeIDs: []int64{11, 10}, BranchID: &branch}, http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewTaskHandler(&taskBranchServiceStub{task: tc.task}, nil, users)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/tasks/1", nil)
+			c.Params = gin.Params{{Key: "id", Value: "1"}}
+			c.Set("user_id", 10)
+			c.Set("role_id", authz.RoleSales)
+
+			h.GetByID(c)
+
+			if w.Code != tc.want {
+				t.Fatalf("expected %d, got %d body=%s", tc.want, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+	"turcompany/internal/repositories"
+)
+
+// dealDocumentsStub serves documents per deal and records the archive scope.
+type dealDocumentsStub struct {
+	docs  map[int64][]*models.Document
+	scope repositories.ArchiveScope
+}
+
+func (s *dealDocumentsStub) GetDocument(_ context.Context, id int64, _, _ int) (*models.Document, error) {
+	for _, docs := range s.docs {
+		for _, d := range docs {
+			if d.ID == id {
+				return d, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (s *dealDocumentsStub) ListDocumentsByDealWithFilter(_ context.Context, dealID int64, _, _ int, _ repositories.DocumentListFilter, scope repositories.ArchiveScope) ([]*models.Document, error) {
+	s.scope = scope
+	return s.docs[dealID], nil
+}
+
+func newDealDetailHandler() *TaskHandler {
+	h := newDealTasksHandler()
+	branch := int64(1)
+	svc := h.service.(*taskListScopeServiceStub)
+	// a task on deal 5 between two other users: Sales rep 10 must not see it
+	svc.tasks = append(svc.tasks, models.Task{ID: 6, CreatorID: 20, AssigneeID: 21, BranchID: &branch, EntityType: "deal", EntityID: 5, Status: models.StatusInProgress})
+	h.SetDocumentResolver(&dealDocumentsStub{docs: map[int64][]*models.Document{
+		5: {{ID: 50, DealID: 5, Status: "draft"}, {ID: 51, DealID: 5, Status: "signed"}},
+		7: {{ID: 70, DealID: 7, Status: "draft"}},
+	}})
+	return h
+}
+
+func getDealDetail(h *TaskHandler, dealID string, userID, roleID int) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/deals/"+dealID+"/detail", nil)
+	c.Params = gin.Params{{Key: "id", Value: dealID}}
+	c.Set("user_id", userID)
+	c.Set("role_id", roleID)
+	h.DealDetail(c)
+	return w
+}
+
+type dealDetailBody struct {
+	Deal      *models.Deals      `json:"deal"`
+	Documents []*models.Document `json:"documents"`
+	Tasks     []models.Task      `json:"tasks"`
+	OpenTasks int                `json:"open_tasks"`
+}
+
+func decodeDealDetail(t *testing.T, w *httptest.ResponseRecorder) dealDetailBody {
+	t.Helper()
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	var body dealDetailBody
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return body
+}
+
+func taskIDs(tasks []models.Task) map[int64]bool {
+	ids := make(map[int64]bool, len(tasks))
+	for _, task := range tasks {
+		ids[task.ID] = true
+	}
+	return ids
+}
+
+func TestTaskHandler_DealDetail_CombinesDealDocumentsAndTasks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := newDealDetailHandler()
+
+	body := decodeDealDetail(t, getDealDetail(h, "5", 20, authz.RoleManagement))
+	if body.Deal == nil || body.Deal.ID != 5 {
+		t.Fatalf("expected deal 5, got %+v", body.Deal)
+	}
+	if len(body.Documents) != 2 || body.Documents[0].ID != 50 || body.Documents[1].ID != 51 {
+		t.Fatalf("expected documents 50 and 51, got %+v", body.Documents)
+	}
+	ids := taskIDs(body.Tasks)
+	if len(body.Tasks) != 3 || !ids[1] || !ids[2] || !ids[6] {
+		t.Fatalf("expected tasks 1, 2 and 6 of deal 5, got %+v", body.Tasks)
+	}
+	if body.OpenTasks != 2 {
+		t.Fatalf("expected 2 open tasks, got %d", body.OpenTasks)
+	}
+	if scope := h.documents.(*dealDocumentsStub).scope; scope != repositories.ArchiveScopeActiveOnly {
+		t.Fatalf("expected active documents only, got scope %v", scope)
+	}
+}
+
+func TestTaskHandler_DealDetail_SalesScoping(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := newDealDetailHandler()
+
+	body := decodeDealDetail(t, getDealDetail(h, "5", 10, authz.RoleSales))
+	if body.Deal == nil || body.Deal.ID != 5 || len(body.Documents) != 2 {
+		t.Fatalf("expected own deal with its documents, got %+v", body)
+	}
+	ids := taskIDs(body.Tasks)
+	if len(body.Tasks) != 2 || !ids[1] || !ids[2] {
+		t.Fatalf("expected only the rep's own tasks 1 and 2, got %+v", body.Tasks)
+	}
+
+	if w := getDealDetail(h, "7", 10, authz.RoleSales); w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 on another rep's deal, got %d body=%s", w.Code, w.Body.String())
+	}
+	if w := getDealDetail(h, "99", 10, authz.RoleSales); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for missing deal, got %d", w.Code)
+	}
+}
+
+func TestTaskHandler_DealDetail_EmptyListsWithoutResolvers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := newDealTasksHandler()
+
+	w := getDealDetail(h, "5", 20, authz.RoleManagement)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(w.Body.Bytes(), &raw); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if string(raw["documents"]) != "[]" {
+		t.Fatalf("expected documents to be an empty list, got %s", raw["documents"])
+	}
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+	"turcompany/internal/services"
+)
+
+// scopedDocumentStub hides hidden documents from everyone but admins, like
+// DocumentService.GetDocument.
+type scopedDocumentStub struct {
+	docs map[int64]*models.Document
+}
+
+func (s *scopedDocumentStub) GetDocument(_ context.Context, id int64, _ int, roleID int) (*models.Document, error) {
+	d := s.docs[id]
+	if d == nil {
+		return nil, services.ErrNotFound
+	}
+	if d.IsHidden && roleID != authz.RoleSystemAdmin {
+		return nil, services.ErrForbidden
+	}
+	return d, nil
+}
+
+func newDocumentTasksHandler() *TaskHandler {
+	branch := int64(1)
+	svc := &taskListScopeServiceStub{tasks: []models.Task{
+		{ID: 1, CreatorID: 10, AssigneeID: 10, BranchID: &branch, EntityType: "document", EntityID: 8, Status: models.StatusNew},
+		{ID: 2, CreatorID: 10, AssigneeID: 10, BranchID: &branch, EntityType: "document", EntityID: 9, Status: models.StatusNew},
+		{ID: 3, CreatorID: 10, AssigneeID: 10, BranchID: &branch, EntityType: "deal", EntityID: 8, Status: models.StatusNew},
+	}}
+	h := NewTaskHandler(svc, nil, &taskBranchUserRepoStub{})
+	h.SetDocumentResolver(&scopedDocumentStub{docs: map[int64]*models.Document{
+		8: {ID: 8, DocType: "contract", Status: "under_review"},
+		9: {ID: 9, DocType: "contract", Status: "draft", IsHidden: true},
+	}})
+	return h
+}
+
+func getDocumentTasks(h *TaskHandler, docID string, roleID int) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/documents/"+docID+"/tasks", nil)
+	c.Params = gin.Params{{Key: "id", Value: docID}}
+	c.Set("user_id", 20)
+	c.Set("role_id", roleID)
+	h.ListForDocument(c)
+	return w
+}
+
+func TestTaskHandler_ListForDocument_RespectsDocumentAccess(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := newDocumentTasksHandler()
+
+	w := getDocumentTasks(h, "8", authz.RoleManagement)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	var body struct {
+		Items []models.Task `json:"items"`
+		Total int           `json:"total"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.Total != 1 || len(body.Items) != 1 || body.Items[0].ID != 1 {
+		t.Fatalf("expected only task 1 for document 8, got %+v", body.Items)
+	}
+
+	if w := getDocumentTasks(h, "9", authz.RoleManagement); w.Code != http.StatusForbidden {
+		t.Fatalf("hidden document: expected 403, got %d", w.Code)
+	}
+	if w := getDocumentTasks(h, "9", authz.RoleSystemAdmin); w.Code != http.StatusOK {
+		t.Fatalf("hidden document, admin: expected 200, got %d", w.Code)
+	}
+	if w := getDocumentTasks(h, "99", authz.RoleManagement); w.Code != http.StatusNotFound {
+		t.Fatalf("missing document: expected 404, got %d", w.Code)
+	}
+}
+
+func TestTaskHandler_GetByID_ExpandsLinkedDocument(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	expand := func(docID int64, roleID int) *taskEntitySummary {
+		t.Helper()
+		svc := &taskBranchServiceStub{task: &models.Task{ID: 5, CreatorID: 1, AssigneeID: 1, EntityType: "document", EntityID: docID}}
+		h := NewTaskHandler(svc, nil, &taskBranchUserRepoStub{})
+		h.SetDocumentResolver(&scopedDocumentStub{docs: map[int64]*models.Document{
+			8: {ID: 8, DocType: "contract", Status: "under_review"},
+			9: {ID: 9, DocType: "contract", Status: "draft", IsHidden: true},
+		}})
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/tasks/5?expand=entity", nil)
+		c.Params = gin.Params{{Key: "id", Value: "5"}}
+		c.Set("user_id", 1)
+		c.Set("role_id", roleID)
+		h.GetByID(c)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+		}
+		var body struct {
+			Entity *taskEntitySummary `json:"entity"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return body.Entity
+	}
+
+	got := expand(8, authz.RoleManagement)
+	if got == nil || got.Type != "document" || got.ID != 8 || got.Title != "contract" || got.Status != "under_review" {
+		t.Fatalf("expected document summary, got %+v", got)
+	}
+	if got := expand(9, authz.RoleManagement); got != nil {
+		t.Fatalf("hidden document must not be expanded for management, got %+v", got)
+	}
+	if got := expand(9, authz.RoleSystemAdmin); got == nil || got.ID != 9 {
+		t.Fatalf("admin should see hidden document, got %+v", got)
+	}
+}
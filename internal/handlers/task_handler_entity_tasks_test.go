@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+	"turcompany/internal/services"
+)
+
+// scopedDealStub allows a deal only to its owner (and to management).
+type scopedDealStub struct {
+	deals map[int]*models.Deals
+}
+
+func (s *scopedDealStub) GetByID(_ context.Context, id int, userID, roleID int) (*models.Deals, error) {
+	d := s.deals[id]
+	if d == nil {
+		return nil, nil
+	}
+	if roleID == authz.RoleSales && d.OwnerID != userID {
+		return nil, services.ErrForbidden
+	}
+	return d, nil
+}
+
+func newDealTasksHandler() *TaskHandler {
+	branch := int64(1)
+	svc := &taskListScopeServiceStub{tasks: []models.Task{
+		{ID: 1, CreatorID: 10, AssigneeID: 10, BranchID: &branch, EntityType: "deal", EntityID: 5, Status: models.StatusNew},
+		{ID: 2, CreatorID: 10, AssigneeID: 10, BranchID: &branch, EntityType: "deal", EntityID: 5, Status: models.StatusDone},
+		{ID: 3, CreatorID: 10, AssigneeID: 10, BranchID: &branch, EntityType: "deal", EntityID: 6, Status: models.StatusNew},
+		{ID: 4, CreatorID: 11, AssigneeID: 11, BranchID: &branch, EntityType: "deal", EntityID: 7, Status: models.StatusNew},
+		{ID: 5, CreatorID: 10, AssigneeID: 10, BranchID: &branch, EntityType: "lead", EntityID: 5, Status: models.StatusNew},
+	}}
+	users := &taskBranchUserRepoStub{users: map[int]*models.User{
+		10: {ID: 10, BranchID: ptrInt(1)},
+		20: {ID: 20, BranchID: ptrInt(1)},
+	}}
+	deals := &scopedDealStub{deals: map[int]*models.Deals{
+		5: {ID: 5, OwnerID: 10},
+		7: {ID: 7, OwnerID: 11},
+	}}
+	h := NewTaskHandler(svc, nil, users)
+	h.SetEntityResolvers(nil, deals, nil)
+	return h
+}
+
+func getDealTasks(h *TaskHandler, dealID string, userID, roleID int) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/deals/"+dealID+"/tasks", nil)
+	c.Params = gin.Params{{Key: "id", Value: dealID}}
+	c.Set("user_id", userID)
+	c.Set("role_id", roleID)
+	h.ListForDeal(c)
+	return w
+}
+
+func TestTaskHandler_ListForDeal_ReturnsLinkedTasksWithCounts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := newDealTasksHandler()
+
+	w := getDealTasks(h, "5", 20, authz.RoleManagement)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	var body struct {
+		Items []models.Task `json:"items"`
+		Open  int           `json:"open"`
+		Total int           `json:"total"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.Total != 2 || body.Open != 1 || len(body.Items) != 2 {
+		t.Fatalf("expected 2 tasks (1 open) for deal 5, got total=%d open=%d items=%d", body.Total, body.Open, len(body.Items))
+	}
+	for _, task := range body.Items {
+		if task.EntityType != "deal" || task.EntityID != 5 {
+			t.Fatalf("unexpected task in result: %+v", task)
+		}
+	}
+}
+
+func TestTaskHandler_ListForDeal_SalesDeniedOnOtherRepsDeal(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := newDealTasksHandler()
+
+	if w := getDealTasks(h, "7", 10, authz.RoleSales); w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 on another rep's deal, got %d body=%s", w.Code, w.Body.String())
+	}
+	if w := getDealTasks(h, "5", 10, authz.RoleSales); w.Code != http.StatusOK {
+		t.Fatalf("expected 200 on own deal, got %d body=%s", w.Code, w.Body.String())
+	}
+	if w := getDealTasks(h, "99", 10, authz.RoleSales); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for missing deal, got %d", w.Code)
+	}
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+)
+
+type taskExpandDealStub struct {
+	deals map[int]*models.Deals
+	calls int
+}
+
+func (s *taskExpandDealStub) GetByID(_ context.Context, id int, _ int, _ int) (*models.Deals, error) {
+	s.calls++
+	return s.deals[id], nil
+}
+
+func getTaskExpanded(h *TaskHandler) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/tasks/5?expand=entity", nil)
+	c.Params = gin.Params{{Key: "id", Value: "5"}}
+	c.Set("user_id", 1)
+	c.Set("role_id", authz.RoleSystemAdmin)
+	h.GetByID(c)
+	return w
+}
+
+func TestTaskHandler_GetByID_ExpandsLinkedDeal(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := &taskBranchServiceStub{task: &models.Task{ID: 5, CreatorID: 1, AssigneeID: 1, EntityType: "deal", EntityID: 42}}
+	deals := &taskExpandDealStub{deals: map[int]*models.Deals{42: {ID: 42, Amount: 1500, Currency: "KZT", Status: "new"}}}
+	h := NewTaskHandler(svc, nil, &taskBranchUserRepoStub{})
+	h.SetEntityResolvers(nil, deals, nil)
+
+	w := getTaskExpanded(h)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	var body struct {
+		ID     int64 `json:"id"`
+		Entity *struct {
+			Type     string   `json:"type"`
+			ID       int64    `json:"id"`
+			Amount   *float64 `json:"amount"`
+			Currency string   `json:"currency"`
+		} `json:"entity"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.ID != 5 {
+		t.Fatalf("expected task fields at top level, got id=%d", body.ID)
+	}
+	if body.Entity == nil || body.Entity.Type != "deal" || body.Entity.ID != 42 {
+		t.Fatalf("expected deal entity, got %+v", body.Entity)
+	}
+	if body.Entity.Amount == nil || *body.Entity.Amount != 1500 || body.Entity.Currency != "KZT" {
+		t.Fatalf("unexpected deal summary: %+v", body.Entity)
+	}
+}
+
+func TestTaskHandler_GetByID_ExpandWithoutLinkedEntity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := &taskBranchServiceStub{task: &models.Task{ID: 5, CreatorID: 1, AssigneeID: 1}}
+	deals := &taskExpandDealStub{}
+	h := NewTaskHandler(svc, nil, &taskBranchUserRepoStub{})
+	h.SetEntityResolvers(nil, deals, nil)
+
+	w := getTaskExpanded(h)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	var body map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if _, ok := body["entity"]; ok {
+		t.Fatalf("expected no entity key, got %s", w.Body.String())
+	}
+	if deals.calls != 0 {
+		t.Fatalf("expected no resolver calls, got %d", deals.calls)
+	}
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+	"turcompany/internal/services"
+)
+
+// lastModifiedTaskRepo extends versionedTaskRepo with the status write,
+// which records the acting user like the SQL does.
+type lastModifiedTaskRepo struct {
+	versionedTaskRepo
+}
+
+func (r *lastModifiedTaskRepo) UpdateStatus(_ context.Context, _ int64, to models.TaskStatus, actorID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.task.Status = to
+	r.task.LastModifiedBy = &actorID
+	r.task.Version++
+	return nil
+}
+
+func TestTaskHandler_UpdateRecordsLastModifier(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &lastModifiedTaskRepo{versionedTaskRepo{task: models.Task{ID: 7, CreatorID: 1, AssigneeID: 1, Title: "Call client", Status: models.StatusNew, Priority: models.PriorityNormal, Version: 1}}}
+	h := NewTaskHandler(services.NewTaskService(repo, nil), nil, nil)
+
+	// User 2 edits a task created by user 1.
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPut, "/tasks/7", strings.NewReader(`{"title":"Call client today"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: "7"}}
+	c.Set("user_id", 2)
+	c.Set("role_id", authz.RoleSystemAdmin)
+	h.Update(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	if repo.task.LastModifiedBy == nil || *repo.task.LastModifiedBy != 2 {
+		t.Fatalf("expected last_modified_by=2, got %v", repo.task.LastModifiedBy)
+	}
+	if repo.task.CreatorID != 1 {
+		t.Fatalf("creator must stay 1, got %d", repo.task.CreatorID)
+	}
+	var resp struct {
+		LastModifiedBy *int64 `json:"last_modified_by"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.LastModifiedBy == nil || *resp.LastModifiedBy != 2 {
+		t.Fatalf("expected last_modified_by=2 in response, got %s", w.Body.String())
+	}
+
+	// A status change by user 3 moves the marker on.
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/tasks/7/status", strings.NewReader(`{"to":"in_progress"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: "7"}}
+	c.Set("user_id", 3)
+	c.Set("role_id", authz.RoleSystemAdmin)
+	h.ChangeStatus(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	if repo.task.LastModifiedBy == nil || *repo.task.LastModifiedBy != 3 {
+		t.Fatalf("expected last_modified_by=3 after status change, got %v", repo.task.LastModifiedBy)
+	}
+}
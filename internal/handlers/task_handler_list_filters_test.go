@@ -71,10 +71,16 @@ func (s *stubTaskListService) ArchiveTask(context.Context, int64, int64, int, st
 func (s *stubTaskListService) UnarchiveTask(context.Context, int64, int64, int) (*models.Task, error) {
 	return nil, nil
 }
-func (s *stubTaskListService) UpdateStatus(context.Context, int64, models.TaskStatus) (*models.Task, error) {
+func (s *stubTaskListService) UpdateStatus(context.Context, int64, models.TaskStatus, int64) (*models.Task, error) {
 	return nil, nil
 }
-func (s *stubTaskListService) UpdateAssignee(context.Context, int64, int64) (*models.Task, error) {
+func (s *stubTaskListService) UpdateStatusBatch(context.Context, []repositories.TaskStatusChange, models.TaskStatus, int64) ([]*models.Task, map[int64]error, error) {
+	return nil, nil, nil
+}
+func (s *stubTaskListService) UpdateAssignee(context.Context, int64, int64, int64) (*models.Task, error) {
+	return nil, nil
+}
+func (s *stubTaskListService) ReassignOpen(context.Context, int64, int64) ([]int64, error) {
 	return nil, nil
 }
 
@@ -104,7 +110,7 @@ func TestTaskHandler_GetAll_ForwardsExtendedFilters(t *testing.T) {
 	}
 }
 
-func TestTaskHandler_GetAll_SalesSeesBranchTasksNotOwnOnly(t *testing.T) {
+func TestTaskHandler_GetAll_SalesFiltersStayWithinOwnTasks(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc := &stubTaskListService{}
 	h := NewTaskHandler(svc, nil, &taskBranchUserRepoStub{users: map[int]*models.User{42: {ID: 42, BranchID: ptrInt(1)}}})
@@ -119,10 +125,13 @@ func TestTaskHandler_GetAll_SalesSeesBranchTasksNotOwnOnly(t *testing.T) {
 	if w.Code != http.StatusOK {
 		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
 	}
-	// Sales role must NOT be restricted to own assignee — they see all tasks in branch.
+	// The requested assignee filter is kept, but only narrows the caller's own tasks.
 	if svc.lastFilter.AssigneeID == nil || *svc.lastFilter.AssigneeID != 123 {
 		t.Fatalf("sales assignee filter must pass through as-is, got %+v", svc.lastFilter.AssigneeID)
 	}
+	if svc.lastFilter.ParticipantID == nil || *svc.lastFilter.ParticipantID != 42 {
+		t.Fatalf("expected sales scoped to own tasks, got %+v", svc.lastFilter.ParticipantID)
+	}
 	if svc.lastFilter.BranchID == nil || *svc.lastFilter.BranchID != 1 {
 		t.Fatalf("expected branch forced to 1, got %+v", svc.lastFilter.BranchID)
 	}
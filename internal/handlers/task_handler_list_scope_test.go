@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+)
+
+// taskListScopeServiceStub applies the participant, branch, entity, status and
+// overdue filters the way the repository does, over a fixed set of tasks.
+type taskListScopeServiceStub struct {
+	taskBranchServiceStub
+	tasks []models.Task
+}
+
+func (s *taskListScopeServiceStub) GetAll(_ context.Context, f models.TaskFilter) ([]models.Task, error) {
+	var out []models.Task
+	for _, t := range s.tasks {
+		if f.BranchID != nil && (t.BranchID == nil || *t.BranchID != *f.BranchID) {
+			continue
+		}
+		if f.AssigneeID != nil && t.AssigneeID != *f.AssigneeID {
+			continue
+		}
+		if f.CreatorID != nil && t.CreatorID != *f.CreatorID {
+			continue
+		}
+		if len(f.Statuses) > 0 && !slices.Contains(f.Statuses, t.Status) {
+			continue
+		}
+		if f.Priority != nil && t.Priority != *f.Priority {
+			continue
+		}
+		if f.EntityType != nil && (t.EntityType != *f.EntityType || f.EntityID == nil || t.EntityID != *f.EntityID) {
+			continue
+		}
+		if f.ParticipantID != nil && t.CreatorID != *f.ParticipantID && t.AssigneeID != *f.ParticipantID {
+			continue
+		}
+		if f.Overdue != nil && *f.Overdue != t.Overdue(time.Now()) {
+			continue
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+func listTaskIDs(t *testing.T, h *TaskHandler, userID, roleID int, query string) []int64 {
+	t.Helper()
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/tasks"+query, nil)
+	c.Set("user_id", userID)
+	c.Set("role_id", roleID)
+	h.GetAll(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	var tasks []models.Task
+	if err := json.Unmarshal(w.Body.Bytes(), &tasks); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	ids := make([]int64, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func TestTaskHandler_GetAll_SalesScopedToOwnTasks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	branch := int64(1)
+	svc := &taskListScopeServiceStub{tasks: []models.Task{
+		{ID: 1, CreatorID: 10, AssigneeID: 10, BranchID: &branch},
+		{ID: 2, CreatorID: 11, AssigneeID: 10, BranchID: &branch},
+		{ID: 3, CreatorID: 10, AssigneeID: 11, BranchID: &branch},
+		{ID: 4, CreatorID: 11, AssigneeID: 11, BranchID: &branch},
+	}}
+	users := &taskBranchUserRepoStub{users: map[int]*models.User{
+		10: {ID: 10, BranchID: ptrInt(1)},
+		20: {ID: 20, BranchID: ptrInt(1)},
+	}}
+	h := NewTaskHandler(svc, nil, users)
+
+	manager := listTaskIDs(t, h, 20, authz.RoleManagement, "")
+	if len(manager) != 4 {
+		t.Fatalf("manager expected all 4 tasks, got %v", manager)
+	}
+
+	sales := listTaskIDs(t, h, 10, authz.RoleSales, "")
+	if len(sales) != 3 || sales[0] != 1 || sales[1] != 2 || sales[2] != 3 {
+		t.Fatalf("sales expected own tasks [1 2 3], got %v", sales)
+	}
+
+	// Asking for a colleague's tasks does not widen the scope.
+	other := listTaskIDs(t, h, 10, authz.RoleSales, "?assignee_id=11")
+	if len(other) != 1 || other[0] != 3 {
+		t.Fatalf("sales expected only task 3 for assignee_id=11, got %v", other)
+	}
+}
+
+func TestTaskHandler_GetAll_MineReturnsCallerAssignments(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	branch := int64(1)
+	svc := &taskListScopeServiceStub{tasks: []models.Task{
+		{ID: 1, CreatorID: 10, AssigneeID: 20, BranchID: &branch},
+		{ID: 2, CreatorID: 20, AssigneeID: 10, BranchID: &branch},
+		{ID: 3, CreatorID: 20, AssigneeID: 20, BranchID: &branch, EntityType: "deal", EntityID: 5},
+		{ID: 4, CreatorID: 11, AssigneeID: 11, BranchID: &branch},
+	}}
+	users := &taskBranchUserRepoStub{users: map[int]*models.User{
+		10: {ID: 10, BranchID: ptrInt(1)},
+		20: {ID: 20, BranchID: ptrInt(1)},
+	}}
+	h := NewTaskHandler(svc, nil, users)
+
+	// Even for management mine=true means the caller's own assignments.
+	mine := listTaskIDs(t, h, 20, authz.RoleManagement, "?mine=true")
+	if len(mine) != 2 || mine[0] != 1 || mine[1] != 3 {
+		t.Fatalf("manager mine=true expected [1 3], got %v", mine)
+	}
+	combined := listTaskIDs(t, h, 20, authz.RoleManagement, "?mine=true&entity_type=deal&entity_id=5")
+	if len(combined) != 1 || combined[0] != 3 {
+		t.Fatalf("mine=true with entity filter expected [3], got %v", combined)
+	}
+	sales := listTaskIDs(t, h, 10, authz.RoleSales, "?mine=1")
+	if len(sales) != 1 || sales[0] != 2 {
+		t.Fatalf("sales mine=true expected only assigned task [2], got %v", sales)
+	}
+
+	for _, query := range []string{"?mine=maybe", "?mine=true&assignee_id=11"} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/tasks"+query, nil)
+		c.Set("user_id", 20)
+		c.Set("role_id", authz.RoleManagement)
+		h.GetAll(c)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestTaskHandler_GetAll_CreatorMeReturnsTasksCallerCreated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	branch := int64(1)
+	svc := &taskListScopeServiceStub{tasks: []models.Task{
+		{ID: 1, CreatorID: 20, AssigneeID: 10, BranchID: &branch, Status: models.StatusNew, Priority: models.PriorityHigh},
+		{ID: 2, CreatorID: 20, AssigneeID: 11, BranchID: &branch, Status: models.StatusInProgress, Priority: models.PriorityHigh},
+		{ID: 3, CreatorID: 20, AssigneeID: 20, BranchID: &branch, Status: models.StatusNew, Priority: models.PriorityLow},
+		{ID: 4, CreatorID: 10, AssigneeID: 20, BranchID: &branch, Status: models.StatusNew, Priority: models.PriorityHigh},
+		{ID: 5, CreatorID: 11, AssigneeID: 11, BranchID: &branch, Status: models.StatusNew, Priority: models.PriorityHigh},
+	}}
+	users := &taskBranchUserRepoStub{users: map[int]*models.User{
+		10: {ID: 10, BranchID: ptrInt(1)},
+		20: {ID: 20, BranchID: ptrInt(1)},
+	}}
+	h := NewTaskHandler(svc, nil, users)
+
+	// Delegated work shows up whoever the assignee is; task 4, merely assigned
+	// to the caller, does not.
+	created := listTaskIDs(t, h, 20, authz.RoleManagement, "?creator=me")
+	if len(created) != 3 || created[0] != 1 || created[1] != 2 || created[2] != 3 {
+		t.Fatalf("creator=me expected [1 2 3], got %v", created)
+	}
+	combined := listTaskIDs(t, h, 20, authz.RoleManagement, "?creator=me&status=new&priority=high")
+	if len(combined) != 1 || combined[0] != 1 {
+		t.Fatalf("creator=me with status/priority expected [1], got %v", combined)
+	}
+	sales := listTaskIDs(t, h, 10, authz.RoleSales, "?creator=ME")
+	if len(sales) != 1 || sales[0] != 4 {
+		t.Fatalf("sales creator=me expected [4], got %v", sales)
+	}
+
+	for _, query := range []string{"?creator=someone", "?creator=me&creator_id=11", "?priority=critical"} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/tasks"+query, nil)
+		c.Set("user_id", 20)
+		c.Set("role_id", authz.RoleManagement)
+		h.GetAll(c)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestTaskHandler_GetAll_MultipleStatuses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	branch := int64(1)
+	svc := &taskListScopeServiceStub{tasks: []models.Task{
+		{ID: 1, CreatorID: 20, AssigneeID: 20, BranchID: &branch, Status: models.StatusNew},
+		{ID: 2, CreatorID: 20, AssigneeID: 20, BranchID: &branch, Status: models.StatusInProgress},
+		{ID: 3, CreatorID: 20, AssigneeID: 20, BranchID: &branch, Status: models.StatusDone},
+		{ID: 4, CreatorID: 20, AssigneeID: 20, BranchID: &branch, Status: models.StatusCancelled},
+	}}
+	h := NewTaskHandler(svc, nil, &taskBranchUserRepoStub{users: map[int]*models.User{20: {ID: 20, BranchID: ptrInt(1)}}})
+
+	open := listTaskIDs(t, h, 20, authz.RoleManagement, "?status=new,in_progress")
+	if len(open) != 2 || open[0] != 1 || open[1] != 2 {
+		t.Fatalf("status=new,in_progress expected [1 2], got %v", open)
+	}
+	mixed := listTaskIDs(t, h, 20, authz.RoleManagement, "?status=%20DONE%20,new,done,")
+	if len(mixed) != 2 || mixed[0] != 1 || mixed[1] != 3 {
+		t.Fatalf("status=done,new expected [1 3], got %v", mixed)
+	}
+
+	for _, query := range []string{"?status=new,archived", "?status=,"} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/tasks"+query, nil)
+		c.Set("user_id", 20)
+		c.Set("role_id", authz.RoleManagement)
+		h.GetAll(c)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestTaskHandler_Agenda_ScopedToCallerOrManagedAssignee(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	branch := int64(1)
+	past := time.Now().Add(-72 * time.Hour)
+	svc := &taskListScopeServiceStub{tasks: []models.Task{
+		{ID: 1, CreatorID: 20, AssigneeID: 10, BranchID: &branch, Status: models.StatusNew, DueDate: &past},
+		{ID: 2, CreatorID: 20, AssigneeID: 10, BranchID: &branch, Status: models.StatusNew},
+		{ID: 3, CreatorID: 20, AssigneeID: 11, BranchID: &branch, Status: models.StatusNew},
+	}}
+	users := &taskBranchUserRepoStub{users: map[int]*models.User{
+		10: {ID: 10, BranchID: ptrInt(1)},
+		20: {ID: 20, BranchID: ptrInt(1)},
+	}}
+	h := NewTaskHandler(svc, nil, users)
+
+	agenda := func(userID, roleID int, query string) (*httptest.ResponseRecorder, map[string][]models.Task) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/tasks/agenda"+query, nil)
+		c.Set("user_id", userID)
+		c.Set("role_id", roleID)
+		h.Agenda(c)
+		var body map[string][]models.Task
+		_ = json.Unmarshal(w.Body.Bytes(), &body)
+		return w, body
+	}
+
+	w, body := agenda(10, authz.RoleSales, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	if len(body["overdue"]) != 1 || body["overdue"][0].ID != 1 || !body["overdue"][0].IsOverdue {
+		t.Fatalf("sales overdue bucket expected [1], got %+v", body["overdue"])
+	}
+	if len(body["later"]) != 1 || body["later"][0].ID != 2 {
+		t.Fatalf("sales later bucket expected [2], got %+v", body["later"])
+	}
+
+	if w, _ := agenda(10, authz.RoleSales, "?assignee_id=11"); w.Code != http.StatusForbidden {
+		t.Fatalf("sales must not read another agenda, got %d", w.Code)
+	}
+
+	w, body = agenda(20, authz.RoleManagement, "?assignee_id=11")
+	if w.Code != http.StatusOK || len(body["later"]) != 1 || body["later"][0].ID != 3 {
+		t.Fatalf("manager agenda for 11 expected later [3], got %d %s", w.Code, w.Body.String())
+	}
+}
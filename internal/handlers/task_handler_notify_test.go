@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+)
+
+// recordingNotifier captures the chats a task notification was sent to.
+type recordingNotifier struct {
+	chats []int64
+}
+
+func (n *recordingNotifier) SendMessage(chatID int64, _ string) error {
+	n.chats = append(n.chats, chatID)
+	return nil
+}
+func (n *recordingNotifier) FormatTaskNotification(*models.Task) string        { return "task" }
+func (n *recordingNotifier) FormatTaskDeletedNotification(*models.Task) string { return "deleted" }
+func (n *recordingNotifier) FormatTasksReassignedNotification(int, string) string {
+	return "reassigned"
+}
+
+// telegramSettingsUserRepo links every user to chat 1000+id; users in muted
+// have task notifications switched off.
+type telegramSettingsUserRepo struct {
+	taskBranchUserRepoStub
+	muted map[int64]bool
+}
+
+func (r *telegramSettingsUserRepo) GetTelegramSettings(_ context.Context, userID int64) (int64, bool, error) {
+	return 1000 + userID, !r.muted[userID], nil
+}
+
+func changeTaskStatus(t *testing.T, task *models.Task, to string, muted map[int64]bool) []int64 {
+	t.Helper()
+	tg := &recordingNotifier{}
+	h := NewTaskHandler(&taskBranchServiceStub{task: task}, nil, &telegramSettingsUserRepo{muted: muted})
+	h.tg = tg
+	h.dispatch = func(f func()) { f() }
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/tasks/1/status", strings.NewReader(`{"to":"`+to+`"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: "1"}}
+	c.Set("user_id", 20)
+	c.Set("role_id", authz.RoleManagement)
+	h.ChangeStatus(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	sort.Slice(tg.chats, func(i, j int) bool { return tg.chats[i] < tg.chats[j] })
+	return tg.chats
+}
+
+func TestTaskHandler_ChangeStatus_NotifiesCreatorOnCompletion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	delegated := &models.Task{ID: 1, CreatorID: 20, AssigneeID: 10, Status: models.StatusInProgress}
+	if got := changeTaskStatus(t, delegated, "done", nil); len(got) != 2 || got[0] != 1010 || got[1] != 1020 {
+		t.Fatalf("assignee and creator must both be notified on done, got chats %v", got)
+	}
+
+	cancelled := &models.Task{ID: 1, CreatorID: 20, AssigneeID: 10, Status: models.StatusInProgress}
+	if got := changeTaskStatus(t, cancelled, "cancelled", map[int64]bool{20: true}); len(got) != 1 || got[0] != 1010 {
+		t.Fatalf("creator with notifications off must be skipped, got chats %v", got)
+	}
+
+	own := &models.Task{ID: 1, CreatorID: 10, AssigneeID: 10, Status: models.StatusInProgress}
+	if got := changeTaskStatus(t, own, "done", nil); len(got) != 1 || got[0] != 1010 {
+		t.Fatalf("creator == assignee must get a single notification, got chats %v", got)
+	}
+
+	started := &models.Task{ID: 1, CreatorID: 20, AssigneeID: 10, Status: models.StatusNew}
+	if got := changeTaskStatus(t, started, "in_progress", nil); len(got) != 1 || got[0] != 1010 {
+		t.Fatalf("creator must not be notified while the task is open, got chats %v", got)
+	}
+}
+
+// telegramLinkUserRepo links only the users listed in chats.
+type telegramLinkUserRepo struct {
+	taskBranchUserRepoStub
+	chats map[int64]int64
+}
+
+func (r *telegramLinkUserRepo) GetTelegramSettings(_ context.Context, userID int64) (int64, bool, error) {
+	chat, ok := r.chats[userID]
+	return chat, ok, nil
+}
+
+type taskEmailSettingsStub map[int64]string // opted-in users and their emails
+
+func (s taskEmailSettingsStub) GetTaskEmailSettings(_ context.Context, userID int64) (string, bool, error) {
+	email, ok := s[userID]
+	return email, ok, nil
+}
+
+type recordingTaskMailer struct {
+	sent []string
+}
+
+func (m *recordingTaskMailer) SendTaskEmail(email string, _ *models.Task, headline string) error {
+	m.sent = append(m.sent, email+"|"+headline)
+	return nil
+}
+
+func TestTaskHandler_ChangeStatus_EmailsOptedInUsersWithoutTelegram(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// 10 uses Telegram and has opted into email too; 11 has no Telegram and
+	// opted in; 12 has neither.
+	task := &models.Task{ID: 1, CreatorID: 20, AssigneeID: 10, AssigneeIDs: []int64{10, 11, 12}, Status: models.StatusNew}
+	tg := &recordingNotifier{}
+	mail := &recordingTaskMailer{}
+	h := NewTaskHandler(&taskBranchServiceStub{task: task}, nil, &telegramLinkUserRepo{chats: map[int64]int64{10: 1010}})
+	h.tg = tg
+	h.dispatch = func(f func()) { f() }
+	h.SetEmailNotifier(mail, taskEmailSettingsStub{10: "tg@example.com", 11: "mail@example.com"})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/tasks/1/status", strings.NewReader(`{"to":"in_progress"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: "1"}}
+	c.Set("user_id", 20)
+	c.Set("role_id", authz.RoleManagement)
+	h.ChangeStatus(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+
+	if len(tg.chats) != 1 || tg.chats[0] != 1010 {
+		t.Fatalf("expected a single Telegram message to user 10, got %v", tg.chats)
+	}
+	if len(mail.sent) != 1 || !strings.HasPrefix(mail.sent[0], "mail@example.com|") {
+		t.Fatalf("expected one email to user 11 only, got %v", mail.sent)
+	}
+}
+
+// blockingNotifier holds every SendMessage until release is closed, like a
+// Bot API answering 429 with a long Retry-After.
+type blockingNotifier struct {
+	recordingNotifier
+	release chan struct{}
+	sent    chan int64
+}
+
+func (n *blockingNotifier) SendMessage(chatID int64, _ string) error {
+	<-n.release
+	n.sent <- chatID
+	return nil
+}
+
+func TestTaskHandler_ChangeStatus_DoesNotWaitForTelegram(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	task := &models.Task{ID: 1, CreatorID: 20, AssigneeID: 10, Status: models.StatusNew}
+	tg := &blockingNotifier{release: make(chan struct{}), sent: make(chan int64, 1)}
+	h := NewTaskHandler(&taskBranchServiceStub{task: task}, nil, &telegramSettingsUserRepo{})
+	h.tg = tg
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/tasks/1/status", strings.NewReader(`{"to":"in_progress"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: "1"}}
+	c.Set("user_id", 20)
+	c.Set("role_id", authz.RoleManagement)
+
+	done := make(chan struct{})
+	go func() {
+		h.ChangeStatus(c)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		close(tg.release)
+		t.Fatal("status change waited for the Telegram send")
+	}
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+
+	close(tg.release)
+	select {
+	case chat := <-tg.sent:
+		if chat != 1010 {
+			t.Fatalf("expected the assignee's chat 1010, got %d", chat)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("notification was not sent in the background")
+	}
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+)
+
+func overdueTestHandler() *TaskHandler {
+	past := time.Now().Add(-48 * time.Hour)
+	future := time.Now().Add(48 * time.Hour)
+	branch := int64(1)
+	svc := &taskListScopeServiceStub{tasks: []models.Task{
+		{ID: 1, CreatorID: 10, AssigneeID: 10, BranchID: &branch, Status: models.StatusNew, DueDate: &past},
+		{ID: 2, CreatorID: 10, AssigneeID: 10, BranchID: &branch, Status: models.StatusInProgress, DueDate: &future},
+		{ID: 3, CreatorID: 10, AssigneeID: 10, BranchID: &branch, Status: models.StatusDone, DueDate: &past},
+		{ID: 4, CreatorID: 10, AssigneeID: 10, BranchID: &branch, Status: models.StatusCancelled, DueDate: &past},
+		{ID: 5, CreatorID: 10, AssigneeID: 10, BranchID: &branch, Status: models.StatusNew},
+	}}
+	users := &taskBranchUserRepoStub{users: map[int]*models.User{20: {ID: 20, BranchID: ptrInt(1)}}}
+	h := NewTaskHandler(svc, nil, users)
+	h.SetTimezone(time.FixedZone("UTC+5", 5*60*60))
+	return h
+}
+
+func getTasks(t *testing.T, h *TaskHandler, query string) (*httptest.ResponseRecorder, []models.Task) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/tasks"+query, nil)
+	c.Set("user_id", 20)
+	c.Set("role_id", authz.RoleManagement)
+	h.GetAll(c)
+	if w.Code != http.StatusOK {
+		return w, nil
+	}
+	var tasks []models.Task
+	if err := json.Unmarshal(w.Body.Bytes(), &tasks); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return w, tasks
+}
+
+func TestTaskHandler_GetAll_SetsIsOverdue(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	_, tasks := getTasks(t, overdueTestHandler(), "")
+	if len(tasks) != 5 {
+		t.Fatalf("expected 5 tasks, got %d", len(tasks))
+	}
+	want := map[int64]bool{1: true, 2: false, 3: false, 4: false, 5: false}
+	for _, task := range tasks {
+		if task.IsOverdue != want[task.ID] {
+			t.Fatalf("task %d: is_overdue=%v, want %v", task.ID, task.IsOverdue, want[task.ID])
+		}
+	}
+}
+
+func TestTaskHandler_GetAll_OverdueFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := overdueTestHandler()
+
+	_, tasks := getTasks(t, h, "?overdue=true")
+	if len(tasks) != 1 || tasks[0].ID != 1 || !tasks[0].IsOverdue {
+		t.Fatalf("expected only overdue task 1, got %+v", tasks)
+	}
+
+	_, tasks = getTasks(t, h, "?overdue=false")
+	if len(tasks) != 4 {
+		t.Fatalf("overdue=false must keep only non-overdue tasks, got %+v", tasks)
+	}
+	for _, task := range tasks {
+		if task.ID == 1 || task.IsOverdue {
+			t.Fatalf("overdue=false returned overdue task %d", task.ID)
+		}
+	}
+
+	if w, _ := getTasks(t, h, "?overdue=maybe"); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid overdue, got %d", w.Code)
+	}
+}
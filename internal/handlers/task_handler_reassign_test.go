@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+	"turcompany/internal/services"
+)
+
+type taskReassignServiceStub struct {
+	taskBranchServiceStub
+	users *taskBranchUserRepoStub
+	calls [][2]int64
+}
+
+func (s *taskReassignServiceStub) ReassignOpen(_ context.Context, from, to int64) ([]int64, error) {
+	if u := s.users.users[int(to)]; u == nil || !u.IsActive {
+		return nil, services.ErrInvalidTaskAssignee
+	}
+	s.calls = append(s.calls, [2]int64{from, to})
+	return []int64{4, 5, 6}, nil
+}
+
+func TestTaskHandler_Reassign(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	users := &taskBranchUserRepoStub{users: map[int]*models.User{
+		7:  {ID: 7, IsActive: true},
+		9:  {ID: 9, IsActive: true},
+		10: {ID: 10},
+	}}
+	cases := []struct {
+		body     string
+		code     int
+		response string
+	}{
+		{`{"from_user":7,"to_user":9}`, http.StatusOK, `{"moved":3}`},
+		{`{"from_user":7,"to_user":7}`, http.StatusBadRequest, ""},
+		{`{"from_user":7,"to_user":10}`, http.StatusBadRequest, ""},
+		{`{"from_user":7,"to_user":404}`, http.StatusBadRequest, ""},
+		{`{"from_user":7}`, http.StatusBadRequest, ""},
+	}
+	for _, tc := range cases {
+		svc := &taskReassignServiceStub{users: users}
+		svc.task = &models.Task{ID: 4, AssigneeID: 9}
+		events := &taskEventRecorder{}
+		h := NewTaskHandler(svc, nil, users)
+		h.dispatch = func(f func()) { f() }
+		h.SetEventPublisher(events)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/tasks/reassign", strings.NewReader(tc.body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set("user_id", 1)
+		c.Set("role_id", authz.RoleManagement)
+		h.Reassign(c)
+
+		if w.Code != tc.code {
+			t.Fatalf("%s: expected %d, got %d body=%s", tc.body, tc.code, w.Code, w.Body.String())
+		}
+		if tc.code != http.StatusOK {
+			if len(svc.calls) != 0 || len(events.events) != 0 {
+				t.Fatalf("%s: rejected request must not reassign or publish", tc.body)
+			}
+			continue
+		}
+		if len(events.events) != 3 || events.events[0] != services.TaskEventAssigned {
+			t.Fatalf("expected task.assigned for each of the 3 moved tasks, got %v", events.events)
+		}
+		if w.Body.String() != tc.response || len(svc.calls) != 1 || svc.calls[0] != [2]int64{7, 9} {
+			t.Fatalf("unexpected result body=%s calls=%v", w.Body.String(), svc.calls)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+)
+
+type taskCreateCaptureStub struct {
+	taskBranchServiceStub
+	created *models.Task
+}
+
+func (s *taskCreateCaptureStub) Create(_ context.Context, task *models.Task) (*models.Task, error) {
+	task.ID = 1
+	s.created = task
+	return task, nil
+}
+
+func postTask(h *TaskHandler, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("user_id", 1)
+	c.Set("role_id", authz.RoleSystemAdmin)
+	h.Create(c)
+	return w
+}
+
+func TestTaskHandler_Create_ReminderOffset(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	svc := &taskCreateCaptureStub{}
+	h := NewTaskHandler(svc, nil, nil)
+	w := postTask(h, `{"title":"t","due_date":"2026-05-10T12:00:00Z","reminder_offset":"24h"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+	if svc.created.ReminderOffset == nil || *svc.created.ReminderOffset != 86400 {
+		t.Fatalf("expected 24h offset in seconds, got %v", svc.created.ReminderOffset)
+	}
+
+	// An explicit reminder_at wins over the offset.
+	svc = &taskCreateCaptureStub{}
+	h = NewTaskHandler(svc, nil, nil)
+	w = postTask(h, `{"title":"t","due_date":"2026-05-10T12:00:00Z","reminder_at":"2026-05-10T08:00:00Z","reminder_offset":"24h"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+	if svc.created.ReminderOffset != nil || svc.created.ReminderAt == nil || svc.created.ReminderAt.Hour() != 8 {
+		t.Fatalf("expected explicit reminder_at to win, got at=%v offset=%v", svc.created.ReminderAt, svc.created.ReminderOffset)
+	}
+
+	if w := postTask(NewTaskHandler(&taskCreateCaptureStub{}, nil, nil), `{"title":"t","reminder_offset":"soon"}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid offset, got %d", w.Code)
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+	"turcompany/internal/services"
+)
+
+type taskSummaryServiceStub struct {
+	stubTaskListService
+	tasks []models.Task
+}
+
+func (s *taskSummaryServiceStub) GetAll(_ context.Context, filter models.TaskFilter) ([]models.Task, error) {
+	s.called = true
+	s.lastFilter = filter
+	return s.tasks, nil
+}
+
+func TestTaskHandler_MySummary_ScopedToCaller(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	soon := time.Now().Add(2 * time.Hour)
+	later := time.Now().Add(48 * time.Hour)
+	svc := &taskSummaryServiceStub{tasks: []models.Task{
+		{ID: 3, AssigneeID: 42, Status: models.StatusInProgress, DueDate: &later},
+		{ID: 8, AssigneeID: 42, Status: models.StatusNew, DueDate: &soon},
+		{ID: 9, AssigneeID: 42, Status: models.StatusNew},
+	}}
+	users := &taskBranchUserRepoStub{users: map[int]*models.User{42: {ID: 42, BranchID: ptrInt(3)}}}
+	h := NewTaskHandler(svc, nil, users)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/me/tasks/summary?limit=1", nil)
+	c.Set("user_id", 42)
+	c.Set("role_id", authz.RoleSales)
+
+	h.MySummary(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	if svc.lastFilter.AssigneeID == nil || *svc.lastFilter.AssigneeID != 42 || svc.lastFilter.StatusGroup != "active" {
+		t.Fatalf("expected caller-scoped active filter, got %+v", svc.lastFilter)
+	}
+	var resp services.TaskSummary
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Open != 3 || resp.ByStatus[models.StatusNew] != 2 || resp.ByStatus[models.StatusInProgress] != 1 {
+		t.Fatalf("unexpected counts: %+v", resp)
+	}
+	if len(resp.NextDue) != 1 || resp.NextDue[0].ID != 8 {
+		t.Fatalf("expected only the soonest task, got %+v", resp.NextDue)
+	}
+}
+
+func TestTaskHandler_MySummary_InvalidLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewTaskHandler(&taskSummaryServiceStub{}, nil, nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/me/tasks/summary?limit=0", nil)
+	c.Set("user_id", 42)
+	c.Set("role_id", authz.RoleSales)
+
+	h.MySummary(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d body=%s", w.Code, w.Body.String())
+	}
+}
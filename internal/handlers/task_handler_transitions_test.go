@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+	"turcompany/internal/services"
+)
+
+func TestTaskHandler_TransitionsFollowStatusAndRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	branch := int64(1)
+	repo := &lastModifiedTaskRepo{versionedTaskRepo{task: models.Task{ID: 7, CreatorID: 10, AssigneeID: 10, BranchID: &branch, Title: "Call client", Status: models.StatusNew, Priority: models.PriorityNormal, Version: 1}}}
+	users := &taskBranchUserRepoStub{users: map[int]*models.User{10: {ID: 10, BranchID: ptrInt(1)}}}
+	h := NewTaskHandler(services.NewTaskService(repo, nil), nil, users)
+
+	transitions := func(userID, roleID int) []models.TaskStatus {
+		t.Helper()
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/tasks/7/transitions", nil)
+		c.Params = gin.Params{{Key: "id", Value: "7"}}
+		c.Set("user_id", userID)
+		c.Set("role_id", roleID)
+		h.Transitions(c)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			AllowedTransitions []models.TaskStatus `json:"allowed_transitions"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return resp.AllowedTransitions
+	}
+
+	want := []models.TaskStatus{models.StatusInProgress, models.StatusCancelled}
+	if got := transitions(10, authz.RoleSales); !slices.Equal(got, want) {
+		t.Fatalf("new task: expected %v, got %v", want, got)
+	}
+
+	repo.task.Status = models.StatusDone
+	if got := transitions(10, authz.RoleSales); len(got) != 0 {
+		t.Fatalf("done task, sales owner: expected no transitions, got %v", got)
+	}
+	want = []models.TaskStatus{models.StatusInProgress}
+	if got := transitions(2, authz.RoleManagement); !slices.Equal(got, want) {
+		t.Fatalf("done task, management: expected reopen %v, got %v", want, got)
+	}
+
+	// The status endpoint enforces the same rule.
+	changeStatus := func(userID, roleID int) int {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/tasks/7/status", strings.NewReader(`{"to":"in_progress"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "id", Value: "7"}}
+		c.Set("user_id", userID)
+		c.Set("role_id", roleID)
+		h.ChangeStatus(c)
+		return w.Code
+	}
+	if code := changeStatus(10, authz.RoleSales); code != http.StatusConflict {
+		t.Fatalf("sales reopen: expected 409, got %d", code)
+	}
+	if code := changeStatus(2, authz.RoleManagement); code != http.StatusOK {
+		t.Fatalf("management reopen: expected 200, got %d", code)
+	}
+	if repo.task.Status != models.StatusInProgress {
+		t.Fatalf("expected task reopened, got %q", repo.task.Status)
+	}
+}
+
+func TestTaskHandler_GetByIDIncludesAllowedTransitions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &lastModifiedTaskRepo{versionedTaskRepo{task: models.Task{ID: 7, CreatorID: 1, AssigneeID: 1, Title: "Call client", Status: models.StatusInProgress, Priority: models.PriorityNormal, Version: 1}}}
+	h := NewTaskHandler(services.NewTaskService(repo, nil), nil, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/tasks/7", nil)
+	c.Params = gin.Params{{Key: "id", Value: "7"}}
+	c.Set("user_id", 1)
+	c.Set("role_id", authz.RoleSystemAdmin)
+	h.GetByID(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	var resp models.Task
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	want := []models.TaskStatus{models.StatusDone, models.StatusCancelled}
+	if !slices.Equal(resp.AllowedTransitions, want) {
+		t.Fatalf("expected %v, got %v", want, resp.AllowedTransitions)
+	}
+}
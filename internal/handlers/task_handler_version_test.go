@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+	"turcompany/internal/repositories"
+	"turcompany/internal/services"
+)
+
+// versionedTaskRepo keeps a single task in memory and applies Update only when
+// the version matches, like the SQL "WHERE id=$ AND version=$".
+type versionedTaskRepo struct {
+	repositories.TaskRepository
+	mu   sync.Mutex
+	task models.Task
+}
+
+func (r *versionedTaskRepo) FindByID(context.Context, int64) (*models.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t := r.task
+	return &t, nil
+}
+
+func (r *versionedTaskRepo) Update(_ context.Context, task *models.Task) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if task.Version != r.task.Version {
+		return repositories.ErrTaskVersionConflict
+	}
+	task.Version++
+	r.task = *task
+	return nil
+}
+
+func putTask(h *TaskHandler, body, ifMatch string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPut, "/tasks/7", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	if ifMatch != "" {
+		c.Request.Header.Set("If-Match", ifMatch)
+	}
+	c.Params = gin.Params{{Key: "id", Value: "7"}}
+	c.Set("user_id", 1)
+	c.Set("role_id", authz.RoleSystemAdmin)
+	h.Update(c)
+	return w
+}
+
+func TestTaskHandler_Update_StaleVersionLosesToConcurrentUpdate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &versionedTaskRepo{task: models.Task{ID: 7, CreatorID: 1, AssigneeID: 1, Title: "Call client", Status: models.StatusNew, Priority: models.PriorityNormal, Version: 1}}
+	h := NewTaskHandler(services.NewTaskService(repo, nil), nil, nil)
+
+	// Both editors loaded version 1; the first save wins.
+	first := putTask(h, `{"title":"Call client today"}`, `"1"`)
+	if first.Code != http.StatusOK {
+		t.Fatalf("first update: expected 200, got %d body=%s", first.Code, first.Body.String())
+	}
+	if got := first.Header().Get("ETag"); got != `"2"` {
+		t.Fatalf("expected ETag \"2\" after update, got %q", got)
+	}
+
+	second := putTask(h, `{"title":"Email client","version":1}`, "")
+	if second.Code != http.StatusConflict {
+		t.Fatalf("stale update: expected 409, got %d body=%s", second.Code, second.Body.String())
+	}
+	if !strings.Contains(second.Body.String(), TaskVersionConflictCode) {
+		t.Fatalf("expected %s in body, got %s", TaskVersionConflictCode, second.Body.String())
+	}
+	if repo.task.Title != "Call client today" || repo.task.Version != 2 {
+		t.Fatalf("stale update must not overwrite: got title=%q version=%d", repo.task.Title, repo.task.Version)
+	}
+}
+
+func TestTaskHandler_Update_InvalidIfMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &versionedTaskRepo{task: models.Task{ID: 7, CreatorID: 1, AssigneeID: 1, Status: models.StatusNew, Version: 1}}
+	h := NewTaskHandler(services.NewTaskService(repo, nil), nil, nil)
+
+	w := putTask(h, `{"title":"x"}`, `"abc"`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d body=%s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+	"turcompany/internal/services"
+)
+
+// taskWebhookRepoStub serves a single subscription and records dead letters.
+type taskWebhookRepoStub struct {
+	sub         models.WebhookSubscription
+	mu          sync.Mutex
+	deadLetters int
+}
+
+func (r *taskWebhookRepoStub) Create(context.Context, *models.WebhookSubscription) error { return nil }
+func (r *taskWebhookRepoStub) List(context.Context) ([]models.WebhookSubscription, error) {
+	return []models.WebhookSubscription{r.sub}, nil
+}
+func (r *taskWebhookRepoStub) Delete(context.Context, int64) (bool, error) { return false, nil }
+func (r *taskWebhookRepoStub) ListActiveForEvent(context.Context, string) ([]models.WebhookSubscription, error) {
+	return []models.WebhookSubscription{r.sub}, nil
+}
+func (r *taskWebhookRepoStub) InsertDeadLetter(context.Context, *models.WebhookDeadLetter) error {
+	r.mu.Lock()
+	r.deadLetters++
+	r.mu.Unlock()
+	return nil
+}
+
+func TestTaskHandler_ChangeStatus_PostsSignedWebhook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const secret = "integration-secret"
+
+	var (
+		mu               sync.Mutex
+		gotSig, gotEvent string
+		gotBody          []byte
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		gotSig = r.Header.Get(services.WebhookSignatureHeader)
+		gotEvent = r.Header.Get(services.WebhookEventHeader)
+		gotBody = body
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	// Loopback targets are refused, so the subscription uses a public name and
+	// the client dials the test server for it.
+	addr := srv.Listener.Addr().String()
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	}}
+	repo := &taskWebhookRepoStub{sub: models.WebhookSubscription{ID: 1, URL: "http://hooks.example.com/crm", Secret: secret, IsActive: true}}
+	webhooks := services.NewWebhookService(repo, client)
+
+	branch := int64(3)
+	svc := &taskBranchServiceStub{
+		task: &models.Task{ID: 55, CreatorID: 10, AssigneeID: 11, BranchID: &branch, Status: models.StatusNew},
+	}
+	users := &taskBranchUserRepoStub{users: map[int]*models.User{10: {ID: 10, BranchID: ptrInt(3)}}}
+	h := NewTaskHandler(svc, nil, users)
+	h.SetEventPublisher(webhooks)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/tasks/55/status", strings.NewReader(`{"to":"in_progress"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: "55"}}
+	c.Set("user_id", 10)
+	c.Set("role_id", authz.RoleVisa)
+
+	h.ChangeStatus(c)
+	webhooks.Wait()
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if gotBody == nil {
+		t.Fatal("expected webhook to be delivered")
+	}
+	if gotEvent != services.TaskEventStatusChanged {
+		t.Fatalf("event header = %q", gotEvent)
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(gotBody)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); gotSig != want {
+		t.Fatalf("signature = %q, want %q", gotSig, want)
+	}
+	if !strings.Contains(string(gotBody), `"event":"task.status_changed"`) {
+		t.Fatalf("unexpected body: %s", gotBody)
+	}
+	if repo.deadLetters != 0 {
+		t.Fatalf("expected no dead letters, got %d", repo.deadLetters)
+	}
+}
@@ -87,7 +87,10 @@ func (h *TelegramSignWebhookHandler) Handle(c *gin.Context) {
 		responseText = "Ссылка истекла"
 	}
 	if h.Telegram != nil && update.CallbackQuery.Message != nil {
-		_ = h.Telegram.SendMessage(update.CallbackQuery.Message.Chat.ID, responseText)
+		// SendMessage may retry with backoff; keep it off the webhook request.
+		go func(chatID int64) {
+			_ = h.Telegram.SendMessage(chatID, responseText)
+		}(update.CallbackQuery.Message.Chat.ID)
 	}
 	c.Status(http.StatusOK)
 }
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+
+	"turcompany/internal/services"
+)
+
+const (
+	// telegramUpdateWorkers is how many Telegram updates are handled at once.
+	telegramUpdateWorkers = 4
+	// telegramUpdateQueueDepth bounds the backlog of each worker.
+	telegramUpdateQueueDepth = 64
+)
+
+// telegramUpdateQueue hands webhook updates to a fixed set of workers.
+// Updates of one chat always land on the same worker, so a /link and the
+// command after it are handled in the order they arrived. The queues are
+// bounded: when a worker falls behind, Enqueue waits instead of piling up
+// goroutines.
+type telegramUpdateQueue struct {
+	queues []chan *services.TelegramUpdate
+}
+
+func newTelegramUpdateQueue(workers, depth int, handle func(*services.TelegramUpdate)) *telegramUpdateQueue {
+	q := &telegramUpdateQueue{queues: make([]chan *services.TelegramUpdate, workers)}
+	for i := range q.queues {
+		ch := make(chan *services.TelegramUpdate, depth)
+		q.queues[i] = ch
+		go func() {
+			for up := range ch {
+				handle(up)
+			}
+		}()
+	}
+	return q
+}
+
+// Enqueue queues up for its chat's worker. It returns false when ctx ends
+// before there is room, in which case the update is dropped.
+func (q *telegramUpdateQueue) Enqueue(ctx context.Context, up *services.TelegramUpdate) bool {
+	var chatID int64
+	if up.Message != nil {
+		chatID = up.Message.Chat.ID
+	}
+	idx := chatID % int64(len(q.queues))
+	if idx < 0 {
+		idx = -idx
+	}
+	select {
+	case q.queues[idx] <- up:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"turcompany/internal/services"
+)
+
+func telegramUpdateFor(updateID int, chatID int64) *services.TelegramUpdate {
+	up := &services.TelegramUpdate{UpdateID: updateID}
+	up.Message = &struct {
+		MessageID int    `json:"message_id"`
+		Text      string `json:"text"`
+		Chat      struct {
+			ID int64 `json:"id"`
+		} `json:"chat"`
+	}{}
+	up.Message.Chat.ID = chatID
+	return up
+}
+
+func TestTelegramUpdateQueue_KeepsPerChatOrder(t *testing.T) {
+	var mu sync.Mutex
+	seen := map[int64][]int{}
+	var wg sync.WaitGroup
+	q := newTelegramUpdateQueue(3, 8, func(up *services.TelegramUpdate) {
+		defer wg.Done()
+		mu.Lock()
+		defer mu.Unlock()
+		seen[up.Message.Chat.ID] = append(seen[up.Message.Chat.ID], up.UpdateID)
+	})
+
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		if !q.Enqueue(context.Background(), telegramUpdateFor(i, int64(i%5)-2)) {
+			t.Fatalf("update %d was not queued", i)
+		}
+	}
+	wg.Wait()
+
+	for chat, ids := range seen {
+		for i := 1; i < len(ids); i++ {
+			if ids[i] < ids[i-1] {
+				t.Fatalf("chat %d handled out of order: %v", chat, ids)
+			}
+		}
+	}
+	if len(seen) != 5 {
+		t.Fatalf("expected updates from 5 chats, got %d", len(seen))
+	}
+}
+
+func TestTelegramUpdateQueue_FullQueueWaitsForContext(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	q := newTelegramUpdateQueue(1, 1, func(*services.TelegramUpdate) { <-release })
+
+	// One update is being handled, one fills the queue.
+	for i := 0; i < 2; i++ {
+		if !q.Enqueue(context.Background(), telegramUpdateFor(i, 7)) {
+			t.Fatalf("update %d was not queued", i)
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if q.Enqueue(ctx, telegramUpdateFor(3, 7)) {
+		t.Fatal("a full queue must not accept more updates")
+	}
+}
@@ -158,7 +158,7 @@ type stubClientAccess struct {
 	errOnDeny error
 }
 
-func (s *stubClientAccess) GetByID(id, userID, roleID int) (*models.Client, error) {
+func (s *stubClientAccess) GetByID(_ context.Context, id, userID, roleID int) (*models.Client, error) {
 	if s.allowAll || s.allowed[int64(id)] {
 		return &models.Client{}, nil
 	}
@@ -171,7 +171,7 @@ type stubLeadAccess struct {
 	errOnDeny error
 }
 
-func (s *stubLeadAccess) GetByID(id, userID, roleID int) (*models.Leads, error) {
+func (s *stubLeadAccess) GetByID(_ context.Context, id, userID, roleID int) (*models.Leads, error) {
 	if s.allowAll || s.allowed[int64(id)] {
 		return &models.Leads{}, nil
 	}
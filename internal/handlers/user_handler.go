@@ -19,6 +19,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"turcompany/internal/authz"
 	"turcompany/internal/models"
+	"turcompany/internal/repositories"
 	"turcompany/internal/services"
 	"turcompany/internal/storage"
 )
@@ -28,6 +29,10 @@ type UserHandler struct {
 	branchService       services.BranchService
 	verificationService *services.UserVerificationService
 	approvalService     *services.UserApprovalService
+	roleService         *services.UserRoleService
+	emailVerification   *services.EmailVerificationService
+	welcomeEmails       *services.WelcomeEmailService
+	notifications       userNotificationSettings
 	filesRoot           string
 	store               storage.Storage
 }
@@ -49,6 +54,8 @@ type createUserRequest struct {
 	RoleID      int    `json:"role_id"`
 	IsVerified  *bool  `json:"is_verified"`
 	IsActive    *bool  `json:"is_active"`
+	// SkipVerification — синоним is_verified=true.
+	SkipVerification bool `json:"skip_verification"`
 }
 
 type updateUserRequest struct {
@@ -82,6 +89,29 @@ func (h *UserHandler) SetApprovalService(svc *services.UserApprovalService) {
 	h.approvalService = svc
 }
 
+func (h *UserHandler) SetRoleService(svc *services.UserRoleService) {
+	h.roleService = svc
+}
+
+func (h *UserHandler) SetEmailVerificationService(svc *services.EmailVerificationService) {
+	h.emailVerification = svc
+}
+
+func (h *UserHandler) SetWelcomeEmailService(svc *services.WelcomeEmailService) {
+	h.welcomeEmails = svc
+}
+
+// userNotificationSettings is the task email opt-in store
+// (*repositories.UserNotificationRepository).
+type userNotificationSettings interface {
+	GetTaskEmailSettings(ctx context.Context, userID int64) (string, bool, error)
+	SetTaskEmailNotify(ctx context.Context, userID int64, enable bool) error
+}
+
+func (h *UserHandler) SetNotificationSettings(settings userNotificationSettings) {
+	h.notifications = settings
+}
+
 type userResponse struct {
 	ID         int         `json:"id"`
 	FirstName  string      `json:"first_name,omitempty"`
@@ -293,7 +323,7 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 	}
 	var req createUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		badRequest(c, "Некорректные данные пользователя")
+		bindError(c, err, "Некорректные данные пользователя")
 		return
 	}
 	trimCreateUserRequest(&req)
@@ -331,6 +361,9 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 	if req.IsVerified != nil {
 		user.IsVerified = *req.IsVerified
 	}
+	if req.SkipVerification {
+		user.IsVerified = true
+	}
 	if req.IsActive != nil {
 		user.IsActive = *req.IsActive
 		user.IsActiveSet = true
@@ -356,7 +389,7 @@ func (h *UserHandler) createUserApprovalRequest(c *gin.Context, requesterID int)
 	}
 	var req createUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		badRequest(c, "Некорректные данные пользователя")
+		bindError(c, err, "Некорректные данные пользователя")
 		return
 	}
 	trimCreateUserRequest(&req)
@@ -410,8 +443,7 @@ func (h *UserHandler) GetMyProfile(c *gin.Context) {
 		return
 	}
 	user, err := h.service.GetUserByID(userID)
-	if err != nil || user == nil {
-		notFound(c, ClientNotFoundCode, "User not found")
+	if userLookupFailed(c, user, err, "User not found") {
 		return
 	}
 	c.JSON(http.StatusOK, h.userToResponse(user))
@@ -457,13 +489,12 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 		return
 	}
 	current, err := h.service.GetUserByID(userID)
-	if err != nil || current == nil {
-		notFound(c, ClientNotFoundCode, "User not found")
+	if userLookupFailed(c, current, err, "User not found") {
 		return
 	}
 	var req updateProfileRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		badRequest(c, "Некорректные данные профиля")
+		bindError(c, err, "Некорректные данные профиля")
 		return
 	}
 	trimUpdateProfileRequest(&req)
@@ -507,8 +538,7 @@ func (h *UserHandler) UploadProfileAvatar(c *gin.Context) {
 		return
 	}
 	current, err := h.service.GetUserByID(userID)
-	if err != nil || current == nil {
-		notFound(c, ClientNotFoundCode, "User not found")
+	if userLookupFailed(c, current, err, "User not found") {
 		return
 	}
 	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, 10<<20)
@@ -596,8 +626,7 @@ func (h *UserHandler) DeleteProfileAvatar(c *gin.Context) {
 		return
 	}
 	current, err := h.service.GetUserByID(userID)
-	if err != nil || current == nil {
-		notFound(c, ClientNotFoundCode, "User not found")
+	if userLookupFailed(c, current, err, "User not found") {
 		return
 	}
 	if err := h.service.DeleteAvatar(userID); err != nil {
@@ -627,8 +656,7 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 		return
 	}
 	user, err := h.service.GetUserByID(id)
-	if err != nil || user == nil {
-		notFound(c, ClientNotFoundCode, "User not found")
+	if userLookupFailed(c, user, err, "User not found") {
 		return
 	}
 	if !authz.CanViewLeadershipData(roleID) && user.RoleID == authz.RoleManagement {
@@ -693,13 +721,12 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		return
 	}
 	target, err := h.service.GetUserByID(id)
-	if err != nil || target == nil {
-		notFound(c, ClientNotFoundCode, "Пользователь не найден")
+	if userLookupFailed(c, target, err, "Пользователь не найден") {
 		return
 	}
 	var req updateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		badRequest(c, "Некорректные данные пользователя")
+		bindError(c, err, "Некорректные данные пользователя")
 		return
 	}
 	trimUpdateUserRequest(&req)
@@ -873,44 +900,79 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 }
 
 func (h *UserHandler) ListUsers(c *gin.Context) {
-	_, roleID := getUserAndRole(c)
+	userID, roleID := getUserAndRole(c)
 	if !authz.CanViewUsers(roleID) {
 		forbidden(c, "Forbidden")
 		return
 	}
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	if page < 1 {
-		page = 1
-	}
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	if limit < 1 {
-		limit = 10
+	paginate := isPaginatedMode(c)
+	var page, limit int
+	if paginate {
+		page, limit = normalizedPageAndSize(c)
+	} else {
+		page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+		if page < 1 {
+			page = 1
+		}
+		limit, _ = strconv.Atoi(c.DefaultQuery("limit", "10"))
+		if limit < 1 {
+			limit = 10
+		}
 	}
-	offset := (page - 1) * limit
-	users, err := h.service.ListUsers(limit, offset)
-	if err != nil {
-		log.Printf("ListUsers: service error: %v", err)
-		internalError(c, "Failed to list users")
-		return
+	offset := offsetFromPage(page, limit)
+	filter := repositories.UserSearchFilter{Query: strings.TrimSpace(c.Query("q"))}
+	if raw := strings.TrimSpace(c.Query("role_id")); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || !authz.IsKnownRole(v) {
+			badRequest(c, "Некорректная роль")
+			return
+		}
+		filter.RoleID = &v
 	}
-	out := make([]*userResponse, 0, len(users))
+	filtered := filter.RoleID != nil || filter.Query != ""
+
 	var current *models.User
 	if !authz.CanViewLeadershipData(roleID) {
-		current, err = h.service.GetUserByID(c.GetInt("user_id"))
-		if err != nil || current == nil || current.BranchID == nil {
+		u, err := h.service.GetUserByID(userID)
+		if err != nil || u == nil || u.BranchID == nil {
 			forbidden(c, "Forbidden")
 			return
 		}
+		current = u
+		// Scope in SQL as well so page sizes and totals match what is shown.
+		filter.BranchID = current.BranchID
+		filter.ExcludeRoleIDs = []int{authz.RoleManagement}
+	}
+
+	var users []*models.User
+	var err error
+	if paginate || filtered {
+		users, err = h.service.SearchUsers(filter, limit, offset)
+	} else {
+		users, err = h.service.ListUsers(limit, offset)
+	}
+	if err != nil {
+		log.Printf("ListUsers: service error: %v", err)
+		internalError(c, "Failed to list users")
+		return
 	}
+	out := make([]*userResponse, 0, len(users))
 	for _, u := range users {
-		if !authz.CanViewLeadershipData(roleID) && u.RoleID == authz.RoleManagement {
-			continue
-		}
-		if !authz.CanViewLeadershipData(roleID) && !sameUserBranch(current, u) {
+		if current != nil && (u.RoleID == authz.RoleManagement || !sameUserBranch(current, u)) {
 			continue
 		}
 		out = append(out, h.userToResponse(u))
 	}
+	if paginate {
+		total, err := h.service.CountUsers(filter)
+		if err != nil {
+			log.Printf("ListUsers: count error: %v", err)
+			internalError(c, "Failed to list users")
+			return
+		}
+		writePaginated(c, out, page, limit, total)
+		return
+	}
 	c.JSON(http.StatusOK, out)
 }
 
@@ -954,10 +1016,14 @@ func (h *UserHandler) GetUserCountByRole(c *gin.Context) {
 func (h *UserHandler) Register(c *gin.Context) {
 	var req createUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		badRequest(c, "Invalid registration payload")
+		bindError(c, err, "Invalid registration payload")
 		return
 	}
 	trimCreateUserRequest(&req)
+	if err := services.ValidatePassword(req.Password); err != nil {
+		badRequest(c, err.Error())
+		return
+	}
 	// Register always creates a sales (branch-scoped) user; branch_id is required so the
 	// new user's pipeline is immediately visible under scope filtering.
 	if msg := h.validateBranchForRole(authz.RoleSales, req.BranchID); msg != "" {
@@ -981,6 +1047,10 @@ func (h *UserHandler) Register(c *gin.Context) {
 	}
 	if err := h.service.CreateUserWithPassword(user, req.Password); err != nil {
 		log.Printf("Register: service error: %v", err)
+		if errors.Is(err, services.ErrEmailAlreadyUsed) {
+			conflict(c, ConflictCode, "Email already registered")
+			return
+		}
 		internalError(c, "Failed to register user")
 		return
 	}
@@ -990,7 +1060,17 @@ func (h *UserHandler) Register(c *gin.Context) {
 			verificationSent = true
 		}
 	}
-	c.JSON(http.StatusCreated, gin.H{"user": h.userToResponse(user), "message": "Registered. Verification code sent.", "verification_sent": verificationSent})
+	emailLinkSent := false
+	if h.emailVerification != nil {
+		if _, err := h.emailVerification.Send(user.ID, user.Email); errors.Is(err, services.ErrEmailVerificationNotConfigured) {
+			log.Printf("Register: email verification is not configured, link not sent user_id=%d", user.ID)
+		} else if err != nil {
+			log.Printf("Register: email verification link not sent user_id=%d: %v", user.ID, err)
+		} else {
+			emailLinkSent = true
+		}
+	}
+	c.JSON(http.StatusCreated, gin.H{"user": h.userToResponse(user), "message": "Registered. Verification code sent.", "verification_sent": verificationSent, "email_verification_sent": emailLinkSent})
 }
 
 func (h *UserHandler) ChangeUserPassword(c *gin.Context) {
@@ -1019,6 +1099,39 @@ func (h *UserHandler) ChangeUserPassword(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Пароль успешно изменён"})
 }
 
+// ChangeMyPassword — POST /profile/password
+// Смена собственного пароля: требуется текущий пароль, новый проверяется политикой.
+func (h *UserHandler) ChangeMyPassword(c *gin.Context) {
+	userID, _ := getUserAndRole(c)
+	if userID == 0 {
+		unauthorized(c, "Unauthorized")
+		return
+	}
+	var req struct {
+		CurrentPassword string `json:"current_password"`
+		NewPassword     string `json:"new_password"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.CurrentPassword == "" || req.NewPassword == "" {
+		badRequest(c, "Укажите текущий и новый пароль")
+		return
+	}
+	if err := h.service.ChangePassword(userID, req.CurrentPassword, req.NewPassword); err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidCurrentPassword):
+			badRequest(c, "Текущий пароль указан неверно")
+		case errors.Is(err, services.ErrWeakPassword):
+			badRequest(c, err.Error())
+		case errors.Is(err, services.ErrNotFound):
+			notFound(c, NotFoundCode, "Пользователь не найден")
+		default:
+			log.Printf("ChangeMyPassword: %v", err)
+			internalError(c, "Не удалось изменить пароль")
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Пароль успешно изменён"})
+}
+
 // BlockUser — POST /users/:id/block
 // Устанавливает is_active=false напрямую (без подтверждения), доступно юристу и выше.
 func (h *UserHandler) BlockUser(c *gin.Context) {
@@ -1029,8 +1142,7 @@ func (h *UserHandler) BlockUser(c *gin.Context) {
 		return
 	}
 	target, err := h.service.GetUserByID(id)
-	if err != nil || target == nil {
-		notFound(c, ClientNotFoundCode, "Пользователь не найден")
+	if userLookupFailed(c, target, err, "Пользователь не найден") {
 		return
 	}
 	// Защита: не позволяем блокировать самого себя и тех, кто выше по роли
@@ -1058,8 +1170,7 @@ func (h *UserHandler) UnblockUser(c *gin.Context) {
 		return
 	}
 	target, err := h.service.GetUserByID(id)
-	if err != nil || target == nil {
-		notFound(c, ClientNotFoundCode, "Пользователь не найден")
+	if userLookupFailed(c, target, err, "Пользователь не найден") {
 		return
 	}
 	body := *target
@@ -1073,6 +1184,199 @@ func (h *UserHandler) UnblockUser(c *gin.Context) {
 	c.JSON(http.StatusOK, h.userToResponse(updated))
 }
 
+type changeUserRoleRequest struct {
+	RoleID int `json:"role_id"`
+}
+
+// ChangeUserRole — POST /users/:id/role
+// Меняет только роль пользователя и пишет запись в user_role_events, только для администратора.
+func (h *UserHandler) ChangeUserRole(c *gin.Context) {
+	actorID, roleID := getUserAndRole(c)
+	if !authz.CanAssignRoles(roleID) {
+		forbidden(c, "Только системный администратор может менять роли")
+		return
+	}
+	if h.roleService == nil {
+		internalError(c, "Сервис ролей недоступен")
+		return
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "Некорректный ID пользователя")
+		return
+	}
+	var req changeUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, "Некорректные данные")
+		return
+	}
+	if !authz.IsKnownRole(req.RoleID) {
+		badRequest(c, "Некорректная роль")
+		return
+	}
+	target, err := h.service.GetUserByID(id)
+	if userLookupFailed(c, target, err, "Пользователь не найден") {
+		return
+	}
+	if msg := h.validateBranchForRole(req.RoleID, target.BranchID); msg != "" {
+		badRequest(c, msg)
+		return
+	}
+	if _, err := h.roleService.ChangeRole(c.Request.Context(), actorID, roleID, id, req.RoleID); err != nil {
+		log.Printf("ChangeUserRole: service error: %v", err)
+		switch {
+		case errors.Is(err, services.ErrForbidden):
+			forbidden(c, "Только системный администратор может менять роли")
+		case errors.Is(err, services.ErrUnknownRole):
+			badRequest(c, "Некорректная роль")
+		case errors.Is(err, services.ErrNotFound):
+			notFound(c, NotFoundCode, "Пользователь не найден")
+		default:
+			internalError(c, "Не удалось изменить роль")
+		}
+		return
+	}
+	updated, err := h.service.GetUserByID(id)
+	if err != nil || updated == nil {
+		c.JSON(http.StatusOK, gin.H{"id": id, "role_id": req.RoleID})
+		return
+	}
+	c.JSON(http.StatusOK, h.userToResponse(updated))
+}
+
+// ReactivateUser — POST /users/:id/reactivate
+// Восстанавливает удалённого пользователя (is_active=true, исходный email), только для администратора.
+func (h *UserHandler) ReactivateUser(c *gin.Context) {
+	_, roleID := getUserAndRole(c)
+	if !authz.CanAssignRoles(roleID) {
+		forbidden(c, "Только системный администратор может восстанавливать пользователей")
+		return
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "Некорректный ID пользователя")
+		return
+	}
+	if err := h.service.ReactivateUser(id); err != nil {
+		log.Printf("ReactivateUser: service error: %v", err)
+		switch {
+		case errors.Is(err, services.ErrNotFound):
+			notFound(c, NotFoundCode, "Пользователь не найден")
+		case errors.Is(err, services.ErrEmailAlreadyUsed):
+			conflict(c, ConflictCode, "Email пользователя уже занят другой учётной записью")
+		default:
+			internalError(c, "Не удалось восстановить пользователя")
+		}
+		return
+	}
+	updated, err := h.service.GetUserByID(id)
+	if err != nil || updated == nil {
+		c.JSON(http.StatusOK, gin.H{"id": id, "is_active": true})
+		return
+	}
+	c.JSON(http.StatusOK, h.userToResponse(updated))
+}
+
+// POST /users/:id/resend-welcome
+// ResendWelcome queues the welcome email again for a user whose original one
+// was lost. Repeats for the same user are throttled.
+func (h *UserHandler) ResendWelcome(c *gin.Context) {
+	_, roleID := getUserAndRole(c)
+	if !authz.CanAssignRoles(roleID) {
+		forbidden(c, "Только системный администратор может отправлять приветственное письмо")
+		return
+	}
+	if h.welcomeEmails == nil {
+		internalError(c, "Сервис писем недоступен")
+		return
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "Некорректный ID пользователя")
+		return
+	}
+	if err := h.welcomeEmails.Resend(id); err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotFound):
+			notFound(c, NotFoundCode, "Пользователь не найден")
+		case errors.Is(err, services.ErrInvalidEmail):
+			badRequestWithCode(c, InvalidEmailCode, "У пользователя не указан email")
+		case errors.Is(err, services.ErrWelcomeResendThrottled):
+			writeError(c, http.StatusTooManyRequests, ValidationFailed, "Письмо уже отправлено, попробуйте позже")
+		default:
+			log.Printf("ResendWelcome: service error: %v", err)
+			internalError(c, "Не удалось отправить приветственное письмо")
+		}
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "Приветственное письмо поставлено в очередь"})
+}
+
+// GET /profile/notifications
+func (h *UserHandler) GetMyNotifications(c *gin.Context) {
+	h.writeMyNotifications(c)
+}
+
+// PUT /profile/notifications { "notify_tasks_email": true }
+// Письма о задачах приходят, только если задача не ушла в Telegram.
+func (h *UserHandler) UpdateMyNotifications(c *gin.Context) {
+	userID, _ := getUserAndRole(c)
+	if userID == 0 {
+		unauthorized(c, "Unauthorized")
+		return
+	}
+	if h.notifications == nil {
+		internalError(c, "Настройки уведомлений недоступны")
+		return
+	}
+	var req struct {
+		NotifyTasksEmail *bool `json:"notify_tasks_email" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err, "Некорректные настройки уведомлений")
+		return
+	}
+	if err := h.notifications.SetTaskEmailNotify(c.Request.Context(), int64(userID), *req.NotifyTasksEmail); err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			notFound(c, NotFoundCode, "Пользователь не найден")
+			return
+		}
+		log.Printf("UpdateMyNotifications: %v", err)
+		internalError(c, "Не удалось сохранить настройки уведомлений")
+		return
+	}
+	h.writeMyNotifications(c)
+}
+
+func (h *UserHandler) writeMyNotifications(c *gin.Context) {
+	userID, _ := getUserAndRole(c)
+	if userID == 0 {
+		unauthorized(c, "Unauthorized")
+		return
+	}
+	if h.notifications == nil {
+		internalError(c, "Настройки уведомлений недоступны")
+		return
+	}
+	user, err := h.service.GetUserByID(userID)
+	if userLookupFailed(c, user, err, "User not found") {
+		return
+	}
+	_, notifyEmail, err := h.notifications.GetTaskEmailSettings(c.Request.Context(), int64(userID))
+	if err != nil {
+		log.Printf("GetMyNotifications: %v", err)
+		internalError(c, "Не удалось получить настройки уведомлений")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"notify_tasks_email": notifyEmail,
+		"telegram": gin.H{
+			"linked":       user.TelegramChatID != 0,
+			"notify_tasks": user.NotifyTasksTelegram,
+		},
+	})
+}
+
 func allowedAvatarExt(ext string) bool {
 	switch strings.ToLower(ext) {
 	case ".jpg", ".jpeg", ".png", ".webp", ".pdf":
@@ -1134,3 +1438,19 @@ func (h *UserHandler) removeStoredFile(ctx context.Context, key string) {
 	}
 	_ = h.store.Delete(ctx, key)
 }
+
+// userLookupFailed answers a failed GetUserByID: 404 with msg when the user
+// does not exist, 500 when the lookup itself failed. It reports whether a
+// response was written.
+func userLookupFailed(c *gin.Context, user *models.User, err error, msg string) bool {
+	if err != nil && !errors.Is(err, services.ErrNotFound) {
+		log.Printf("[user] get user: %v", err)
+		internalError(c, "Failed to get user")
+		return true
+	}
+	if err != nil || user == nil {
+		notFound(c, ClientNotFoundCode, msg)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/repositories"
+)
+
+func TestGetMyProfile_NotFoundVsStorageError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cases := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"missing user", fmt.Errorf("%w: no rows", repositories.ErrNotFound), http.StatusNotFound},
+		{"nil user", nil, http.StatusNotFound},
+		{"storage error", errors.New("connection reset"), http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		svc := &stubUserService{byIDErr: tc.err}
+		h := NewUserHandler(svc, nil, nil, nil)
+		r := gin.New()
+		r.Use(func(c *gin.Context) { c.Set("user_id", 7); c.Set("role_id", authz.RoleSales); c.Next() })
+		r.GET("/users/me", h.GetMyProfile)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/me", nil))
+		if w.Code != tc.want {
+			t.Errorf("%s: expected %d, got %d body=%s", tc.name, tc.want, w.Code, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+	"turcompany/internal/services"
+)
+
+type countingVerificationRepo struct {
+	services.UserVerificationRepo
+	created int
+}
+
+func (r *countingVerificationRepo) Create(int, string, time.Time, time.Time) (int64, error) {
+	r.created++
+	return int64(r.created), nil
+}
+
+type countingVerificationMail struct{ services.EmailService }
+
+func (countingVerificationMail) SendVerificationCode(string, string, int) error { return nil }
+
+type countingSMSSender struct{ sent []services.SMSMessage }
+
+func (s *countingSMSSender) Send(_ context.Context, msg services.SMSMessage) (*services.SMSResult, error) {
+	s.sent = append(s.sent, msg)
+	return &services.SMSResult{}, nil
+}
+
+func postCreateUser(t *testing.T, extra map[string]interface{}) (*httptest.ResponseRecorder, *stubUserService, *countingVerificationRepo, *countingSMSSender) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	svc := &stubUserService{byID: &models.User{ID: 101, Phone: "+77001112233"}}
+	repo := &countingVerificationRepo{}
+	sms := &countingSMSSender{}
+	verification := services.NewUserVerificationService(repo, svc, countingVerificationMail{}, nil)
+	verification.SetSMSSender(sms)
+	h := NewUserHandler(svc, nil, verification, nil)
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", 1)
+		c.Set("role_id", authz.RoleSystemAdmin)
+		c.Next()
+	})
+	r.POST("/users", h.CreateUser)
+
+	body := map[string]interface{}{
+		"first_name":  "Aigerim",
+		"last_name":   "Tulegenova",
+		"middle_name": "Serikovna",
+		"position":    "Manager",
+		"email":       "employee@example.com",
+		"password":    "Passw0rd",
+		"phone":       "+77001112233",
+		"role_id":     authz.RoleSales,
+		"branch_id":   1,
+	}
+	for k, v := range extra {
+		body[k] = v
+	}
+	raw, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(raw))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w, svc, repo, sms
+}
+
+func TestCreateUser_SkipVerificationIsAliasOfIsVerified(t *testing.T) {
+	w, svc, repo, sms := postCreateUser(t, map[string]interface{}{"is_verified": false, "skip_verification": true})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+	if svc.createdUser == nil || !svc.createdUser.IsVerified {
+		t.Fatalf("expected verified user, got %+v", svc.createdUser)
+	}
+	if repo.created != 0 || len(sms.sent) != 0 {
+		t.Fatalf("expected no verification attempt, got codes=%d sms=%d", repo.created, len(sms.sent))
+	}
+}
+
+func TestCreateUser_UnverifiedUserGetsNoVerificationSMS(t *testing.T) {
+	w, svc, repo, sms := postCreateUser(t, map[string]interface{}{"is_verified": false})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+	if svc.createdUser == nil || svc.createdUser.IsVerified {
+		t.Fatalf("expected unverified user, got %+v", svc.createdUser)
+	}
+	if repo.created != 0 || len(sms.sent) != 0 {
+		t.Fatalf("admin create must not send a verification code, got codes=%d sms=%d", repo.created, len(sms.sent))
+	}
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+)
+
+func listUsersRouter(svc *stubUserService, roleID int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewUserHandler(svc, nil, nil, nil)
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set("user_id", 1); c.Set("role_id", roleID); c.Next() })
+	r.GET("/users", h.ListUsers)
+	return r
+}
+
+func TestListUsers_PassesRoleAndQueryToSearch(t *testing.T) {
+	svc := &stubUserService{users: []*models.User{{ID: 5, Email: "anna@example.com", RoleID: authz.RoleSales}}}
+	r := listUsersRouter(svc, authz.RoleSystemAdmin)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users?role_id=10&q=%20anna@%20", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status: got=%d body=%s", w.Code, w.Body.String())
+	}
+	if !svc.searchCalled {
+		t.Fatal("expected SearchUsers to be used when filters are present")
+	}
+	if svc.searchFilter.RoleID == nil || *svc.searchFilter.RoleID != authz.RoleSales {
+		t.Fatalf("unexpected role filter: %v", svc.searchFilter.RoleID)
+	}
+	if svc.searchFilter.Query != "anna@" {
+		t.Fatalf("expected trimmed query, got %q", svc.searchFilter.Query)
+	}
+}
+
+func TestListUsers_WithoutFiltersUsesPlainList(t *testing.T) {
+	svc := &stubUserService{}
+	r := listUsersRouter(svc, authz.RoleSystemAdmin)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users?page=2&limit=5", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status: got=%d body=%s", w.Code, w.Body.String())
+	}
+	if svc.searchCalled {
+		t.Fatal("SearchUsers must not be used without filters")
+	}
+}
+
+func TestListUsers_RejectsUnknownRoleFilter(t *testing.T) {
+	svc := &stubUserService{}
+	r := listUsersRouter(svc, authz.RoleSystemAdmin)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users?role_id=999", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("unexpected status: got=%d want=%d", w.Code, http.StatusBadRequest)
+	}
+}
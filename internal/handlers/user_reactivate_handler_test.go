@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+	"turcompany/internal/services"
+)
+
+func reactivateRouter(svc *stubUserService, roleID int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewUserHandler(svc, nil, nil, nil)
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set("user_id", 1); c.Set("role_id", roleID); c.Next() })
+	r.POST("/users/:id/reactivate", h.ReactivateUser)
+	return r
+}
+
+func TestReactivateUser_AdminRestoresUser(t *testing.T) {
+	svc := &stubUserService{byID: &models.User{ID: 42, Email: "back@example.com", IsActive: true}}
+	r := reactivateRouter(svc, authz.RoleSystemAdmin)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/42/reactivate", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status: got=%d want=%d body=%s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if svc.reactivatedID != 42 {
+		t.Fatalf("expected user 42 to be reactivated, got %d", svc.reactivatedID)
+	}
+}
+
+func TestReactivateUser_NonAdminForbidden(t *testing.T) {
+	for _, roleID := range []int{authz.RoleManagement, authz.RoleLegal, authz.RoleHR} {
+		svc := &stubUserService{}
+		r := reactivateRouter(svc, roleID)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/42/reactivate", nil))
+		if w.Code != http.StatusForbidden {
+			t.Fatalf("role %d: unexpected status: got=%d want=%d", roleID, w.Code, http.StatusForbidden)
+		}
+		if svc.reactivatedID != 0 {
+			t.Fatalf("role %d: service must not be called", roleID)
+		}
+	}
+}
+
+func TestReactivateUser_EmailTakenReturnsConflict(t *testing.T) {
+	svc := &stubUserService{reactivateErr: services.ErrEmailAlreadyUsed}
+	r := reactivateRouter(svc, authz.RoleSystemAdmin)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/42/reactivate", nil))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("unexpected status: got=%d want=%d body=%s", w.Code, http.StatusConflict, w.Body.String())
+	}
+}
+
+func TestLogin_DeactivatedUserIsRejected(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authSvc := services.NewAuthService([]byte("01234567890123456789012345678901"), nil, 0, 0, 4, nil)
+	hash, err := authSvc.HashPassword("Passw0rd!")
+	if err != nil {
+		t.Fatalf("hash: %v", err)
+	}
+	svc := &stubUserService{byEmail: &models.User{ID: 42, Email: "gone@example.com", PasswordHash: hash, RoleID: authz.RoleSales, IsVerified: true, IsActive: false}}
+	h := NewAuthHandler(svc, authSvc, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(`{"email":"gone@example.com","password":"Passw0rd!"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	h.Login(c)
+
+	assertErrorCode(t, w, http.StatusForbidden, UserDisabledCode)
+	if strings.Contains(w.Body.String(), "access_token") {
+		t.Fatalf("deactivated user must not get tokens: %s", w.Body.String())
+	}
+}
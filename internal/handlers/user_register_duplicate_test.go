@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+	"turcompany/internal/services"
+)
+
+// duplicateEmailUserRepo fails inserts the way Postgres does when the
+// users.email unique constraint is hit.
+type duplicateEmailUserRepo struct {
+	chatTestUserRepo
+}
+
+func (r *duplicateEmailUserRepo) Create(*models.User) error {
+	return &pq.Error{Code: "23505", Constraint: "users_email_key", Message: `duplicate key value violates unique constraint "users_email_key"`}
+}
+
+func TestRegisterAndCreateUser_DuplicateEmailReturns409(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authSvc := services.NewAuthService([]byte("01234567890123456789012345678901"), nil, 0, 0, 4, nil)
+	svc := services.NewUserService(&duplicateEmailUserRepo{}, nil, authSvc)
+	h := NewUserHandler(svc, nil, nil, nil)
+
+	r := gin.New()
+	r.POST("/register", h.Register)
+	r.POST("/users", func(c *gin.Context) {
+		c.Set("user_id", 1)
+		c.Set("role_id", authz.RoleSystemAdmin)
+		h.CreateUser(c)
+	})
+
+	for path, body := range map[string]string{
+		"/register": `{"company_name":"Acme","email":"taken@example.com","password":"Passw0rd","phone":"+77001112233","branch_id":7}`,
+		"/users":    `{"first_name":"Aigerim","last_name":"Tulegenova","position":"Manager","email":"taken@example.com","password":"Passw0rd","phone":"+77001112233","role_id":` + strconv.Itoa(authz.RoleSales) + `,"branch_id":7}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusConflict {
+			t.Fatalf("%s: expected 409 for a taken email, got %d body=%s", path, w.Code, w.Body.String())
+		}
+		if path == "/register" && !strings.Contains(w.Body.String(), "Email already registered") {
+			t.Fatalf("unexpected register body %s", w.Body.String())
+		}
+	}
+}
+
+type emptyEmailVerificationRepo struct{}
+
+func (emptyEmailVerificationRepo) Create(int, string, time.Time) error { return nil }
+func (emptyEmailVerificationRepo) GetByTokenHash(string) (*models.EmailVerification, error) {
+	return nil, nil
+}
+func (emptyEmailVerificationRepo) Confirm(int64, int, time.Time) error { return nil }
+
+func TestRegister_EmailVerificationNotConfiguredIsNotReportedAsSent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authSvc := services.NewAuthService([]byte("01234567890123456789012345678901"), nil, 0, 0, 4, nil)
+	svc := services.NewUserService(&chatTestUserRepo{}, nil, authSvc)
+	h := NewUserHandler(svc, nil, nil, nil)
+	h.SetEmailVerificationService(services.NewEmailVerificationService(emptyEmailVerificationRepo{}, nil, "", nil))
+
+	r := gin.New()
+	r.POST("/register", h.Register)
+	req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewBufferString(`{"company_name":"Acme","email":"new@example.com","password":"Passw0rd","phone":"+77001112233","branch_id":7}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+	var body struct {
+		EmailVerificationSent *bool `json:"email_verification_sent"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if body.EmailVerificationSent == nil || *body.EmailVerificationSent {
+		t.Fatalf("email_verification_sent must be false without a base URL, body=%s", w.Body.String())
+	}
+}
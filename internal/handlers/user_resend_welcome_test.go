@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+	"turcompany/internal/services"
+)
+
+type welcomeEmailFake struct {
+	services.EmailService
+	sent chan string
+}
+
+func (f *welcomeEmailFake) SendWelcomeEmail(email, name string) error {
+	f.sent <- email + "|" + name
+	return nil
+}
+
+type welcomeUsersStub map[int]*models.User
+
+func (s welcomeUsersStub) GetByID(id int) (*models.User, error) { return s[id], nil }
+
+func TestUserHandler_ResendWelcome_SendsAndThrottles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	emails := &welcomeEmailFake{sent: make(chan string, 2)}
+	users := welcomeUsersStub{5: {ID: 5, Email: "a@example.com", FirstName: "Айдана", LastName: "К"}}
+	h := NewUserHandler(nil, nil, nil, nil)
+	h.SetWelcomeEmailService(services.NewWelcomeEmailService(users, emails, nil))
+
+	post := func(id string) int {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/users/"+id+"/resend-welcome", nil)
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		c.Set("user_id", 1)
+		c.Set("role_id", authz.RoleSystemAdmin)
+		h.ResendWelcome(c)
+		return w.Code
+	}
+
+	if code := post("5"); code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", code)
+	}
+	select {
+	case got := <-emails.sent:
+		if got != "a@example.com|Айдана К" {
+			t.Fatalf("unexpected welcome email: %q", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("welcome email was not sent")
+	}
+
+	if code := post("5"); code != http.StatusTooManyRequests {
+		t.Fatalf("rapid repeat: expected 429, got %d", code)
+	}
+	if code := post("6"); code != http.StatusNotFound {
+		t.Fatalf("unknown user: expected 404, got %d", code)
+	}
+	select {
+	case got := <-emails.sent:
+		t.Fatalf("throttled request must not send, got %q", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+	"turcompany/internal/services"
+)
+
+type roleEventRepoStub struct {
+	roles  map[int]int
+	events []models.UserRoleEvent
+}
+
+func (r *roleEventRepoStub) ChangeRole(_ context.Context, userID, actorID, newRoleID int) (*models.UserRoleEvent, error) {
+	old := r.roles[userID]
+	if old == newRoleID {
+		return nil, nil
+	}
+	r.roles[userID] = newRoleID
+	event := models.UserRoleEvent{ID: int64(len(r.events) + 1), UserID: userID, ActorID: actorID, OldRoleID: old, NewRoleID: newRoleID, CreatedAt: time.Now()}
+	r.events = append(r.events, event)
+	return &event, nil
+}
+
+func changeRoleRouter(repo *roleEventRepoStub, roleID int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	branchID := 3
+	svc := &stubUserService{byID: &models.User{ID: 42, RoleID: authz.RoleSales, BranchID: &branchID}}
+	h := NewUserHandler(svc, nil, nil, nil)
+	h.SetRoleService(services.NewUserRoleService(repo))
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set("user_id", 1); c.Set("role_id", roleID); c.Next() })
+	r.POST("/users/:id/role", h.ChangeUserRole)
+	return r
+}
+
+func TestChangeUserRole_NonAdminForbidden(t *testing.T) {
+	repo := &roleEventRepoStub{roles: map[int]int{42: authz.RoleSales}}
+	r := changeRoleRouter(repo, authz.RoleManagement)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/42/role", bytes.NewBufferString(`{"role_id":40}`)))
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("unexpected status: got=%d want=%d body=%s", w.Code, http.StatusForbidden, w.Body.String())
+	}
+	if len(repo.events) != 0 || repo.roles[42] != authz.RoleSales {
+		t.Fatalf("role must not change for non-admin, events=%+v", repo.events)
+	}
+}
+
+func TestChangeUserRole_AdminRecordsAuditEvent(t *testing.T) {
+	repo := &roleEventRepoStub{roles: map[int]int{42: authz.RoleSales}}
+	r := changeRoleRouter(repo, authz.RoleSystemAdmin)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/42/role", bytes.NewBufferString(`{"role_id":40}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status: got=%d want=%d body=%s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if len(repo.events) != 1 {
+		t.Fatalf("expected one audit event, got %+v", repo.events)
+	}
+	got := repo.events[0]
+	if got.UserID != 42 || got.ActorID != 1 || got.OldRoleID != authz.RoleSales || got.NewRoleID != authz.RoleManagement {
+		t.Fatalf("unexpected audit event: %+v", got)
+	}
+}
+
+func TestChangeUserRole_RejectsUnknownRole(t *testing.T) {
+	repo := &roleEventRepoStub{roles: map[int]int{42: authz.RoleSales}}
+	r := changeRoleRouter(repo, authz.RoleSystemAdmin)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/42/role", bytes.NewBufferString(`{"role_id":999}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("unexpected status: got=%d want=%d body=%s", w.Code, http.StatusBadRequest, w.Body.String())
+	}
+	if len(repo.events) != 0 {
+		t.Fatalf("unknown role must not be recorded, got %+v", repo.events)
+	}
+}
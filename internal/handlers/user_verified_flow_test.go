@@ -13,6 +13,7 @@ import (
 
 	"turcompany/internal/authz"
 	"turcompany/internal/models"
+	"turcompany/internal/repositories"
 	"turcompany/internal/services"
 )
 
@@ -22,6 +23,14 @@ type stubUserService struct {
 	createErr   error
 	byEmail     *models.User
 	byID        *models.User
+	byIDErr     error
+
+	reactivatedID int
+	reactivateErr error
+
+	users        []*models.User
+	searchCalled bool
+	searchFilter repositories.UserSearchFilter
 }
 
 func (s *stubUserService) CreateUser(*models.User) error { return nil }
@@ -35,8 +44,9 @@ func (s *stubUserService) CreateUserWithPassword(user *models.User, _ string) er
 	}
 	return s.createErr
 }
-func (s *stubUserService) GetUserByID(int) (*models.User, error) { return s.byID, nil }
+func (s *stubUserService) GetUserByID(int) (*models.User, error) { return s.byID, s.byIDErr }
 func (s *stubUserService) AdminChangePassword(int, string) error { return nil }
+func (s *stubUserService) ChangePassword(int, string, string) error { return nil }
 func (s *stubUserService) ApplyUpdatePatch(int, *models.UserApprovalUpdatePayload) error {
 	return nil
 }
@@ -47,13 +57,32 @@ func (s *stubUserService) UpdateUser(user *models.User) error {
 	return nil
 }
 func (s *stubUserService) DeleteUser(int) error                            { return nil }
-func (s *stubUserService) ListUsers(int, int) ([]*models.User, error)      { return nil, nil }
+func (s *stubUserService) ReactivateUser(id int) error {
+	s.reactivatedID = id
+	return s.reactivateErr
+}
+func (s *stubUserService) ListUsers(int, int) ([]*models.User, error)      { return s.users, nil }
+func (s *stubUserService) SearchUsers(filter repositories.UserSearchFilter, limit, offset int) ([]*models.User, error) {
+	s.searchCalled, s.searchFilter = true, filter
+	if offset >= len(s.users) {
+		return nil, nil
+	}
+	end := offset + limit
+	if end > len(s.users) {
+		end = len(s.users)
+	}
+	return s.users[offset:end], nil
+}
+func (s *stubUserService) CountUsers(repositories.UserSearchFilter) (int, error) {
+	return len(s.users), nil
+}
 func (s *stubUserService) GetUserByEmail(string) (*models.User, error)     { return s.byEmail, nil }
 func (s *stubUserService) GetAuthUserByEmail(string) (*models.User, error) { return s.byEmail, nil }
 func (s *stubUserService) GetUserCount() (int, error)                      { return 0, nil }
 func (s *stubUserService) GetUserCountByRole(int) (int, error)             { return 0, nil }
 func (s *stubUserService) UpdateRefresh(int, string, time.Time) error      { return nil }
 func (s *stubUserService) GetByRefreshToken(string) (*models.User, error)  { return nil, nil }
+func (s *stubUserService) ClearRefresh(int) error                          { return nil }
 func (s *stubUserService) RotateRefresh(string, string, time.Time) (*models.User, error) {
 	return nil, nil
 }
@@ -292,7 +321,7 @@ func TestRegister_ZeroBranchIDRejected(t *testing.T) {
 
 func TestLogin_VerifiedUserCanLoginImmediately(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	authSvc := services.NewAuthService([]byte("01234567890123456789012345678901"), nil, 0, 0, nil)
+	authSvc := services.NewAuthService([]byte("01234567890123456789012345678901"), nil, 0, 0, 0, nil)
 	hash, err := authSvc.HashPassword("Passw0rd")
 	if err != nil {
 		t.Fatalf("HashPassword error: %v", err)
@@ -324,7 +353,7 @@ func TestLogin_VerifiedUserCanLoginImmediately(t *testing.T) {
 
 func TestLogin_InactiveUserCannotLogin(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	authSvc := services.NewAuthService([]byte("01234567890123456789012345678901"), nil, 0, 0, nil)
+	authSvc := services.NewAuthService([]byte("01234567890123456789012345678901"), nil, 0, 0, 0, nil)
 	hash, err := authSvc.HashPassword("Passw0rd")
 	if err != nil {
 		t.Fatalf("HashPassword error: %v", err)
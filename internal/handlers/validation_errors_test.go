@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/authz"
+)
+
+type validationErrorsResponse struct {
+	ErrorCode string `json:"error_code"`
+	Message   string `json:"message"`
+	Details   struct {
+		Errors []FieldError `json:"errors"`
+	} `json:"details"`
+}
+
+func newCreateFromClientRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewDocumentHandler(nil, nil)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", 1)
+		c.Set("role_id", authz.RoleSales)
+		c.Next()
+	})
+	r.POST("/documents/create-from-client", h.CreateDocumentFromClient)
+	return r
+}
+
+func TestCreateFromClientMissingRequiredFieldReturnsFieldErrors(t *testing.T) {
+	r := newCreateFromClientRouter()
+	req := httptest.NewRequest(http.MethodPost, "/documents/create-from-client",
+		strings.NewReader(`{"client_id":7,"client_type":"individual"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", w.Code, w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), "createFromClientRequest") || strings.Contains(w.Body.String(), "DocType") {
+		t.Fatalf("response leaks struct names: %s", w.Body.String())
+	}
+	var resp validationErrorsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.ErrorCode != ValidationFailed {
+		t.Fatalf("expected %s, got %q", ValidationFailed, resp.ErrorCode)
+	}
+	if len(resp.Details.Errors) != 1 {
+		t.Fatalf("expected one field error, got %+v", resp.Details.Errors)
+	}
+	got := resp.Details.Errors[0]
+	if got.Field != "doc_type" || got.Message != "is required" {
+		t.Fatalf("unexpected field error: %+v", got)
+	}
+}
+
+func TestCreateFromClientMalformedJSONFallsBackToBadRequest(t *testing.T) {
+	r := newCreateFromClientRouter()
+	req := httptest.NewRequest(http.MethodPost, "/documents/create-from-client", strings.NewReader(`{"client_id":`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp APIError
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.ErrorCode != BadRequestCode || resp.Details != nil {
+		t.Fatalf("expected plain bad request, got %+v", resp)
+	}
+}
@@ -14,7 +14,8 @@ import (
 )
 
 type VerifyHandler struct {
-	verification *services.UserVerificationService
+	verification      *services.UserVerificationService
+	emailVerification *services.EmailVerificationService
 
 	mu          sync.Mutex
 	lastResends map[string]time.Time
@@ -29,6 +30,33 @@ func NewVerifyHandler(s *services.UserVerificationService) *VerifyHandler {
 	}
 }
 
+func (h *VerifyHandler) SetEmailVerificationService(svc *services.EmailVerificationService) {
+	h.emailVerification = svc
+}
+
+// VerifyEmail confirms the address from the link emailed on registration.
+func (h *VerifyHandler) VerifyEmail(c *gin.Context) {
+	if h.emailVerification == nil {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	userID, err := h.emailVerification.Confirm(c.Query("token"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrEmailVerificationNotFound):
+			badRequest(c, "Invalid verification link")
+		case errors.Is(err, services.ErrEmailVerificationExpired):
+			badRequest(c, "Verification link expired")
+		case errors.Is(err, services.ErrEmailVerificationUsed):
+			badRequest(c, "Verification link already used")
+		default:
+			internalError(c, "Email verification failed")
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Email verified", "user_id": userID})
+}
+
 func (h *VerifyHandler) ConfirmUser(c *gin.Context) {
 	var req models.RegisterConfirmRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -39,6 +67,9 @@ func (h *VerifyHandler) ConfirmUser(c *gin.Context) {
 	ok, err := h.verification.Confirm(req.UserID, req.Code)
 	if err != nil {
 		switch {
+		case errors.Is(err, services.ErrAccountLocked):
+			writeAccountLocked(c, err)
+			return
 		case errors.Is(err, services.ErrCodeExpired):
 			badRequest(c, "Code expired, please resend")
 			return
@@ -80,6 +111,10 @@ func (h *VerifyHandler) ResendUser(c *gin.Context) {
 	}
 
 	if err := h.verification.Resend(req.UserID); err != nil {
+		if errors.Is(err, services.ErrAccountLocked) {
+			writeAccountLocked(c, err)
+			return
+		}
 		if errors.Is(err, services.ErrResendThrottled) {
 			writeError(c, http.StatusTooManyRequests, ValidationFailed, "Too many requests, try later")
 			return
@@ -97,6 +132,19 @@ func (h *VerifyHandler) ResendUser(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Verification code sent"})
 }
 
+// writeAccountLocked responds 423 with the lock expiry and a Retry-After hint.
+func writeAccountLocked(c *gin.Context, err error) {
+	var locked *services.AccountLockedError
+	if !errors.As(err, &locked) {
+		writeError(c, http.StatusLocked, AccountLockedCode, "Too many failed attempts, account temporarily locked")
+		return
+	}
+	if wait := time.Until(locked.Until); wait > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(wait.Seconds())+1))
+	}
+	writeErrorWithDetails(c, http.StatusLocked, AccountLockedCode, "Too many failed attempts, account temporarily locked", gin.H{"locked_until": locked.Until})
+}
+
 func (h *VerifyHandler) allowResend(key string) bool {
 	now := time.Now()
 	h.mu.Lock()
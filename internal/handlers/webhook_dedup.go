@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+const (
+	// webhookDedupCapacity bounds how many recent keys are remembered; the
+	// oldest key is forgotten first when a burst exceeds it.
+	webhookDedupCapacity = 10000
+	// webhookDedupSweepEvery is how often expired keys are purged.
+	webhookDedupSweepEvery = time.Minute
+)
+
+// webhookDedup is a bounded LRU of recently seen webhook keys. Lookups and
+// inserts are O(1); expired entries are purged by a background sweep rather
+// than on the request path.
+type webhookDedup struct {
+	mu       sync.Mutex
+	capacity int
+	now      func() time.Time
+	order    *list.List // front = most recently inserted
+	entries  map[string]*list.Element
+}
+
+type webhookDedupEntry struct {
+	key       string
+	expiresAt time.Time
+}
+
+func newWebhookDedup(capacity int, now func() time.Time) *webhookDedup {
+	return &webhookDedup{
+		capacity: capacity,
+		now:      now,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element, capacity),
+	}
+}
+
+// seen records key for window and reports whether it was already recorded
+// and still within its window.
+func (d *webhookDedup) seen(key string, window time.Duration) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := d.now()
+	if el, ok := d.entries[key]; ok {
+		entry := el.Value.(*webhookDedupEntry)
+		if now.Before(entry.expiresAt) {
+			return true
+		}
+		entry.expiresAt = now.Add(window)
+		d.order.MoveToFront(el)
+		return false
+	}
+	d.entries[key] = d.order.PushFront(&webhookDedupEntry{key: key, expiresAt: now.Add(window)})
+	if d.order.Len() > d.capacity {
+		d.remove(d.order.Back())
+	}
+	return false
+}
+
+// sweep drops expired entries.
+func (d *webhookDedup) sweep() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := d.now()
+	for el := d.order.Back(); el != nil; {
+		prev := el.Prev()
+		if !now.Before(el.Value.(*webhookDedupEntry).expiresAt) {
+			d.remove(el)
+		}
+		el = prev
+	}
+}
+
+func (d *webhookDedup) remove(el *list.Element) {
+	d.order.Remove(el)
+	delete(d.entries, el.Value.(*webhookDedupEntry).key)
+}
+
+var (
+	defaultWebhookDedup   = newWebhookDedup(webhookDedupCapacity, time.Now)
+	webhookDedupSweepOnce sync.Once
+)
+
+// dropIfDuplicate reports whether key was already seen within window, in
+// which case the caller should ignore the delivery.
+func dropIfDuplicate(key string, window time.Duration) bool {
+	webhookDedupSweepOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(webhookDedupSweepEvery)
+			defer ticker.Stop()
+			for range ticker.C {
+				defaultWebhookDedup.sweep()
+			}
+		}()
+	})
+	return defaultWebhookDedup.seen(key, window)
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestWebhookDedup_DropsDuplicatesWithinWindow(t *testing.T) {
+	now := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	d := newWebhookDedup(100, func() time.Time { return now })
+
+	if d.seen("tg:1", time.Minute) {
+		t.Fatalf("first delivery must not be a duplicate")
+	}
+	now = now.Add(30 * time.Second)
+	if !d.seen("tg:1", time.Minute) {
+		t.Fatalf("redelivery within window must be a duplicate")
+	}
+	now = now.Add(time.Minute)
+	if d.seen("tg:1", time.Minute) {
+		t.Fatalf("delivery after window must not be a duplicate")
+	}
+}
+
+func TestWebhookDedup_BoundedAndSwept(t *testing.T) {
+	now := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	d := newWebhookDedup(3, func() time.Time { return now })
+	for i := 0; i < 5; i++ {
+		d.seen("k"+strconv.Itoa(i), time.Minute)
+	}
+	if got := len(d.entries); got != 3 {
+		t.Fatalf("expected capacity 3, got %d", got)
+	}
+	if d.seen("k4", time.Minute) != true {
+		t.Fatalf("newest key must still be remembered")
+	}
+
+	now = now.Add(2 * time.Minute)
+	d.sweep()
+	if got := len(d.entries); got != 0 || d.order.Len() != 0 {
+		t.Fatalf("expected all entries swept, got %d", got)
+	}
+}
+
+func BenchmarkWebhookDedup_ManyKeys(b *testing.B) {
+	for _, n := range []int{1_000, 100_000} {
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			d := newWebhookDedup(n, time.Now)
+			for i := 0; i < n; i++ {
+				d.seen("warm:"+strconv.Itoa(i), time.Hour)
+			}
+			keys := make([]string, b.N)
+			for i := range keys {
+				keys[i] = "k:" + strconv.Itoa(i)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				d.seen(keys[i], time.Hour)
+			}
+		})
+	}
+}
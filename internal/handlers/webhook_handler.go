@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"turcompany/internal/models"
+	"turcompany/internal/services"
+)
+
+type WebhookHandler struct {
+	svc *services.WebhookService
+}
+
+func NewWebhookHandler(svc *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{svc: svc}
+}
+
+// List — GET /api/v1/webhooks
+// Только для админа (проверяется в роутере через RequireRoles). Секреты не отдаются.
+func (h *WebhookHandler) List(c *gin.Context) {
+	subs, err := h.svc.List(c.Request.Context())
+	if err != nil {
+		log.Printf("[webhook][list][err] %v", err)
+		internalError(c, "Failed to list webhooks")
+		return
+	}
+	if subs == nil {
+		subs = []models.WebhookSubscription{}
+	}
+	c.JSON(http.StatusOK, gin.H{"data": subs})
+}
+
+// Create — POST /api/v1/webhooks
+// Секрет возвращается только в этом ответе; пустой secret генерируется сервером.
+func (h *WebhookHandler) Create(c *gin.Context) {
+	userID, _ := getUserAndRole(c)
+	var req struct {
+		URL        string   `json:"url" binding:"required"`
+		Secret     string   `json:"secret"`
+		EventTypes []string `json:"event_types"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, "Invalid payload")
+		return
+	}
+
+	sub, err := h.svc.Register(c.Request.Context(), req.URL, req.Secret, req.EventTypes, userID)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidWebhookURL) || errors.Is(err, services.ErrUnknownWebhookEvent) {
+			badRequest(c, err.Error())
+			return
+		}
+		log.Printf("[webhook][create][err] %v", err)
+		internalError(c, "Failed to create webhook")
+		return
+	}
+	log.Printf("[webhook][create][ok] id=%d by userID=%d", sub.ID, userID)
+	c.JSON(http.StatusCreated, sub)
+}
+
+// Delete — DELETE /api/v1/webhooks/:id
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		badRequest(c, "Invalid id")
+		return
+	}
+	if err := h.svc.Delete(c.Request.Context(), id); err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			notFound(c, NotFoundCode, "Webhook not found")
+			return
+		}
+		log.Printf("[webhook][delete][err] id=%d: %v", id, err)
+		internalError(c, "Failed to delete webhook")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
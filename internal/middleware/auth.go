@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"crypto/rsa"
 	"errors"
 	"log"
 	"net/http"
@@ -11,6 +12,9 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// DefaultJWTIssuer is the iss claim used when security.jwt_issuer is not set.
+const DefaultJWTIssuer = "kub-api"
+
 type Claims struct {
 	UserID int `json:"user_id"`
 	RoleID int `json:"role_id"`
@@ -19,7 +23,7 @@ type Claims struct {
 
 func isPublicPath(path string) bool {
 	switch path {
-	case "/register", "/register/confirm", "/register/resend", "/auth/login", "/auth/refresh":
+	case "/register", "/register/confirm", "/register/resend", "/verify-email", "/auth/login", "/auth/refresh":
 		return true
 	case "/auth/forgot-password", "/auth/reset-password":
 		return true
@@ -57,7 +61,39 @@ func extractBearerToken(authHeader string) string {
 	return strings.TrimSpace(parts[1])
 }
 
+// RevocationChecker reports whether an access token was revoked by its jti.
+type RevocationChecker interface {
+	IsRevoked(jti string) bool
+}
+
+// AuthOptions tunes NewAuthMiddlewareWithOptions.
+type AuthOptions struct {
+	// Issuer is the required iss claim; empty means DefaultJWTIssuer.
+	Issuer string
+	// Revocations, when set, rejects tokens whose jti was revoked (logout).
+	Revocations RevocationChecker
+	// PublicKey, when set, switches verification to RS256: only RS256 tokens
+	// are accepted and the shared secret is no longer used.
+	PublicKey *rsa.PublicKey
+}
+
 func NewAuthMiddleware(jwtSecret []byte) gin.HandlerFunc {
+	return NewAuthMiddlewareWithOptions(jwtSecret, AuthOptions{})
+}
+
+// NewAuthMiddlewareWithOptions is NewAuthMiddleware with a configurable
+// issuer and revocation list.
+func NewAuthMiddlewareWithOptions(jwtSecret []byte, opts AuthOptions) gin.HandlerFunc {
+	issuer := strings.TrimSpace(opts.Issuer)
+	if issuer == "" {
+		issuer = DefaultJWTIssuer
+	}
+	method := jwt.SigningMethodHS256.Alg()
+	var verifyKey interface{} = jwtSecret
+	if opts.PublicKey != nil {
+		method = jwt.SigningMethodRS256.Alg()
+		verifyKey = opts.PublicKey
+	}
 	return func(c *gin.Context) {
 		if c.Request.Method == http.MethodOptions {
 			c.Next()
@@ -86,12 +122,14 @@ func NewAuthMiddleware(jwtSecret []byte) gin.HandlerFunc {
 		}
 
 		claims := &Claims{}
+		// Принимаем только настроенный алгоритм: HS256-токен, подписанный
+		// публичным ключом как секретом, не должен пройти при RS256.
 		token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			if token.Method.Alg() != method {
 				return nil, jwt.ErrTokenSignatureInvalid
 			}
-			return jwtSecret, nil
-		})
+			return verifyKey, nil
+		}, jwt.WithIssuer(issuer), jwt.WithValidMethods([]string{method}))
 		if err != nil || !token.Valid {
 			reason := "invalid_token"
 			message := "Invalid token"
@@ -102,6 +140,8 @@ func NewAuthMiddleware(jwtSecret []byte) gin.HandlerFunc {
 			case errors.Is(err, jwt.ErrTokenSignatureInvalid):
 				reason = "invalid_signature"
 				message = "Invalid token signature"
+			case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+				reason = "invalid_issuer"
 			}
 			log.Printf("[auth][middleware] unauthorized: reason=%s path=%s method=%s err=%v", reason, c.Request.URL.Path, c.Request.Method, err)
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": message})
@@ -116,8 +156,16 @@ func NewAuthMiddleware(jwtSecret []byte) gin.HandlerFunc {
 			return
 		}
 
+		if opts.Revocations != nil && claims.ID != "" && opts.Revocations.IsRevoked(claims.ID) {
+			log.Printf("[auth][middleware] unauthorized: reason=revoked_token path=%s method=%s user_id=%d", c.Request.URL.Path, c.Request.Method, claims.UserID)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token revoked"})
+			return
+		}
+
 		c.Set("user_id", claims.UserID)
 		c.Set("role_id", claims.RoleID)
+		c.Set("token_id", claims.ID)
+		c.Set("token_expires_at", claims.ExpiresAt.Time)
 		c.Next()
 	}
 }
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func rs256Claims() *Claims {
+	now := time.Now().UTC()
+	return &Claims{UserID: 1, RoleID: 2, RegisteredClaims: jwt.RegisteredClaims{
+		Issuer:    DefaultJWTIssuer,
+		IssuedAt:  jwt.NewNumericDate(now.Add(-time.Minute)),
+		ExpiresAt: jwt.NewNumericDate(now.Add(10 * time.Minute)),
+	}}
+}
+
+func serveWithToken(r *gin.Engine, token string) int {
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestAuthMiddleware_RS256(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := []byte("01234567890123456789012345678901")
+	pubDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+
+	rs256, err := jwt.NewWithClaims(jwt.SigningMethodRS256, rs256Claims()).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Классическая подмена алгоритма: HS256, где секретом служит публичный ключ.
+	confused, err := jwt.NewWithClaims(jwt.SigningMethodHS256, rs256Claims()).SignedString(pubPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hs256, err := jwt.NewWithClaims(jwt.SigningMethodHS256, rs256Claims()).SignedString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rsRouter := gin.New()
+	rsRouter.Use(NewAuthMiddlewareWithOptions(secret, AuthOptions{PublicKey: &key.PublicKey}))
+	rsRouter.GET("/protected", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	if code := serveWithToken(rsRouter, rs256); code != http.StatusOK {
+		t.Fatalf("RS256 token must verify with the public key, got %d", code)
+	}
+	if code := serveWithToken(rsRouter, confused); code != http.StatusUnauthorized {
+		t.Fatalf("HS256 token signed with the public key must be rejected, got %d", code)
+	}
+	if code := serveWithToken(rsRouter, hs256); code != http.StatusUnauthorized {
+		t.Fatalf("HS256 token must be rejected when RS256 is configured, got %d", code)
+	}
+
+	hsRouter := gin.New()
+	hsRouter.Use(NewAuthMiddleware(secret))
+	hsRouter.GET("/protected", func(c *gin.Context) { c.Status(http.StatusOK) })
+	if code := serveWithToken(hsRouter, rs256); code != http.StatusUnauthorized {
+		t.Fatalf("RS256 token must be rejected when HS256 is configured, got %d", code)
+	}
+	if code := serveWithToken(hsRouter, hs256); code != http.StatusOK {
+		t.Fatalf("HS256 stays the default, got %d", code)
+	}
+}
@@ -52,12 +52,89 @@ func TestAuthMiddleware_ExpiredTokenReturns401(t *testing.T) {
 	}
 }
 
+func TestAuthMiddleware_RequiresConfiguredIssuer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	secret := []byte("01234567890123456789012345678901")
+
+	r := gin.New()
+	r.Use(NewAuthMiddlewareWithOptions(secret, AuthOptions{Issuer: "kub-test"}))
+	r.GET("/protected", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	now := time.Now().UTC()
+	for _, tc := range []struct {
+		issuer string
+		want   int
+	}{
+		{"kub-test", http.StatusOK},
+		{"someone-else", http.StatusUnauthorized},
+		{"", http.StatusUnauthorized},
+	} {
+		token := signTokenWithIssuer(t, secret, tc.issuer, now.Add(-time.Minute), now.Add(10*time.Minute))
+		req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Fatalf("issuer %q: unexpected status: got=%d want=%d", tc.issuer, w.Code, tc.want)
+		}
+	}
+}
+
+type revokedSet map[string]bool
+
+func (s revokedSet) IsRevoked(jti string) bool { return s[jti] }
+
+func TestAuthMiddleware_RejectsRevokedToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	secret := []byte("01234567890123456789012345678901")
+	now := time.Now().UTC()
+	sign := func(jti string) string {
+		claims := &Claims{UserID: 1, RoleID: 2, RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			Issuer:    DefaultJWTIssuer,
+			IssuedAt:  jwt.NewNumericDate(now.Add(-time.Minute)),
+			ExpiresAt: jwt.NewNumericDate(now.Add(10 * time.Minute)),
+		}}
+		s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+		if err != nil {
+			t.Fatalf("SignedString returned error: %v", err)
+		}
+		return s
+	}
+
+	r := gin.New()
+	r.Use(NewAuthMiddlewareWithOptions(secret, AuthOptions{Revocations: revokedSet{"gone": true}}))
+	r.GET("/protected", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	for jti, want := range map[string]int{"gone": http.StatusUnauthorized, "live": http.StatusOK} {
+		req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+		req.Header.Set("Authorization", "Bearer "+sign(jti))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Fatalf("jti %q: unexpected status: got=%d want=%d", jti, w.Code, want)
+		}
+	}
+}
+
 func signToken(t *testing.T, secret []byte, iat, exp time.Time) string {
+	t.Helper()
+	return signTokenWithIssuer(t, secret, DefaultJWTIssuer, iat, exp)
+}
+
+func signTokenWithIssuer(t *testing.T, secret []byte, issuer string, iat, exp time.Time) string {
 	t.Helper()
 	claims := &Claims{
 		UserID: 1,
 		RoleID: 2,
 		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
 			IssuedAt:  jwt.NewNumericDate(iat),
 			ExpiresAt: jwt.NewNumericDate(exp),
 		},
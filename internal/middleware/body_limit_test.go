@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMaxBodyBytes_RejectsOversizedBodyBeforeBinding(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	bound := 0
+	r := gin.New()
+	r.Use(MaxBodyBytes(64, 128))
+	r.POST("/tasks", func(c *gin.Context) {
+		var payload map[string]any
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		bound++
+		c.Status(http.StatusCreated)
+	})
+	r.POST("/upload", MaxUploadBytes(128), func(c *gin.Context) {
+		if _, err := io.ReadAll(c.Request.Body); err != nil {
+			c.Status(http.StatusRequestEntityTooLarge)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	big := `{"title":"` + strings.Repeat("x", 100) + `"}`
+	for _, tc := range []struct {
+		name    string
+		path    string
+		ctype   string
+		body    string
+		chunked bool
+		want    int
+	}{
+		{"small json", "/tasks", "application/json", `{"title":"ok"}`, false, http.StatusCreated},
+		{"oversized json", "/tasks", "application/json", big, false, http.StatusRequestEntityTooLarge},
+		{"oversized chunked json", "/tasks", "application/json", big, true, http.StatusRequestEntityTooLarge},
+		{"multipart within upload limit", "/upload", "multipart/form-data; boundary=x", strings.Repeat("y", 100), false, http.StatusOK},
+		{"multipart over upload limit", "/upload", "multipart/form-data; boundary=x", strings.Repeat("y", 200), false, http.StatusRequestEntityTooLarge},
+		{"chunked multipart over upload limit", "/upload", "multipart/form-data; boundary=x", strings.Repeat("y", 200), true, http.StatusRequestEntityTooLarge},
+	} {
+		req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", tc.ctype)
+		if tc.chunked {
+			req.ContentLength = -1
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Fatalf("%s: got %d want %d", tc.name, w.Code, tc.want)
+		}
+	}
+	if bound != 1 {
+		t.Fatalf("only the small body may reach binding, bound=%d", bound)
+	}
+}
+
+func TestMaxBodyBytes_CapsMultipartOnJSONRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(MaxBodyBytes(64, 128))
+	reached := false
+	r.POST("/tasks", func(c *gin.Context) {
+		reached = true
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(strings.Repeat("x", 256)))
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=xyz")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("multipart over upload limit: expected 413, got %d", w.Code)
+	}
+	if reached {
+		t.Fatalf("handler must not run for an oversized multipart body")
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(strings.Repeat("x", 100)))
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=xyz")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("multipart within upload limit: expected 200, got %d", w.Code)
+	}
+}
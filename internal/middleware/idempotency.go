@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/models"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	idempotencyReplayMimeType = "application/json; charset=utf-8"
+)
+
+// IdempotencyStore is implemented by services.IdempotencyService.
+type IdempotencyStore interface {
+	Begin(ctx context.Context, userID int, scope, key string) (*models.IdempotencyKey, bool, error)
+	Complete(ctx context.Context, id int64, status int, body []byte) error
+	Release(ctx context.Context, id int64) error
+}
+
+// Idempotency makes a create endpoint safe to retry: a request carrying an
+// Idempotency-Key header runs once per user and route, and repeats with the
+// same key get the original response back. Requests without the header are
+// passed through untouched. Only 2xx responses are remembered; a failed
+// request releases its key so the client can retry with it.
+func Idempotency(store IdempotencyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := strings.TrimSpace(c.GetHeader(IdempotencyKeyHeader))
+		if store == nil || key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key is too long"})
+			return
+		}
+		userV, _ := c.Get("user_id")
+		userID, _ := userV.(int)
+		if userID <= 0 {
+			c.Next()
+			return
+		}
+
+		scope := c.Request.Method + " " + c.FullPath()
+		rec, claimed, err := store.Begin(c.Request.Context(), userID, scope, key)
+		if err != nil {
+			// Fail open: a broken idempotency store must not block creation.
+			log.Printf("[idempotency] begin failed: scope=%q user_id=%d err=%v", scope, userID, err)
+			c.Next()
+			return
+		}
+		if !claimed {
+			if rec.ResponseStatus == 0 {
+				c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "request with this Idempotency-Key is still in progress"})
+				return
+			}
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(rec.ResponseStatus, idempotencyReplayMimeType, rec.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		w := &bodyCaptureWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+
+		// The client may have gone away; the outcome still has to be recorded.
+		ctx := context.WithoutCancel(c.Request.Context())
+		status := w.Status()
+		if status >= http.StatusOK && status < http.StatusMultipleChoices {
+			if err := store.Complete(ctx, rec.ID, status, w.body.Bytes()); err != nil {
+				log.Printf("[idempotency] complete failed: scope=%q user_id=%d err=%v", scope, userID, err)
+			}
+			return
+		}
+		if err := store.Release(ctx, rec.ID); err != nil {
+			log.Printf("[idempotency] release failed: scope=%q user_id=%d err=%v", scope, userID, err)
+		}
+	}
+}
+
+type bodyCaptureWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyCaptureWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyCaptureWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
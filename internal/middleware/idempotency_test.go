@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/models"
+)
+
+type stubIdempotencyStore struct {
+	rec       *models.IdempotencyKey
+	claimed   bool
+	completed int
+	released  int
+}
+
+func (s *stubIdempotencyStore) Begin(context.Context, int, string, string) (*models.IdempotencyKey, bool, error) {
+	return s.rec, s.claimed, nil
+}
+
+func (s *stubIdempotencyStore) Complete(context.Context, int64, int, []byte) error {
+	s.completed++
+	return nil
+}
+
+func (s *stubIdempotencyStore) Release(context.Context, int64) error {
+	s.released++
+	return nil
+}
+
+func newIdempotencyTestRouter(store IdempotencyStore, status int) *gin.Engine {
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", 1)
+		c.Next()
+	})
+	r.POST("/deals", Idempotency(store), func(c *gin.Context) {
+		c.JSON(status, gin.H{"id": 1})
+	})
+	return r
+}
+
+func doIdempotentPost(r *gin.Engine) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/deals", nil)
+	req.Header.Set(IdempotencyKeyHeader, "k1")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotency_FailedRequestReleasesKey(t *testing.T) {
+	store := &stubIdempotencyStore{rec: &models.IdempotencyKey{ID: 5}, claimed: true}
+	w := doIdempotentPost(newIdempotencyTestRouter(store, http.StatusBadRequest))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+	if store.released != 1 || store.completed != 0 {
+		t.Fatalf("expected release only, got released=%d completed=%d", store.released, store.completed)
+	}
+}
+
+func TestIdempotency_InFlightKeyConflicts(t *testing.T) {
+	store := &stubIdempotencyStore{rec: &models.IdempotencyKey{ID: 5}, claimed: false}
+	w := doIdempotentPost(newIdempotencyTestRouter(store, http.StatusCreated))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", w.Code)
+	}
+}
+
+func TestIdempotency_ReplaysStoredResponse(t *testing.T) {
+	store := &stubIdempotencyStore{rec: &models.IdempotencyKey{ID: 5, ResponseStatus: http.StatusCreated, ResponseBody: []byte(`{"id":42}`)}}
+	w := doIdempotentPost(newIdempotencyTestRouter(store, http.StatusCreated))
+	if w.Code != http.StatusCreated || w.Body.String() != `{"id":42}` {
+		t.Fatalf("expected stored response, got %d %s", w.Code, w.Body.String())
+	}
+	if w.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Fatalf("expected %s header", IdempotentReplayedHeader)
+	}
+}
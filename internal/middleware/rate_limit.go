@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateLimitSweepEvery controls how often idle buckets are dropped.
+const rateLimitSweepEvery = 1024
+
+// RateLimit throttles a route group with a token bucket per caller and
+// route: each bucket holds up to limit requests and refills at limit per
+// window. The caller is the authenticated user_id, or the client IP on
+// public routes. Rejected requests get 429 with Retry-After in seconds.
+// A non-positive limit or window disables the limiter.
+func RateLimit(limit int, window time.Duration) gin.HandlerFunc {
+	if limit <= 0 || window <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return newRateLimiter(limit, window, time.Now).handle
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type rateLimiter struct {
+	capacity float64
+	perSec   float64
+	window   time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	calls   int
+}
+
+func newRateLimiter(limit int, window time.Duration, now func() time.Time) *rateLimiter {
+	return &rateLimiter{
+		capacity: float64(limit),
+		perSec:   float64(limit) / window.Seconds(),
+		window:   window,
+		now:      now,
+		buckets:  make(map[string]*tokenBucket),
+	}
+}
+
+func (l *rateLimiter) handle(c *gin.Context) {
+	key := rateLimitKey(c)
+	ok, retryAfter := l.take(key)
+	if ok {
+		c.Next()
+		return
+	}
+	secs := int(math.Ceil(retryAfter.Seconds()))
+	if secs < 1 {
+		secs = 1
+	}
+	log.Printf("[rate-limit] rejected key=%q retry_after=%ds", key, secs)
+	c.Header("Retry-After", strconv.Itoa(secs))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests, please retry later"})
+}
+
+// take spends one token from the bucket for key. When the bucket is empty it
+// reports how long until the next token is available.
+func (l *rateLimiter) take(key string) (bool, time.Duration) {
+	now := l.now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.calls++
+	if l.calls%rateLimitSweepEvery == 0 {
+		l.sweep(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: l.capacity, last: now}
+		l.buckets[key] = b
+	} else if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(l.capacity, b.tokens+elapsed*l.perSec)
+		b.last = now
+	}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	missing := 1 - b.tokens
+	return false, time.Duration(missing / l.perSec * float64(time.Second))
+}
+
+// sweep drops buckets that have been idle long enough to be full again.
+func (l *rateLimiter) sweep(now time.Time) {
+	for k, b := range l.buckets {
+		if now.Sub(b.last) >= l.window {
+			delete(l.buckets, k)
+		}
+	}
+}
+
+func rateLimitKey(c *gin.Context) string {
+	route := c.FullPath()
+	if route == "" {
+		route = c.Request.URL.Path
+	}
+	route = c.Request.Method + " " + route
+	if v, ok := c.Get("user_id"); ok {
+		if uid, ok := v.(int); ok && uid > 0 {
+			return "user:" + strconv.Itoa(uid) + "|" + route
+		}
+	}
+	return "ip:" + c.ClientIP() + "|" + route
+}
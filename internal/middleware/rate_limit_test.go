@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type fakeClock struct{ t time.Time }
+
+func (f *fakeClock) now() time.Time { return f.t }
+
+func newRateLimitTestRouter(l *rateLimiter, userID int) *gin.Engine {
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if userID > 0 {
+			c.Set("user_id", userID)
+		}
+		c.Next()
+	})
+	r.POST("/documents/create-from-lead", l.handle, func(c *gin.Context) { c.Status(http.StatusCreated) })
+	r.POST("/documents/upload", l.handle, func(c *gin.Context) { c.Status(http.StatusCreated) })
+	return r
+}
+
+func doRateLimited(r *gin.Engine, path, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	req.RemoteAddr = ip + ":1234"
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimit_RejectsRequestOverLimit(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+	r := newRateLimitTestRouter(newRateLimiter(3, time.Minute, clock.now), 7)
+
+	for i := 1; i <= 3; i++ {
+		if w := doRateLimited(r, "/documents/create-from-lead", "10.0.0.1"); w.Code != http.StatusCreated {
+			t.Fatalf("request %d: expected 201, got %d", i, w.Code)
+		}
+	}
+	w := doRateLimited(r, "/documents/create-from-lead", "10.0.0.1")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("4th request: expected 429, got %d", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "20" {
+		t.Fatalf("expected Retry-After 20, got %q", got)
+	}
+}
+
+func TestRateLimit_BucketRefills(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+	r := newRateLimitTestRouter(newRateLimiter(2, time.Minute, clock.now), 7)
+
+	doRateLimited(r, "/documents/create-from-lead", "10.0.0.1")
+	doRateLimited(r, "/documents/create-from-lead", "10.0.0.1")
+	if w := doRateLimited(r, "/documents/create-from-lead", "10.0.0.1"); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 once bucket is empty, got %d", w.Code)
+	}
+
+	// 2 запроса в минуту → один токен каждые 30с
+	clock.t = clock.t.Add(30 * time.Second)
+	if w := doRateLimited(r, "/documents/create-from-lead", "10.0.0.1"); w.Code != http.StatusCreated {
+		t.Fatalf("expected 201 after partial refill, got %d", w.Code)
+	}
+	if w := doRateLimited(r, "/documents/create-from-lead", "10.0.0.1"); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 after spending the refilled token, got %d", w.Code)
+	}
+
+	clock.t = clock.t.Add(10 * time.Minute)
+	for i := 1; i <= 2; i++ {
+		if w := doRateLimited(r, "/documents/create-from-lead", "10.0.0.1"); w.Code != http.StatusCreated {
+			t.Fatalf("request %d after full refill: expected 201, got %d", i, w.Code)
+		}
+	}
+	if w := doRateLimited(r, "/documents/create-from-lead", "10.0.0.1"); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("bucket must not grow beyond limit, got %d", w.Code)
+	}
+}
+
+func TestRateLimit_KeyedByUserAndRoute(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+	l := newRateLimiter(1, time.Minute, clock.now)
+	user7 := newRateLimitTestRouter(l, 7)
+	user8 := newRateLimitTestRouter(l, 8)
+
+	if w := doRateLimited(user7, "/documents/create-from-lead", "10.0.0.1"); w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", w.Code)
+	}
+	if w := doRateLimited(user7, "/documents/upload", "10.0.0.1"); w.Code != http.StatusCreated {
+		t.Fatalf("another route must have its own bucket, got %d", w.Code)
+	}
+	if w := doRateLimited(user8, "/documents/create-from-lead", "10.0.0.1"); w.Code != http.StatusCreated {
+		t.Fatalf("another user on the same IP must have its own bucket, got %d", w.Code)
+	}
+	if w := doRateLimited(user7, "/documents/create-from-lead", "10.0.0.2"); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("user bucket must not depend on IP, got %d", w.Code)
+	}
+}
+
+func TestRateLimit_FallsBackToClientIP(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+	r := newRateLimitTestRouter(newRateLimiter(1, time.Minute, clock.now), 0)
+
+	if w := doRateLimited(r, "/documents/create-from-lead", "10.0.0.1"); w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", w.Code)
+	}
+	if w := doRateLimited(r, "/documents/create-from-lead", "10.0.0.1"); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 for the same IP, got %d", w.Code)
+	}
+	if w := doRateLimited(r, "/documents/create-from-lead", "10.0.0.2"); w.Code != http.StatusCreated {
+		t.Fatalf("expected 201 for another IP, got %d", w.Code)
+	}
+}
+
+func TestRateLimit_DisabledWithNonPositiveLimit(t *testing.T) {
+	r := gin.New()
+	r.POST("/x", RateLimit(0, time.Minute), func(c *gin.Context) { c.Status(http.StatusOK) })
+	for i := 0; i < 5; i++ {
+		if w := doRateLimited(r, "/x", "10.0.0.1"); w.Code != http.StatusOK {
+			t.Fatalf("disabled limiter must pass requests, got %d", w.Code)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestTimeout attaches a deadline of d to the request context, so that
+// repository calls made with c.Request.Context() are cancelled once it
+// passes. WebSocket upgrades are long-lived and keep the original context;
+// upload routes drop the deadline again in MaxUploadBytes. A non-positive d
+// disables the timeout.
+func RequestTimeout(d time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if d <= 0 || isWebSocketRequest(c.Request) {
+			c.Next()
+			return
+		}
+		c.Set(untimedContextKey, c.Request.Context())
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// untimedContextKey holds the request context as it was before
+// RequestTimeout added its deadline.
+const untimedContextKey = "request_timeout.untimed_ctx"
+
+// dropRequestTimeout restores the context RequestTimeout replaced, so a large
+// upload over a slow link is not cut off. Client disconnects still cancel it.
+func dropRequestTimeout(c *gin.Context) {
+	if ctx, ok := c.Value(untimedContextKey).(context.Context); ok {
+		c.Request = c.Request.WithContext(ctx)
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestTimeout_SetsDeadlineExceptForWebSocket(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestTimeout(time.Minute))
+	var hasDeadline bool
+	r.GET("/x", func(c *gin.Context) {
+		_, hasDeadline = c.Request.Context().Deadline()
+		c.Status(http.StatusOK)
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/x", nil))
+	if !hasDeadline {
+		t.Fatal("regular request must get a deadline")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/x", nil)
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	if hasDeadline {
+		t.Fatal("websocket upgrade must keep the original context")
+	}
+}
+
+func TestRequestTimeout_UploadRoutesKeepTheUntimedContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestTimeout(time.Minute))
+	hasDeadline := map[string]bool{}
+	record := func(c *gin.Context) {
+		_, hasDeadline[c.FullPath()] = c.Request.Context().Deadline()
+		c.Status(http.StatusOK)
+	}
+	r.POST("/tasks", record)
+	r.POST("/upload", MaxUploadBytes(1<<20), record)
+
+	for _, path := range []string{"/tasks", "/upload"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, path, strings.NewReader("x")))
+	}
+	if !hasDeadline["/tasks"] {
+		t.Fatal("regular route must keep the deadline")
+	}
+	if hasDeadline["/upload"] {
+		t.Fatal("upload route must not get the request deadline")
+	}
+}
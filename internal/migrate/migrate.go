@@ -0,0 +1,123 @@
+// Package migrate applies the embedded db/migrations/*.up.sql files in
+// filename order and records each applied version in schema_migrations.
+//
+// The files are written to be idempotent because scripts/run-migrations.sh
+// re-applies all of them on every deploy; this runner only applies versions
+// that are not yet recorded, so it is safe to use alongside the script.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"sort"
+	"strings"
+)
+
+const upSuffix = ".up.sql"
+
+// advisoryLockKey serialises concurrent runners (e.g. several replicas
+// booting at once).
+const advisoryLockKey = 72_604_201
+
+type Migration struct {
+	Version string
+	File    string
+}
+
+// Plan lists the up migrations in fsys that are not in applied, ordered by
+// filename.
+func Plan(fsys fs.FS, applied map[string]bool) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "*"+upSuffix)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	var pending []Migration
+	for _, f := range files {
+		version := strings.TrimSuffix(path.Base(f), upSuffix)
+		if applied[version] {
+			continue
+		}
+		pending = append(pending, Migration{Version: version, File: f})
+	}
+	return pending, nil
+}
+
+// Apply runs every pending migration and returns the versions it applied.
+func Apply(ctx context.Context, db *sql.DB, fsys fs.FS) ([]string, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// Migrations may legitimately run longer than the pool's statement_timeout;
+	// lift it for this connection and restore the session default before the
+	// connection goes back to the pool.
+	if _, err := conn.ExecContext(ctx, `SET statement_timeout = 0`); err != nil {
+		return nil, fmt.Errorf("migrate: disable statement timeout: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), `RESET statement_timeout`)
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, advisoryLockKey); err != nil {
+		return nil, fmt.Errorf("migrate: acquire lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, advisoryLockKey)
+
+	if _, err := conn.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version    TEXT PRIMARY KEY,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+)`); err != nil {
+		return nil, fmt.Errorf("migrate: create schema_migrations: %w", err)
+	}
+
+	applied, err := appliedVersions(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := Plan(fsys, applied)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []string
+	for _, m := range pending {
+		body, err := fs.ReadFile(fsys, m.File)
+		if err != nil {
+			return done, fmt.Errorf("migrate: read %s: %w", m.File, err)
+		}
+		// Files manage their own BEGIN/COMMIT where needed, so they are sent
+		// as-is rather than wrapped in a transaction here.
+		if _, err := conn.ExecContext(ctx, string(body)); err != nil {
+			return done, fmt.Errorf("migrate: apply %s: %w", m.File, err)
+		}
+		if _, err := conn.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES ($1) ON CONFLICT DO NOTHING`, m.Version); err != nil {
+			return done, fmt.Errorf("migrate: record %s: %w", m.Version, err)
+		}
+		log.Printf("[migrate] applied %s", m.Version)
+		done = append(done, m.Version)
+	}
+	return done, nil
+}
+
+func appliedVersions(ctx context.Context, conn *sql.Conn) (map[string]bool, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: list applied: %w", err)
+	}
+	defer rows.Close()
+	applied := make(map[string]bool)
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		applied[v] = true
+	}
+	return applied, rows.Err()
+}
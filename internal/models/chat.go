@@ -21,6 +21,7 @@ type Chat struct {
 	ParticipantsPreview []ChatParticipantLite `json:"participants_preview,omitempty"`
 	MemberProfiles      []ChatParticipantLite `json:"member_profiles,omitempty"`
 	LastMessageText     string                `json:"last_message_text"`
+	LastMessageSenderID int                   `json:"last_message_sender_id,omitempty"`
 	LastMessageAt       time.Time             `json:"last_message_at"`
 	Online              bool                  `json:"online"`
 	LastSeen            time.Time             `json:"last_seen"`
@@ -19,6 +19,7 @@ type Deals struct {
 	Currency      string     `json:"currency"`
 	Status        string     `json:"status"`
 	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
 	ExtraJSON     string     `json:"extra_json" db:"extra_json"`
 	IsArchived    bool       `json:"is_archived"`
 	ArchivedAt    *time.Time `json:"archived_at,omitempty"`
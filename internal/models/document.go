@@ -21,6 +21,12 @@ type Document struct {
 	SignUserAgent string     `json:"sign_user_agent,omitempty"` // User-Agent браузера
 	SignMetadata  string     `json:"sign_metadata,omitempty"`   // JSON с метаданными подписи
 	SignedBy      string     `json:"signed_by,omitempty"`
+	// Кто отметил документ подписанным и sha256 файла на тот момент
+	SignedByUserID *int64 `json:"signed_by_user_id,omitempty"`
+	SignatureHash  string `json:"signature_hash,omitempty"`
+	// Кто и когда одобрил или вернул документ на ревью
+	ReviewedByUserID *int64    `json:"reviewed_by_user_id,omitempty"`
+	ReviewedAt       *time.Time `json:"reviewed_at,omitempty"`
 	IsArchived    bool       `json:"is_archived"`
 	ArchivedAt    *time.Time `json:"archived_at,omitempty"`
 	ArchivedBy    *int       `json:"archived_by,omitempty"`
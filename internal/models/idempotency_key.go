@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// IdempotencyKey remembers the response of a create request so a retry with
+// the same Idempotency-Key header gets the original resource back.
+type IdempotencyKey struct {
+	ID             int64     `db:"id"`
+	UserID         int       `db:"user_id"`
+	Scope          string    `db:"scope"`
+	Key            string    `db:"idem_key"`
+	ResponseStatus int       `db:"response_status"` // 0 while the request is in flight
+	ResponseBody   []byte    `db:"response_body"`
+	CreatedAt      time.Time `db:"created_at"`
+	ExpiresAt      time.Time `db:"expires_at"`
+}
@@ -11,14 +11,46 @@ type Leads struct {
 	Phone         string     `json:"phone"`
 	Source        string     `json:"source"`
 	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
 	OwnerID       int        `json:"owner_id"`
 	BranchID      *int       `json:"branch_id,omitempty"`
 	BranchName    string     `json:"branch_name,omitempty"`
 	DepartmentID  *int       `json:"department_id,omitempty"`
 	FunnelID      *int       `json:"funnel_id,omitempty"`
 	Status        string     `json:"status"`
+	Score         *int       `json:"score,omitempty"`
 	IsArchived    bool       `json:"is_archived"`
 	ArchivedAt    *time.Time `json:"archived_at,omitempty"`
 	ArchivedBy    *int       `json:"archived_by,omitempty"`
 	ArchiveReason string     `json:"archive_reason,omitempty"`
 }
+
+// LeadSourceUnknown is the source of leads created without one, including
+// every lead that predates source tracking.
+const LeadSourceUnknown = "unknown"
+
+// leadSources lists the accepted lead sources: manual entry and marketing
+// channels, plus the integration channels (wazzup, binotel) that create leads.
+var leadSources = map[string]bool{
+	LeadSourceUnknown: true,
+	"manual":          true,
+	"web":             true,
+	"referral":        true,
+	"cold_call":       true,
+	"phone":           true,
+	"whatsapp":        true,
+	"telegram":        true,
+	"instagram":       true,
+	"binotel":         true,
+}
+
+// Lead score bounds; a lead without a score has not been rated yet.
+const (
+	LeadScoreMin = 0
+	LeadScoreMax = 100
+)
+
+// IsKnownLeadSource reports whether source is an accepted lead source.
+func IsKnownLeadSource(source string) bool {
+	return leadSources[source]
+}
@@ -13,3 +13,11 @@ type PaginatedResponse[T any] struct {
 	Items      []T            `json:"items"`
 	Pagination PaginationMeta `json:"pagination"`
 }
+
+// CursorPage is the ?cursor= (keyset) list response. NextCursor is empty on
+// the last page.
+type CursorPage[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasNext    bool   `json:"has_next"`
+}
@@ -13,6 +13,12 @@ type RevenueRow struct {
 	Currency    string  `db:"currency" json:"currency"`
 }
 
+type OwnerRevenueRow struct {
+	OwnerID     int     `db:"owner_id" json:"owner_id"`
+	TotalAmount float64 `db:"total_amount" json:"total_amount"`
+	Currency    string  `db:"currency" json:"currency"`
+}
+
 type TopClientRow struct {
 	ClientID    int     `db:"client_id" json:"client_id"`
 	ClientType  string  `db:"client_type" json:"client_type"`
@@ -26,3 +32,10 @@ type LeadSummaryRow struct {
 	Source string `db:"source" json:"source"`
 	Count  int64  `db:"count" json:"count"`
 }
+
+// LeadSourceRow counts the leads of one source and how many of them converted.
+type LeadSourceRow struct {
+	Source    string `db:"source" json:"source"`
+	Total     int64  `db:"total" json:"total"`
+	Converted int64  `db:"converted" json:"converted"`
+}
@@ -36,28 +36,50 @@ type Task struct {
 	Description    string       `json:"description"`
 	DueDate        *time.Time   `json:"due_date,omitempty"`
 	ReminderAt     *time.Time   `json:"reminder_at,omitempty"`
+	ReminderOffset *int64       `json:"reminder_offset_seconds,omitempty"` // seconds before DueDate; when set, ReminderAt is derived from it
 	LastRemindedAt *time.Time   `json:"last_reminded_at,omitempty"`
 	Priority       TaskPriority `json:"priority"`
 	Status         TaskStatus   `json:"status"`
 	CreatedAt      time.Time    `json:"created_at"`
 	UpdatedAt      time.Time    `json:"updated_at"`
+	LastModifiedBy *int64       `json:"last_modified_by,omitempty"` // user behind the latest edit, status or assignee change
+	Version        int          `json:"version"`                    // bumped on every write; PUT /tasks/:id checks it
 	IsArchived     bool         `json:"is_archived"`
 	ArchivedAt     *time.Time   `json:"archived_at,omitempty"`
 	ArchivedBy     *int64       `json:"archived_by,omitempty"`
 	ArchiveReason  string       `json:"archive_reason,omitempty"`
+	IsOverdue      bool         `json:"is_overdue"` // computed per response, not stored
+	// AllowedTransitions lists the statuses the caller may move the task to;
+	// computed on single-task responses, absent when there are none.
+	AllowedTransitions []TaskStatus `json:"allowed_transitions,omitempty"`
+}
+
+// Overdue reports whether the task is past its due date and still open.
+func (t *Task) Overdue(now time.Time) bool {
+	if t.DueDate == nil || t.Status == StatusDone || t.Status == StatusCancelled {
+		return false
+	}
+	return t.DueDate.Before(now)
 }
 
 // TaskFilter defines the available parameters for filtering tasks.
 type TaskFilter struct {
-	AssigneeID  *int64
-	CreatorID   *int64
-	EntityID    *int64
-	EntityType  *string
-	Status      *TaskStatus
+	AssigneeID *int64
+	CreatorID  *int64
+	EntityID   *int64
+	EntityType *string
+	// Statuses keeps tasks in any of the listed statuses; it overrides StatusGroup.
+	Statuses    []TaskStatus
+	Priority    *TaskPriority
 	StatusGroup string
 	Query       string
 	SortBy      string
 	Order       string
 	Archive     string
 	BranchID    *int64
+	// ParticipantID limits the list to tasks the user created or is assigned to.
+	ParticipantID *int64
+	// Overdue keeps only open tasks whose due date has passed when true and
+	// excludes them when false; nil applies no overdue filter.
+	Overdue *bool
 }
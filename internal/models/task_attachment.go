@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// TaskAttachment is a file uploaded to a task. StorageKey is internal and
+// never leaves the API; clients download through the task attachment route.
+type TaskAttachment struct {
+	ID         int64     `json:"id"`
+	TaskID     int64     `json:"task_id"`
+	FileName   string    `json:"file_name"`
+	MimeType   string    `json:"mime_type"`
+	SizeBytes  int64     `json:"size_bytes"`
+	StorageKey string    `json:"-"`
+	UploadedBy *int64    `json:"uploaded_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// UserRoleEvent records a single role change made by an administrator.
+type UserRoleEvent struct {
+	ID        int64     `json:"id"`
+	UserID    int       `json:"user_id"`
+	ActorID   int       `json:"actor_id"`
+	OldRoleID int       `json:"old_role_id"`
+	NewRoleID int       `json:"new_role_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -2,14 +2,20 @@ package pdf
 
 import (
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/jung-kurt/gofpdf"
 )
 
+// DealFilesDir — подкаталог RootDir, где лежат файлы, сгенерированные для
+// конкретной сделки: <RootDir>/deals/<dealID>/<file>.
+const DealFilesDir = "deals"
+
 // Generator — интерфейс (удобно мокать в тестах)
 type Generator interface {
 	GenerateContract(data ContractData) (string, error)
@@ -18,11 +24,18 @@ type Generator interface {
 	GenerateFromTemplate(templateName string, placeholders map[string]string, filename string) (string, error)
 }
 
+// FontConfig — TTF-файлы для PDF.
+type FontConfig struct {
+	RegularPath string // обычное начертание, например "assets/fonts/DejaVuSans.ttf"
+	BoldPath    string // полужирное, например "assets/fonts/DejaVuSans-Bold.ttf"; может отсутствовать
+}
+
 // DocumentGenerator — реализация
 type DocumentGenerator struct {
 	RootDir      string // корень хранения PDF, например "./files"
 	TemplatesDir string // корень шаблонов, например "./assets/templates"
 	FontPath     string // путь до TTF, например "assets/fonts/DejaVuSans.ttf"
+	BoldFontPath string // путь до полужирного TTF; пусто — синтетический жирный
 	fontName     string // внутреннее имя шрифта в PDF
 }
 
@@ -47,14 +60,23 @@ type InvoiceData struct {
 // NewDocumentGenerator создаёт генератор
 // rootDir      — куда складывать PDF (например, "files")
 // templatesDir — откуда брать .txt шаблоны (например, "assets/templates")
-// fontPath     — путь к TTF-шрифту (например, "assets/fonts/DejaVuSans.ttf")
-func NewDocumentGenerator(rootDir, templatesDir, fontPath string) *DocumentGenerator {
-	return &DocumentGenerator{
+// fonts        — TTF-шрифты; если файла fonts.BoldPath нет, стиль "B"
+// рисуется обычным начертанием с обводкой (синтетический жирный)
+func NewDocumentGenerator(rootDir, templatesDir string, fonts FontConfig) *DocumentGenerator {
+	g := &DocumentGenerator{
 		RootDir:      filepath.Clean(rootDir),
 		TemplatesDir: filepath.Clean(templatesDir),
-		FontPath:     fontPath,
+		FontPath:     fonts.RegularPath,
 		fontName:     "DejaVu",
 	}
+	if bold := strings.TrimSpace(fonts.BoldPath); bold != "" {
+		if info, err := os.Stat(bold); err == nil && !info.IsDir() {
+			g.BoldFontPath = bold
+		} else {
+			log.Printf("[pdf] bold font %s not available, using synthetic bold: %v", bold, err)
+		}
+	}
+	return g
 }
 
 // ======================= CONTRACT =======================
@@ -64,7 +86,7 @@ func (g *DocumentGenerator) GenerateContract(data ContractData) (string, error)
 	if filename == "" {
 		filename = fmt.Sprintf("contract_deal_%d.pdf", data.DealID)
 	}
-	absPath, err := g.ensureTarget(filename)
+	absPath, err := g.ensureTarget(g.dealDir(data.DealID), filename)
 	if err != nil {
 		return "", err
 	}
@@ -79,10 +101,10 @@ func (g *DocumentGenerator) GenerateContract(data ContractData) (string, error)
 	pdf.AddPage()
 
 	// ===== Заголовок
-	pdf.SetFont(g.fontName, "B", 18)
+	g.setFont(pdf, "B", 18)
 	pdf.CellFormat(0, 10, "ДОГОВОР", "", 1, "C", false, 0, "")
 
-	pdf.SetFont(g.fontName, "", 12)
+	g.setFont(pdf, "", 12)
 	sub := fmt.Sprintf("№ KUB-%06d  от  %s",
 		data.DealID,
 		data.CreatedAt.Format("02.01.2006"),
@@ -106,7 +128,7 @@ func (g *DocumentGenerator) GenerateContract(data ContractData) (string, error)
 	pdf.Ln(1)
 
 	// Короткая вводная
-	pdf.SetFont(g.fontName, "", 11)
+	g.setFont(pdf, "", 11)
 	intro := "Стороны договорились о предоставлении услуг в соответствии с условиями настоящего договора. " +
 		"Подробные условия, сроки и порядок расчётов определяются Соглашением и Приложениями к нему."
 	pdf.MultiCell(0, 6, intro, "", "L", false)
@@ -115,7 +137,7 @@ func (g *DocumentGenerator) GenerateContract(data ContractData) (string, error)
 
 	// ===== Условия
 	g.sectionTitle(pdf, "Основные условия")
-	pdf.SetFont(g.fontName, "", 11)
+	g.setFont(pdf, "", 11)
 	terms := []string{
 		"1. Срок оказания услуг определяется календарным планом и согласуется Сторонами.",
 		"2. Заказчик обязуется оплатить услуги Исполнителя в размере, указанном выше.",
@@ -133,7 +155,7 @@ func (g *DocumentGenerator) GenerateContract(data ContractData) (string, error)
 	pdf.Ln(6)
 
 	lineY := pdf.GetY()
-	pdf.SetFont(g.fontName, "", 11)
+	g.setFont(pdf, "", 11)
 	pdf.CellFormat(80, 6, "Исполнитель", "", 0, "L", false, 0, "")
 	pdf.CellFormat(30, 6, "", "", 0, "L", false, 0, "")
 	pdf.CellFormat(80, 6, "Заказчик", "", 1, "L", false, 0, "")
@@ -157,7 +179,7 @@ func (g *DocumentGenerator) GenerateContract(data ContractData) (string, error)
 	pdf.AliasNbPages("")
 	pdf.SetFooterFunc(func() {
 		pdf.SetY(-15)
-		pdf.SetFont(g.fontName, "", 10)
+		g.setFont(pdf, "", 10)
 		pdf.CellFormat(0, 10,
 			fmt.Sprintf("Стр. %d/{nb}", pdf.PageNo()),
 			"", 0, "C", false, 0, "",
@@ -177,19 +199,19 @@ func (g *DocumentGenerator) GenerateInvoice(data InvoiceData) (string, error) {
 	if filename == "" {
 		filename = fmt.Sprintf("invoice_deal_%d.pdf", data.DealID)
 	}
-	absPath, err := g.ensureTarget(filename)
+	absPath, err := g.ensureTarget(g.dealDir(data.DealID), filename)
 	if err != nil {
 		return "", err
 	}
 
 	pdf := gofpdf.New("P", "mm", "A4", "")
 	g.addUTF8Font(pdf)
-	pdf.SetFont(g.fontName, "", 14)
+	g.setFont(pdf, "", 14)
 	pdf.SetMargins(20, 20, 20)
 	pdf.SetAutoPageBreak(true, 20)
 	pdf.AddPage()
 
-	pdf.SetFont(g.fontName, "B", 16)
+	g.setFont(pdf, "B", 16)
 	pdf.SetY(20)
 	center := (210 - pdf.GetStringWidth("СЧЕТ")) / 2
 	if center < 10 {
@@ -267,7 +289,7 @@ func (g *DocumentGenerator) GenerateFromTemplate(
 		filename = fmt.Sprintf("doc_%d.pdf", time.Now().Unix())
 	}
 
-	absPath, err := g.ensureTarget(filename)
+	absPath, err := g.ensureTarget(g.pdfDir(), filename)
 	if err != nil {
 		return "", err
 	}
@@ -280,7 +302,7 @@ func (g *DocumentGenerator) GenerateFromTemplate(
 
 	g.addUTF8Font(pdf)
 	pdf.AddPage()
-	pdf.SetFont(g.fontName, "", 11)
+	g.setFont(pdf, "", 11)
 
 	lines := strings.Split(content, "\n")
 	for _, line := range lines {
@@ -298,15 +320,15 @@ func (g *DocumentGenerator) GenerateFromTemplate(
 // ======================= HELPERS =======================
 
 func (g *DocumentGenerator) sectionTitle(pdf *gofpdf.Fpdf, s string) {
-	pdf.SetFont(g.fontName, "B", 12)
+	g.setFont(pdf, "B", 12)
 	pdf.CellFormat(0, 7, s, "", 1, "L", false, 0, "")
-	pdf.SetFont(g.fontName, "", 11)
+	g.setFont(pdf, "", 11)
 }
 
 func (g *DocumentGenerator) kvLine(pdf *gofpdf.Fpdf, key, val string) {
-	pdf.SetFont(g.fontName, "B", 11)
+	g.setFont(pdf, "B", 11)
 	pdf.CellFormat(45, 6, key+":", "", 0, "L", false, 0, "")
-	pdf.SetFont(g.fontName, "", 11)
+	g.setFont(pdf, "", 11)
 	pdf.CellFormat(0, 6, val, "", 1, "L", false, 0, "")
 }
 
@@ -317,13 +339,12 @@ func (g *DocumentGenerator) hr(pdf *gofpdf.Fpdf) {
 	pdf.SetY(y + 2)
 }
 
-func (g *DocumentGenerator) ensureTarget(filename string) (string, error) {
-	pdfDir := g.pdfDir()
-	if err := os.MkdirAll(pdfDir, 0o755); err != nil {
+func (g *DocumentGenerator) ensureTarget(dir, filename string) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return "", fmt.Errorf("create files dir: %w", err)
 	}
 	filename = filepath.Base(filename) // безопасность
-	return filepath.Join(pdfDir, filename), nil
+	return filepath.Join(dir, filename), nil
 }
 
 func (g *DocumentGenerator) pdfDir() string {
@@ -333,18 +354,52 @@ func (g *DocumentGenerator) pdfDir() string {
 	return filepath.Join(g.RootDir, "pdf")
 }
 
+// dealDir — каталог файлов сделки: <RootDir>/deals/<dealID>.
+// Без ID сделки (0) файлы складываются в общий pdf/.
+func (g *DocumentGenerator) dealDir(dealID int) string {
+	if dealID <= 0 {
+		return g.pdfDir()
+	}
+	if g.RootDir == "" {
+		g.RootDir = "files"
+	}
+	return filepath.Join(g.RootDir, DealFilesDir, strconv.Itoa(dealID))
+}
+
+// relativePath — путь относительно RootDir вида "/deals/7/contract_deal_7.pdf".
 func (g *DocumentGenerator) relativePath(absPath string) string {
-	filename := filepath.Base(absPath)
-	return "/" + filepath.ToSlash(filepath.Join("pdf", filename))
+	rel, err := filepath.Rel(g.RootDir, absPath)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		rel = filepath.Join("pdf", filepath.Base(absPath))
+	}
+	return "/" + filepath.ToSlash(rel)
 }
 
 func (g *DocumentGenerator) addUTF8Font(pdf *gofpdf.Fpdf) {
 	pdf.AddUTF8Font(g.fontName, "", g.FontPath)
+	if g.BoldFontPath != "" {
+		pdf.AddUTF8Font(g.fontName, "B", g.BoldFontPath)
+		return
+	}
 	pdf.AddUTF8Font(g.fontName, "B", g.FontPath)
 }
 
+// setFont выставляет шрифт; без полужирного TTF стиль "B" имитируется
+// режимом «заливка + обводка».
+func (g *DocumentGenerator) setFont(pdf *gofpdf.Fpdf, style string, size float64) {
+	pdf.SetFont(g.fontName, style, size)
+	if g.BoldFontPath != "" {
+		return
+	}
+	if strings.Contains(style, "B") {
+		pdf.SetTextRenderingMode(2)
+	} else {
+		pdf.SetTextRenderingMode(0)
+	}
+}
+
 func (g *DocumentGenerator) addLines(pdf *gofpdf.Fpdf, lines []string) {
-	pdf.SetFont(g.fontName, "", 12)
+	g.setFont(pdf, "", 12)
 	left := 20.0
 	for _, line := range lines {
 		pdf.SetX(left)
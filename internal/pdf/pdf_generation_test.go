@@ -0,0 +1,92 @@
+package pdf
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+const (
+	testRegularFont = "../../assets/fonts/DejaVuSans.ttf"
+	testBoldFont    = "../../assets/fonts/DejaVuSans-Bold.ttf"
+)
+
+func TestAddUTF8Font_RegistersDistinctBoldFace(t *testing.T) {
+	g := NewDocumentGenerator(t.TempDir(), t.TempDir(), FontConfig{RegularPath: testRegularFont, BoldPath: testBoldFont})
+	if g.BoldFontPath != testBoldFont {
+		t.Fatalf("bold font must be used when the file exists, got %q", g.BoldFontPath)
+	}
+
+	doc := gofpdf.New("P", "mm", "A4", "")
+	doc.SetCompression(false)
+	g.addUTF8Font(doc)
+	doc.AddPage()
+	g.setFont(doc, "", 12)
+	doc.Cell(40, 10, "Договор")
+	g.setFont(doc, "B", 12)
+	doc.Cell(40, 10, "ДОГОВОР")
+
+	var buf bytes.Buffer
+	if err := doc.Output(&buf); err != nil {
+		t.Fatalf("output: %v", err)
+	}
+	// Начертания различаются толщиной штриха в дескрипторе шрифта.
+	stems := map[string]bool{}
+	for _, m := range regexp.MustCompile(`/StemV (\d+)`).FindAllSubmatch(buf.Bytes(), -1) {
+		stems[string(m[1])] = true
+	}
+	if len(stems) != 2 {
+		t.Fatalf("expected two distinct embedded faces, got StemV values %v", stems)
+	}
+	if bytes.Contains(buf.Bytes(), []byte("\n2 Tr")) {
+		t.Fatal("synthetic bold must not be used when a bold face is configured")
+	}
+}
+
+func TestNewDocumentGenerator_MissingBoldFallsBackToSynthetic(t *testing.T) {
+	g := NewDocumentGenerator(t.TempDir(), t.TempDir(), FontConfig{
+		RegularPath: testRegularFont,
+		BoldPath:    filepath.Join(t.TempDir(), "missing-bold.ttf"),
+	})
+	if g.BoldFontPath != "" {
+		t.Fatalf("missing bold font must be ignored, got %q", g.BoldFontPath)
+	}
+
+	rel, err := g.GenerateContract(ContractData{LeadTitle: "ТОО Ромашка", DealID: 3, Amount: "100.00", Currency: "KZT", CreatedAt: time.Now()})
+	if err != nil {
+		t.Fatalf("generate contract with synthetic bold: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(g.RootDir, filepath.FromSlash(rel))); err != nil {
+		t.Fatalf("generated file missing: %v", err)
+	}
+
+	doc := gofpdf.New("P", "mm", "A4", "")
+	doc.SetCompression(false)
+	g.addUTF8Font(doc)
+	doc.AddPage()
+	g.setFont(doc, "B", 12)
+	doc.Cell(40, 10, "ДОГОВОР")
+	var buf bytes.Buffer
+	if err := doc.Output(&buf); err != nil {
+		t.Fatalf("output: %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("\n2 Tr")) {
+		t.Fatal("expected synthetic bold (fill+stroke text rendering)")
+	}
+}
+
+func TestGenerateContract_StoresUnderDealDir(t *testing.T) {
+	g := NewDocumentGenerator(t.TempDir(), t.TempDir(), FontConfig{RegularPath: testRegularFont, BoldPath: testBoldFont})
+	rel, err := g.GenerateContract(ContractData{LeadTitle: "ТОО Ромашка", DealID: 7, Amount: "100.00", Currency: "KZT", CreatedAt: time.Now()})
+	if err != nil {
+		t.Fatalf("generate contract: %v", err)
+	}
+	if rel != "/deals/7/contract_deal_7.pdf" {
+		t.Fatalf("unexpected relative path %q", rel)
+	}
+}
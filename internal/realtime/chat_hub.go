@@ -1,7 +1,9 @@
 package realtime
 
 import (
+	"errors"
 	"log"
+	"sort"
 	"sync"
 	"time"
 
@@ -9,6 +11,10 @@ import (
 	"turcompany/internal/repositories"
 )
 
+// ErrTooManyConnections is returned by Register when the per-user or global
+// connection limit would be exceeded.
+var ErrTooManyConnections = errors.New("too many websocket connections")
+
 type presence struct {
 	Online   bool
 	LastSeen time.Time
@@ -48,6 +54,19 @@ type ChatHub struct {
 
 	presence   map[int]presence
 	presenceMu sync.RWMutex
+
+	// online mirrors chats for readers outside the event loop: chat id ->
+	// users with at least one open stream to that chat.
+	online   map[int]map[int]struct{}
+	onlineMu sync.RWMutex
+
+	// Connection limits are tracked outside the event loop so Register can
+	// reject synchronously. A non-positive limit disables the check.
+	limitsMu     sync.Mutex
+	maxPerUser   int
+	maxTotal     int
+	connOwners   map[*Conn]int
+	connsPerUser map[int]int
 }
 
 func NewChatHub(repo repositories.ChatRepository) *ChatHub {
@@ -62,9 +81,21 @@ func NewChatHub(repo repositories.ChatRepository) *ChatHub {
 		notifyEvent:  make(chan chatEventNotification, 128),
 		stop:         make(chan struct{}),
 		presence:     make(map[int]presence),
+		online:       make(map[int]map[int]struct{}),
+		connOwners:   make(map[*Conn]int),
+		connsPerUser: make(map[int]int),
 	}
 }
 
+// SetConnectionLimits caps how many streams a single user and the whole hub
+// may hold open. A non-positive value disables the respective limit.
+func (h *ChatHub) SetConnectionLimits(perUser, total int) {
+	h.limitsMu.Lock()
+	defer h.limitsMu.Unlock()
+	h.maxPerUser = perUser
+	h.maxTotal = total
+}
+
 // Run starts the hub event loop. Should be launched in a dedicated goroutine.
 func (h *ChatHub) Run() {
 	for {
@@ -92,8 +123,15 @@ func (h *ChatHub) Stop() {
 	close(h.stop)
 }
 
-func (h *ChatHub) Register(chatID int, userID int, conn *Conn) {
+// Register subscribes conn to chat updates. It returns ErrTooManyConnections
+// without registering when a connection limit is reached; the caller is then
+// responsible for closing conn.
+func (h *ChatHub) Register(chatID int, userID int, conn *Conn) error {
+	if err := h.acquireSlot(userID, conn); err != nil {
+		return err
+	}
 	h.register <- subscription{chatID: chatID, userID: userID, conn: conn}
+	return nil
 }
 
 func (h *ChatHub) Unregister(chatID int, userID int, conn *Conn) {
@@ -164,23 +202,33 @@ func (h *ChatHub) handleRegister(sub subscription) {
 	if h.chats[sub.chatID][sub.userID] == nil {
 		h.chats[sub.chatID][sub.userID] = make(map[*Conn]struct{})
 	}
+	joined := len(h.chats[sub.chatID][sub.userID]) == 0
 	h.chats[sub.chatID][sub.userID][sub.conn] = struct{}{}
 	h.setPresence(sub.userID, true)
 	if err := h.repo.SetOnline(sub.userID, true); err != nil {
 		log.Printf("[chat_hub] failed to set user %d online: %v", sub.userID, err)
 	}
+	if joined {
+		h.setChatOnline(sub.chatID, sub.userID, true)
+		h.notifyPresence(sub.chatID, sub.userID, "presence:join")
+	}
 }
 
 func (h *ChatHub) handleUnregister(sub subscription) {
 	if conns, ok := h.chats[sub.chatID]; ok {
 		if userConns, ok := conns[sub.userID]; ok {
-			if _, exists := userConns[sub.conn]; exists {
+			_, exists := userConns[sub.conn]
+			if exists {
 				delete(userConns, sub.conn)
 			}
 			stillOnline := len(userConns) > 0
 			if len(userConns) == 0 {
 				delete(conns, sub.userID)
 			}
+			if exists && !stillOnline {
+				h.setChatOnline(sub.chatID, sub.userID, false)
+				h.notifyPresence(sub.chatID, sub.userID, "presence:leave")
+			}
 			h.setPresence(sub.userID, stillOnline)
 			if !stillOnline {
 				if err := h.repo.SetOnline(sub.userID, false); err != nil {
@@ -192,11 +240,43 @@ func (h *ChatHub) handleUnregister(sub subscription) {
 			delete(h.chats, sub.chatID)
 		}
 	}
+	h.releaseSlot(sub.conn)
 	if err := sub.conn.Close(); err != nil {
 		log.Printf("[chat_hub] error closing websocket: %v", err)
 	}
 }
 
+func (h *ChatHub) acquireSlot(userID int, conn *Conn) error {
+	h.limitsMu.Lock()
+	defer h.limitsMu.Unlock()
+	if h.maxTotal > 0 && len(h.connOwners) >= h.maxTotal {
+		return ErrTooManyConnections
+	}
+	if h.maxPerUser > 0 && h.connsPerUser[userID] >= h.maxPerUser {
+		return ErrTooManyConnections
+	}
+	h.connOwners[conn] = userID
+	h.connsPerUser[userID]++
+	return nil
+}
+
+// releaseSlot frees the slot held by conn. It is safe to call more than once
+// for the same connection.
+func (h *ChatHub) releaseSlot(conn *Conn) {
+	h.limitsMu.Lock()
+	defer h.limitsMu.Unlock()
+	userID, ok := h.connOwners[conn]
+	if !ok {
+		return
+	}
+	delete(h.connOwners, conn)
+	if h.connsPerUser[userID] <= 1 {
+		delete(h.connsPerUser, userID)
+	} else {
+		h.connsPerUser[userID]--
+	}
+}
+
 func (h *ChatHub) handleBroadcast(msg *models.ChatMessage) {
 	conns := h.chats[msg.ChatID]
 	for userID, userConns := range conns {
@@ -276,6 +356,53 @@ func (h *ChatHub) shutdown() {
 		}
 		delete(h.chats, chatID)
 	}
+	h.onlineMu.Lock()
+	h.online = make(map[int]map[int]struct{})
+	h.onlineMu.Unlock()
+	h.limitsMu.Lock()
+	h.connOwners = make(map[*Conn]int)
+	h.connsPerUser = make(map[int]int)
+	h.limitsMu.Unlock()
+}
+
+// notifyPresence tells everyone connected to the chat that userID joined or
+// left, together with the resulting online set.
+func (h *ChatHub) notifyPresence(chatID, userID int, eventType string) {
+	h.handleNotifyEvent(chatEventNotification{chatID: chatID, payload: struct {
+		Type   string `json:"type"`
+		ChatID int    `json:"chat_id"`
+		UserID int    `json:"user_id"`
+		Online []int  `json:"online"`
+	}{Type: eventType, ChatID: chatID, UserID: userID, Online: h.OnlineMembers(chatID)}})
+}
+
+func (h *ChatHub) setChatOnline(chatID, userID int, online bool) {
+	h.onlineMu.Lock()
+	defer h.onlineMu.Unlock()
+	if online {
+		if h.online[chatID] == nil {
+			h.online[chatID] = make(map[int]struct{})
+		}
+		h.online[chatID][userID] = struct{}{}
+		return
+	}
+	delete(h.online[chatID], userID)
+	if len(h.online[chatID]) == 0 {
+		delete(h.online, chatID)
+	}
+}
+
+// OnlineMembers returns the ids of users with an open stream to the chat,
+// in ascending order.
+func (h *ChatHub) OnlineMembers(chatID int) []int {
+	h.onlineMu.RLock()
+	defer h.onlineMu.RUnlock()
+	ids := make([]int, 0, len(h.online[chatID]))
+	for id := range h.online[chatID] {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
 }
 
 // PresenceSnapshot returns online status for provided users.
@@ -0,0 +1,156 @@
+package realtime
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"turcompany/internal/repositories"
+)
+
+func newPipeConn(t *testing.T) *Conn {
+	t.Helper()
+	server, client := net.Pipe()
+	t.Cleanup(func() {
+		_ = server.Close()
+		_ = client.Close()
+	})
+	return &Conn{conn: server}
+}
+
+func TestChatHubRegister_EnforcesPerUserLimit(t *testing.T) {
+	hub := NewChatHub(nil)
+	hub.SetConnectionLimits(2, 0)
+
+	for i := 0; i < 2; i++ {
+		if err := hub.Register(1, 7, newPipeConn(t)); err != nil {
+			t.Fatalf("register %d: %v", i, err)
+		}
+	}
+	if err := hub.Register(1, 7, newPipeConn(t)); !errors.Is(err, ErrTooManyConnections) {
+		t.Fatalf("expected ErrTooManyConnections for third connection, got %v", err)
+	}
+	if err := hub.Register(1, 8, newPipeConn(t)); err != nil {
+		t.Fatalf("other user must not be affected by per-user limit: %v", err)
+	}
+}
+
+func TestChatHubRegister_EnforcesGlobalLimitAndReleases(t *testing.T) {
+	hub := NewChatHub(nil)
+	hub.SetConnectionLimits(0, 1)
+
+	first := newPipeConn(t)
+	if err := hub.Register(1, 7, first); err != nil {
+		t.Fatalf("register first: %v", err)
+	}
+	if err := hub.Register(2, 8, newPipeConn(t)); !errors.Is(err, ErrTooManyConnections) {
+		t.Fatalf("expected ErrTooManyConnections over global limit, got %v", err)
+	}
+
+	hub.releaseSlot(first)
+	hub.releaseSlot(first)
+	if err := hub.Register(2, 8, newPipeConn(t)); err != nil {
+		t.Fatalf("expected slot to be free after release: %v", err)
+	}
+}
+
+type presenceChatRepo struct {
+	repositories.ChatRepository
+}
+
+func (presenceChatRepo) SetOnline(int, bool) error { return nil }
+
+// pipeWithEvents returns a server-side conn and a channel with the JSON
+// payloads the hub writes to it.
+func pipeWithEvents(t *testing.T) (*Conn, <-chan map[string]interface{}) {
+	t.Helper()
+	server, client := net.Pipe()
+	t.Cleanup(func() {
+		_ = server.Close()
+		_ = client.Close()
+	})
+	events := make(chan map[string]interface{}, 16)
+	go func() {
+		r := bufio.NewReader(client)
+		for {
+			var head [2]byte
+			if _, err := io.ReadFull(r, head[:]); err != nil {
+				return
+			}
+			n := int(head[1] & 0x7f)
+			if n == 126 {
+				var ext [2]byte
+				if _, err := io.ReadFull(r, ext[:]); err != nil {
+					return
+				}
+				n = int(binary.BigEndian.Uint16(ext[:]))
+			}
+			payload := make([]byte, n)
+			if _, err := io.ReadFull(r, payload); err != nil {
+				return
+			}
+			var evt map[string]interface{}
+			if json.Unmarshal(payload, &evt) == nil {
+				events <- evt
+			}
+		}
+	}()
+	return &Conn{conn: server}, events
+}
+
+func nextPresenceEvent(t *testing.T, events <-chan map[string]interface{}) (string, int, []int) {
+	t.Helper()
+	select {
+	case evt := <-events:
+		var online []int
+		for _, id := range evt["online"].([]interface{}) {
+			online = append(online, int(id.(float64)))
+		}
+		return evt["type"].(string), int(evt["user_id"].(float64)), online
+	case <-time.After(2 * time.Second):
+		t.Fatal("no presence event")
+		return "", 0, nil
+	}
+}
+
+func TestChatHubPresence_TracksJoinAndLeave(t *testing.T) {
+	hub := NewChatHub(presenceChatRepo{})
+	first, firstEvents := pipeWithEvents(t)
+	second, _ := pipeWithEvents(t)
+	secondAgain, _ := pipeWithEvents(t)
+
+	hub.handleRegister(subscription{chatID: 1, userID: 7, conn: first})
+	if typ, uid, online := nextPresenceEvent(t, firstEvents); typ != "presence:join" || uid != 7 || !reflect.DeepEqual(online, []int{7}) {
+		t.Fatalf("unexpected join event %s user=%d online=%v", typ, uid, online)
+	}
+	hub.handleRegister(subscription{chatID: 1, userID: 8, conn: second})
+	hub.handleRegister(subscription{chatID: 1, userID: 8, conn: secondAgain})
+	if typ, uid, online := nextPresenceEvent(t, firstEvents); typ != "presence:join" || uid != 8 || !reflect.DeepEqual(online, []int{7, 8}) {
+		t.Fatalf("unexpected join event %s user=%d online=%v", typ, uid, online)
+	}
+	if got := hub.OnlineMembers(1); !reflect.DeepEqual(got, []int{7, 8}) {
+		t.Fatalf("expected users 7 and 8 online, got %v", got)
+	}
+
+	// второй поток того же пользователя держит его в онлайне
+	hub.handleUnregister(subscription{chatID: 1, userID: 8, conn: second})
+	if got := hub.OnlineMembers(1); !reflect.DeepEqual(got, []int{7, 8}) {
+		t.Fatalf("user with another open stream must stay online, got %v", got)
+	}
+	hub.handleUnregister(subscription{chatID: 1, userID: 8, conn: secondAgain})
+	if typ, uid, online := nextPresenceEvent(t, firstEvents); typ != "presence:leave" || uid != 8 || !reflect.DeepEqual(online, []int{7}) {
+		t.Fatalf("unexpected leave event %s user=%d online=%v", typ, uid, online)
+	}
+	if got := hub.OnlineMembers(1); !reflect.DeepEqual(got, []int{7}) {
+		t.Fatalf("disconnected user must leave the presence set, got %v", got)
+	}
+	if got := hub.OnlineMembers(2); len(got) != 0 {
+		t.Fatalf("other chats must be empty, got %v", got)
+	}
+}
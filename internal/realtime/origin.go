@@ -0,0 +1,68 @@
+package realtime
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// ErrOriginNotAllowed is returned by Upgrade when the request's Origin header
+// is not on the allowlist.
+var ErrOriginNotAllowed = errors.New("websocket origin not allowed")
+
+var (
+	allowedOriginsMu sync.RWMutex
+	allowedOrigins   = map[string]struct{}{}
+	allowAnyOrigin   bool
+)
+
+// SetAllowedOrigins replaces the list of browser origins allowed to open a
+// WebSocket. It is normally the CORS allowlist; "*" allows every origin.
+func SetAllowedOrigins(origins []string) {
+	next := make(map[string]struct{}, len(origins))
+	anyOrigin := false
+	for _, raw := range origins {
+		origin := normalizeOrigin(raw)
+		if origin == "*" {
+			anyOrigin = true
+			continue
+		}
+		if origin != "" {
+			next[origin] = struct{}{}
+		}
+	}
+	allowedOriginsMu.Lock()
+	allowedOrigins = next
+	allowAnyOrigin = anyOrigin
+	allowedOriginsMu.Unlock()
+}
+
+// OriginAllowed reports whether r may be upgraded. Requests without an Origin
+// header come from non-browser clients and are allowed, as are same-host
+// requests; everything else must match the allowlist.
+func OriginAllowed(r *http.Request) bool {
+	raw := strings.TrimSpace(r.Header.Get("Origin"))
+	if raw == "" {
+		return true
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	if strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	allowedOriginsMu.RLock()
+	defer allowedOriginsMu.RUnlock()
+	if allowAnyOrigin {
+		return true
+	}
+	_, ok := allowedOrigins[normalizeOrigin(raw)]
+	return ok
+}
+
+func normalizeOrigin(raw string) string {
+	return strings.TrimRight(strings.ToLower(strings.TrimSpace(raw)), "/")
+}
@@ -0,0 +1,60 @@
+package realtime
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOriginAllowed(t *testing.T) {
+	SetAllowedOrigins([]string{"https://crm.example.com", "http://localhost:5173/"})
+	t.Cleanup(func() { SetAllowedOrigins(nil) })
+
+	tests := []struct {
+		name   string
+		origin string
+		want   bool
+	}{
+		{name: "no origin header", origin: "", want: true},
+		{name: "allowlisted", origin: "https://crm.example.com", want: true},
+		{name: "allowlisted trailing slash in config", origin: "http://localhost:5173", want: true},
+		{name: "case insensitive", origin: "HTTPS://CRM.EXAMPLE.COM", want: true},
+		{name: "same host", origin: "https://api.example.com", want: true},
+		{name: "foreign site", origin: "https://evil.example.net", want: false},
+		{name: "scheme mismatch", origin: "http://crm.example.com", want: false},
+		{name: "null origin", origin: "null", want: false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://api.example.com/api/v1/chats/1/stream", nil)
+			if tc.origin != "" {
+				req.Header.Set("Origin", tc.origin)
+			}
+			if got := OriginAllowed(req); got != tc.want {
+				t.Fatalf("OriginAllowed(%q) = %v, want %v", tc.origin, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestOriginAllowed_Wildcard(t *testing.T) {
+	SetAllowedOrigins([]string{"*"})
+	t.Cleanup(func() { SetAllowedOrigins(nil) })
+
+	req := httptest.NewRequest("GET", "http://api.example.com/ws", nil)
+	req.Header.Set("Origin", "https://anything.example.org")
+	if !OriginAllowed(req) {
+		t.Fatal("expected wildcard allowlist to accept any origin")
+	}
+}
+
+func TestUpgrade_RejectsDisallowedOrigin(t *testing.T) {
+	SetAllowedOrigins([]string{"https://crm.example.com"})
+	t.Cleanup(func() { SetAllowedOrigins(nil) })
+
+	req := httptest.NewRequest("GET", "http://api.example.com/ws", nil)
+	req.Header.Set("Origin", "https://evil.example.net")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	if _, err := Upgrade(httptest.NewRecorder(), req); err != ErrOriginNotAllowed {
+		t.Fatalf("expected ErrOriginNotAllowed, got %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package realtime
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// DefaultTicketTTL is how long a WebSocket ticket stays redeemable.
+const DefaultTicketTTL = 30 * time.Second
+
+// Ticket is the identity a WebSocket ticket was issued for.
+type Ticket struct {
+	UserID int
+	RoleID int
+}
+
+type ticketEntry struct {
+	Ticket
+	expiresAt time.Time
+}
+
+// TicketStore issues short-lived one-time tickets that authenticate a
+// WebSocket upgrade: browsers cannot set an Authorization header on
+// `new WebSocket(...)`, so the client fetches a ticket over the normal API
+// and passes it as ?ticket=. Tickets live in memory only, so they do not
+// survive a restart and are not shared between instances.
+type TicketStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	tickets map[string]ticketEntry
+}
+
+func NewTicketStore(ttl time.Duration) *TicketStore {
+	if ttl <= 0 {
+		ttl = DefaultTicketTTL
+	}
+	return &TicketStore{ttl: ttl, now: time.Now, tickets: map[string]ticketEntry{}}
+}
+
+// Issue returns a new ticket for the user and its expiry time.
+func (s *TicketStore) Issue(userID, roleID int) (string, time.Time, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", time.Time{}, err
+	}
+	token := hex.EncodeToString(buf)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	// Заодно выбрасываем просроченные, чтобы неиспользованные билеты не копились.
+	for k, e := range s.tickets {
+		if !now.Before(e.expiresAt) {
+			delete(s.tickets, k)
+		}
+	}
+	expiresAt := now.Add(s.ttl)
+	s.tickets[token] = ticketEntry{Ticket: Ticket{UserID: userID, RoleID: roleID}, expiresAt: expiresAt}
+	return token, expiresAt, nil
+}
+
+// Redeem consumes the ticket. It reports false for unknown, already used or
+// expired tickets.
+func (s *TicketStore) Redeem(token string) (Ticket, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.tickets[token]
+	if !ok {
+		return Ticket{}, false
+	}
+	delete(s.tickets, token)
+	if !s.now().Before(e.expiresAt) {
+		return Ticket{}, false
+	}
+	return e.Ticket, true
+}
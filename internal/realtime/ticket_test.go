@@ -0,0 +1,48 @@
+package realtime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTicketStore_RedeemsOnce(t *testing.T) {
+	s := NewTicketStore(time.Minute)
+	token, expiresAt, err := s.Issue(7, 10)
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	if token == "" || !expiresAt.After(time.Now()) {
+		t.Fatalf("unexpected ticket %q expiring at %s", token, expiresAt)
+	}
+	got, ok := s.Redeem(token)
+	if !ok || got != (Ticket{UserID: 7, RoleID: 10}) {
+		t.Fatalf("expected ticket for user 7, got %+v ok=%v", got, ok)
+	}
+	if _, ok := s.Redeem(token); ok {
+		t.Fatal("ticket must not be redeemable twice")
+	}
+	if _, ok := s.Redeem("unknown"); ok {
+		t.Fatal("unknown ticket must be rejected")
+	}
+}
+
+func TestTicketStore_ExpiredTicketIsRejectedAndPruned(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	s := NewTicketStore(30 * time.Second)
+	s.now = func() time.Time { return now }
+
+	stale, _, _ := s.Issue(7, 10)
+	now = now.Add(30 * time.Second)
+	if _, ok := s.Redeem(stale); ok {
+		t.Fatal("expired ticket must be rejected")
+	}
+
+	left, _, _ := s.Issue(7, 10)
+	now = now.Add(time.Minute)
+	if _, _, err := s.Issue(8, 10); err != nil {
+		t.Fatal(err)
+	}
+	if _, kept := s.tickets[left]; kept || len(s.tickets) != 1 {
+		t.Fatalf("expired tickets must be pruned on issue, have %d", len(s.tickets))
+	}
+}
@@ -1,6 +1,9 @@
 package realtime
 
 import (
+	"bufio"
+	"bytes"
+	"compress/flate"
 	"crypto/sha1"
 	"encoding/base64"
 	"encoding/binary"
@@ -10,16 +13,107 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
 
+// DefaultMaxFrameSize caps the payload length accepted from clients when no
+// explicit limit has been configured.
+const DefaultMaxFrameSize = 1 << 20
+
+var (
+	errFrameTooLarge   = errors.New("websocket frame exceeds maximum size")
+	errUnmaskedFrame   = errors.New("websocket client frame is not masked")
+	errReservedBitsSet = errors.New("websocket frame uses reserved bits that were not negotiated")
+	maxFrameSize       atomic.Int64
+)
+
+func init() {
+	maxFrameSize.Store(DefaultMaxFrameSize)
+}
+
+// SetMaxFrameSize sets the largest payload, in bytes, a client frame may
+// declare. A non-positive value restores DefaultMaxFrameSize.
+func SetMaxFrameSize(n int64) {
+	if n <= 0 {
+		n = DefaultMaxFrameSize
+	}
+	maxFrameSize.Store(n)
+}
+
+// ConnOptions tunes the handshake and read side of upgraded connections.
+type ConnOptions struct {
+	// HandshakeTimeout bounds writing the 101 response after hijacking.
+	HandshakeTimeout time.Duration
+	// ReadTimeout closes a connection that sends no frame, pongs included,
+	// for this long. While it is set the server pings every ReadTimeout/2, so
+	// idle clients that answer pings stay connected. Zero disables both.
+	ReadTimeout time.Duration
+	// ReadBufferSize is the size of the buffered reader wrapping the socket.
+	ReadBufferSize int
+}
+
+// DefaultConnOptions are used until SetConnOptions is called.
+var DefaultConnOptions = ConnOptions{
+	HandshakeTimeout: 10 * time.Second,
+	ReadTimeout:      10 * time.Minute,
+	ReadBufferSize:   4096,
+}
+
+var connOptions atomic.Value
+
+// SetConnOptions replaces the options applied by Upgrade. Non-positive
+// handshake timeout and buffer size fall back to DefaultConnOptions.
+func SetConnOptions(opts ConnOptions) {
+	if opts.HandshakeTimeout <= 0 {
+		opts.HandshakeTimeout = DefaultConnOptions.HandshakeTimeout
+	}
+	if opts.ReadTimeout < 0 {
+		opts.ReadTimeout = 0
+	}
+	if opts.ReadBufferSize <= 0 {
+		opts.ReadBufferSize = DefaultConnOptions.ReadBufferSize
+	}
+	connOptions.Store(opts)
+}
+
+func currentConnOptions() ConnOptions {
+	if opts, ok := connOptions.Load().(ConnOptions); ok {
+		return opts
+	}
+	return DefaultConnOptions
+}
+
+// CloseTryAgainLater is the close status sent when the server is refusing a
+// connection because of load (RFC 6455 registry, code 1013).
+const CloseTryAgainLater = 1013
+
+const (
+	closeProtocolError = 1002
+	closeMessageTooBig = 1009
+)
+
 // Conn is a minimal WebSocket connection supporting text frames.
 type Conn struct {
-	conn net.Conn
+	conn        net.Conn
+	reader      *bufio.Reader
+	readTimeout time.Duration
+	// deflate is set when permessage-deflate was negotiated in Upgrade.
+	deflate bool
+	// writeMu keeps frames from the keepalive pinger and other writers whole.
+	writeMu   sync.Mutex
+	done      chan struct{}
+	closeOnce sync.Once
 }
 
 func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	if !OriginAllowed(r) {
+		return nil, ErrOriginNotAllowed
+	}
 	key := r.Header.Get("Sec-WebSocket-Key")
 	if key == "" {
 		return nil, errors.New("missing websocket key")
@@ -32,9 +126,19 @@ func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
 	if err != nil {
 		return nil, err
 	}
+	opts := currentConnOptions()
+	if err := rawConn.SetDeadline(time.Now().Add(opts.HandshakeTimeout)); err != nil {
+		rawConn.Close()
+		return nil, err
+	}
 
 	accept := computeAcceptKey(key)
-	if _, err := fmt.Fprintf(buf, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", accept); err != nil {
+	deflate := negotiateDeflate(r.Header)
+	extensions := ""
+	if deflate {
+		extensions = "Sec-WebSocket-Extensions: " + permessageDeflateResponse + "\r\n"
+	}
+	if _, err := fmt.Fprintf(buf, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n%s\r\n", accept, extensions); err != nil {
 		rawConn.Close()
 		return nil, err
 	}
@@ -42,7 +146,39 @@ func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
 		rawConn.Close()
 		return nil, err
 	}
-	return &Conn{conn: rawConn}, nil
+	if err := rawConn.SetDeadline(time.Time{}); err != nil {
+		rawConn.Close()
+		return nil, err
+	}
+	// Wrap the hijacked reader so bytes the server already buffered are kept.
+	conn := &Conn{
+		conn:        rawConn,
+		reader:      bufio.NewReaderSize(buf.Reader, opts.ReadBufferSize),
+		readTimeout: opts.ReadTimeout,
+		deflate:     deflate,
+		done:        make(chan struct{}),
+	}
+	if opts.ReadTimeout > 0 {
+		go conn.keepAlive(opts.ReadTimeout / 2)
+	}
+	return conn, nil
+}
+
+// keepAlive pings the client until the connection is closed or a write
+// fails; the client's pongs refresh the read deadline in readFrame.
+func (c *Conn) keepAlive(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			if err := c.writeFrame(0x9, nil); err != nil {
+				return
+			}
+		}
+	}
 }
 
 func computeAcceptKey(key string) string {
@@ -68,54 +204,110 @@ func (c *Conn) WriteJSON(v interface{}) error {
 	if err != nil {
 		return err
 	}
+	if c.deflate && len(data) >= deflateMinSize {
+		compressed, err := deflatePayload(data)
+		if err != nil {
+			return err
+		}
+		return c.writeFrame(rsv1|0x1, compressed)
+	}
 	return c.writeFrame(0x1, data)
 }
 
 func (c *Conn) Close() error {
+	c.stopKeepAlive()
 	_ = c.writeFrame(0x8, []byte{})
 	return c.conn.Close()
 }
 
+// CloseWithReason sends a close frame carrying the given status code and
+// reason before closing the underlying connection.
+func (c *Conn) CloseWithReason(code uint16, reason string) error {
+	c.stopKeepAlive()
+	_ = c.writeFrame(0x8, closePayload(code, reason))
+	return c.conn.Close()
+}
+
+func (c *Conn) stopKeepAlive() {
+	if c.done == nil {
+		return
+	}
+	c.closeOnce.Do(func() { close(c.done) })
+}
+
+func closePayload(code uint16, reason string) []byte {
+	payload := make([]byte, 2, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, code)
+	return append(payload, reason...)
+}
+
+func (c *Conn) src() io.Reader {
+	if c.reader != nil {
+		return c.reader
+	}
+	return c.conn
+}
+
 func (c *Conn) readFrame() ([]byte, error) {
+	// Every frame, control frames included, restarts the idle timer.
+	if c.readTimeout > 0 {
+		if err := c.conn.SetReadDeadline(time.Now().Add(c.readTimeout)); err != nil {
+			return nil, err
+		}
+	}
 	header := make([]byte, 2)
-	if _, err := io.ReadFull(c.conn, header); err != nil {
+	if _, err := io.ReadFull(c.src(), header); err != nil {
 		return nil, err
 	}
 	fin := header[0]&0x80 != 0
+	compressed := header[0]&rsv1 != 0
 	opcode := header[0] & 0x0F
 	masked := header[1]&0x80 != 0
-	length := int(header[1] & 0x7F)
+	length := uint64(header[1] & 0x7F)
+
+	// RSV1 marks a compressed message (RFC 7692); it is only valid on data
+	// frames of a connection that negotiated permessage-deflate.
+	if header[0]&0x30 != 0 || (compressed && (!c.deflate || opcode&0x8 != 0)) {
+		_ = c.writeFrame(0x8, closePayload(closeProtocolError, "unexpected reserved bits"))
+		return nil, errReservedBitsSet
+	}
+
+	// RFC 6455 §5.1: a server must close the connection on unmasked client frames.
+	if !masked {
+		_ = c.writeFrame(0x8, closePayload(closeProtocolError, "client frames must be masked"))
+		return nil, errUnmaskedFrame
+	}
 
 	if length == 126 {
 		ext := make([]byte, 2)
-		if _, err := io.ReadFull(c.conn, ext); err != nil {
+		if _, err := io.ReadFull(c.src(), ext); err != nil {
 			return nil, err
 		}
-		length = int(binary.BigEndian.Uint16(ext))
+		length = uint64(binary.BigEndian.Uint16(ext))
 	} else if length == 127 {
 		ext := make([]byte, 8)
-		if _, err := io.ReadFull(c.conn, ext); err != nil {
+		if _, err := io.ReadFull(c.src(), ext); err != nil {
 			return nil, err
 		}
-		length = int(binary.BigEndian.Uint64(ext))
+		length = binary.BigEndian.Uint64(ext)
+	}
+	if length > uint64(maxFrameSize.Load()) {
+		_ = c.writeFrame(0x8, closePayload(closeMessageTooBig, "frame too large"))
+		return nil, errFrameTooLarge
 	}
 
 	var maskKey [4]byte
-	if masked {
-		if _, err := io.ReadFull(c.conn, maskKey[:]); err != nil {
-			return nil, err
-		}
+	if _, err := io.ReadFull(c.src(), maskKey[:]); err != nil {
+		return nil, err
 	}
 
 	payload := make([]byte, length)
-	if _, err := io.ReadFull(c.conn, payload); err != nil {
+	if _, err := io.ReadFull(c.src(), payload); err != nil {
 		return nil, err
 	}
 
-	if masked {
-		for i := 0; i < length; i++ {
-			payload[i] ^= maskKey[i%4]
-		}
+	for i := range payload {
+		payload[i] ^= maskKey[i%4]
 	}
 
 	if opcode == 0x8 { // close
@@ -125,16 +317,26 @@ func (c *Conn) readFrame() ([]byte, error) {
 		_ = c.writeFrame(0xA, payload)
 		return c.readFrame()
 	}
+	if opcode == 0xA { // unsolicited pong
+		return c.readFrame()
+	}
 	if !fin {
 		return nil, errors.New("fragmented frames are not supported")
 	}
 	if opcode != 0x1 { // not text
 		return nil, errors.New("unsupported websocket opcode")
 	}
+	if compressed {
+		return inflatePayload(payload, maxFrameSize.Load())
+	}
 	return payload, nil
 }
 
+// writeFrame sends a final frame. opcode may carry rsv1 to mark a payload
+// compressed with deflatePayload.
 func (c *Conn) writeFrame(opcode byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
 	header := []byte{0x80 | opcode}
 	length := len(payload)
 	if length < 126 {
@@ -161,3 +363,83 @@ func (c *Conn) writeFrame(opcode byte, payload []byte) error {
 	}
 	return nil
 }
+
+// rsv1 is the first-byte bit that marks a compressed message.
+const rsv1 = 0x40
+
+// deflateMinSize is the smallest message worth compressing; shorter JSON
+// (acks, presence, typing) often grows under deflate.
+const deflateMinSize = 256
+
+// permessageDeflateResponse is the only parameter set the server agrees to:
+// both sides reset the compression context after every message, so each
+// message inflates on its own and a Conn keeps no compressor state.
+const permessageDeflateResponse = "permessage-deflate; server_no_context_takeover; client_no_context_takeover"
+
+// deflateTail is the empty sync-flush block RFC 7692 strips from the end of
+// every compressed message.
+var deflateTail = []byte{0x00, 0x00, 0xff, 0xff}
+
+// negotiateDeflate reports whether the client offered permessage-deflate
+// with parameters the server can honour.
+func negotiateDeflate(h http.Header) bool {
+	for _, line := range h.Values("Sec-WebSocket-Extensions") {
+		for _, offer := range strings.Split(line, ",") {
+			params := strings.Split(offer, ";")
+			if !strings.EqualFold(strings.TrimSpace(params[0]), "permessage-deflate") {
+				continue
+			}
+			if deflateParamsAcceptable(params[1:]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func deflateParamsAcceptable(params []string) bool {
+	for _, p := range params {
+		name, _, _ := strings.Cut(strings.TrimSpace(p), "=")
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "", "server_no_context_takeover", "client_no_context_takeover", "client_max_window_bits":
+			// inflating with the full 32 KiB window reads any client window size
+		default:
+			// server_max_window_bits cannot be honoured: compress/flate
+			// always uses a 32 KiB window
+			return false
+		}
+	}
+	return true
+}
+
+func deflatePayload(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	fw, err := flate.NewWriter(&buf, flate.BestSpeed)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := fw.Flush(); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), deflateTail), nil
+}
+
+// inflatePayload restores a compressed message, refusing output larger than
+// limit so a small frame cannot expand into an unbounded allocation.
+func inflatePayload(data []byte, limit int64) ([]byte, error) {
+	// tail + a final empty stored block let the reader end with io.EOF
+	src := io.MultiReader(bytes.NewReader(data), bytes.NewReader(deflateTail), bytes.NewReader([]byte{0x01, 0x00, 0x00, 0xff, 0xff}))
+	fr := flate.NewReader(src)
+	defer fr.Close()
+	out, err := io.ReadAll(io.LimitReader(fr, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(out)) > limit {
+		return nil, errFrameTooLarge
+	}
+	return out, nil
+}
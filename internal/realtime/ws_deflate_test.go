@@ -0,0 +1,180 @@
+package realtime
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// largeChatPayload is big enough to cross deflateMinSize.
+var largeChatPayload = map[string]string{"type": "message", "text": strings.Repeat("Договор по сделке согласован. ", 40)}
+
+// upgradeWithClient upgrades a real connection, offering extensions (if any),
+// and returns the server-side Conn, the raw client socket and the response.
+func upgradeWithClient(t *testing.T, extensions string) (*Conn, net.Conn, *bufio.Reader, *http.Response) {
+	t.Helper()
+	conns := make(chan *Conn, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r)
+		if err != nil {
+			t.Errorf("upgrade: %v", err)
+			return
+		}
+		conns <- conn
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := net.Dial("tcp", strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	handshake := "GET / HTTP/1.1\r\nHost: example.com\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n"
+	if extensions != "" {
+		handshake += "Sec-WebSocket-Extensions: " + extensions + "\r\n"
+	}
+	if _, err := client.Write([]byte(handshake + "\r\n")); err != nil {
+		t.Fatalf("write handshake: %v", err)
+	}
+	br := bufio.NewReader(client)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatalf("read handshake response: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("unexpected handshake status %d", resp.StatusCode)
+	}
+	conn := <-conns
+	t.Cleanup(func() { _ = conn.conn.Close() })
+	return conn, client, br, resp
+}
+
+// readServerFrame reads one unmasked server frame: first header byte and payload.
+func readServerFrame(t *testing.T, r io.Reader) (byte, []byte) {
+	t.Helper()
+	var head [2]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		t.Fatalf("read frame header: %v", err)
+	}
+	n := int(head[1] & 0x7f)
+	if n == 126 {
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			t.Fatal(err)
+		}
+		n = int(binary.BigEndian.Uint16(ext[:]))
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		t.Fatalf("read frame payload: %v", err)
+	}
+	return head[0], payload
+}
+
+// clientDeflate compresses like a browser with client_no_context_takeover.
+func clientDeflate(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	fw, _ := flate.NewWriter(&buf, flate.DefaultCompression)
+	_, _ = fw.Write(data)
+	_ = fw.Flush()
+	return bytes.TrimSuffix(buf.Bytes(), []byte{0, 0, 0xff, 0xff})
+}
+
+func TestUpgrade_NegotiatesDeflateAndRoundTrips(t *testing.T) {
+	conn, client, br, resp := upgradeWithClient(t, "permessage-deflate; client_max_window_bits")
+	if got := resp.Header.Get("Sec-WebSocket-Extensions"); got != permessageDeflateResponse {
+		t.Fatalf("expected negotiated extension, got %q", got)
+	}
+	want, _ := json.Marshal(largeChatPayload)
+
+	// сервер → клиент: сжатый кадр с RSV1
+	go func() { _ = conn.WriteJSON(largeChatPayload) }()
+	first, payload := readServerFrame(t, br)
+	if first != 0x80|rsv1|0x1 {
+		t.Fatalf("expected compressed text frame, first byte %#x", first)
+	}
+	if len(payload) >= len(want) {
+		t.Fatalf("compressed payload (%d bytes) is not smaller than %d", len(payload), len(want))
+	}
+	inflated, err := io.ReadAll(flate.NewReader(io.MultiReader(bytes.NewReader(payload), bytes.NewReader([]byte{0, 0, 0xff, 0xff, 1, 0, 0, 0xff, 0xff}))))
+	if err != nil || !bytes.Equal(inflated, want) {
+		t.Fatalf("client could not inflate server frame: err=%v", err)
+	}
+
+	// клиент → сервер: сжатый кадр и затем обычный — оба читаются
+	compressedFrame := clientFrame(clientDeflate(t, want), true)
+	compressedFrame[0] |= rsv1
+	go func() {
+		_, _ = client.Write(compressedFrame)
+		_, _ = client.Write(clientFrame([]byte(`{"text":"plain"}`), true))
+	}()
+	var got map[string]string
+	if err := conn.ReadJSON(&got); err != nil || got["text"] != largeChatPayload["text"] {
+		t.Fatalf("server could not read compressed frame: err=%v", err)
+	}
+	if err := conn.ReadJSON(&got); err != nil || got["text"] != "plain" {
+		t.Fatalf("uncompressed frame on a deflate connection must still work: err=%v got=%v", err, got)
+	}
+}
+
+func TestUpgrade_WithoutDeflateOfferStaysUncompressed(t *testing.T) {
+	conn, client, br, resp := upgradeWithClient(t, "")
+	if got := resp.Header.Get("Sec-WebSocket-Extensions"); got != "" {
+		t.Fatalf("extension must not be negotiated without an offer, got %q", got)
+	}
+	want, _ := json.Marshal(largeChatPayload)
+
+	go func() { _ = conn.WriteJSON(largeChatPayload) }()
+	first, payload := readServerFrame(t, br)
+	if first != 0x81 || !bytes.Equal(payload, want) {
+		t.Fatalf("expected plain text frame, first byte %#x", first)
+	}
+
+	compressedFrame := clientFrame(clientDeflate(t, want), true)
+	compressedFrame[0] |= rsv1
+	go func() {
+		_, _ = client.Write(compressedFrame)
+		_, _ = io.Copy(io.Discard, br)
+	}()
+	var got map[string]string
+	if err := conn.ReadJSON(&got); !errors.Is(err, errReservedBitsSet) {
+		t.Fatalf("compressed frame without negotiation must be rejected, got %v", err)
+	}
+}
+
+func TestNegotiateDeflate_RejectsUnsupportedParameters(t *testing.T) {
+	for offer, want := range map[string]bool{
+		"permessage-deflate": true,
+		"x-webkit-deflate-frame, permessage-deflate; client_max_window_bits=10": true,
+		"permessage-deflate; server_max_window_bits=10":                         false,
+		"permessage-deflate; server_max_window_bits=10, permessage-deflate":     true,
+		"x-webkit-deflate-frame": false,
+	} {
+		h := http.Header{}
+		h.Set("Sec-WebSocket-Extensions", offer)
+		if got := negotiateDeflate(h); got != want {
+			t.Errorf("%q: negotiated=%v, want %v", offer, got, want)
+		}
+	}
+}
+
+func TestInflatePayload_EnforcesLimit(t *testing.T) {
+	bomb, err := deflatePayload(bytes.Repeat([]byte("a"), 10000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := inflatePayload(bomb, 1000); !errors.Is(err, errFrameTooLarge) {
+		t.Fatalf("expected errFrameTooLarge, got %v", err)
+	}
+}
@@ -0,0 +1,233 @@
+package realtime
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clientFrame builds a single text frame the way a browser would send it.
+func clientFrame(payload []byte, masked bool) []byte {
+	frame := []byte{0x81}
+	maskBit := byte(0)
+	if masked {
+		maskBit = 0x80
+	}
+	switch {
+	case len(payload) < 126:
+		frame = append(frame, maskBit|byte(len(payload)))
+	default:
+		frame = append(frame, maskBit|126, 0, 0)
+		binary.BigEndian.PutUint16(frame[2:], uint16(len(payload)))
+	}
+	if !masked {
+		return append(frame, payload...)
+	}
+	key := [4]byte{1, 2, 3, 4}
+	frame = append(frame, key[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^key[i%4])
+	}
+	return frame
+}
+
+func readWithClient(t *testing.T, raw []byte) ([]byte, error) {
+	t.Helper()
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+	// Drain any close frame the server writes back while the frame is sent.
+	go func() { _, _ = io.Copy(io.Discard, client) }()
+	go func() { _, _ = client.Write(raw) }()
+	return (&Conn{conn: server}).readFrame()
+}
+
+func TestReadFrame_AcceptsMaskedFrame(t *testing.T) {
+	got, err := readWithClient(t, clientFrame([]byte(`{"text":"hi"}`), true))
+	if err != nil {
+		t.Fatalf("readFrame: %v", err)
+	}
+	if string(got) != `{"text":"hi"}` {
+		t.Fatalf("unexpected payload %q", got)
+	}
+}
+
+func TestReadFrame_RejectsUnmaskedFrame(t *testing.T) {
+	_, err := readWithClient(t, clientFrame([]byte("hi"), false))
+	if !errors.Is(err, errUnmaskedFrame) {
+		t.Fatalf("expected errUnmaskedFrame, got %v", err)
+	}
+}
+
+func TestReadFrame_RejectsOversizedLengthBeforeAllocating(t *testing.T) {
+	// Claims a 2^62-byte payload but sends nothing after the header.
+	raw := []byte{0x81, 0x80 | 127, 0, 0, 0, 0, 0, 0, 0, 0}
+	binary.BigEndian.PutUint64(raw[2:], 1<<62)
+	_, err := readWithClient(t, raw)
+	if !errors.Is(err, errFrameTooLarge) {
+		t.Fatalf("expected errFrameTooLarge, got %v", err)
+	}
+}
+
+func TestReadFrame_RespectsConfiguredLimit(t *testing.T) {
+	SetMaxFrameSize(200)
+	t.Cleanup(func() { SetMaxFrameSize(0) })
+
+	if _, err := readWithClient(t, clientFrame(make([]byte, 200), true)); err != nil {
+		t.Fatalf("frame at the limit must be accepted: %v", err)
+	}
+	if _, err := readWithClient(t, clientFrame(make([]byte, 201), true)); !errors.Is(err, errFrameTooLarge) {
+		t.Fatalf("expected errFrameTooLarge above the limit, got %v", err)
+	}
+}
+
+func TestUpgrade_ReadDeadlineClosesSilentConnection(t *testing.T) {
+	SetConnOptions(ConnOptions{ReadTimeout: 50 * time.Millisecond})
+	t.Cleanup(func() { SetConnOptions(DefaultConnOptions) })
+
+	readErr := make(chan error, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r)
+		if err != nil {
+			readErr <- err
+			return
+		}
+		defer conn.Close()
+		var v map[string]interface{}
+		readErr <- conn.ReadJSON(&v)
+	}))
+	defer srv.Close()
+
+	client, err := net.Dial("tcp", strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer client.Close()
+	handshake := "GET / HTTP/1.1\r\nHost: example.com\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n"
+	if _, err := client.Write([]byte(handshake)); err != nil {
+		t.Fatalf("write handshake: %v", err)
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(client), nil)
+	if err != nil {
+		t.Fatalf("read handshake response: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("unexpected handshake status %d", resp.StatusCode)
+	}
+
+	select {
+	case err := <-readErr:
+		var netErr net.Error
+		if !errors.As(err, &netErr) || !netErr.Timeout() {
+			t.Fatalf("expected read timeout, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("silent connection was not timed out")
+	}
+}
+
+// dialUpgraded completes a WebSocket handshake against srv and returns the
+// raw client side of the connection.
+func dialUpgraded(t *testing.T, srv *httptest.Server) (net.Conn, *bufio.Reader) {
+	t.Helper()
+	client, err := net.Dial("tcp", strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	handshake := "GET / HTTP/1.1\r\nHost: example.com\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n"
+	if _, err := client.Write([]byte(handshake)); err != nil {
+		t.Fatalf("write handshake: %v", err)
+	}
+	br := bufio.NewReader(client)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatalf("read handshake response: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("unexpected handshake status %d", resp.StatusCode)
+	}
+	return client, br
+}
+
+func TestUpgrade_ClientPingsKeepConnectionPastReadTimeout(t *testing.T) {
+	SetConnOptions(ConnOptions{ReadTimeout: 100 * time.Millisecond})
+	t.Cleanup(func() { SetConnOptions(DefaultConnOptions) })
+
+	readErr := make(chan error, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r)
+		if err != nil {
+			readErr <- err
+			return
+		}
+		defer conn.Close()
+		var v map[string]interface{}
+		readErr <- conn.ReadJSON(&v)
+	}))
+	defer srv.Close()
+
+	client, br := dialUpgraded(t, srv)
+	go func() { _, _ = io.Copy(io.Discard, br) }()
+
+	ping := []byte{0x89, 0x80, 1, 2, 3, 4}
+	for i := 0; i < 8; i++ {
+		time.Sleep(40 * time.Millisecond)
+		if _, err := client.Write(ping); err != nil {
+			t.Fatalf("write ping: %v", err)
+		}
+	}
+	select {
+	case err := <-readErr:
+		t.Fatalf("connection closed while the client kept pinging: %v", err)
+	default:
+	}
+	if _, err := client.Write(clientFrame([]byte(`{"text":"hi"}`), true)); err != nil {
+		t.Fatalf("write message: %v", err)
+	}
+	select {
+	case err := <-readErr:
+		if err != nil {
+			t.Fatalf("ReadJSON after pings: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("message was not read")
+	}
+}
+
+func TestUpgrade_ServerPingsIdleClient(t *testing.T) {
+	SetConnOptions(ConnOptions{ReadTimeout: 100 * time.Millisecond})
+	t.Cleanup(func() { SetConnOptions(DefaultConnOptions) })
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var v map[string]interface{}
+		_ = conn.ReadJSON(&v)
+	}))
+	defer srv.Close()
+
+	client, br := dialUpgraded(t, srv)
+	if err := client.SetReadDeadline(time.Now().Add(2 * time.Second)); err != nil {
+		t.Fatalf("set deadline: %v", err)
+	}
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(br, header); err != nil {
+		t.Fatalf("expected a keepalive ping: %v", err)
+	}
+	if header[0] != 0x89 {
+		t.Fatalf("expected ping frame, got first byte %#x", header[0])
+	}
+}
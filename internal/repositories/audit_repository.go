@@ -77,6 +77,9 @@ type AuditListFilter struct {
 	IncludeHidden bool
 	// когда задан — возвращает только записи данного актора
 	ActorUserID *int
+	// границы по created_at: From включительно, To не включительно
+	From *time.Time
+	To   *time.Time
 }
 
 func (r *AuditRepository) List(ctx context.Context, limit, offset int, f AuditListFilter) ([]*AuditLogEntry, error) {
@@ -85,10 +88,12 @@ func (r *AuditRepository) List(ctx context.Context, limit, offset int, f AuditLi
 		FROM audit_logs
 		WHERE ($1 OR is_hidden = FALSE)
 		  AND ($2::int IS NULL OR actor_user_id = $2)
+		  AND ($5::timestamptz IS NULL OR created_at >= $5)
+		  AND ($6::timestamptz IS NULL OR created_at < $6)
 		ORDER BY created_at DESC
 		LIMIT $3 OFFSET $4
 	`
-	rows, err := r.db.QueryContext(ctx, q, f.IncludeHidden, f.ActorUserID, limit, offset)
+	rows, err := r.db.QueryContext(ctx, q, f.IncludeHidden, f.ActorUserID, limit, offset, f.From, f.To)
 	if err != nil {
 		if IsSQLState(err, SQLStateUndefinedTable) {
 			return nil, errors.Join(ErrAuditSchemaMissing, err)
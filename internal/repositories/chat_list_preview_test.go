@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+)
+
+func TestChatRepository_ListUserChats_RecentFirstWithPreview(t *testing.T) {
+	created := time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)
+	recent := time.Date(2026, 4, 3, 18, 30, 0, 0, time.UTC)
+	older := time.Date(2026, 4, 2, 11, 0, 0, 0, time.UTC)
+	db := openScriptedDB(t, scriptedStep{
+		kind:  "query",
+		query: "ORDER BY COALESCE(lm.created_at, c.created_at) DESC, c.id DESC",
+		args:  []any{7},
+		columns: []string{"id", "branch_id", "name", "is_group", "created_at", "members",
+			"last_message_text", "last_message_sender_id", "last_message_at", "online", "last_seen", "unread_count"},
+		rows: [][]driver.Value{
+			{int64(2), nil, "Продажи", true, created, "{7,9}", "Договор подписан", int64(9), recent, nil, nil, int64(1)},
+			{int64(5), nil, "", false, created, "{7,8}", "Привет", int64(7), older, true, nil, int64(0)},
+			{int64(1), nil, "Пустой", true, created, "{7}", nil, nil, nil, nil, nil, int64(0)},
+		},
+	})
+
+	chats, err := NewChatRepository(db).ListUserChats(7)
+	if err != nil {
+		t.Fatalf("ListUserChats: %v", err)
+	}
+	if len(chats) != 3 || chats[0].ID != 2 || chats[1].ID != 5 || chats[2].ID != 1 {
+		t.Fatalf("expected chats in recency order 2, 5, 1, got %+v", chats)
+	}
+	first := chats[0]
+	if first.LastMessageText != "Договор подписан" || first.LastMessageSenderID != 9 || !first.LastMessageAt.Equal(recent) {
+		t.Fatalf("preview must match the latest message, got %q by %d at %s", first.LastMessageText, first.LastMessageSenderID, first.LastMessageAt)
+	}
+	if empty := chats[2]; empty.LastMessageText != "" || empty.LastMessageSenderID != 0 || !empty.LastMessageAt.IsZero() {
+		t.Fatalf("chat without messages must have an empty preview, got %+v", empty)
+	}
+}
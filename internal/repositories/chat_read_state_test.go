@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+)
+
+func TestChatRepository_MarkChatRead_NeverMovesMarkerBack(t *testing.T) {
+	readAt := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	db := openScriptedDB(t,
+		scriptedStep{
+			kind:    "query",
+			query:   "SELECT 1 FROM messages WHERE id = $1 AND chat_id = $2",
+			args:    []any{5, 3},
+			columns: []string{"?column?"},
+			rows:    [][]driver.Value{{int64(1)}},
+		},
+		scriptedStep{
+			kind:    "query",
+			query:   "SET last_read_message_id = GREATEST(COALESCE(chat_read_state.last_read_message_id, 0), EXCLUDED.last_read_message_id)",
+			args:    []any{3, 7, 5},
+			columns: []string{"last_read_message_id", "read_at"},
+			// маркер уже стоял на 12 — остаётся на 12
+			rows: [][]driver.Value{{int64(12), readAt}},
+		},
+	)
+	msgID := 5
+	lastRead, at, err := NewChatRepository(db).MarkChatRead(3, 7, &msgID)
+	if err != nil {
+		t.Fatalf("MarkChatRead: %v", err)
+	}
+	if lastRead != 12 || !at.Equal(readAt) {
+		t.Fatalf("expected the stored marker (12, %s), got (%d, %s)", readAt, lastRead, at)
+	}
+}
@@ -65,6 +65,7 @@ SELECT c.id,
        c.created_at,
        COALESCE(array_agg(cm.user_id ORDER BY cm.user_id), '{}') AS members,
        lm.text AS last_message_text,
+       lm.sender_id AS last_message_sender_id,
        lm.created_at AS last_message_at,
        us.online,
        us.last_seen,
@@ -72,7 +73,7 @@ SELECT c.id,
 FROM chats c
 JOIN chat_members cm ON cm.chat_id = c.id
 LEFT JOIN LATERAL (
-    SELECT text, created_at
+    SELECT text, sender_id, created_at
     FROM messages m
     WHERE m.chat_id = c.id
     ORDER BY created_at DESC, id DESC
@@ -93,8 +94,8 @@ LEFT JOIN LATERAL (
     WHERE m.chat_id = c.id AND m.id > COALESCE(crs.last_read_message_id, 0)
 ) unread ON true
 WHERE c.id IN (SELECT chat_id FROM chat_members WHERE user_id = $1)
-GROUP BY c.id, c.name, c.is_group, c.created_at, lm.text, lm.created_at, us.online, us.last_seen, unread.unread_count
-ORDER BY c.id
+GROUP BY c.id, c.name, c.is_group, c.created_at, lm.text, lm.sender_id, lm.created_at, us.online, us.last_seen, unread.unread_count
+ORDER BY COALESCE(lm.created_at, c.created_at) DESC, c.id DESC
 `
 	rows, err := r.DB.Query(q, userID)
 	if err != nil {
@@ -108,6 +109,7 @@ ORDER BY c.id
 		var (
 			members     pq.Int64Array
 			lastText    sql.NullString
+			lastSender  sql.NullInt64
 			lastAt      sql.NullTime
 			online      sql.NullBool
 			lastSeen    sql.NullTime
@@ -122,6 +124,7 @@ ORDER BY c.id
 			&chat.CreatedAt,
 			&members,
 			&lastText,
+			&lastSender,
 			&lastAt,
 			&online,
 			&lastSeen,
@@ -136,6 +139,9 @@ ORDER BY c.id
 		if lastText.Valid {
 			chat.LastMessageText = lastText.String
 		}
+		if lastSender.Valid {
+			chat.LastMessageSenderID = int(lastSender.Int64)
+		}
 		if lastAt.Valid {
 			chat.LastMessageAt = lastAt.Time
 		}
@@ -806,16 +812,30 @@ LIMIT 1
 	return msgs[0], nil
 }
 
+// UpdateLastRead moves the member's read marker forward to messageID. The
+// marker never moves back, so loading an older history page keeps newer
+// messages read.
 func (r *chatRepository) UpdateLastRead(chatID, userID, messageID int) error {
+	_, _, err := r.advanceLastRead(chatID, userID, messageID)
+	return err
+}
+
+// advanceLastRead is UpdateLastRead returning the resulting marker.
+func (r *chatRepository) advanceLastRead(chatID, userID, messageID int) (int, time.Time, error) {
 	const q = `
 INSERT INTO chat_read_state (chat_id, user_id, last_read_message_id, read_at)
 VALUES ($1, $2, $3, NOW())
 ON CONFLICT (chat_id, user_id) DO UPDATE
-SET last_read_message_id = EXCLUDED.last_read_message_id,
+SET last_read_message_id = GREATEST(COALESCE(chat_read_state.last_read_message_id, 0), EXCLUDED.last_read_message_id),
     read_at = NOW()
+RETURNING last_read_message_id, read_at
 `
-	_, err := r.DB.Exec(q, chatID, userID, messageID)
-	return err
+	var lastRead sql.NullInt64
+	var readAt time.Time
+	if err := r.DB.QueryRow(q, chatID, userID, messageID).Scan(&lastRead, &readAt); err != nil {
+		return 0, time.Time{}, err
+	}
+	return int(lastRead.Int64), readAt, nil
 }
 
 func (r *chatRepository) MarkChatRead(chatID, userID int, messageID *int) (int, time.Time, error) {
@@ -825,26 +845,17 @@ func (r *chatRepository) MarkChatRead(chatID, userID int, messageID *int) (int,
 		if err := r.DB.QueryRow(checkQ, *messageID, chatID).Scan(&ok); err != nil {
 			return 0, time.Time{}, err
 		}
-		if err := r.UpdateLastRead(chatID, userID, *messageID); err != nil {
-			return 0, time.Time{}, err
-		}
-		return *messageID, time.Now(), nil
+		return r.advanceLastRead(chatID, userID, *messageID)
 	}
 
 	lastMsg, err := r.LastMessage(chatID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			if err := r.UpdateLastRead(chatID, userID, 0); err != nil {
-				return 0, time.Time{}, err
-			}
-			return 0, time.Now(), nil
+			return r.advanceLastRead(chatID, userID, 0)
 		}
 		return 0, time.Time{}, err
 	}
-	if err := r.UpdateLastRead(chatID, userID, lastMsg.ID); err != nil {
-		return 0, time.Time{}, err
-	}
-	return lastMsg.ID, time.Now(), nil
+	return r.advanceLastRead(chatID, userID, lastMsg.ID)
 }
 
 func (r *chatRepository) CountUnread(chatID, userID int) (int, error) {
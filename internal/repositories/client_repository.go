@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -279,12 +280,25 @@ func scanClient(scanner clientRowScanner) (*models.Client, error) {
 	return c, nil
 }
 
-func (r *ClientRepository) Create(c *models.Client) (int64, error) {
-	tx, err := r.db.Begin()
+func (r *ClientRepository) Create(ctx context.Context, c *models.Client) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return 0, err
 	}
 	defer tx.Rollback()
+	id, err := createClientTx(ctx, tx, c)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// createClientTx inserts the client row and its type-specific profile inside
+// tx, so callers can make client creation part of a larger unit of work.
+func createClientTx(ctx context.Context, tx *sql.Tx, c *models.Client) (int64, error) {
 	if c.CreatedAt.IsZero() {
 		c.CreatedAt = time.Now()
 	}
@@ -295,23 +309,48 @@ func (r *ClientRepository) Create(c *models.Client) (int64, error) {
 	q := `INSERT INTO clients (owner_id, branch_id, client_type, display_name, primary_phone, primary_email, address, contact_info, created_at, updated_at, name, phone, email, bin_iin)
 VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14) RETURNING id`
 	var id int64
-	err = tx.QueryRow(q, c.OwnerID, c.BranchID, c.ClientType, c.Name, nullString(c.Phone), nullString(c.Email), c.Address, c.ContactInfo, c.CreatedAt, c.UpdatedAt, c.Name, nullString(c.Phone), nullString(c.Email), nullString(c.BinIin)).Scan(&id)
+	err := tx.QueryRowContext(ctx, q, c.OwnerID, c.BranchID, c.ClientType, c.Name, nullString(c.Phone), nullString(c.Email), c.Address, c.ContactInfo, c.CreatedAt, c.UpdatedAt, c.Name, nullString(c.Phone), nullString(c.Email), nullString(c.BinIin)).Scan(&id)
 	if err != nil {
 		return 0, fmt.Errorf("create client: %w", err)
 	}
 	c.ID = int(id)
-	if err := upsertProfilesTx(tx, c); err != nil {
-		return 0, err
-	}
-	if err := tx.Commit(); err != nil {
+	if err := upsertProfilesTx(ctx, tx, c); err != nil {
 		return 0, err
 	}
 	return id, nil
 }
 
-func upsertProfilesTx(tx *sql.Tx, c *models.Client) error {
+// lookupClientByIdentityTx finds and locks the client that already holds c's
+// BIN or IIN, so an insert that hit a unique violation can reuse it. It
+// returns id 0 when neither matches.
+func lookupClientByIdentityTx(ctx context.Context, tx *sql.Tx, c *models.Client) (int, string, error) {
+	bin, iin := strings.TrimSpace(c.BinIin), strings.TrimSpace(c.IIN)
+	if bin == "" && iin == "" {
+		return 0, "", nil
+	}
+	var id int
+	var clientType sql.NullString
+	err := tx.QueryRowContext(ctx, `
+		SELECT c.id, c.client_type
+		FROM clients c
+		LEFT JOIN client_legal_profiles lp ON lp.client_id = c.id
+		LEFT JOIN client_individual_profiles ip ON ip.client_id = c.id
+		WHERE ($1 <> '' AND COALESCE(lp.bin, c.bin_iin) = $1) OR ($2 <> '' AND ip.iin = $2)
+		ORDER BY c.id
+		LIMIT 1
+		FOR UPDATE OF c`, bin, iin).Scan(&id, &clientType)
+	if err == sql.ErrNoRows {
+		return 0, "", nil
+	}
+	if err != nil {
+		return 0, "", fmt.Errorf("lookup client by bin/iin: %w", err)
+	}
+	return id, clientType.String, nil
+}
+
+func upsertProfilesTx(ctx context.Context, tx *sql.Tx, c *models.Client) error {
 	if c.ClientType == models.ClientTypeIndividual {
-		_, err := tx.Exec(`INSERT INTO client_individual_profiles (client_id,last_name,first_name,middle_name,iin,id_number,passport_series,passport_number,passport_identity,registration_address,actual_address,country,trip_purpose,birth_date,birth_place,citizenship,sex,marital_status,passport_issue_date,passport_expire_date,driver_license_issue_date,driver_license_expire_date,previous_last_name,spouse_name,spouse_contacts,has_children,children_list,education,education_level,job,trips_last5_years,relatives_in_destination,trusted_person,specialty,trusted_person_phone,driver_license_number,education_institution_name,education_institution_address,position,visas_received,visa_refusals,height,weight,driver_license_categories,therapist_name,clinic_name,diseases_last3_years,additional_info,updated_at)
+		_, err := tx.ExecContext(ctx, `INSERT INTO client_individual_profiles (client_id,last_name,first_name,middle_name,iin,id_number,passport_series,passport_number,passport_identity,registration_address,actual_address,country,trip_purpose,birth_date,birth_place,citizenship,sex,marital_status,passport_issue_date,passport_expire_date,driver_license_issue_date,driver_license_expire_date,previous_last_name,spouse_name,spouse_contacts,has_children,children_list,education,education_level,job,trips_last5_years,relatives_in_destination,trusted_person,specialty,trusted_person_phone,driver_license_number,education_institution_name,education_institution_address,position,visas_received,visa_refusals,height,weight,driver_license_categories,therapist_name,clinic_name,diseases_last3_years,additional_info,updated_at)
 VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26,$27,$28,$29,$30,$31,$32,$33,$34,$35,$36,$37,$38,$39,$40,$41,$42,$43,$44,$45,$46,$47,$48,NOW())
 ON CONFLICT (client_id) DO UPDATE SET
 last_name=EXCLUDED.last_name,first_name=EXCLUDED.first_name,middle_name=EXCLUDED.middle_name,iin=EXCLUDED.iin,id_number=EXCLUDED.id_number,passport_series=EXCLUDED.passport_series,passport_number=EXCLUDED.passport_number,passport_identity=EXCLUDED.passport_identity,registration_address=EXCLUDED.registration_address,actual_address=EXCLUDED.actual_address,country=EXCLUDED.country,trip_purpose=EXCLUDED.trip_purpose,birth_date=EXCLUDED.birth_date,birth_place=EXCLUDED.birth_place,citizenship=EXCLUDED.citizenship,sex=EXCLUDED.sex,marital_status=EXCLUDED.marital_status,passport_issue_date=EXCLUDED.passport_issue_date,passport_expire_date=EXCLUDED.passport_expire_date,driver_license_issue_date=EXCLUDED.driver_license_issue_date,driver_license_expire_date=EXCLUDED.driver_license_expire_date,previous_last_name=EXCLUDED.previous_last_name,spouse_name=EXCLUDED.spouse_name,spouse_contacts=EXCLUDED.spouse_contacts,has_children=EXCLUDED.has_children,children_list=EXCLUDED.children_list,education=EXCLUDED.education,education_level=EXCLUDED.education_level,job=EXCLUDED.job,trips_last5_years=EXCLUDED.trips_last5_years,relatives_in_destination=EXCLUDED.relatives_in_destination,trusted_person=EXCLUDED.trusted_person,specialty=EXCLUDED.specialty,trusted_person_phone=EXCLUDED.trusted_person_phone,driver_license_number=EXCLUDED.driver_license_number,education_institution_name=EXCLUDED.education_institution_name,education_institution_address=EXCLUDED.education_institution_address,position=EXCLUDED.position,visas_received=EXCLUDED.visas_received,visa_refusals=EXCLUDED.visa_refusals,height=EXCLUDED.height,weight=EXCLUDED.weight,driver_license_categories=EXCLUDED.driver_license_categories,therapist_name=EXCLUDED.therapist_name,clinic_name=EXCLUDED.clinic_name,diseases_last3_years=EXCLUDED.diseases_last3_years,additional_info=EXCLUDED.additional_info,updated_at=NOW()`,
@@ -321,11 +360,11 @@ last_name=EXCLUDED.last_name,first_name=EXCLUDED.first_name,middle_name=EXCLUDED
 		if err != nil {
 			return fmt.Errorf("upsert individual profile: %w", err)
 		}
-		_, _ = tx.Exec(`DELETE FROM client_legal_profiles WHERE client_id=$1`, c.ID)
+		_, _ = tx.ExecContext(ctx, `DELETE FROM client_legal_profiles WHERE client_id=$1`, c.ID)
 		return nil
 	}
 	lp := buildLegalProfileForUpsert(c)
-	_, err := tx.Exec(`INSERT INTO client_legal_profiles (client_id,company_name,bin,legal_form,director_full_name,contact_person_name,contact_person_position,contact_person_phone,contact_person_email,legal_address,actual_address,bank_name,iban,bik,kbe,tax_regime,website,industry,company_size,additional_info,updated_at)
+	_, err := tx.ExecContext(ctx, `INSERT INTO client_legal_profiles (client_id,company_name,bin,legal_form,director_full_name,contact_person_name,contact_person_position,contact_person_phone,contact_person_email,legal_address,actual_address,bank_name,iban,bik,kbe,tax_regime,website,industry,company_size,additional_info,updated_at)
 VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,NOW())
 ON CONFLICT (client_id) DO UPDATE SET
 company_name=EXCLUDED.company_name,bin=EXCLUDED.bin,legal_form=EXCLUDED.legal_form,director_full_name=EXCLUDED.director_full_name,contact_person_name=EXCLUDED.contact_person_name,contact_person_position=EXCLUDED.contact_person_position,contact_person_phone=EXCLUDED.contact_person_phone,contact_person_email=EXCLUDED.contact_person_email,legal_address=EXCLUDED.legal_address,actual_address=EXCLUDED.actual_address,bank_name=EXCLUDED.bank_name,iban=EXCLUDED.iban,bik=EXCLUDED.bik,kbe=EXCLUDED.kbe,tax_regime=EXCLUDED.tax_regime,website=EXCLUDED.website,industry=EXCLUDED.industry,company_size=EXCLUDED.company_size,additional_info=EXCLUDED.additional_info,updated_at=NOW()`,
@@ -333,7 +372,7 @@ company_name=EXCLUDED.company_name,bin=EXCLUDED.bin,legal_form=EXCLUDED.legal_fo
 	if err != nil {
 		return fmt.Errorf("upsert legal profile: %w", err)
 	}
-	_, _ = tx.Exec(`DELETE FROM client_individual_profiles WHERE client_id=$1`, c.ID)
+	_, _ = tx.ExecContext(ctx, `DELETE FROM client_individual_profiles WHERE client_id=$1`, c.ID)
 	return nil
 }
 
@@ -363,28 +402,86 @@ func buildLegalProfileForUpsert(c *models.Client) models.ClientLegalProfile {
 	return lp
 }
 
-func (r *ClientRepository) Update(c *models.Client) error {
+func (r *ClientRepository) Update(ctx context.Context, c *models.Client) error {
 	q := `UPDATE clients SET owner_id=$1, branch_id=$2, client_type=$3, display_name=$4, primary_phone=$5, primary_email=$6, address=$7, contact_info=$8, updated_at=NOW(), name=$9, phone=$10, email=$11, bin_iin=$12 WHERE id=$13`
-	_, err := r.db.Exec(q, c.OwnerID, c.BranchID, c.ClientType, c.Name, nullString(c.Phone), nullString(c.Email), c.Address, c.ContactInfo, c.Name, nullString(c.Phone), nullString(c.Email), nullString(c.BinIin), c.ID)
+	_, err := r.db.ExecContext(ctx, q, c.OwnerID, c.BranchID, c.ClientType, c.Name, nullString(c.Phone), nullString(c.Email), c.Address, c.ContactInfo, c.Name, nullString(c.Phone), nullString(c.Email), nullString(c.BinIin), c.ID)
 	if err != nil {
 		return fmt.Errorf("update client: %w", err)
 	}
-	tx, err := r.db.Begin()
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
-	if err := upsertProfilesTx(tx, c); err != nil {
+	if err := upsertProfilesTx(ctx, tx, c); err != nil {
 		return err
 	}
 	return tx.Commit()
 }
 
-func (r *ClientRepository) Delete(id int) error {
-	_, err := r.db.Exec(`DELETE FROM clients WHERE id=$1`, id)
+func (r *ClientRepository) Delete(ctx context.Context, id int) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM clients WHERE id=$1`, id)
 	return err
 }
 
+// Merge folds duplicateID into primaryID in one transaction: deals move via
+// DealRepository.ReassignClient, documents, files, calls, chats and linked
+// tasks are repointed, then the duplicate is deleted. Files keep their primary flag only
+// when the primary client has no primary file in that category yet. Returns
+// ErrClientNotFound when either client is missing.
+func (r *ClientRepository) Merge(ctx context.Context, primaryID, duplicateID int) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	rows, err := tx.QueryContext(ctx, `SELECT id FROM clients WHERE id IN ($1, $2) ORDER BY id FOR UPDATE`, primaryID, duplicateID)
+	if err != nil {
+		return 0, fmt.Errorf("lock clients: %w", err)
+	}
+	locked := 0
+	for rows.Next() {
+		locked++
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return 0, fmt.Errorf("lock clients: %w", err)
+	}
+	rows.Close()
+	if locked != 2 {
+		return 0, ErrClientNotFound
+	}
+
+	moved, err := NewDealRepository(r.db).ReassignClient(ctx, tx, duplicateID, primaryID)
+	if err != nil {
+		return 0, err
+	}
+	repoint := []struct{ name, query string }{
+		{"documents", `UPDATE documents SET client_id = $2 WHERE client_id = $1`},
+		{"client files", `UPDATE client_files f SET client_id = $2,
+	is_primary = f.is_primary AND NOT EXISTS (
+		SELECT 1 FROM client_files p WHERE p.client_id = $2 AND p.category = f.category AND p.is_primary
+	)
+WHERE f.client_id = $1`},
+		{"calls", `UPDATE telephony_calls SET client_id = $2 WHERE client_id = $1`},
+		{"chats", `UPDATE chats SET client_ref_id = $2 WHERE client_ref_id = $1`},
+		{"tasks", `UPDATE tasks SET entity_id = $2 WHERE entity_type = 'client' AND entity_id = $1`},
+	}
+	for _, step := range repoint {
+		if _, err := tx.ExecContext(ctx, step.query, duplicateID, primaryID); err != nil {
+			return 0, fmt.Errorf("repoint %s: %w", step.name, err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM clients WHERE id = $1`, duplicateID); err != nil {
+		return 0, fmt.Errorf("delete duplicate client: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return moved, nil
+}
+
 func clientArchiveWhere(scope ArchiveScope) string {
 	switch scope {
 	case ArchiveScopeArchivedOnly:
@@ -396,8 +493,8 @@ func clientArchiveWhere(scope ArchiveScope) string {
 	}
 }
 
-func (r *ClientRepository) Archive(id, archivedBy int, reason string) error {
-	_, err := r.db.Exec(`
+func (r *ClientRepository) Archive(ctx context.Context, id, archivedBy int, reason string) error {
+	_, err := r.db.ExecContext(ctx, `
 		UPDATE clients
 		SET is_archived = TRUE,
 		    archived_at = NOW(),
@@ -408,8 +505,8 @@ func (r *ClientRepository) Archive(id, archivedBy int, reason string) error {
 	return err
 }
 
-func (r *ClientRepository) Unarchive(id int) error {
-	_, err := r.db.Exec(`
+func (r *ClientRepository) Unarchive(ctx context.Context, id int) error {
+	_, err := r.db.ExecContext(ctx, `
 		UPDATE clients
 		SET is_archived = FALSE,
 		    archived_at = NULL,
@@ -420,12 +517,12 @@ func (r *ClientRepository) Unarchive(id int) error {
 	return err
 }
 
-func (r *ClientRepository) GetByID(id int) (*models.Client, error) {
-	return r.GetByIDWithArchiveScope(id, ArchiveScopeActiveOnly)
+func (r *ClientRepository) GetByID(ctx context.Context, id int) (*models.Client, error) {
+	return r.GetByIDWithArchiveScope(ctx, id, ArchiveScopeActiveOnly)
 }
 
-func (r *ClientRepository) GetByIDWithArchiveScope(id int, scope ArchiveScope) (*models.Client, error) {
-	row := r.db.QueryRow(clientSelect+` WHERE c.id=$1 AND `+clientArchiveWhere(scope), id)
+func (r *ClientRepository) GetByIDWithArchiveScope(ctx context.Context, id int, scope ArchiveScope) (*models.Client, error) {
+	row := r.db.QueryRowContext(ctx, clientSelect+` WHERE c.id=$1 AND `+clientArchiveWhere(scope), id)
 	c, err := scanClient(row)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -436,11 +533,11 @@ func (r *ClientRepository) GetByIDWithArchiveScope(id int, scope ArchiveScope) (
 	return c, nil
 }
 
-func (r *ClientRepository) GetByIDWithBranchScope(id int, branchID *int, scope ArchiveScope) (*models.Client, error) {
+func (r *ClientRepository) GetByIDWithBranchScope(ctx context.Context, id int, branchID *int, scope ArchiveScope) (*models.Client, error) {
 	if branchID == nil {
-		return r.GetByIDWithArchiveScope(id, scope)
+		return r.GetByIDWithArchiveScope(ctx, id, scope)
 	}
-	row := r.db.QueryRow(clientSelect+` WHERE c.id=$1 AND c.branch_id=$2 AND `+clientArchiveWhere(scope), id, *branchID)
+	row := r.db.QueryRowContext(ctx, clientSelect+` WHERE c.id=$1 AND c.branch_id=$2 AND `+clientArchiveWhere(scope), id, *branchID)
 	c, err := scanClient(row)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -451,32 +548,32 @@ func (r *ClientRepository) GetByIDWithBranchScope(id int, branchID *int, scope A
 	return c, nil
 }
 
-func (r *ClientRepository) GetByBIN(bin string) (*models.Client, error) {
-	row := r.db.QueryRow(clientSelect+` WHERE COALESCE(lp.bin,c.bin_iin)=$1 LIMIT 1`, strings.TrimSpace(bin))
+func (r *ClientRepository) GetByBIN(ctx context.Context, bin string) (*models.Client, error) {
+	row := r.db.QueryRowContext(ctx, clientSelect+` WHERE COALESCE(lp.bin,c.bin_iin)=$1 LIMIT 1`, strings.TrimSpace(bin))
 	c, err := scanClient(row)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	return c, err
 }
-func (r *ClientRepository) GetByIIN(iin string) (*models.Client, error) {
-	row := r.db.QueryRow(clientSelect+` WHERE ip.iin=$1 LIMIT 1`, strings.TrimSpace(iin))
+func (r *ClientRepository) GetByIIN(ctx context.Context, iin string) (*models.Client, error) {
+	row := r.db.QueryRowContext(ctx, clientSelect+` WHERE ip.iin=$1 LIMIT 1`, strings.TrimSpace(iin))
 	c, err := scanClient(row)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	return c, err
 }
-func (r *ClientRepository) GetByPhone(phone string) (*models.Client, error) {
-	row := r.db.QueryRow(clientSelect+` WHERE COALESCE(c.primary_phone,c.phone)=$1 LIMIT 1`, strings.TrimSpace(phone))
+func (r *ClientRepository) GetByPhone(ctx context.Context, phone string) (*models.Client, error) {
+	row := r.db.QueryRowContext(ctx, clientSelect+` WHERE COALESCE(c.primary_phone,c.phone)=$1 LIMIT 1`, strings.TrimSpace(phone))
 	c, err := scanClient(row)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	return c, err
 }
-func (r *ClientRepository) GetByEmail(email string) (*models.Client, error) {
-	row := r.db.QueryRow(clientSelect+` WHERE LOWER(COALESCE(c.primary_email,c.email))=LOWER($1) LIMIT 1`, strings.TrimSpace(email))
+func (r *ClientRepository) GetByEmail(ctx context.Context, email string) (*models.Client, error) {
+	row := r.db.QueryRowContext(ctx, clientSelect+` WHERE LOWER(COALESCE(c.primary_email,c.email))=LOWER($1) LIMIT 1`, strings.TrimSpace(email))
 	c, err := scanClient(row)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -485,77 +582,115 @@ func (r *ClientRepository) GetByEmail(email string) (*models.Client, error) {
 }
 
 // UpdateAvatar updates client avatar URL and path
-func (r *ClientRepository) UpdateAvatar(clientID int, avatarURL, avatarPath string) error {
+func (r *ClientRepository) UpdateAvatar(ctx context.Context, clientID int, avatarURL, avatarPath string) error {
 	q := `UPDATE clients SET avatar_url=$1, avatar_path=$2, avatar_crop_x=NULL, avatar_crop_y=NULL, avatar_crop_scale=NULL, avatar_crop_size=NULL, updated_at=NOW() WHERE id=$3`
-	_, err := r.db.Exec(q, nullString(avatarURL), nullString(avatarPath), clientID)
+	_, err := r.db.ExecContext(ctx, q, nullString(avatarURL), nullString(avatarPath), clientID)
 	return err
 }
 
 // UpdateAvatarCrop updates client avatar crop coordinates
-func (r *ClientRepository) UpdateAvatarCrop(clientID int, cropX, cropY, cropScale, cropSize *float64) error {
+func (r *ClientRepository) UpdateAvatarCrop(ctx context.Context, clientID int, cropX, cropY, cropScale, cropSize *float64) error {
 	q := `UPDATE clients SET avatar_crop_x=$1, avatar_crop_y=$2, avatar_crop_scale=$3, avatar_crop_size=$4, updated_at=NOW() WHERE id=$5`
-	_, err := r.db.Exec(q, cropX, cropY, cropScale, cropSize, clientID)
+	_, err := r.db.ExecContext(ctx, q, cropX, cropY, cropScale, cropSize, clientID)
 	return err
 }
 
 // DeleteAvatar removes client avatar
-func (r *ClientRepository) DeleteAvatar(clientID int) error {
+func (r *ClientRepository) DeleteAvatar(ctx context.Context, clientID int) error {
 	q := `UPDATE clients SET avatar_url=NULL, avatar_path=NULL, avatar_crop_x=NULL, avatar_crop_y=NULL, avatar_crop_scale=NULL, avatar_crop_size=NULL, updated_at=NOW() WHERE id=$1`
-	_, err := r.db.Exec(q, clientID)
+	_, err := r.db.ExecContext(ctx, q, clientID)
 	return err
 }
 
-func (r *ClientRepository) ListAll(limit, offset int, clientType string) ([]*models.Client, error) {
-	return r.ListAllWithFilterAndArchiveScope(limit, offset, ClientListFilter{ClientType: clientType}, ArchiveScopeActiveOnly)
+func (r *ClientRepository) ListAll(ctx context.Context, limit, offset int, clientType string) ([]*models.Client, error) {
+	return r.ListAllWithFilterAndArchiveScope(ctx, limit, offset, ClientListFilter{ClientType: clientType}, ArchiveScopeActiveOnly)
 }
 
-func (r *ClientRepository) ListAllWithArchiveScope(limit, offset int, clientType string, scope ArchiveScope) ([]*models.Client, error) {
-	return r.ListAllWithFilterAndArchiveScope(limit, offset, ClientListFilter{ClientType: clientType}, scope)
+func (r *ClientRepository) ListAllWithArchiveScope(ctx context.Context, limit, offset int, clientType string, scope ArchiveScope) ([]*models.Client, error) {
+	return r.ListAllWithFilterAndArchiveScope(ctx, limit, offset, ClientListFilter{ClientType: clientType}, scope)
 }
 
-func (r *ClientRepository) ListAllWithFilterAndArchiveScope(limit, offset int, filter ClientListFilter, scope ArchiveScope) ([]*models.Client, error) {
-	return r.listWithFilterAndArchiveScope(nil, "", limit, offset, filter, scope)
+func (r *ClientRepository) ListAllWithFilterAndArchiveScope(ctx context.Context, limit, offset int, filter ClientListFilter, scope ArchiveScope) ([]*models.Client, error) {
+	return r.listWithFilterAndArchiveScope(ctx, nil, "", limit, offset, filter, scope)
 }
-func (r *ClientRepository) List(limit, offset int) ([]*models.Client, error) {
-	return r.ListAll(limit, offset, "")
+func (r *ClientRepository) List(ctx context.Context, limit, offset int) ([]*models.Client, error) {
+	return r.ListAll(ctx, limit, offset, "")
 }
-func (r *ClientRepository) ListByOwner(ownerID, limit, offset int, clientType string) ([]*models.Client, error) {
-	return r.ListByOwnerWithFilterAndArchiveScope(ownerID, limit, offset, ClientListFilter{ClientType: clientType}, ArchiveScopeActiveOnly)
+func (r *ClientRepository) ListByOwner(ctx context.Context, ownerID, limit, offset int, clientType string) ([]*models.Client, error) {
+	return r.ListByOwnerWithFilterAndArchiveScope(ctx, ownerID, limit, offset, ClientListFilter{ClientType: clientType}, ArchiveScopeActiveOnly)
 }
 
-func (r *ClientRepository) ListByOwnerWithArchiveScope(ownerID, limit, offset int, clientType string, scope ArchiveScope) ([]*models.Client, error) {
-	return r.ListByOwnerWithFilterAndArchiveScope(ownerID, limit, offset, ClientListFilter{ClientType: clientType}, scope)
+func (r *ClientRepository) ListByOwnerWithArchiveScope(ctx context.Context, ownerID, limit, offset int, clientType string, scope ArchiveScope) ([]*models.Client, error) {
+	return r.ListByOwnerWithFilterAndArchiveScope(ctx, ownerID, limit, offset, ClientListFilter{ClientType: clientType}, scope)
 }
 
-func (r *ClientRepository) ListByOwnerWithFilterAndArchiveScope(ownerID, limit, offset int, filter ClientListFilter, scope ArchiveScope) ([]*models.Client, error) {
-	return r.listWithFilterAndArchiveScope(&ownerID, "", limit, offset, filter, scope)
+func (r *ClientRepository) ListByOwnerWithFilterAndArchiveScope(ctx context.Context, ownerID, limit, offset int, filter ClientListFilter, scope ArchiveScope) ([]*models.Client, error) {
+	return r.listWithFilterAndArchiveScope(ctx, &ownerID, "", limit, offset, filter, scope)
 }
-func (r *ClientRepository) ListIndividuals(ownerID int, search string, limit, offset int) ([]*models.Client, error) {
-	return r.ListIndividualsWithArchiveScope(ownerID, search, limit, offset, ArchiveScopeActiveOnly)
+func (r *ClientRepository) ListIndividuals(ctx context.Context, ownerID int, search string, limit, offset int) ([]*models.Client, error) {
+	return r.ListIndividualsWithArchiveScope(ctx, ownerID, search, limit, offset, ArchiveScopeActiveOnly)
 }
 
-func (r *ClientRepository) ListIndividualsWithArchiveScope(ownerID int, search string, limit, offset int, scope ArchiveScope) ([]*models.Client, error) {
+func (r *ClientRepository) ListIndividualsWithArchiveScope(ctx context.Context, ownerID int, search string, limit, offset int, scope ArchiveScope) ([]*models.Client, error) {
 	filter := ClientListFilter{Query: search}
-	return r.listWithFilterAndArchiveScope(nil, models.ClientTypeIndividual, limit, offset, filter, scope)
+	return r.listWithFilterAndArchiveScope(ctx, nil, models.ClientTypeIndividual, limit, offset, filter, scope)
 }
-func (r *ClientRepository) ListCompanies(ownerID int, search string, limit, offset int) ([]*models.Client, error) {
-	return r.ListCompaniesWithArchiveScope(ownerID, search, limit, offset, ArchiveScopeActiveOnly)
+func (r *ClientRepository) ListCompanies(ctx context.Context, ownerID int, search string, limit, offset int) ([]*models.Client, error) {
+	return r.ListCompaniesWithArchiveScope(ctx, ownerID, search, limit, offset, ArchiveScopeActiveOnly)
 }
 
-func (r *ClientRepository) ListCompaniesWithArchiveScope(ownerID int, search string, limit, offset int, scope ArchiveScope) ([]*models.Client, error) {
+func (r *ClientRepository) ListCompaniesWithArchiveScope(ctx context.Context, ownerID int, search string, limit, offset int, scope ArchiveScope) ([]*models.Client, error) {
 	filter := ClientListFilter{Query: search}
-	return r.listWithFilterAndArchiveScope(nil, models.ClientTypeLegal, limit, offset, filter, scope)
+	return r.listWithFilterAndArchiveScope(ctx, nil, models.ClientTypeLegal, limit, offset, filter, scope)
 }
-func (r *ClientRepository) FindByName(name string) ([]*models.Client, error) {
+func (r *ClientRepository) FindByName(ctx context.Context, name string) ([]*models.Client, error) {
 	q := clientSelect + ` WHERE COALESCE(c.display_name,c.name) ILIKE $1 ORDER BY c.created_at DESC`
-	return r.queryMany(q, like(name))
+	return r.queryMany(ctx, q, like(name))
+}
+
+// SearchByNameOrBIN returns one page of active clients whose BIN equals q or
+// whose name contains it, exact BIN matches first, and the total number of
+// matches. A non-nil ownerID or branchID narrows the search to that scope.
+func (r *ClientRepository) SearchByNameOrBIN(ctx context.Context, q string, ownerID, branchID *int, limit, offset int) ([]*models.Client, int, error) {
+	q = strings.TrimSpace(q)
+	conditions := []string{
+		"c.is_archived = FALSE",
+		`(COALESCE(lp.bin,c.bin_iin) = $1 OR COALESCE(c.display_name,c.name) ILIKE $2 ESCAPE '\')`,
+	}
+	args := []any{q, "%" + escapeLikePattern(q) + "%"}
+	if ownerID != nil {
+		args = append(args, *ownerID)
+		conditions = append(conditions, fmt.Sprintf("c.owner_id = $%d", len(args)))
+	}
+	if branchID != nil {
+		args = append(args, *branchID)
+		conditions = append(conditions, fmt.Sprintf("c.branch_id = $%d", len(args)))
+	}
+	where := strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(1) "+clientSelectFrom+" WHERE "+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count client search: %w", err)
+	}
+	if total == 0 || offset >= total {
+		return []*models.Client{}, total, nil
+	}
+
+	args = append(args, limit, offset)
+	query := clientSelect + " WHERE " + where + fmt.Sprintf(
+		" ORDER BY (COALESCE(lp.bin,c.bin_iin) = $1) DESC, c.created_at DESC, c.id DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+	clients, err := r.queryMany(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("client search: %w", err)
+	}
+	return clients, total, nil
 }
 
-func (r *ClientRepository) UpdatePartial(id int, updates map[string]any) error {
+func (r *ClientRepository) UpdatePartial(ctx context.Context, id int, updates map[string]any) error {
 	if len(updates) == 0 {
 		return nil
 	}
-	current, err := r.GetByID(id)
+	current, err := r.GetByID(ctx, id)
 	if err != nil || current == nil {
 		return err
 	}
@@ -644,11 +779,11 @@ func (r *ClientRepository) UpdatePartial(id int, updates map[string]any) error {
 			}
 		}
 	}
-	return r.Update(current)
+	return r.Update(ctx, current)
 }
 
-func (r *ClientRepository) queryMany(q string, args ...any) ([]*models.Client, error) {
-	rows, err := r.db.Query(q, args...)
+func (r *ClientRepository) queryMany(ctx context.Context, q string, args ...any) ([]*models.Client, error) {
+	rows, err := r.db.QueryContext(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -672,12 +807,12 @@ func like(v string) string {
 	return "%" + v + "%"
 }
 
-func (r *ClientRepository) listWithFilterAndArchiveScope(ownerID *int, forcedType string, limit, offset int, filter ClientListFilter, scope ArchiveScope) ([]*models.Client, error) {
+func (r *ClientRepository) listWithFilterAndArchiveScope(ctx context.Context, ownerID *int, forcedType string, limit, offset int, filter ClientListFilter, scope ArchiveScope) ([]*models.Client, error) {
 	sortExpr, sortOrder := clientSortExpression(filter)
 	where, args := buildClientListWhere(ownerID, forcedType, filter, scope, 1)
 	args = append(args, limit, offset)
 	query := clientSelect + fmt.Sprintf(` WHERE %s ORDER BY %s %s LIMIT $%d OFFSET $%d`, where, sortExpr, sortOrder, len(args)-1, len(args))
-	clients, err := r.queryMany(query, args...)
+	clients, err := r.queryMany(ctx, query, args...)
 	if err == nil {
 		return clients, nil
 	}
@@ -692,25 +827,25 @@ func (r *ClientRepository) listWithFilterAndArchiveScope(ownerID *int, forcedTyp
 		return nil, err
 	}
 	legacyQuery := clientSelectLegacy + fmt.Sprintf(` WHERE %s ORDER BY %s %s LIMIT $%d OFFSET $%d`, where, sortExpr, sortOrder, len(args)-1, len(args))
-	clients, err = r.queryMany(legacyQuery, args...)
+	clients, err = r.queryMany(ctx, legacyQuery, args...)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", fallbackLabel, err)
 	}
 	return clients, nil
 }
 
-func (r *ClientRepository) CountWithFilterAndArchiveScope(ownerID *int, forcedType string, filter ClientListFilter, scope ArchiveScope) (int, error) {
+func (r *ClientRepository) CountWithFilterAndArchiveScope(ctx context.Context, ownerID *int, forcedType string, filter ClientListFilter, scope ArchiveScope) (int, error) {
 	where, args := buildClientListWhere(ownerID, forcedType, filter, scope, 1)
 	var total int
 	query := "SELECT COUNT(1) " + clientSelectFrom + fmt.Sprintf(" WHERE %s", where)
-	if err := r.db.QueryRow(query, args...).Scan(&total); err == nil {
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&total); err == nil {
 		return total, nil
 	} else if !isProfileSplitTableMissing(err) {
 		return 0, fmt.Errorf("count clients primary query: %w", err)
 	}
 
 	legacyQuery := "SELECT COUNT(1) " + clientSelectFromLegacy + fmt.Sprintf(" WHERE %s", where)
-	if err := r.db.QueryRow(legacyQuery, args...).Scan(&total); err != nil {
+	if err := r.db.QueryRowContext(ctx, legacyQuery, args...).Scan(&total); err != nil {
 		return 0, fmt.Errorf("count clients legacy fallback: %w", err)
 	}
 	return total, nil
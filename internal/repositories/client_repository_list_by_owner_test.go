@@ -102,7 +102,7 @@ func TestQueryManyWrapsScanError(t *testing.T) {
 	defer db.Close()
 
 	repo := NewClientRepository(db)
-	_, err = repo.queryMany("SELECT 1")
+	_, err = repo.queryMany(context.Background(), "SELECT 1")
 	if err == nil {
 		t.Fatal("expected scan error")
 	}
@@ -131,7 +131,7 @@ func TestListByOwnerWrapsPrimaryAndFallbackErrors(t *testing.T) {
 	defer db.Close()
 
 	repo := NewClientRepository(db)
-	_, err = repo.ListByOwner(7, 20, 0, "")
+	_, err = repo.ListByOwner(context.Background(), 7, 20, 0, "")
 	if err == nil {
 		t.Fatal("expected error")
 	}
@@ -163,7 +163,7 @@ func TestListByOwnerSupportsMixedClientTypeRows(t *testing.T) {
 	defer db.Close()
 
 	repo := NewClientRepository(db)
-	clients, err := repo.ListByOwner(7, 20, 0, "")
+	clients, err := repo.ListByOwner(context.Background(), 7, 20, 0, "")
 	if err != nil {
 		t.Fatalf("ListByOwner returned error: %v", err)
 	}
@@ -213,7 +213,7 @@ func TestListByOwnerHandlesNullPrimaryEmail(t *testing.T) {
 	defer db.Close()
 
 	repo := NewClientRepository(db)
-	clients, err := repo.ListByOwner(7, 20, 0, "")
+	clients, err := repo.ListByOwner(context.Background(), 7, 20, 0, "")
 	if err != nil {
 		t.Fatalf("ListByOwner returned error: %v", err)
 	}
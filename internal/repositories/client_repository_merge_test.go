@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func openScriptedClientDB(t *testing.T, steps []scriptedStep) (*sql.DB, *scriptedDriver) {
+	t.Helper()
+	driverName := fmt.Sprintf("scripted-client-%d", time.Now().UnixNano())
+	mockDriver := &scriptedDriver{steps: steps}
+	sql.Register(driverName, mockDriver)
+	db, err := sql.Open(driverName, "")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db, mockDriver
+}
+
+func TestClientRepository_Merge_MovesDealsAndDeletesDuplicate(t *testing.T) {
+	db, mockDriver := openScriptedClientDB(t, []scriptedStep{
+		{kind: "begin"},
+		{
+			kind:    "query",
+			query:   "SELECT id FROM clients WHERE id IN ($1, $2) ORDER BY id FOR UPDATE",
+			args:    []any{int64(5), int64(9)},
+			columns: []string{"id"},
+			rows:    [][]driver.Value{{int64(5)}, {int64(9)}},
+		},
+		{
+			kind:   "exec",
+			query:  "UPDATE deals SET client_id = $2, updated_at = NOW() WHERE client_id = $1",
+			args:   []any{int64(9), int64(5)},
+			result: driver.RowsAffected(3),
+		},
+		{kind: "exec", query: "UPDATE documents SET client_id = $2 WHERE client_id = $1", args: []any{int64(9), int64(5)}},
+		{kind: "exec", query: "UPDATE client_files f SET client_id = $2", args: []any{int64(9), int64(5)}},
+		{kind: "exec", query: "UPDATE telephony_calls SET client_id = $2 WHERE client_id = $1", args: []any{int64(9), int64(5)}},
+		{kind: "exec", query: "UPDATE chats SET client_ref_id = $2 WHERE client_ref_id = $1", args: []any{int64(9), int64(5)}},
+		{kind: "exec", query: "UPDATE tasks SET entity_id = $2 WHERE entity_type = 'client' AND entity_id = $1", args: []any{int64(9), int64(5)}},
+		{kind: "exec", query: "DELETE FROM clients WHERE id = $1", args: []any{int64(9)}},
+		{kind: "commit"},
+	})
+
+	moved, err := NewClientRepository(db).Merge(context.Background(), 5, 9)
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if moved != 3 {
+		t.Fatalf("moved deals = %d, want 3", moved)
+	}
+	if !mockDriver.consumedAll() {
+		t.Fatalf("not all scripted steps were consumed: pos=%d total=%d", mockDriver.pos, len(mockDriver.steps))
+	}
+}
+
+func TestClientRepository_Merge_MissingClientRollsBack(t *testing.T) {
+	db, mockDriver := openScriptedClientDB(t, []scriptedStep{
+		{kind: "begin"},
+		{
+			kind:    "query",
+			query:   "SELECT id FROM clients WHERE id IN ($1, $2) ORDER BY id FOR UPDATE",
+			args:    []any{int64(5), int64(9)},
+			columns: []string{"id"},
+			rows:    [][]driver.Value{{int64(5)}},
+		},
+		{kind: "rollback"},
+	})
+
+	if _, err := NewClientRepository(db).Merge(context.Background(), 5, 9); !errors.Is(err, ErrClientNotFound) {
+		t.Fatalf("expected ErrClientNotFound, got %v", err)
+	}
+	if !mockDriver.consumedAll() {
+		t.Fatalf("not all scripted steps were consumed: pos=%d total=%d", mockDriver.pos, len(mockDriver.steps))
+	}
+}
+
+func TestClientRepository_Merge_DealFailureRollsBack(t *testing.T) {
+	db, mockDriver := openScriptedClientDB(t, []scriptedStep{
+		{kind: "begin"},
+		{
+			kind:    "query",
+			query:   "SELECT id FROM clients WHERE id IN ($1, $2) ORDER BY id FOR UPDATE",
+			args:    []any{int64(5), int64(9)},
+			columns: []string{"id"},
+			rows:    [][]driver.Value{{int64(5)}, {int64(9)}},
+		},
+		{kind: "exec", query: "UPDATE deals SET client_id = $2", args: []any{int64(9), int64(5)}, err: errors.New("boom")},
+		{kind: "rollback"},
+	})
+
+	if _, err := NewClientRepository(db).Merge(context.Background(), 5, 9); err == nil {
+		t.Fatal("expected error when deals cannot be reassigned")
+	}
+	if !mockDriver.consumedAll() {
+		t.Fatalf("not all scripted steps were consumed: pos=%d total=%d", mockDriver.pos, len(mockDriver.steps))
+	}
+}
@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+)
+
+func TestClientRepository_SearchByNameOrBIN_PagesInSQL(t *testing.T) {
+	branch := 4
+	db, mockDriver := openScriptedClientDB(t, []scriptedStep{
+		{
+			kind:    "query",
+			query:   `SELECT COUNT(1) FROM clients c LEFT JOIN client_individual_profiles ip ON ip.client_id = c.id LEFT JOIN client_legal_profiles lp ON lp.client_id = c.id WHERE c.is_archived = FALSE AND (COALESCE(lp.bin,c.bin_iin) = $1 OR COALESCE(c.display_name,c.name) ILIKE $2 ESCAPE '\') AND c.branch_id = $3`,
+			args:    []any{"50%", `%50\%%`, int64(4)},
+			columns: []string{"count"},
+			rows:    [][]driver.Value{{int64(7)}},
+		},
+		{
+			kind:  "query",
+			query: "AND c.branch_id = $3 ORDER BY (COALESCE(lp.bin,c.bin_iin) = $1) DESC, c.created_at DESC, c.id DESC LIMIT $4 OFFSET $5",
+			args:  []any{"50%", `%50\%%`, int64(4), int64(5), int64(5)},
+		},
+	})
+
+	items, total, err := NewClientRepository(db).SearchByNameOrBIN(context.Background(), " 50% ", nil, &branch, 5, 5)
+	if err != nil {
+		t.Fatalf("SearchByNameOrBIN: %v", err)
+	}
+	if total != 7 || len(items) != 0 {
+		t.Fatalf("expected total 7 and the scripted empty page, got total=%d items=%d", total, len(items))
+	}
+	if !mockDriver.consumedAll() {
+		t.Fatalf("not all scripted steps were consumed: pos=%d total=%d", mockDriver.pos, len(mockDriver.steps))
+	}
+}
+
+func TestClientRepository_SearchByNameOrBIN_SkipsPageQueryPastTotal(t *testing.T) {
+	owner := 10
+	db, mockDriver := openScriptedClientDB(t, []scriptedStep{
+		{
+			kind:    "query",
+			query:   "AND c.owner_id = $3",
+			args:    []any{"alpha", "%alpha%", int64(10)},
+			columns: []string{"count"},
+			rows:    [][]driver.Value{{int64(2)}},
+		},
+	})
+
+	items, total, err := NewClientRepository(db).SearchByNameOrBIN(context.Background(), "alpha", &owner, nil, 10, 20)
+	if err != nil {
+		t.Fatalf("SearchByNameOrBIN: %v", err)
+	}
+	if total != 2 || len(items) != 0 {
+		t.Fatalf("expected total 2 and an empty page, got total=%d items=%d", total, len(items))
+	}
+	if !mockDriver.consumedAll() {
+		t.Fatalf("not all scripted steps were consumed: pos=%d total=%d", mockDriver.pos, len(mockDriver.steps))
+	}
+}
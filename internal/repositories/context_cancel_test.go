@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// Отменённый контекст запроса должен прерывать SQL до обращения к базе:
+// скриптованный драйвер без шагов упал бы на любом реальном запросе.
+func TestRepositories_CancelledContextAbortsQuery(t *testing.T) {
+	db := openScriptedDB(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := map[string]func() error{
+		"lead": func() error {
+			_, err := NewLeadRepository(db).GetByID(ctx, 1)
+			return err
+		},
+		"deal": func() error {
+			_, err := NewDealRepository(db).GetByID(ctx, 1)
+			return err
+		},
+		"client": func() error {
+			_, err := NewClientRepository(db).GetByID(ctx, 1)
+			return err
+		},
+		"document": func() error {
+			_, err := NewDocumentRepository(db).GetByID(ctx, 1)
+			return err
+		},
+	}
+	for name, call := range calls {
+		if err := call(); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: expected context.Canceled, got %v", name, err)
+		}
+	}
+}
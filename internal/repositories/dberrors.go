@@ -1,7 +1,9 @@
 package repositories
 
 import (
+	"database/sql"
 	"errors"
+	"fmt"
 
 	"github.com/lib/pq"
 )
@@ -35,3 +37,12 @@ func ConstraintName(err error) string {
 	}
 	return string(pqErr.Constraint)
 }
+
+// notFoundOr maps sql.ErrNoRows to ErrNotFound and leaves other errors as is.
+// The result still matches sql.ErrNoRows for callers that check it directly.
+func notFoundOr(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+	return err
+}
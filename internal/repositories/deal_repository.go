@@ -29,6 +29,12 @@ type DealListFilter struct {
 	Order        string
 	BranchID     *int
 	DepartmentID *int
+	// OwnerID limits the list to one owner's deals; the service sets it for
+	// sales so GET /deals only returns their own deals.
+	OwnerID *int
+	// After switches the list to keyset paging: only deals after this
+	// (created_at, id) position, ordered by created_at.
+	After *ListCursor
 }
 
 func NewDealRepository(db *sql.DB) *DealRepository {
@@ -58,7 +64,7 @@ func dealArchiveWhere(scope ArchiveScope, alias string) string {
 }
 
 // Создание сделки — возвращает ID новой записи
-func (r *DealRepository) Create(deal *models.Deals) (int64, error) {
+func (r *DealRepository) Create(ctx context.Context, deal *models.Deals) (int64, error) {
 	query := `
 		INSERT INTO deals (lead_id, client_id, owner_id, branch_id, amount, currency, status, created_at, department_id)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8,
@@ -70,7 +76,7 @@ func (r *DealRepository) Create(deal *models.Deals) (int64, error) {
 		RETURNING id
 	`
 	var id int64
-	err := r.db.QueryRow(
+	err := r.db.QueryRowContext(ctx,
 		query,
 		deal.LeadID,    // $1
 		deal.ClientID,  // $2
@@ -89,13 +95,13 @@ func (r *DealRepository) Create(deal *models.Deals) (int64, error) {
 }
 
 // Получение сделки по lead_id (последняя по времени)
-func (r *DealRepository) GetByLeadID(leadID int) (*models.Deals, error) {
-	return r.GetByLeadIDWithArchiveScope(leadID, ArchiveScopeActiveOnly)
+func (r *DealRepository) GetByLeadID(ctx context.Context, leadID int) (*models.Deals, error) {
+	return r.GetByLeadIDWithArchiveScope(ctx, leadID, ArchiveScopeActiveOnly)
 }
 
-func (r *DealRepository) GetByLeadIDWithArchiveScope(leadID int, scope ArchiveScope) (*models.Deals, error) {
+func (r *DealRepository) GetByLeadIDWithArchiveScope(ctx context.Context, leadID int, scope ArchiveScope) (*models.Deals, error) {
 	query := `
-		SELECT d.id, d.lead_id, d.client_id, COALESCE(c.client_type, ''), d.owner_id, d.branch_id, COALESCE(b.name,''), d.department_id, d.funnel_id, d.amount, d.currency, d.status, d.created_at, d.is_archived, d.archived_at, d.archived_by, d.archive_reason
+		SELECT d.id, d.lead_id, d.client_id, COALESCE(c.client_type, ''), d.owner_id, d.branch_id, COALESCE(b.name,''), d.department_id, d.funnel_id, d.amount, d.currency, d.status, d.created_at, d.updated_at, d.is_archived, d.archived_at, d.archived_by, d.archive_reason
 		FROM deals d
 		LEFT JOIN clients c ON c.id = d.client_id
 		LEFT JOIN branches b ON b.id = d.branch_id
@@ -116,7 +122,7 @@ func (r *DealRepository) GetByLeadIDWithArchiveScope(leadID int, scope ArchiveSc
 	var archivedBy sql.NullInt64
 	var archiveReason sql.NullString
 
-	err := r.db.QueryRow(fmt.Sprintf(query, dealArchiveWhere(scope, "d")), leadID).Scan(
+	err := r.db.QueryRowContext(ctx, fmt.Sprintf(query, dealArchiveWhere(scope, "d")), leadID).Scan(
 		&deal.ID,
 		&deal.LeadID,
 		&deal.ClientID,
@@ -130,6 +136,7 @@ func (r *DealRepository) GetByLeadIDWithArchiveScope(leadID int, scope ArchiveSc
 		&deal.Currency,
 		&status,
 		&deal.CreatedAt,
+		&deal.UpdatedAt,
 		&isArchived,
 		&archivedAt,
 		&archivedBy,
@@ -172,13 +179,13 @@ func (r *DealRepository) GetByLeadIDWithArchiveScope(leadID int, scope ArchiveSc
 	return deal, nil
 }
 
-func (r *DealRepository) Update(deal *models.Deals) error {
+func (r *DealRepository) Update(ctx context.Context, deal *models.Deals) error {
 	query := `
 		UPDATE deals
-		SET lead_id=$1, client_id=$2, owner_id=$3, branch_id=$4, amount=$5, currency=$6, status=$7
+		SET lead_id=$1, client_id=$2, owner_id=$3, branch_id=$4, amount=$5, currency=$6, status=$7, updated_at=NOW()
 		WHERE id=$8
 	`
-	_, err := r.db.Exec(query,
+	_, err := r.db.ExecContext(ctx, query,
 		deal.LeadID,   // $1
 		deal.ClientID, // $2
 		deal.OwnerID,  // $3
@@ -196,13 +203,13 @@ func (r *DealRepository) Update(deal *models.Deals) error {
 }
 
 // Получение по ID
-func (r *DealRepository) GetByID(id int) (*models.Deals, error) {
-	return r.GetByIDWithArchiveScope(id, ArchiveScopeActiveOnly)
+func (r *DealRepository) GetByID(ctx context.Context, id int) (*models.Deals, error) {
+	return r.GetByIDWithArchiveScope(ctx, id, ArchiveScopeActiveOnly)
 }
 
-func (r *DealRepository) GetByIDWithArchiveScope(id int, scope ArchiveScope) (*models.Deals, error) {
+func (r *DealRepository) GetByIDWithArchiveScope(ctx context.Context, id int, scope ArchiveScope) (*models.Deals, error) {
 	query := `
-		SELECT d.id, d.lead_id, d.client_id, COALESCE(c.client_type, ''), d.owner_id, d.branch_id, COALESCE(b.name,''), d.department_id, d.funnel_id, d.amount, d.currency, d.status, d.created_at, d.is_archived, d.archived_at, d.archived_by, d.archive_reason
+		SELECT d.id, d.lead_id, d.client_id, COALESCE(c.client_type, ''), d.owner_id, d.branch_id, COALESCE(b.name,''), d.department_id, d.funnel_id, d.amount, d.currency, d.status, d.created_at, d.updated_at, d.is_archived, d.archived_at, d.archived_by, d.archive_reason
 		FROM deals d
 		LEFT JOIN clients c ON c.id = d.client_id
 		LEFT JOIN branches b ON b.id = d.branch_id
@@ -221,7 +228,7 @@ func (r *DealRepository) GetByIDWithArchiveScope(id int, scope ArchiveScope) (*m
 	var archivedBy sql.NullInt64
 	var archiveReason sql.NullString
 
-	err := r.db.QueryRow(fmt.Sprintf(query, dealArchiveWhere(scope, "d")), id).Scan(
+	err := r.db.QueryRowContext(ctx, fmt.Sprintf(query, dealArchiveWhere(scope, "d")), id).Scan(
 		&deal.ID,
 		&deal.LeadID,
 		&deal.ClientID,
@@ -235,6 +242,7 @@ func (r *DealRepository) GetByIDWithArchiveScope(id int, scope ArchiveScope) (*m
 		&deal.Currency,
 		&status,
 		&deal.CreatedAt,
+		&deal.UpdatedAt,
 		&isArchived,
 		&archivedAt,
 		&archivedBy,
@@ -278,9 +286,9 @@ func (r *DealRepository) GetByIDWithArchiveScope(id int, scope ArchiveScope) (*m
 }
 
 // Удаление по ID
-func (r *DealRepository) Delete(id int) error {
+func (r *DealRepository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM deals WHERE id=$1`
-	result, err := r.db.Exec(query, id)
+	result, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("удаление сделки: %w", err)
 	}
@@ -294,42 +302,44 @@ func (r *DealRepository) Delete(id int) error {
 	return nil
 }
 
-func (r *DealRepository) Archive(id, archivedBy int, reason string) error {
+func (r *DealRepository) Archive(ctx context.Context, id, archivedBy int, reason string) error {
 	query := `
 		UPDATE deals
 		SET is_archived = TRUE,
 		    archived_at = NOW(),
 		    archived_by = $2,
-		    archive_reason = $3
+		    archive_reason = $3,
+		    updated_at = NOW()
 		WHERE id=$1
 	`
-	_, err := r.db.Exec(query, id, archivedBy, reason)
+	_, err := r.db.ExecContext(ctx, query, id, archivedBy, reason)
 	return err
 }
 
-func (r *DealRepository) Unarchive(id int) error {
+func (r *DealRepository) Unarchive(ctx context.Context, id int) error {
 	query := `
 		UPDATE deals
 		SET is_archived = FALSE,
 		    archived_at = NULL,
 		    archived_by = NULL,
-		    archive_reason = NULL
+		    archive_reason = NULL,
+		    updated_at = NOW()
 		WHERE id=$1
 	`
-	_, err := r.db.Exec(query, id)
+	_, err := r.db.ExecContext(ctx, query, id)
 	return err
 }
 
 // Подсчёт сделок
-func (r *DealRepository) CountDeals() (int, error) {
+func (r *DealRepository) CountDeals(ctx context.Context) (int, error) {
 	var count int
 	query := "SELECT COUNT(*) FROM deals WHERE is_archived = FALSE"
-	err := r.db.QueryRow(query).Scan(&count)
+	err := r.db.QueryRowContext(ctx, query).Scan(&count)
 	return count, err
 }
 
 // Фильтрация
-func (r *DealRepository) FilterDeals(status, fromDate, toDate, currency, sortBy, order string, amountMin, amountMax float64, limit, offset int) ([]models.Deals, error) {
+func (r *DealRepository) FilterDeals(ctx context.Context, status, fromDate, toDate, currency, sortBy, order string, amountMin, amountMax float64, limit, offset int) ([]models.Deals, error) {
 	if sortBy == "" {
 		sortBy = "created_at"
 	}
@@ -342,13 +352,14 @@ func (r *DealRepository) FilterDeals(status, fromDate, toDate, currency, sortBy,
 		"amount":     "d.amount",
 		"status":     "d.status",
 		"currency":   "d.currency",
+		"updated_at": "d.updated_at",
 	}
 	sortExpr, ok := allowedSortFields[sortBy]
 	if !ok {
 		sortExpr = "d.created_at"
 	}
 
-	query := "SELECT d.id, d.lead_id, d.client_id, COALESCE(c.client_type, ''), d.owner_id, d.branch_id, COALESCE(b.name,''), d.department_id, d.funnel_id, d.amount, d.currency, d.status, d.created_at, d.is_archived, d.archived_at, d.archived_by, d.archive_reason FROM deals d LEFT JOIN clients c ON c.id = d.client_id LEFT JOIN branches b ON b.id = d.branch_id WHERE d.is_archived = FALSE"
+	query := "SELECT d.id, d.lead_id, d.client_id, COALESCE(c.client_type, ''), d.owner_id, d.branch_id, COALESCE(b.name,''), d.department_id, d.funnel_id, d.amount, d.currency, d.status, d.created_at, d.updated_at, d.is_archived, d.archived_at, d.archived_by, d.archive_reason FROM deals d LEFT JOIN clients c ON c.id = d.client_id LEFT JOIN branches b ON b.id = d.branch_id WHERE d.is_archived = FALSE"
 	args := []interface{}{}
 	i := 1
 
@@ -386,7 +397,7 @@ func (r *DealRepository) FilterDeals(status, fromDate, toDate, currency, sortBy,
 	query += fmt.Sprintf(" ORDER BY %s %s LIMIT $%d OFFSET $%d", sortExpr, order, i, i+1)
 	args = append(args, limit, offset)
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -398,6 +409,7 @@ func (r *DealRepository) FilterDeals(status, fromDate, toDate, currency, sortBy,
 		var status sql.NullString
 		var branchID sql.NullInt64
 		var branchName sql.NullString
+		var departmentID sql.NullInt64
 		var funnelID sql.NullInt64
 		var isArchived bool
 		var archivedAt sql.NullTime
@@ -412,11 +424,13 @@ func (r *DealRepository) FilterDeals(status, fromDate, toDate, currency, sortBy,
 			&deal.OwnerID,
 			&branchID,
 			&branchName,
+			&departmentID,
 			&funnelID,
 			&deal.Amount,
 			&deal.Currency,
 			&status,
 			&deal.CreatedAt,
+			&deal.UpdatedAt,
 			&isArchived,
 			&archivedAt,
 			&archivedBy,
@@ -433,6 +447,10 @@ func (r *DealRepository) FilterDeals(status, fromDate, toDate, currency, sortBy,
 		if branchName.Valid {
 			deal.BranchName = branchName.String
 		}
+		if departmentID.Valid {
+			v := int(departmentID.Int64)
+			deal.DepartmentID = &v
+		}
 		if funnelID.Valid {
 			v := int(funnelID.Int64)
 			deal.FunnelID = &v
@@ -452,35 +470,33 @@ func (r *DealRepository) FilterDeals(status, fromDate, toDate, currency, sortBy,
 	return deals, nil
 }
 
-func (r *DealRepository) ListAll(limit, offset int) ([]*models.Deals, error) {
-	return r.ListAllWithFilterAndArchiveScope(limit, offset, DealListFilter{}, ArchiveScopeActiveOnly)
+func (r *DealRepository) ListAll(ctx context.Context, limit, offset int) ([]*models.Deals, error) {
+	return r.ListAllWithFilterAndArchiveScope(ctx, limit, offset, DealListFilter{}, ArchiveScopeActiveOnly)
 }
 
-func (r *DealRepository) ListAllWithArchiveScope(limit, offset int, scope ArchiveScope) ([]*models.Deals, error) {
-	return r.ListAllWithFilterAndArchiveScope(limit, offset, DealListFilter{}, scope)
+func (r *DealRepository) ListAllWithArchiveScope(ctx context.Context, limit, offset int, scope ArchiveScope) ([]*models.Deals, error) {
+	return r.ListAllWithFilterAndArchiveScope(ctx, limit, offset, DealListFilter{}, scope)
 }
 
-func (r *DealRepository) ListAllWithFilterAndArchiveScope(limit, offset int, filter DealListFilter, scope ArchiveScope) ([]*models.Deals, error) {
+func (r *DealRepository) ListAllWithFilterAndArchiveScope(ctx context.Context, limit, offset int, filter DealListFilter, scope ArchiveScope) ([]*models.Deals, error) {
 	query := `
-		SELECT d.id, d.lead_id, d.client_id, COALESCE(c.client_type, ''), d.owner_id, d.branch_id, COALESCE(b.name,''), d.department_id, d.funnel_id, d.amount, d.currency, d.status, d.created_at, d.is_archived, d.archived_at, d.archived_by, d.archive_reason
+		SELECT d.id, d.lead_id, d.client_id, COALESCE(c.client_type, ''), d.owner_id, d.branch_id, COALESCE(b.name,''), d.department_id, d.funnel_id, d.amount, d.currency, d.status, d.created_at, d.updated_at, d.is_archived, d.archived_at, d.archived_by, d.archive_reason
 		FROM deals d
 		LEFT JOIN clients c ON c.id = d.client_id
 		LEFT JOIN branches b ON b.id = d.branch_id
 		WHERE %s%s
-		ORDER BY %s %s
+		ORDER BY %s
 		LIMIT $%d OFFSET $%d
 	`
 
-	sortExpr, sortOrder := dealSortExpression(filter)
 	extraWhere, args := buildDealListWhere(filter, 1)
 	args = append(args, limit, offset)
-	rows, err := r.db.Query(
+	rows, err := r.db.QueryContext(ctx,
 		fmt.Sprintf(
 			query,
 			dealArchiveWhere(scope, "d"),
 			extraWhere,
-			sortExpr,
-			sortOrder,
+			dealOrderBy(filter),
 			len(args)-1,
 			len(args),
 		),
@@ -518,6 +534,7 @@ func (r *DealRepository) ListAllWithFilterAndArchiveScope(limit, offset int, fil
 			&d.Currency,
 			&status,
 			&d.CreatedAt,
+			&d.UpdatedAt,
 			&isArchived,
 			&archivedAt,
 			&archivedBy,
@@ -557,41 +574,45 @@ func (r *DealRepository) ListAllWithFilterAndArchiveScope(limit, offset int, fil
 	return deals, nil
 }
 
-func (r *DealRepository) ListPaginated(limit, offset int) ([]*models.Deals, error) {
-	return r.ListAll(limit, offset)
+func (r *DealRepository) ListPaginated(ctx context.Context, limit, offset int) ([]*models.Deals, error) {
+	return r.ListAll(ctx, limit, offset)
+}
+
+// ListPaginatedAfter is the keyset variant of ListPaginated: up to limit
+// active rows after the cursor (nil = first page), newest first.
+func (r *DealRepository) ListPaginatedAfter(ctx context.Context, after *ListCursor, limit int) ([]*models.Deals, error) {
+	return r.ListAllWithFilterAndArchiveScope(ctx, limit, 0, DealListFilter{After: after}, ArchiveScopeActiveOnly)
 }
 
 // Только сделки конкретного владельца
-func (r *DealRepository) ListByOwner(ownerID, limit, offset int) ([]*models.Deals, error) {
-	return r.ListByOwnerWithFilterAndArchiveScope(ownerID, limit, offset, DealListFilter{}, ArchiveScopeActiveOnly)
+func (r *DealRepository) ListByOwner(ctx context.Context, ownerID, limit, offset int) ([]*models.Deals, error) {
+	return r.ListByOwnerWithFilterAndArchiveScope(ctx, ownerID, limit, offset, DealListFilter{}, ArchiveScopeActiveOnly)
 }
 
-func (r *DealRepository) ListByOwnerWithArchiveScope(ownerID, limit, offset int, scope ArchiveScope) ([]*models.Deals, error) {
-	return r.ListByOwnerWithFilterAndArchiveScope(ownerID, limit, offset, DealListFilter{}, scope)
+func (r *DealRepository) ListByOwnerWithArchiveScope(ctx context.Context, ownerID, limit, offset int, scope ArchiveScope) ([]*models.Deals, error) {
+	return r.ListByOwnerWithFilterAndArchiveScope(ctx, ownerID, limit, offset, DealListFilter{}, scope)
 }
 
-func (r *DealRepository) ListByOwnerWithFilterAndArchiveScope(ownerID, limit, offset int, filter DealListFilter, scope ArchiveScope) ([]*models.Deals, error) {
+func (r *DealRepository) ListByOwnerWithFilterAndArchiveScope(ctx context.Context, ownerID, limit, offset int, filter DealListFilter, scope ArchiveScope) ([]*models.Deals, error) {
 	query := `
-		SELECT d.id, d.lead_id, d.client_id, COALESCE(c.client_type, ''), d.owner_id, d.branch_id, COALESCE(b.name,''), d.department_id, d.funnel_id, d.amount, d.currency, d.status, d.created_at, d.is_archived, d.archived_at, d.archived_by, d.archive_reason
+		SELECT d.id, d.lead_id, d.client_id, COALESCE(c.client_type, ''), d.owner_id, d.branch_id, COALESCE(b.name,''), d.department_id, d.funnel_id, d.amount, d.currency, d.status, d.created_at, d.updated_at, d.is_archived, d.archived_at, d.archived_by, d.archive_reason
 		FROM deals d
 		LEFT JOIN clients c ON c.id = d.client_id
 		LEFT JOIN branches b ON b.id = d.branch_id
 		WHERE d.owner_id = $1 AND %s%s
-		ORDER BY %s %s
+		ORDER BY %s
 		LIMIT $%d OFFSET $%d
 	`
 
-	sortExpr, sortOrder := dealSortExpression(filter)
 	extraWhere, args := buildDealListWhere(filter, 2)
 	args = append([]interface{}{ownerID}, args...)
 	args = append(args, limit, offset)
-	rows, err := r.db.Query(
+	rows, err := r.db.QueryContext(ctx,
 		fmt.Sprintf(
 			query,
 			dealArchiveWhere(scope, "d"),
 			extraWhere,
-			sortExpr,
-			sortOrder,
+			dealOrderBy(filter),
 			len(args)-1,
 			len(args),
 		),
@@ -629,6 +650,7 @@ func (r *DealRepository) ListByOwnerWithFilterAndArchiveScope(ownerID, limit, of
 			&d.Currency,
 			&status,
 			&d.CreatedAt,
+			&d.UpdatedAt,
 			&isArchived,
 			&archivedAt,
 			&archivedBy,
@@ -668,22 +690,22 @@ func (r *DealRepository) ListByOwnerWithFilterAndArchiveScope(ownerID, limit, of
 	return deals, nil
 }
 
-func (r *DealRepository) CountAllWithFilterAndArchiveScope(filter DealListFilter, scope ArchiveScope) (int, error) {
+func (r *DealRepository) CountAllWithFilterAndArchiveScope(ctx context.Context, filter DealListFilter, scope ArchiveScope) (int, error) {
 	extraWhere, args := buildDealListWhere(filter, 1)
 	query := fmt.Sprintf(`SELECT COUNT(1) FROM deals d LEFT JOIN clients c ON c.id = d.client_id WHERE %s%s`, dealArchiveWhere(scope, "d"), extraWhere)
 	var total int
-	if err := r.db.QueryRow(query, args...).Scan(&total); err != nil {
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
 		return 0, err
 	}
 	return total, nil
 }
 
-func (r *DealRepository) CountByOwnerWithFilterAndArchiveScope(ownerID int, filter DealListFilter, scope ArchiveScope) (int, error) {
+func (r *DealRepository) CountByOwnerWithFilterAndArchiveScope(ctx context.Context, ownerID int, filter DealListFilter, scope ArchiveScope) (int, error) {
 	extraWhere, args := buildDealListWhere(filter, 2)
 	args = append([]interface{}{ownerID}, args...)
 	query := fmt.Sprintf(`SELECT COUNT(1) FROM deals d LEFT JOIN clients c ON c.id = d.client_id WHERE d.owner_id = $1 AND %s%s`, dealArchiveWhere(scope, "d"), extraWhere)
 	var total int
-	if err := r.db.QueryRow(query, args...).Scan(&total); err != nil {
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
 		return 0, err
 	}
 	return total, nil
@@ -744,6 +766,11 @@ func buildDealListWhere(filter DealListFilter, startAt int) (string, []interface
 		args = append(args, *filter.DepartmentID)
 		idx++
 	}
+	if filter.OwnerID != nil {
+		where += fmt.Sprintf(" AND d.owner_id = $%d", idx)
+		args = append(args, *filter.OwnerID)
+		idx++
+	}
 	if filter.Query != "" {
 		likePattern := "%" + strings.ToLower(filter.Query) + "%"
 		where += fmt.Sprintf(` AND (
@@ -755,6 +782,13 @@ func buildDealListWhere(filter DealListFilter, startAt int) (string, []interface
 			LOWER(COALESCE(d.currency, '')) LIKE $%d
 		)`, idx, idx, idx, idx, idx, idx)
 		args = append(args, likePattern)
+		idx++
+	}
+	if filter.After != nil {
+		_, order := dealSortExpression(filter)
+		keyset, keysetArgs := keysetWhere("d", order, filter.After, idx)
+		where += keyset
+		args = append(args, keysetArgs...)
 	}
 
 	return where, args
@@ -778,6 +812,9 @@ func dealSortExpression(filter DealListFilter) (string, string) {
 	if strings.EqualFold(filter.Order, "asc") {
 		order = "ASC"
 	}
+	if filter.After != nil {
+		return "d.created_at", order
+	}
 	switch filter.SortBy {
 	case "amount":
 		return "d.amount", order
@@ -785,49 +822,60 @@ func dealSortExpression(filter DealListFilter) (string, string) {
 		return "COALESCE(d.status, 'new')", order
 	case "client_name":
 		return "LOWER(COALESCE(c.display_name, c.name, ''))", order
+	case "updated_at":
+		return "d.updated_at", order
 	default:
 		return "d.created_at", order
 	}
 }
 
-func (r *DealRepository) UpdateStatus(id int, status string) error {
-	const q = `UPDATE deals SET status = $1 WHERE id = $2`
-	_, err := r.db.Exec(q, status, id)
+// dealOrderBy is the ORDER BY of deal lists; id breaks ties so both offset
+// and keyset pages are stable.
+func dealOrderBy(filter DealListFilter) string {
+	expr, order := dealSortExpression(filter)
+	return fmt.Sprintf("%s %s, d.id %s", expr, order, order)
+}
+
+func (r *DealRepository) UpdateStatus(ctx context.Context, id int, status string) error {
+	const q = `UPDATE deals SET status = $1, updated_at = NOW() WHERE id = $2`
+	_, err := r.db.ExecContext(ctx, q, status, id)
 	return err
 }
 
 // MoveStage updates a deal's stage (and derived status), assigning the deal to
 // funnelID if it didn't belong to a funnel yet.
-func (r *DealRepository) MoveStage(id, stageID, funnelID int, status string) error {
+func (r *DealRepository) MoveStage(ctx context.Context, id, stageID, funnelID int, status string) error {
 	const q = `
 		UPDATE deals
 		SET stage_id = $1,
 		    funnel_id = COALESCE(funnel_id, $2),
-		    status = $3
+		    status = $3,
+		    updated_at = NOW()
 		WHERE id = $4
 	`
-	_, err := r.db.Exec(q, stageID, funnelID, status, id)
+	_, err := r.db.ExecContext(ctx, q, stageID, funnelID, status, id)
 	return err
 }
 
 // MoveStageAndFunnel forcibly updates a deal's funnel, stage, and status.
 // Used for admin-configured automatic cross-funnel transitions.
-func (r *DealRepository) MoveStageAndFunnel(id, stageID, funnelID int, status string) error {
+func (r *DealRepository) MoveStageAndFunnel(ctx context.Context, id, stageID, funnelID int, status string) error {
 	const q = `
 		UPDATE deals
 		SET stage_id  = $1,
 		    funnel_id = $2,
-		    status    = $3
+		    status    = $3,
+		    updated_at = NOW()
 		WHERE id = $4
 	`
-	_, err := r.db.Exec(q, stageID, funnelID, status, id)
+	_, err := r.db.ExecContext(ctx, q, stageID, funnelID, status, id)
 	return err
 }
 
 // GetLatestByClientID возвращает последнюю сделку по client_id
-func (r *DealRepository) GetLatestByClientID(clientID int) (*models.Deals, error) {
+func (r *DealRepository) GetLatestByClientID(ctx context.Context, clientID int) (*models.Deals, error) {
 	query := `
-		SELECT d.id, d.lead_id, d.client_id, COALESCE(c.client_type, ''), d.owner_id, d.branch_id, COALESCE(b.name,''), d.department_id, d.funnel_id, d.amount, d.currency, d.status, d.created_at, d.is_archived, d.archived_at, d.archived_by, d.archive_reason
+		SELECT d.id, d.lead_id, d.client_id, COALESCE(c.client_type, ''), d.owner_id, d.branch_id, COALESCE(b.name,''), d.department_id, d.funnel_id, d.amount, d.currency, d.status, d.created_at, d.updated_at, d.is_archived, d.archived_at, d.archived_by, d.archive_reason
 		FROM deals d
 		LEFT JOIN clients c ON c.id = d.client_id
 		LEFT JOIN branches b ON b.id = d.branch_id
@@ -847,7 +895,7 @@ func (r *DealRepository) GetLatestByClientID(clientID int) (*models.Deals, error
 	var archivedBy sql.NullInt64
 	var archiveReason sql.NullString
 
-	err := r.db.QueryRow(query, clientID).Scan(
+	err := r.db.QueryRowContext(ctx, query, clientID).Scan(
 		&deal.ID,
 		&deal.LeadID,
 		&deal.ClientID,
@@ -861,6 +909,7 @@ func (r *DealRepository) GetLatestByClientID(clientID int) (*models.Deals, error
 		&deal.Currency,
 		&status,
 		&deal.CreatedAt,
+		&deal.UpdatedAt,
 		&isArchived,
 		&archivedAt,
 		&archivedBy,
@@ -904,9 +953,9 @@ func (r *DealRepository) GetLatestByClientID(clientID int) (*models.Deals, error
 }
 
 // GetLatestByClientRef возвращает последнюю сделку по точной typed ссылке клиента.
-func (r *DealRepository) GetLatestByClientRef(clientID int, clientType string) (*models.Deals, error) {
+func (r *DealRepository) GetLatestByClientRef(ctx context.Context, clientID int, clientType string) (*models.Deals, error) {
 	query := `
-		SELECT d.id, d.lead_id, d.client_id, COALESCE(c.client_type, ''), d.owner_id, d.branch_id, COALESCE(b.name,''), d.department_id, d.funnel_id, d.amount, d.currency, d.status, d.created_at, d.is_archived, d.archived_at, d.archived_by, d.archive_reason
+		SELECT d.id, d.lead_id, d.client_id, COALESCE(c.client_type, ''), d.owner_id, d.branch_id, COALESCE(b.name,''), d.department_id, d.funnel_id, d.amount, d.currency, d.status, d.created_at, d.updated_at, d.is_archived, d.archived_at, d.archived_by, d.archive_reason
 		FROM deals d
 		JOIN clients c ON c.id = d.client_id
 		LEFT JOIN branches b ON b.id = d.branch_id
@@ -926,7 +975,7 @@ func (r *DealRepository) GetLatestByClientRef(clientID int, clientType string) (
 	var archivedBy sql.NullInt64
 	var archiveReason sql.NullString
 
-	err := r.db.QueryRow(query, clientID, clientType).Scan(
+	err := r.db.QueryRowContext(ctx, query, clientID, clientType).Scan(
 		&deal.ID,
 		&deal.LeadID,
 		&deal.ClientID,
@@ -940,6 +989,7 @@ func (r *DealRepository) GetLatestByClientRef(clientID int, clientType string) (
 		&deal.Currency,
 		&status,
 		&deal.CreatedAt,
+		&deal.UpdatedAt,
 		&isArchived,
 		&archivedAt,
 		&archivedBy,
@@ -1058,6 +1108,51 @@ func (r *DealRepository) GetDealsRevenueStats(ctx context.Context, from, to time
 	return result, nil
 }
 
+// SumWonAmountByOwner возвращает сумму выигранных сделок по каждому владельцу
+// (отдельно по валютам).
+func (r *DealRepository) SumWonAmountByOwner(ctx context.Context, from, to time.Time, branchID *int) ([]models.OwnerRevenueRow, error) {
+	query, args := buildWonAmountByOwnerQuery(from, to, branchID)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("won amount by owner: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.OwnerRevenueRow
+	for rows.Next() {
+		var row models.OwnerRevenueRow
+		if err := rows.Scan(&row.OwnerID, &row.TotalAmount, &row.Currency); err != nil {
+			return nil, fmt.Errorf("scan won amount by owner row: %w", err)
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate won amount by owner rows: %w", err)
+	}
+
+	return result, nil
+}
+
+func buildWonAmountByOwnerQuery(from, to time.Time, branchID *int) (string, []interface{}) {
+	query := `
+		SELECT
+			owner_id,
+			SUM(amount) AS total_amount,
+			currency
+		FROM deals
+		WHERE status = 'won' AND created_at BETWEEN $1 AND $2`
+	args := []interface{}{from, to}
+
+	if branchID != nil {
+		query += fmt.Sprintf(" AND branch_id = $%d", len(args)+1)
+		args = append(args, *branchID)
+	}
+
+	query += " GROUP BY owner_id, currency ORDER BY total_amount DESC, owner_id"
+	return query, args
+}
+
 // GetTopClientsByRevenue возвращает топ клиентов по сумме выигранных сделок.
 func (r *DealRepository) GetTopClientsByRevenue(ctx context.Context, from, to time.Time, ownerID *int, branchID *int, limit int) ([]models.TopClientRow, error) {
 	query := `
@@ -1107,3 +1202,13 @@ func (r *DealRepository) GetTopClientsByRevenue(ctx context.Context, from, to ti
 
 	return result, nil
 }
+
+// ReassignClient moves every deal of fromClientID to toClientID inside tx and
+// reports how many deals moved.
+func (r *DealRepository) ReassignClient(ctx context.Context, tx *sql.Tx, fromClientID, toClientID int) (int64, error) {
+	res, err := tx.ExecContext(ctx, `UPDATE deals SET client_id = $2, updated_at = NOW() WHERE client_id = $1`, fromClientID, toClientID)
+	if err != nil {
+		return 0, fmt.Errorf("reassign deals: %w", err)
+	}
+	return res.RowsAffected()
+}
@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestBuildDealListWhere_SearchQueryAddsAllExpectedFields(t *testing.T) {
@@ -72,6 +73,7 @@ func TestDealSortExpression(t *testing.T) {
 		{name: "amount asc", filter: DealListFilter{SortBy: "amount", Order: "asc"}, wantBy: "d.amount", wantOrd: "ASC"},
 		{name: "status desc", filter: DealListFilter{SortBy: "status", Order: "desc"}, wantBy: "COALESCE(d.status, 'new')", wantOrd: "DESC"},
 		{name: "client name", filter: DealListFilter{SortBy: "client_name", Order: "asc"}, wantBy: "LOWER(COALESCE(c.display_name, c.name, ''))", wantOrd: "ASC"},
+		{name: "updated at", filter: DealListFilter{SortBy: "updated_at", Order: "desc"}, wantBy: "d.updated_at", wantOrd: "DESC"},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -84,3 +86,36 @@ func TestDealSortExpression(t *testing.T) {
 }
 
 func contains(s, needle string) bool { return strings.Contains(s, needle) }
+
+func TestBuildWonAmountByOwnerQuery_OnlyWonDealsGroupedByOwner(t *testing.T) {
+	branchID := 4
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	query, args := buildWonAmountByOwnerQuery(from, from.AddDate(0, 1, 0), &branchID)
+	for _, p := range []string{"SUM(amount)", "status = 'won'", "branch_id = $3", "GROUP BY owner_id, currency"} {
+		if !strings.Contains(query, p) {
+			t.Fatalf("expected query to contain %q, got: %s", p, query)
+		}
+	}
+	if len(args) != 3 || args[2] != branchID {
+		t.Fatalf("unexpected args: %#v", args)
+	}
+
+	query, args = buildWonAmountByOwnerQuery(from, from.AddDate(0, 1, 0), nil)
+	if strings.Contains(query, "branch_id") || len(args) != 2 {
+		t.Fatalf("branch filter must be omitted when nil, got: %s %#v", query, args)
+	}
+}
+
+func TestBuildDealListWhere_OwnerWithStatusAndAmount(t *testing.T) {
+	owner := 42
+	amountMin := 1000.0
+	where, args := buildDealListWhere(DealListFilter{Status: "won", AmountMin: &amountMin, OwnerID: &owner}, 1)
+	for _, clause := range []string{"COALESCE(d.status, 'new') = $1", "d.amount >= $2", "d.owner_id = $3"} {
+		if !contains(where, clause) {
+			t.Fatalf("expected where clause to contain %q, got: %s", clause, where)
+		}
+	}
+	if !reflect.DeepEqual(args, []interface{}{"won", 1000.0, 42}) {
+		t.Fatalf("unexpected args: %#v", args)
+	}
+}
@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestDealRepository_SumWonAmountByOwner_CountsWonDealsOnly(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	branchID := 3
+	driverName := fmt.Sprintf("scripted-deal-revenue-%d", time.Now().UnixNano())
+	mockDriver := &scriptedDriver{steps: []scriptedStep{{
+		kind: "query",
+		query: "SELECT owner_id, SUM(amount) AS total_amount, currency FROM deals " +
+			"WHERE status = 'won' AND created_at BETWEEN $1 AND $2 AND branch_id = $3 " +
+			"GROUP BY owner_id, currency ORDER BY total_amount DESC, owner_id",
+		args:    []any{from, to, int64(branchID)},
+		columns: []string{"owner_id", "total_amount", "currency"},
+		rows: [][]driver.Value{
+			{int64(7), 350.5, "KZT"},
+			{int64(9), 300.0, "KZT"},
+		},
+	}}}
+	sql.Register(driverName, mockDriver)
+	db, err := sql.Open(driverName, "")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+
+	rows, err := NewDealRepository(db).SumWonAmountByOwner(context.Background(), from, to, &branchID)
+	if err != nil {
+		t.Fatalf("SumWonAmountByOwner: %v", err)
+	}
+	if len(rows) != 2 || rows[0].OwnerID != 7 || rows[0].TotalAmount != 350.5 || rows[0].Currency != "KZT" || rows[1].OwnerID != 9 {
+		t.Fatalf("unexpected rows: %+v", rows)
+	}
+	if !mockDriver.consumedAll() {
+		t.Fatal("expected the won-only revenue query to run")
+	}
+}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strings"
@@ -46,6 +47,7 @@ const documentBaseSelect = `
 	SELECT dcm.id, dcm.deal_id, dcm.client_id, dcm.branch_id, COALESCE(br.name,''), dcm.doc_type, dcm.file_path, dcm.file_path_docx, dcm.file_path_pdf, dcm.status,
 	       dcm.signed_at, dcm.created_at, COALESCE(dcm.sign_method,''), COALESCE(dcm.sign_ip,''),
 	       COALESCE(dcm.sign_user_agent,''), COALESCE(dcm.sign_metadata,''), COALESCE(dcm.signed_by,''),
+	       dcm.signed_by_user_id, COALESCE(dcm.signature_hash,''), dcm.reviewed_by_user_id, dcm.reviewed_at,
 	       dcm.is_archived, dcm.archived_at, dcm.archived_by, COALESCE(dcm.archive_reason,''),
 	       dcm.is_hidden, dcm.created_by,
 	       COALESCE(dcm.scope,'deal'), COALESCE(dcm.title,''), COALESCE(dcm.description,''), dcm.target_user_id
@@ -68,8 +70,9 @@ func scanDocument(scanner interface{ Scan(dest ...any) error }) (*models.Documen
 	var archivedBy, createdBy sql.NullInt64
 	var dealID, branchID, clientID sql.NullInt64
 	var branchName sql.NullString
-	var targetUserID sql.NullInt64
-	if err := scanner.Scan(&d.ID, &dealID, &clientID, &branchID, &branchName, &d.DocType, &d.FilePath, &d.FilePathDocx, &d.FilePathPdf, &d.Status, &signedAt, &createdAt, &d.SignMethod, &d.SignIP, &d.SignUserAgent, &d.SignMetadata, &d.SignedBy, &d.IsArchived, &archivedAt, &archivedBy, &d.ArchiveReason, &d.IsHidden, &createdBy, &d.Scope, &d.Title, &d.Description, &targetUserID); err != nil {
+	var targetUserID, signedByUserID, reviewedByUserID sql.NullInt64
+	var reviewedAt sql.NullTime
+	if err := scanner.Scan(&d.ID, &dealID, &clientID, &branchID, &branchName, &d.DocType, &d.FilePath, &d.FilePathDocx, &d.FilePathPdf, &d.Status, &signedAt, &createdAt, &d.SignMethod, &d.SignIP, &d.SignUserAgent, &d.SignMetadata, &d.SignedBy, &signedByUserID, &d.SignatureHash, &reviewedByUserID, &reviewedAt, &d.IsArchived, &archivedAt, &archivedBy, &d.ArchiveReason, &d.IsHidden, &createdBy, &d.Scope, &d.Title, &d.Description, &targetUserID); err != nil {
 		return nil, err
 	}
 	if dealID.Valid {
@@ -108,10 +111,22 @@ func scanDocument(scanner interface{ Scan(dest ...any) error }) (*models.Documen
 		v := targetUserID.Int64
 		d.TargetUserID = &v
 	}
+	if signedByUserID.Valid {
+		v := signedByUserID.Int64
+		d.SignedByUserID = &v
+	}
+	if reviewedByUserID.Valid {
+		v := reviewedByUserID.Int64
+		d.ReviewedByUserID = &v
+	}
+	if reviewedAt.Valid {
+		v := reviewedAt.Time
+		d.ReviewedAt = &v
+	}
 	return &d, nil
 }
 
-func (r *DocumentRepository) Create(doc *models.Document) (int64, error) {
+func (r *DocumentRepository) Create(ctx context.Context, doc *models.Document) (int64, error) {
 	scope := doc.Scope
 	if scope == "" {
 		scope = "deal"
@@ -123,7 +138,7 @@ func (r *DocumentRepository) Create(doc *models.Document) (int64, error) {
 	var id int64
 	var createdAt sql.NullTime
 	dealID := sql.NullInt64{Int64: doc.DealID, Valid: doc.DealID != 0}
-	if err := r.db.QueryRow(q, dealID, doc.ClientID, doc.BranchID, doc.DocType, doc.FilePath, doc.FilePathDocx, doc.FilePathPdf, doc.Status, doc.IsHidden, doc.CreatedBy, scope, doc.Title, doc.Description, doc.TargetUserID).Scan(&id, &createdAt); err != nil {
+	if err := r.db.QueryRowContext(ctx, q, dealID, doc.ClientID, doc.BranchID, doc.DocType, doc.FilePath, doc.FilePathDocx, doc.FilePathPdf, doc.Status, doc.IsHidden, doc.CreatedBy, scope, doc.Title, doc.Description, doc.TargetUserID).Scan(&id, &createdAt); err != nil {
 		return 0, fmt.Errorf("create document: %w", err)
 	}
 	doc.ID = id
@@ -133,15 +148,16 @@ func (r *DocumentRepository) Create(doc *models.Document) (int64, error) {
 	return id, nil
 }
 
-func (r *DocumentRepository) GetByID(id int64) (*models.Document, error) {
-	return r.GetByIDWithArchiveScope(id, ArchiveScopeActiveOnly)
+func (r *DocumentRepository) GetByID(ctx context.Context, id int64) (*models.Document, error) {
+	return r.GetByIDWithArchiveScope(ctx, id, ArchiveScopeActiveOnly)
 }
 
-func (r *DocumentRepository) GetByIDWithArchiveScope(id int64, scope ArchiveScope) (*models.Document, error) {
+func (r *DocumentRepository) GetByIDWithArchiveScope(ctx context.Context, id int64, scope ArchiveScope) (*models.Document, error) {
 	const q = `
 		SELECT id, deal_id, client_id, branch_id, doc_type, file_path, file_path_docx, file_path_pdf, status,
 		       signed_at, created_at, COALESCE(sign_method,''), COALESCE(sign_ip,''),
 		       COALESCE(sign_user_agent,''), COALESCE(sign_metadata,''), COALESCE(signed_by,''),
+		       signed_by_user_id, COALESCE(signature_hash,''), reviewed_by_user_id, reviewed_at,
 		       is_archived, archived_at, archived_by, COALESCE(archive_reason,''),
 		       is_hidden, created_by,
 		       COALESCE(scope,'deal'), COALESCE(title,''), COALESCE(description,''), target_user_id
@@ -149,11 +165,13 @@ func (r *DocumentRepository) GetByIDWithArchiveScope(id int64, scope ArchiveScop
 		WHERE id = $1 AND %s`
 	var d models.Document
 	var signedAt, createdAt, archivedAt sql.NullTime
-	var archivedBy, createdBy, targetUserID sql.NullInt64
+	var archivedBy, createdBy, targetUserID, signedByUserID, reviewedByUserID sql.NullInt64
+	var reviewedAt sql.NullTime
 	var dealID, branchID, clientID sql.NullInt64
-	err := r.db.QueryRow(fmt.Sprintf(q, documentArchiveWhere(scope)), id).Scan(
+	err := r.db.QueryRowContext(ctx, fmt.Sprintf(q, documentArchiveWhere(scope)), id).Scan(
 		&d.ID, &dealID, &clientID, &branchID, &d.DocType, &d.FilePath, &d.FilePathDocx, &d.FilePathPdf, &d.Status,
 		&signedAt, &createdAt, &d.SignMethod, &d.SignIP, &d.SignUserAgent, &d.SignMetadata, &d.SignedBy,
+		&signedByUserID, &d.SignatureHash, &reviewedByUserID, &reviewedAt,
 		&d.IsArchived, &archivedAt, &archivedBy, &d.ArchiveReason, &d.IsHidden, &createdBy,
 		&d.Scope, &d.Title, &d.Description, &targetUserID,
 	)
@@ -196,28 +214,40 @@ func (r *DocumentRepository) GetByIDWithArchiveScope(id int64, scope ArchiveScop
 		v := targetUserID.Int64
 		d.TargetUserID = &v
 	}
+	if signedByUserID.Valid {
+		v := signedByUserID.Int64
+		d.SignedByUserID = &v
+	}
+	if reviewedByUserID.Valid {
+		v := reviewedByUserID.Int64
+		d.ReviewedByUserID = &v
+	}
+	if reviewedAt.Valid {
+		v := reviewedAt.Time
+		d.ReviewedAt = &v
+	}
 	return &d, nil
 }
 
-func (r *DocumentRepository) Update(doc *models.Document) error {
+func (r *DocumentRepository) Update(ctx context.Context, doc *models.Document) error {
 	const q = `
 		UPDATE documents SET deal_id=$1, branch_id=$2, doc_type=$3, file_path=$4, file_path_docx=$5, file_path_pdf=$6, status=$7
 		WHERE id = $8`
-	if _, err := r.db.Exec(q, doc.DealID, doc.BranchID, doc.DocType, doc.FilePath, doc.FilePathDocx, doc.FilePathPdf, doc.Status, doc.ID); err != nil {
+	if _, err := r.db.ExecContext(ctx, q, doc.DealID, doc.BranchID, doc.DocType, doc.FilePath, doc.FilePathDocx, doc.FilePathPdf, doc.Status, doc.ID); err != nil {
 		return fmt.Errorf("update document: %w", err)
 	}
 	return nil
 }
 
-func (r *DocumentRepository) Delete(id int64) error {
-	if _, err := r.db.Exec(`DELETE FROM documents WHERE id = $1`, id); err != nil {
+func (r *DocumentRepository) Delete(ctx context.Context, id int64) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM documents WHERE id = $1`, id); err != nil {
 		return fmt.Errorf("delete document: %w", err)
 	}
 	return nil
 }
 
-func (r *DocumentRepository) Archive(id int64, archivedBy int, reason string) error {
-	_, err := r.db.Exec(`
+func (r *DocumentRepository) Archive(ctx context.Context, id int64, archivedBy int, reason string) error {
+	_, err := r.db.ExecContext(ctx, `
 		UPDATE documents
 		SET is_archived = TRUE,
 		    archived_at = NOW(),
@@ -231,8 +261,8 @@ func (r *DocumentRepository) Archive(id int64, archivedBy int, reason string) er
 	return nil
 }
 
-func (r *DocumentRepository) Unarchive(id int64) error {
-	_, err := r.db.Exec(`
+func (r *DocumentRepository) Unarchive(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `
 		UPDATE documents
 		SET is_archived = FALSE,
 		    archived_at = NULL,
@@ -246,21 +276,21 @@ func (r *DocumentRepository) Unarchive(id int64) error {
 	return nil
 }
 
-func (r *DocumentRepository) ListDocumentsByDeal(dealID int64) ([]*models.Document, error) {
-	return r.ListDocumentsByDealWithArchiveScope(dealID, ArchiveScopeActiveOnly)
+func (r *DocumentRepository) ListDocumentsByDeal(ctx context.Context, dealID int64) ([]*models.Document, error) {
+	return r.ListDocumentsByDealWithArchiveScope(ctx, dealID, ArchiveScopeActiveOnly)
 }
 
-func (r *DocumentRepository) ListDocumentsByDealWithArchiveScope(dealID int64, scope ArchiveScope) ([]*models.Document, error) {
+func (r *DocumentRepository) ListDocumentsByDealWithArchiveScope(ctx context.Context, dealID int64, scope ArchiveScope) ([]*models.Document, error) {
 	filter := DocumentListFilter{DealID: &dealID}
-	return r.ListDocumentsByDealWithFilterAndArchiveScope(dealID, filter, scope)
+	return r.ListDocumentsByDealWithFilterAndArchiveScope(ctx, dealID, filter, scope)
 }
 
-func (r *DocumentRepository) ListDocumentsByDealWithFilterAndArchiveScope(dealID int64, filter DocumentListFilter, scope ArchiveScope) ([]*models.Document, error) {
+func (r *DocumentRepository) ListDocumentsByDealWithFilterAndArchiveScope(ctx context.Context, dealID int64, filter DocumentListFilter, scope ArchiveScope) ([]*models.Document, error) {
 	filter.DealID = &dealID
 	where, args := buildDocumentListWhere(filter, scope, 1)
 	sortExpr, sortOrder := documentSortExpression(filter)
 	query := documentBaseSelect + fmt.Sprintf(" WHERE %s ORDER BY %s %s", where, sortExpr, sortOrder)
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("list by deal: %w", err)
 	}
@@ -276,9 +306,9 @@ func (r *DocumentRepository) ListDocumentsByDealWithFilterAndArchiveScope(dealID
 	return res, rows.Err()
 }
 
-func (r *DocumentRepository) ListDocumentsByDealWithFilterAndArchiveScopePaginated(dealID int64, limit, offset int, filter DocumentListFilter, scope ArchiveScope) ([]*models.Document, error) {
+func (r *DocumentRepository) ListDocumentsByDealWithFilterAndArchiveScopePaginated(ctx context.Context, dealID int64, limit, offset int, filter DocumentListFilter, scope ArchiveScope) ([]*models.Document, error) {
 	filter.DealID = &dealID
-	items, err := r.ListDocumentsWithFilterAndArchiveScope(limit, offset, filter, scope)
+	items, err := r.ListDocumentsWithFilterAndArchiveScope(ctx, limit, offset, filter, scope)
 	if err != nil {
 		return nil, err
 	}
@@ -288,40 +318,57 @@ func (r *DocumentRepository) ListDocumentsByDealWithFilterAndArchiveScopePaginat
 	return items, nil
 }
 
-func (r *DocumentRepository) UpdateStatus(id int64, status string) error {
+func (r *DocumentRepository) UpdateStatus(ctx context.Context, id int64, status string) error {
 	if status == "signed" {
-		if _, err := r.db.Exec(`UPDATE documents SET status = $1, signed_at = NOW() WHERE id = $2`, status, id); err != nil {
+		if _, err := r.db.ExecContext(ctx, `UPDATE documents SET status = $1, signed_at = NOW() WHERE id = $2`, status, id); err != nil {
 			return fmt.Errorf("update status: %w", err)
 		}
 		return nil
 	}
-	if _, err := r.db.Exec(`UPDATE documents SET status = $1 WHERE id = $2`, status, id); err != nil {
+	if _, err := r.db.ExecContext(ctx, `UPDATE documents SET status = $1 WHERE id = $2`, status, id); err != nil {
 		return fmt.Errorf("update status: %w", err)
 	}
 	return nil
 }
 
-func (r *DocumentRepository) MarkSigned(id int64, signedBy string, signedAt time.Time) error {
-	if _, err := r.db.Exec(`UPDATE documents SET status='signed', signed_at=$2, signed_by=NULLIF($3,'') WHERE id=$1`, id, signedAt, signedBy); err != nil {
+func (r *DocumentRepository) MarkSigned(ctx context.Context, id int64, signedBy string, signedAt time.Time) error {
+	if _, err := r.db.ExecContext(ctx, `UPDATE documents SET status='signed', signed_at=$2, signed_by=NULLIF($3,'') WHERE id=$1`, id, signedAt, signedBy); err != nil {
 		return fmt.Errorf("mark signed: %w", err)
 	}
 	return nil
 }
 
-func (r *DocumentRepository) ListDocuments(limit, offset int) ([]*models.Document, error) {
-	return r.ListDocumentsWithArchiveScope(limit, offset, ArchiveScopeActiveOnly)
+// SetSignatureAudit records who marked the document signed and the hash of its
+// file at that moment.
+func (r *DocumentRepository) SetSignatureAudit(ctx context.Context, id int64, signedByUserID int, signatureHash string) error {
+	if _, err := r.db.ExecContext(ctx, `UPDATE documents SET signed_by_user_id=NULLIF($2,0), signature_hash=NULLIF($3,'') WHERE id=$1`, id, signedByUserID, signatureHash); err != nil {
+		return fmt.Errorf("set signature audit: %w", err)
+	}
+	return nil
+}
+
+// SetReviewAudit records who approved or returned the document and when.
+func (r *DocumentRepository) SetReviewAudit(ctx context.Context, id int64, reviewedByUserID int, reviewedAt time.Time) error {
+	if _, err := r.db.ExecContext(ctx, `UPDATE documents SET reviewed_by_user_id=NULLIF($2,0), reviewed_at=$3 WHERE id=$1`, id, reviewedByUserID, reviewedAt); err != nil {
+		return fmt.Errorf("set review audit: %w", err)
+	}
+	return nil
+}
+
+func (r *DocumentRepository) ListDocuments(ctx context.Context, limit, offset int) ([]*models.Document, error) {
+	return r.ListDocumentsWithArchiveScope(ctx, limit, offset, ArchiveScopeActiveOnly)
 }
 
-func (r *DocumentRepository) ListDocumentsWithArchiveScope(limit, offset int, scope ArchiveScope) ([]*models.Document, error) {
-	return r.ListDocumentsWithFilterAndArchiveScope(limit, offset, DocumentListFilter{}, scope)
+func (r *DocumentRepository) ListDocumentsWithArchiveScope(ctx context.Context, limit, offset int, scope ArchiveScope) ([]*models.Document, error) {
+	return r.ListDocumentsWithFilterAndArchiveScope(ctx, limit, offset, DocumentListFilter{}, scope)
 }
 
-func (r *DocumentRepository) ListDocumentsWithFilterAndArchiveScope(limit, offset int, filter DocumentListFilter, scope ArchiveScope) ([]*models.Document, error) {
+func (r *DocumentRepository) ListDocumentsWithFilterAndArchiveScope(ctx context.Context, limit, offset int, filter DocumentListFilter, scope ArchiveScope) ([]*models.Document, error) {
 	where, args := buildDocumentListWhere(filter, scope, 1)
 	sortExpr, sortOrder := documentSortExpression(filter)
 	args = append(args, limit, offset)
 	query := documentBaseSelect + fmt.Sprintf(" WHERE %s ORDER BY %s %s LIMIT $%d OFFSET $%d", where, sortExpr, sortOrder, len(args)-1, len(args))
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("list documents: %w", err)
 	}
@@ -337,11 +384,11 @@ func (r *DocumentRepository) ListDocumentsWithFilterAndArchiveScope(limit, offse
 	return res, rows.Err()
 }
 
-func (r *DocumentRepository) CountDocumentsWithFilterAndArchiveScope(filter DocumentListFilter, scope ArchiveScope) (int, error) {
+func (r *DocumentRepository) CountDocumentsWithFilterAndArchiveScope(ctx context.Context, filter DocumentListFilter, scope ArchiveScope) (int, error) {
 	where, args := buildDocumentListWhere(filter, scope, 1)
 	query := "SELECT COUNT(1) " + documentBaseFrom + fmt.Sprintf(" WHERE %s", where)
 	var total int
-	if err := r.db.QueryRow(query, args...).Scan(&total); err != nil {
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
 		return 0, fmt.Errorf("count documents: %w", err)
 	}
 	return total, nil
@@ -430,8 +477,8 @@ func documentSortExpression(filter DocumentListFilter) (string, string) {
 	}
 }
 
-func (r *DocumentRepository) UpdateSigningMeta(id int64, signMethod, signIP, signUserAgent, signMetadata string) error {
-	_, err := r.db.Exec(`
+func (r *DocumentRepository) UpdateSigningMeta(ctx context.Context, id int64, signMethod, signIP, signUserAgent, signMetadata string) error {
+	_, err := r.db.ExecContext(ctx, `
 		UPDATE documents
 		SET sign_method = NULLIF($1, ''), sign_ip = NULLIF($2, ''), sign_user_agent = NULLIF($3, ''), sign_metadata = NULLIF($4, '')
 		WHERE id = $5
@@ -443,14 +490,14 @@ func (r *DocumentRepository) UpdateSigningMeta(id int64, signMethod, signIP, sig
 }
 
 // ListByClientID returns documents for a client (via direct client_id column + deal client_id)
-func (r *DocumentRepository) ListByClientID(clientID int64, scope ArchiveScope) ([]*models.Document, error) {
+func (r *DocumentRepository) ListByClientID(ctx context.Context, clientID int64, scope ArchiveScope) ([]*models.Document, error) {
 	filter := DocumentListFilter{ClientID: &clientID}
-	return r.ListDocumentsWithFilterAndArchiveScope(1000, 0, filter, scope)
+	return r.ListDocumentsWithFilterAndArchiveScope(ctx, 1000, 0, filter, scope)
 }
 
 // SetClientID sets the client_id on a document
-func (r *DocumentRepository) SetClientID(docID, clientID int64) error {
-	_, err := r.db.Exec(`UPDATE documents SET client_id = $1 WHERE id = $2`, clientID, docID)
+func (r *DocumentRepository) SetClientID(ctx context.Context, docID, clientID int64) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE documents SET client_id = $1 WHERE id = $2`, clientID, docID)
 	if err != nil {
 		return fmt.Errorf("set document client_id: %w", err)
 	}
@@ -49,3 +49,38 @@ func TestDocumentSortExpressionWhitelist(t *testing.T) {
 		}
 	}
 }
+
+func TestBuildDocumentListWhere_StatusAndType(t *testing.T) {
+	tests := []struct {
+		name    string
+		f       DocumentListFilter
+		want    []string
+		notWant []string
+		args    []any
+	}{
+		{name: "status", f: DocumentListFilter{Status: "under_review"}, want: []string{"dcm.status = $1"}, notWant: []string{"dcm.doc_type ="}, args: []any{"under_review"}},
+		{name: "type", f: DocumentListFilter{DocType: "contract"}, want: []string{"dcm.doc_type = $1"}, notWant: []string{"dcm.status ="}, args: []any{"contract"}},
+		{name: "both", f: DocumentListFilter{Status: "under_review", DocType: "contract"}, want: []string{"dcm.status = $1", "dcm.doc_type = $2"}, args: []any{"under_review", "contract"}},
+	}
+	for _, tc := range tests {
+		where, args := buildDocumentListWhere(tc.f, ArchiveScopeActiveOnly, 1)
+		for _, s := range tc.want {
+			if !strings.Contains(where, s) {
+				t.Fatalf("%s: expected %q in where: %s", tc.name, s, where)
+			}
+		}
+		for _, s := range tc.notWant {
+			if strings.Contains(where, s) {
+				t.Fatalf("%s: unexpected %q in where: %s", tc.name, s, where)
+			}
+		}
+		if len(args) != len(tc.args) {
+			t.Fatalf("%s: unexpected args: %#v", tc.name, args)
+		}
+		for i := range args {
+			if args[i] != tc.args[i] {
+				t.Fatalf("%s: unexpected args: %#v", tc.name, args)
+			}
+		}
+	}
+}
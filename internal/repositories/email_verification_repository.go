@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"database/sql"
+	"time"
+
+	"turcompany/internal/models"
+)
+
+type EmailVerificationRepository interface {
+	Create(userID int, tokenHash string, expiresAt time.Time) error
+	GetByTokenHash(tokenHash string) (*models.EmailVerification, error)
+	// Confirm marks the token used and stamps users.email_verified_at in one
+	// transaction.
+	Confirm(id int64, userID int, at time.Time) error
+}
+
+type emailVerificationRepository struct {
+	DB *sql.DB
+}
+
+func NewEmailVerificationRepository(db *sql.DB) EmailVerificationRepository {
+	return &emailVerificationRepository{DB: db}
+}
+
+func (r *emailVerificationRepository) Create(userID int, tokenHash string, expiresAt time.Time) error {
+	const q = `
+INSERT INTO email_verifications (user_id, token_hash, expires_at)
+VALUES ($1, $2, $3)
+`
+	_, err := r.DB.Exec(q, userID, tokenHash, expiresAt)
+	return err
+}
+
+func (r *emailVerificationRepository) GetByTokenHash(tokenHash string) (*models.EmailVerification, error) {
+	const q = `
+SELECT id, user_id, token_hash, expires_at, used_at, created_at
+FROM email_verifications
+WHERE token_hash = $1
+`
+	ev := &models.EmailVerification{}
+	var usedAt sql.NullTime
+	if err := r.DB.QueryRow(q, tokenHash).Scan(&ev.ID, &ev.UserID, &ev.TokenHash, &ev.ExpiresAt, &usedAt, &ev.CreatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if usedAt.Valid {
+		t := usedAt.Time
+		ev.UsedAt = &t
+	}
+	return ev, nil
+}
+
+func (r *emailVerificationRepository) Confirm(id int64, userID int, at time.Time) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`UPDATE email_verifications SET used_at = $2 WHERE id = $1 AND used_at IS NULL`, id, at)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+	if _, err := tx.Exec(`UPDATE users SET email_verified_at = COALESCE(email_verified_at, $2) WHERE id = $1`, userID, at); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -2,9 +2,19 @@ package repositories
 
 import "errors"
 
+// ErrNotFound is returned by lookups that have no nil-result form (users).
+// Single-row GetByID-style lookups of other entities return (nil, nil) when
+// the row is missing; any non-nil error from them is a storage failure.
+var ErrNotFound = errors.New("not found")
+
 var (
-	ErrDealAlreadyExists  = errors.New("deal already exists")
-	ErrClientNotFound     = errors.New("client not found")
-	ErrClientFileNotFound = errors.New("client file not found")
-	ErrAuditSchemaMissing = errors.New("audit_logs table is missing")
+	ErrDealAlreadyExists   = errors.New("deal already exists")
+	ErrClientNotFound      = errors.New("client not found")
+	ErrClientFileNotFound  = errors.New("client file not found")
+	ErrAuditSchemaMissing  = errors.New("audit_logs table is missing")
+	ErrLeadNotConvertible  = errors.New("lead is not in a convertible status")
+	ErrTaskVersionConflict = errors.New("task was modified by someone else")
+	ErrLeadNotFound        = errors.New("lead not found")
+	ErrClientTypeRequired  = errors.New("client_type is required")
+	ErrClientTypeMismatch  = errors.New("client_type does not match stored client type")
 )
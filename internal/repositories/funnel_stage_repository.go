@@ -129,7 +129,7 @@ func (r *FunnelStageRepository) Delete(id int, reassignToStageID *int) error {
 			err = ErrStageHasDeals
 			return err
 		}
-		if _, err = tx.Exec(`UPDATE deals SET stage_id = $1 WHERE stage_id = $2`, *reassignToStageID, id); err != nil {
+		if _, err = tx.Exec(`UPDATE deals SET stage_id = $1, updated_at = NOW() WHERE stage_id = $2`, *reassignToStageID, id); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"turcompany/internal/models"
+)
+
+type IdempotencyRepository interface {
+	// Reserve claims (userID, scope, key). It returns claimed=true when the
+	// caller owns the key (new, expired, or abandoned in flight before
+	// staleBefore); otherwise it returns the stored record.
+	Reserve(ctx context.Context, userID int, scope, key string, now, staleBefore, expiresAt time.Time) (rec *models.IdempotencyKey, claimed bool, err error)
+	Complete(ctx context.Context, id int64, status int, body []byte) error
+	Release(ctx context.Context, id int64) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type idempotencyRepository struct {
+	DB *sql.DB
+}
+
+func NewIdempotencyRepository(db *sql.DB) IdempotencyRepository {
+	return &idempotencyRepository{DB: db}
+}
+
+func (r *idempotencyRepository) Reserve(ctx context.Context, userID int, scope, key string, now, staleBefore, expiresAt time.Time) (*models.IdempotencyKey, bool, error) {
+	// An expired row (or one whose request died before completing) is taken
+	// over in place so the unique constraint never blocks a fresh request.
+	const claimQ = `
+INSERT INTO idempotency_keys (user_id, scope, idem_key, created_at, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id, scope, idem_key) DO UPDATE
+SET response_status = NULL,
+    response_body = NULL,
+    created_at = EXCLUDED.created_at,
+    expires_at = EXCLUDED.expires_at
+WHERE idempotency_keys.expires_at <= $4
+   OR (idempotency_keys.response_status IS NULL AND idempotency_keys.created_at <= $6)
+RETURNING id, created_at, expires_at
+`
+	rec := &models.IdempotencyKey{UserID: userID, Scope: scope, Key: key}
+	err := r.DB.QueryRowContext(ctx, claimQ, userID, scope, key, now, expiresAt, staleBefore).Scan(&rec.ID, &rec.CreatedAt, &rec.ExpiresAt)
+	if err == nil {
+		return rec, true, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, false, err
+	}
+
+	const getQ = `
+SELECT id, response_status, response_body, created_at, expires_at
+FROM idempotency_keys
+WHERE user_id = $1 AND scope = $2 AND idem_key = $3
+`
+	var status sql.NullInt64
+	if err := r.DB.QueryRowContext(ctx, getQ, userID, scope, key).Scan(&rec.ID, &status, &rec.ResponseBody, &rec.CreatedAt, &rec.ExpiresAt); err != nil {
+		return nil, false, err
+	}
+	if status.Valid {
+		rec.ResponseStatus = int(status.Int64)
+	}
+	return rec, false, nil
+}
+
+func (r *idempotencyRepository) Complete(ctx context.Context, id int64, status int, body []byte) error {
+	_, err := r.DB.ExecContext(ctx, `UPDATE idempotency_keys SET response_status = $2, response_body = $3 WHERE id = $1`, id, status, body)
+	return err
+}
+
+func (r *idempotencyRepository) Release(ctx context.Context, id int64) error {
+	_, err := r.DB.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE id = $1 AND response_status IS NULL`, id)
+	return err
+}
+
+func (r *idempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= $1`, now)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -37,6 +37,9 @@ type LeadListFilter struct {
 	// ScopeUserID, when set alongside DepartmentID, widens the department filter so
 	// the owner still sees their own NULL-department leads (fail-closed for peers).
 	ScopeUserID *int
+	// After switches the list to keyset paging: only leads after this
+	// (created_at, id) position, ordered by created_at.
+	After *ListCursor
 }
 
 type ArchiveScope string
@@ -80,6 +83,7 @@ func scanLead(scanner leadRowScanner) (*models.Leads, error) {
 	var archivedAt sql.NullTime
 	var archivedBy sql.NullInt64
 	var archiveReason sql.NullString
+	var score sql.NullInt64
 
 	if err := scanner.Scan(
 		&lead.ID,
@@ -88,6 +92,7 @@ func scanLead(scanner leadRowScanner) (*models.Leads, error) {
 		&phone,
 		&source,
 		&lead.CreatedAt,
+		&lead.UpdatedAt,
 		&ownerID,
 		&branchID,
 		&branchName,
@@ -98,9 +103,14 @@ func scanLead(scanner leadRowScanner) (*models.Leads, error) {
 		&archivedAt,
 		&archivedBy,
 		&archiveReason,
+		&score,
 	); err != nil {
 		return nil, err
 	}
+	if score.Valid {
+		v := int(score.Int64)
+		lead.Score = &v
+	}
 
 	lead.Description = stringFromNull(description)
 	lead.Phone = stringFromNull(phone)
@@ -148,21 +158,22 @@ func leadArchiveWhere(scope ArchiveScope) string {
 	}
 }
 
-// Создание лида с возвратом ID + created_at из БД
-func (r *LeadRepository) Create(lead *models.Leads) (int64, error) {
+// Создание лида с возвратом ID + created_at/updated_at из БД
+func (r *LeadRepository) Create(ctx context.Context, lead *models.Leads) (int64, error) {
 	const query = `
-		INSERT INTO leads (title, description, phone, source, owner_id, branch_id, funnel_id, status, department_id)
+		INSERT INTO leads (title, description, phone, source, owner_id, branch_id, funnel_id, status, department_id, score)
 		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), $5, $6, $7, $8,
 			COALESCE(
 				(SELECT f.department_id FROM funnels f WHERE f.id = $7),
 				(SELECT u.department_id FROM users u WHERE u.id = $5)
-			)
+			),
+			$9
 		)
-		RETURNING id, created_at
+		RETURNING id, created_at, updated_at
 	`
 
 	var id int64
-	err := r.db.QueryRow(
+	err := r.db.QueryRowContext(ctx,
 		query,
 		lead.Title,
 		lead.Description,
@@ -172,15 +183,16 @@ func (r *LeadRepository) Create(lead *models.Leads) (int64, error) {
 		lead.BranchID,
 		lead.FunnelID,
 		lead.Status,
-	).Scan(&id, &lead.CreatedAt)
+		lead.Score,
+	).Scan(&id, &lead.CreatedAt, &lead.UpdatedAt)
 	if err != nil {
 		return 0, fmt.Errorf("create lead: %w", err)
 	}
 	return id, nil
 }
 
-// Обновление лида БЕЗ изменения created_at
-func (r *LeadRepository) Update(lead *models.Leads) error {
+// Обновление лида БЕЗ изменения created_at; updated_at выставляется в NOW()
+func (r *LeadRepository) Update(ctx context.Context, lead *models.Leads) error {
 	const query = `
 		UPDATE leads
 		SET title = $1,
@@ -189,10 +201,12 @@ func (r *LeadRepository) Update(lead *models.Leads) error {
 		    source = NULLIF($4, ''),
 		    owner_id = $5,
 		    branch_id = $6,
-		    status = $7
+		    score = $9,
+		    status = $7,
+		    updated_at = NOW()
 		WHERE id = $8
 	`
-	_, err := r.db.Exec(
+	_, err := r.db.ExecContext(ctx,
 		query,
 		lead.Title,
 		lead.Description,
@@ -202,6 +216,7 @@ func (r *LeadRepository) Update(lead *models.Leads) error {
 		lead.BranchID,
 		lead.Status,
 		lead.ID,
+		lead.Score,
 	)
 	if err != nil {
 		return fmt.Errorf("update lead: %w", err)
@@ -210,16 +225,16 @@ func (r *LeadRepository) Update(lead *models.Leads) error {
 }
 
 // GetByID: корректно обрабатывает отсутствие строки
-func (r *LeadRepository) GetByID(id int) (*models.Leads, error) {
-	return r.GetByIDWithArchiveScope(id, ArchiveScopeActiveOnly)
+func (r *LeadRepository) GetByID(ctx context.Context, id int) (*models.Leads, error) {
+	return r.GetByIDWithArchiveScope(ctx, id, ArchiveScopeActiveOnly)
 }
 
-func (r *LeadRepository) GetByIDWithArchiveScope(id int, scope ArchiveScope) (*models.Leads, error) {
+func (r *LeadRepository) GetByIDWithArchiveScope(ctx context.Context, id int, scope ArchiveScope) (*models.Leads, error) {
 	const query = `
-		SELECT l.id, l.title, l.description, l.phone, l.source, l.created_at, l.owner_id, l.branch_id, COALESCE(b.name,''), l.department_id, l.funnel_id, l.status, l.is_archived, l.archived_at, l.archived_by, l.archive_reason FROM leads l LEFT JOIN branches b ON b.id=l.branch_id
+		SELECT l.id, l.title, l.description, l.phone, l.source, l.created_at, l.updated_at, l.owner_id, l.branch_id, COALESCE(b.name,''), l.department_id, l.funnel_id, l.status, l.is_archived, l.archived_at, l.archived_by, l.archive_reason, l.score FROM leads l LEFT JOIN branches b ON b.id=l.branch_id
 		WHERE l.id = $1 AND %s
 	`
-	row := r.db.QueryRow(fmt.Sprintf(query, leadArchiveWhere(scope)), id)
+	row := r.db.QueryRowContext(ctx, fmt.Sprintf(query, leadArchiveWhere(scope)), id)
 	lead, err := scanLead(row)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -230,57 +245,59 @@ func (r *LeadRepository) GetByIDWithArchiveScope(id int, scope ArchiveScope) (*m
 	return lead, nil
 }
 
-func (r *LeadRepository) Delete(id int) error {
+func (r *LeadRepository) Delete(ctx context.Context, id int) error {
 	const query = `DELETE FROM leads WHERE id=$1`
-	_, err := r.db.Exec(query, id)
+	_, err := r.db.ExecContext(ctx, query, id)
 	return err
 }
 
-func (r *LeadRepository) Archive(id, archivedBy int, reason string) error {
+func (r *LeadRepository) Archive(ctx context.Context, id, archivedBy int, reason string) error {
 	const query = `
 		UPDATE leads
 		SET is_archived = TRUE,
 		    archived_at = NOW(),
 		    archived_by = $2,
-		    archive_reason = $3
+		    archive_reason = $3,
+		    updated_at = NOW()
 		WHERE id = $1
 	`
-	_, err := r.db.Exec(query, id, archivedBy, reason)
+	_, err := r.db.ExecContext(ctx, query, id, archivedBy, reason)
 	return err
 }
 
-func (r *LeadRepository) Unarchive(id int) error {
+func (r *LeadRepository) Unarchive(ctx context.Context, id int) error {
 	const query = `
 		UPDATE leads
 		SET is_archived = FALSE,
 		    archived_at = NULL,
 		    archived_by = NULL,
-		    archive_reason = NULL
+		    archive_reason = NULL,
+		    updated_at = NOW()
 		WHERE id = $1
 	`
-	_, err := r.db.Exec(query, id)
+	_, err := r.db.ExecContext(ctx, query, id)
 	return err
 }
 
-func (r *LeadRepository) CountLeads() (int, error) {
+func (r *LeadRepository) CountLeads(ctx context.Context) (int, error) {
 	var count int
-	err := r.db.QueryRow(`SELECT COUNT(*) FROM leads WHERE is_archived = FALSE`).Scan(&count)
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM leads WHERE is_archived = FALSE`).Scan(&count)
 	return count, err
 }
 
-func (r *LeadRepository) FilterLeads(status string, ownerID int, sortBy, order string, limit, offset int) ([]models.Leads, error) {
+func (r *LeadRepository) FilterLeads(ctx context.Context, status string, ownerID int, sortBy, order string, limit, offset int) ([]models.Leads, error) {
 	if sortBy == "" {
 		sortBy = "created_at"
 	}
 	if order != "asc" && order != "desc" {
 		order = "desc"
 	}
-	allowed := map[string]bool{"created_at": true, "owner_id": true, "status": true}
+	allowed := map[string]bool{"created_at": true, "updated_at": true, "owner_id": true, "status": true}
 	if !allowed[sortBy] {
 		sortBy = "created_at"
 	}
 
-	query := "SELECT l.id, l.title, l.description, l.phone, l.source, l.created_at, l.owner_id, l.branch_id, COALESCE(b.name,''), l.department_id, l.funnel_id, l.status, l.is_archived, l.archived_at, l.archived_by, l.archive_reason FROM leads l LEFT JOIN branches b ON b.id=l.branch_id WHERE l.is_archived = FALSE"
+	query := "SELECT l.id, l.title, l.description, l.phone, l.source, l.created_at, l.updated_at, l.owner_id, l.branch_id, COALESCE(b.name,''), l.department_id, l.funnel_id, l.status, l.is_archived, l.archived_at, l.archived_by, l.archive_reason, l.score FROM leads l LEFT JOIN branches b ON b.id=l.branch_id WHERE l.is_archived = FALSE"
 	args := []interface{}{}
 	i := 1
 
@@ -298,7 +315,7 @@ func (r *LeadRepository) FilterLeads(status string, ownerID int, sortBy, order s
 	query += fmt.Sprintf(" ORDER BY %s %s LIMIT $%d OFFSET $%d", sortBy, order, i, i+1)
 	args = append(args, limit, offset)
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -315,32 +332,30 @@ func (r *LeadRepository) FilterLeads(status string, ownerID int, sortBy, order s
 	return out, nil
 }
 
-func (r *LeadRepository) ListAll(limit, offset int) ([]*models.Leads, error) {
-	return r.ListAllWithFilterAndArchiveScope(limit, offset, LeadListFilter{}, ArchiveScopeActiveOnly)
+func (r *LeadRepository) ListAll(ctx context.Context, limit, offset int) ([]*models.Leads, error) {
+	return r.ListAllWithFilterAndArchiveScope(ctx, limit, offset, LeadListFilter{}, ArchiveScopeActiveOnly)
 }
 
-func (r *LeadRepository) ListAllWithArchiveScope(limit, offset int, scope ArchiveScope) ([]*models.Leads, error) {
-	return r.ListAllWithFilterAndArchiveScope(limit, offset, LeadListFilter{}, scope)
+func (r *LeadRepository) ListAllWithArchiveScope(ctx context.Context, limit, offset int, scope ArchiveScope) ([]*models.Leads, error) {
+	return r.ListAllWithFilterAndArchiveScope(ctx, limit, offset, LeadListFilter{}, scope)
 }
 
-func (r *LeadRepository) ListAllWithFilterAndArchiveScope(limit, offset int, filter LeadListFilter, scope ArchiveScope) ([]*models.Leads, error) {
+func (r *LeadRepository) ListAllWithFilterAndArchiveScope(ctx context.Context, limit, offset int, filter LeadListFilter, scope ArchiveScope) ([]*models.Leads, error) {
 	const query = `
-		SELECT l.id, l.title, l.description, l.phone, l.source, l.created_at, l.owner_id, l.branch_id, COALESCE(b.name,''), l.department_id, l.funnel_id, l.status, l.is_archived, l.archived_at, l.archived_by, l.archive_reason
+		SELECT l.id, l.title, l.description, l.phone, l.source, l.created_at, l.updated_at, l.owner_id, l.branch_id, COALESCE(b.name,''), l.department_id, l.funnel_id, l.status, l.is_archived, l.archived_at, l.archived_by, l.archive_reason, l.score
 		FROM leads l LEFT JOIN branches b ON b.id=l.branch_id
 		WHERE %s%s
-		ORDER BY %s %s
+		ORDER BY %s
 		LIMIT $%d OFFSET $%d
 	`
-	sortExpr, sortOrder := leadSortExpression(filter)
 	extraWhere, args := buildLeadListWhere(filter, 1)
 	args = append(args, limit, offset)
-	rows, err := r.db.Query(
+	rows, err := r.db.QueryContext(ctx,
 		fmt.Sprintf(
 			query,
 			leadArchiveWhere(scope),
 			extraWhere,
-			sortExpr,
-			sortOrder,
+			leadOrderBy(filter),
 			len(args)-1,
 			len(args),
 		),
@@ -362,38 +377,42 @@ func (r *LeadRepository) ListAllWithFilterAndArchiveScope(limit, offset int, fil
 	return out, nil
 }
 
-func (r *LeadRepository) ListPaginated(limit, offset int) ([]*models.Leads, error) {
-	return r.ListAll(limit, offset)
+func (r *LeadRepository) ListPaginated(ctx context.Context, limit, offset int) ([]*models.Leads, error) {
+	return r.ListAll(ctx, limit, offset)
+}
+
+// ListPaginatedAfter is the keyset variant of ListPaginated: up to limit
+// active rows after the cursor (nil = first page), newest first.
+func (r *LeadRepository) ListPaginatedAfter(ctx context.Context, after *ListCursor, limit int) ([]*models.Leads, error) {
+	return r.ListAllWithFilterAndArchiveScope(ctx, limit, 0, LeadListFilter{After: after}, ArchiveScopeActiveOnly)
 }
 
 // «Только мои» лиды
-func (r *LeadRepository) ListByOwner(ownerID, limit, offset int) ([]*models.Leads, error) {
-	return r.ListByOwnerWithFilterAndArchiveScope(ownerID, limit, offset, LeadListFilter{}, ArchiveScopeActiveOnly)
+func (r *LeadRepository) ListByOwner(ctx context.Context, ownerID, limit, offset int) ([]*models.Leads, error) {
+	return r.ListByOwnerWithFilterAndArchiveScope(ctx, ownerID, limit, offset, LeadListFilter{}, ArchiveScopeActiveOnly)
 }
 
-func (r *LeadRepository) ListByOwnerWithArchiveScope(ownerID, limit, offset int, scope ArchiveScope) ([]*models.Leads, error) {
-	return r.ListByOwnerWithFilterAndArchiveScope(ownerID, limit, offset, LeadListFilter{}, scope)
+func (r *LeadRepository) ListByOwnerWithArchiveScope(ctx context.Context, ownerID, limit, offset int, scope ArchiveScope) ([]*models.Leads, error) {
+	return r.ListByOwnerWithFilterAndArchiveScope(ctx, ownerID, limit, offset, LeadListFilter{}, scope)
 }
 
-func (r *LeadRepository) ListByOwnerWithFilterAndArchiveScope(ownerID, limit, offset int, filter LeadListFilter, scope ArchiveScope) ([]*models.Leads, error) {
+func (r *LeadRepository) ListByOwnerWithFilterAndArchiveScope(ctx context.Context, ownerID, limit, offset int, filter LeadListFilter, scope ArchiveScope) ([]*models.Leads, error) {
 	const query = `
-		SELECT l.id, l.title, l.description, l.phone, l.source, l.created_at, l.owner_id, l.branch_id, COALESCE(b.name,''), l.department_id, l.funnel_id, l.status, l.is_archived, l.archived_at, l.archived_by, l.archive_reason
+		SELECT l.id, l.title, l.description, l.phone, l.source, l.created_at, l.updated_at, l.owner_id, l.branch_id, COALESCE(b.name,''), l.department_id, l.funnel_id, l.status, l.is_archived, l.archived_at, l.archived_by, l.archive_reason, l.score
 		FROM leads l LEFT JOIN branches b ON b.id=l.branch_id
 		WHERE owner_id = $1 AND %s%s
-		ORDER BY %s %s
+		ORDER BY %s
 		LIMIT $%d OFFSET $%d
 	`
-	sortExpr, sortOrder := leadSortExpression(filter)
 	extraWhere, args := buildLeadListWhere(filter, 2)
 	args = append([]interface{}{ownerID}, args...)
 	args = append(args, limit, offset)
-	rows, err := r.db.Query(
+	rows, err := r.db.QueryContext(ctx,
 		fmt.Sprintf(
 			query,
 			leadArchiveWhere(scope),
 			extraWhere,
-			sortExpr,
-			sortOrder,
+			leadOrderBy(filter),
 			len(args)-1,
 			len(args),
 		),
@@ -415,22 +434,22 @@ func (r *LeadRepository) ListByOwnerWithFilterAndArchiveScope(ownerID, limit, of
 	return out, nil
 }
 
-func (r *LeadRepository) CountAllWithFilterAndArchiveScope(filter LeadListFilter, scope ArchiveScope) (int, error) {
+func (r *LeadRepository) CountAllWithFilterAndArchiveScope(ctx context.Context, filter LeadListFilter, scope ArchiveScope) (int, error) {
 	extraWhere, args := buildLeadListWhere(filter, 1)
 	query := fmt.Sprintf(`SELECT COUNT(1) FROM leads l WHERE %s%s`, leadArchiveWhere(scope), extraWhere)
 	var total int
-	if err := r.db.QueryRow(query, args...).Scan(&total); err != nil {
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
 		return 0, err
 	}
 	return total, nil
 }
 
-func (r *LeadRepository) CountByOwnerWithFilterAndArchiveScope(ownerID int, filter LeadListFilter, scope ArchiveScope) (int, error) {
+func (r *LeadRepository) CountByOwnerWithFilterAndArchiveScope(ctx context.Context, ownerID int, filter LeadListFilter, scope ArchiveScope) (int, error) {
 	extraWhere, args := buildLeadListWhere(filter, 2)
 	args = append([]interface{}{ownerID}, args...)
 	query := fmt.Sprintf(`SELECT COUNT(1) FROM leads l WHERE owner_id = $1 AND %s%s`, leadArchiveWhere(scope), extraWhere)
 	var total int
-	if err := r.db.QueryRow(query, args...).Scan(&total); err != nil {
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
 		return 0, err
 	}
 	return total, nil
@@ -469,7 +488,7 @@ func buildLeadListWhere(filter LeadListFilter, startAt int) (string, []interface
 		idx++
 	}
 	if filter.Source != "" {
-		where += fmt.Sprintf(" AND COALESCE(l.source, '') = $%d", idx)
+		where += fmt.Sprintf(" AND COALESCE(NULLIF(l.source, ''), 'unknown') = $%d", idx)
 		args = append(args, filter.Source)
 		idx++
 	}
@@ -487,6 +506,12 @@ func buildLeadListWhere(filter LeadListFilter, startAt int) (string, []interface
 			idx++
 		}
 	}
+	if filter.After != nil {
+		_, order := leadSortExpression(filter)
+		keyset, keysetArgs := keysetWhere("l", order, filter.After, idx)
+		where += keyset
+		args = append(args, keysetArgs...)
+	}
 
 	return where, args
 }
@@ -507,25 +532,46 @@ func leadSortExpression(filter LeadListFilter) (string, string) {
 	if strings.EqualFold(filter.Order, "asc") {
 		order = "ASC"
 	}
+	if filter.After != nil {
+		return "created_at", order
+	}
 	switch filter.SortBy {
 	case "status":
 		return "COALESCE(status, 'new')", order
 	case "title":
 		return "LOWER(COALESCE(title, ''))", order
+	case "updated_at":
+		return "updated_at", order
+	case "score":
+		// unscored leads rank below every score, so DESC lists them last
+		return "COALESCE(score, -1)", order
 	default:
 		return "created_at", order
 	}
 }
 
-func (r *LeadRepository) UpdateStatus(id int, status string) error {
-	const q = `UPDATE leads SET status = $1 WHERE id = $2`
-	_, err := r.db.Exec(q, status, id)
+// leadOrderBy is the ORDER BY of lead lists; id breaks ties so both offset
+// and keyset pages are stable.
+func leadOrderBy(filter LeadListFilter) string {
+	expr, order := leadSortExpression(filter)
+	return fmt.Sprintf("%s %s, l.id %s", expr, order, order)
+}
+
+func (r *LeadRepository) UpdateStatus(ctx context.Context, id int, status string) error {
+	const q = `UPDATE leads SET status = $1, updated_at = NOW() WHERE id = $2`
+	_, err := r.db.ExecContext(ctx, q, status, id)
+	return err
+}
+
+func (r *LeadRepository) UpdateScore(ctx context.Context, id, score int) error {
+	const q = `UPDATE leads SET score = $1, updated_at = NOW() WHERE id = $2`
+	_, err := r.db.ExecContext(ctx, q, score, id)
 	return err
 }
 
-func (r *LeadRepository) UpdateOwner(id, ownerID int) error {
-	const q = `UPDATE leads SET owner_id = $1 WHERE id = $2`
-	_, err := r.db.Exec(q, ownerID, id)
+func (r *LeadRepository) UpdateOwner(ctx context.Context, id, ownerID int) error {
+	const q = `UPDATE leads SET owner_id = $1, updated_at = NOW() WHERE id = $2`
+	_, err := r.db.ExecContext(ctx, q, ownerID, id)
 	return err
 }
 
@@ -565,28 +611,65 @@ func (r *LeadRepository) GetLeadsSummaryStats(ctx context.Context, from, to time
 	return result, nil
 }
 
+// GetLeadSourceStats counts the leads created in the period per source, and
+// how many of them were converted to a deal. Leads without a source fall into
+// the "unknown" bucket.
+func (r *LeadRepository) GetLeadSourceStats(ctx context.Context, from, to time.Time, ownerID *int, branchID *int) ([]models.LeadSourceRow, error) {
+	query := `SELECT COALESCE(NULLIF(source, ''), 'unknown') AS source, COUNT(*) AS total,
+		COUNT(*) FILTER (WHERE status = 'converted') AS converted
+		FROM leads WHERE created_at BETWEEN $1 AND $2`
+	args := []interface{}{from, to}
+	idx := 3
+
+	if ownerID != nil {
+		query += fmt.Sprintf(" AND owner_id = $%d", idx)
+		args = append(args, *ownerID)
+		idx++
+	}
+	if branchID != nil {
+		query += fmt.Sprintf(" AND branch_id = $%d", idx)
+		args = append(args, *branchID)
+	}
+
+	query += " GROUP BY 1 ORDER BY total DESC, source"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("lead source stats: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.LeadSourceRow
+	for rows.Next() {
+		var row models.LeadSourceRow
+		if err := rows.Scan(&row.Source, &row.Total, &row.Converted); err != nil {
+			return nil, fmt.Errorf("scan lead source row: %w", err)
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
 func (r *LeadRepository) ConvertToDeal(ctx context.Context, leadID int, deal *models.Deals, client *models.Client) (*models.Deals, error) {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("begin convert lead tx: %w", err)
 	}
-	defer func() {
-		if err != nil {
-			_ = tx.Rollback()
-		}
-	}()
+	// Every early return (including sentinel errors that don't go through err)
+	// must roll back; after Commit this is a no-op.
+	defer func() { _ = tx.Rollback() }()
 
 	var leadStatus sql.NullString
-	if err = tx.QueryRow(`SELECT status FROM leads WHERE id = $1 FOR UPDATE`, leadID).Scan(&leadStatus); err != nil {
+	if err = tx.QueryRowContext(ctx, `SELECT status FROM leads WHERE id = $1 FOR UPDATE`, leadID).Scan(&leadStatus); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("lead not found")
+			return nil, ErrLeadNotFound
 		}
 		return nil, fmt.Errorf("lock lead: %w", err)
 	}
 
 	loadClientType := func(clientID int) (string, error) {
 		var clientType sql.NullString
-		if err := tx.QueryRow(`SELECT client_type FROM clients WHERE id = $1`, clientID).Scan(&clientType); err != nil {
+		if err := tx.QueryRowContext(ctx, `SELECT client_type FROM clients WHERE id = $1`, clientID).Scan(&clientType); err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				return "", nil
 			}
@@ -598,7 +681,7 @@ func (r *LeadRepository) ConvertToDeal(ctx context.Context, leadID int, deal *mo
 	loadExistingDealForUpdate := func(leadID int) (*models.Deals, error) {
 		existing := &models.Deals{}
 		var status sql.NullString
-		err := tx.QueryRow(`
+		err := tx.QueryRowContext(ctx, `
 			SELECT d.id, d.lead_id, d.client_id, d.owner_id, d.amount, d.currency, d.status, d.created_at
 			FROM deals d
 			WHERE d.lead_id = $1
@@ -629,7 +712,7 @@ func (r *LeadRepository) ConvertToDeal(ctx context.Context, leadID int, deal *mo
 	existing, existingErr := loadExistingDealForUpdate(leadID)
 	if existingErr == nil {
 		if normalizeLeadStatus(leadStatus) != "converted" {
-			if _, err = tx.Exec(`UPDATE leads SET status = 'converted' WHERE id = $1`, leadID); err != nil {
+			if _, err = tx.ExecContext(ctx, `UPDATE leads SET status = 'converted', updated_at = NOW() WHERE id = $1`, leadID); err != nil {
 				return nil, fmt.Errorf("update lead status after existing deal: %w", err)
 			}
 		}
@@ -644,31 +727,59 @@ func (r *LeadRepository) ConvertToDeal(ctx context.Context, leadID int, deal *mo
 
 	leadStatusValue := normalizeLeadStatus(leadStatus)
 	if leadStatusValue != "confirmed" {
-		return nil, errors.New("lead is not in a convertible status")
+		return nil, ErrLeadNotConvertible
 	}
 
 	if client == nil {
 		return nil, errors.New("client data is required")
 	}
-	if client.ID == 0 {
-		return nil, errors.New("client data is required")
+	if strings.TrimSpace(client.ClientType) == "" {
+		return nil, ErrClientTypeRequired
 	}
 	var storedClientType string
-	if err = tx.QueryRow(`SELECT id, client_type FROM clients WHERE id = $1 FOR UPDATE`, client.ID).Scan(&deal.ClientID, &storedClientType); err != nil {
+	if client.ID == 0 {
+		// New client: created in the same transaction so a failed conversion
+		// never leaves an orphaned client behind. The savepoint lets a
+		// concurrent conversion that inserted the same BIN/IIN first win: our
+		// insert is undone and the conversion continues with that client.
+		if _, err = tx.ExecContext(ctx, `SAVEPOINT convert_new_client`); err != nil {
+			return nil, fmt.Errorf("savepoint new client: %w", err)
+		}
+		id, createErr := createClientTx(ctx, tx, client)
+		switch {
+		case createErr == nil:
+			deal.ClientID = int(id)
+			storedClientType = client.ClientType
+		case IsSQLState(createErr, SQLStateUniqueViolation):
+			if _, err = tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT convert_new_client`); err != nil {
+				return nil, fmt.Errorf("rollback to savepoint new client: %w", err)
+			}
+			client.ID = 0
+			existingID, existingType, lookupErr := lookupClientByIdentityTx(ctx, tx, client)
+			if lookupErr != nil {
+				return nil, lookupErr
+			}
+			if existingID == 0 {
+				return nil, createErr
+			}
+			client.ID = existingID
+			deal.ClientID = existingID
+			storedClientType = existingType
+		default:
+			return nil, createErr
+		}
+	} else if err = tx.QueryRowContext(ctx, `SELECT id, client_type FROM clients WHERE id = $1 FOR UPDATE`, client.ID).Scan(&deal.ClientID, &storedClientType); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrClientNotFound
 		}
 		return nil, fmt.Errorf("lookup client: %w", err)
 	}
-	if strings.TrimSpace(client.ClientType) == "" {
-		return nil, errors.New("client_type is required")
-	}
 	if strings.ToLower(strings.TrimSpace(client.ClientType)) != strings.ToLower(strings.TrimSpace(storedClientType)) {
-		return nil, errors.New("client_type does not match stored client type")
+		return nil, ErrClientTypeMismatch
 	}
 	deal.ClientType = strings.ToLower(strings.TrimSpace(storedClientType))
 
-	err = tx.QueryRow(`
+	err = tx.QueryRowContext(ctx, `
 		INSERT INTO deals (lead_id, client_id, owner_id, branch_id, amount, currency, status, created_at, department_id)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8,
 			COALESCE(
@@ -694,7 +805,7 @@ func (r *LeadRepository) ConvertToDeal(ctx context.Context, leadID int, deal *mo
 			if err != nil {
 				return nil, fmt.Errorf("fetch existing deal after conflict: %w", err)
 			}
-			if _, err = tx.Exec(`UPDATE leads SET status = 'converted' WHERE id = $1`, leadID); err != nil {
+			if _, err = tx.ExecContext(ctx, `UPDATE leads SET status = 'converted', updated_at = NOW() WHERE id = $1`, leadID); err != nil {
 				return nil, fmt.Errorf("update lead status after conflict: %w", err)
 			}
 			if err = tx.Commit(); err != nil {
@@ -705,7 +816,7 @@ func (r *LeadRepository) ConvertToDeal(ctx context.Context, leadID int, deal *mo
 		return nil, fmt.Errorf("insert deal: %w", err)
 	}
 
-	if _, err = tx.Exec(`UPDATE leads SET status = 'converted' WHERE id = $1`, leadID); err != nil {
+	if _, err = tx.ExecContext(ctx, `UPDATE leads SET status = 'converted', updated_at = NOW() WHERE id = $1`, leadID); err != nil {
 		return nil, fmt.Errorf("update lead status: %w", err)
 	}
 
@@ -13,17 +13,20 @@ import (
 	"testing"
 	"time"
 
+	"github.com/lib/pq"
+
 	"turcompany/internal/models"
 )
 
 type scriptedStep struct {
-	kind    string
-	query   string
-	args    []any
-	columns []string
-	rows    [][]driver.Value
-	result  driver.Result
-	err     error
+	kind     string
+	query    string
+	args     []any
+	skipArgs bool // don't check args (long INSERTs where only the statement matters)
+	columns  []string
+	rows     [][]driver.Value
+	result   driver.Result
+	err      error
 }
 
 type scriptedDriver struct {
@@ -82,8 +85,10 @@ func (c *scriptedConn) QueryContext(_ context.Context, query string, args []driv
 	if !strings.Contains(normalizeSpace(query), normalizeSpace(step.query)) {
 		return nil, fmt.Errorf("unexpected query: %q does not contain %q", query, step.query)
 	}
-	if err := assertArgs(args, step.args); err != nil {
-		return nil, err
+	if !step.skipArgs {
+		if err := assertArgs(args, step.args); err != nil {
+			return nil, err
+		}
 	}
 	if step.err != nil {
 		return nil, step.err
@@ -99,8 +104,10 @@ func (c *scriptedConn) ExecContext(_ context.Context, query string, args []drive
 	if !strings.Contains(normalizeSpace(query), normalizeSpace(step.query)) {
 		return nil, fmt.Errorf("unexpected exec query: %q does not contain %q", query, step.query)
 	}
-	if err := assertArgs(args, step.args); err != nil {
-		return nil, err
+	if !step.skipArgs {
+		if err := assertArgs(args, step.args); err != nil {
+			return nil, err
+		}
 	}
 	if step.err != nil {
 		return nil, step.err
@@ -232,7 +239,7 @@ func TestLeadRepository_ConvertToDeal_ExistingDealDoesNotUseOuterJoinForUpdate(t
 			},
 			{
 				kind:  "exec",
-				query: "UPDATE leads SET status = 'converted' WHERE id = $1",
+				query: "UPDATE leads SET status = 'converted', updated_at = NOW() WHERE id = $1",
 				args:  []any{int64(42)},
 			},
 			{kind: "commit"},
@@ -268,3 +275,196 @@ func TestLeadRepository_ConvertToDeal_ExistingDealDoesNotUseOuterJoinForUpdate(t
 		t.Fatalf("not all scripted steps were consumed: pos=%d total=%d", mockDriver.pos, len(mockDriver.steps))
 	}
 }
+
+func TestLeadRepository_ConvertToDeal_LeadUpdateFailureRollsBackClientAndDeal(t *testing.T) {
+	driverName := fmt.Sprintf("scripted-convert-rollback-%d", time.Now().UnixNano())
+	mockDriver := &scriptedDriver{
+		steps: []scriptedStep{
+			{kind: "begin"},
+			{
+				kind:    "query",
+				query:   "SELECT status FROM leads WHERE id = $1 FOR UPDATE",
+				args:    []any{int64(42)},
+				columns: []string{"status"},
+				rows:    [][]driver.Value{{"confirmed"}},
+			},
+			{
+				kind:  "query",
+				query: "FROM deals d WHERE d.lead_id = $1 ORDER BY d.created_at DESC LIMIT 1 FOR UPDATE",
+				args:  []any{int64(42)},
+				err:   sql.ErrNoRows,
+			},
+			{kind: "exec", query: "SAVEPOINT convert_new_client"},
+			{
+				kind:     "query",
+				query:    "INSERT INTO clients (owner_id, branch_id, client_type",
+				skipArgs: true,
+				columns:  []string{"id"},
+				rows:     [][]driver.Value{{int64(501)}},
+			},
+			{kind: "exec", query: "INSERT INTO client_individual_profiles", skipArgs: true},
+			{kind: "exec", query: "DELETE FROM client_legal_profiles WHERE client_id=$1", args: []any{int64(501)}},
+			{
+				kind:     "query",
+				query:    "INSERT INTO deals (lead_id, client_id, owner_id, branch_id, amount, currency, status, created_at, department_id)",
+				skipArgs: true,
+				columns:  []string{"id"},
+				rows:     [][]driver.Value{{int64(900)}},
+			},
+			{
+				kind:  "exec",
+				query: "UPDATE leads SET status = 'converted', updated_at = NOW() WHERE id = $1",
+				args:  []any{int64(42)},
+				err:   errors.New("forced lead update failure"),
+			},
+			{kind: "rollback"},
+		},
+	}
+	sql.Register(driverName, mockDriver)
+
+	db, err := sql.Open(driverName, "")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewLeadRepository(db)
+	deal := &models.Deals{LeadID: 42, OwnerID: 9, Amount: 100, Currency: "KZT", Status: "new"}
+	client := &models.Client{ClientType: models.ClientTypeIndividual, Name: "New Client", OwnerID: 9}
+
+	got, err := repo.ConvertToDeal(context.Background(), 42, deal, client)
+	if err == nil || !strings.Contains(err.Error(), "forced lead update failure") {
+		t.Fatalf("expected forced failure, got deal=%v err=%v", got, err)
+	}
+	if got != nil {
+		t.Fatalf("expected no deal on failure, got %+v", got)
+	}
+	// The scripted driver has no commit step: reaching the end with the
+	// rollback consumed proves the client and deal inserts were undone.
+	if !mockDriver.consumedAll() {
+		t.Fatalf("expected rollback after failure: pos=%d total=%d", mockDriver.pos, len(mockDriver.steps))
+	}
+}
+
+func TestLeadRepository_ConvertToDeal_StoredClientTypeChangedIsMismatch(t *testing.T) {
+	driverName := fmt.Sprintf("scripted-convert-type-mismatch-%d", time.Now().UnixNano())
+	mockDriver := &scriptedDriver{
+		steps: []scriptedStep{
+			{kind: "begin"},
+			{
+				kind:    "query",
+				query:   "SELECT status FROM leads WHERE id = $1 FOR UPDATE",
+				args:    []any{int64(42)},
+				columns: []string{"status"},
+				rows:    [][]driver.Value{{"confirmed"}},
+			},
+			{
+				kind:  "query",
+				query: "FROM deals d WHERE d.lead_id = $1 ORDER BY d.created_at DESC LIMIT 1 FOR UPDATE",
+				args:  []any{int64(42)},
+				err:   sql.ErrNoRows,
+			},
+			{
+				kind:    "query",
+				query:   "SELECT id, client_type FROM clients WHERE id = $1 FOR UPDATE",
+				args:    []any{int64(7)},
+				columns: []string{"id", "client_type"},
+				rows:    [][]driver.Value{{int64(7), "legal"}},
+			},
+			{kind: "rollback"},
+		},
+	}
+	sql.Register(driverName, mockDriver)
+
+	db, err := sql.Open(driverName, "")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewLeadRepository(db)
+	deal := &models.Deals{LeadID: 42, OwnerID: 9, Amount: 100, Currency: "KZT", Status: "new"}
+	client := &models.Client{ID: 7, ClientType: models.ClientTypeIndividual}
+
+	if _, err := repo.ConvertToDeal(context.Background(), 42, deal, client); !errors.Is(err, ErrClientTypeMismatch) {
+		t.Fatalf("expected ErrClientTypeMismatch, got %v", err)
+	}
+	if !mockDriver.consumedAll() {
+		t.Fatalf("expected rollback after mismatch: pos=%d total=%d", mockDriver.pos, len(mockDriver.steps))
+	}
+}
+
+func TestLeadRepository_ConvertToDeal_ConcurrentClientInsertReusesWinner(t *testing.T) {
+	driverName := fmt.Sprintf("scripted-convert-bin-race-%d", time.Now().UnixNano())
+	mockDriver := &scriptedDriver{
+		steps: []scriptedStep{
+			{kind: "begin"},
+			{
+				kind:    "query",
+				query:   "SELECT status FROM leads WHERE id = $1 FOR UPDATE",
+				args:    []any{int64(42)},
+				columns: []string{"status"},
+				rows:    [][]driver.Value{{"confirmed"}},
+			},
+			{
+				kind:  "query",
+				query: "FROM deals d WHERE d.lead_id = $1 ORDER BY d.created_at DESC LIMIT 1 FOR UPDATE",
+				args:  []any{int64(42)},
+				err:   sql.ErrNoRows,
+			},
+			{kind: "exec", query: "SAVEPOINT convert_new_client"},
+			{
+				kind:     "query",
+				query:    "INSERT INTO clients (owner_id, branch_id, client_type",
+				skipArgs: true,
+				err:      &pq.Error{Code: SQLStateUniqueViolation, Constraint: "clients_bin_iin_unique_idx"},
+			},
+			{kind: "exec", query: "ROLLBACK TO SAVEPOINT convert_new_client"},
+			{
+				kind:    "query",
+				query:   "WHERE ($1 <> '' AND COALESCE(lp.bin, c.bin_iin) = $1) OR ($2 <> '' AND ip.iin = $2)",
+				args:    []any{"123456789012", ""},
+				columns: []string{"id", "client_type"},
+				rows:    [][]driver.Value{{int64(77), "legal"}},
+			},
+			{
+				kind:     "query",
+				query:    "INSERT INTO deals (lead_id, client_id, owner_id, branch_id, amount, currency, status, created_at, department_id)",
+				skipArgs: true,
+				columns:  []string{"id"},
+				rows:     [][]driver.Value{{int64(900)}},
+			},
+			{
+				kind:  "exec",
+				query: "UPDATE leads SET status = 'converted', updated_at = NOW() WHERE id = $1",
+				args:  []any{int64(42)},
+			},
+			{kind: "commit"},
+		},
+	}
+	sql.Register(driverName, mockDriver)
+
+	db, err := sql.Open(driverName, "")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewLeadRepository(db)
+	deal := &models.Deals{LeadID: 42, OwnerID: 9, Amount: 100, Currency: "KZT", Status: "new"}
+	client := &models.Client{ClientType: models.ClientTypeLegal, Name: "ТОО Ромашка", BinIin: "123456789012", OwnerID: 9}
+
+	got, err := repo.ConvertToDeal(context.Background(), 42, deal, client)
+	if err != nil {
+		t.Fatalf("ConvertToDeal: %v", err)
+	}
+	if got.ID != 900 || got.ClientID != 77 || got.ClientType != "legal" {
+		t.Fatalf("expected deal 900 on the existing client 77, got %+v", got)
+	}
+	if client.ID != 77 {
+		t.Fatalf("expected client to point at the existing row, got id %d", client.ID)
+	}
+	if !mockDriver.consumedAll() {
+		t.Fatalf("not all steps consumed: pos=%d total=%d", mockDriver.pos, len(mockDriver.steps))
+	}
+}
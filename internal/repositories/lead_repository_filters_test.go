@@ -131,6 +131,8 @@ func TestLeadSortExpressionWhitelist(t *testing.T) {
 		{filter: LeadListFilter{}, wantBy: "created_at", wantOrd: "DESC"},
 		{filter: LeadListFilter{SortBy: "status", Order: "asc"}, wantBy: "COALESCE(status, 'new')", wantOrd: "ASC"},
 		{filter: LeadListFilter{SortBy: "title", Order: "desc"}, wantBy: "LOWER(COALESCE(title, ''))", wantOrd: "DESC"},
+		{filter: LeadListFilter{SortBy: "updated_at", Order: "desc"}, wantBy: "updated_at", wantOrd: "DESC"},
+		{filter: LeadListFilter{SortBy: "score"}, wantBy: "COALESCE(score, -1)", wantOrd: "DESC"},
 	}
 	for _, tc := range tests {
 		gotBy, gotOrd := leadSortExpression(tc.filter)
@@ -135,7 +135,7 @@ func TestListAllWithFilter_QueryOnly_UsesValidPlaceholders(t *testing.T) {
 	repo := NewLeadRepository(db)
 	filter := LeadListFilter{Query: "smoke"}
 
-	leads, err := repo.ListAllWithFilterAndArchiveScope(5, 0, filter, ArchiveScopeActiveOnly)
+	leads, err := repo.ListAllWithFilterAndArchiveScope(context.Background(), 5, 0, filter, ArchiveScopeActiveOnly)
 	if err != nil {
 		t.Fatalf("ListAllWithFilterAndArchiveScope returned error: %v", err)
 	}
@@ -156,7 +156,7 @@ func toInt(v any) (int, bool) {
 }
 
 func (r *leadListRegressionRows) Columns() []string {
-	return []string{"id", "title", "description", "phone", "source", "created_at", "owner_id", "branch_id", "branch_name", "department_id", "funnel_id", "status", "is_archived", "archived_at", "archived_by", "archive_reason"}
+	return []string{"id", "title", "description", "phone", "source", "created_at", "updated_at", "owner_id", "branch_id", "branch_name", "department_id", "funnel_id", "status", "is_archived", "archived_at", "archived_by", "archive_reason", "score"}
 }
 
 func (r *leadListRegressionRows) Close() error { return nil }
@@ -167,7 +167,7 @@ func (r *leadListRegressionRows) Next(dest []driver.Value) error {
 	}
 	r.done = true
 	now := time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC)
-	row := []driver.Value{1, "t", "d", "7700", "web", now, 77, 1, "Main", nil, nil, "new", false, nil, nil, ""}
+	row := []driver.Value{1, "t", "d", "7700", "web", now, now, 77, 1, "Main", nil, nil, "new", false, nil, nil, "", nil}
 	for i := range dest {
 		dest[i] = row[i]
 	}
@@ -191,7 +191,7 @@ func TestListAllWithFilter_StatusGroupQueryBranch_UsesValidPlaceholders(t *testi
 		BranchID:    &branchID,
 	}
 
-	leads, err := repo.ListAllWithFilterAndArchiveScope(5, 0, filter, ArchiveScopeActiveOnly)
+	leads, err := repo.ListAllWithFilterAndArchiveScope(context.Background(), 5, 0, filter, ArchiveScopeActiveOnly)
 	if err != nil {
 		t.Fatalf("ListAllWithFilterAndArchiveScope returned error: %v", err)
 	}
@@ -216,7 +216,7 @@ func TestListByOwnerWithFilter_QueryBranch_UsesValidPlaceholders(t *testing.T) {
 		BranchID: &branchID,
 	}
 
-	leads, err := repo.ListByOwnerWithFilterAndArchiveScope(77, 5, 0, filter, ArchiveScopeActiveOnly)
+	leads, err := repo.ListByOwnerWithFilterAndArchiveScope(context.Background(), 77, 5, 0, filter, ArchiveScopeActiveOnly)
 	if err != nil {
 		t.Fatalf("ListByOwnerWithFilterAndArchiveScope returned error: %v", err)
 	}
@@ -242,7 +242,7 @@ func TestListByOwnerWithFilter_StatusGroupQueryBranch_UsesValidPlaceholders(t *t
 		BranchID:    &branchID,
 	}
 
-	leads, err := repo.ListByOwnerWithFilterAndArchiveScope(77, 5, 0, filter, ArchiveScopeActiveOnly)
+	leads, err := repo.ListByOwnerWithFilterAndArchiveScope(context.Background(), 77, 5, 0, filter, ArchiveScopeActiveOnly)
 	if err != nil {
 		t.Fatalf("ListByOwnerWithFilterAndArchiveScope returned error: %v", err)
 	}
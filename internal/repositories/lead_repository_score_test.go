@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestLeadRepository_ListByScore_HottestFirstUnscoredLast(t *testing.T) {
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	lead := func(id int64, score any) []driver.Value {
+		return []driver.Value{id, "lead", "", nil, "web", now, now, int64(7), nil, "", nil, nil, "new", false, nil, nil, nil, score}
+	}
+	driverName := fmt.Sprintf("scripted-lead-score-%d", time.Now().UnixNano())
+	mockDriver := &scriptedDriver{steps: []scriptedStep{{
+		kind:     "query",
+		query:    "ORDER BY COALESCE(score, -1) DESC, l.id DESC",
+		skipArgs: true,
+		columns: []string{
+			"id", "title", "description", "phone", "source", "created_at", "updated_at", "owner_id", "branch_id", "branch_name",
+			"department_id", "funnel_id", "status", "is_archived", "archived_at", "archived_by", "archive_reason", "score",
+		},
+		rows: [][]driver.Value{lead(3, int64(90)), lead(1, int64(40)), lead(2, nil)},
+	}}}
+	sql.Register(driverName, mockDriver)
+	db, err := sql.Open(driverName, "")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+
+	leads, err := NewLeadRepository(db).ListAllWithFilterAndArchiveScope(context.Background(), 20, 0, LeadListFilter{SortBy: "score"}, ArchiveScopeActiveOnly)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(leads) != 3 || leads[0].Score == nil || *leads[0].Score != 90 || *leads[1].Score != 40 || leads[2].Score != nil {
+		t.Fatalf("expected scores [90 40 nil], got %+v", leads)
+	}
+	if !mockDriver.consumedAll() {
+		t.Fatal("expected the list query to run")
+	}
+}
@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestLeadRepository_GetLeadSourceStats_GroupsConversionsBySource(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	branchID := 3
+	driverName := fmt.Sprintf("scripted-lead-source-%d", time.Now().UnixNano())
+	mockDriver := &scriptedDriver{steps: []scriptedStep{{
+		kind: "query",
+		query: "SELECT COALESCE(NULLIF(source, ''), 'unknown') AS source, COUNT(*) AS total, " +
+			"COUNT(*) FILTER (WHERE status = 'converted') AS converted " +
+			"FROM leads WHERE created_at BETWEEN $1 AND $2 AND branch_id = $3 GROUP BY 1 ORDER BY total DESC, source",
+		args:    []any{from, to, int64(branchID)},
+		columns: []string{"source", "total", "converted"},
+		rows: [][]driver.Value{
+			{"web", int64(4), int64(1)},
+			{"unknown", int64(2), int64(0)},
+			{"referral", int64(1), int64(1)},
+		},
+	}}}
+	sql.Register(driverName, mockDriver)
+	db, err := sql.Open(driverName, "")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+
+	rows, err := NewLeadRepository(db).GetLeadSourceStats(context.Background(), from, to, nil, &branchID)
+	if err != nil {
+		t.Fatalf("GetLeadSourceStats: %v", err)
+	}
+	if len(rows) != 3 || rows[0].Source != "web" || rows[0].Total != 4 || rows[0].Converted != 1 ||
+		rows[1].Source != "unknown" || rows[2].Source != "referral" || rows[2].Converted != 1 {
+		t.Fatalf("unexpected rows: %+v", rows)
+	}
+	if !mockDriver.consumedAll() {
+		t.Fatal("expected the stats query to run")
+	}
+}
@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ListCursor is a keyset position in a list ordered by (created_at, id): the
+// values of the last row already returned. Rows strictly after it form the
+// next page, so inserts and deletes between requests never shift the window
+// the way OFFSET does.
+type ListCursor struct {
+	CreatedAt time.Time
+	ID        int
+}
+
+// Encode renders the cursor as an opaque URL-safe token.
+func (c ListCursor) Encode() string {
+	raw := strconv.FormatInt(c.CreatedAt.UnixNano(), 10) + ":" + strconv.Itoa(c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeListCursor parses a token produced by Encode. An empty token means
+// "from the start" and yields nil.
+func DecodeListCursor(token string) (*ListCursor, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	nanosRaw, idRaw, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	nanos, err := strconv.ParseInt(nanosRaw, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	id, err := strconv.Atoi(idRaw)
+	if err != nil || id <= 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &ListCursor{CreatedAt: time.Unix(0, nanos).UTC(), ID: id}, nil
+}
+
+// keysetWhere keeps rows after cursor in (alias.created_at, alias.id) order;
+// order is the SQL direction of the list ("ASC" or "DESC").
+func keysetWhere(alias, order string, cursor *ListCursor, idx int) (string, []interface{}) {
+	if cursor == nil {
+		return "", nil
+	}
+	cmp := "<"
+	if order == "ASC" {
+		cmp = ">"
+	}
+	where := fmt.Sprintf(" AND (%[1]s.created_at, %[1]s.id) %[2]s ($%[3]d, $%[4]d)", alias, cmp, idx, idx+1)
+	return where, []interface{}{cursor.CreatedAt, cursor.ID}
+}
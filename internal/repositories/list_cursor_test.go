@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestListCursor_RoundTrip(t *testing.T) {
+	in := ListCursor{CreatedAt: time.Date(2026, 4, 3, 10, 20, 30, 123456000, time.UTC), ID: 42}
+	out, err := DecodeListCursor(in.Encode())
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if out == nil || !out.CreatedAt.Equal(in.CreatedAt) || out.ID != in.ID {
+		t.Fatalf("round trip mismatch: got %+v want %+v", out, in)
+	}
+	if cur, err := DecodeListCursor(""); cur != nil || err != nil {
+		t.Fatalf("empty cursor must mean first page, got (%+v, %v)", cur, err)
+	}
+}
+
+func TestDecodeListCursor_RejectsGarbage(t *testing.T) {
+	for _, raw := range []string{"%%%", "bm90LWEtY3Vyc29y", "MTIzNA", "MTIzOmFiYw", "MTIzOjA", "YWJjOjE"} {
+		if _, err := DecodeListCursor(raw); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("%q: expected ErrInvalidCursor, got %v", raw, err)
+		}
+	}
+}
+
+func TestDealRepository_ListPaginatedAfter_UsesKeyset(t *testing.T) {
+	after := &ListCursor{CreatedAt: time.Date(2026, 4, 3, 10, 0, 0, 0, time.UTC), ID: 17}
+	db := openScriptedDB(t, scriptedStep{
+		kind:  "query",
+		query: "AND (d.created_at, d.id) < ($1, $2) ORDER BY d.created_at DESC, d.id DESC LIMIT $3 OFFSET $4",
+		args:  []any{after.CreatedAt, 17, 25, 0},
+	})
+	if _, err := NewDealRepository(db).ListPaginatedAfter(context.Background(), after, 25); err != nil {
+		t.Fatalf("ListPaginatedAfter: %v", err)
+	}
+}
+
+func TestLeadRepository_ListPaginatedAfter_UsesKeyset(t *testing.T) {
+	after := &ListCursor{CreatedAt: time.Date(2026, 4, 3, 10, 0, 0, 0, time.UTC), ID: 17}
+	db := openScriptedDB(t, scriptedStep{
+		kind:  "query",
+		query: "AND (l.created_at, l.id) < ($1, $2) ORDER BY created_at DESC, l.id DESC LIMIT $3 OFFSET $4",
+		args:  []any{after.CreatedAt, 17, 25, 0},
+	})
+	if _, err := NewLeadRepository(db).ListPaginatedAfter(context.Background(), after, 25); err != nil {
+		t.Fatalf("ListPaginatedAfter: %v", err)
+	}
+}
+
+func TestDealListKeyset_FollowsFiltersAndOrder(t *testing.T) {
+	branch := 3
+	after := &ListCursor{CreatedAt: time.Date(2026, 4, 3, 10, 0, 0, 0, time.UTC), ID: 17}
+	where, args := buildDealListWhere(DealListFilter{BranchID: &branch, Order: "asc", After: after}, 1)
+	const want = " AND d.branch_id = $1 AND (d.created_at, d.id) > ($2, $3)"
+	if where != want {
+		t.Fatalf("where = %q, want %q", where, want)
+	}
+	if len(args) != 3 || args[2] != 17 {
+		t.Fatalf("unexpected args %v", args)
+	}
+	if got := dealOrderBy(DealListFilter{SortBy: "amount", After: after}); got != "d.created_at DESC, d.id DESC" {
+		t.Fatalf("keyset mode must order by created_at, got %q", got)
+	}
+}
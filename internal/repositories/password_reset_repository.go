@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"time"
 
@@ -8,9 +9,14 @@ import (
 )
 
 type PasswordResetRepository interface {
-	Create(userID int, token string, expiresAt time.Time) error
-	GetByToken(token string) (*models.PasswordReset, error)
-	MarkUsed(token string) error
+	Create(ctx context.Context, userID int, token string, expiresAt time.Time) error
+	GetByToken(ctx context.Context, token string) (*models.PasswordReset, error)
+	// GetLatestActiveByUser returns the newest unused reset that has not
+	// expired at now, or nil when there is none.
+	GetLatestActiveByUser(ctx context.Context, userID int, now time.Time) (*models.PasswordReset, error)
+	// MarkUsed consumes an unused token and reports whether this call did so;
+	// false means the token is unknown or another request already used it.
+	MarkUsed(ctx context.Context, token string) (bool, error)
 }
 
 type passwordResetRepository struct {
@@ -21,23 +27,23 @@ func NewPasswordResetRepository(db *sql.DB) PasswordResetRepository {
 	return &passwordResetRepository{DB: db}
 }
 
-func (r *passwordResetRepository) Create(userID int, token string, expiresAt time.Time) error {
+func (r *passwordResetRepository) Create(ctx context.Context, userID int, token string, expiresAt time.Time) error {
 	const q = `
 INSERT INTO password_resets (user_id, token, expires_at)
 VALUES ($1, $2, $3)
 `
-	_, err := r.DB.Exec(q, userID, token, expiresAt)
+	_, err := r.DB.ExecContext(ctx, q, userID, token, expiresAt)
 	return err
 }
 
-func (r *passwordResetRepository) GetByToken(token string) (*models.PasswordReset, error) {
+func (r *passwordResetRepository) GetByToken(ctx context.Context, token string) (*models.PasswordReset, error) {
 	const q = `
 SELECT id, user_id, token, expires_at, used, created_at
 FROM password_resets
 WHERE token = $1
 `
 	pr := &models.PasswordReset{}
-	if err := r.DB.QueryRow(q, token).Scan(&pr.ID, &pr.UserID, &pr.Token, &pr.ExpiresAt, &pr.Used, &pr.CreatedAt); err != nil {
+	if err := r.DB.QueryRowContext(ctx, q, token).Scan(&pr.ID, &pr.UserID, &pr.Token, &pr.ExpiresAt, &pr.Used, &pr.CreatedAt); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
@@ -46,10 +52,35 @@ WHERE token = $1
 	return pr, nil
 }
 
-func (r *passwordResetRepository) MarkUsed(token string) error {
+func (r *passwordResetRepository) GetLatestActiveByUser(ctx context.Context, userID int, now time.Time) (*models.PasswordReset, error) {
 	const q = `
-UPDATE password_resets SET used = TRUE WHERE token = $1
+SELECT id, user_id, token, expires_at, used, created_at
+FROM password_resets
+WHERE user_id = $1 AND used = FALSE AND expires_at > $2
+ORDER BY created_at DESC, id DESC
+LIMIT 1
 `
-	_, err := r.DB.Exec(q, token)
-	return err
+	pr := &models.PasswordReset{}
+	if err := r.DB.QueryRowContext(ctx, q, userID, now).Scan(&pr.ID, &pr.UserID, &pr.Token, &pr.ExpiresAt, &pr.Used, &pr.CreatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return pr, nil
+}
+
+func (r *passwordResetRepository) MarkUsed(ctx context.Context, token string) (bool, error) {
+	const q = `
+UPDATE password_resets SET used = TRUE WHERE token = $1 AND used = FALSE
+`
+	res, err := r.DB.ExecContext(ctx, q, token)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
 }
@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var notFoundDriverSeq int32
+
+func openScriptedDB(t *testing.T, steps ...scriptedStep) *sql.DB {
+	t.Helper()
+	name := fmt.Sprintf("scripted-not-found-%d-%d", time.Now().UnixNano(), atomic.AddInt32(&notFoundDriverSeq, 1))
+	sql.Register(name, &scriptedDriver{steps: steps})
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+// nilLookups — GetByID-подобные методы, которые на отсутствующую строку
+// отвечают (nil, nil).
+var nilLookups = []struct {
+	name  string
+	table string
+	call  func(db *sql.DB) (bool, error)
+}{
+	{"task", "FROM tasks", func(db *sql.DB) (bool, error) {
+		v, err := NewTaskRepository(db).FindByID(context.Background(), 1)
+		return v == nil, err
+	}},
+	{"deal", "FROM deals", func(db *sql.DB) (bool, error) {
+		v, err := NewDealRepository(db).GetByID(context.Background(), 1)
+		return v == nil, err
+	}},
+	{"lead", "FROM leads", func(db *sql.DB) (bool, error) {
+		v, err := NewLeadRepository(db).GetByID(context.Background(), 1)
+		return v == nil, err
+	}},
+	{"document", "FROM documents", func(db *sql.DB) (bool, error) {
+		v, err := NewDocumentRepository(db).GetByID(context.Background(), 1)
+		return v == nil, err
+	}},
+	{"client", "FROM clients", func(db *sql.DB) (bool, error) {
+		v, err := NewClientRepository(db).GetByID(context.Background(), 1)
+		return v == nil, err
+	}},
+}
+
+func TestGetByID_MissingRowIsNil(t *testing.T) {
+	for _, tc := range nilLookups {
+		db := openScriptedDB(t, scriptedStep{kind: "query", query: tc.table, skipArgs: true})
+		isNil, err := tc.call(db)
+		if err != nil || !isNil {
+			t.Errorf("%s: expected (nil, nil) for a missing row, got nil=%v err=%v", tc.name, isNil, err)
+		}
+	}
+}
+
+func TestGetByID_StorageErrorIsNotNotFound(t *testing.T) {
+	boom := errors.New("connection reset")
+	for _, tc := range nilLookups {
+		db := openScriptedDB(t, scriptedStep{kind: "query", query: tc.table, skipArgs: true, err: boom})
+		isNil, err := tc.call(db)
+		if !errors.Is(err, boom) || !isNil {
+			t.Errorf("%s: expected the storage error, got nil=%v err=%v", tc.name, isNil, err)
+		}
+		if errors.Is(err, ErrNotFound) {
+			t.Errorf("%s: storage error must not look like not found: %v", tc.name, err)
+		}
+	}
+}
+
+func TestUserRepository_MissingRowIsErrNotFound(t *testing.T) {
+	lookups := map[string]func(UserRepository) error{
+		"GetByID":        func(r UserRepository) error { _, err := r.GetByID(1); return err },
+		"GetByIDSimple":  func(r UserRepository) error { _, err := r.GetByIDSimple(1); return err },
+		"GetByEmail":     func(r UserRepository) error { _, err := r.GetByEmail("a@b.kz"); return err },
+		"GetAuthByEmail": func(r UserRepository) error { _, err := r.GetAuthByEmail("a@b.kz"); return err },
+	}
+	for name, call := range lookups {
+		db := openScriptedDB(t, scriptedStep{kind: "query", query: "FROM users", skipArgs: true})
+		err := call(NewUserRepository(db))
+		if !errors.Is(err, ErrNotFound) || !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("%s: expected ErrNotFound wrapping sql.ErrNoRows, got %v", name, err)
+		}
+
+		boom := errors.New("connection reset")
+		db = openScriptedDB(t, scriptedStep{kind: "query", query: "FROM users", skipArgs: true, err: boom})
+		if err := call(NewUserRepository(db)); !errors.Is(err, boom) || errors.Is(err, ErrNotFound) {
+			t.Errorf("%s: expected the storage error as is, got %v", name, err)
+		}
+	}
+}
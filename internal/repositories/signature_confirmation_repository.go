@@ -216,6 +216,30 @@ func (r *SignatureConfirmationRepository) HasApproved(
 	return exists, nil
 }
 
+// HasApprovedForDocument is HasApproved without the user filter: it reports
+// whether any signer confirmed the document on channel at or after since.
+func (r *SignatureConfirmationRepository) HasApprovedForDocument(
+	ctx context.Context,
+	documentID int64,
+	channel string,
+	since time.Time,
+) (bool, error) {
+	const q = `
+		SELECT EXISTS (
+			SELECT 1
+			FROM signature_confirmations
+			WHERE document_id = $1
+			  AND channel = $2
+			  AND status = 'approved'
+			  AND approved_at >= $3
+		)`
+	var exists bool
+	if err := r.DB.QueryRowContext(ctx, q, documentID, channel, since).Scan(&exists); err != nil {
+		return false, fmt.Errorf("check approved confirmation: %w", err)
+	}
+	return exists, nil
+}
+
 func (r *SignatureConfirmationRepository) Approve(
 	ctx context.Context,
 	id string,
@@ -312,6 +336,68 @@ func (r *SignatureConfirmationRepository) CancelPrevious(
 	return affected, nil
 }
 
+// RefreshPending reissues the latest pending confirmation for the
+// document/user/channel in place: new OTP and token hashes, reset attempts and
+// a new expiry. Meta is replaced (preview/open markers belong to the previous
+// send) apart from a running resend_count. Any older pending rows are
+// cancelled in the same statement so a resend never leaves more than one live
+// confirmation behind. Returns nil when there is no pending row to refresh.
+func (r *SignatureConfirmationRepository) RefreshPending(
+	ctx context.Context,
+	documentID int64,
+	userID int64,
+	channel string,
+	otpHash *string,
+	tokenHash *string,
+	expiresAt time.Time,
+	meta []byte,
+) (*models.SignatureConfirmation, error) {
+	const q = `
+		WITH latest AS (
+			SELECT id
+			FROM signature_confirmations
+			WHERE document_id = $1
+			  AND user_id = $2
+			  AND channel = $3
+			  AND status = 'pending'
+			ORDER BY expires_at DESC
+			LIMIT 1
+			FOR UPDATE
+		), superseded AS (
+			UPDATE signature_confirmations
+			SET status = 'cancelled'
+			WHERE document_id = $1
+			  AND user_id = $2
+			  AND channel = $3
+			  AND status = 'pending'
+			  AND id NOT IN (SELECT id FROM latest)
+		)
+		UPDATE signature_confirmations sc
+		SET otp_hash = $4,
+		    token_hash = $5,
+		    attempts = 0,
+		    expires_at = $6,
+		    meta = COALESCE($7::jsonb, '{}'::jsonb)
+		        || jsonb_build_object('resend_count', COALESCE((sc.meta->>'resend_count')::int, 0) + 1)
+		FROM latest
+		WHERE sc.id = latest.id
+		RETURNING sc.id, sc.document_id, sc.user_id, sc.channel, sc.status, sc.otp_hash, sc.token_hash,
+		          sc.attempts, sc.expires_at, sc.approved_at, sc.rejected_at, sc.meta`
+	var metaVal any
+	if len(meta) > 0 {
+		metaVal = string(meta)
+	}
+	row := r.DB.QueryRowContext(ctx, q, documentID, userID, channel, otpHash, tokenHash, expiresAt, metaVal)
+	confirmation, err := scanSignatureConfirmation(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("refresh pending confirmation: %w", err)
+	}
+	return confirmation, nil
+}
+
 func (r *SignatureConfirmationRepository) UpdateMeta(
 	ctx context.Context,
 	id string,
@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"turcompany/internal/models"
+)
+
+type TaskAttachmentRepository interface {
+	Create(ctx context.Context, att *models.TaskAttachment) error
+	ListByTask(ctx context.Context, taskID int64) ([]models.TaskAttachment, error)
+	// GetByID returns nil, nil when the attachment does not exist.
+	GetByID(ctx context.Context, id int64) (*models.TaskAttachment, error)
+}
+
+type taskAttachmentRepository struct {
+	DB *sql.DB
+}
+
+func NewTaskAttachmentRepository(db *sql.DB) TaskAttachmentRepository {
+	return &taskAttachmentRepository{DB: db}
+}
+
+const taskAttachmentColumns = `id, task_id, file_name, mime_type, size_bytes, storage_key, uploaded_by, created_at`
+
+func (r *taskAttachmentRepository) Create(ctx context.Context, att *models.TaskAttachment) error {
+	const q = `
+INSERT INTO task_attachments (task_id, file_name, mime_type, size_bytes, storage_key, uploaded_by)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, created_at
+`
+	return r.DB.QueryRowContext(ctx, q, att.TaskID, att.FileName, att.MimeType, att.SizeBytes, att.StorageKey, att.UploadedBy).
+		Scan(&att.ID, &att.CreatedAt)
+}
+
+func (r *taskAttachmentRepository) ListByTask(ctx context.Context, taskID int64) ([]models.TaskAttachment, error) {
+	rows, err := r.DB.QueryContext(ctx, `SELECT `+taskAttachmentColumns+` FROM task_attachments WHERE task_id = $1 ORDER BY created_at, id`, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.TaskAttachment
+	for rows.Next() {
+		att, err := scanTaskAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *att)
+	}
+	return out, rows.Err()
+}
+
+func (r *taskAttachmentRepository) GetByID(ctx context.Context, id int64) (*models.TaskAttachment, error) {
+	row := r.DB.QueryRowContext(ctx, `SELECT `+taskAttachmentColumns+` FROM task_attachments WHERE id = $1`, id)
+	att, err := scanTaskAttachment(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return att, err
+}
+
+func scanTaskAttachment(s interface{ Scan(dest ...any) error }) (*models.TaskAttachment, error) {
+	var (
+		att        models.TaskAttachment
+		uploadedBy sql.NullInt64
+	)
+	if err := s.Scan(&att.ID, &att.TaskID, &att.FileName, &att.MimeType, &att.SizeBytes, &att.StorageKey, &uploadedBy, &att.CreatedAt); err != nil {
+		return nil, err
+	}
+	if uploadedBy.Valid {
+		v := uploadedBy.Int64
+		att.UploadedBy = &v
+	}
+	return &att, nil
+}
@@ -23,12 +23,27 @@ type TaskRepository interface {
 	Unarchive(ctx context.Context, id int64) error
 
 	// NEW:
-	UpdateStatus(ctx context.Context, id int64, to models.TaskStatus) error
-	UpdateAssignee(ctx context.Context, id int64, assigneeID int64) error
+	UpdateStatus(ctx context.Context, id int64, to models.TaskStatus, actorID int64) error
+	// UpdateStatusBatch moves each task from its expected status to `to` in
+	// one transaction. Items are independent: a failing item is rolled back
+	// to its savepoint and reported in the returned map, the rest commit.
+	UpdateStatusBatch(ctx context.Context, changes []TaskStatusChange, to models.TaskStatus, actorID int64) (map[int64]error, error)
+	UpdateAssignee(ctx context.Context, id int64, assigneeID int64, actorID int64) error
+	// ReassignTasks hands the listed tasks over from one assignee to another
+	// in one transaction and returns the ids actually moved.
+	ReassignTasks(ctx context.Context, ids []int64, from, to int64) ([]int64, error)
 	ListDueForReminder(ctx context.Context, limit int) ([]models.Task, error)
 	SetReminderFired(ctx context.Context, id int64) error
 }
 
+// TaskStatusChange is one item of UpdateStatusBatch. From is the status the
+// caller validated the transition against; if the task moved on meanwhile
+// the item fails with ErrTaskVersionConflict.
+type TaskStatusChange struct {
+	ID   int64
+	From models.TaskStatus
+}
+
 type taskRepository struct {
 	db *sql.DB
 }
@@ -63,15 +78,15 @@ func (r *taskRepository) Store(ctx context.Context, task *models.Task) error {
 	query := `
 		INSERT INTO tasks (
 			creator_id, assignee_id, branch_id, entity_id, entity_type, title, description,
-			due_date, reminder_at, priority, status, created_at, updated_at
+			due_date, reminder_at, priority, status, created_at, updated_at, reminder_offset_seconds
 		)
-		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13)
-		RETURNING id, created_at, updated_at`
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14)
+		RETURNING id, created_at, updated_at, version`
 	if err := tx.QueryRowContext(ctx, query,
 		task.CreatorID, task.AssigneeID, task.BranchID, task.EntityID, task.EntityType,
 		task.Title, task.Description, task.DueDate, task.ReminderAt, task.Priority, task.Status,
-		task.CreatedAt, task.UpdatedAt,
-	).Scan(&task.ID, &task.CreatedAt, &task.UpdatedAt); err != nil {
+		task.CreatedAt, task.UpdatedAt, task.ReminderOffset,
+	).Scan(&task.ID, &task.CreatedAt, &task.UpdatedAt, &task.Version); err != nil {
 		return err
 	}
 	if err := replaceTaskAssignees(ctx, tx, task.ID, task.AssigneeIDs); err != nil {
@@ -160,14 +175,14 @@ func (r *taskRepository) FindByID(ctx context.Context, id int64) (*models.Task,
 
 func (r *taskRepository) FindByIDWithArchiveScope(ctx context.Context, id int64, scope ArchiveScope) (*models.Task, error) {
 	query := `SELECT id, COALESCE(creator_id, 0), COALESCE(assignee_id, 0), branch_id, entity_id, entity_type, title, description,
-       due_date, reminder_at, last_reminded_at, priority, status, created_at, updated_at, is_archived, archived_at, archived_by, COALESCE(archive_reason,'')
+       due_date, reminder_at, reminder_offset_seconds, last_reminded_at, priority, status, created_at, updated_at, version, is_archived, archived_at, archived_by, COALESCE(archive_reason,''), last_modified_by
        FROM tasks WHERE id = $1 AND ` + taskArchiveWhere(scope)
 	task := &models.Task{}
 	var branchID sql.NullInt64
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&task.ID, &task.CreatorID, &task.AssigneeID, &branchID, &task.EntityID, &task.EntityType,
-		&task.Title, &task.Description, &task.DueDate, &task.ReminderAt, &task.LastRemindedAt,
-		&task.Priority, &task.Status, &task.CreatedAt, &task.UpdatedAt, &task.IsArchived, &task.ArchivedAt, &task.ArchivedBy, &task.ArchiveReason,
+		&task.Title, &task.Description, &task.DueDate, &task.ReminderAt, &task.ReminderOffset, &task.LastRemindedAt,
+		&task.Priority, &task.Status, &task.CreatedAt, &task.UpdatedAt, &task.Version, &task.IsArchived, &task.ArchivedAt, &task.ArchivedBy, &task.ArchiveReason, &task.LastModifiedBy,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -189,11 +204,10 @@ func (r *taskRepository) FindByIDWithArchiveScope(ctx context.Context, id int64,
 
 func (r *taskRepository) FindAll(ctx context.Context, filter models.TaskFilter) ([]models.Task, error) {
 	baseQuery := `SELECT id, COALESCE(creator_id, 0), COALESCE(assignee_id, 0), branch_id, entity_id, entity_type, title, description,
-       due_date, reminder_at, last_reminded_at, priority, status, created_at, updated_at, is_archived, archived_at, archived_by, COALESCE(archive_reason,'') FROM tasks`
+       due_date, reminder_at, reminder_offset_seconds, last_reminded_at, priority, status, created_at, updated_at, version, is_archived, archived_at, archived_by, COALESCE(archive_reason,''), last_modified_by FROM tasks`
 	whereClause, args := buildTaskFilterWhere(filter, 1)
 	baseQuery += " WHERE " + whereClause
-	sortExpr, sortOrder := taskSortExpression(filter.SortBy, filter.Order)
-	baseQuery += fmt.Sprintf(" ORDER BY %s %s", sortExpr, sortOrder)
+	baseQuery += taskOrderBy(filter)
 
 	rows, err := r.db.QueryContext(ctx, baseQuery, args...)
 	if err != nil {
@@ -207,8 +221,8 @@ func (r *taskRepository) FindAll(ctx context.Context, filter models.TaskFilter)
 		var branchID sql.NullInt64
 		if err := rows.Scan(
 			&t.ID, &t.CreatorID, &t.AssigneeID, &branchID, &t.EntityID, &t.EntityType,
-			&t.Title, &t.Description, &t.DueDate, &t.ReminderAt, &t.LastRemindedAt,
-			&t.Priority, &t.Status, &t.CreatedAt, &t.UpdatedAt, &t.IsArchived, &t.ArchivedAt, &t.ArchivedBy, &t.ArchiveReason,
+			&t.Title, &t.Description, &t.DueDate, &t.ReminderAt, &t.ReminderOffset, &t.LastRemindedAt,
+			&t.Priority, &t.Status, &t.CreatedAt, &t.UpdatedAt, &t.Version, &t.IsArchived, &t.ArchivedAt, &t.ArchivedBy, &t.ArchiveReason, &t.LastModifiedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -229,12 +243,11 @@ func (r *taskRepository) FindAll(ctx context.Context, filter models.TaskFilter)
 
 func (r *taskRepository) FindAllPaginated(ctx context.Context, filter models.TaskFilter, limit, offset int) ([]models.Task, error) {
 	baseQuery := `SELECT id, COALESCE(creator_id, 0), COALESCE(assignee_id, 0), branch_id, entity_id, entity_type, title, description,
-       due_date, reminder_at, last_reminded_at, priority, status, created_at, updated_at, is_archived, archived_at, archived_by, COALESCE(archive_reason,'') FROM tasks`
+       due_date, reminder_at, reminder_offset_seconds, last_reminded_at, priority, status, created_at, updated_at, version, is_archived, archived_at, archived_by, COALESCE(archive_reason,''), last_modified_by FROM tasks`
 	whereClause, args := buildTaskFilterWhere(filter, 1)
 	baseQuery += " WHERE " + whereClause
-	sortExpr, sortOrder := taskSortExpression(filter.SortBy, filter.Order)
 	args = append(args, limit, offset)
-	baseQuery += fmt.Sprintf(" ORDER BY %s %s LIMIT $%d OFFSET $%d", sortExpr, sortOrder, len(args)-1, len(args))
+	baseQuery += taskOrderBy(filter) + fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)-1, len(args))
 
 	rows, err := r.db.QueryContext(ctx, baseQuery, args...)
 	if err != nil {
@@ -247,8 +260,8 @@ func (r *taskRepository) FindAllPaginated(ctx context.Context, filter models.Tas
 		var branchID sql.NullInt64
 		if err := rows.Scan(
 			&t.ID, &t.CreatorID, &t.AssigneeID, &branchID, &t.EntityID, &t.EntityType,
-			&t.Title, &t.Description, &t.DueDate, &t.ReminderAt, &t.LastRemindedAt,
-			&t.Priority, &t.Status, &t.CreatedAt, &t.UpdatedAt, &t.IsArchived, &t.ArchivedAt, &t.ArchivedBy, &t.ArchiveReason,
+			&t.Title, &t.Description, &t.DueDate, &t.ReminderAt, &t.ReminderOffset, &t.LastRemindedAt,
+			&t.Priority, &t.Status, &t.CreatedAt, &t.UpdatedAt, &t.Version, &t.IsArchived, &t.ArchivedAt, &t.ArchivedBy, &t.ArchiveReason, &t.LastModifiedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -292,6 +305,11 @@ func buildTaskFilterWhere(filter models.TaskFilter, startAt int) (string, []inte
 		args = append(args, *filter.CreatorID)
 		argID++
 	}
+	if filter.ParticipantID != nil {
+		conditions = append(conditions, fmt.Sprintf("(creator_id = $%d OR EXISTS (SELECT 1 FROM task_assignees ta WHERE ta.task_id = tasks.id AND ta.user_id = $%d))", argID, argID))
+		args = append(args, *filter.ParticipantID)
+		argID++
+	}
 	if filter.BranchID != nil {
 		conditions = append(conditions, fmt.Sprintf("branch_id = $%d", argID))
 		args = append(args, *filter.BranchID)
@@ -307,9 +325,17 @@ func buildTaskFilterWhere(filter models.TaskFilter, startAt int) (string, []inte
 		args = append(args, *filter.EntityType)
 		argID++
 	}
-	if filter.Status != nil {
+	if len(filter.Statuses) == 1 {
 		conditions = append(conditions, fmt.Sprintf("status = $%d", argID))
-		args = append(args, *filter.Status)
+		args = append(args, filter.Statuses[0])
+		argID++
+	} else if len(filter.Statuses) > 1 {
+		statuses := make([]string, len(filter.Statuses))
+		for i, st := range filter.Statuses {
+			statuses[i] = string(st)
+		}
+		conditions = append(conditions, fmt.Sprintf("status = ANY($%d)", argID))
+		args = append(args, pq.Array(statuses))
 		argID++
 	} else {
 		statuses := taskStatusesFromGroup(filter.StatusGroup)
@@ -319,6 +345,18 @@ func buildTaskFilterWhere(filter models.TaskFilter, startAt int) (string, []inte
 			argID++
 		}
 	}
+	if filter.Priority != nil {
+		conditions = append(conditions, fmt.Sprintf("priority = $%d", argID))
+		args = append(args, *filter.Priority)
+		argID++
+	}
+	if filter.Overdue != nil {
+		if *filter.Overdue {
+			conditions = append(conditions, "due_date < NOW() AND status NOT IN ('done','cancelled')")
+		} else {
+			conditions = append(conditions, "(due_date IS NULL OR due_date >= NOW() OR status IN ('done','cancelled'))")
+		}
+	}
 	if strings.TrimSpace(filter.Query) != "" {
 		conditions = append(conditions, fmt.Sprintf("(LOWER(COALESCE(title,'')) LIKE $%d OR LOWER(COALESCE(description,'')) LIKE $%d)", argID, argID))
 		args = append(args, "%"+strings.ToLower(strings.TrimSpace(filter.Query))+"%")
@@ -347,25 +385,48 @@ func taskStatusesFromGroup(group string) []string {
 	}
 }
 
+// taskSortFields maps the sort_by values accepted by the task list to SQL.
+// Priority is ranked low→urgent instead of sorted alphabetically.
+var taskSortFields = map[string]string{
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"due_date":   "due_date",
+	"priority":   "CASE priority WHEN 'low' THEN 1 WHEN 'normal' THEN 2 WHEN 'high' THEN 3 WHEN 'urgent' THEN 4 ELSE 0 END",
+	"status":     "status",
+	"title":      "LOWER(COALESCE(title,''))",
+}
+
+// IsTaskSortField reports whether sortBy is an accepted task sort_by value.
+func IsTaskSortField(sortBy string) bool {
+	_, ok := taskSortFields[sortBy]
+	return ok
+}
+
+// taskSortExpression resolves sort_by/order against taskSortFields; unknown
+// fields fall back to created_at and anything but "asc" means DESC.
 func taskSortExpression(sortBy, order string) (string, string) {
 	sortOrder := "DESC"
 	if strings.EqualFold(order, "asc") {
 		sortOrder = "ASC"
 	}
-	switch sortBy {
-	case "due_date":
-		return "due_date", sortOrder
-	case "priority":
-		return "priority", sortOrder
-	case "status":
-		return "status", sortOrder
-	case "title":
-		return "LOWER(COALESCE(title,''))", sortOrder
-	default:
-		return "created_at", sortOrder
+	expr, ok := taskSortFields[sortBy]
+	if !ok {
+		expr = taskSortFields["created_at"]
 	}
+	return expr, sortOrder
+}
+
+// taskOrderBy builds the ORDER BY clause for task lists. Tasks without a due
+// date go last in both directions and id keeps ties in a stable order.
+func taskOrderBy(filter models.TaskFilter) string {
+	expr, order := taskSortExpression(filter.SortBy, filter.Order)
+	return fmt.Sprintf(" ORDER BY %s %s NULLS LAST, id %s", expr, order, order)
 }
 
+// Update writes the task only if its version still equals task.Version and
+// bumps the version; a mismatch (someone saved in between) returns
+// ErrTaskVersionConflict and changes nothing. creator_id, branch_id,
+// created_at and the archive columns are never written here.
 func (r *taskRepository) Update(ctx context.Context, task *models.Task) error {
 	task.AssigneeIDs = dedupeAssignees(task.AssigneeID, task.AssigneeIDs)
 	if len(task.AssigneeIDs) > 0 {
@@ -380,13 +441,20 @@ func (r *taskRepository) Update(ctx context.Context, task *models.Task) error {
 
 	query := `
 		UPDATE tasks SET
-			assignee_id=$1, branch_id=$2, title=$3, description=$4, due_date=$5,
-			reminder_at=$6, priority=$7, status=$8, updated_at=$9
-		WHERE id=$10`
-	if _, err := tx.ExecContext(ctx, query,
-		task.AssigneeID, task.BranchID, task.Title, task.Description, task.DueDate,
-		task.ReminderAt, task.Priority, task.Status, task.UpdatedAt, task.ID,
-	); err != nil {
+			assignee_id=$1, title=$2, description=$3, due_date=$4,
+			reminder_at=$5, priority=$6, status=$7, updated_at=$8, entity_id=$9,
+			entity_type=$10, reminder_offset_seconds=$11, last_modified_by=$12,
+			version=version+1
+		WHERE id=$13 AND version=$14
+		RETURNING version`
+	if err := tx.QueryRowContext(ctx, query,
+		task.AssigneeID, task.Title, task.Description, task.DueDate,
+		task.ReminderAt, task.Priority, task.Status, task.UpdatedAt, task.EntityID,
+		task.EntityType, task.ReminderOffset, task.LastModifiedBy, task.ID, task.Version,
+	).Scan(&task.Version); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrTaskVersionConflict
+		}
 		return err
 	}
 	if err := replaceTaskAssignees(ctx, tx, task.ID, task.AssigneeIDs); err != nil {
@@ -407,7 +475,8 @@ func (r *taskRepository) Archive(ctx context.Context, id int64, archivedBy int64
 		    archived_at = NOW(),
 		    archived_by = $2,
 		    archive_reason = $3,
-		    updated_at = NOW()
+		    updated_at = NOW(),
+		    version = version + 1
 		WHERE id = $1
 	`, id, archivedBy, reason)
 	return err
@@ -420,19 +489,57 @@ func (r *taskRepository) Unarchive(ctx context.Context, id int64) error {
 		    archived_at = NULL,
 		    archived_by = NULL,
 		    archive_reason = NULL,
-		    updated_at = NOW()
+		    updated_at = NOW(),
+		    version = version + 1
 		WHERE id = $1
 	`, id)
 	return err
 }
 
-func (r *taskRepository) UpdateStatus(ctx context.Context, id int64, to models.TaskStatus) error {
+func (r *taskRepository) UpdateStatus(ctx context.Context, id int64, to models.TaskStatus, actorID int64) error {
 	_, err := r.db.ExecContext(ctx,
-		`UPDATE tasks SET status=$1, updated_at=NOW() WHERE id=$2`, to, id)
+		`UPDATE tasks SET status=$1, last_modified_by=$3, updated_at=NOW(), version=version+1 WHERE id=$2`, to, id, actorID)
 	return err
 }
 
-func (r *taskRepository) UpdateAssignee(ctx context.Context, id int64, assigneeID int64) error {
+func (r *taskRepository) UpdateStatusBatch(ctx context.Context, changes []TaskStatusChange, to models.TaskStatus, actorID int64) (map[int64]error, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	failed := map[int64]error{}
+	for _, ch := range changes {
+		if _, err := tx.ExecContext(ctx, `SAVEPOINT task_status_item`); err != nil {
+			return nil, err
+		}
+		res, err := tx.ExecContext(ctx,
+			`UPDATE tasks SET status=$1, last_modified_by=$4, updated_at=NOW(), version=version+1 WHERE id=$2 AND status=$3`, to, ch.ID, ch.From, actorID)
+		if err == nil {
+			var n int64
+			if n, err = res.RowsAffected(); err == nil && n == 0 {
+				err = ErrTaskVersionConflict
+			}
+		}
+		if err != nil {
+			if _, rbErr := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT task_status_item`); rbErr != nil {
+				return nil, rbErr
+			}
+			failed[ch.ID] = err
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `RELEASE SAVEPOINT task_status_item`); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return failed, nil
+}
+
+func (r *taskRepository) UpdateAssignee(ctx context.Context, id int64, assigneeID int64, actorID int64) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
@@ -440,7 +547,7 @@ func (r *taskRepository) UpdateAssignee(ctx context.Context, id int64, assigneeI
 	defer tx.Rollback()
 
 	if _, err := tx.ExecContext(ctx,
-		`UPDATE tasks SET assignee_id=$1, updated_at=NOW() WHERE id=$2`, assigneeID, id); err != nil {
+		`UPDATE tasks SET assignee_id=$1, last_modified_by=$3, updated_at=NOW(), version=version+1 WHERE id=$2`, assigneeID, id, actorID); err != nil {
 		return err
 	}
 	if err := replaceTaskAssignees(ctx, tx, id, []int64{assigneeID}); err != nil {
@@ -449,10 +556,65 @@ func (r *taskRepository) UpdateAssignee(ctx context.Context, id int64, assigneeI
 	return tx.Commit()
 }
 
+// ReassignTasks skips tasks that were closed (done/cancelled) or lost `from`
+// as an assignee since the caller listed them. `from` is replaced in place,
+// so co-assignees and the primary slot are kept.
+func (r *taskRepository) ReassignTasks(ctx context.Context, ids []int64, from, to int64) ([]int64, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+UPDATE tasks SET assignee_id = CASE WHEN assignee_id = $1 THEN $2 ELSE assignee_id END,
+       updated_at = NOW(), version = version + 1
+ WHERE id = ANY($3) AND status NOT IN ('done','cancelled')
+   AND (assignee_id = $1 OR EXISTS (SELECT 1 FROM task_assignees ta WHERE ta.task_id = tasks.id AND ta.user_id = $1))
+RETURNING id`, from, to, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	var moved []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		moved = append(moved, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(moved) == 0 {
+		return nil, nil
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO task_assignees (task_id, user_id)
+		 SELECT task_id, $2 FROM task_assignees WHERE user_id = $1 AND task_id = ANY($3)
+		 ON CONFLICT DO NOTHING`, from, to, pq.Array(moved)); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM task_assignees WHERE user_id = $1 AND task_id = ANY($2)`, from, pq.Array(moved)); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return moved, nil
+}
+
 func (r *taskRepository) ListDueForReminder(ctx context.Context, limit int) ([]models.Task, error) {
 	q := `
 SELECT id, COALESCE(creator_id, 0), COALESCE(assignee_id, 0), branch_id, entity_id, entity_type, title, description,
-       due_date, reminder_at, last_reminded_at, priority, status, created_at, updated_at, is_archived, archived_at, archived_by, COALESCE(archive_reason,'')
+       due_date, reminder_at, reminder_offset_seconds, last_reminded_at, priority, status, created_at, updated_at, version, is_archived, archived_at, archived_by, COALESCE(archive_reason,''), last_modified_by
 FROM tasks
 WHERE reminder_at IS NOT NULL
   AND is_archived = FALSE
@@ -473,7 +635,7 @@ LIMIT $1`
 		var branchID sql.NullInt64
 		if err := rows.Scan(
 			&t.ID, &t.CreatorID, &t.AssigneeID, &branchID, &t.EntityID, &t.EntityType, &t.Title, &t.Description,
-			&t.DueDate, &t.ReminderAt, &t.LastRemindedAt, &t.Priority, &t.Status, &t.CreatedAt, &t.UpdatedAt, &t.IsArchived, &t.ArchivedAt, &t.ArchivedBy, &t.ArchiveReason,
+			&t.DueDate, &t.ReminderAt, &t.ReminderOffset, &t.LastRemindedAt, &t.Priority, &t.Status, &t.CreatedAt, &t.UpdatedAt, &t.Version, &t.IsArchived, &t.ArchivedAt, &t.ArchivedBy, &t.ArchiveReason, &t.LastModifiedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"turcompany/internal/models"
+)
+
+// batchStatusDriver records executed statements; UPDATEs for a task id
+// return the scripted rows-affected count or error.
+type batchStatusDriver struct {
+	affected map[int64]int64
+	fail     map[int64]error
+	log      []string
+}
+
+type batchStatusConn struct{ drv *batchStatusDriver }
+type batchStatusTx struct{ drv *batchStatusDriver }
+
+var batchStatusDriverSeq int64
+
+func (d *batchStatusDriver) Open(string) (driver.Conn, error) { return &batchStatusConn{drv: d}, nil }
+
+func (c *batchStatusConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not implemented")
+}
+func (c *batchStatusConn) Close() error { return nil }
+func (c *batchStatusConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+func (c *batchStatusConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	c.drv.log = append(c.drv.log, "BEGIN")
+	return &batchStatusTx{drv: c.drv}, nil
+}
+
+func (c *batchStatusConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	q := strings.TrimSpace(query)
+	if !strings.HasPrefix(q, "UPDATE tasks") {
+		c.drv.log = append(c.drv.log, q)
+		return driver.RowsAffected(0), nil
+	}
+	id := args[1].Value.(int64)
+	c.drv.log = append(c.drv.log, fmt.Sprintf("UPDATE %d", id))
+	if err := c.drv.fail[id]; err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(c.drv.affected[id]), nil
+}
+
+func (t *batchStatusTx) Commit() error {
+	t.drv.log = append(t.drv.log, "COMMIT")
+	return nil
+}
+func (t *batchStatusTx) Rollback() error {
+	t.drv.log = append(t.drv.log, "ROLLBACK")
+	return nil
+}
+
+func TestTaskRepository_UpdateStatusBatch_PerItemSavepoints(t *testing.T) {
+	boom := errors.New("boom")
+	drv := &batchStatusDriver{
+		affected: map[int64]int64{1: 1, 2: 0},
+		fail:     map[int64]error{3: boom},
+	}
+	name := fmt.Sprintf("task-batch-status-%d", atomic.AddInt64(&batchStatusDriverSeq, 1))
+	sql.Register(name, drv)
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewTaskRepository(db)
+	failed, err := repo.UpdateStatusBatch(context.Background(), []TaskStatusChange{
+		{ID: 1, From: models.StatusInProgress},
+		{ID: 2, From: models.StatusInProgress}, // moved on meanwhile
+		{ID: 3, From: models.StatusInProgress}, // statement fails
+	}, models.StatusDone, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(failed) != 2 || !errors.Is(failed[2], ErrTaskVersionConflict) || !errors.Is(failed[3], boom) {
+		t.Fatalf("unexpected failures: %v", failed)
+	}
+
+	want := []string{
+		"BEGIN",
+		"SAVEPOINT task_status_item", "UPDATE 1", "RELEASE SAVEPOINT task_status_item",
+		"SAVEPOINT task_status_item", "UPDATE 2", "ROLLBACK TO SAVEPOINT task_status_item",
+		"SAVEPOINT task_status_item", "UPDATE 3", "ROLLBACK TO SAVEPOINT task_status_item",
+		"COMMIT",
+	}
+	if strings.Join(drv.log, "|") != strings.Join(want, "|") {
+		t.Fatalf("unexpected statements:\n got %v\nwant %v", drv.log, want)
+	}
+}
@@ -1,8 +1,11 @@
 package repositories
 
 import (
+	"context"
+	"database/sql/driver"
 	"strings"
 	"testing"
+	"time"
 
 	"turcompany/internal/models"
 )
@@ -28,9 +31,12 @@ func TestTaskSortExpressionWhitelist(t *testing.T) {
 	}{
 		{"", "", "created_at", "DESC"},
 		{"due_date", "asc", "due_date", "ASC"},
-		{"priority", "desc", "priority", "DESC"},
+		{"priority", "desc", "CASE priority WHEN 'low' THEN 1 WHEN 'normal' THEN 2 WHEN 'high' THEN 3 WHEN 'urgent' THEN 4 ELSE 0 END", "DESC"},
 		{"status", "asc", "status", "ASC"},
 		{"title", "desc", "LOWER(COALESCE(title,''))", "DESC"},
+		{"updated_at", "asc", "updated_at", "ASC"},
+		{"due_date; DROP TABLE tasks", "asc", "created_at", "ASC"},
+		{"bogus", "sideways", "created_at", "DESC"},
 	}
 	for _, tc := range tests {
 		gotBy, gotOrd := taskSortExpression(tc.sortBy, tc.order)
@@ -41,28 +47,118 @@ func TestTaskSortExpressionWhitelist(t *testing.T) {
 }
 
 func TestTaskFilterQueryAndStatusPriority(t *testing.T) {
-	status := models.TaskStatus("done")
-	filter := models.TaskFilter{Status: &status, StatusGroup: "active", Query: "archive"}
-	conditions := []string{}
-	args := []interface{}{}
-	argID := 1
+	filter := models.TaskFilter{Statuses: []models.TaskStatus{models.StatusDone}, StatusGroup: "active", Query: "archive"}
+	where, args := buildTaskFilterWhere(filter, 1)
+	if len(args) != 2 {
+		t.Fatalf("expected 2 args (status + q), got %d: %v", len(args), args)
+	}
+	if args[0] != models.StatusDone || !strings.Contains(where, "status = $1") {
+		t.Fatalf("expected exact status priority, got %s %v", where, args)
+	}
+}
 
-	if filter.Status != nil {
-		conditions = append(conditions, "status = $")
-		args = append(args, *filter.Status)
-		argID++
-	} else {
-		_ = taskStatusesFromGroup(filter.StatusGroup)
-	}
-	if strings.TrimSpace(filter.Query) != "" {
-		conditions = append(conditions, "q")
-		args = append(args, "%"+strings.ToLower(strings.TrimSpace(filter.Query))+"%")
-		argID++
+func TestBuildTaskFilterWhere_MultipleStatuses(t *testing.T) {
+	filter := models.TaskFilter{Statuses: []models.TaskStatus{models.StatusNew, models.StatusInProgress}, StatusGroup: "closed"}
+	where, args := buildTaskFilterWhere(filter, 1)
+	if !strings.Contains(where, "status = ANY($1)") {
+		t.Fatalf("unexpected where clause: %s", where)
 	}
-	if len(args) != 2 {
-		t.Fatalf("expected 2 args (status + q), got %d", len(args))
+	if len(args) != 1 {
+		t.Fatalf("expected one array arg, got %v", args)
+	}
+	v, err := args[0].(driver.Valuer).Value()
+	if err != nil || v != "{\"new\",\"in_progress\"}" {
+		t.Fatalf("unexpected status array %v (%v)", v, err)
+	}
+}
+
+func TestBuildTaskFilterWhere_ParticipantMatchesCreatorOrAssignee(t *testing.T) {
+	uid := int64(7)
+	where, args := buildTaskFilterWhere(models.TaskFilter{ParticipantID: &uid}, 1)
+	if !strings.Contains(where, "(creator_id = $1 OR EXISTS (SELECT 1 FROM task_assignees ta WHERE ta.task_id = tasks.id AND ta.user_id = $1))") {
+		t.Fatalf("unexpected where clause: %s", where)
+	}
+	if len(args) != 1 || args[0] != uid {
+		t.Fatalf("unexpected args: %v", args)
+	}
+}
+
+func TestBuildTaskFilterWhere_Overdue(t *testing.T) {
+	overdue := true
+	where, args := buildTaskFilterWhere(models.TaskFilter{Overdue: &overdue}, 1)
+	if !strings.Contains(where, "due_date < NOW() AND status NOT IN ('done','cancelled')") {
+		t.Fatalf("unexpected where clause: %s", where)
+	}
+	if len(args) != 0 {
+		t.Fatalf("unexpected args: %v", args)
+	}
+	if where, _ := buildTaskFilterWhere(models.TaskFilter{}, 1); strings.Contains(where, "due_date <") {
+		t.Fatalf("overdue clause must be opt-in: %s", where)
+	}
+}
+
+func TestBuildTaskFilterWhere_NotOverdue(t *testing.T) {
+	overdue := false
+	where, args := buildTaskFilterWhere(models.TaskFilter{Overdue: &overdue}, 1)
+	if !strings.Contains(where, "(due_date IS NULL OR due_date >= NOW() OR status IN ('done','cancelled'))") {
+		t.Fatalf("unexpected where clause: %s", where)
 	}
-	if args[0] != models.StatusDone {
-		t.Fatalf("expected exact status priority, got %v", args[0])
+	if strings.Contains(where, "due_date < NOW()") {
+		t.Fatalf("overdue=false must not keep overdue tasks: %s", where)
+	}
+	if len(args) != 0 {
+		t.Fatalf("unexpected args: %v", args)
+	}
+}
+
+func TestBuildTaskFilterWhere_CreatorAndPriority(t *testing.T) {
+	creator := int64(7)
+	priority := models.PriorityUrgent
+	where, args := buildTaskFilterWhere(models.TaskFilter{CreatorID: &creator, Priority: &priority}, 1)
+	if !strings.Contains(where, "creator_id = $1") || !strings.Contains(where, "priority = $2") {
+		t.Fatalf("unexpected where clause: %s", where)
+	}
+	if len(args) != 2 || args[0] != creator || args[1] != priority {
+		t.Fatalf("unexpected args: %v", args)
+	}
+}
+
+func taskRow(id int64, due any) []driver.Value {
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	return []driver.Value{
+		id, int64(1), int64(2), nil, int64(0), "", "task", "",
+		due, nil, nil, nil, "normal", "new", now, now, int64(1), false, nil, nil, "", nil,
+	}
+}
+
+func TestTaskRepository_FindAll_DueDateAscending(t *testing.T) {
+	soon := time.Date(2026, 5, 2, 9, 0, 0, 0, time.UTC)
+	later := soon.Add(72 * time.Hour)
+	// Строки приходят в том порядке, который Postgres выдаст для этого ORDER BY.
+	db := openScriptedDB(t,
+		scriptedStep{
+			kind: "query", query: "ORDER BY due_date ASC NULLS LAST, id ASC", skipArgs: true,
+			columns: make([]string, 22),
+			rows:    [][]driver.Value{taskRow(9, soon), taskRow(4, later), taskRow(2, nil)},
+		},
+		scriptedStep{kind: "query", query: "FROM task_assignees", skipArgs: true, columns: []string{"task_id", "user_id"}},
+	)
+
+	tasks, err := NewTaskRepository(db).FindAll(context.Background(), models.TaskFilter{SortBy: "due_date", Order: "asc"})
+	if err != nil {
+		t.Fatalf("FindAll: %v", err)
+	}
+	if len(tasks) != 3 || tasks[0].ID != 9 || tasks[0].DueDate == nil || !tasks[0].DueDate.Equal(soon) {
+		t.Fatalf("expected the soonest-due task first, got %+v", tasks)
+	}
+	if tasks[2].DueDate != nil {
+		t.Fatalf("task without due date must come last, got %+v", tasks[2])
+	}
+}
+
+func TestTaskRepository_FindAll_InvalidSortFallsBackToCreatedAt(t *testing.T) {
+	db := openScriptedDB(t, scriptedStep{kind: "query", query: "ORDER BY created_at DESC NULLS LAST, id DESC", skipArgs: true, columns: make([]string, 22)})
+	if _, err := NewTaskRepository(db).FindAll(context.Background(), models.TaskFilter{SortBy: "password_hash"}); err != nil {
+		t.Fatalf("invalid sort_by must fall back to created_at: %v", err)
 	}
 }
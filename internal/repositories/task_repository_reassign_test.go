@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestTaskRepository_ReassignTasks_SwapsAssigneeInOneTx(t *testing.T) {
+	db := openScriptedDB(t,
+		scriptedStep{kind: "begin"},
+		scriptedStep{
+			kind:    "query",
+			query:   "WHERE id = ANY($3) AND status NOT IN ('done','cancelled')",
+			args:    []any{int64(7), int64(9), pq.Array([]int64{1, 2, 3})},
+			columns: []string{"id"},
+			rows:    [][]driver.Value{{int64(1)}, {int64(3)}},
+		},
+		scriptedStep{
+			kind:  "exec",
+			query: "INSERT INTO task_assignees (task_id, user_id) SELECT task_id, $2 FROM task_assignees WHERE user_id = $1 AND task_id = ANY($3)",
+			args:  []any{int64(7), int64(9), pq.Array([]int64{1, 3})},
+		},
+		scriptedStep{
+			kind:  "exec",
+			query: "DELETE FROM task_assignees WHERE user_id = $1 AND task_id = ANY($2)",
+			args:  []any{int64(7), pq.Array([]int64{1, 3})},
+		},
+		scriptedStep{kind: "commit"},
+	)
+
+	moved, err := NewTaskRepository(db).ReassignTasks(context.Background(), []int64{1, 2, 3}, 7, 9)
+	if err != nil {
+		t.Fatalf("ReassignTasks: %v", err)
+	}
+	if len(moved) != 2 || moved[0] != 1 || moved[1] != 3 {
+		t.Fatalf("expected tasks 1 and 3 moved, got %v", moved)
+	}
+}
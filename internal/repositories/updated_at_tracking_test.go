@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+	"time"
+
+	"turcompany/internal/models"
+)
+
+func TestLeadRepository_UpdateBumpsUpdatedAtKeepsCreatedAt(t *testing.T) {
+	createdAt := time.Date(2026, time.March, 1, 9, 0, 0, 0, time.UTC)
+	updatedAt := time.Date(2026, time.April, 2, 15, 30, 0, 0, time.UTC)
+
+	driverName := fmt.Sprintf("scripted-lead-updated-at-%d", time.Now().UnixNano())
+	mockDriver := &scriptedDriver{
+		steps: []scriptedStep{
+			{
+				kind:  "exec",
+				query: "status = $7, updated_at = NOW() WHERE id = $8",
+				args:  []any{"Lead", "", "", "", int64(5), (*int)(nil), "in_progress", int64(11), (*int)(nil)},
+			},
+			{
+				kind:  "query",
+				query: "SELECT l.id, l.title, l.description, l.phone, l.source, l.created_at, l.updated_at",
+				args:  []any{int64(11)},
+				columns: []string{
+					"id", "title", "description", "phone", "source", "created_at", "updated_at", "owner_id", "branch_id", "branch_name",
+					"department_id", "funnel_id", "status", "is_archived", "archived_at", "archived_by", "archive_reason", "score",
+				},
+				rows: [][]driver.Value{{int64(11), "Lead", "", nil, nil, createdAt, updatedAt, int64(5), nil, "", nil, nil, "in_progress", false, nil, nil, nil, nil}},
+			},
+		},
+	}
+	sql.Register(driverName, mockDriver)
+	db, err := sql.Open(driverName, "")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewLeadRepository(db)
+	if err := repo.Update(context.Background(), &models.Leads{ID: 11, Title: "Lead", OwnerID: 5, Status: "in_progress", CreatedAt: createdAt}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	got, err := repo.GetByID(context.Background(), 11)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if !got.CreatedAt.Equal(createdAt) {
+		t.Fatalf("created_at changed: got %v want %v", got.CreatedAt, createdAt)
+	}
+	if !got.UpdatedAt.After(got.CreatedAt) {
+		t.Fatalf("expected updated_at %v to be after created_at %v", got.UpdatedAt, got.CreatedAt)
+	}
+	if !mockDriver.consumedAll() {
+		t.Fatal("not all scripted steps were consumed")
+	}
+}
+
+func TestDealRepository_UpdateBumpsUpdatedAtKeepsCreatedAt(t *testing.T) {
+	createdAt := time.Date(2026, time.March, 1, 9, 0, 0, 0, time.UTC)
+	updatedAt := time.Date(2026, time.April, 2, 15, 30, 0, 0, time.UTC)
+
+	driverName := fmt.Sprintf("scripted-deal-updated-at-%d", time.Now().UnixNano())
+	mockDriver := &scriptedDriver{
+		steps: []scriptedStep{
+			{
+				kind:  "exec",
+				query: "SET lead_id=$1, client_id=$2, owner_id=$3, branch_id=$4, amount=$5, currency=$6, status=$7, updated_at=NOW() WHERE id=$8",
+				args:  []any{int64(3), int64(4), int64(5), (*int)(nil), float64(100), "KZT", "negotiation", int64(21)},
+			},
+			{
+				kind:  "query",
+				query: "d.status, d.created_at, d.updated_at, d.is_archived",
+				args:  []any{int64(21)},
+				columns: []string{
+					"id", "lead_id", "client_id", "client_type", "owner_id", "branch_id", "branch_name", "department_id", "funnel_id",
+					"amount", "currency", "status", "created_at", "updated_at", "is_archived", "archived_at", "archived_by", "archive_reason",
+				},
+				rows: [][]driver.Value{{int64(21), int64(3), int64(4), "individual", int64(5), nil, "", nil, nil, float64(100), "KZT", "negotiation", createdAt, updatedAt, false, nil, nil, nil}},
+			},
+		},
+	}
+	sql.Register(driverName, mockDriver)
+	db, err := sql.Open(driverName, "")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewDealRepository(db)
+	if err := repo.Update(context.Background(), &models.Deals{ID: 21, LeadID: 3, ClientID: 4, OwnerID: 5, Amount: 100, Currency: "KZT", Status: "negotiation", CreatedAt: createdAt}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	got, err := repo.GetByID(context.Background(), 21)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if !got.CreatedAt.Equal(createdAt) {
+		t.Fatalf("created_at changed: got %v want %v", got.CreatedAt, createdAt)
+	}
+	if !got.UpdatedAt.Equal(updatedAt) || !got.UpdatedAt.After(got.CreatedAt) {
+		t.Fatalf("expected updated_at %v after created_at %v", got.UpdatedAt, got.CreatedAt)
+	}
+	if !mockDriver.consumedAll() {
+		t.Fatal("not all scripted steps were consumed")
+	}
+}
@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+)
+
+// UserNotificationRepository holds the per-user task email opt-in
+// (users.notify_tasks_email). Telegram settings stay in UserRepository.
+type UserNotificationRepository struct {
+	db *sql.DB
+}
+
+func NewUserNotificationRepository(db *sql.DB) *UserNotificationRepository {
+	return &UserNotificationRepository{db: db}
+}
+
+// GetTaskEmailSettings returns the user's email and whether task emails are
+// enabled. Deactivated users are reported as not opted in.
+func (r *UserNotificationRepository) GetTaskEmailSettings(ctx context.Context, userID int64) (string, bool, error) {
+	var email sql.NullString
+	var notify bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT email,
+		       CASE WHEN COALESCE(is_active, TRUE) THEN notify_tasks_email ELSE FALSE END
+		FROM users
+		WHERE id=$1
+	`, userID).Scan(&email, &notify)
+	if err != nil {
+		return "", false, notFoundOr(err)
+	}
+	return email.String, notify, nil
+}
+
+func (r *UserNotificationRepository) SetTaskEmailNotify(ctx context.Context, userID int64, enable bool) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE users SET notify_tasks_email=$1, updated_at=NOW() WHERE id=$2`, enable, userID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
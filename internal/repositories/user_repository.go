@@ -3,9 +3,12 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"strings"
 	"time"
 
+	"github.com/lib/pq"
+
 	"turcompany/internal/models"
 )
 
@@ -15,7 +18,10 @@ type UserRepository interface {
 	Update(user *models.User) error
 	ApplyUserPatch(userID int, patch *models.UserApprovalUpdatePayload) error
 	Delete(id int) error
+	Reactivate(id int) error
 	List(limit, offset int) ([]*models.User, error)
+	Search(filter UserSearchFilter, limit, offset int) ([]*models.User, error)
+	CountSearch(filter UserSearchFilter) (int, error)
 	GetByEmail(email string) (*models.User, error)
 	GetAuthByEmail(email string) (*models.User, error)
 	GetCount() (int, error)
@@ -84,7 +90,7 @@ func (r *userRepository) GetByID(id int) (*models.User, error) {
 	`
 	u, d := &models.User{}, &userDBFields{}
 	if err := r.DB.QueryRow(q, id).Scan(d.dest(u)...); err != nil {
-		return nil, err
+		return nil, notFoundOr(err)
 	}
 	d.apply(u)
 	return u, nil
@@ -143,6 +149,11 @@ func (r *userRepository) Delete(id int) error {
 			refresh_revoked=TRUE,
 			telegram_chat_id=NULL,
 			notify_tasks_telegram=FALSE,
+			deleted_at=COALESCE(deleted_at, NOW()),
+			deleted_email=CASE
+				WHEN email LIKE 'deleted-user-%@deleted.local' THEN deleted_email
+				ELSE email
+			END,
 			email=CASE
 				WHEN email LIKE 'deleted-user-%@deleted.local' THEN email
 				ELSE 'deleted-user-' || id::text || '-' || EXTRACT(EPOCH FROM NOW())::bigint::text || '@deleted.local'
@@ -153,6 +164,32 @@ func (r *userRepository) Delete(id int) error {
 	return err
 }
 
+// Reactivate restores a deleted or blocked user, putting back the email that
+// Delete anonymised. Returns sql.ErrNoRows when the user does not exist.
+func (r *userRepository) Reactivate(id int) error {
+	const q = `
+		UPDATE users
+		SET is_active=TRUE,
+			deleted_at=NULL,
+			email=COALESCE(deleted_email, email),
+			deleted_email=NULL,
+			updated_at=NOW()
+		WHERE id=$1
+	`
+	res, err := r.DB.Exec(q, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
 func (r *userRepository) UpdatePassword(userID int, passwordHash string) error {
 	_, err := r.DB.Exec(`UPDATE users SET password_hash=$1, refresh_token=NULL, refresh_expires_at=NULL, refresh_revoked=TRUE WHERE id=$2`, passwordHash, userID)
 	return err
@@ -190,6 +227,90 @@ func (r *userRepository) List(limit, offset int) ([]*models.User, error) {
 	return res, rows.Err()
 }
 
+// UserSearchFilter narrows Search and CountSearch. Inactive users are always
+// excluded.
+type UserSearchFilter struct {
+	RoleID *int
+	// Query matches email or company_name, case-insensitively.
+	Query    string
+	BranchID *int
+	// ExcludeRoleIDs hides users with these roles (e.g. leadership for
+	// non-leadership viewers).
+	ExcludeRoleIDs []int
+}
+
+// Search lists active users matching filter.
+func (r *userRepository) Search(filter UserSearchFilter, limit, offset int) ([]*models.User, error) {
+	where, args := buildUserSearchWhere(filter)
+	args = append(args, limit, offset)
+	query := `
+		SELECT
+			id, company_name, bin_iin, first_name, last_name, middle_name, position,
+			email, '' as password_hash, role_id, branch_id, department_id, is_active,
+			NULL as refresh_token, NULL as refresh_expires_at, FALSE as refresh_revoked,
+			phone, address, extra_info, avatar_url, avatar_path, avatar_original_path,
+			avatar_crop_x, avatar_crop_y, avatar_crop_scale, avatar_crop_size,
+			is_verified, verified_at, updated_at,
+			COALESCE(telegram_chat_id,0), COALESCE(notify_tasks_telegram,TRUE)
+		FROM users
+		WHERE ` + where + fmt.Sprintf(`
+		ORDER BY id
+		LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := make([]*models.User, 0)
+	for rows.Next() {
+		u, d := &models.User{}, &userDBFields{}
+		if err := rows.Scan(d.dest(u)...); err != nil {
+			return nil, err
+		}
+		d.apply(u)
+		res = append(res, u)
+	}
+	return res, rows.Err()
+}
+
+func (r *userRepository) CountSearch(filter UserSearchFilter) (int, error) {
+	where, args := buildUserSearchWhere(filter)
+	var c int
+	err := r.DB.QueryRow(`SELECT COUNT(*) FROM users WHERE `+where, args...).Scan(&c)
+	return c, err
+}
+
+func buildUserSearchWhere(filter UserSearchFilter) (string, []interface{}) {
+	clauses := []string{"COALESCE(is_active, TRUE) = TRUE"}
+	args := make([]interface{}, 0, 4)
+	if filter.RoleID != nil {
+		args = append(args, *filter.RoleID)
+		clauses = append(clauses, fmt.Sprintf("role_id = $%d", len(args)))
+	}
+	if filter.BranchID != nil {
+		args = append(args, *filter.BranchID)
+		clauses = append(clauses, fmt.Sprintf("branch_id = $%d", len(args)))
+	}
+	if len(filter.ExcludeRoleIDs) > 0 {
+		args = append(args, pq.Array(filter.ExcludeRoleIDs))
+		clauses = append(clauses, fmt.Sprintf("NOT (role_id = ANY($%d))", len(args)))
+	}
+	if q := strings.TrimSpace(filter.Query); q != "" {
+		args = append(args, "%"+escapeLikePattern(q)+"%")
+		n := len(args)
+		clauses = append(clauses, fmt.Sprintf(`(email ILIKE $%d ESCAPE '\' OR COALESCE(company_name, '') ILIKE $%d ESCAPE '\')`, n, n))
+	}
+	return strings.Join(clauses, " AND "), args
+}
+
+// likePatternEscaper makes %, _ and the escape character itself literal in a
+// LIKE/ILIKE pattern used with ESCAPE '\'.
+var likePatternEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLikePattern(s string) string {
+	return likePatternEscaper.Replace(s)
+}
+
 func (r *userRepository) GetByEmail(email string) (*models.User, error) {
 	const q = `
 		SELECT
@@ -204,7 +325,7 @@ func (r *userRepository) GetByEmail(email string) (*models.User, error) {
 	`
 	u, d := &models.User{}, &userDBFields{}
 	if err := r.DB.QueryRow(q, email).Scan(d.dest(u)...); err != nil {
-		return nil, err
+		return nil, notFoundOr(err)
 	}
 	d.apply(u)
 	return u, nil
@@ -239,7 +360,7 @@ func (r *userRepository) GetAuthByEmail(email string) (*models.User, error) {
 	`
 	u, d := &models.User{}, &userAuthDBFields{}
 	if err := r.DB.QueryRow(q, email).Scan(d.dest(u)...); err != nil {
-		return nil, err
+		return nil, notFoundOr(err)
 	}
 	d.apply(u)
 	return u, nil
@@ -430,7 +551,7 @@ func (r *userRepository) GetByIDSimple(id int) (*models.User, error) {
 	var tgChatID sql.NullInt64
 	var tgNotify sql.NullBool
 	if err := row.Scan(&u.ID, &u.Email, &tgChatID, &tgNotify); err != nil {
-		return nil, err
+		return nil, notFoundOr(err)
 	}
 	if tgChatID.Valid {
 		u.TelegramChatID = tgChatID.Int64
@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func TestUserRepository_SearchAndCountShareTheFilter(t *testing.T) {
+	roleID, branchID := 10, 2
+	filter := UserSearchFilter{RoleID: &roleID, BranchID: &branchID, ExcludeRoleIDs: []int{40}, Query: "acme"}
+	where := "WHERE COALESCE(is_active, TRUE) = TRUE AND role_id = $1 AND branch_id = $2 " +
+		"AND NOT (role_id = ANY($3)) " +
+		`AND (email ILIKE $4 ESCAPE '\' OR COALESCE(company_name, '') ILIKE $4 ESCAPE '\')`
+	filterArgs := []any{int64(roleID), int64(branchID), pq.Array([]int{40}), "%acme%"}
+
+	driverName := fmt.Sprintf("scripted-user-count-%d", time.Now().UnixNano())
+	mockDriver := &scriptedDriver{steps: []scriptedStep{
+		{
+			kind:    "query",
+			query:   "FROM users " + where + " ORDER BY id LIMIT $5 OFFSET $6",
+			args:    append(append([]any{}, filterArgs...), int64(3), int64(6)),
+			columns: []string{"id"},
+		},
+		{
+			kind:    "query",
+			query:   "SELECT COUNT(*) FROM users " + where,
+			args:    filterArgs,
+			columns: []string{"count"},
+			rows:    [][]driver.Value{{int64(7)}},
+		},
+	}}
+	sql.Register(driverName, mockDriver)
+	db, err := sql.Open(driverName, "")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+	repo := NewUserRepository(db)
+
+	if _, err := repo.Search(filter, 3, 6); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	total, err := repo.CountSearch(filter)
+	if err != nil {
+		t.Fatalf("CountSearch: %v", err)
+	}
+	if total != 7 {
+		t.Fatalf("expected total 7, got %d", total)
+	}
+	if !mockDriver.consumedAll() {
+		t.Fatal("expected both the page and the count query to run")
+	}
+}
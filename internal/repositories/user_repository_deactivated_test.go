@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestUserRepository_ListHidesDeactivatedUsers(t *testing.T) {
+	driverName := fmt.Sprintf("scripted-user-list-active-%d", time.Now().UnixNano())
+	mockDriver := &scriptedDriver{steps: []scriptedStep{{
+		kind:    "query",
+		query:   "FROM users WHERE COALESCE(is_active, TRUE) = TRUE ORDER BY id LIMIT $1 OFFSET $2",
+		args:    []any{int64(20), int64(0)},
+		columns: []string{"id"},
+	}}}
+	sql.Register(driverName, mockDriver)
+	db, err := sql.Open(driverName, "")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+
+	users, err := NewUserRepository(db).List(20, 0)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(users) != 0 {
+		t.Fatalf("unexpected users: %+v", users)
+	}
+	if !mockDriver.consumedAll() {
+		t.Fatal("expected the active-only list query to run")
+	}
+}
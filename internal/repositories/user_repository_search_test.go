@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildUserSearchWhere_RoleFilter(t *testing.T) {
+	roleID := 30
+	where, args := buildUserSearchWhere(UserSearchFilter{RoleID: &roleID})
+	if !strings.Contains(where, "role_id = $1") {
+		t.Fatalf("expected role clause, got: %s", where)
+	}
+	if !strings.Contains(where, "COALESCE(is_active, TRUE) = TRUE") {
+		t.Fatalf("inactive users must stay hidden, got: %s", where)
+	}
+	if len(args) != 1 || args[0] != 30 {
+		t.Fatalf("unexpected args: %#v", args)
+	}
+}
+
+func TestBuildUserSearchWhere_PartialEmailOrCompany(t *testing.T) {
+	roleID := 10
+	where, args := buildUserSearchWhere(UserSearchFilter{RoleID: &roleID, Query: "  anna@exa "})
+	if !strings.Contains(where, `(email ILIKE $2 ESCAPE '\' OR COALESCE(company_name, '') ILIKE $2 ESCAPE '\')`) {
+		t.Fatalf("expected case-insensitive email/company match, got: %s", where)
+	}
+	if len(args) != 2 || args[1] != "%anna@exa%" {
+		t.Fatalf("unexpected args: %#v", args)
+	}
+
+	where, args = buildUserSearchWhere(UserSearchFilter{Query: "acme"})
+	if strings.Contains(where, "role_id") || len(args) != 1 || args[0] != "%acme%" {
+		t.Fatalf("unexpected where/args without role: %s %#v", where, args)
+	}
+}
+
+func TestBuildUserSearchWhere_EscapesLikeWildcards(t *testing.T) {
+	_, args := buildUserSearchWhere(UserSearchFilter{Query: `%`})
+	if len(args) != 1 || args[0] != `%\%%` {
+		t.Fatalf("a bare %% must match only a literal percent sign, got %#v", args)
+	}
+	_, args = buildUserSearchWhere(UserSearchFilter{Query: `a_b\c`})
+	if len(args) != 1 || args[0] != `%a\_b\\c%` {
+		t.Fatalf("unexpected escaped pattern: %#v", args)
+	}
+}
+
+func TestBuildUserSearchWhere_BranchScopeAndExcludedRoles(t *testing.T) {
+	branchID := 2
+	where, args := buildUserSearchWhere(UserSearchFilter{BranchID: &branchID, ExcludeRoleIDs: []int{40}})
+	if !strings.Contains(where, "branch_id = $1") || !strings.Contains(where, "NOT (role_id = ANY($2))") {
+		t.Fatalf("expected branch scope and role exclusion, got: %s", where)
+	}
+	if len(args) != 2 {
+		t.Fatalf("unexpected args: %#v", args)
+	}
+}
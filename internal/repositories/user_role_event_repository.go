@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"turcompany/internal/models"
+)
+
+type UserRoleEventRepository interface {
+	// ChangeRole sets the user's role and records the change in one
+	// transaction. It returns sql.ErrNoRows when the user does not exist and a
+	// nil event when the role is already newRoleID.
+	ChangeRole(ctx context.Context, userID, actorID, newRoleID int) (*models.UserRoleEvent, error)
+}
+
+type userRoleEventRepository struct {
+	DB *sql.DB
+}
+
+func NewUserRoleEventRepository(db *sql.DB) UserRoleEventRepository {
+	return &userRoleEventRepository{DB: db}
+}
+
+func (r *userRoleEventRepository) ChangeRole(ctx context.Context, userID, actorID, newRoleID int) (event *models.UserRoleEvent, err error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var oldRoleID int
+	if err = tx.QueryRowContext(ctx, `SELECT role_id FROM users WHERE id=$1 FOR UPDATE`, userID).Scan(&oldRoleID); err != nil {
+		return nil, err
+	}
+	if oldRoleID == newRoleID {
+		return nil, tx.Commit()
+	}
+
+	if _, err = tx.ExecContext(ctx, `UPDATE users SET role_id=$1, updated_at=NOW() WHERE id=$2`, newRoleID, userID); err != nil {
+		return nil, err
+	}
+
+	event = &models.UserRoleEvent{UserID: userID, ActorID: actorID, OldRoleID: oldRoleID, NewRoleID: newRoleID}
+	const q = `
+		INSERT INTO user_role_events (user_id, actor_id, old_role_id, new_role_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+	if err = tx.QueryRowContext(ctx, q, userID, actorID, oldRoleID, newRoleID).Scan(&event.ID, &event.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
@@ -164,3 +164,41 @@ func (r *UserVerificationRepository) ExpireNow(id int64) error {
 	_, err := r.DB.Exec(`UPDATE user_verifications SET expires_at = NOW() WHERE id=$1`, id)
 	return err
 }
+
+// CountExhaustedSince — сколько кодов сожжено превышением попыток с момента since.
+func (r *UserVerificationRepository) CountExhaustedSince(userID int, since time.Time, maxAttempts int) (int, error) {
+	const q = `
+		SELECT COUNT(*)
+		FROM user_verifications
+		WHERE user_id = $1 AND sent_at >= $2 AND attempts >= $3 AND confirmed = FALSE
+	`
+	var c int
+	if err := r.DB.QueryRow(q, userID, since, maxAttempts).Scan(&c); err != nil {
+		return 0, fmt.Errorf("user_verification count exhausted: %w", err)
+	}
+	return c, nil
+}
+
+// GetLockedUntil — текущая блокировка пользователя (nil, если не заблокирован).
+func (r *UserVerificationRepository) GetLockedUntil(userID int) (*time.Time, error) {
+	var until sql.NullTime
+	if err := r.DB.QueryRow(`SELECT locked_until FROM users WHERE id = $1`, userID).Scan(&until); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("user_verification locked_until: %w", err)
+	}
+	if !until.Valid {
+		return nil, nil
+	}
+	t := until.Time
+	return &t, nil
+}
+
+// LockUser — блокирует подтверждение и повторную отправку до until.
+func (r *UserVerificationRepository) LockUser(userID int, until time.Time) error {
+	if _, err := r.DB.Exec(`UPDATE users SET locked_until = $2 WHERE id = $1`, userID, until); err != nil {
+		return fmt.Errorf("user_verification lock user: %w", err)
+	}
+	return nil
+}
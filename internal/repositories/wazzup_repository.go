@@ -507,7 +507,8 @@ func (r *wazzupRepository) CreateLeadFromInbound(ctx context.Context, ownerID in
 func (r *wazzupRepository) UpdateLeadDescriptionIfEmpty(ctx context.Context, leadID int, firstMessage string) error {
 	const q = `
 		UPDATE leads
-		SET description = $1
+		SET description = $1,
+		    updated_at = NOW()
 		WHERE id = $2
 		  AND (description IS NULL OR BTRIM(description) = '')
 	`
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+
+	"turcompany/internal/models"
+)
+
+type WebhookRepository interface {
+	Create(ctx context.Context, sub *models.WebhookSubscription) error
+	List(ctx context.Context) ([]models.WebhookSubscription, error)
+	// Delete reports whether a subscription with the given id existed.
+	Delete(ctx context.Context, id int64) (bool, error)
+	// ListActiveForEvent returns active subscriptions listening to event,
+	// including those with an empty event_types list (all events).
+	ListActiveForEvent(ctx context.Context, event string) ([]models.WebhookSubscription, error)
+	InsertDeadLetter(ctx context.Context, dl *models.WebhookDeadLetter) error
+}
+
+type webhookRepository struct {
+	DB *sql.DB
+}
+
+func NewWebhookRepository(db *sql.DB) WebhookRepository {
+	return &webhookRepository{DB: db}
+}
+
+const webhookSubscriptionColumns = `id, url, secret, event_types, is_active, created_by, created_at`
+
+func (r *webhookRepository) Create(ctx context.Context, sub *models.WebhookSubscription) error {
+	const q = `
+INSERT INTO webhook_subscriptions (url, secret, event_types, is_active, created_by)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, created_at
+`
+	eventTypes := sub.EventTypes
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	return r.DB.QueryRowContext(ctx, q, sub.URL, sub.Secret, pq.Array(eventTypes), sub.IsActive, sub.CreatedBy).
+		Scan(&sub.ID, &sub.CreatedAt)
+}
+
+func (r *webhookRepository) List(ctx context.Context) ([]models.WebhookSubscription, error) {
+	return r.query(ctx, `SELECT `+webhookSubscriptionColumns+` FROM webhook_subscriptions ORDER BY id`)
+}
+
+func (r *webhookRepository) Delete(ctx context.Context, id int64) (bool, error) {
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *webhookRepository) ListActiveForEvent(ctx context.Context, event string) ([]models.WebhookSubscription, error) {
+	return r.query(ctx, `
+SELECT `+webhookSubscriptionColumns+`
+FROM webhook_subscriptions
+WHERE is_active AND (cardinality(event_types) = 0 OR $1 = ANY(event_types))
+ORDER BY id
+`, event)
+}
+
+func (r *webhookRepository) InsertDeadLetter(ctx context.Context, dl *models.WebhookDeadLetter) error {
+	const q = `
+INSERT INTO webhook_dead_letters (subscription_id, event_type, payload, attempts, last_error)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, created_at
+`
+	return r.DB.QueryRowContext(ctx, q, dl.SubscriptionID, dl.EventType, []byte(dl.Payload), dl.Attempts, dl.LastError).
+		Scan(&dl.ID, &dl.CreatedAt)
+}
+
+func (r *webhookRepository) query(ctx context.Context, q string, args ...any) ([]models.WebhookSubscription, error) {
+	rows, err := r.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.WebhookSubscription
+	for rows.Next() {
+		var (
+			sub       models.WebhookSubscription
+			createdBy sql.NullInt64
+		)
+		if err := rows.Scan(&sub.ID, &sub.URL, &sub.Secret, pq.Array(&sub.EventTypes), &sub.IsActive, &createdBy, &sub.CreatedAt); err != nil {
+			return nil, err
+		}
+		if createdBy.Valid {
+			v := int(createdBy.Int64)
+			sub.CreatedBy = &v
+		}
+		out = append(out, sub)
+	}
+	return out, rows.Err()
+}
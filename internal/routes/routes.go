@@ -9,6 +9,13 @@ import (
 	"turcompany/internal/middleware"
 )
 
+// RateLimits are the per-group throttles built from config.RateLimit.
+// A nil field leaves the group unthrottled.
+type RateLimits struct {
+	SMS       gin.HandlerFunc // отправка и подтверждение SMS-кодов
+	Documents gin.HandlerFunc // группа /documents (генерация, загрузка)
+}
+
 func SetupRoutes(
 	r *gin.Engine,
 	userHandler *handlers.UserHandler,
@@ -45,9 +52,23 @@ func SetupRoutes(
 	docVersionHandler *handlers.DocumentVersionHandler,
 	feedHandler *handlers.FeedHandler,
 	approvalHandler *handlers.UserApprovalHandler, // может быть nil
-	feedEventHandler *handlers.FeedEventHandler,   // может быть nil
+	feedEventHandler *handlers.FeedEventHandler, // может быть nil
+	webhookHandler *handlers.WebhookHandler, // может быть nil
+	idempotency gin.HandlerFunc, // может быть nil; Idempotency-Key для POST /tasks и POST /deals
+	uploads gin.HandlerFunc, // может быть nil; лимит размера тела на маршрутах загрузки файлов
+	rateLimits RateLimits,
 	authMiddleware gin.HandlerFunc,
 ) *gin.Engine {
+	passThrough := func(c *gin.Context) { c.Next() }
+	if rateLimits.SMS == nil {
+		rateLimits.SMS = passThrough
+	}
+	if rateLimits.Documents == nil {
+		rateLimits.Documents = passThrough
+	}
+	if uploads == nil {
+		uploads = passThrough
+	}
 
 	// =====================
 	// PUBLIC (no JWT)
@@ -71,7 +92,8 @@ func SetupRoutes(
 
 	r.POST("/register", userHandler.Register)
 	r.POST("/register/confirm", verifyHandler.ConfirmUser)
-	r.POST("/register/resend", verifyHandler.ResendUser)
+	r.POST("/register/resend", rateLimits.SMS, verifyHandler.ResendUser)
+	r.GET("/verify-email", verifyHandler.VerifyEmail)
 
 	if signHandler != nil {
 		signPublic := r.Group("/api/v1/sign/sessions")
@@ -95,7 +117,7 @@ func SetupRoutes(
 		r.GET("/api/v1/sign/sms/verify", signConfirmHandler.VerifySMSToken)
 		r.GET("/api/v1/sign/sms/preview", signConfirmHandler.PreviewBySMSToken)
 		r.POST("/documents/:id/sign/confirm/email", signConfirmHandler.ConfirmByEmailCode)
-		r.POST("/documents/:id/sign/confirm/sms", signConfirmHandler.ConfirmBySMSCode)
+		r.POST("/documents/:id/sign/confirm/sms", rateLimits.SMS, signConfirmHandler.ConfirmBySMSCode)
 	}
 	if telegramSignHandler != nil {
 		r.POST("/telegram/webhook", telegramSignHandler.Handle)
@@ -129,12 +151,30 @@ func SetupRoutes(
 		r.GET("/api/v1/public/organization/contacts", orgHandler.GetPublicContacts)
 	}
 
+	// Chat WebSocket: браузер не может передать Authorization при апгрейде,
+	// поэтому кроме JWT принимается одноразовый ?ticket= (GET /chats/ws-ticket).
+	r.GET("/chats/:id/ws",
+		chatHandler.StreamTicketAuth(authMiddleware),
+		middleware.ReadOnlyGuard(),
+		middleware.RequirePermission("chat.view", "chat"),
+		chatHandler.Stream,
+	)
+
 	// =====================
 	// PROTECTED (JWT)
 	// =====================
 	r.Use(authMiddleware)
 	r.Use(middleware.ReadOnlyGuard())
 
+	// Logout and /auth/me are registered here rather than in the public /auth
+	// group: they need the caller's token.
+	r.POST("/auth/logout", authHandler.Logout)
+	r.GET("/auth/me", authHandler.Me)
+
+	if idempotency == nil {
+		idempotency = passThrough
+	}
+
 	if signHandler != nil {
 		signProtected := r.Group("/api/v1/sign/sessions")
 		{
@@ -224,7 +264,10 @@ func SetupRoutes(
 	{
 		profile.GET("", userHandler.GetProfile)
 		profile.PATCH("", userHandler.UpdateProfile)
-		profile.POST("/avatar", userHandler.UploadProfileAvatar)
+		profile.POST("/password", userHandler.ChangeMyPassword)
+		profile.GET("/notifications", userHandler.GetMyNotifications)
+		profile.PUT("/notifications", userHandler.UpdateMyNotifications)
+		profile.POST("/avatar", uploads, userHandler.UploadProfileAvatar)
 		profile.PATCH("/avatar/crop", userHandler.UpdateProfileAvatarCrop)
 		profile.DELETE("/avatar", userHandler.DeleteProfileAvatar)
 		profile.GET("/avatar/content", userHandler.ServeMyAvatar)
@@ -234,6 +277,7 @@ func SetupRoutes(
 	{
 		users.POST("", middleware.RequirePermission("users.create", "user"), userHandler.CreateUser)
 		users.GET("/me", userHandler.GetMyProfile)
+		users.GET("/me/tasks/summary", taskHandler.MySummary)
 		users.GET("/count", middleware.RequirePermission("users.view", "user"), userHandler.GetUserCount)
 		users.GET("/count/role/:role_id", middleware.RequirePermission("users.view", "user"), userHandler.GetUserCountByRole)
 		users.GET("", middleware.RequirePermission("users.view", "user"), userHandler.ListUsers)
@@ -245,6 +289,9 @@ func SetupRoutes(
 		// Блокировка/разблокировка — прямое действие для юриста (без подтверждения)
 		users.POST("/:id/block", middleware.RequirePermission("users.block", "user"), userHandler.BlockUser)
 		users.POST("/:id/unblock", middleware.RequirePermission("users.block", "user"), userHandler.UnblockUser)
+		users.POST("/:id/reactivate", middleware.RequirePermission("users.delete", "user"), userHandler.ReactivateUser)
+		users.POST("/:id/resend-welcome", middleware.RequireRoles(authz.RoleSystemAdmin), userHandler.ResendWelcome)
+		users.POST("/:id/role", middleware.RequireRoles(authz.RoleSystemAdmin), userHandler.ChangeUserRole)
 	}
 
 	// USER APPROVAL REQUESTS — запросы юриста/HR на create/delete, одобряемые администратором
@@ -263,6 +310,16 @@ func SetupRoutes(
 		}
 	}
 
+	// OUTBOUND WEBHOOKS — подписки на события задач (только админ)
+	if webhookHandler != nil {
+		webhooks := r.Group("/api/v1/webhooks", middleware.RequireRoles(authz.RoleSystemAdmin))
+		{
+			webhooks.GET("", webhookHandler.List)
+			webhooks.POST("", webhookHandler.Create)
+			webhooks.DELETE("/:id", webhookHandler.Delete)
+		}
+	}
+
 	// BRANCHES — read gated by branches.view (admin + management only);
 	// create/update/delete are admin-only (RequirePermission + handler role check).
 	branches := r.Group("/branches")
@@ -291,6 +348,8 @@ func SetupRoutes(
 		clients.GET("/individual", middleware.RequirePermission("clients.view", "client"), clientHandler.ListIndividuals)
 		clients.GET("/company", middleware.RequirePermission("clients.view", "client"), clientHandler.ListCompanies)
 		clients.GET("/my", middleware.RequirePermission("clients.view", "client"), clientHandler.ListMy)
+		clients.GET("/search", middleware.RequirePermission("clients.view", "client"), clientHandler.Search)
+		clients.POST("/merge", middleware.RequirePermission("clients.delete", "client"), clientHandler.Merge)
 		clients.PUT("/:id", middleware.RequirePermission("clients.update", "client"), clientHandler.Update)
 		clients.PATCH("/:id", middleware.RequirePermission("clients.update", "client"), clientHandler.Patch)
 		clients.DELETE("/:id", middleware.RequirePermission("clients.delete", "client"), clientHandler.Delete)
@@ -301,12 +360,12 @@ func SetupRoutes(
 			clients.GET("/:id/profile", clientProfileHandler.GetProfile)
 		}
 		if clientFilesHandler != nil {
-			clients.POST("/:id/files", clientFilesHandler.Upload)
+			clients.POST("/:id/files", uploads, clientFilesHandler.Upload)
 			clients.GET("/:id/files/primary", clientFilesHandler.ServePrimaryInline)
 			clients.GET("/:id/files/primary/download", clientFilesHandler.ServePrimaryDownload)
 		}
 		if clientAvatarHandler != nil {
-			clients.POST("/:id/avatar", middleware.RequirePermission("clients.update", "client"), clientAvatarHandler.Upload)
+			clients.POST("/:id/avatar", uploads, middleware.RequirePermission("clients.update", "client"), clientAvatarHandler.Upload)
 			clients.PATCH("/:id/avatar/crop", middleware.RequirePermission("clients.update", "client"), clientAvatarHandler.UpdateCrop)
 			clients.DELETE("/:id/avatar", middleware.RequirePermission("clients.update", "client"), clientAvatarHandler.Delete)
 			clients.GET("/:id/avatar/content", clientAvatarHandler.Serve)
@@ -352,6 +411,8 @@ func SetupRoutes(
 		leads.GET("/my", middleware.RequirePermission("leads.view", "lead"), leadHandler.ListMy)
 		leads.POST("/:id/assign", middleware.RequirePermission("leads.update", "lead"), leadHandler.Assign)
 		leads.POST("/:id/status", middleware.RequirePermission("leads.update", "lead"), leadHandler.UpdateStatus)
+		leads.POST("/:id/score", middleware.RequirePermission("leads.update", "lead"), leadHandler.SetScore)
+		leads.GET("/:id/tasks", middleware.RequirePermission("leads.view", "lead"), taskHandler.ListForLead)
 		if funnelHandler != nil {
 			leads.PATCH("/:id/funnel", middleware.RequirePermission(authz.ActionLeadsMoveBetweenFunnels, "lead"), funnelHandler.MoveLeadToFunnel)
 		}
@@ -367,7 +428,7 @@ func SetupRoutes(
 	// DEALS — guarded per action; visa/partner have no deals.* permissions → 403
 	deals := r.Group("/deals")
 	{
-		deals.POST("", middleware.RequirePermission("deals.create", "deal"), dealHandler.Create)
+		deals.POST("", middleware.RequirePermission("deals.create", "deal"), idempotency, dealHandler.Create)
 		deals.GET("/:id", middleware.RequirePermission("deals.view", "deal"), dealHandler.GetByID)
 		deals.PUT("/:id", middleware.RequirePermission("deals.update", "deal"), dealHandler.Update)
 		deals.DELETE("/:id", middleware.RequirePermission("deals.delete", "deal"), dealHandler.Delete)
@@ -378,22 +439,28 @@ func SetupRoutes(
 		deals.POST("/:id/status", middleware.RequirePermission("deals.update", "deal"), dealHandler.UpdateStatus)
 		deals.POST("/:id/move", middleware.RequirePermission("deals.update", "deal"), dealHandler.Move)
 		deals.GET("/:id/history", middleware.RequirePermission("deals.view", "deal"), dealHandler.GetHistory)
+		deals.GET("/:id/tasks", middleware.RequirePermission("deals.view", "deal"), taskHandler.ListForDeal)
+		deals.GET("/:id/detail", middleware.RequirePermission("deals.view", "deal"), taskHandler.DealDetail)
+		deals.GET("/:id/documents", middleware.RequirePermission("documents.view", "document"), documentHandler.ListDealDocuments)
 	}
 
 	// DOCUMENTS — RequirePermission guard per endpoint; public signing routes are above (no JWT)
-	docs := r.Group("/documents")
+	docs := r.Group("/documents", rateLimits.Documents)
 	{
 		docs.GET("", middleware.RequirePermission("documents.view", "document"), documentHandler.ListDocuments)
 		docs.GET("/types", middleware.RequirePermission("documents.view", "document"), documentHandler.ListDocumentTypes)
 		docs.POST("", middleware.RequirePermission("documents.create", "document"), documentHandler.CreateDocument)
-		docs.POST("/upload", middleware.RequirePermission("documents.create", "document"), documentHandler.Upload)
-		docs.POST("/upload-with-meta", middleware.RequirePermission("documents.create", "document"), documentHandler.UploadWithMeta)
+		docs.POST("/upload", uploads, middleware.RequirePermission("documents.create", "document"), documentHandler.Upload)
+		docs.POST("/upload-with-meta", uploads, middleware.RequirePermission("documents.create", "document"), documentHandler.UploadWithMeta)
 		docs.GET("/:id", middleware.RequirePermission("documents.view", "document"), documentHandler.GetDocument)
+		docs.GET("/:id/tasks", middleware.RequirePermission("documents.view", "document"), taskHandler.ListForDocument)
 		docs.DELETE("/:id", middleware.RequirePermission("documents.delete", "document"), documentHandler.DeleteDocument)
 		docs.POST("/:id/archive", middleware.RequirePermission("documents.update", "document"), documentHandler.ArchiveDocument)
 		docs.POST("/:id/unarchive", middleware.RequirePermission("documents.update", "document"), documentHandler.UnarchiveDocument)
 		docs.POST("/create-from-lead", middleware.RequirePermission("documents.create", "document"), documentHandler.CreateDocumentFromLead)
+		docs.POST("/preview", middleware.RequirePermission("documents.create", "document"), documentHandler.PreviewDocumentFromLead)
 		docs.POST("/create-from-client", middleware.RequirePermission("documents.create", "document"), documentHandler.CreateDocumentFromClient)
+		docs.POST("/:id/regenerate", middleware.RequirePermission("documents.create", "document"), documentHandler.RegenerateDocument)
 		docs.GET("/deal/:dealid", middleware.RequirePermission("documents.view", "document"), documentHandler.ListDocumentsByDeal)
 		docs.GET("/:id/file", middleware.RequirePermission("documents.view", "document"), documentHandler.ServeFile)
 		docs.GET("/:id/download", middleware.RequirePermission("documents.download", "document"), documentHandler.Download)
@@ -404,7 +471,7 @@ func SetupRoutes(
 		if signConfirmHandler != nil {
 			docs.POST("/:id/sign/start", middleware.RequirePermission("documents.send", "document"), signConfirmHandler.StartSigning)
 			docs.POST("/:id/sign/start/email", middleware.RequirePermission("documents.send", "document"), signConfirmHandler.StartSigningEmail)
-			docs.POST("/:id/sign/start/sms", middleware.RequirePermission("documents.send", "document"), signConfirmHandler.StartSigningSMS)
+			docs.POST("/:id/sign/start/sms", middleware.RequirePermission("documents.send", "document"), rateLimits.SMS, signConfirmHandler.StartSigningSMS)
 			docs.GET("/:id/sign/contact-options", middleware.RequirePermission("documents.view", "document"), signConfirmHandler.ContactOptions)
 			docs.GET("/:id/sign/status", middleware.RequirePermission("documents.view", "document"), signConfirmHandler.Status)
 			if docPublicLinkHandler != nil {
@@ -416,12 +483,17 @@ func SetupRoutes(
 		}
 		if docVersionHandler != nil {
 			docs.GET("/:id/versions", middleware.RequirePermission("documents.view", "document"), docVersionHandler.ListVersions)
-			docs.POST("/:id/versions", middleware.RequirePermission("documents.update", "document"), docVersionHandler.UploadVersion)
+			docs.POST("/:id/versions", uploads, middleware.RequirePermission("documents.update", "document"), docVersionHandler.UploadVersion)
 			docs.GET("/:id/versions/:vid/file", middleware.RequirePermission("documents.view", "document"), docVersionHandler.ServeVersionFile)
 			docs.POST("/:id/versions/:vid/restore", middleware.RequirePermission("documents.update", "document"), docVersionHandler.RestoreVersion)
 		}
 	}
 
+	// SMS delivery status of the latest signing SMS; document access as above.
+	if signHistoryHandler != nil {
+		r.GET("/sms/status/:document_id", middleware.RequirePermission("documents.view", "document"), signHistoryHandler.GetSMSStatus)
+	}
+
 	// CHATS — gated by chat.view; all roles currently have it, but the guard
 	// blocks future roles without it and provides explicit 403 over silent 200.
 	chats := r.Group("/chats", middleware.RequirePermission("chat.view", "chat"))
@@ -430,8 +502,10 @@ func SetupRoutes(
 		chats.GET("", chatHandler.ListChats)
 		chats.GET("/search", chatHandler.SearchChats)
 		chats.GET("/unread", chatHandler.ListUnread)
+		chats.GET("/ws-ticket", chatHandler.IssueStreamTicket)
 		chats.GET("/status/:id", chatHandler.GetUserStatus)
 		chats.GET("/:id/pins", chatHandler.ListPins)
+		chats.GET("/:id/presence", chatHandler.Presence)
 		chats.GET("/:id/favorites", chatHandler.ListFavorites)
 
 		chats.POST("/personal", chatHandler.CreatePersonalChat)
@@ -440,8 +514,8 @@ func SetupRoutes(
 		chats.POST("/:id/add-members", chatHandler.AddMembers)
 		chats.POST("/:id/leave", chatHandler.LeaveChat)
 		chats.POST("/:id/read", chatHandler.MarkRead)
-		chats.POST("/:id/upload", chatHandler.UploadAttachment)
-		chats.POST("/:id/attachments", chatHandler.UploadAttachmentAlias)
+		chats.POST("/:id/upload", uploads, chatHandler.UploadAttachment)
+		chats.POST("/:id/attachments", uploads, chatHandler.UploadAttachmentAlias)
 
 		chats.DELETE("/:id", middleware.RequirePermission("chat.delete", "chat"), chatHandler.DeleteChat)
 
@@ -456,7 +530,6 @@ func SetupRoutes(
 		chats.POST("/:id/messages/:message_id/favorite", chatHandler.FavoriteMessage)
 		chats.DELETE("/:id/messages/:message_id/favorite", chatHandler.UnfavoriteMessage)
 
-		chats.GET("/:id/ws", chatHandler.Stream)
 		r.GET("/attachments/:id/download", chatHandler.DownloadAttachment)
 	}
 
@@ -474,22 +547,30 @@ func SetupRoutes(
 		),
 	)
 	{
-		tasks.POST("", taskHandler.Create)
+		tasks.POST("", idempotency, taskHandler.Create)
 		tasks.GET("", taskHandler.GetAll)
+		tasks.GET("/agenda", taskHandler.Agenda)
+		tasks.POST("/batch-status", taskHandler.BatchStatus)
+		tasks.POST("/reassign", middleware.RequireRoles(authz.RoleManagement, authz.RoleSystemAdmin), taskHandler.Reassign)
 		tasks.GET("/:id", taskHandler.GetByID)
 		tasks.PUT("/:id", taskHandler.Update)
 		tasks.DELETE("/:id", middleware.RequirePermission("tasks.delete", "task"), taskHandler.Delete)
+		tasks.GET("/:id/transitions", taskHandler.Transitions)
 		tasks.POST("/:id/status", taskHandler.ChangeStatus)
 		tasks.POST("/:id/assign", taskHandler.Assign)
 		tasks.POST("/:id/complete", taskHandler.Complete)
 		tasks.POST("/:id/remind-later", taskHandler.RemindLater)
 		tasks.POST("/:id/archive", taskHandler.Archive)
 		tasks.POST("/:id/unarchive", taskHandler.Unarchive)
+		tasks.POST("/:id/attachments", uploads, taskHandler.UploadAttachment)
+		tasks.GET("/:id/attachments", taskHandler.ListAttachments)
+		tasks.GET("/:id/attachments/:attachment_id/download", taskHandler.DownloadAttachment)
 	}
 
 	// FEED — лента действий, видимость записей зависит от роли
 	if feedHandler != nil {
 		r.GET("/api/v1/feed", middleware.RequirePermission("feed.view", "feed"), feedHandler.List)
+		r.GET("/audit", middleware.RequireRoles(authz.RoleSystemAdmin, authz.RoleControl), feedHandler.AuditLog)
 	}
 
 	// FEED EVENTS — запросы на подтверждение от визового и других отделов
@@ -508,6 +589,8 @@ func SetupRoutes(
 	{
 		reports.GET("/funnel", reportHandler.GetFunnel)
 		reports.GET("/leads", reportHandler.GetLeadsSummary)
+		reports.GET("/leads/by-source", reportHandler.GetLeadsBySource)
+		reports.GET("/summary", reportHandler.GetSummary)
 		reports.GET("/revenue", reportHandler.GetRevenue)
 		reports.GET("/revenue/export", reportHandler.ExportRevenue)
 	}
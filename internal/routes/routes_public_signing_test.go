@@ -51,7 +51,9 @@ func (s *publicVerifyRepoStub) UpdateMeta(context.Context, string, []byte) (*mod
 
 type publicVerifyDocStub struct{}
 
-func (s *publicVerifyDocStub) GetByID(int64) (*models.Document, error) { return nil, nil }
+func (s *publicVerifyDocStub) GetByID(context.Context, int64) (*models.Document, error) {
+	return nil, nil
+}
 
 func TestSetupRoutes_PublicSigningVerifyAPIWithoutAuth(t *testing.T) {
 	gin.SetMode(gin.TestMode)
@@ -109,6 +111,10 @@ func TestSetupRoutes_PublicSigningVerifyAPIWithoutAuth(t *testing.T) {
 		nil, // feedHandler
 		nil, // approvalHandler
 		nil, // feedEventHandler
+		nil, // webhookHandler
+		nil, // idempotency
+		nil, // uploads
+		RateLimits{},
 		middleware.NewAuthMiddleware([]byte("test-secret")),
 	)
 
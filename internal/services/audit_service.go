@@ -5,16 +5,24 @@ import (
 	"encoding/json"
 	"errors"
 	"log"
+	"time"
 
 	"turcompany/internal/authz"
 	"turcompany/internal/repositories"
 )
 
+// AuditStore persists and lists audit_logs rows; *repositories.AuditRepository
+// implements it.
+type AuditStore interface {
+	Insert(ctx context.Context, actorUserID *int, action, entityType, entityID string, ip, userAgent *string, metaJSON string, isHidden bool) error
+	List(ctx context.Context, limit, offset int, f repositories.AuditListFilter) ([]*repositories.AuditLogEntry, error)
+}
+
 type AuditService struct {
-	repo *repositories.AuditRepository
+	repo AuditStore
 }
 
-func NewAuditService(repo *repositories.AuditRepository) *AuditService {
+func NewAuditService(repo AuditStore) *AuditService {
 	return &AuditService{repo: repo}
 }
 
@@ -112,3 +120,60 @@ func (s *AuditService) ListFeed(ctx context.Context, userID, roleID, limit, offs
 	}
 	return out, nil
 }
+
+// AuditLogEntry is one audit_logs row as returned by GET /audit.
+type AuditLogEntry struct {
+	FeedEntry
+	IP        *string `json:"ip"`
+	UserAgent *string `json:"user_agent"`
+}
+
+// AuditLogQuery filters GET /audit: by actor and by a created_at range
+// (From inclusive, To exclusive).
+type AuditLogQuery struct {
+	ActorUserID *int
+	From        *time.Time
+	To          *time.Time
+}
+
+// ListAuditLog returns the raw audit trail for compliance review. Only admin
+// and quality_control (audit) may read it; hidden partner records stay
+// visible to admin only, as in ListFeed.
+func (s *AuditService) ListAuditLog(ctx context.Context, roleID int, q AuditLogQuery, limit, offset int) ([]*AuditLogEntry, error) {
+	if roleID != authz.RoleSystemAdmin && roleID != authz.RoleControl {
+		return nil, ErrForbidden
+	}
+	if s == nil || s.repo == nil {
+		return nil, nil
+	}
+	rows, err := s.repo.List(ctx, limit, offset, repositories.AuditListFilter{
+		IncludeHidden: roleID == authz.RoleSystemAdmin,
+		ActorUserID:   q.ActorUserID,
+		From:          q.From,
+		To:            q.To,
+	})
+	if err != nil {
+		if errors.Is(err, repositories.ErrAuditSchemaMissing) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	out := make([]*AuditLogEntry, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, &AuditLogEntry{
+			FeedEntry: FeedEntry{
+				ID:          r.ID,
+				ActorUserID: r.ActorUserID,
+				Action:      r.Action,
+				EntityType:  r.EntityType,
+				EntityID:    r.EntityID,
+				Meta:        r.Meta,
+				IsHidden:    r.IsHidden,
+				CreatedAt:   r.CreatedAt.Format(time.RFC3339),
+			},
+			IP:        r.IP,
+			UserAgent: r.UserAgent,
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/repositories"
+)
+
+type auditListStoreStub struct {
+	filter repositories.AuditListFilter
+	rows   []*repositories.AuditLogEntry
+}
+
+func (s *auditListStoreStub) Insert(context.Context, *int, string, string, string, *string, *string, string, bool) error {
+	return nil
+}
+
+func (s *auditListStoreStub) List(_ context.Context, _, _ int, f repositories.AuditListFilter) ([]*repositories.AuditLogEntry, error) {
+	s.filter = f
+	return s.rows, nil
+}
+
+func TestListAuditLog_RolesAndFilters(t *testing.T) {
+	actor := 42
+	from := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	q := AuditLogQuery{ActorUserID: &actor, From: &from, To: &to}
+
+	store := &auditListStoreStub{rows: []*repositories.AuditLogEntry{{ID: 1, ActorUserID: &actor, Action: "http.POST /tasks", CreatedAt: from}}}
+	svc := NewAuditService(store)
+
+	if _, err := svc.ListAuditLog(context.Background(), authz.RoleSales, q, 20, 0); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("sales: err = %v, want ErrForbidden", err)
+	}
+
+	entries, err := svc.ListAuditLog(context.Background(), authz.RoleControl, q, 20, 0)
+	if err != nil {
+		t.Fatalf("audit role: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Action != "http.POST /tasks" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+	if store.filter.IncludeHidden {
+		t.Fatal("quality_control must not see hidden partner records")
+	}
+	if store.filter.ActorUserID != &actor || store.filter.From != &from || store.filter.To != &to {
+		t.Fatalf("filter not passed through: %+v", store.filter)
+	}
+
+	if _, err := svc.ListAuditLog(context.Background(), authz.RoleSystemAdmin, q, 20, 0); err != nil {
+		t.Fatalf("admin: %v", err)
+	}
+	if !store.filter.IncludeHidden {
+		t.Fatal("admin must see hidden records")
+	}
+}
@@ -1,7 +1,12 @@
 package services
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/hex"
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -16,6 +21,11 @@ type AuthService interface {
 	HashPassword(password string) (string, error)
 	GenerateAccessToken(userID, roleID int) (string, time.Time, error)
 	GenerateRefreshToken() (string, time.Time, error)
+	// SetIssuer changes the iss claim written to access tokens.
+	SetIssuer(issuer string)
+	// UseRS256 signs access tokens with the RSA key instead of the shared
+	// secret.
+	UseRS256(key *rsa.PrivateKey)
 }
 
 type authService struct {
@@ -23,10 +33,16 @@ type authService struct {
 	RefreshSecret []byte
 	AccessTTL     time.Duration
 	RefreshTTL    time.Duration
+	BcryptCost    int
+	Issuer        string
+	signingMethod jwt.SigningMethod
+	signingKey    interface{}
 	now           func() time.Time
 }
 
-func NewAuthService(accessSecret, refreshSecret []byte, accessTTL, refreshTTL time.Duration, now func() time.Time) AuthService {
+// NewAuthService builds the JWT/password service. Zero TTLs and a zero bcrypt
+// cost fall back to the defaults.
+func NewAuthService(accessSecret, refreshSecret []byte, accessTTL, refreshTTL time.Duration, bcryptCost int, now func() time.Time) AuthService {
 	if len(accessSecret) == 0 {
 		panic("access secret is required")
 	}
@@ -36,6 +52,12 @@ func NewAuthService(accessSecret, refreshSecret []byte, accessTTL, refreshTTL ti
 	if refreshTTL <= 0 {
 		refreshTTL = 30 * 24 * time.Hour
 	}
+	if bcryptCost == 0 {
+		bcryptCost = bcrypt.DefaultCost
+	}
+	if bcryptCost < bcrypt.MinCost || bcryptCost > bcrypt.MaxCost {
+		panic(fmt.Sprintf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost))
+	}
 	if now == nil {
 		now = time.Now
 	}
@@ -44,6 +66,10 @@ func NewAuthService(accessSecret, refreshSecret []byte, accessTTL, refreshTTL ti
 		RefreshSecret: refreshSecret,
 		AccessTTL:     accessTTL,
 		RefreshTTL:    refreshTTL,
+		BcryptCost:    bcryptCost,
+		Issuer:        middleware.DefaultJWTIssuer,
+		signingMethod: jwt.SigningMethodHS256,
+		signingKey:    accessSecret,
 		now:           now,
 	}
 }
@@ -53,29 +79,84 @@ func (s *authService) VerifyPassword(hash, password string) bool {
 }
 
 func (s *authService) HashPassword(password string) (string, error) {
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), s.BcryptCost)
 	return string(hash), err
 }
 
+func (s *authService) SetIssuer(issuer string) {
+	if issuer = strings.TrimSpace(issuer); issuer != "" {
+		s.Issuer = issuer
+	}
+}
+
+func (s *authService) UseRS256(key *rsa.PrivateKey) {
+	if key == nil {
+		return
+	}
+	s.signingMethod = jwt.SigningMethodRS256
+	s.signingKey = key
+}
+
 func (s *authService) GenerateAccessToken(userID, roleID int) (string, time.Time, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("generate token id: %w", err)
+	}
 	nowUTC := s.now().UTC()
 	expiresAt := nowUTC.Add(s.AccessTTL)
 	accessClaims := &middleware.Claims{
 		UserID: userID,
 		RoleID: roleID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			Issuer:    s.Issuer,
 			IssuedAt:  jwt.NewNumericDate(nowUTC),
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	signed, err := token.SignedString(s.AccessSecret)
+	token := jwt.NewWithClaims(s.signingMethod, accessClaims)
+	signed, err := token.SignedString(s.signingKey)
 	if err != nil {
 		return "", time.Time{}, fmt.Errorf("sign access token: %w", err)
 	}
 	return signed, expiresAt, nil
 }
 
+// LoadRSAKeyPair reads the PEM-encoded RS256 signing key and its public
+// counterpart, and makes sure the two belong together.
+func LoadRSAKeyPair(privateKeyFile, publicKeyFile string) (*rsa.PrivateKey, *rsa.PublicKey, error) {
+	privPEM, err := os.ReadFile(privateKeyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read jwt private key: %w", err)
+	}
+	priv, err := jwt.ParseRSAPrivateKeyFromPEM(privPEM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse jwt private key: %w", err)
+	}
+	pubPEM, err := os.ReadFile(publicKeyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read jwt public key: %w", err)
+	}
+	pub, err := jwt.ParseRSAPublicKeyFromPEM(pubPEM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse jwt public key: %w", err)
+	}
+	if !priv.PublicKey.Equal(pub) {
+		return nil, nil, fmt.Errorf("jwt public key does not match the private key")
+	}
+	return priv, pub, nil
+}
+
+// newTokenID returns a random jti so a single access token can be told apart
+// in audit logs and revoked later.
+func newTokenID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
 func (s *authService) GenerateRefreshToken() (string, time.Time, error) {
 	token, err := utils.NewRefreshToken(32)
 	if err != nil {
@@ -1,17 +1,24 @@
 package services
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
 
 	"turcompany/internal/middleware"
 )
 
 func TestGenerateAccessToken_DefaultTTLIsAboutTwoHours(t *testing.T) {
 	fixedNow := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
-	svc := NewAuthService([]byte("01234567890123456789012345678901"), nil, 0, 0, func() time.Time {
+	svc := NewAuthService([]byte("01234567890123456789012345678901"), nil, 0, 0, 0, func() time.Time {
 		return fixedNow
 	})
 
@@ -41,8 +48,87 @@ func TestGenerateAccessToken_DefaultTTLIsAboutTwoHours(t *testing.T) {
 		t.Fatal("expected iat and exp in access token")
 	}
 
+	if claims.Issuer != middleware.DefaultJWTIssuer {
+		t.Fatalf("unexpected iss: got=%q want=%q", claims.Issuer, middleware.DefaultJWTIssuer)
+	}
+	if len(claims.ID) != 32 {
+		t.Fatalf("expected a random jti, got %q", claims.ID)
+	}
+
 	ttl := claims.ExpiresAt.Sub(claims.IssuedAt.Time)
 	if diff := ttl - 2*time.Hour; diff > time.Second || diff < -time.Second {
 		t.Fatalf("unexpected ttl: got=%s want about=2h", ttl)
 	}
 }
+
+func TestHashPassword_UsesConfiguredBcryptCost(t *testing.T) {
+	svc := NewAuthService([]byte("01234567890123456789012345678901"), nil, 0, 0, bcrypt.MinCost+1, nil)
+	hash, err := svc.HashPassword("Passw0rd")
+	if err != nil {
+		t.Fatalf("HashPassword returned error: %v", err)
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	if err != nil {
+		t.Fatalf("bcrypt.Cost: %v", err)
+	}
+	if cost != bcrypt.MinCost+1 {
+		t.Fatalf("unexpected cost: got=%d want=%d", cost, bcrypt.MinCost+1)
+	}
+	if !svc.VerifyPassword(hash, "Passw0rd") {
+		t.Fatal("hash should verify")
+	}
+
+	def := NewAuthService([]byte("01234567890123456789012345678901"), nil, 0, 0, 0, nil)
+	if got := def.(*authService).BcryptCost; got != bcrypt.DefaultCost {
+		t.Fatalf("zero cost should fall back to default, got %d", got)
+	}
+}
+
+func TestAuthService_UseRS256SignsWithLoadedKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	writePEM := func(name, typ string, der []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	pubDER, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	otherPubDER, _ := x509.MarshalPKIXPublicKey(&other.PublicKey)
+	privPath := writePEM("jwt.key", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key))
+	pubPath := writePEM("jwt.pub", "PUBLIC KEY", pubDER)
+	otherPubPath := writePEM("other.pub", "PUBLIC KEY", otherPubDER)
+
+	if _, _, err := LoadRSAKeyPair(privPath, otherPubPath); err == nil {
+		t.Fatal("mismatched key pair must be rejected")
+	}
+	priv, pub, err := LoadRSAKeyPair(privPath, pubPath)
+	if err != nil {
+		t.Fatalf("LoadRSAKeyPair: %v", err)
+	}
+
+	svc := NewAuthService([]byte("01234567890123456789012345678901"), nil, 0, 0, 0, nil)
+	svc.UseRS256(priv)
+	token, _, err := svc.GenerateAccessToken(10, 20)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken returned error: %v", err)
+	}
+	claims := &middleware.Claims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return pub, nil
+	}, jwt.WithValidMethods([]string{"RS256"}))
+	if err != nil || !parsed.Valid {
+		t.Fatalf("RS256 token must verify with the public key: %v", err)
+	}
+	if claims.UserID != 10 || claims.RoleID != 20 {
+		t.Fatalf("unexpected claims %+v", claims)
+	}
+}
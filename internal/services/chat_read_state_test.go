@@ -0,0 +1,77 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+	"turcompany/internal/repositories"
+)
+
+// readStateChatRepo keeps one chat's message ids and per-member read markers.
+type readStateChatRepo struct {
+	repositories.ChatRepository
+	messages []int
+	lastRead map[int]int
+}
+
+func (r *readStateChatRepo) IsMember(int, int) (bool, error) { return true, nil }
+func (r *readStateChatRepo) GetChatByID(id int) (*models.Chat, error) {
+	return &models.Chat{ID: id}, nil
+}
+
+func (r *readStateChatRepo) MarkChatRead(_, userID int, messageID *int) (int, time.Time, error) {
+	upTo := r.messages[len(r.messages)-1]
+	if messageID != nil {
+		upTo = *messageID
+	}
+	if upTo > r.lastRead[userID] {
+		r.lastRead[userID] = upTo
+	}
+	return r.lastRead[userID], time.Now(), nil
+}
+
+func (r *readStateChatRepo) CountUnread(_, userID int) (int, error) {
+	n := 0
+	for _, id := range r.messages {
+		if id > r.lastRead[userID] {
+			n++
+		}
+	}
+	return n, nil
+}
+
+type readStateUserRepo struct{ repositories.UserRepository }
+
+func (readStateUserRepo) GetByID(id int) (*models.User, error) {
+	return &models.User{ID: id, RoleID: authz.RoleSystemAdmin}, nil
+}
+
+func TestChatServiceMarkChatRead_DecreasesUnread(t *testing.T) {
+	repo := &readStateChatRepo{messages: []int{10, 11, 12, 13}, lastRead: map[int]int{}}
+	svc := NewChatService(repo, t.TempDir(), readStateUserRepo{}, nil)
+
+	if n, _ := repo.CountUnread(1, 7); n != 4 {
+		t.Fatalf("expected 4 unread before reading, got %d", n)
+	}
+	upTo := 11
+	unread, evt, err := svc.MarkChatRead(1, 7, &upTo)
+	if err != nil {
+		t.Fatalf("MarkChatRead: %v", err)
+	}
+	if unread != 2 {
+		t.Fatalf("expected 2 unread after reading up to 11, got %d", unread)
+	}
+	if evt == nil || evt.Type != "chat:read" || evt.UserID != 7 || evt.LastReadMessageID != 11 {
+		t.Fatalf("unexpected read receipt %+v", evt)
+	}
+
+	unread, evt, err = svc.MarkChatRead(1, 7, nil)
+	if err != nil || unread != 0 || evt.LastReadMessageID != 13 {
+		t.Fatalf("reading the whole chat must clear unread, got unread=%d evt=%+v err=%v", unread, evt, err)
+	}
+	if unread, _, _ := svc.MarkChatRead(1, 7, &upTo); unread != 0 {
+		t.Fatalf("marking an older message must not bring unread back, got %d", unread)
+	}
+}
@@ -16,7 +16,7 @@ import (
 )
 
 type clientAccessChecker interface {
-	GetByID(id int, userID, roleID int) (*models.Client, error)
+	GetByID(ctx context.Context, id int, userID, roleID int) (*models.Client, error)
 }
 
 type clientFileStore interface {
@@ -93,7 +93,7 @@ func (s *ClientFilesService) UploadPrimary(ctx context.Context, userID, roleID,
 	if fileHeader == nil {
 		return nil, ErrFileRequired
 	}
-	client, err := s.ensureClientAccess(clientID, userID, roleID)
+	client, err := s.ensureClientAccess(ctx, clientID, userID, roleID)
 	if err != nil {
 		return nil, err
 	}
@@ -154,7 +154,7 @@ func (s *ClientFilesService) UploadPrimary(ctx context.Context, userID, roleID,
 
 // ResolvePrimaryPath returns the storage key, file name, and MIME type for a client file.
 func (s *ClientFilesService) ResolvePrimaryPath(ctx context.Context, userID, roleID, clientID int, category string) (key string, fileName string, mimeType string, err error) {
-	if _, err = s.ensureClientAccess(clientID, userID, roleID); err != nil {
+	if _, err = s.ensureClientAccess(ctx, clientID, userID, roleID); err != nil {
 		return "", "", "", err
 	}
 	category = normalizeClientFileCategory(category)
@@ -173,8 +173,8 @@ func (s *ClientFilesService) ResolvePrimaryPath(ctx context.Context, userID, rol
 	return key, fileName, mimeType, nil
 }
 
-func (s *ClientFilesService) ensureClientAccess(clientID, userID, roleID int) (*models.Client, error) {
-	client, err := s.Clients.GetByID(clientID, userID, roleID)
+func (s *ClientFilesService) ensureClientAccess(ctx context.Context, clientID, userID, roleID int) (*models.Client, error) {
+	client, err := s.Clients.GetByID(ctx, clientID, userID, roleID)
 	if err != nil {
 		return nil, err
 	}
@@ -600,12 +600,12 @@ func isValidEmail(email string) bool {
 	return addr.Address == email
 }
 
-func (s *ClientService) ensureEmailUnique(email string, excludeID int) error {
+func (s *ClientService) ensureEmailUnique(ctx context.Context, email string, excludeID int) error {
 	email = strings.TrimSpace(email)
 	if email == "" {
 		return nil
 	}
-	existing, err := s.Repo.GetByEmail(email)
+	existing, err := s.Repo.GetByEmail(ctx, email)
 	if err != nil {
 		return err
 	}
@@ -628,7 +628,7 @@ func normalizePhone(value string) string {
 	return b.String()
 }
 
-func (s *ClientService) Create(c *models.Client, userID, roleID int) (int64, error) {
+func (s *ClientService) Create(ctx context.Context, c *models.Client, userID, roleID int) (int64, error) {
 	if err := s.authorizeWrite(roleID); err != nil {
 		return 0, err
 	}
@@ -660,14 +660,14 @@ func (s *ClientService) Create(c *models.Client, userID, roleID int) (int64, err
 	if err := validateCreateRedFields(c); err != nil {
 		return 0, err
 	}
-	id, err := s.Repo.Create(c)
+	id, err := s.Repo.Create(ctx, c)
 	if err != nil {
 		return 0, mapClientDBError(err)
 	}
 	return id, nil
 }
 
-func (s *ClientService) Update(c *models.Client, userID, roleID int) error {
+func (s *ClientService) Update(ctx context.Context, c *models.Client, userID, roleID int) error {
 	if err := s.authorizeWrite(roleID); err != nil {
 		return err
 	}
@@ -678,7 +678,7 @@ func (s *ClientService) Update(c *models.Client, userID, roleID int) error {
 	if roleID == authz.RoleVisa || roleID == authz.RolePartner {
 		return ErrClientEditNeedsApproval
 	}
-	current, err := s.Repo.GetByID(c.ID)
+	current, err := s.Repo.GetByID(ctx, c.ID)
 	if err != nil {
 		return err
 	}
@@ -710,32 +710,32 @@ func (s *ClientService) Update(c *models.Client, userID, roleID int) error {
 	if err := s.normalizeAndValidate(c); err != nil {
 		return err
 	}
-	if err := s.ensureEmailUnique(c.Email, c.ID); err != nil {
+	if err := s.ensureEmailUnique(ctx, c.Email, c.ID); err != nil {
 		return err
 	}
-	err = s.Repo.Update(c)
+	err = s.Repo.Update(ctx, c)
 	if err != nil {
 		return mapClientDBError(err)
 	}
 	return nil
 }
 
-func (s *ClientService) GetByID(id int, userID, roleID int) (*models.Client, error) {
-	return s.getClientByIDWithScope(id, userID, roleID, repositories.ArchiveScopeActiveOnly)
+func (s *ClientService) GetByID(ctx context.Context, id int, userID, roleID int) (*models.Client, error) {
+	return s.getClientByIDWithScope(ctx, id, userID, roleID, repositories.ArchiveScopeActiveOnly)
 }
 
-func (s *ClientService) GetByIDWithArchiveScope(id int, userID, roleID int, archiveScope repositories.ArchiveScope) (*models.Client, error) {
-	return s.getClientByIDWithScope(id, userID, roleID, archiveScope)
+func (s *ClientService) GetByIDWithArchiveScope(ctx context.Context, id int, userID, roleID int, archiveScope repositories.ArchiveScope) (*models.Client, error) {
+	return s.getClientByIDWithScope(ctx, id, userID, roleID, archiveScope)
 }
 
-func (s *ClientService) getClientByIDWithScope(id int, userID, roleID int, archiveScope repositories.ArchiveScope) (*models.Client, error) {
+func (s *ClientService) getClientByIDWithScope(ctx context.Context, id int, userID, roleID int, archiveScope repositories.ArchiveScope) (*models.Client, error) {
 	dataScope, err := resolveClientScope(userID, roleID, s.UserRepo)
 	if err != nil {
 		return nil, err
 	}
 	switch dataScope.Kind {
 	case ScopeKindBranch:
-		client, err := s.Repo.GetByIDWithBranchScope(id, dataScope.BranchID, archiveScope)
+		client, err := s.Repo.GetByIDWithBranchScope(ctx, id, dataScope.BranchID, archiveScope)
 		if err != nil || client == nil {
 			return client, err
 		}
@@ -744,7 +744,7 @@ func (s *ClientService) getClientByIDWithScope(id int, userID, roleID int, archi
 		}
 		return client, nil
 	case ScopeKindOwn:
-		client, err := s.Repo.GetByIDWithArchiveScope(id, archiveScope)
+		client, err := s.Repo.GetByIDWithArchiveScope(ctx, id, archiveScope)
 		if err != nil || client == nil {
 			return client, err
 		}
@@ -753,17 +753,17 @@ func (s *ClientService) getClientByIDWithScope(id int, userID, roleID int, archi
 		}
 		return client, nil
 	case ScopeKindAll:
-		return s.Repo.GetByIDWithArchiveScope(id, archiveScope)
+		return s.Repo.GetByIDWithArchiveScope(ctx, id, archiveScope)
 	default:
 		return nil, ErrForbidden
 	}
 }
 
-func (s *ClientService) Delete(id int, userID, roleID int) error {
+func (s *ClientService) Delete(ctx context.Context, id int, userID, roleID int) error {
 	if !authz.CanHardDeleteBusinessEntity(roleID) {
 		return ErrForbidden
 	}
-	current, err := s.Repo.GetByIDWithArchiveScope(id, repositories.ArchiveScopeAll)
+	current, err := s.Repo.GetByIDWithArchiveScope(ctx, id, repositories.ArchiveScopeAll)
 	if err != nil {
 		return err
 	}
@@ -780,7 +780,7 @@ func (s *ClientService) Delete(id int, userID, roleID int) error {
 	if roleID == authz.RoleSales && current.OwnerID != userID {
 		return ErrForbidden
 	}
-	err = s.Repo.Delete(id)
+	err = s.Repo.Delete(ctx, id)
 	if errors.Is(err, sql.ErrNoRows) {
 		return ErrClientNotFound
 	}
@@ -790,19 +790,76 @@ func (s *ClientService) Delete(id int, userID, roleID int) error {
 	return err
 }
 
-func (s *ClientService) GetOrCreateByBIN(bin string, fallback *models.Client, userID, roleID int) (*models.Client, error) {
-	dataScope, err := resolveClientScope(userID, roleID, s.UserRepo)
+// Merge folds duplicateID into primaryID (deals, documents, files, calls and
+// chats move over) and deletes the duplicate. It returns the number of deals
+// moved. Admin only, like hard delete.
+func (s *ClientService) Merge(ctx context.Context, primaryID, duplicateID, _, roleID int) (int64, error) {
+	if !authz.CanHardDeleteBusinessEntity(roleID) {
+		return 0, ErrForbidden
+	}
+	if primaryID <= 0 || duplicateID <= 0 {
+		return 0, ErrClientIDRequired
+	}
+	if primaryID == duplicateID {
+		return 0, ErrClientMergeSameClient
+	}
+	moved, err := s.Repo.Merge(ctx, primaryID, duplicateID)
+	if errors.Is(err, repositories.ErrClientNotFound) {
+		return 0, ErrClientNotFound
+	}
+	return moved, err
+}
+
+func (s *ClientService) GetOrCreateByBIN(ctx context.Context, bin string, fallback *models.Client, userID, roleID int) (*models.Client, error) {
+	client, isNew, dataScope, err := s.findOrPrepareByBIN(ctx, bin, fallback, userID, roleID)
+	if err != nil || !isNew {
+		return client, err
+	}
+	id, err := s.Repo.Create(ctx, fallback)
 	if err != nil {
+		if repositories.IsSQLState(err, repositories.SQLStateUniqueViolation) {
+			if fallback.BinIin != "" {
+				existing, lookupErr := s.Repo.GetByBIN(ctx, fallback.BinIin)
+				if lookupErr != nil {
+					return nil, lookupErr
+				}
+				if existing != nil && clientMatchesScope(dataScope, existing) {
+					return existing, nil
+				}
+			}
+			if fallback.IIN != "" {
+				existing, lookupErr := s.Repo.GetByIIN(ctx, fallback.IIN)
+				if lookupErr != nil {
+					return nil, lookupErr
+				}
+				if existing != nil && clientMatchesScope(dataScope, existing) {
+					return existing, nil
+				}
+			}
+		}
 		return nil, err
 	}
+	fallback.ID = int(id)
+	return fallback, nil
+}
+
+// findOrPrepareByBIN looks up an existing in-scope client by BIN, IIN or
+// phone. When none matches it validates fallback and binds it to the caller's
+// scope without saving it, returning isNew=true so the caller decides where
+// (and in which transaction) the client is inserted.
+func (s *ClientService) findOrPrepareByBIN(ctx context.Context, bin string, fallback *models.Client, userID, roleID int) (*models.Client, bool, DataScope, error) {
+	dataScope, err := resolveClientScope(userID, roleID, s.UserRepo)
+	if err != nil {
+		return nil, false, dataScope, err
+	}
 	bin = strings.TrimSpace(bin)
 	if bin != "" {
-		existing, err := s.Repo.GetByBIN(bin)
+		existing, err := s.Repo.GetByBIN(ctx, bin)
 		if err != nil {
-			return nil, err
+			return nil, false, dataScope, err
 		}
 		if existing != nil && clientMatchesScope(dataScope, existing) {
-			return existing, nil
+			return existing, false, dataScope, nil
 		}
 	}
 
@@ -812,31 +869,31 @@ func (s *ClientService) GetOrCreateByBIN(bin string, fallback *models.Client, us
 	}
 
 	if fallback != nil && fallback.IIN != "" {
-		existing, err := s.Repo.GetByIIN(fallback.IIN)
+		existing, err := s.Repo.GetByIIN(ctx, fallback.IIN)
 		if err != nil {
-			return nil, err
+			return nil, false, dataScope, err
 		}
 		if existing != nil && clientMatchesScope(dataScope, existing) {
-			return existing, nil
+			return existing, false, dataScope, nil
 		}
 	}
 
 	if fallback != nil && fallback.IIN == "" && fallback.Phone != "" {
-		existing, err := s.Repo.GetByPhone(fallback.Phone)
+		existing, err := s.Repo.GetByPhone(ctx, fallback.Phone)
 		if err != nil {
-			return nil, err
+			return nil, false, dataScope, err
 		}
 		if existing != nil && clientMatchesScope(dataScope, existing) {
-			return existing, nil
+			return existing, false, dataScope, nil
 		}
 	}
 
 	if fallback == nil {
-		return nil, errors.New("client data is required")
+		return nil, false, dataScope, errors.New("client data is required")
 	}
 
 	if err := s.normalizeAndValidate(fallback); err != nil {
-		return nil, err
+		return nil, false, dataScope, err
 	}
 
 	switch dataScope.Kind {
@@ -849,32 +906,7 @@ func (s *ClientService) GetOrCreateByBIN(bin string, fallback *models.Client, us
 			fallback.BranchID = branchID
 		}
 	}
-	id, err := s.Repo.Create(fallback)
-	if err != nil {
-		if repositories.IsSQLState(err, repositories.SQLStateUniqueViolation) {
-			if fallback.BinIin != "" {
-				existing, lookupErr := s.Repo.GetByBIN(fallback.BinIin)
-				if lookupErr != nil {
-					return nil, lookupErr
-				}
-				if existing != nil && clientMatchesScope(dataScope, existing) {
-					return existing, nil
-				}
-			}
-			if fallback.IIN != "" {
-				existing, lookupErr := s.Repo.GetByIIN(fallback.IIN)
-				if lookupErr != nil {
-					return nil, lookupErr
-				}
-				if existing != nil && clientMatchesScope(dataScope, existing) {
-					return existing, nil
-				}
-			}
-		}
-		return nil, err
-	}
-	fallback.ID = int(id)
-	return fallback, nil
+	return fallback, true, dataScope, nil
 }
 
 func mapClientDBError(err error) error {
@@ -918,15 +950,15 @@ func mapClientDBError(err error) error {
 	return err
 }
 
-func (s *ClientService) ListAll(limit, offset int, clientType string) ([]*models.Client, error) {
-	return s.Repo.ListAllWithFilterAndArchiveScope(limit, offset, repositories.ClientListFilter{ClientType: clientType}, repositories.ArchiveScopeActiveOnly)
+func (s *ClientService) ListAll(ctx context.Context, limit, offset int, clientType string) ([]*models.Client, error) {
+	return s.Repo.ListAllWithFilterAndArchiveScope(ctx, limit, offset, repositories.ClientListFilter{ClientType: clientType}, repositories.ArchiveScopeActiveOnly)
 }
 
-func (s *ClientService) ListMine(userID, limit, offset int, clientType string) ([]*models.Client, error) {
-	return s.Repo.ListByOwnerWithFilterAndArchiveScope(userID, limit, offset, repositories.ClientListFilter{ClientType: clientType}, repositories.ArchiveScopeActiveOnly)
+func (s *ClientService) ListMine(ctx context.Context, userID, limit, offset int, clientType string) ([]*models.Client, error) {
+	return s.Repo.ListByOwnerWithFilterAndArchiveScope(ctx, userID, limit, offset, repositories.ClientListFilter{ClientType: clientType}, repositories.ArchiveScopeActiveOnly)
 }
 
-func (s *ClientService) ListIndividualsForRole(userID, roleID, limit, offset int, filter repositories.ClientListFilter, archiveScope repositories.ArchiveScope) ([]*models.Client, error) {
+func (s *ClientService) ListIndividualsForRole(ctx context.Context, userID, roleID, limit, offset int, filter repositories.ClientListFilter, archiveScope repositories.ArchiveScope) ([]*models.Client, error) {
 	dataScope, err := resolveClientScope(userID, roleID, s.UserRepo)
 	if err != nil {
 		return nil, err
@@ -935,11 +967,11 @@ func (s *ClientService) ListIndividualsForRole(userID, roleID, limit, offset int
 		return nil, err
 	}
 	filter.ClientType = models.ClientTypeIndividual
-	return listClientsForScope(s.Repo, dataScope, limit, offset, filter, archiveScope)
+	return listClientsForScope(ctx, s.Repo, dataScope, limit, offset, filter, archiveScope)
 }
 
-func (s *ClientService) ListIndividualsForRoleWithTotal(userID, roleID, limit, offset int, filter repositories.ClientListFilter, archiveScope repositories.ArchiveScope) ([]*models.Client, int, error) {
-	items, err := s.ListIndividualsForRole(userID, roleID, limit, offset, filter, archiveScope)
+func (s *ClientService) ListIndividualsForRoleWithTotal(ctx context.Context, userID, roleID, limit, offset int, filter repositories.ClientListFilter, archiveScope repositories.ArchiveScope) ([]*models.Client, int, error) {
+	items, err := s.ListIndividualsForRole(ctx, userID, roleID, limit, offset, filter, archiveScope)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -947,14 +979,14 @@ func (s *ClientService) ListIndividualsForRoleWithTotal(userID, roleID, limit, o
 	if err != nil {
 		return nil, 0, err
 	}
-	total, err := countClientsForScope(s.Repo, dataScope, models.ClientTypeIndividual, repositories.ClientListFilter{Query: filter.Query}, archiveScope)
+	total, err := countClientsForScope(ctx, s.Repo, dataScope, models.ClientTypeIndividual, repositories.ClientListFilter{Query: filter.Query}, archiveScope)
 	if err != nil {
 		return nil, 0, err
 	}
 	return items, total, nil
 }
 
-func (s *ClientService) ListCompaniesForRole(userID, roleID, limit, offset int, filter repositories.ClientListFilter, archiveScope repositories.ArchiveScope) ([]*models.Client, error) {
+func (s *ClientService) ListCompaniesForRole(ctx context.Context, userID, roleID, limit, offset int, filter repositories.ClientListFilter, archiveScope repositories.ArchiveScope) ([]*models.Client, error) {
 	dataScope, err := resolveClientScope(userID, roleID, s.UserRepo)
 	if err != nil {
 		return nil, err
@@ -963,11 +995,11 @@ func (s *ClientService) ListCompaniesForRole(userID, roleID, limit, offset int,
 		return nil, err
 	}
 	filter.ClientType = models.ClientTypeLegal
-	return listClientsForScope(s.Repo, dataScope, limit, offset, filter, archiveScope)
+	return listClientsForScope(ctx, s.Repo, dataScope, limit, offset, filter, archiveScope)
 }
 
-func (s *ClientService) ListCompaniesForRoleWithTotal(userID, roleID, limit, offset int, filter repositories.ClientListFilter, archiveScope repositories.ArchiveScope) ([]*models.Client, int, error) {
-	items, err := s.ListCompaniesForRole(userID, roleID, limit, offset, filter, archiveScope)
+func (s *ClientService) ListCompaniesForRoleWithTotal(ctx context.Context, userID, roleID, limit, offset int, filter repositories.ClientListFilter, archiveScope repositories.ArchiveScope) ([]*models.Client, int, error) {
+	items, err := s.ListCompaniesForRole(ctx, userID, roleID, limit, offset, filter, archiveScope)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -975,14 +1007,14 @@ func (s *ClientService) ListCompaniesForRoleWithTotal(userID, roleID, limit, off
 	if err != nil {
 		return nil, 0, err
 	}
-	total, err := countClientsForScope(s.Repo, dataScope, models.ClientTypeLegal, repositories.ClientListFilter{Query: filter.Query}, archiveScope)
+	total, err := countClientsForScope(ctx, s.Repo, dataScope, models.ClientTypeLegal, repositories.ClientListFilter{Query: filter.Query}, archiveScope)
 	if err != nil {
 		return nil, 0, err
 	}
 	return items, total, nil
 }
 
-func (s *ClientService) ListForRole(userID, roleID, limit, offset int, filter repositories.ClientListFilter, archiveScope repositories.ArchiveScope) ([]*models.Client, error) {
+func (s *ClientService) ListForRole(ctx context.Context, userID, roleID, limit, offset int, filter repositories.ClientListFilter, archiveScope repositories.ArchiveScope) ([]*models.Client, error) {
 	dataScope, err := resolveClientScope(userID, roleID, s.UserRepo)
 	if err != nil {
 		return nil, err
@@ -990,11 +1022,11 @@ func (s *ClientService) ListForRole(userID, roleID, limit, offset int, filter re
 	if err := s.validateClientListFilter(&filter); err != nil {
 		return nil, err
 	}
-	return listClientsForScope(s.Repo, dataScope, limit, offset, filter, archiveScope)
+	return listClientsForScope(ctx, s.Repo, dataScope, limit, offset, filter, archiveScope)
 }
 
-func (s *ClientService) ListForRoleWithTotal(userID, roleID, limit, offset int, filter repositories.ClientListFilter, archiveScope repositories.ArchiveScope) ([]*models.Client, int, error) {
-	items, err := s.ListForRole(userID, roleID, limit, offset, filter, archiveScope)
+func (s *ClientService) ListForRoleWithTotal(ctx context.Context, userID, roleID, limit, offset int, filter repositories.ClientListFilter, archiveScope repositories.ArchiveScope) ([]*models.Client, int, error) {
+	items, err := s.ListForRole(ctx, userID, roleID, limit, offset, filter, archiveScope)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -1002,29 +1034,39 @@ func (s *ClientService) ListForRoleWithTotal(userID, roleID, limit, offset int,
 	if err != nil {
 		return nil, 0, err
 	}
-	total, err := countClientsForScope(s.Repo, dataScope, "", filter, archiveScope)
+	total, err := countClientsForScope(ctx, s.Repo, dataScope, "", filter, archiveScope)
 	if err != nil {
 		return nil, 0, err
 	}
 	return items, total, nil
 }
 
-func (s *ClientService) ListMineWithArchiveScope(_ int, limit, offset int, filter repositories.ClientListFilter, archiveScope repositories.ArchiveScope) ([]*models.Client, error) {
+// SearchForRole looks clients up by exact BIN or name substring within the
+// caller's data scope.
+func (s *ClientService) SearchForRole(ctx context.Context, userID, roleID int, q string, limit, offset int) ([]*models.Client, int, error) {
+	dataScope, err := resolveClientScope(userID, roleID, s.UserRepo)
+	if err != nil {
+		return nil, 0, err
+	}
+	return searchClientsForScope(ctx, s.Repo, dataScope, q, limit, offset)
+}
+
+func (s *ClientService) ListMineWithArchiveScope(ctx context.Context, _ int, limit, offset int, filter repositories.ClientListFilter, archiveScope repositories.ArchiveScope) ([]*models.Client, error) {
 	if err := s.validateClientListFilter(&filter); err != nil {
 		return nil, err
 	}
-	return s.Repo.ListAllWithFilterAndArchiveScope(limit, offset, filter, archiveScope)
+	return s.Repo.ListAllWithFilterAndArchiveScope(ctx, limit, offset, filter, archiveScope)
 }
 
-func (s *ClientService) ListMineWithArchiveScopeAndTotal(_ int, limit, offset int, filter repositories.ClientListFilter, archiveScope repositories.ArchiveScope) ([]*models.Client, int, error) {
+func (s *ClientService) ListMineWithArchiveScopeAndTotal(ctx context.Context, _ int, limit, offset int, filter repositories.ClientListFilter, archiveScope repositories.ArchiveScope) ([]*models.Client, int, error) {
 	if err := s.validateClientListFilter(&filter); err != nil {
 		return nil, 0, err
 	}
-	items, err := s.Repo.ListAllWithFilterAndArchiveScope(limit, offset, filter, archiveScope)
+	items, err := s.Repo.ListAllWithFilterAndArchiveScope(ctx, limit, offset, filter, archiveScope)
 	if err != nil {
 		return nil, 0, err
 	}
-	total, err := s.Repo.CountWithFilterAndArchiveScope(nil, "", filter, archiveScope)
+	total, err := s.Repo.CountWithFilterAndArchiveScope(ctx, nil, "", filter, archiveScope)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -1044,11 +1086,11 @@ func (s *ClientService) validateClientListFilter(filter *repositories.ClientList
 	return nil
 }
 
-func (s *ClientService) ArchiveClient(id, userID, roleID int, reason string) error {
+func (s *ClientService) ArchiveClient(ctx context.Context, id, userID, roleID int, reason string) error {
 	if !authz.CanArchiveBusinessEntity(roleID) {
 		return ErrForbidden
 	}
-	client, err := s.Repo.GetByIDWithArchiveScope(id, repositories.ArchiveScopeAll)
+	client, err := s.Repo.GetByIDWithArchiveScope(ctx, id, repositories.ArchiveScopeAll)
 	if err != nil {
 		return err
 	}
@@ -1065,14 +1107,14 @@ func (s *ClientService) ArchiveClient(id, userID, roleID int, reason string) err
 	if client.IsArchived {
 		return nil
 	}
-	return s.Repo.Archive(id, userID, reason)
+	return s.Repo.Archive(ctx, id, userID, reason)
 }
 
-func (s *ClientService) UnarchiveClient(id, userID, roleID int) error {
+func (s *ClientService) UnarchiveClient(ctx context.Context, id, userID, roleID int) error {
 	if !authz.CanArchiveBusinessEntity(roleID) {
 		return ErrForbidden
 	}
-	client, err := s.Repo.GetByIDWithArchiveScope(id, repositories.ArchiveScopeAll)
+	client, err := s.Repo.GetByIDWithArchiveScope(ctx, id, repositories.ArchiveScopeAll)
 	if err != nil {
 		return err
 	}
@@ -1089,11 +1131,11 @@ func (s *ClientService) UnarchiveClient(id, userID, roleID int) error {
 	if !client.IsArchived {
 		return ErrNotArchived
 	}
-	return s.Repo.Unarchive(id)
+	return s.Repo.Unarchive(ctx, id)
 }
 
 func (s *ClientService) GetMissingYellow(ctx context.Context, clientID, userID, roleID int) ([]string, error) {
-	client, err := s.GetByID(clientID, userID, roleID)
+	client, err := s.GetByID(ctx, clientID, userID, roleID)
 	if err != nil {
 		return nil, err
 	}
@@ -1278,7 +1320,7 @@ func (s *ClientService) fetchPrimaryFileExists(ctx context.Context, clientID int
 }
 
 func (s *ClientService) GetProfile(ctx context.Context, clientID, userID, roleID int) (*ClientProfilePayload, error) {
-	client, err := s.GetByID(clientID, userID, roleID)
+	client, err := s.GetByID(ctx, clientID, userID, roleID)
 	if err != nil {
 		return nil, err
 	}
@@ -1306,7 +1348,7 @@ func (s *ClientService) GetProfile(ctx context.Context, clientID, userID, roleID
 	}, nil
 }
 
-func (s *ClientService) Patch(id int, updates map[string]any, userID, roleID int) (*models.Client, error) {
+func (s *ClientService) Patch(ctx context.Context, id int, updates map[string]any, userID, roleID int) (*models.Client, error) {
 	if err := s.authorizeWrite(roleID); err != nil {
 		return nil, err
 	}
@@ -1316,7 +1358,7 @@ func (s *ClientService) Patch(id int, updates map[string]any, userID, roleID int
 	if roleID == authz.RoleVisa || roleID == authz.RolePartner {
 		return nil, ErrClientEditNeedsApproval
 	}
-	current, err := s.Repo.GetByID(id)
+	current, err := s.Repo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -1339,7 +1381,7 @@ func (s *ClientService) Patch(id int, updates map[string]any, userID, roleID int
 		if email != "" && !isValidEmail(email) {
 			return nil, ErrInvalidEmail
 		}
-		if err := s.ensureEmailUnique(email, id); err != nil {
+		if err := s.ensureEmailUnique(ctx, email, id); err != nil {
 			return nil, err
 		}
 		updates["email"] = email
@@ -1363,8 +1405,8 @@ func (s *ClientService) Patch(id int, updates map[string]any, userID, roleID int
 	if len(updates) == 0 {
 		return current, nil
 	}
-	if err := s.Repo.UpdatePartial(id, updates); err != nil {
+	if err := s.Repo.UpdatePartial(ctx, id, updates); err != nil {
 		return nil, err
 	}
-	return s.Repo.GetByID(id)
+	return s.Repo.GetByID(ctx, id)
 }
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"turcompany/internal/authz"
+)
+
+func TestClientServiceMerge_ValidatesBeforeTouchingRepo(t *testing.T) {
+	svc := &ClientService{}
+	cases := []struct {
+		name                 string
+		roleID, primary, dup int
+		want                 error
+	}{
+		{"non admin", authz.RoleManagement, 5, 9, ErrForbidden},
+		{"same client", authz.RoleSystemAdmin, 5, 5, ErrClientMergeSameClient},
+		{"missing id", authz.RoleSystemAdmin, 0, 9, ErrClientIDRequired},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := svc.Merge(context.Background(), tc.primary, tc.dup, 1, tc.roleID); !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"turcompany/internal/models"
+)
+
+type searchClientRepoStub struct {
+	clients []*models.Client
+}
+
+// SearchByNameOrBIN filters in memory the way the repository query does:
+// active clients only, exact BIN matches first, then the window.
+func (r *searchClientRepoStub) SearchByNameOrBIN(_ context.Context, q string, ownerID, branchID *int, limit, offset int) ([]*models.Client, int, error) {
+	needle := strings.ToLower(q)
+	var byBIN, byName []*models.Client
+	for _, c := range r.clients {
+		if c.IsArchived || (ownerID != nil && c.OwnerID != *ownerID) || (branchID != nil && (c.BranchID == nil || *c.BranchID != *branchID)) {
+			continue
+		}
+		switch {
+		case c.BinIin == q:
+			byBIN = append(byBIN, c)
+		case strings.Contains(strings.ToLower(c.Name), needle):
+			byName = append(byName, c)
+		}
+	}
+	matches := append(byBIN, byName...)
+	total := len(matches)
+	if offset >= total {
+		return []*models.Client{}, total, nil
+	}
+	end := min(offset+limit, total)
+	return matches[offset:end], total, nil
+}
+
+func newSearchClientRepoStub() *searchClientRepoStub {
+	branch := 1
+	otherBranch := 2
+	return &searchClientRepoStub{clients: []*models.Client{
+		{ID: 1, Name: "Alpha Travel LLP", BinIin: "123456789012", OwnerID: 10, BranchID: &branch},
+		{ID: 2, Name: "Beta Tours", BinIin: "210987654321", OwnerID: 11, BranchID: &branch},
+		{ID: 3, Name: "Alpha Cargo", BinIin: "555555555555", OwnerID: 12, BranchID: &otherBranch},
+		{ID: 4, Name: "Alpha Archive", BinIin: "999999999999", OwnerID: 10, BranchID: &branch, IsArchived: true},
+	}}
+}
+
+func TestSearchClientsForScope_NameSubstring(t *testing.T) {
+	repo := newSearchClientRepoStub()
+
+	items, total, err := searchClientsForScope(context.Background(), repo, DataScope{Kind: ScopeKindAll}, "alpha", 10, 0)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if total != 2 || len(items) != 2 || items[0].ID != 1 || items[1].ID != 3 {
+		t.Fatalf("expected active Alpha clients 1 and 3, got total=%d items=%+v", total, items)
+	}
+
+	branch := 1
+	items, total, err = searchClientsForScope(context.Background(), repo, DataScope{Kind: ScopeKindBranch, BranchID: &branch}, "alpha", 10, 0)
+	if err != nil {
+		t.Fatalf("branch search: %v", err)
+	}
+	if total != 1 || items[0].ID != 1 {
+		t.Fatalf("branch scope must hide other branches, got total=%d items=%+v", total, items)
+	}
+
+	items, total, err = searchClientsForScope(context.Background(), repo, DataScope{Kind: ScopeKindBranch}, "alpha", 10, 0)
+	if err != nil || total != 0 || len(items) != 0 {
+		t.Fatalf("branch scope without a branch must find nothing, got total=%d items=%+v err=%v", total, items, err)
+	}
+}
+
+func TestSearchClientsForScope_ExactBINReturnsSingleClient(t *testing.T) {
+	repo := newSearchClientRepoStub()
+
+	items, total, err := searchClientsForScope(context.Background(), repo, DataScope{Kind: ScopeKindAll}, " 210987654321 ", 10, 0)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if total != 1 || len(items) != 1 || items[0].ID != 2 {
+		t.Fatalf("expected only client 2, got total=%d items=%+v", total, items)
+	}
+
+	items, total, err = searchClientsForScope(context.Background(), repo, DataScope{Kind: ScopeKindOwn, UserID: 10}, "210987654321", 10, 0)
+	if err != nil {
+		t.Fatalf("own-scope search: %v", err)
+	}
+	if total != 0 || len(items) != 0 {
+		t.Fatalf("BIN match outside own scope must be hidden, got %+v", items)
+	}
+}
+
+func TestSearchClientsForScope_Paginates(t *testing.T) {
+	repo := newSearchClientRepoStub()
+
+	items, total, err := searchClientsForScope(context.Background(), repo, DataScope{Kind: ScopeKindAll}, "alpha", 1, 1)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if total != 2 || len(items) != 1 || items[0].ID != 3 {
+		t.Fatalf("expected second page with client 3, got total=%d items=%+v", total, items)
+	}
+
+	items, total, err = searchClientsForScope(context.Background(), repo, DataScope{Kind: ScopeKindAll}, "alpha", 10, 5)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if total != 2 || len(items) != 0 {
+		t.Fatalf("offset past the end must return an empty page, got total=%d items=%+v", total, items)
+	}
+}
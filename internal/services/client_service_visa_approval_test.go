@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -16,11 +17,11 @@ import (
 func TestClientEdit_VisaRequiresAdminApproval(t *testing.T) {
 	s := &ClientService{}
 
-	if err := s.Update(&models.Client{ID: 1}, 100, authz.RoleVisa); !errors.Is(err, ErrClientEditNeedsApproval) {
+	if err := s.Update(context.Background(), &models.Client{ID: 1}, 100, authz.RoleVisa); !errors.Is(err, ErrClientEditNeedsApproval) {
 		t.Fatalf("visa direct Update must require approval, got %v", err)
 	}
 
-	if _, err := s.Patch(1, map[string]any{"name": "x"}, 100, authz.RoleVisa); !errors.Is(err, ErrClientEditNeedsApproval) {
+	if _, err := s.Patch(context.Background(), 1, map[string]any{"name": "x"}, 100, authz.RoleVisa); !errors.Is(err, ErrClientEditNeedsApproval) {
 		t.Fatalf("visa direct Patch must require approval, got %v", err)
 	}
 
@@ -36,11 +37,11 @@ func TestClientEdit_VisaRequiresAdminApproval(t *testing.T) {
 func TestClientEdit_PartnerRequiresAdminApproval(t *testing.T) {
 	s := &ClientService{}
 
-	if err := s.Update(&models.Client{ID: 1}, 100, authz.RolePartner); !errors.Is(err, ErrClientEditNeedsApproval) {
+	if err := s.Update(context.Background(), &models.Client{ID: 1}, 100, authz.RolePartner); !errors.Is(err, ErrClientEditNeedsApproval) {
 		t.Fatalf("partner direct Update must require approval, got %v", err)
 	}
 
-	if _, err := s.Patch(1, map[string]any{"name": "x"}, 100, authz.RolePartner); !errors.Is(err, ErrClientEditNeedsApproval) {
+	if _, err := s.Patch(context.Background(), 1, map[string]any{"name": "x"}, 100, authz.RolePartner); !errors.Is(err, ErrClientEditNeedsApproval) {
 		t.Fatalf("partner direct Patch must require approval, got %v", err)
 	}
 }
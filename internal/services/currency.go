@@ -0,0 +1,57 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultDealCurrencies is the ISO 4217 whitelist used when the config does
+// not list deals.currencies.
+var DefaultDealCurrencies = []string{"KZT", "USD", "EUR", "RUB"}
+
+// currencySet is an uppercase whitelist of currency codes a deal may use.
+type currencySet map[string]struct{}
+
+func newCurrencySet(codes []string) currencySet {
+	set := currencySet{}
+	for _, code := range codes {
+		if code = strings.ToUpper(strings.TrimSpace(code)); code != "" {
+			set[code] = struct{}{}
+		}
+	}
+	if len(set) == 0 {
+		return newCurrencySet(DefaultDealCurrencies)
+	}
+	return set
+}
+
+// normalize uppercases the code and checks it against the whitelist; a nil
+// set falls back to DefaultDealCurrencies. Unknown codes wrap
+// ErrInvalidCurrency with the allowed values.
+func (s currencySet) normalize(value string) (string, error) {
+	if s == nil {
+		s = newCurrencySet(nil)
+	}
+	code := strings.ToUpper(strings.TrimSpace(value))
+	if code == "" {
+		return "", ErrCurrencyRequired
+	}
+	if _, ok := s[code]; !ok {
+		return "", fmt.Errorf("%w: allowed values are %s", ErrInvalidCurrency, strings.Join(s.codes(), ", "))
+	}
+	return code, nil
+}
+
+// codes returns the whitelist sorted, for error messages.
+func (s currencySet) codes() []string {
+	if s == nil {
+		s = newCurrencySet(nil)
+	}
+	out := make([]string, 0, len(s))
+	for code := range s {
+		out = append(out, code)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+)
+
+func TestCurrencySetNormalize_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		allowed []string
+		input   string
+		want    string
+		wantErr error
+	}{
+		{name: "uppercase", input: "USD", want: "USD"},
+		{name: "lowercase is normalized", input: " usd ", want: "USD"},
+		{name: "default includes KZT", input: "kzt", want: "KZT"},
+		{name: "symbol rejected", input: "US$", wantErr: ErrInvalidCurrency},
+		{name: "name rejected", input: "Dollars", wantErr: ErrInvalidCurrency},
+		{name: "missing", input: " ", wantErr: ErrCurrencyRequired},
+		{name: "configured list", allowed: []string{"gbp"}, input: "gbp", want: "GBP"},
+		{name: "configured list replaces defaults", allowed: []string{"GBP"}, input: "USD", wantErr: ErrInvalidCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newCurrencySet(tt.allowed).normalize(tt.input)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v for %q, got %v", tt.wantErr, tt.input, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error for %q: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Fatalf("unexpected normalized value: got=%q want=%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDealCreate_RejectsUnknownCurrency(t *testing.T) {
+	svc := NewDealService(nil)
+	_, err := svc.Create(context.Background(), &models.Deals{
+		LeadID:     2,
+		ClientID:   4,
+		ClientType: "legal",
+		Amount:     50000,
+		Currency:   "US$",
+	}, 101, authz.RoleSales)
+	if !errors.Is(err, ErrInvalidCurrency) {
+		t.Fatalf("expected ErrInvalidCurrency, got %v", err)
+	}
+}
+
+func TestConvertLeadToDeal_RejectsUnknownCurrency(t *testing.T) {
+	svc := NewLeadService(nil, nil, nil)
+	svc.SetAllowedCurrencies([]string{"KZT"})
+
+	_, err := svc.ConvertLeadToDeal(context.Background(), 1, 1000, "usd", 10, 10, authz.RoleSales, 2, models.ClientTypeIndividual)
+	if !errors.Is(err, ErrInvalidCurrency) {
+		t.Fatalf("expected ErrInvalidCurrency, got %v", err)
+	}
+	_, err = svc.ConvertLeadToDealWithClientData(context.Background(), 1, 1000, "Dollars", 10, 10, authz.RoleSales, &models.Client{ClientType: models.ClientTypeIndividual})
+	if !errors.Is(err, ErrInvalidCurrency) {
+		t.Fatalf("expected ErrInvalidCurrency from conversion with client data, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"strings"
 
@@ -16,6 +17,7 @@ type DealService struct {
 	UserRepo           repositories.UserRepository
 	StageRepo          *repositories.FunnelStageRepository
 	TransitionRuleRepo *repositories.FunnelTransitionRuleRepository
+	currencies         currencySet
 }
 
 func NewDealService(repo *repositories.DealRepository, clientRepo ...*repositories.ClientRepository) *DealService {
@@ -39,6 +41,12 @@ func (s *DealService) SetTransitionRuleRepo(repo *repositories.FunnelTransitionR
 	s.TransitionRuleRepo = repo
 }
 
+// SetAllowedCurrencies replaces the currency whitelist; an empty list keeps
+// DefaultDealCurrencies.
+func (s *DealService) SetAllowedCurrencies(codes []string) {
+	s.currencies = newCurrencySet(codes)
+}
+
 func normalizeRequiredDealClientType(value string) (string, error) {
 	v := strings.ToLower(strings.TrimSpace(value))
 	if v == "" {
@@ -52,7 +60,7 @@ func normalizeRequiredDealClientType(value string) (string, error) {
 	}
 }
 
-func (s *DealService) validateTypedClientRef(clientID int, clientType string) (string, error) {
+func (s *DealService) validateTypedClientRef(ctx context.Context, clientID int, clientType string) (string, error) {
 	if clientID == 0 {
 		return "", ErrClientIDRequired
 	}
@@ -63,7 +71,7 @@ func (s *DealService) validateTypedClientRef(clientID int, clientType string) (s
 	if s.ClientRepo == nil {
 		return "", ErrClientRepoNotConfigured
 	}
-	client, err := s.ClientRepo.GetByID(clientID)
+	client, err := s.ClientRepo.GetByID(ctx, clientID)
 	if err != nil {
 		return "", err
 	}
@@ -80,7 +88,7 @@ func (s *DealService) validateTypedClientRef(clientID int, clientType string) (s
 	return requestedType, nil
 }
 
-func (s *DealService) Create(deal *models.Deals, userID, roleID int) (int64, error) {
+func (s *DealService) Create(ctx context.Context, deal *models.Deals, userID, roleID int) (int64, error) {
 	if authz.IsReadOnly(roleID) {
 		return 0, ErrReadOnly
 	}
@@ -92,7 +100,12 @@ func (s *DealService) Create(deal *models.Deals, userID, roleID int) (int64, err
 	if deal.Amount <= 0 {
 		return 0, ErrAmountInvalid
 	}
-	clientType, err := s.validateTypedClientRef(deal.ClientID, deal.ClientType)
+	currency, err := s.currencies.normalize(deal.Currency)
+	if err != nil {
+		return 0, err
+	}
+	deal.Currency = currency
+	clientType, err := s.validateTypedClientRef(ctx, deal.ClientID, deal.ClientType)
 	if err != nil {
 		return 0, err
 	}
@@ -109,7 +122,7 @@ func (s *DealService) Create(deal *models.Deals, userID, roleID int) (int64, err
 		}
 	}
 	if s.LeadRepo != nil {
-		if lead, err := s.LeadRepo.GetByID(deal.LeadID); err == nil && lead != nil {
+		if lead, err := s.LeadRepo.GetByID(ctx, deal.LeadID); err == nil && lead != nil {
 			deal.BranchID = lead.BranchID
 		}
 	}
@@ -130,10 +143,10 @@ func (s *DealService) Create(deal *models.Deals, userID, roleID int) (int64, err
 		deal.Status = "new"
 	}
 
-	id, err := s.Repo.Create(deal)
+	id, err := s.Repo.Create(ctx, deal)
 	if err != nil {
 		if repositories.IsSQLState(err, repositories.SQLStateUniqueViolation) && repositories.ConstraintName(err) == "deals_lead_unique_idx" {
-			existing, lookupErr := s.Repo.GetByLeadID(deal.LeadID)
+			existing, lookupErr := s.Repo.GetByLeadID(ctx, deal.LeadID)
 			if lookupErr != nil {
 				return 0, &DealAlreadyExistsError{LeadID: deal.LeadID}
 			}
@@ -161,14 +174,14 @@ func (s *DealService) Create(deal *models.Deals, userID, roleID int) (int64, err
 	return id, nil
 }
 
-func (s *DealService) Update(deal *models.Deals, userID, roleID int) error {
+func (s *DealService) Update(ctx context.Context, deal *models.Deals, userID, roleID int) error {
 	// 1) Базовые проверки ролей
 	if authz.IsReadOnly(roleID) {
 		return ErrReadOnly
 	}
 
 	// 2) Получаем текущую сделку
-	current, err := s.Repo.GetByID(deal.ID)
+	current, err := s.Repo.GetByID(ctx, deal.ID)
 	if err != nil {
 		return err
 	}
@@ -200,7 +213,7 @@ func (s *DealService) Update(deal *models.Deals, userID, roleID int) error {
 	if deal.ClientID == 0 {
 		return ErrClientIDRequired
 	}
-	clientType, err := s.validateTypedClientRef(deal.ClientID, deal.ClientType)
+	clientType, err := s.validateTypedClientRef(ctx, deal.ClientID, deal.ClientType)
 	if err != nil {
 		return err
 	}
@@ -213,9 +226,14 @@ func (s *DealService) Update(deal *models.Deals, userID, roleID int) error {
 		return ErrAmountInvalid
 	}
 
-	if deal.Currency == "" {
+	if strings.TrimSpace(deal.Currency) == "" {
 		deal.Currency = current.Currency
 	}
+	currency, err := s.currencies.normalize(deal.Currency)
+	if err != nil {
+		return err
+	}
+	deal.Currency = currency
 
 	if deal.Status == "" {
 		deal.Status = current.Status
@@ -236,7 +254,7 @@ func (s *DealService) Update(deal *models.Deals, userID, roleID int) error {
 	}
 
 	// 6) Сохраняем изменения в БД
-	err = s.Repo.Update(deal)
+	err = s.Repo.Update(ctx, deal)
 	if err != nil {
 		if repositories.IsSQLState(err, repositories.SQLStateUniqueViolation) && repositories.ConstraintName(err) == "deals_lead_unique_idx" {
 			return &DealAlreadyExistsError{LeadID: deal.LeadID}
@@ -259,8 +277,8 @@ func (s *DealService) Update(deal *models.Deals, userID, roleID int) error {
 	return nil
 }
 
-func (s *DealService) GetByID(id int, userID, roleID int) (*models.Deals, error) {
-	deal, err := s.Repo.GetByID(id)
+func (s *DealService) GetByID(ctx context.Context, id int, userID, roleID int) (*models.Deals, error) {
+	deal, err := s.Repo.GetByID(ctx, id)
 	if err != nil || deal == nil {
 		return deal, err
 	}
@@ -274,8 +292,8 @@ func (s *DealService) GetByID(id int, userID, roleID int) (*models.Deals, error)
 	return deal, nil
 }
 
-func (s *DealService) GetByIDWithArchiveScope(id int, userID, roleID int, scope repositories.ArchiveScope) (*models.Deals, error) {
-	deal, err := s.Repo.GetByIDWithArchiveScope(id, scope)
+func (s *DealService) GetByIDWithArchiveScope(ctx context.Context, id int, userID, roleID int, scope repositories.ArchiveScope) (*models.Deals, error) {
+	deal, err := s.Repo.GetByIDWithArchiveScope(ctx, id, scope)
 	if err != nil || deal == nil {
 		return deal, err
 	}
@@ -289,11 +307,11 @@ func (s *DealService) GetByIDWithArchiveScope(id int, userID, roleID int, scope
 	return deal, nil
 }
 
-func (s *DealService) Delete(id int, userID, roleID int) error {
+func (s *DealService) Delete(ctx context.Context, id int, userID, roleID int) error {
 	if !authz.CanHardDeleteBusinessEntity(roleID) {
 		return ErrForbidden
 	}
-	deal, err := s.Repo.GetByIDWithArchiveScope(id, repositories.ArchiveScopeAll)
+	deal, err := s.Repo.GetByIDWithArchiveScope(ctx, id, repositories.ArchiveScopeAll)
 	if err != nil {
 		return err
 	}
@@ -307,43 +325,43 @@ func (s *DealService) Delete(id int, userID, roleID int) error {
 	if !dealMatchesScope(dataScope, deal) {
 		return ErrForbidden
 	}
-	return s.Repo.Delete(id)
+	return s.Repo.Delete(ctx, id)
 }
 
-func (s *DealService) ListAll(limit, offset int) ([]*models.Deals, error) {
-	return s.Repo.ListAllWithFilterAndArchiveScope(limit, offset, repositories.DealListFilter{}, repositories.ArchiveScopeActiveOnly)
+func (s *DealService) ListAll(ctx context.Context, limit, offset int) ([]*models.Deals, error) {
+	return s.Repo.ListAllWithFilterAndArchiveScope(ctx, limit, offset, repositories.DealListFilter{}, repositories.ArchiveScopeActiveOnly)
 }
 
-func (s *DealService) ListAllWithArchiveScope(limit, offset int, scope repositories.ArchiveScope) ([]*models.Deals, error) {
-	return s.Repo.ListAllWithFilterAndArchiveScope(limit, offset, repositories.DealListFilter{}, scope)
+func (s *DealService) ListAllWithArchiveScope(ctx context.Context, limit, offset int, scope repositories.ArchiveScope) ([]*models.Deals, error) {
+	return s.Repo.ListAllWithFilterAndArchiveScope(ctx, limit, offset, repositories.DealListFilter{}, scope)
 }
 
-func (s *DealService) ListMy(ownerID, limit, offset int) ([]*models.Deals, error) {
-	return s.Repo.ListByOwnerWithFilterAndArchiveScope(ownerID, limit, offset, repositories.DealListFilter{}, repositories.ArchiveScopeActiveOnly)
+func (s *DealService) ListMy(ctx context.Context, ownerID, limit, offset int) ([]*models.Deals, error) {
+	return s.Repo.ListByOwnerWithFilterAndArchiveScope(ctx, ownerID, limit, offset, repositories.DealListFilter{}, repositories.ArchiveScopeActiveOnly)
 }
 
-func (s *DealService) ListMyWithArchiveScope(ownerID, limit, offset int, scope repositories.ArchiveScope) ([]*models.Deals, error) {
-	return s.Repo.ListByOwnerWithFilterAndArchiveScope(ownerID, limit, offset, repositories.DealListFilter{}, scope)
+func (s *DealService) ListMyWithArchiveScope(ctx context.Context, ownerID, limit, offset int, scope repositories.ArchiveScope) ([]*models.Deals, error) {
+	return s.Repo.ListByOwnerWithFilterAndArchiveScope(ctx, ownerID, limit, offset, repositories.DealListFilter{}, scope)
 }
 
-func (s *DealService) ListForRole(userID, roleID, limit, offset int, scope repositories.ArchiveScope, filter repositories.DealListFilter) ([]*models.Deals, error) {
-	// sales resolves to ScopeKindBranch (свой отдел+филиал) via resolveDealScope —
-	// no hard guard. Was previously blocked, forcing sales onto /deals/my
-	// (ListAll = все сделки), leaking every department's deals.
+func (s *DealService) ListForRole(ctx context.Context, userID, roleID, limit, offset int, scope repositories.ArchiveScope, filter repositories.DealListFilter) ([]*models.Deals, error) {
+	// sales resolves to ScopeKindBranch (свой отдел+филиал) via resolveDealScope,
+	// and dealFilterForRole additionally pins OwnerID, so sales see only their
+	// own deals inside that branch.
 	dataScope, err := resolveDealScope(userID, roleID, s.UserRepo)
 	if err != nil {
 		return nil, err
 	}
-	return listDealsForScope(s.Repo, dataScope, limit, offset, filter, scope)
+	return listDealsForScope(ctx, s.Repo, dataScope, limit, offset, dealFilterForRole(userID, roleID, filter), scope)
 }
 
-func (s *DealService) ListMyWithFilterAndArchiveScope(ownerID int, limit, offset int, scope repositories.ArchiveScope, filter repositories.DealListFilter) ([]*models.Deals, error) {
+func (s *DealService) ListMyWithFilterAndArchiveScope(ctx context.Context, ownerID int, limit, offset int, scope repositories.ArchiveScope, filter repositories.DealListFilter) ([]*models.Deals, error) {
 	// "Мои" = сделки текущего владельца. Ранее ошибочно возвращало ВСЕ сделки.
-	return s.Repo.ListByOwnerWithFilterAndArchiveScope(ownerID, limit, offset, filter, scope)
+	return s.Repo.ListByOwnerWithFilterAndArchiveScope(ctx, ownerID, limit, offset, filter, scope)
 }
 
-func (s *DealService) ListForRoleWithTotal(userID, roleID, limit, offset int, scope repositories.ArchiveScope, filter repositories.DealListFilter) ([]*models.Deals, int, error) {
-	items, err := s.ListForRole(userID, roleID, limit, offset, scope, filter)
+func (s *DealService) ListForRoleWithTotal(ctx context.Context, userID, roleID, limit, offset int, scope repositories.ArchiveScope, filter repositories.DealListFilter) ([]*models.Deals, int, error) {
+	items, err := s.ListForRole(ctx, userID, roleID, limit, offset, scope, filter)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -351,35 +369,35 @@ func (s *DealService) ListForRoleWithTotal(userID, roleID, limit, offset int, sc
 	if err != nil {
 		return nil, 0, err
 	}
-	total, err := countDealsForScope(s.Repo, dataScope, filter, scope)
+	total, err := countDealsForScope(ctx, s.Repo, dataScope, dealFilterForRole(userID, roleID, filter), scope)
 	if err != nil {
 		return nil, 0, err
 	}
 	return items, total, nil
 }
 
-func (s *DealService) ListMyWithFilterAndArchiveScopeAndTotal(ownerID int, limit, offset int, scope repositories.ArchiveScope, filter repositories.DealListFilter) ([]*models.Deals, int, error) {
+func (s *DealService) ListMyWithFilterAndArchiveScopeAndTotal(ctx context.Context, ownerID int, limit, offset int, scope repositories.ArchiveScope, filter repositories.DealListFilter) ([]*models.Deals, int, error) {
 	// "Мои" = сделки текущего владельца. Ранее возвращало ВСЕ сделки (ListAll).
-	items, err := s.Repo.ListByOwnerWithFilterAndArchiveScope(ownerID, limit, offset, filter, scope)
+	items, err := s.Repo.ListByOwnerWithFilterAndArchiveScope(ctx, ownerID, limit, offset, filter, scope)
 	if err != nil {
 		return nil, 0, err
 	}
-	total, err := s.Repo.CountByOwnerWithFilterAndArchiveScope(ownerID, filter, scope)
+	total, err := s.Repo.CountByOwnerWithFilterAndArchiveScope(ctx, ownerID, filter, scope)
 	if err != nil {
 		return nil, 0, err
 	}
 	return items, total, nil
 }
 
-func (s *DealService) GetByLeadID(leadID int) (*models.Deals, error) {
-	return s.Repo.GetByLeadID(leadID)
+func (s *DealService) GetByLeadID(ctx context.Context, leadID int) (*models.Deals, error) {
+	return s.Repo.GetByLeadID(ctx, leadID)
 }
 
-func (s *DealService) UpdateStatus(id int, to string, userID, roleID int) error {
+func (s *DealService) UpdateStatus(ctx context.Context, id int, to string, userID, roleID int) error {
 	if authz.IsReadOnly(roleID) {
 		return ErrReadOnly
 	}
-	deal, err := s.Repo.GetByID(id)
+	deal, err := s.Repo.GetByID(ctx, id)
 	if err != nil || deal == nil {
 		return err
 	}
@@ -396,7 +414,7 @@ func (s *DealService) UpdateStatus(id int, to string, userID, roleID int) error
 	if !canTransition(deal.Status, to, DealTransitions) {
 		return errors.New("invalid status transition")
 	}
-	return s.Repo.UpdateStatus(id, to)
+	return s.Repo.UpdateStatus(ctx, id, to)
 }
 
 // MoveStage moves a deal to a different funnel stage (kanban drag&drop) and
@@ -404,14 +422,14 @@ func (s *DealService) UpdateStatus(id int, to string, userID, roleID int) error
 // sync with the stage type: moving into a "won"/"lost" stage sets the matching
 // status, while moving a previously won/lost/cancelled deal into a regular
 // stage resets it to "in_progress".
-func (s *DealService) MoveStage(dealID, stageID int, comment string, userID, roleID int) error {
+func (s *DealService) MoveStage(ctx context.Context, dealID, stageID int, comment string, userID, roleID int) error {
 	if authz.IsReadOnly(roleID) {
 		return ErrReadOnly
 	}
 	if s.StageRepo == nil {
 		return ErrInvalidState
 	}
-	deal, err := s.Repo.GetByID(dealID)
+	deal, err := s.Repo.GetByID(ctx, dealID)
 	if err != nil {
 		return err
 	}
@@ -468,7 +486,7 @@ func (s *DealService) MoveStage(dealID, stageID int, comment string, userID, rol
 		}
 	}
 
-	if err := s.Repo.MoveStage(dealID, stageID, stage.FunnelID, newStatus); err != nil {
+	if err := s.Repo.MoveStage(ctx, dealID, stageID, stage.FunnelID, newStatus); err != nil {
 		return err
 	}
 
@@ -485,14 +503,14 @@ func (s *DealService) MoveStage(dealID, stageID int, comment string, userID, rol
 
 	// Apply admin-configured automatic cross-funnel transition rules.
 	// Pass depth=1 to guard against circular rule chains (max 10 hops).
-	_ = s.applyTransitionRules(dealID, stage.FunnelID, stageID, userID, 1)
+	_ = s.applyTransitionRules(ctx, dealID, stage.FunnelID, stageID, userID, 1)
 	return nil
 }
 
 // applyTransitionRules checks if any active transition rules fire for the given
 // (funnelID, stageID) and, if so, moves the deal accordingly. The depth guard
 // prevents infinite loops from misconfigured circular rules.
-func (s *DealService) applyTransitionRules(dealID, funnelID, stageID, changedBy, depth int) error {
+func (s *DealService) applyTransitionRules(ctx context.Context, dealID, funnelID, stageID, changedBy, depth int) error {
 	if depth > 10 || s.TransitionRuleRepo == nil || s.StageRepo == nil {
 		return nil
 	}
@@ -503,7 +521,7 @@ func (s *DealService) applyTransitionRules(dealID, funnelID, stageID, changedBy,
 	}
 
 	// Reload deal to have an up-to-date snapshot.
-	deal, err := s.Repo.GetByID(dealID)
+	deal, err := s.Repo.GetByID(ctx, dealID)
 	if err != nil || deal == nil {
 		return err
 	}
@@ -533,7 +551,7 @@ func (s *DealService) applyTransitionRules(dealID, funnelID, stageID, changedBy,
 		}
 	}
 
-	if err := s.Repo.MoveStageAndFunnel(dealID, rule.ToStageID, rule.ToFunnelID, newStatus); err != nil {
+	if err := s.Repo.MoveStageAndFunnel(ctx, dealID, rule.ToStageID, rule.ToFunnelID, newStatus); err != nil {
 		return err
 	}
 
@@ -552,16 +570,16 @@ func (s *DealService) applyTransitionRules(dealID, funnelID, stageID, changedBy,
 	}
 
 	// Recursively check if the new stage also triggers a rule.
-	return s.applyTransitionRules(dealID, rule.ToFunnelID, rule.ToStageID, changedBy, depth+1)
+	return s.applyTransitionRules(ctx, dealID, rule.ToFunnelID, rule.ToStageID, changedBy, depth+1)
 }
 
 // GetHistory returns the stage-transition history for a deal, enforcing the
 // same access scope as reading the deal itself.
-func (s *DealService) GetHistory(dealID, userID, roleID int) ([]*models.DealStageHistory, error) {
+func (s *DealService) GetHistory(ctx context.Context, dealID, userID, roleID int) ([]*models.DealStageHistory, error) {
 	if s.StageRepo == nil {
 		return nil, ErrInvalidState
 	}
-	deal, err := s.Repo.GetByID(dealID)
+	deal, err := s.Repo.GetByID(ctx, dealID)
 	if err != nil {
 		return nil, err
 	}
@@ -581,11 +599,11 @@ func (s *DealService) GetHistory(dealID, userID, roleID int) ([]*models.DealStag
 	return s.StageRepo.ListHistory(dealID)
 }
 
-func (s *DealService) ArchiveDeal(id, userID, roleID int, reason string) error {
+func (s *DealService) ArchiveDeal(ctx context.Context, id, userID, roleID int, reason string) error {
 	if !authz.CanArchiveBusinessEntity(roleID) {
 		return ErrForbidden
 	}
-	deal, err := s.Repo.GetByIDWithArchiveScope(id, repositories.ArchiveScopeAll)
+	deal, err := s.Repo.GetByIDWithArchiveScope(ctx, id, repositories.ArchiveScopeAll)
 	if err != nil {
 		return err
 	}
@@ -605,14 +623,14 @@ func (s *DealService) ArchiveDeal(id, userID, roleID int, reason string) error {
 	if deal.IsArchived {
 		return nil
 	}
-	return s.Repo.Archive(id, userID, reason)
+	return s.Repo.Archive(ctx, id, userID, reason)
 }
 
-func (s *DealService) UnarchiveDeal(id, userID, roleID int) error {
+func (s *DealService) UnarchiveDeal(ctx context.Context, id, userID, roleID int) error {
 	if !authz.CanArchiveBusinessEntity(roleID) {
 		return ErrForbidden
 	}
-	deal, err := s.Repo.GetByIDWithArchiveScope(id, repositories.ArchiveScopeAll)
+	deal, err := s.Repo.GetByIDWithArchiveScope(ctx, id, repositories.ArchiveScopeAll)
 	if err != nil {
 		return err
 	}
@@ -632,5 +650,5 @@ func (s *DealService) UnarchiveDeal(id, userID, roleID int) error {
 	if !deal.IsArchived {
 		return ErrNotArchived
 	}
-	return s.Repo.Unarchive(id)
+	return s.Repo.Unarchive(ctx, id)
 }
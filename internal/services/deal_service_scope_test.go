@@ -11,6 +11,7 @@ package services
 // countDealsForScope (added during Step 1 of the refactor).
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -45,7 +46,7 @@ func TestDealListForRole_SalesResolvesToBranch(t *testing.T) {
 // TestDealListForRole_PartnerForbidden confirms partner cannot list deals at all.
 func TestDealListForRole_PartnerForbidden(t *testing.T) {
 	svc := &DealService{}
-	_, err := svc.ListForRole(1, authz.RolePartner, 10, 0, repositories.ArchiveScopeActiveOnly, repositories.DealListFilter{})
+	_, err := svc.ListForRole(context.Background(), 1, authz.RolePartner, 10, 0, repositories.ArchiveScopeActiveOnly, repositories.DealListFilter{})
 	if !errors.Is(err, ErrForbidden) {
 		t.Errorf("partner ListForRole: want ErrForbidden, got %v", err)
 	}
@@ -54,7 +55,7 @@ func TestDealListForRole_PartnerForbidden(t *testing.T) {
 // TestDealListForRole_HRForbidden confirms hr cannot list deals.
 func TestDealListForRole_HRForbidden(t *testing.T) {
 	svc := &DealService{}
-	_, err := svc.ListForRole(1, authz.RoleHR, 10, 0, repositories.ArchiveScopeActiveOnly, repositories.DealListFilter{})
+	_, err := svc.ListForRole(context.Background(), 1, authz.RoleHR, 10, 0, repositories.ArchiveScopeActiveOnly, repositories.DealListFilter{})
 	if !errors.Is(err, ErrForbidden) {
 		t.Errorf("hr ListForRole: want ErrForbidden, got %v", err)
 	}
@@ -63,7 +64,7 @@ func TestDealListForRole_HRForbidden(t *testing.T) {
 // TestDealListForRole_LegalForbidden confirms legal cannot list deals.
 func TestDealListForRole_LegalForbidden(t *testing.T) {
 	svc := &DealService{}
-	_, err := svc.ListForRole(1, authz.RoleLegal, 10, 0, repositories.ArchiveScopeActiveOnly, repositories.DealListFilter{})
+	_, err := svc.ListForRole(context.Background(), 1, authz.RoleLegal, 10, 0, repositories.ArchiveScopeActiveOnly, repositories.DealListFilter{})
 	if !errors.Is(err, ErrForbidden) {
 		t.Errorf("legal ListForRole: want ErrForbidden, got %v", err)
 	}
@@ -72,7 +73,7 @@ func TestDealListForRole_LegalForbidden(t *testing.T) {
 // TestDealListForRole_UnknownRoleForbidden confirms unknown roles cannot list deals.
 func TestDealListForRole_UnknownRoleForbidden(t *testing.T) {
 	svc := &DealService{}
-	_, err := svc.ListForRole(1, 999, 10, 0, repositories.ArchiveScopeActiveOnly, repositories.DealListFilter{})
+	_, err := svc.ListForRole(context.Background(), 1, 999, 10, 0, repositories.ArchiveScopeActiveOnly, repositories.DealListFilter{})
 	if !errors.Is(err, ErrForbidden) {
 		t.Errorf("unknown role ListForRole: want ErrForbidden, got %v", err)
 	}
@@ -145,7 +146,7 @@ type dealRepoSpy struct {
 	calledAll    bool
 }
 
-func (r *dealRepoSpy) ListAllWithFilterAndArchiveScope(_, _ int, filter repositories.DealListFilter, _ repositories.ArchiveScope) ([]*models.Deals, error) {
+func (r *dealRepoSpy) ListAllWithFilterAndArchiveScope(_ context.Context, _, _ int, filter repositories.DealListFilter, _ repositories.ArchiveScope) ([]*models.Deals, error) {
 	r.calledBranch = filter.BranchID
 	if filter.BranchID == nil {
 		r.calledAll = true
@@ -153,7 +154,7 @@ func (r *dealRepoSpy) ListAllWithFilterAndArchiveScope(_, _ int, filter reposito
 	return r.deals, nil
 }
 
-func (r *dealRepoSpy) CountAllWithFilterAndArchiveScope(filter repositories.DealListFilter, _ repositories.ArchiveScope) (int, error) {
+func (r *dealRepoSpy) CountAllWithFilterAndArchiveScope(_ context.Context, filter repositories.DealListFilter, _ repositories.ArchiveScope) (int, error) {
 	r.calledBranch = filter.BranchID
 	if filter.BranchID == nil {
 		r.calledAll = true
@@ -168,7 +169,7 @@ func TestListDealsForScope_BranchInjectsBranchID(t *testing.T) {
 	spy := &dealRepoSpy{}
 	scope := DataScope{Kind: ScopeKindBranch, BranchID: &branchID}
 
-	_, err := listDealsForScope(spy, scope, 10, 0, repositories.DealListFilter{}, repositories.ArchiveScopeActiveOnly)
+	_, err := listDealsForScope(context.Background(), spy, scope, 10, 0, repositories.DealListFilter{}, repositories.ArchiveScopeActiveOnly)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -182,7 +183,7 @@ func TestListDealsForScope_AllNoFilter(t *testing.T) {
 	spy := &dealRepoSpy{}
 	scope := DataScope{Kind: ScopeKindAll}
 
-	_, err := listDealsForScope(spy, scope, 10, 0, repositories.DealListFilter{}, repositories.ArchiveScopeActiveOnly)
+	_, err := listDealsForScope(context.Background(), spy, scope, 10, 0, repositories.DealListFilter{}, repositories.ArchiveScopeActiveOnly)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -198,7 +199,7 @@ func TestCountDealsForScope_BranchInjectsBranchID(t *testing.T) {
 	spy := &dealRepoSpy{deals: make([]*models.Deals, 4)}
 	scope := DataScope{Kind: ScopeKindBranch, BranchID: &branchID}
 
-	total, err := countDealsForScope(spy, scope, repositories.DealListFilter{}, repositories.ArchiveScopeActiveOnly)
+	total, err := countDealsForScope(context.Background(), spy, scope, repositories.DealListFilter{}, repositories.ArchiveScopeActiveOnly)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -215,7 +216,7 @@ func TestCountDealsForScope_AllNoFilter(t *testing.T) {
 	spy := &dealRepoSpy{deals: make([]*models.Deals, 2)}
 	scope := DataScope{Kind: ScopeKindAll}
 
-	total, err := countDealsForScope(spy, scope, repositories.DealListFilter{}, repositories.ArchiveScopeActiveOnly)
+	total, err := countDealsForScope(context.Background(), spy, scope, repositories.DealListFilter{}, repositories.ArchiveScopeActiveOnly)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -265,3 +266,26 @@ func TestDealMatchesScope_ForbiddenAlwaysFalse(t *testing.T) {
 		t.Errorf("Forbidden scope must never match a deal")
 	}
 }
+
+// TestDealFilterForRole_SalesPinnedToOwner verifies GET /deals keeps sales on
+// their own deals while their status/amount filters still apply.
+func TestDealFilterForRole_SalesPinnedToOwner(t *testing.T) {
+	other := 99
+	amountMin := 500.0
+	filter := repositories.DealListFilter{Status: "won", AmountMin: &amountMin, OwnerID: &other}
+
+	got := dealFilterForRole(10, authz.RoleSales, filter)
+	if got.OwnerID == nil || *got.OwnerID != 10 {
+		t.Fatalf("sales must be pinned to owner 10, got %v", got.OwnerID)
+	}
+	if got.Status != "won" || got.AmountMin == nil || *got.AmountMin != amountMin {
+		t.Fatalf("sales filters must be kept, got %+v", got)
+	}
+	if *filter.OwnerID != other {
+		t.Fatal("caller's filter must not be modified")
+	}
+
+	if got := dealFilterForRole(10, authz.RoleManagement, repositories.DealListFilter{}); got.OwnerID != nil {
+		t.Fatalf("management must not be pinned to an owner, got %v", *got.OwnerID)
+	}
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -59,7 +60,7 @@ func TestNormalizeRequiredDealClientType_Table(t *testing.T) {
 
 func TestDealCreate_ClientRepoNotConfigured(t *testing.T) {
 	svc := NewDealService(nil)
-	_, err := svc.Create(&models.Deals{
+	_, err := svc.Create(context.Background(), &models.Deals{
 		LeadID:     2,
 		ClientID:   4,
 		ClientType: "legal",
@@ -11,6 +11,7 @@ import (
 	"io/fs"
 	"log"
 	"mime/multipart"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -40,42 +41,67 @@ var (
 )
 
 type DocumentRepo interface {
-	Create(doc *models.Document) (int64, error)
-	GetByID(id int64) (*models.Document, error)
-	GetByIDWithArchiveScope(id int64, scope repositories.ArchiveScope) (*models.Document, error)
-	ListDocuments(limit, offset int) ([]*models.Document, error)
-	ListDocumentsWithArchiveScope(limit, offset int, scope repositories.ArchiveScope) ([]*models.Document, error)
-	ListDocumentsByDeal(dealID int64) ([]*models.Document, error)
-	ListDocumentsByDealWithArchiveScope(dealID int64, scope repositories.ArchiveScope) ([]*models.Document, error)
-	Delete(id int64) error
-	Archive(id int64, archivedBy int, reason string) error
-	Unarchive(id int64) error
-	UpdateStatus(id int64, status string) error
-	MarkSigned(id int64, signedBy string, signedAt time.Time) error
-	Update(doc *models.Document) error
-	UpdateSigningMeta(id int64, signMethod, signIP, signUserAgent, signMetadata string) error
+	Create(ctx context.Context, doc *models.Document) (int64, error)
+	GetByID(ctx context.Context, id int64) (*models.Document, error)
+	GetByIDWithArchiveScope(ctx context.Context, id int64, scope repositories.ArchiveScope) (*models.Document, error)
+	ListDocuments(ctx context.Context, limit, offset int) ([]*models.Document, error)
+	ListDocumentsWithArchiveScope(ctx context.Context, limit, offset int, scope repositories.ArchiveScope) ([]*models.Document, error)
+	ListDocumentsByDeal(ctx context.Context, dealID int64) ([]*models.Document, error)
+	ListDocumentsByDealWithArchiveScope(ctx context.Context, dealID int64, scope repositories.ArchiveScope) ([]*models.Document, error)
+	Delete(ctx context.Context, id int64) error
+	Archive(ctx context.Context, id int64, archivedBy int, reason string) error
+	Unarchive(ctx context.Context, id int64) error
+	UpdateStatus(ctx context.Context, id int64, status string) error
+	MarkSigned(ctx context.Context, id int64, signedBy string, signedAt time.Time) error
+	Update(ctx context.Context, doc *models.Document) error
+	UpdateSigningMeta(ctx context.Context, id int64, signMethod, signIP, signUserAgent, signMetadata string) error
 }
 
 type documentFilterRepo interface {
-	ListDocumentsWithFilterAndArchiveScope(limit, offset int, filter repositories.DocumentListFilter, scope repositories.ArchiveScope) ([]*models.Document, error)
-	ListDocumentsByDealWithFilterAndArchiveScope(dealID int64, filter repositories.DocumentListFilter, scope repositories.ArchiveScope) ([]*models.Document, error)
-	CountDocumentsWithFilterAndArchiveScope(filter repositories.DocumentListFilter, scope repositories.ArchiveScope) (int, error)
-	ListDocumentsByDealWithFilterAndArchiveScopePaginated(dealID int64, limit, offset int, filter repositories.DocumentListFilter, scope repositories.ArchiveScope) ([]*models.Document, error)
+	ListDocumentsWithFilterAndArchiveScope(ctx context.Context, limit, offset int, filter repositories.DocumentListFilter, scope repositories.ArchiveScope) ([]*models.Document, error)
+	ListDocumentsByDealWithFilterAndArchiveScope(ctx context.Context, dealID int64, filter repositories.DocumentListFilter, scope repositories.ArchiveScope) ([]*models.Document, error)
+	CountDocumentsWithFilterAndArchiveScope(ctx context.Context, filter repositories.DocumentListFilter, scope repositories.ArchiveScope) (int, error)
+	ListDocumentsByDealWithFilterAndArchiveScopePaginated(ctx context.Context, dealID int64, limit, offset int, filter repositories.DocumentListFilter, scope repositories.ArchiveScope) ([]*models.Document, error)
+}
+
+// documentSignAuditRepo is implemented by repositories that store who marked a
+// document signed; stores without it simply skip the audit columns.
+type documentSignAuditRepo interface {
+	SetSignatureAudit(ctx context.Context, id int64, signedByUserID int, signatureHash string) error
+}
+
+// documentReviewAuditRepo is implemented by repositories that store who
+// reviewed a document; stores without it skip the review columns.
+type documentReviewAuditRepo interface {
+	SetReviewAudit(ctx context.Context, id int64, reviewedByUserID int, reviewedAt time.Time) error
+}
+
+// SMSConfirmationChecker reports whether any signer has approved a
+// confirmation for the document on the given channel at or after since.
+type SMSConfirmationChecker interface {
+	HasApprovedForDocument(ctx context.Context, documentID int64, channel string, since time.Time) (bool, error)
 }
 
 type LeadRepo interface {
-	GetByID(id int) (*models.Leads, error)
+	GetByID(ctx context.Context, id int) (*models.Leads, error)
 }
 
 type DealRepo interface {
-	GetByID(id int) (*models.Deals, error)
-	GetByLeadID(leadID int) (*models.Deals, error)
-	GetLatestByClientID(clientID int) (*models.Deals, error)
-	GetLatestByClientRef(clientID int, clientType string) (*models.Deals, error)
+	GetByID(ctx context.Context, id int) (*models.Deals, error)
+	GetByLeadID(ctx context.Context, leadID int) (*models.Deals, error)
+	GetLatestByClientID(ctx context.Context, clientID int) (*models.Deals, error)
+	GetLatestByClientRef(ctx context.Context, clientID int, clientType string) (*models.Deals, error)
 }
 
 type ClientRepo interface {
-	GetByID(id int) (*models.Client, error)
+	GetByID(ctx context.Context, id int) (*models.Client, error)
+}
+
+// DocumentVersionRepo is the part of repositories.DocumentVersionRepository
+// used when a document is regenerated.
+type DocumentVersionRepo interface {
+	GetLatestVersion(ctx context.Context, docID int64) (int, error)
+	CreateVersion(ctx context.Context, v *models.DocumentVersion) (int64, error)
 }
 
 type DocumentService struct {
@@ -91,6 +117,13 @@ type DocumentService struct {
 	DocxGen   docx.Generator
 	XlsxGen   xlsx.Generator
 	Store     storage.Storage // nil = local disk only
+
+	VersionRepo DocumentVersionRepo // история при перегенерации; nil — без неё
+
+	// smsConfirmations / smsRequiredDocTypes enforce documents.require_sms_signature.
+	smsConfirmations    SMSConfirmationChecker
+	smsRequiredDocTypes map[string]bool
+
 	now       func() time.Time
 	displayTZ *time.Location
 }
@@ -103,6 +136,58 @@ func (s *DocumentService) SetStore(store storage.Storage) {
 	s.Store = store
 }
 
+func (s *DocumentService) SetVersionRepo(repo DocumentVersionRepo) {
+	s.VersionRepo = repo
+}
+
+// SetSMSSignatureRequirement makes manual signing of the listed doc types
+// wait for an approved SMS confirmation; "*" covers every type. An empty list
+// keeps manual signing independent of SMS.
+func (s *DocumentService) SetSMSSignatureRequirement(checker SMSConfirmationChecker, docTypes []string) {
+	s.smsConfirmations = checker
+	s.smsRequiredDocTypes = nil
+	for _, docType := range docTypes {
+		docType = strings.ToLower(strings.TrimSpace(docType))
+		if docType == "" {
+			continue
+		}
+		if s.smsRequiredDocTypes == nil {
+			s.smsRequiredDocTypes = make(map[string]bool)
+		}
+		s.smsRequiredDocTypes[docType] = true
+	}
+}
+
+// ensureSMSConfirmed returns ErrSMSSignatureRequired when doc's type requires
+// an SMS signature that has not been confirmed in the current signing round.
+func (s *DocumentService) ensureSMSConfirmed(ctx context.Context, doc *models.Document) error {
+	docType := strings.ToLower(strings.TrimSpace(doc.DocType))
+	if !s.smsRequiredDocTypes["*"] && !s.smsRequiredDocTypes[docType] {
+		return nil
+	}
+	if s.smsConfirmations == nil {
+		return ErrSMSSignatureRequired
+	}
+	ok, err := s.smsConfirmations.HasApprovedForDocument(ctx, doc.ID, "sms", signingRoundStart(doc))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrSMSSignatureRequired
+	}
+	return nil
+}
+
+// signingRoundStart is when doc's current signing round began: its last review
+// decision, or its creation when it has never been reviewed. Every approve or
+// return starts a new round, so confirmations from earlier rounds do not count.
+func signingRoundStart(doc *models.Document) time.Time {
+	if doc.ReviewedAt != nil {
+		return *doc.ReviewedAt
+	}
+	return doc.CreatedAt
+}
+
 func (s *DocumentService) branchScopeForRole(userID, roleID int) (*int, error) {
 	switch roleID {
 	case authz.RoleSales, authz.RoleVisa, authz.RoleControl, authz.RolePartner:
@@ -153,26 +238,26 @@ func dealMatchesBranch(scope *int, deal *models.Deals) bool {
 
 func (s *DocumentService) ensureDealAccess(deal *models.Deals, userID, roleID int) error {
 	if deal == nil {
-		return errors.New("not found")
+		return ErrNotFound
 	}
 	if roleID == authz.RoleSales && deal.OwnerID != userID {
-		return errors.New("forbidden")
+		return ErrForbidden
 	}
 	branchScope, err := s.branchScopeForRole(userID, roleID)
 	if err != nil {
 		return err
 	}
 	if !dealMatchesBranch(branchScope, deal) {
-		return errors.New("forbidden")
+		return ErrForbidden
 	}
 	return nil
 }
 
-func (s *DocumentService) loadDocumentDealForAccess(doc *models.Document, userID, roleID int) (*models.Deals, error) {
+func (s *DocumentService) loadDocumentDealForAccess(ctx context.Context, doc *models.Document, userID, roleID int) (*models.Deals, error) {
 	if doc == nil {
 		return nil, ErrNotFound
 	}
-	deal, err := s.DealRepo.GetByID(int(doc.DealID))
+	deal, err := s.DealRepo.GetByID(ctx, int(doc.DealID))
 	if err != nil || deal == nil {
 		return nil, ErrNotFound
 	}
@@ -282,6 +367,15 @@ func (s *DocumentService) SetTimeProvider(now func() time.Time, displayTZ *time.
 	}
 }
 
+// currentTime returns the configured clock, falling back to time.Now for
+// services built without NewDocumentService.
+func (s *DocumentService) currentTime() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
+
 // DOCX-шаблоны для client-ориентированных документов
 var clientDocDocxMap = map[string]string{
 	"contract_full":          "contract_full.docx",
@@ -382,36 +476,36 @@ func isSupportedDocType(value string) bool {
 }
 
 // PrepareForSignature подготавливает документ к юридически значимой подписи
-func (s *DocumentService) PrepareForSignature(id int64, userID, roleID int) error {
+func (s *DocumentService) PrepareForSignature(ctx context.Context, id int64, userID, roleID int) error {
 	if !authz.HasPermission(authz.RoleCodeByID(roleID), "documents.send") {
-		return errors.New("forbidden")
+		return ErrForbidden
 	}
 
-	doc, err := s.DocRepo.GetByID(id)
+	doc, err := s.DocRepo.GetByID(ctx, id)
 	if err != nil || doc == nil {
-		return errors.New("not found")
+		return ErrNotFound
 	}
 
-	if _, err := s.loadDocumentDealForAccess(doc, userID, roleID); err != nil {
+	if _, err := s.loadDocumentDealForAccess(ctx, doc, userID, roleID); err != nil {
 		return err
 	}
 
 	if doc.Status != "approved" {
-		return errors.New("document must be approved before signature")
+		return ErrDocumentNotApproved
 	}
 
 	// Меняем статус на "готов к подписи"
-	return s.DocRepo.UpdateStatus(id, "sent_for_signature")
+	return s.DocRepo.UpdateStatus(ctx, id, "sent_for_signature")
 }
 
 // GetSignatureMetadata получает метаданные подписи документа
-func (s *DocumentService) GetSignatureMetadata(id int64, userID, roleID int) (map[string]interface{}, error) {
-	doc, err := s.DocRepo.GetByID(id)
+func (s *DocumentService) GetSignatureMetadata(ctx context.Context, id int64, userID, roleID int) (map[string]interface{}, error) {
+	doc, err := s.DocRepo.GetByID(ctx, id)
 	if err != nil || doc == nil {
-		return nil, errors.New("not found")
+		return nil, ErrNotFound
 	}
 
-	if _, err := s.loadDocumentDealForAccess(doc, userID, roleID); err != nil {
+	if _, err := s.loadDocumentDealForAccess(ctx, doc, userID, roleID); err != nil {
 		return nil, err
 	}
 
@@ -465,23 +559,23 @@ func (s *DocumentService) GetLegalConsentText(docType string) string {
 
 // ====================== CRUD ======================
 
-func (s *DocumentService) CreateDocument(doc *models.Document, userID, roleID int) (int64, error) {
+func (s *DocumentService) CreateDocument(ctx context.Context, doc *models.Document, userID, roleID int) (int64, error) {
 	if !authz.HasPermission(authz.RoleCodeByID(roleID), "documents.create") {
-		return 0, errors.New("forbidden")
+		return 0, ErrForbidden
 	}
 
 	if doc.DealID == 0 {
-		return 0, errors.New("deal not found")
+		return 0, ErrDealNotFound
 	}
 
-	deal, err := s.DealRepo.GetByID(int(doc.DealID))
+	deal, err := s.DealRepo.GetByID(ctx, int(doc.DealID))
 	if err != nil || deal == nil {
-		return 0, errors.New("deal not found")
+		return 0, ErrDealNotFound
 	}
 
 	doc.DocType = normalizeDocType(doc.DocType)
 	if !isSupportedDocType(doc.DocType) {
-		return 0, errors.New("unsupported doc_type")
+		return 0, ErrUnsupportedDocType
 	}
 
 	if err := s.ensureDealAccess(deal, userID, roleID); err != nil {
@@ -506,38 +600,38 @@ func (s *DocumentService) CreateDocument(doc *models.Document, userID, roleID in
 		doc.IsHidden = true
 	}
 
-	return s.DocRepo.Create(doc)
+	return s.DocRepo.Create(ctx, doc)
 }
 
 // UploadDocument сохраняет присланный файл и создает запись документа.
-func (s *DocumentService) UploadDocument(dealID int64, docType string, file *multipart.FileHeader, userID, roleID int) (*models.Document, error) {
+func (s *DocumentService) UploadDocument(ctx context.Context, dealID int64, docType string, file *multipart.FileHeader, userID, roleID int) (*models.Document, error) {
 	if !authz.HasPermission(authz.RoleCodeByID(roleID), "documents.create") {
-		return nil, errors.New("forbidden")
+		return nil, ErrForbidden
 	}
 	if dealID == 0 {
-		return nil, errors.New("deal not found")
+		return nil, ErrDealNotFound
 	}
-	deal, err := s.DealRepo.GetByID(int(dealID))
+	deal, err := s.DealRepo.GetByID(ctx, int(dealID))
 	if err != nil || deal == nil {
-		return nil, errors.New("deal not found")
+		return nil, ErrDealNotFound
 	}
 	if err := s.ensureDealAccess(deal, userID, roleID); err != nil {
 		return nil, err
 	}
 	docType = normalizeDocType(docType)
 	if docType == "" {
-		return nil, errors.New("doc_type is required")
+		return nil, ErrDocTypeRequired
 	}
 	if !isSupportedDocType(docType) {
-		return nil, errors.New("unsupported doc_type")
+		return nil, ErrUnsupportedDocType
 	}
 	if file == nil {
-		return nil, errors.New("file is required")
+		return nil, ErrFileRequired
 	}
 
 	safeName := filepath.Base(file.Filename)
 	if safeName == "" || safeName == "." {
-		return nil, errors.New("invalid filename")
+		return nil, ErrInvalidFilename
 	}
 	finalName := fmt.Sprintf("%d_%s", time.Now().UnixNano(), safeName)
 
@@ -565,7 +659,7 @@ func (s *DocumentService) UploadDocument(dealID int64, docType string, file *mul
 		v := int64(*deal.BranchID)
 		doc.BranchID = &v
 	}
-	id, err := s.DocRepo.Create(doc)
+	id, err := s.DocRepo.Create(ctx, doc)
 	if err != nil {
 		return nil, err
 	}
@@ -573,10 +667,10 @@ func (s *DocumentService) UploadDocument(dealID int64, docType string, file *mul
 	return doc, nil
 }
 
-func (s *DocumentService) UploadDocumentWithMeta(scope, title, description string, targetUserID *int64, file *multipart.FileHeader, userID, roleID int) (*models.Document, error) {
+func (s *DocumentService) UploadDocumentWithMeta(ctx context.Context, scope, title, description string, targetUserID *int64, file *multipart.FileHeader, userID, roleID int) (*models.Document, error) {
 	safeName := filepath.Base(file.Filename)
 	if safeName == "" || safeName == "." {
-		return nil, errors.New("invalid filename")
+		return nil, ErrInvalidFilename
 	}
 	finalName := fmt.Sprintf("%d_%s", time.Now().UnixNano(), safeName)
 	relPath := filepath.ToSlash(filepath.Join("scoped", scope, finalName))
@@ -601,7 +695,7 @@ func (s *DocumentService) UploadDocumentWithMeta(scope, title, description strin
 		FilePath:     relPath,
 		CreatedBy:    &createdBy,
 	}
-	id, err := s.DocRepo.Create(doc)
+	id, err := s.DocRepo.Create(ctx, doc)
 	if err != nil {
 		return nil, err
 	}
@@ -609,74 +703,74 @@ func (s *DocumentService) UploadDocumentWithMeta(scope, title, description strin
 	return doc, nil
 }
 
-func (s *DocumentService) GetDocument(id int64, userID, roleID int) (*models.Document, error) {
-	doc, err := s.DocRepo.GetByID(id)
+func (s *DocumentService) GetDocument(ctx context.Context, id int64, userID, roleID int) (*models.Document, error) {
+	doc, err := s.DocRepo.GetByID(ctx, id)
 	if err != nil || doc == nil {
 		return nil, err
 	}
 	if !isHiddenDocVisible(doc, userID, roleID) {
-		return nil, errors.New("forbidden")
+		return nil, ErrForbidden
 	}
 	if roleID != authz.RoleSales && roleID != authz.RoleVisa && roleID != authz.RoleControl {
 		return doc, nil
 	}
 	if s.DealRepo == nil {
-		return nil, errors.New("not found")
+		return nil, ErrNotFound
 	}
-	deal, derr := s.DealRepo.GetByID(int(doc.DealID))
+	deal, derr := s.DealRepo.GetByID(ctx, int(doc.DealID))
 	if derr != nil || deal == nil {
-		return nil, errors.New("not found")
+		return nil, ErrNotFound
 	}
 	if err := s.ensureDealAccess(deal, userID, roleID); err != nil {
-		if err.Error() == "forbidden" {
-			return nil, errors.New("forbidden")
+		if errors.Is(err, ErrForbidden) {
+			return nil, ErrForbidden
 		}
-		return nil, errors.New("not found")
+		return nil, ErrNotFound
 	}
 	return doc, nil
 }
 
-func (s *DocumentService) GetDocumentWithArchiveScope(id int64, userID, roleID int, scope repositories.ArchiveScope) (*models.Document, error) {
-	doc, err := s.DocRepo.GetByIDWithArchiveScope(id, scope)
+func (s *DocumentService) GetDocumentWithArchiveScope(ctx context.Context, id int64, userID, roleID int, scope repositories.ArchiveScope) (*models.Document, error) {
+	doc, err := s.DocRepo.GetByIDWithArchiveScope(ctx, id, scope)
 	if err != nil || doc == nil {
 		return nil, err
 	}
 	if !isHiddenDocVisible(doc, userID, roleID) {
-		return nil, errors.New("forbidden")
+		return nil, ErrForbidden
 	}
 	if roleID != authz.RoleSales && roleID != authz.RoleVisa && roleID != authz.RoleControl {
 		return doc, nil
 	}
 	if s.DealRepo == nil {
-		return nil, errors.New("not found")
+		return nil, ErrNotFound
 	}
-	deal, derr := s.DealRepo.GetByID(int(doc.DealID))
+	deal, derr := s.DealRepo.GetByID(ctx, int(doc.DealID))
 	if derr != nil || deal == nil {
-		return nil, errors.New("not found")
+		return nil, ErrNotFound
 	}
 	if err := s.ensureDealAccess(deal, userID, roleID); err != nil {
-		if err.Error() == "forbidden" {
-			return nil, errors.New("forbidden")
+		if errors.Is(err, ErrForbidden) {
+			return nil, ErrForbidden
 		}
-		return nil, errors.New("not found")
+		return nil, ErrNotFound
 	}
 	return doc, nil
 }
 
-func (s *DocumentService) ResolveSignerEmail(id int64, userID, roleID int, fallbackEmail string) (string, error) {
-	resolved, err := s.ResolveSignerForEmail(id, userID, roleID, SignerOverrides{Email: fallbackEmail})
+func (s *DocumentService) ResolveSignerEmail(ctx context.Context, id int64, userID, roleID int, fallbackEmail string) (string, error) {
+	resolved, err := s.ResolveSignerForEmail(ctx, id, userID, roleID, SignerOverrides{Email: fallbackEmail})
 	if err != nil {
 		return "", err
 	}
 	return resolved.Email, nil
 }
 
-func (s *DocumentService) ResolveSigner(id int64, userID, roleID int, overrides SignerOverrides) (ResolvedSigner, error) {
-	return s.ResolveSignerForEmail(id, userID, roleID, overrides)
+func (s *DocumentService) ResolveSigner(ctx context.Context, id int64, userID, roleID int, overrides SignerOverrides) (ResolvedSigner, error) {
+	return s.ResolveSignerForEmail(ctx, id, userID, roleID, overrides)
 }
 
-func (s *DocumentService) ResolveSignerForEmail(id int64, userID, roleID int, overrides SignerOverrides) (ResolvedSigner, error) {
-	result, err := s.resolveSignerBase(id, userID, roleID, overrides)
+func (s *DocumentService) ResolveSignerForEmail(ctx context.Context, id int64, userID, roleID int, overrides SignerOverrides) (ResolvedSigner, error) {
+	result, err := s.resolveSignerBase(ctx, id, userID, roleID, overrides)
 	if err != nil {
 		return ResolvedSigner{}, err
 	}
@@ -686,8 +780,8 @@ func (s *DocumentService) ResolveSignerForEmail(id int64, userID, roleID int, ov
 	return result, nil
 }
 
-func (s *DocumentService) ResolveSignerForSMS(id int64, userID, roleID int, overrides SignerOverrides) (ResolvedSigner, error) {
-	result, err := s.resolveSignerBase(id, userID, roleID, overrides)
+func (s *DocumentService) ResolveSignerForSMS(ctx context.Context, id int64, userID, roleID int, overrides SignerOverrides) (ResolvedSigner, error) {
+	result, err := s.resolveSignerBase(ctx, id, userID, roleID, overrides)
 	if err != nil {
 		return ResolvedSigner{}, err
 	}
@@ -697,23 +791,23 @@ func (s *DocumentService) ResolveSignerForSMS(id int64, userID, roleID int, over
 	return result, nil
 }
 
-func (s *DocumentService) ResolveSignerDefault(id int64, userID, roleID int, overrides SignerOverrides) (ResolvedSigner, error) {
-	return s.resolveSignerBase(id, userID, roleID, overrides)
+func (s *DocumentService) ResolveSignerDefault(ctx context.Context, id int64, userID, roleID int, overrides SignerOverrides) (ResolvedSigner, error) {
+	return s.resolveSignerBase(ctx, id, userID, roleID, overrides)
 }
 
-func (s *DocumentService) GetSigningContactOptions(id int64, userID, roleID int) (SigningContactOptions, error) {
-	doc, err := s.GetDocument(id, userID, roleID)
+func (s *DocumentService) GetSigningContactOptions(ctx context.Context, id int64, userID, roleID int) (SigningContactOptions, error) {
+	doc, err := s.GetDocument(ctx, id, userID, roleID)
 	if err != nil || doc == nil {
 		return SigningContactOptions{}, err
 	}
 	if s.DealRepo == nil {
 		return SigningContactOptions{DocumentID: id}, nil
 	}
-	deal, err := s.DealRepo.GetByID(int(doc.DealID))
+	deal, err := s.DealRepo.GetByID(ctx, int(doc.DealID))
 	if err != nil || deal == nil {
 		return SigningContactOptions{}, err
 	}
-	resolved, err := s.ResolveSignerDefault(id, userID, roleID, SignerOverrides{})
+	resolved, err := s.ResolveSignerDefault(ctx, id, userID, roleID, SignerOverrides{})
 	if err != nil {
 		return SigningContactOptions{}, err
 	}
@@ -741,8 +835,8 @@ func (s *DocumentService) GetSigningContactOptions(id int64, userID, roleID int)
 	}, nil
 }
 
-func (s *DocumentService) resolveSignerBase(id int64, userID, roleID int, overrides SignerOverrides) (ResolvedSigner, error) {
-	doc, err := s.GetDocument(id, userID, roleID)
+func (s *DocumentService) resolveSignerBase(ctx context.Context, id int64, userID, roleID int, overrides SignerOverrides) (ResolvedSigner, error) {
+	doc, err := s.GetDocument(ctx, id, userID, roleID)
 	if err != nil || doc == nil {
 		return ResolvedSigner{}, err
 	}
@@ -754,11 +848,11 @@ func (s *DocumentService) resolveSignerBase(id int64, userID, roleID int, overri
 	if s.DealRepo == nil || s.ClientRepo == nil {
 		return ResolvedSigner{Email: overrides.Email, FullName: overrides.FullName, Position: overrides.Position, Phone: normalizedOverridePhone}, nil
 	}
-	deal, err := s.DealRepo.GetByID(int(doc.DealID))
+	deal, err := s.DealRepo.GetByID(ctx, int(doc.DealID))
 	if err != nil || deal == nil {
 		return ResolvedSigner{Email: overrides.Email, FullName: overrides.FullName, Position: overrides.Position, Phone: normalizedOverridePhone}, nil
 	}
-	client, err := s.ClientRepo.GetByID(deal.ClientID)
+	client, err := s.ClientRepo.GetByID(ctx, deal.ClientID)
 	if err != nil || client == nil {
 		return ResolvedSigner{Email: overrides.Email, FullName: overrides.FullName, Position: overrides.Position, Phone: normalizedOverridePhone}, nil
 	}
@@ -807,29 +901,29 @@ func (s *DocumentService) resolveSignerBase(id int64, userID, roleID int, overri
 	return result, nil
 }
 
-func (s *DocumentService) ListDocuments(limit, offset int) ([]*models.Document, error) {
-	return s.DocRepo.ListDocumentsWithArchiveScope(limit, offset, repositories.ArchiveScopeActiveOnly)
+func (s *DocumentService) ListDocuments(ctx context.Context, limit, offset int) ([]*models.Document, error) {
+	return s.DocRepo.ListDocumentsWithArchiveScope(ctx, limit, offset, repositories.ArchiveScopeActiveOnly)
 }
 
-func (s *DocumentService) ListDocumentsWithArchiveScope(limit, offset int, scope repositories.ArchiveScope) ([]*models.Document, error) {
-	return s.DocRepo.ListDocumentsWithArchiveScope(limit, offset, scope)
+func (s *DocumentService) ListDocumentsWithArchiveScope(ctx context.Context, limit, offset int, scope repositories.ArchiveScope) ([]*models.Document, error) {
+	return s.DocRepo.ListDocumentsWithArchiveScope(ctx, limit, offset, scope)
 }
 
-func (s *DocumentService) ListDocumentsWithFilterAndArchiveScope(limit, offset int, filter repositories.DocumentListFilter, scope repositories.ArchiveScope) ([]*models.Document, error) {
+func (s *DocumentService) ListDocumentsWithFilterAndArchiveScope(ctx context.Context, limit, offset int, filter repositories.DocumentListFilter, scope repositories.ArchiveScope) ([]*models.Document, error) {
 	if repo, ok := s.DocRepo.(documentFilterRepo); ok {
-		return repo.ListDocumentsWithFilterAndArchiveScope(limit, offset, filter, scope)
+		return repo.ListDocumentsWithFilterAndArchiveScope(ctx, limit, offset, filter, scope)
 	}
-	return s.DocRepo.ListDocumentsWithArchiveScope(limit, offset, scope)
+	return s.DocRepo.ListDocumentsWithArchiveScope(ctx, limit, offset, scope)
 }
 
-func (s *DocumentService) ListDocumentsByDeal(dealID int64, userID, roleID int, scope repositories.ArchiveScope) ([]*models.Document, error) {
-	return s.ListDocumentsByDealWithFilter(dealID, userID, roleID, repositories.DocumentListFilter{}, scope)
+func (s *DocumentService) ListDocumentsByDeal(ctx context.Context, dealID int64, userID, roleID int, scope repositories.ArchiveScope) ([]*models.Document, error) {
+	return s.ListDocumentsByDealWithFilter(ctx, dealID, userID, roleID, repositories.DocumentListFilter{}, scope)
 }
 
-func (s *DocumentService) ListDocumentsByDealWithFilter(dealID int64, userID, roleID int, filter repositories.DocumentListFilter, scope repositories.ArchiveScope) ([]*models.Document, error) {
-	deal, err := s.DealRepo.GetByID(int(dealID))
+func (s *DocumentService) ListDocumentsByDealWithFilter(ctx context.Context, dealID int64, userID, roleID int, filter repositories.DocumentListFilter, scope repositories.ArchiveScope) ([]*models.Document, error) {
+	deal, err := s.DealRepo.GetByID(ctx, int(dealID))
 	if err != nil || deal == nil {
-		return nil, errors.New("not found")
+		return nil, ErrNotFound
 	}
 	if err := s.ensureDealAccess(deal, userID, roleID); err != nil {
 		return nil, err
@@ -838,13 +932,13 @@ func (s *DocumentService) ListDocumentsByDealWithFilter(dealID int64, userID, ro
 		filter.HiddenVisibilityUserID = &userID
 	}
 	if repo, ok := s.DocRepo.(documentFilterRepo); ok {
-		return repo.ListDocumentsByDealWithFilterAndArchiveScope(dealID, filter, scope)
+		return repo.ListDocumentsByDealWithFilterAndArchiveScope(ctx, dealID, filter, scope)
 	}
-	return s.DocRepo.ListDocumentsByDealWithArchiveScope(dealID, scope)
+	return s.DocRepo.ListDocumentsByDealWithArchiveScope(ctx, dealID, scope)
 }
 
-func (s *DocumentService) ListDocumentsWithFilterAndArchiveScopeAndTotal(limit, offset int, filter repositories.DocumentListFilter, scope repositories.ArchiveScope) ([]*models.Document, int, error) {
-	items, err := s.ListDocumentsWithFilterAndArchiveScope(limit, offset, filter, scope)
+func (s *DocumentService) ListDocumentsWithFilterAndArchiveScopeAndTotal(ctx context.Context, limit, offset int, filter repositories.DocumentListFilter, scope repositories.ArchiveScope) ([]*models.Document, int, error) {
+	items, err := s.ListDocumentsWithFilterAndArchiveScope(ctx, limit, offset, filter, scope)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -852,61 +946,78 @@ func (s *DocumentService) ListDocumentsWithFilterAndArchiveScopeAndTotal(limit,
 	if !ok {
 		return items, len(items), nil
 	}
-	total, err := repo.CountDocumentsWithFilterAndArchiveScope(filter, scope)
+	total, err := repo.CountDocumentsWithFilterAndArchiveScope(ctx, filter, scope)
 	if err != nil {
 		return nil, 0, err
 	}
 	return items, total, nil
 }
 
-func (s *DocumentService) ListDocumentsByDealWithFilterAndTotal(dealID int64, userID, roleID, limit, offset int, filter repositories.DocumentListFilter, scope repositories.ArchiveScope) ([]*models.Document, int, error) {
-	deal, err := s.DealRepo.GetByID(int(dealID))
-	if err != nil || deal == nil {
-		return nil, 0, errors.New("not found")
-	}
-	if err := s.ensureDealAccess(deal, userID, roleID); err != nil {
+func (s *DocumentService) ListDocumentsByDealWithFilterAndTotal(ctx context.Context, dealID int64, userID, roleID, limit, offset int, filter repositories.DocumentListFilter, scope repositories.ArchiveScope) ([]*models.Document, int, error) {
+	items, err := s.ListDocumentsByDealWithFilterPaginated(ctx, dealID, userID, roleID, limit, offset, filter, scope)
+	if err != nil {
 		return nil, 0, err
 	}
+	repo, ok := s.DocRepo.(documentFilterRepo)
+	if !ok {
+		return items, len(items), nil
+	}
 	if roleID != authz.RoleSystemAdmin {
 		filter.HiddenVisibilityUserID = &userID
 	}
+	filter.DealID = &dealID
+	total, err := repo.CountDocumentsWithFilterAndArchiveScope(ctx, filter, scope)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// ListDocumentsByDealWithFilterPaginated returns one limit/offset window of the
+// deal's documents without counting the rest.
+func (s *DocumentService) ListDocumentsByDealWithFilterPaginated(ctx context.Context, dealID int64, userID, roleID, limit, offset int, filter repositories.DocumentListFilter, scope repositories.ArchiveScope) ([]*models.Document, error) {
 	repo, ok := s.DocRepo.(documentFilterRepo)
 	if !ok {
-		items, listErr := s.ListDocumentsByDealWithFilter(dealID, userID, roleID, filter, scope)
-		if listErr != nil {
-			return nil, 0, listErr
+		items, err := s.ListDocumentsByDealWithFilter(ctx, dealID, userID, roleID, filter, scope)
+		if err != nil {
+			return nil, err
 		}
 		if items == nil {
 			items = make([]*models.Document, 0)
 		}
-		return items, len(items), nil
+		return items, nil
+	}
+	deal, err := s.DealRepo.GetByID(ctx, int(dealID))
+	if err != nil || deal == nil {
+		return nil, ErrNotFound
+	}
+	if err := s.ensureDealAccess(deal, userID, roleID); err != nil {
+		return nil, err
 	}
-	items, err := repo.ListDocumentsByDealWithFilterAndArchiveScopePaginated(dealID, limit, offset, filter, scope)
+	if roleID != authz.RoleSystemAdmin {
+		filter.HiddenVisibilityUserID = &userID
+	}
+	items, err := repo.ListDocumentsByDealWithFilterAndArchiveScopePaginated(ctx, dealID, limit, offset, filter, scope)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	if items == nil {
 		items = make([]*models.Document, 0)
 	}
-	filter.DealID = &dealID
-	total, err := repo.CountDocumentsWithFilterAndArchiveScope(filter, scope)
-	if err != nil {
-		return nil, 0, err
-	}
-	return items, total, nil
+	return items, nil
 }
 
-func (s *DocumentService) DeleteDocument(id int64, userID, roleID int) error {
-	if !authz.CanHardDeleteBusinessEntity(roleID) {
-		return errors.New("forbidden")
+func (s *DocumentService) DeleteDocument(ctx context.Context, id int64, userID, roleID int) error {
+	if !authz.HasCapability(roleID, authz.CapDeleteDocument) {
+		return ErrForbidden
 	}
-	doc, err := s.DocRepo.GetByIDWithArchiveScope(id, repositories.ArchiveScopeAll)
+	doc, err := s.DocRepo.GetByIDWithArchiveScope(ctx, id, repositories.ArchiveScopeAll)
 	if err != nil || doc == nil {
-		return errors.New("not found")
+		return ErrNotFound
 	}
-	deal, derr := s.DealRepo.GetByID(int(doc.DealID))
+	deal, derr := s.DealRepo.GetByID(ctx, int(doc.DealID))
 	if derr != nil || deal == nil {
-		return errors.New("not found")
+		return ErrNotFound
 	}
 	if err := s.ensureDealAccess(deal, userID, roleID); err != nil {
 		return err
@@ -914,23 +1025,23 @@ func (s *DocumentService) DeleteDocument(id int64, userID, roleID int) error {
 	if err := s.deleteDocumentFiles(doc); err != nil {
 		return err
 	}
-	return s.DocRepo.Delete(id)
+	return s.DocRepo.Delete(ctx, id)
 }
 
-func (s *DocumentService) ArchiveDocument(id int64, userID, roleID int, reason string) error {
+func (s *DocumentService) ArchiveDocument(ctx context.Context, id int64, userID, roleID int, reason string) error {
 	if !authz.HasPermission(authz.RoleCodeByID(roleID), "documents.update") {
-		return errors.New("forbidden")
+		return ErrForbidden
 	}
-	doc, err := s.DocRepo.GetByIDWithArchiveScope(id, repositories.ArchiveScopeAll)
+	doc, err := s.DocRepo.GetByIDWithArchiveScope(ctx, id, repositories.ArchiveScopeAll)
 	if err != nil || doc == nil {
-		return errors.New("not found")
+		return ErrNotFound
 	}
 	if !isHiddenDocVisible(doc, userID, roleID) {
-		return errors.New("forbidden")
+		return ErrForbidden
 	}
-	deal, derr := s.DealRepo.GetByID(int(doc.DealID))
+	deal, derr := s.DealRepo.GetByID(ctx, int(doc.DealID))
 	if derr != nil || deal == nil {
-		return errors.New("not found")
+		return ErrNotFound
 	}
 	if err := s.ensureDealAccess(deal, userID, roleID); err != nil {
 		return err
@@ -938,117 +1049,180 @@ func (s *DocumentService) ArchiveDocument(id int64, userID, roleID int, reason s
 	if doc.IsArchived {
 		return nil
 	}
-	return s.DocRepo.Archive(id, userID, reason)
+	return s.DocRepo.Archive(ctx, id, userID, reason)
 }
 
-func (s *DocumentService) UnarchiveDocument(id int64, userID, roleID int) error {
+func (s *DocumentService) UnarchiveDocument(ctx context.Context, id int64, userID, roleID int) error {
 	if !authz.HasPermission(authz.RoleCodeByID(roleID), "documents.update") {
-		return errors.New("forbidden")
+		return ErrForbidden
 	}
-	doc, err := s.DocRepo.GetByIDWithArchiveScope(id, repositories.ArchiveScopeAll)
+	doc, err := s.DocRepo.GetByIDWithArchiveScope(ctx, id, repositories.ArchiveScopeAll)
 	if err != nil || doc == nil {
-		return errors.New("not found")
+		return ErrNotFound
 	}
 	if !isHiddenDocVisible(doc, userID, roleID) {
-		return errors.New("forbidden")
+		return ErrForbidden
 	}
-	deal, derr := s.DealRepo.GetByID(int(doc.DealID))
+	deal, derr := s.DealRepo.GetByID(ctx, int(doc.DealID))
 	if derr != nil || deal == nil {
-		return errors.New("not found")
+		return ErrNotFound
 	}
 	if err := s.ensureDealAccess(deal, userID, roleID); err != nil {
 		return err
 	}
 	if !doc.IsArchived {
-		return errors.New("not archived")
+		return ErrNotArchived
 	}
-	return s.DocRepo.Unarchive(id)
+	return s.DocRepo.Unarchive(ctx, id)
 }
 
 // ================== Статусы ==================
 
-func (s *DocumentService) Submit(id int64, userID, roleID int) error {
+func (s *DocumentService) Submit(ctx context.Context, id int64, userID, roleID int) error {
 	if !authz.HasPermission(authz.RoleCodeByID(roleID), "documents.update") {
-		return errors.New("forbidden")
+		return ErrForbidden
 	}
-	doc, err := s.DocRepo.GetByID(id)
+	doc, err := s.DocRepo.GetByID(ctx, id)
 	if err != nil || doc == nil {
-		return errors.New("not found")
+		return ErrNotFound
 	}
-	deal, derr := s.DealRepo.GetByID(int(doc.DealID))
+	deal, derr := s.DealRepo.GetByID(ctx, int(doc.DealID))
 	if derr != nil || deal == nil {
-		return errors.New("not found")
+		return ErrNotFound
 	}
 	if err := s.ensureDealAccess(deal, userID, roleID); err != nil {
 		return err
 	}
 	if doc.Status != "draft" {
-		return errors.New("invalid status")
+		return ErrInvalidStatus
 	}
-	return s.DocRepo.UpdateStatus(id, "under_review")
+	return s.DocRepo.UpdateStatus(ctx, id, "under_review")
 }
 
-func (s *DocumentService) Review(id int64, action string, userID, roleID int) error {
+func (s *DocumentService) Review(ctx context.Context, id int64, action string, userID, roleID int) error {
 	if !authz.CanProcessDocuments(roleID) {
-		return errors.New("forbidden")
+		return ErrForbidden
 	}
-	doc, err := s.DocRepo.GetByID(id)
+	doc, err := s.DocRepo.GetByID(ctx, id)
 	if err != nil || doc == nil {
-		return errors.New("not found")
+		return ErrNotFound
 	}
 	if doc.Status != "under_review" {
-		return errors.New("invalid status")
+		return ErrInvalidStatus
 	}
-	if _, err := s.loadDocumentDealForAccess(doc, userID, roleID); err != nil {
+	if _, err := s.loadDocumentDealForAccess(ctx, doc, userID, roleID); err != nil {
 		return err
 	}
+	status := ""
 	switch action {
 	case "approve":
-		return s.DocRepo.UpdateStatus(id, "approved")
+		status = "approved"
 	case "return":
-		return s.DocRepo.UpdateStatus(id, "returned")
+		status = "returned"
 	default:
-		return errors.New("bad action")
+		return ErrBadAction
 	}
+	if err := s.DocRepo.UpdateStatus(ctx, id, status); err != nil {
+		return err
+	}
+	if repo, ok := s.DocRepo.(documentReviewAuditRepo); ok {
+		if err := repo.SetReviewAudit(ctx, id, userID, s.currentTime()); err != nil {
+			log.Printf("[doc][review][audit] document_id=%d store_failed err=%v", id, err)
+		}
+	}
+	return nil
 }
 
-func (s *DocumentService) Sign(id int64, userID, roleID int) error {
+func (s *DocumentService) Sign(ctx context.Context, id int64, userID, roleID int) error {
 	// Только Management вручную
 	if roleID != authz.RoleManagement && roleID != authz.RoleSystemAdmin {
-		return errors.New("forbidden")
+		return ErrForbidden
 	}
-	doc, err := s.DocRepo.GetByID(id)
+	doc, err := s.DocRepo.GetByID(ctx, id)
 	if err != nil || doc == nil {
-		return errors.New("not found")
+		return ErrNotFound
 	}
 	if !(doc.Status == "approved" || doc.Status == "returned") {
-		return errors.New("invalid status")
+		return ErrInvalidStatus
+	}
+	if err := s.ensureSMSConfirmed(ctx, doc); err != nil {
+		return err
+	}
+	if err := s.DocRepo.MarkSigned(ctx, id, "", s.currentTime()); err != nil {
+		return err
 	}
-	return s.DocRepo.MarkSigned(id, "", time.Now())
+	s.recordSignatureAudit(ctx, doc, userID)
+	return nil
 }
 
-func (s *DocumentService) MarkDocumentSigned(id int64, signedBy string, signedAt *time.Time, userID, roleID int) error {
+func (s *DocumentService) MarkDocumentSigned(ctx context.Context, id int64, signedBy string, signedAt *time.Time, userID, roleID int) error {
 	if roleID != authz.RoleManagement && roleID != authz.RoleSystemAdmin {
-		return errors.New("forbidden")
+		return ErrForbidden
 	}
-	doc, err := s.DocRepo.GetByID(id)
+	doc, err := s.DocRepo.GetByID(ctx, id)
 	if err != nil || doc == nil {
-		return errors.New("not found")
+		return ErrNotFound
 	}
 	if !(doc.Status == "approved" || doc.Status == "returned" || doc.Status == "sent_for_signature") {
-		return errors.New("invalid status")
+		return ErrInvalidStatus
+	}
+	if err := s.ensureSMSConfirmed(ctx, doc); err != nil {
+		return err
 	}
-	ts := time.Now()
+	ts := s.currentTime()
 	if signedAt != nil {
 		ts = *signedAt
 	}
-	return s.DocRepo.MarkSigned(id, strings.TrimSpace(signedBy), ts)
+	if err := s.DocRepo.MarkSigned(ctx, id, strings.TrimSpace(signedBy), ts); err != nil {
+		return err
+	}
+	s.recordSignatureAudit(ctx, doc, userID)
+	return nil
+}
+
+// recordSignatureAudit stores the signing user and the sha256 of the document
+// file for the audit view of GET /documents/:id. It is best-effort: the
+// document is already signed, so failures are only logged.
+func (s *DocumentService) recordSignatureAudit(ctx context.Context, doc *models.Document, userID int) {
+	repo, ok := s.DocRepo.(documentSignAuditRepo)
+	if !ok {
+		return
+	}
+	hash, err := s.documentFileHash(doc)
+	if err != nil {
+		log.Printf("[doc][sign][audit] document_id=%d hash_failed err=%v", doc.ID, err)
+	}
+	if err := repo.SetSignatureAudit(ctx, doc.ID, userID, hash); err != nil {
+		log.Printf("[doc][sign][audit] document_id=%d store_failed err=%v", doc.ID, err)
+	}
 }
 
-func (s *DocumentService) FinalizeSigning(docID int64) error {
-	doc, err := s.DocRepo.GetByID(docID)
+// documentFileHash returns "sha256:<hex>" of the document's PDF (or its
+// original file when no PDF exists).
+func (s *DocumentService) documentFileHash(doc *models.Document) (string, error) {
+	rel := strings.TrimSpace(doc.FilePathPdf)
+	if rel == "" {
+		rel = strings.TrimSpace(doc.FilePath)
+	}
+	if rel == "" {
+		return "", ErrBadFilePath
+	}
+	local, cleanup, err := s.downloadToTemp(rel)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+	sum, err := sha256File(local)
+	if err != nil {
+		return "", err
+	}
+	return "sha256:" + sum, nil
+}
+
+func (s *DocumentService) FinalizeSigning(ctx context.Context, docID int64) error {
+	doc, err := s.DocRepo.GetByID(ctx, docID)
 	if err != nil || doc == nil {
-		return errors.New("not found")
+		return ErrNotFound
 	}
 
 	// уже подписан — просто выходим
@@ -1057,19 +1231,19 @@ func (s *DocumentService) FinalizeSigning(docID int64) error {
 	}
 
 	if doc.Status != "approved" {
-		return errors.New("invalid status")
+		return ErrInvalidStatus
 	}
 
-	return s.DocRepo.UpdateStatus(docID, "signed")
+	return s.DocRepo.UpdateStatus(ctx, docID, "signed")
 }
 
-func (s *DocumentService) FinalizeSignedArtifact(session *models.SignSession) error {
+func (s *DocumentService) FinalizeSignedArtifact(ctx context.Context, session *models.SignSession) error {
 	if session == nil {
 		return errors.New("sign session is nil")
 	}
-	doc, err := s.DocRepo.GetByID(session.DocumentID)
+	doc, err := s.DocRepo.GetByID(ctx, session.DocumentID)
 	if err != nil || doc == nil {
-		return errors.New("not found")
+		return ErrNotFound
 	}
 	originalRel := strings.TrimSpace(doc.FilePathPdf)
 	if originalRel == "" {
@@ -1078,15 +1252,15 @@ func (s *DocumentService) FinalizeSignedArtifact(session *models.SignSession) er
 	// Download original to local temp if needed (S3 mode).
 	originalLocal, cleanupOrig, err := s.downloadToTemp(originalRel)
 	if err != nil || originalLocal == "" {
-		return errors.New("bad filepath")
+		return ErrBadFilePath
 	}
 	defer cleanupOrig()
 
 	if strings.ToLower(filepath.Ext(originalLocal)) != ".pdf" {
-		return errors.New("file not found")
+		return ErrFileNotFound
 	}
 	if _, err := os.Stat(originalLocal); err != nil {
-		return errors.New("file not found")
+		return ErrFileNotFound
 	}
 	if err := s.validateSessionDocumentHash(originalLocal, session.DocHash); err != nil {
 		return err
@@ -1139,17 +1313,17 @@ func (s *DocumentService) FinalizeSignedArtifact(session *models.SignSession) er
 	signedRel := "/pdf/" + signedName
 	meta := map[string]any{"signed_pdf_path": signedRel}
 	metaRaw, _ := json.Marshal(meta)
-	if err := s.DocRepo.UpdateSigningMeta(doc.ID, "email_otp", session.SignedIP, session.SignedUserAgent, string(metaRaw)); err != nil {
+	if err := s.DocRepo.UpdateSigningMeta(ctx, doc.ID, "email_otp", session.SignedIP, session.SignedUserAgent, string(metaRaw)); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s *DocumentService) StampSessionSignature(docID int64, signatureImageBase64 string) error {
+func (s *DocumentService) StampSessionSignature(ctx context.Context, docID int64, signatureImageBase64 string) error {
 	if !IsBase64Signature(signatureImageBase64) {
 		return nil
 	}
-	doc, err := s.DocRepo.GetByID(docID)
+	doc, err := s.DocRepo.GetByID(ctx, docID)
 	if err != nil || doc == nil {
 		return fmt.Errorf("stamp_session: doc not found: %w", err)
 	}
@@ -1245,61 +1419,127 @@ func (s *DocumentService) validateSessionDocumentHash(path string, expected stri
 
 // ================== Работа с файлами ==================
 
-func (s *DocumentService) resolveAndAuthorizeFile(docID int64, userID, roleID int) (absPath, fileName string, err error) {
-	doc, err := s.DocRepo.GetByID(docID)
+func (s *DocumentService) resolveAndAuthorizeFile(ctx context.Context, docID int64, userID, roleID int) (absPath, fileName string, err error) {
+	doc, err := s.DocRepo.GetByID(ctx, docID)
 	if err != nil || doc == nil {
-		return "", "", errors.New("not found")
+		return "", "", ErrNotFound
 	}
-	deal, derr := s.DealRepo.GetByID(int(doc.DealID))
+	deal, derr := s.DealRepo.GetByID(ctx, int(doc.DealID))
 	if derr != nil || deal == nil {
-		return "", "", errors.New("not found")
+		return "", "", ErrNotFound
 	}
 	// Sales — только свои документы
 	if err := s.ensureDealAccess(deal, userID, roleID); err != nil {
 		return "", "", err
 	}
 
-	// Теперь FilePath может быть "/pdf/xxx.pdf" или "xxx.pdf" (для upload)
-	rel := strings.TrimSpace(doc.FilePath)
+	rel, err := sanitizeDocumentRelPath(doc.FilePath, doc.DealID)
+	if err != nil {
+		return "", "", err
+	}
+
+	abs, err := s.filesRootPath(rel)
+	if err != nil {
+		return "", "", err
+	}
+	info, statErr := os.Stat(abs)
+	if statErr != nil || info.IsDir() {
+		return "", "", ErrFileNotFound
+	}
+	return abs, filepath.Base(abs), nil
+}
+
+// sanitizeDocumentRelPath приводит сохранённый FilePath к пути относительно
+// FilesRoot. Допустимы "/pdf/xxx.pdf", "xxx.pdf" (upload) и файлы сделки
+// "/deals/<dealID>/xxx.pdf" — последние только для своей сделки и без
+// дальнейшей вложенности. Любые ".." отклоняются.
+func sanitizeDocumentRelPath(raw string, dealID int64) (string, error) {
+	rel := strings.TrimSpace(raw)
+	if strings.ContainsAny(rel, "\x00\r\n") {
+		return "", ErrBadFilePath
+	}
 	rel = strings.ReplaceAll(rel, "\\", "/")
-	rel = strings.TrimPrefix(rel, "/")
+	rel = strings.TrimLeft(rel, "/")
 
 	// если кто-то вдруг сохранил "files/..." — убираем префикс
-	if strings.HasPrefix(rel, "files/") {
-		rel = strings.TrimPrefix(rel, "files/")
+	rel = strings.TrimPrefix(rel, "files/")
+
+	// защита от ".." — в том числе закодированных ("%2e%2e%2f", "%252e...")
+	if hasEncodedTraversal(rel) {
+		return "", ErrBadFilePath
+	}
+	// "C:/..." и прочие диски Windows
+	if len(rel) >= 2 && rel[1] == ':' {
+		return "", ErrBadFilePath
+	}
+	if rel == "" || rel == "." {
+		return "", ErrBadFilePath
 	}
 
-	// защита от ".."
-	if strings.Contains(rel, "..") {
-		return "", "", errors.New("bad filepath")
+	parts := strings.Split(rel, "/")
+	for _, p := range parts {
+		if p == "" || p == "." {
+			return "", ErrBadFilePath
+		}
 	}
+	if parts[0] == pdf.DealFilesDir {
+		if len(parts) != 3 {
+			return "", ErrBadFilePath
+		}
+		id, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil || id <= 0 || id != dealID || parts[1] != strconv.FormatInt(id, 10) {
+			return "", ErrBadFilePath
+		}
+	}
+	return rel, nil
+}
 
-	if rel == "" || rel == "." {
-		return "", "", errors.New("bad filepath")
+// hasEncodedTraversal сообщает, содержит ли rel ".." или обратный слэш —
+// как есть или после (многократного) percent-декодирования.
+func hasEncodedTraversal(rel string) bool {
+	for i := 0; i < 3; i++ {
+		if strings.Contains(rel, "..") || strings.Contains(rel, "\\") {
+			return true
+		}
+		decoded, err := url.PathUnescape(rel)
+		if err != nil {
+			return strings.Contains(rel, "%")
+		}
+		if decoded == rel {
+			return false
+		}
+		rel = decoded
 	}
+	return strings.Contains(rel, "%")
+}
 
-	abs := filepath.Join(s.FilesRoot, rel)
-	info, statErr := os.Stat(abs)
-	if statErr != nil || info.IsDir() {
-		return "", "", errors.New("file not found")
+// filesRootPath переводит очищенный относительный путь в абсолютный и
+// проверяет, что результат не выходит за пределы FilesRoot.
+func (s *DocumentService) filesRootPath(rel string) (string, error) {
+	root := filepath.Clean(s.FilesRoot)
+	abs := filepath.Join(root, filepath.FromSlash(rel))
+	inside, err := filepath.Rel(root, abs)
+	if err != nil || inside == "." || inside == ".." || filepath.IsAbs(inside) ||
+		strings.HasPrefix(inside, ".."+string(filepath.Separator)) {
+		return "", ErrBadFilePath
 	}
-	return abs, filepath.Base(abs), nil
+	return abs, nil
 }
 
-func (s *DocumentService) EnsureSigningAllowed(docID int64, userID, roleID int) error {
-	doc, err := s.DocRepo.GetByID(docID)
+func (s *DocumentService) EnsureSigningAllowed(ctx context.Context, docID int64, userID, roleID int) error {
+	doc, err := s.DocRepo.GetByID(ctx, docID)
 	if err != nil || doc == nil {
-		return errors.New("not found")
+		return ErrNotFound
 	}
-	deal, derr := s.DealRepo.GetByID(int(doc.DealID))
+	deal, derr := s.DealRepo.GetByID(ctx, int(doc.DealID))
 	if derr != nil || deal == nil {
-		return errors.New("not found")
+		return ErrNotFound
 	}
 	if err := s.ensureDealAccess(deal, userID, roleID); err != nil {
 		return err
 	}
 	if doc.Status != "approved" {
-		return errors.New("invalid status")
+		return ErrInvalidStatus
 	}
 	return nil
 }
@@ -1310,24 +1550,48 @@ func (s *DocumentService) EnsureSigningAllowed(docID int64, userID, roleID int)
 //   - "docx"     : doc.FilePathDocx
 //   - "xlsx"     : doc.FilePath (только если .xlsx)
 //   - "original" : предпочесть docx если есть, иначе doc.FilePath
-func (s *DocumentService) ResolveFileForHTTP(docID int64, userID, roleID int, variant string) (string, string, error) {
-	doc, err := s.DocRepo.GetByID(docID)
+func (s *DocumentService) ResolveFileForHTTP(ctx context.Context, docID int64, userID, roleID int, variant string) (string, string, error) {
+	doc, err := s.DocRepo.GetByID(ctx, docID)
 	if err != nil || doc == nil {
-		return "", "", errors.New("not found")
+		return "", "", ErrNotFound
 	}
 	if !isHiddenDocVisible(doc, userID, roleID) {
-		return "", "", errors.New("forbidden")
+		return "", "", ErrForbidden
 	}
 	if doc.DealID != 0 {
-		deal, derr := s.DealRepo.GetByID(int(doc.DealID))
+		deal, derr := s.DealRepo.GetByID(ctx, int(doc.DealID))
 		if derr != nil || deal == nil {
-			return "", "", errors.New("not found")
+			return "", "", ErrNotFound
 		}
 		if err := s.ensureDealAccess(deal, userID, roleID); err != nil {
 			return "", "", err
 		}
 	}
 
+	rel, err := documentFileKey(doc, variant)
+	if err != nil {
+		return "", "", err
+	}
+
+	// With S3 storage, we return the key directly (no local stat check).
+	if s.Store != nil {
+		return rel, filepath.Base(rel), nil
+	}
+
+	abs, err := s.filesRootPath(rel)
+	if err != nil {
+		return "", "", err
+	}
+	info, statErr := os.Stat(abs)
+	if statErr != nil || info.IsDir() {
+		return "", "", ErrFileNotFound
+	}
+	return abs, filepath.Base(abs), nil
+}
+
+// documentFileKey picks the storage key of a document file variant
+// ("original", "pdf", "docx", ...) and normalizes it relative to FilesRoot.
+func documentFileKey(doc *models.Document, variant string) (string, error) {
 	variant = strings.ToLower(strings.TrimSpace(variant))
 	var rel string
 	switch variant {
@@ -1349,12 +1613,12 @@ func (s *DocumentService) ResolveFileForHTTP(docID int64, userID, roleID int, va
 	case "docx":
 		rel = doc.FilePathDocx
 		if strings.TrimSpace(rel) == "" {
-			return "", "", errors.New("file not found")
+			return "", ErrFileNotFound
 		}
 	case "xlsx":
 		rel = doc.FilePath
 		if strings.ToLower(filepath.Ext(strings.TrimSpace(rel))) != ".xlsx" {
-			return "", "", errors.New("file not found")
+			return "", ErrFileNotFound
 		}
 	case "original", "source":
 		if strings.TrimSpace(doc.FilePathDocx) != "" {
@@ -1367,27 +1631,31 @@ func (s *DocumentService) ResolveFileForHTTP(docID int64, userID, roleID int, va
 	}
 
 	// normalize + validate
-	rel = strings.TrimSpace(rel)
-	rel = strings.ReplaceAll(rel, "\\", "/")
-	rel = strings.TrimPrefix(rel, "/")
-	if strings.HasPrefix(rel, "files/") {
-		rel = strings.TrimPrefix(rel, "files/")
+	return sanitizeDocumentRelPath(rel, doc.DealID)
+}
+
+// DocumentFileExists reports whether the file served by /documents/:id/file
+// (the "original" variant) is actually present — on local disk under
+// FilesRoot or, failing that, in the configured Store.
+func (s *DocumentService) DocumentFileExists(ctx context.Context, doc *models.Document) bool {
+	rel, err := documentFileKey(doc, "original")
+	if err != nil {
+		return false
 	}
-	if strings.Contains(rel, "..") || rel == "" || rel == "." {
-		return "", "", errors.New("bad filepath")
+	if abs, err := s.filesRootPath(rel); err == nil {
+		if info, err := os.Stat(abs); err == nil && !info.IsDir() {
+			return true
+		}
 	}
-
-	// With S3 storage, we return the key directly (no local stat check).
-	if s.Store != nil {
-		return rel, filepath.Base(rel), nil
+	if s.Store == nil {
+		return false
 	}
-
-	abs := filepath.Join(s.FilesRoot, rel)
-	info, statErr := os.Stat(abs)
-	if statErr != nil || info.IsDir() {
-		return "", "", errors.New("file not found")
+	r, _, err := s.Store.Open(ctx, rel)
+	if err != nil {
+		return false
 	}
-	return abs, filepath.Base(abs), nil
+	_ = r.Close()
+	return true
 }
 
 // storeSave saves reader content under key; falls back to local disk when Store is nil.
@@ -1491,7 +1759,7 @@ func (s *DocumentService) resolveStoragePath(rel string) (string, error) {
 		rel = strings.TrimPrefix(rel, "files/")
 	}
 	if strings.Contains(rel, "..") || rel == "." || rel == "" {
-		return "", errors.New("bad filepath")
+		return "", ErrBadFilePath
 	}
 	return filepath.Join(s.FilesRoot, rel), nil
 }
@@ -1520,26 +1788,24 @@ func (s *DocumentService) deleteDocumentFiles(doc *models.Document) error {
 
 // ================== Документы из лида (старый контракт/invoice) ==================
 
-func (s *DocumentService) CreateDocumentFromLead(leadID int, docType string, userID, roleID int) (*models.Document, error) {
-	docType = normalizeDocType(docType)
-	lead, err := s.LeadRepo.GetByID(leadID)
-	if err != nil || lead == nil {
-		return nil, errors.New("lead not found")
-	}
-	deal, err := s.DealRepo.GetByLeadID(leadID)
-	if err != nil || deal == nil {
-		return nil, errors.New("deal not found")
-	}
-	if err := s.ensureDealAccess(deal, userID, roleID); err != nil {
-		return nil, err
-	}
-
-	if s.PDFGen == nil {
-		return nil, errors.New("pdf generator not configured")
+// generateLeadPDF renders a contract or invoice for the deal with the
+// built-in PDF generator and returns the normalized storage path. An empty
+// filename keeps the generator's default name.
+func (s *DocumentService) generateLeadPDF(docType string, lead *models.Leads, deal *models.Deals, filename string) (string, error) {
+	relPath, err := s.renderLeadPDF(docType, lead, deal, filename)
+	if err != nil {
+		return "", err
 	}
+	s.uploadGeneratedFile(relPath)
+	return relPath, nil
+}
 
+// renderLeadPDF writes the lead contract/invoice under FilesRoot and returns
+// its normalized relative path; the file stays local.
+func (s *DocumentService) renderLeadPDF(docType string, lead *models.Leads, deal *models.Deals, filename string) (string, error) {
 	amountStr := strconv.FormatFloat(deal.Amount, 'f', 2, 64)
 	var relPath string
+	var err error
 	switch docType {
 	case "contract":
 		relPath, err = s.PDFGen.GenerateContract(pdf.ContractData{
@@ -1548,6 +1814,7 @@ func (s *DocumentService) CreateDocumentFromLead(leadID int, docType string, use
 			Amount:    amountStr,
 			Currency:  deal.Currency,
 			CreatedAt: deal.CreatedAt,
+			Filename:  filename,
 		})
 	case "invoice":
 		relPath, err = s.PDFGen.GenerateInvoice(pdf.InvoiceData{
@@ -1556,16 +1823,77 @@ func (s *DocumentService) CreateDocumentFromLead(leadID int, docType string, use
 			Amount:    amountStr,
 			Currency:  deal.Currency,
 			CreatedAt: deal.CreatedAt,
+			Filename:  filename,
 		})
 	default:
-		return nil, errors.New("unsupported_doc_type_for_lead_use_create_from_client")
+		return "", ErrUnsupportedDocTypeForLead
 	}
 	if err != nil {
+		return "", err
+	}
+
+	return normalizeStoragePath(relPath), nil
+}
+
+// PreviewDocumentFromLead renders the same PDF as CreateDocumentFromLead,
+// with the same access checks, and returns its bytes. Nothing is stored:
+// the temporary file is removed and no document row is created.
+func (s *DocumentService) PreviewDocumentFromLead(ctx context.Context, leadID int, docType string, userID, roleID int) ([]byte, error) {
+	docType = normalizeDocType(docType)
+	lead, err := s.LeadRepo.GetByID(ctx, leadID)
+	if err != nil || lead == nil {
+		return nil, ErrLeadNotFound
+	}
+	deal, err := s.DealRepo.GetByLeadID(ctx, leadID)
+	if err != nil || deal == nil {
+		return nil, ErrDealNotFound
+	}
+	if err := s.ensureDealAccess(deal, userID, roleID); err != nil {
 		return nil, err
 	}
+	if s.PDFGen == nil {
+		return nil, errors.New("pdf generator not configured")
+	}
 
-	relPath = normalizeStoragePath(relPath)
-	s.uploadGeneratedFile(relPath)
+	filename := fmt.Sprintf("preview_%s_deal_%d_%d.pdf", docType, deal.ID, s.currentTime().UnixNano())
+	relPath, err := s.renderLeadPDF(docType, lead, deal, filename)
+	if err != nil {
+		return nil, err
+	}
+	absPath, err := s.resolveStoragePath(relPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if rmErr := os.Remove(absPath); rmErr != nil && !os.IsNotExist(rmErr) {
+			log.Printf("[documents] preview: remove %s: %v", relPath, rmErr)
+		}
+	}()
+	return os.ReadFile(absPath)
+}
+
+func (s *DocumentService) CreateDocumentFromLead(ctx context.Context, leadID int, docType string, userID, roleID int) (*models.Document, error) {
+	docType = normalizeDocType(docType)
+	lead, err := s.LeadRepo.GetByID(ctx, leadID)
+	if err != nil || lead == nil {
+		return nil, ErrLeadNotFound
+	}
+	deal, err := s.DealRepo.GetByLeadID(ctx, leadID)
+	if err != nil || deal == nil {
+		return nil, ErrDealNotFound
+	}
+	if err := s.ensureDealAccess(deal, userID, roleID); err != nil {
+		return nil, err
+	}
+
+	if s.PDFGen == nil {
+		return nil, errors.New("pdf generator not configured")
+	}
+
+	relPath, err := s.generateLeadPDF(docType, lead, deal, "")
+	if err != nil {
+		return nil, err
+	}
 
 	createdByLead := userID
 	doc := &models.Document{
@@ -1578,7 +1906,7 @@ func (s *DocumentService) CreateDocumentFromLead(leadID int, docType string, use
 		CreatedBy:   &createdByLead,
 		IsHidden:    roleID == authz.RolePartner,
 	}
-	id, ierr := s.DocRepo.Create(doc)
+	id, ierr := s.DocRepo.Create(ctx, doc)
 	if ierr != nil {
 		return nil, ierr
 	}
@@ -1586,9 +1914,98 @@ func (s *DocumentService) CreateDocumentFromLead(leadID int, docType string, use
 	return doc, nil
 }
 
+// RegenerateDocument re-renders a contract or invoice created from a lead
+// with the deal's current amount and lead title. The previous file is kept
+// as a document version and the new one becomes the next version. Signed
+// documents are never regenerated.
+func (s *DocumentService) RegenerateDocument(ctx context.Context, docID int64, userID, roleID int) (*models.Document, error) {
+	doc, err := s.DocRepo.GetByID(ctx, docID)
+	if err != nil || doc == nil {
+		return nil, ErrNotFound
+	}
+	if !isHiddenDocVisible(doc, userID, roleID) {
+		return nil, ErrNotFound
+	}
+	deal, err := s.loadDocumentDealForAccess(ctx, doc, userID, roleID)
+	if err != nil {
+		return nil, err
+	}
+	if doc.Status == "signed" || doc.SignedAt != nil {
+		return nil, ErrDocumentAlreadySigned
+	}
+	docType := normalizeDocType(doc.DocType)
+	if docType != "contract" && docType != "invoice" {
+		return nil, ErrDocumentNotRegenerable
+	}
+	lead, err := s.LeadRepo.GetByID(ctx, deal.LeadID)
+	if err != nil || lead == nil {
+		return nil, ErrLeadNotFound
+	}
+	if s.PDFGen == nil {
+		return nil, errors.New("pdf generator not configured")
+	}
+
+	version := 0
+	if s.VersionRepo != nil {
+		latest, err := s.VersionRepo.GetLatestVersion(ctx, doc.ID)
+		if err != nil {
+			return nil, err
+		}
+		// документ ещё без истории — текущий файл становится версией 1
+		if latest == 0 && strings.TrimSpace(doc.FilePath) != "" {
+			latest = 1
+			if _, err := s.VersionRepo.CreateVersion(ctx, generatedDocumentVersion(doc, latest, userID, "")); err != nil {
+				return nil, err
+			}
+		}
+		version = latest + 1
+	}
+
+	suffix := strconv.Itoa(version)
+	if version == 0 {
+		suffix = strconv.FormatInt(s.now().Unix(), 10)
+	}
+	filename := fmt.Sprintf("%s_deal_%d_v%s.pdf", docType, deal.ID, suffix)
+	relPath, err := s.generateLeadPDF(docType, lead, deal, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	doc.FilePath = relPath
+	doc.FilePathPdf = relPath
+	doc.FilePathDocx = ""
+	if err := s.DocRepo.Update(ctx, doc); err != nil {
+		return nil, err
+	}
+	if s.VersionRepo != nil {
+		if _, err := s.VersionRepo.CreateVersion(ctx, generatedDocumentVersion(doc, version, userID, "regenerated")); err != nil {
+			log.Printf("[documents] regenerate: version record doc=%d v=%d: %v", doc.ID, version, err)
+		}
+	}
+	return doc, nil
+}
+
+func generatedDocumentVersion(doc *models.Document, version, userID int, comment string) *models.DocumentVersion {
+	mimeType := "application/pdf"
+	v := &models.DocumentVersion{
+		DocumentID:   doc.ID,
+		Version:      version,
+		FilePath:     doc.FilePath,
+		FilePathPdf:  doc.FilePathPdf,
+		FilePathDocx: doc.FilePathDocx,
+		MimeType:     &mimeType,
+		UploadedBy:   &userID,
+	}
+	if comment != "" {
+		v.Comment = &comment
+	}
+	return v
+}
+
 // ================== Документы из клиента (новый поток) ==================
 
 func (s *DocumentService) CreateDocumentFromClient(
+	ctx context.Context,
 	clientID int,
 	clientType string,
 	dealID int,
@@ -1605,7 +2022,7 @@ func (s *DocumentService) CreateDocumentFromClient(
 
 	spec, hasSpec := GetDocumentTypeSpec(docType)
 	if !hasSpec {
-		return nil, errors.New("unsupported doc_type")
+		return nil, ErrUnsupportedDocType
 	}
 
 	// --- Клиент ---
@@ -1613,9 +2030,9 @@ func (s *DocumentService) CreateDocumentFromClient(
 	if err != nil {
 		return nil, err
 	}
-	client, err := s.ClientRepo.GetByID(clientID)
+	client, err := s.ClientRepo.GetByID(ctx, clientID)
 	if err != nil || client == nil {
-		return nil, errors.New("client not found")
+		return nil, ErrClientNotFound
 	}
 	storedClientType, err := normalizeRequiredDealClientType(client.ClientType)
 	if err != nil {
@@ -1631,23 +2048,23 @@ func (s *DocumentService) CreateDocumentFromClient(
 	var deal *models.Deals
 	if needDeal {
 		if dealID > 0 {
-			deal, err = s.DealRepo.GetByID(dealID)
+			deal, err = s.DealRepo.GetByID(ctx, dealID)
 			if err != nil || deal == nil {
-				return nil, errors.New("deal not found")
+				return nil, ErrDealNotFound
 			}
 			if deal.ClientID != clientID {
-				return nil, errors.New("deal does not belong to client")
+				return nil, ErrDealClientMismatch
 			}
 			if strings.TrimSpace(deal.ClientType) != "" && strings.ToLower(strings.TrimSpace(deal.ClientType)) != normalizedClientType {
 				return nil, ErrClientTypeMismatch
 			}
 		} else {
-			deal, err = s.DealRepo.GetLatestByClientRef(clientID, normalizedClientType)
+			deal, err = s.DealRepo.GetLatestByClientRef(ctx, clientID, normalizedClientType)
 			if err != nil {
 				return nil, err
 			}
 			if deal == nil {
-				return nil, errors.New("deal not found")
+				return nil, ErrDealNotFound
 			}
 		}
 
@@ -1711,7 +2128,7 @@ func (s *DocumentService) CreateDocumentFromClient(
 			IsHidden:     roleID == authz.RolePartner,
 		}
 
-		id, err := s.DocRepo.Create(doc)
+		id, err := s.DocRepo.Create(ctx, doc)
 		if err != nil {
 			return nil, err
 		}
@@ -1720,7 +2137,7 @@ func (s *DocumentService) CreateDocumentFromClient(
 	}
 
 	if spec.Format == DocumentFormatDOCX && s.DocxGen == nil {
-		return nil, errors.New("pdf_conversion_disabled")
+		return nil, ErrPDFConversionDisabled
 	}
 	// ================== DOCX ==================
 	if spec.Format == DocumentFormatDOCX {
@@ -1755,7 +2172,7 @@ func (s *DocumentService) CreateDocumentFromClient(
 			IsHidden:     roleID == authz.RolePartner,
 		}
 
-		id, err := s.DocRepo.Create(doc)
+		id, err := s.DocRepo.Create(ctx, doc)
 		if err != nil {
 			return nil, err
 		}
@@ -1763,7 +2180,7 @@ func (s *DocumentService) CreateDocumentFromClient(
 		return doc, nil
 	}
 
-	return nil, errors.New("unsupported doc_type")
+	return nil, ErrUnsupportedDocType
 }
 
 func validateClientFieldsForDocType(docType string, c *models.Client) (missing []string) {
@@ -2265,16 +2682,16 @@ func wrapGenerationError(docType string, err error) error {
 	}
 	msg := strings.ToLower(strings.TrimSpace(err.Error()))
 	if strings.Contains(msg, "template not found") {
-		return errors.New("template_not_found")
+		return ErrTemplateNotFound
 	}
 	if strings.Contains(msg, "pdf_conversion_disabled") {
-		return errors.New("pdf_conversion_disabled")
+		return ErrPDFConversionDisabled
 	}
 	if strings.Contains(msg, "conversion is disabled") {
-		return errors.New("pdf_conversion_disabled")
+		return ErrPDFConversionDisabled
 	}
 	if strings.Contains(msg, "pdf_conversion_failed") || strings.Contains(msg, "libreoffice conversion") {
-		return errors.New("pdf_conversion_failed")
+		return ErrPDFConversionFailed
 	}
 	return errors.New("document generation failed")
 }
@@ -2611,10 +3028,10 @@ func mergePDFs(originalAbs, signPageAbs, signedAbs string) error {
 		return fmt.Errorf("create signed pdf dir: %w", err)
 	}
 	if _, err := os.Stat(originalAbs); err != nil {
-		return errors.New("file not found")
+		return ErrFileNotFound
 	}
 	if _, err := os.Stat(signPageAbs); err != nil {
-		return errors.New("file not found")
+		return ErrFileNotFound
 	}
 	if path, err := exec.LookPath("pdfcpu"); err == nil {
 		cmd := exec.Command(path, "merge", "-mode", "create", signedAbs, originalAbs, signPageAbs)
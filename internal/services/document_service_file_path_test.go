@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+)
+
+func newFilePathTestService(t *testing.T, filePath string, dealID int64) (*DocumentService, string) {
+	t.Helper()
+	root := t.TempDir()
+	branch := 1
+	return &DocumentService{
+		FilesRoot: root,
+		DocRepo:   &docRepoStub{doc: &models.Document{ID: 1, DealID: dealID, FilePath: filePath}},
+		DealRepo:  &dealRepoStub{deal: &models.Deals{ID: int(dealID), BranchID: &branch}},
+	}, root
+}
+
+func TestResolveAndAuthorizeFile_NestedDealPath(t *testing.T) {
+	svc, root := newFilePathTestService(t, "/deals/7/contract_deal_7.pdf", 7)
+	dir := filepath.Join(root, "deals", "7")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "contract_deal_7.pdf"), []byte("%PDF"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	abs, name, err := svc.resolveAndAuthorizeFile(context.Background(), 1, 1, authz.RoleSystemAdmin)
+	if err != nil {
+		t.Fatalf("resolve nested deal path: %v", err)
+	}
+	if abs != filepath.Join(dir, "contract_deal_7.pdf") || name != "contract_deal_7.pdf" {
+		t.Fatalf("unexpected resolution: abs=%q name=%q", abs, name)
+	}
+}
+
+func TestResolveAndAuthorizeFile_LegacyFlatPath(t *testing.T) {
+	svc, root := newFilePathTestService(t, "/pdf/contract_deal_3.pdf", 3)
+	if err := os.MkdirAll(filepath.Join(root, "pdf"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "pdf", "contract_deal_3.pdf"), []byte("%PDF"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := svc.resolveAndAuthorizeFile(context.Background(), 1, 1, authz.RoleSystemAdmin); err != nil {
+		t.Fatalf("legacy /pdf/ path must still resolve: %v", err)
+	}
+}
+
+func TestResolveAndAuthorizeFile_RejectsTraversal(t *testing.T) {
+	cases := []string{
+		"../secret.pdf",
+		"/deals/7/../../secret.pdf",
+		"deals/7/../8/contract.pdf",
+		"files/../secret.pdf",
+		"..\\secret.pdf",
+		"deals\\7\\..\\..\\secret.pdf",
+	}
+	for _, fp := range cases {
+		svc, _ := newFilePathTestService(t, fp, 7)
+		if _, _, err := svc.resolveAndAuthorizeFile(context.Background(), 1, 1, authz.RoleSystemAdmin); !errors.Is(err, ErrBadFilePath) {
+			t.Errorf("%q: expected ErrBadFilePath, got %v", fp, err)
+		}
+	}
+}
+
+func TestSanitizeDocumentRelPath_DealPrefix(t *testing.T) {
+	cases := []struct {
+		in     string
+		dealID int64
+		want   string
+		ok     bool
+	}{
+		{"/deals/7/contract.pdf", 7, "deals/7/contract.pdf", true},
+		{"files/deals/7/invoice.pdf", 7, "deals/7/invoice.pdf", true},
+		{"/deals/8/contract.pdf", 7, "", false},     // чужая сделка
+		{"/deals/07/contract.pdf", 7, "", false},    // неканоничный ID
+		{"/deals/abc/contract.pdf", 7, "", false},   // не число
+		{"/deals/7/sub/contract.pdf", 7, "", false}, // лишняя вложенность
+		{"/deals/7/", 7, "", false},
+		{"/deals/7", 7, "", false},
+		{"", 7, "", false},
+	}
+	for _, tc := range cases {
+		got, err := sanitizeDocumentRelPath(tc.in, tc.dealID)
+		if tc.ok {
+			if err != nil || got != tc.want {
+				t.Errorf("%q: got (%q, %v), want %q", tc.in, got, err, tc.want)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrBadFilePath) {
+			t.Errorf("%q: expected ErrBadFilePath, got (%q, %v)", tc.in, got, err)
+		}
+	}
+}
+
+// maliciousFilePaths — значения file_path, которые не должны выводить за
+// пределы FilesRoot ни в одном из обработчиков файлов.
+var maliciousFilePaths = []string{
+	"..",
+	"../",
+	"../secret.txt",
+	"../../etc/passwd",
+	"..%2f..%2fetc%2fpasswd",
+	"..%2F..%2Fsecret.txt",
+	"%2e%2e/secret.txt",
+	"%2e%2e%2fsecret.txt",
+	"%252e%252e%252fsecret.txt",
+	"pdf/%2e%2e/%2e%2e/secret.txt",
+	"..\\secret.txt",
+	"..\\..\\etc\\passwd",
+	"pdf\\..\\..\\secret.txt",
+	"..%5csecret.txt",
+	"%2e%2e%5csecret.txt",
+	".\\.\\..\\secret.txt",
+	"files/../secret.txt",
+	"files/..%2fsecret.txt",
+	"/../secret.txt",
+	"//../secret.txt",
+	"C:\\Windows\\win.ini",
+	"C:/Windows/win.ini",
+	"c:secret.txt",
+	"pdf/./../secret.txt",
+	"pdf/a.pdf\x00.txt",
+	"%00",
+	"%zz",
+	".",
+	"/",
+	"",
+	"   ",
+}
+
+func writeSecretOutsideRoot(t *testing.T, root string) {
+	t.Helper()
+	// root = <tmp>/<n>; секрет лежит уровнем выше
+	if err := os.WriteFile(filepath.Join(filepath.Dir(root), "secret.txt"), []byte("top secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertInsideRoot(t *testing.T, root, abs string) {
+	t.Helper()
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) || filepath.IsAbs(rel) {
+		t.Fatalf("path %q escapes FilesRoot %q", abs, root)
+	}
+}
+
+func TestResolveAndAuthorizeFile_MaliciousPathsStayInsideRoot(t *testing.T) {
+	for _, fp := range maliciousFilePaths {
+		svc, root := newFilePathTestService(t, fp, 7)
+		writeSecretOutsideRoot(t, root)
+
+		abs, _, err := svc.resolveAndAuthorizeFile(context.Background(), 1, 1, authz.RoleSystemAdmin)
+		if err == nil {
+			assertInsideRoot(t, root, abs)
+			t.Errorf("%q: expected an error, resolved to %q", fp, abs)
+			continue
+		}
+		if !errors.Is(err, ErrBadFilePath) && !errors.Is(err, ErrFileNotFound) {
+			t.Errorf("%q: unexpected error %v", fp, err)
+		}
+	}
+}
+
+func TestResolveFileForHTTP_MaliciousPathsStayInsideRoot(t *testing.T) {
+	for _, fp := range maliciousFilePaths {
+		svc, root := newFilePathTestService(t, fp, 7)
+		writeSecretOutsideRoot(t, root)
+
+		for _, variant := range []string{"main", "pdf", "original"} {
+			abs, _, err := svc.ResolveFileForHTTP(context.Background(), 1, 1, authz.RoleSystemAdmin, variant)
+			if err == nil {
+				assertInsideRoot(t, root, abs)
+				t.Errorf("%q (%s): expected an error, resolved to %q", fp, variant, abs)
+			}
+		}
+	}
+}
+
+func TestResolveFileForHTTP_LegitimatePathsResolve(t *testing.T) {
+	cases := []struct{ filePath, onDisk string }{
+		{"/pdf/contract_deal_7.pdf", "pdf/contract_deal_7.pdf"},
+		{"pdf/contract_deal_7.pdf", "pdf/contract_deal_7.pdf"},
+		{"files/pdf/contract_deal_7.pdf", "pdf/contract_deal_7.pdf"},
+		{"\\pdf\\contract_deal_7.pdf", "pdf/contract_deal_7.pdf"},
+		{"upload_1.pdf", "upload_1.pdf"},
+		{"/deals/7/invoice_deal_7.pdf", "deals/7/invoice_deal_7.pdf"},
+		{"/docx/Договор клиента.docx", "docx/Договор клиента.docx"},
+		{"/pdf/report%20final.pdf", "pdf/report%20final.pdf"},
+	}
+	for _, tc := range cases {
+		svc, root := newFilePathTestService(t, tc.filePath, 7)
+		want := filepath.Join(root, filepath.FromSlash(tc.onDisk))
+		if err := os.MkdirAll(filepath.Dir(want), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(want, []byte("data"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		abs, name, err := svc.ResolveFileForHTTP(context.Background(), 1, 1, authz.RoleSystemAdmin, "main")
+		if err != nil {
+			t.Errorf("%q: %v", tc.filePath, err)
+			continue
+		}
+		if abs != want || name != filepath.Base(want) {
+			t.Errorf("%q: got (%q, %q), want %q", tc.filePath, abs, name, want)
+		}
+	}
+}
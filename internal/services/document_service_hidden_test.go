@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"testing"
 
 	"turcompany/internal/authz"
@@ -71,7 +72,7 @@ func TestGetDocument_AdminSeesHiddenDoc(t *testing.T) {
 	svc := &DocumentService{
 		DocRepo: &docRepoStub{doc: &models.Document{ID: 1, DealID: 0, IsHidden: true, CreatedBy: intPtr(99)}},
 	}
-	doc, err := svc.GetDocument(1, 1, authz.RoleSystemAdmin)
+	doc, err := svc.GetDocument(context.Background(), 1, 1, authz.RoleSystemAdmin)
 	if err != nil || doc == nil {
 		t.Fatalf("admin must see hidden doc, got err=%v", err)
 	}
@@ -86,7 +87,7 @@ func TestGetDocument_PartnerAuthorSeesOwnHiddenDoc(t *testing.T) {
 		DealRepo: &dealRepoStub{deal: &models.Deals{ID: 1, BranchID: &branch}},
 		UserRepo: &docScopeUserRepoStub{user: &models.User{BranchID: &branch}},
 	}
-	doc, err := svc.GetDocument(1, partnerUserID, authz.RolePartner)
+	doc, err := svc.GetDocument(context.Background(), 1, partnerUserID, authz.RolePartner)
 	if err != nil || doc == nil {
 		t.Fatalf("partner-author must see own hidden doc, got err=%v", err)
 	}
@@ -97,7 +98,7 @@ func TestGetDocument_ManagementCannotSeeHiddenDoc(t *testing.T) {
 	svc := &DocumentService{
 		DocRepo: &docRepoStub{doc: &models.Document{ID: 1, DealID: 0, IsHidden: true, CreatedBy: intPtr(99)}},
 	}
-	doc, err := svc.GetDocument(1, 1, authz.RoleManagement)
+	doc, err := svc.GetDocument(context.Background(), 1, 1, authz.RoleManagement)
 	if err == nil || doc != nil {
 		t.Fatal("management must NOT see hidden document (expected forbidden error)")
 	}
@@ -108,7 +109,7 @@ func TestGetDocument_LegalCannotSeeHiddenDoc(t *testing.T) {
 	svc := &DocumentService{
 		DocRepo: &docRepoStub{doc: &models.Document{ID: 1, DealID: 0, IsHidden: true, CreatedBy: intPtr(99)}},
 	}
-	doc, err := svc.GetDocument(1, 1, authz.RoleLegal)
+	doc, err := svc.GetDocument(context.Background(), 1, 1, authz.RoleLegal)
 	if err == nil || doc != nil {
 		t.Fatal("legal must NOT see hidden document (expected forbidden error)")
 	}
@@ -119,7 +120,7 @@ func TestGetDocument_HRCannotSeeHiddenDoc(t *testing.T) {
 	svc := &DocumentService{
 		DocRepo: &docRepoStub{doc: &models.Document{ID: 1, DealID: 0, IsHidden: true, CreatedBy: intPtr(99)}},
 	}
-	doc, err := svc.GetDocument(1, 1, authz.RoleHR)
+	doc, err := svc.GetDocument(context.Background(), 1, 1, authz.RoleHR)
 	if err == nil || doc != nil {
 		t.Fatal("HR must NOT see hidden document (expected forbidden error)")
 	}
@@ -131,7 +132,7 @@ func TestGetDocument_NonHiddenDocVisibleToManagement(t *testing.T) {
 	svc := &DocumentService{
 		DocRepo: &docRepoStub{doc: &models.Document{ID: 1, DealID: 0, IsHidden: false}},
 	}
-	doc, err := svc.GetDocument(1, 1, authz.RoleManagement)
+	doc, err := svc.GetDocument(context.Background(), 1, 1, authz.RoleManagement)
 	if err != nil || doc == nil {
 		t.Fatalf("management must see non-hidden documents (regression): err=%v", err)
 	}
@@ -142,7 +143,7 @@ func TestGetDocument_NonHiddenDocVisibleToLegal(t *testing.T) {
 	svc := &DocumentService{
 		DocRepo: &docRepoStub{doc: &models.Document{ID: 1, DealID: 0, IsHidden: false}},
 	}
-	doc, err := svc.GetDocument(1, 1, authz.RoleLegal)
+	doc, err := svc.GetDocument(context.Background(), 1, 1, authz.RoleLegal)
 	if err != nil || doc == nil {
 		t.Fatalf("legal must see non-hidden documents (regression): err=%v", err)
 	}
@@ -155,7 +156,7 @@ type capturingDocRepo struct {
 	captured *models.Document
 }
 
-func (r *capturingDocRepo) Create(doc *models.Document) (int64, error) {
+func (r *capturingDocRepo) Create(_ context.Context, doc *models.Document) (int64, error) {
 	captured := *doc
 	r.captured = &captured
 	return 1, nil
@@ -174,7 +175,7 @@ func TestCreateDocument_PartnerForbidden(t *testing.T) {
 		UserRepo: &docScopeUserRepoStub{user: &models.User{BranchID: &branch}},
 	}
 	doc := &models.Document{DealID: 1, DocType: "invoice", Status: "draft", FilePath: "test.pdf"}
-	_, err := svc.CreateDocument(doc, partnerUserID, authz.RolePartner)
+	_, err := svc.CreateDocument(context.Background(), doc, partnerUserID, authz.RolePartner)
 	if err == nil {
 		t.Fatal("partner must NOT be able to create documents (documents — view only)")
 	}
@@ -191,7 +192,7 @@ func TestCreateDocument_ManagementNotHidden(t *testing.T) {
 		DealRepo: &dealRepoStub{deal: &models.Deals{ID: 1}},
 	}
 	doc := &models.Document{DealID: 1, DocType: "invoice", Status: "draft", FilePath: "test.pdf"}
-	_, err := svc.CreateDocument(doc, 1, authz.RoleManagement)
+	_, err := svc.CreateDocument(context.Background(), doc, 1, authz.RoleManagement)
 	if err != nil {
 		t.Fatalf("CreateDocument failed: %v", err)
 	}
@@ -208,21 +209,21 @@ type filterCapturingDocRepo struct {
 	capturedFilter repositories.DocumentListFilter
 }
 
-func (r *filterCapturingDocRepo) ListDocumentsWithFilterAndArchiveScope(_ int, _ int, filter repositories.DocumentListFilter, _ repositories.ArchiveScope) ([]*models.Document, error) {
+func (r *filterCapturingDocRepo) ListDocumentsWithFilterAndArchiveScope(_ context.Context, _ int, _ int, filter repositories.DocumentListFilter, _ repositories.ArchiveScope) ([]*models.Document, error) {
 	r.capturedFilter = filter
 	return nil, nil
 }
 
-func (r *filterCapturingDocRepo) ListDocumentsByDealWithFilterAndArchiveScope(_ int64, filter repositories.DocumentListFilter, _ repositories.ArchiveScope) ([]*models.Document, error) {
+func (r *filterCapturingDocRepo) ListDocumentsByDealWithFilterAndArchiveScope(_ context.Context, _ int64, filter repositories.DocumentListFilter, _ repositories.ArchiveScope) ([]*models.Document, error) {
 	r.capturedFilter = filter
 	return nil, nil
 }
 
-func (r *filterCapturingDocRepo) CountDocumentsWithFilterAndArchiveScope(filter repositories.DocumentListFilter, _ repositories.ArchiveScope) (int, error) {
+func (r *filterCapturingDocRepo) CountDocumentsWithFilterAndArchiveScope(_ context.Context, filter repositories.DocumentListFilter, _ repositories.ArchiveScope) (int, error) {
 	return 0, nil
 }
 
-func (r *filterCapturingDocRepo) ListDocumentsByDealWithFilterAndArchiveScopePaginated(_ int64, _, _ int, filter repositories.DocumentListFilter, _ repositories.ArchiveScope) ([]*models.Document, error) {
+func (r *filterCapturingDocRepo) ListDocumentsByDealWithFilterAndArchiveScopePaginated(_ context.Context, _ int64, _, _ int, filter repositories.DocumentListFilter, _ repositories.ArchiveScope) ([]*models.Document, error) {
 	r.capturedFilter = filter
 	return nil, nil
 }
@@ -238,7 +239,7 @@ func TestListDocumentsByDealWithFilter_InjectsHiddenVisibilityForNonAdmin(t *tes
 		DealRepo: &dealRepoStub{deal: &models.Deals{ID: 1, BranchID: &branch}},
 	}
 
-	_, _ = svc.ListDocumentsByDealWithFilter(1, mgmtUserID, authz.RoleManagement, repositories.DocumentListFilter{}, repositories.ArchiveScopeActiveOnly)
+	_, _ = svc.ListDocumentsByDealWithFilter(context.Background(), 1, mgmtUserID, authz.RoleManagement, repositories.DocumentListFilter{}, repositories.ArchiveScopeActiveOnly)
 
 	if repo.capturedFilter.HiddenVisibilityUserID == nil {
 		t.Fatal("management list must have HiddenVisibilityUserID set")
@@ -258,7 +259,7 @@ func TestListDocumentsByDealWithFilter_AdminNoHiddenFilter(t *testing.T) {
 		DealRepo: &dealRepoStub{deal: &models.Deals{ID: 1, BranchID: &branch}},
 	}
 
-	_, _ = svc.ListDocumentsByDealWithFilter(1, 1, authz.RoleSystemAdmin, repositories.DocumentListFilter{}, repositories.ArchiveScopeActiveOnly)
+	_, _ = svc.ListDocumentsByDealWithFilter(context.Background(), 1, 1, authz.RoleSystemAdmin, repositories.DocumentListFilter{}, repositories.ArchiveScopeActiveOnly)
 
 	if repo.capturedFilter.HiddenVisibilityUserID != nil {
 		t.Error("admin list must NOT have HiddenVisibilityUserID set (admin sees all)")
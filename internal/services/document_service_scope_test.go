@@ -21,6 +21,11 @@ func (r *docScopeUserRepoStub) ApplyUserPatch(int, *models.UserApprovalUpdatePay
 }
 func (r *docScopeUserRepoStub) Update(*models.User) error                   { return nil }
 func (r *docScopeUserRepoStub) Delete(int) error                            { return nil }
+func (r *docScopeUserRepoStub) Reactivate(int) error { return nil }
+func (r *docScopeUserRepoStub) Search(repositories.UserSearchFilter, int, int) ([]*models.User, error) {
+	return nil, nil
+}
+func (r *docScopeUserRepoStub) CountSearch(repositories.UserSearchFilter) (int, error) { return 0, nil }
 func (r *docScopeUserRepoStub) List(int, int) ([]*models.User, error)       { return nil, nil }
 func (r *docScopeUserRepoStub) GetByEmail(string) (*models.User, error)     { return nil, nil }
 func (r *docScopeUserRepoStub) GetAuthByEmail(string) (*models.User, error) { return nil, nil }
@@ -168,35 +173,35 @@ func TestEnsureDealAccess_HRAndLegalHaveUnrestrictedDealAccess(t *testing.T) {
 
 type docRepoStub struct{ doc *models.Document }
 
-func (r *docRepoStub) Create(*models.Document) (int64, error) { return 1, nil }
-func (r *docRepoStub) GetByID(int64) (*models.Document, error) {
+func (r *docRepoStub) Create(context.Context, *models.Document) (int64, error) { return 1, nil }
+func (r *docRepoStub) GetByID(context.Context, int64) (*models.Document, error) {
 	return r.doc, nil
 }
-func (r *docRepoStub) GetByIDWithArchiveScope(id int64, _ repositories.ArchiveScope) (*models.Document, error) {
+func (r *docRepoStub) GetByIDWithArchiveScope(_ context.Context, id int64, _ repositories.ArchiveScope) (*models.Document, error) {
 	return r.doc, nil
 }
-func (r *docRepoStub) ListDocuments(int, int) ([]*models.Document, error)      { return nil, nil }
-func (r *docRepoStub) ListDocumentsWithArchiveScope(int, int, repositories.ArchiveScope) ([]*models.Document, error) {
+func (r *docRepoStub) ListDocuments(context.Context, int, int) ([]*models.Document, error)      { return nil, nil }
+func (r *docRepoStub) ListDocumentsWithArchiveScope(context.Context, int, int, repositories.ArchiveScope) ([]*models.Document, error) {
 	return nil, nil
 }
-func (r *docRepoStub) ListDocumentsByDeal(int64) ([]*models.Document, error) { return nil, nil }
-func (r *docRepoStub) ListDocumentsByDealWithArchiveScope(int64, repositories.ArchiveScope) ([]*models.Document, error) {
+func (r *docRepoStub) ListDocumentsByDeal(context.Context, int64) ([]*models.Document, error) { return nil, nil }
+func (r *docRepoStub) ListDocumentsByDealWithArchiveScope(context.Context, int64, repositories.ArchiveScope) ([]*models.Document, error) {
 	return nil, nil
 }
-func (r *docRepoStub) Delete(int64) error                                           { return nil }
-func (r *docRepoStub) Archive(int64, int, string) error                             { return nil }
-func (r *docRepoStub) Unarchive(int64) error                                        { return nil }
-func (r *docRepoStub) UpdateStatus(int64, string) error                             { return nil }
-func (r *docRepoStub) MarkSigned(int64, string, time.Time) error                    { return nil }
-func (r *docRepoStub) Update(*models.Document) error                                { return nil }
-func (r *docRepoStub) UpdateSigningMeta(int64, string, string, string, string) error { return nil }
+func (r *docRepoStub) Delete(context.Context, int64) error                                           { return nil }
+func (r *docRepoStub) Archive(context.Context, int64, int, string) error                             { return nil }
+func (r *docRepoStub) Unarchive(context.Context, int64) error                                        { return nil }
+func (r *docRepoStub) UpdateStatus(context.Context, int64, string) error                             { return nil }
+func (r *docRepoStub) MarkSigned(context.Context, int64, string, time.Time) error                    { return nil }
+func (r *docRepoStub) Update(context.Context, *models.Document) error                                { return nil }
+func (r *docRepoStub) UpdateSigningMeta(context.Context, int64, string, string, string, string) error { return nil }
 
 type dealRepoStub struct{ deal *models.Deals }
 
-func (r *dealRepoStub) GetByID(int) (*models.Deals, error)                    { return r.deal, nil }
-func (r *dealRepoStub) GetByLeadID(int) (*models.Deals, error)                { return nil, nil }
-func (r *dealRepoStub) GetLatestByClientID(int) (*models.Deals, error)        { return nil, nil }
-func (r *dealRepoStub) GetLatestByClientRef(int, string) (*models.Deals, error) { return nil, nil }
+func (r *dealRepoStub) GetByID(context.Context, int) (*models.Deals, error)                    { return r.deal, nil }
+func (r *dealRepoStub) GetByLeadID(context.Context, int) (*models.Deals, error)                { return nil, nil }
+func (r *dealRepoStub) GetLatestByClientID(context.Context, int) (*models.Deals, error)        { return nil, nil }
+func (r *dealRepoStub) GetLatestByClientRef(context.Context, int, string) (*models.Deals, error) { return nil, nil }
 
 // ─── quality_control: delete is always forbidden ────────────────────────────
 
@@ -205,7 +210,7 @@ func (r *dealRepoStub) GetLatestByClientRef(int, string) (*models.Deals, error)
 // and service CanHardDeleteBusinessEntity — all independently block QC.
 func TestDeleteDocument_QCForbidden(t *testing.T) {
 	svc := &DocumentService{} // permission check fires before any repo access
-	err := svc.DeleteDocument(1, 1, authz.RoleControl)
+	err := svc.DeleteDocument(context.Background(), 1, 1, authz.RoleControl)
 	if err == nil {
 		t.Fatal("quality_control must not hard-delete documents")
 	}
@@ -214,7 +219,7 @@ func TestDeleteDocument_QCForbidden(t *testing.T) {
 // TestDeleteDocument_ManagementForbidden verifies management cannot hard-delete.
 func TestDeleteDocument_ManagementForbidden(t *testing.T) {
 	svc := &DocumentService{}
-	if err := svc.DeleteDocument(1, 1, authz.RoleManagement); err == nil {
+	if err := svc.DeleteDocument(context.Background(), 1, 1, authz.RoleManagement); err == nil {
 		t.Fatal("management must not hard-delete documents")
 	}
 }
@@ -226,7 +231,7 @@ func TestDeleteDocument_AdminAllowed(t *testing.T) {
 		DocRepo:  &docRepoStub{doc: &models.Document{}}, // empty file paths → deleteDocumentFiles no-ops
 		DealRepo: &dealRepoStub{deal: &models.Deals{BranchID: &branch}},
 	}
-	if err := svc.DeleteDocument(1, 1, authz.RoleSystemAdmin); err != nil {
+	if err := svc.DeleteDocument(context.Background(), 1, 1, authz.RoleSystemAdmin); err != nil {
 		t.Fatalf("admin must be able to delete documents, got: %v", err)
 	}
 }
@@ -242,7 +247,7 @@ func TestSubmit_QCOwnBranchAllowed(t *testing.T) {
 		DealRepo: &dealRepoStub{deal: &models.Deals{BranchID: &branch}},
 		UserRepo: &docScopeUserRepoStub{user: &models.User{BranchID: &branch}},
 	}
-	if err := svc.Submit(1, 100, authz.RoleControl); err != nil {
+	if err := svc.Submit(context.Background(), 1, 100, authz.RoleControl); err != nil {
 		t.Fatalf("QC must be able to submit own-branch document, got: %v", err)
 	}
 }
@@ -255,7 +260,7 @@ func TestSubmit_QCForeignBranchForbidden(t *testing.T) {
 		DealRepo: &dealRepoStub{deal: &models.Deals{BranchID: &dealBranch}},
 		UserRepo: &docScopeUserRepoStub{user: &models.User{BranchID: &qcBranch}},
 	}
-	if err := svc.Submit(1, 100, authz.RoleControl); err == nil {
+	if err := svc.Submit(context.Background(), 1, 100, authz.RoleControl); err == nil {
 		t.Fatal("QC must not submit document from foreign branch")
 	}
 }
@@ -271,7 +276,7 @@ func TestArchiveDocument_QCOwnBranchAllowed(t *testing.T) {
 		DealRepo: &dealRepoStub{deal: &models.Deals{BranchID: &branch}},
 		UserRepo: &docScopeUserRepoStub{user: &models.User{BranchID: &branch}},
 	}
-	if err := svc.ArchiveDocument(1, 100, authz.RoleControl, "reason"); err != nil {
+	if err := svc.ArchiveDocument(context.Background(), 1, 100, authz.RoleControl, "reason"); err != nil {
 		t.Fatalf("QC must be able to archive own-branch document, got: %v", err)
 	}
 }
@@ -284,7 +289,7 @@ func TestArchiveDocument_QCForeignBranchForbidden(t *testing.T) {
 		DealRepo: &dealRepoStub{deal: &models.Deals{BranchID: &dealBranch}},
 		UserRepo: &docScopeUserRepoStub{user: &models.User{BranchID: &qcBranch}},
 	}
-	if err := svc.ArchiveDocument(1, 100, authz.RoleControl, "reason"); err == nil {
+	if err := svc.ArchiveDocument(context.Background(), 1, 100, authz.RoleControl, "reason"); err == nil {
 		t.Fatal("QC must not archive foreign-branch document")
 	}
 }
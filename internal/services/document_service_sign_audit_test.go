@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+)
+
+type signAuditDocRepoStub struct {
+	docRepoStub
+	auditID     int64
+	auditUserID int
+	auditHash   string
+	signedAt    time.Time
+	status      string
+	reviewerID  int
+	reviewedAt  time.Time
+}
+
+func (r *signAuditDocRepoStub) MarkSigned(_ context.Context, _ int64, _ string, signedAt time.Time) error {
+	r.signedAt = signedAt
+	return nil
+}
+
+func (r *signAuditDocRepoStub) UpdateStatus(_ context.Context, _ int64, status string) error {
+	r.status = status
+	return nil
+}
+
+func (r *signAuditDocRepoStub) SetReviewAudit(_ context.Context, _ int64, userID int, at time.Time) error {
+	r.reviewerID, r.reviewedAt = userID, at
+	return nil
+}
+
+func (r *signAuditDocRepoStub) SetSignatureAudit(_ context.Context, id int64, userID int, hash string) error {
+	r.auditID, r.auditUserID, r.auditHash = id, userID, hash
+	return nil
+}
+
+func TestMarkDocumentSigned_RecordsSignerAndFileHash(t *testing.T) {
+	root := t.TempDir()
+	content := []byte("%PDF-1.4 signed contract")
+	if err := os.MkdirAll(filepath.Join(root, "pdf"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "pdf", "contract_5.pdf"), content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	repo := &signAuditDocRepoStub{docRepoStub: docRepoStub{doc: &models.Document{
+		ID:          5,
+		Status:      "approved",
+		FilePathPdf: "/pdf/contract_5.pdf",
+	}}}
+	svc := &DocumentService{DocRepo: repo, FilesRoot: root}
+
+	if err := svc.MarkDocumentSigned(context.Background(), 5, "Иван Иванов", nil, 42, authz.RoleManagement); err != nil {
+		t.Fatalf("MarkDocumentSigned() error = %v", err)
+	}
+	sum := sha256.Sum256(content)
+	if repo.auditID != 5 || repo.auditUserID != 42 {
+		t.Fatalf("audit stored for doc=%d user=%d, want doc=5 user=42", repo.auditID, repo.auditUserID)
+	}
+	if want := "sha256:" + hex.EncodeToString(sum[:]); repo.auditHash != want {
+		t.Fatalf("signature hash = %q, want %q", repo.auditHash, want)
+	}
+}
+
+func TestSign_RecordsActingUserAndTimestamp(t *testing.T) {
+	now := time.Date(2025, 4, 2, 11, 0, 0, 0, time.UTC)
+	repo := &signAuditDocRepoStub{docRepoStub: docRepoStub{doc: &models.Document{ID: 9, Status: "approved"}}}
+	svc := &DocumentService{DocRepo: repo}
+	svc.SetTimeProvider(func() time.Time { return now }, nil)
+
+	if err := svc.Sign(context.Background(), 9, 17, authz.RoleManagement); err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	if repo.auditID != 9 || repo.auditUserID != 17 {
+		t.Fatalf("signer stored for doc=%d user=%d, want doc=9 user=17", repo.auditID, repo.auditUserID)
+	}
+	if !repo.signedAt.Equal(now) {
+		t.Fatalf("signed_at = %v, want %v", repo.signedAt, now)
+	}
+}
+
+func TestReview_RecordsReviewerAndTimestamp(t *testing.T) {
+	now := time.Date(2025, 4, 1, 15, 30, 0, 0, time.UTC)
+	for action, wantStatus := range map[string]string{"approve": "approved", "return": "returned"} {
+		repo := &signAuditDocRepoStub{docRepoStub: docRepoStub{doc: &models.Document{ID: 9, DealID: 3, Status: "under_review"}}}
+		svc := &DocumentService{DocRepo: repo, DealRepo: &dealRepoStub{deal: &models.Deals{ID: 3}}}
+		svc.SetTimeProvider(func() time.Time { return now }, nil)
+
+		if err := svc.Review(context.Background(), 9, action, 23, authz.RoleManagement); err != nil {
+			t.Fatalf("Review(%s) error = %v", action, err)
+		}
+		if repo.status != wantStatus {
+			t.Fatalf("Review(%s) status = %q, want %q", action, repo.status, wantStatus)
+		}
+		if repo.reviewerID != 23 || !repo.reviewedAt.Equal(now) {
+			t.Fatalf("Review(%s) stored reviewer=%d at=%v, want 23 at %v", action, repo.reviewerID, repo.reviewedAt, now)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -12,32 +13,40 @@ type signerDocRepoStub struct {
 	doc *models.Document
 }
 
-func (r *signerDocRepoStub) Create(doc *models.Document) (int64, error) { return 0, nil }
-func (r *signerDocRepoStub) GetByID(id int64) (*models.Document, error) { return r.doc, nil }
-func (r *signerDocRepoStub) GetByIDWithArchiveScope(id int64, scope repositories.ArchiveScope) (*models.Document, error) {
+func (r *signerDocRepoStub) Create(_ context.Context, doc *models.Document) (int64, error) {
+	return 0, nil
+}
+func (r *signerDocRepoStub) GetByID(_ context.Context, id int64) (*models.Document, error) {
+	return r.doc, nil
+}
+func (r *signerDocRepoStub) GetByIDWithArchiveScope(_ context.Context, id int64, scope repositories.ArchiveScope) (*models.Document, error) {
 	return r.doc, nil
 }
-func (r *signerDocRepoStub) ListDocuments(limit, offset int) ([]*models.Document, error) {
+func (r *signerDocRepoStub) ListDocuments(_ context.Context, limit, offset int) ([]*models.Document, error) {
 	return nil, nil
 }
-func (r *signerDocRepoStub) ListDocumentsWithArchiveScope(limit, offset int, scope repositories.ArchiveScope) ([]*models.Document, error) {
+func (r *signerDocRepoStub) ListDocumentsWithArchiveScope(_ context.Context, limit, offset int, scope repositories.ArchiveScope) ([]*models.Document, error) {
 	return nil, nil
 }
-func (r *signerDocRepoStub) ListDocumentsByDeal(dealID int64) ([]*models.Document, error) {
+func (r *signerDocRepoStub) ListDocumentsByDeal(_ context.Context, dealID int64) ([]*models.Document, error) {
 	return nil, nil
 }
-func (r *signerDocRepoStub) ListDocumentsByDealWithArchiveScope(dealID int64, scope repositories.ArchiveScope) ([]*models.Document, error) {
+func (r *signerDocRepoStub) ListDocumentsByDealWithArchiveScope(_ context.Context, dealID int64, scope repositories.ArchiveScope) ([]*models.Document, error) {
 	return nil, nil
 }
-func (r *signerDocRepoStub) Delete(id int64) error                                 { return nil }
-func (r *signerDocRepoStub) Archive(id int64, archivedBy int, reason string) error { return nil }
-func (r *signerDocRepoStub) Unarchive(id int64) error                              { return nil }
-func (r *signerDocRepoStub) UpdateStatus(id int64, status string) error            { return nil }
-func (r *signerDocRepoStub) MarkSigned(id int64, signedBy string, signedAt time.Time) error {
+func (r *signerDocRepoStub) Delete(_ context.Context, id int64) error { return nil }
+func (r *signerDocRepoStub) Archive(_ context.Context, id int64, archivedBy int, reason string) error {
 	return nil
 }
-func (r *signerDocRepoStub) Update(doc *models.Document) error { return nil }
-func (r *signerDocRepoStub) UpdateSigningMeta(id int64, signMethod, signIP, signUserAgent, signMetadata string) error {
+func (r *signerDocRepoStub) Unarchive(_ context.Context, id int64) error { return nil }
+func (r *signerDocRepoStub) UpdateStatus(_ context.Context, id int64, status string) error {
+	return nil
+}
+func (r *signerDocRepoStub) MarkSigned(_ context.Context, id int64, signedBy string, signedAt time.Time) error {
+	return nil
+}
+func (r *signerDocRepoStub) Update(_ context.Context, doc *models.Document) error { return nil }
+func (r *signerDocRepoStub) UpdateSigningMeta(_ context.Context, id int64, signMethod, signIP, signUserAgent, signMetadata string) error {
 	return nil
 }
 
@@ -45,12 +54,16 @@ type signerDealRepoStub struct {
 	deal *models.Deals
 }
 
-func (r *signerDealRepoStub) GetByID(id int) (*models.Deals, error)         { return r.deal, nil }
-func (r *signerDealRepoStub) GetByLeadID(leadID int) (*models.Deals, error) { return nil, nil }
-func (r *signerDealRepoStub) GetLatestByClientID(clientID int) (*models.Deals, error) {
+func (r *signerDealRepoStub) GetByID(_ context.Context, id int) (*models.Deals, error) {
+	return r.deal, nil
+}
+func (r *signerDealRepoStub) GetByLeadID(_ context.Context, leadID int) (*models.Deals, error) {
 	return nil, nil
 }
-func (r *signerDealRepoStub) GetLatestByClientRef(clientID int, clientType string) (*models.Deals, error) {
+func (r *signerDealRepoStub) GetLatestByClientID(_ context.Context, clientID int) (*models.Deals, error) {
+	return nil, nil
+}
+func (r *signerDealRepoStub) GetLatestByClientRef(_ context.Context, clientID int, clientType string) (*models.Deals, error) {
 	return nil, nil
 }
 
@@ -58,7 +71,9 @@ type signerClientRepoStub struct {
 	client *models.Client
 }
 
-func (r *signerClientRepoStub) GetByID(id int) (*models.Client, error) { return r.client, nil }
+func (r *signerClientRepoStub) GetByID(_ context.Context, id int) (*models.Client, error) {
+	return r.client, nil
+}
 
 func newSignerResolutionService(client *models.Client) *DocumentService {
 	return &DocumentService{
@@ -70,7 +85,7 @@ func newSignerResolutionService(client *models.Client) *DocumentService {
 
 func TestResolveSignerForSMS_AllowsMissingEmailWhenPhoneExists(t *testing.T) {
 	svc := newSignerResolutionService(&models.Client{ID: 303, ClientType: models.ClientTypeIndividual, Phone: "+7 777 000 0000"})
-	resolved, err := svc.ResolveSignerForSMS(101, 11, 1, SignerOverrides{})
+	resolved, err := svc.ResolveSignerForSMS(context.Background(), 101, 11, 1, SignerOverrides{})
 	if err != nil {
 		t.Fatalf("ResolveSignerForSMS returned error: %v", err)
 	}
@@ -84,7 +99,7 @@ func TestResolveSignerForSMS_AllowsMissingEmailWhenPhoneExists(t *testing.T) {
 
 func TestResolveSignerForEmail_AllowsMissingPhoneWhenEmailExists(t *testing.T) {
 	svc := newSignerResolutionService(&models.Client{ID: 303, ClientType: models.ClientTypeIndividual, Email: "client@example.com"})
-	resolved, err := svc.ResolveSignerForEmail(101, 11, 1, SignerOverrides{})
+	resolved, err := svc.ResolveSignerForEmail(context.Background(), 101, 11, 1, SignerOverrides{})
 	if err != nil {
 		t.Fatalf("ResolveSignerForEmail returned error: %v", err)
 	}
@@ -98,7 +113,7 @@ func TestResolveSignerForEmail_AllowsMissingPhoneWhenEmailExists(t *testing.T) {
 
 func TestResolveSignerForSMS_ManualPhoneOverridesClientPhone(t *testing.T) {
 	svc := newSignerResolutionService(&models.Client{ID: 303, ClientType: models.ClientTypeIndividual, Phone: "+7 701 111 1111"})
-	resolved, err := svc.ResolveSignerForSMS(101, 11, 1, SignerOverrides{Phone: "+7 702 222 2222"})
+	resolved, err := svc.ResolveSignerForSMS(context.Background(), 101, 11, 1, SignerOverrides{Phone: "+7 702 222 2222"})
 	if err != nil {
 		t.Fatalf("ResolveSignerForSMS returned error: %v", err)
 	}
@@ -109,7 +124,7 @@ func TestResolveSignerForSMS_ManualPhoneOverridesClientPhone(t *testing.T) {
 
 func TestResolveSignerForEmail_ManualEmailOverridesClientEmail(t *testing.T) {
 	svc := newSignerResolutionService(&models.Client{ID: 303, ClientType: models.ClientTypeIndividual, Email: "client@example.com"})
-	resolved, err := svc.ResolveSignerForEmail(101, 11, 1, SignerOverrides{Email: "manual@example.com"})
+	resolved, err := svc.ResolveSignerForEmail(context.Background(), 101, 11, 1, SignerOverrides{Email: "manual@example.com"})
 	if err != nil {
 		t.Fatalf("ResolveSignerForEmail returned error: %v", err)
 	}
@@ -120,7 +135,7 @@ func TestResolveSignerForEmail_ManualEmailOverridesClientEmail(t *testing.T) {
 
 func TestGetSigningContactOptions_PrefersSMSWhenPhoneExists(t *testing.T) {
 	svc := newSignerResolutionService(&models.Client{ID: 303, ClientType: models.ClientTypeIndividual, Phone: "+7 700 123 4567", Email: "client@example.com"})
-	options, err := svc.GetSigningContactOptions(101, 11, 1)
+	options, err := svc.GetSigningContactOptions(context.Background(), 101, 11, 1)
 	if err != nil {
 		t.Fatalf("GetSigningContactOptions returned error: %v", err)
 	}
@@ -131,7 +146,7 @@ func TestGetSigningContactOptions_PrefersSMSWhenPhoneExists(t *testing.T) {
 
 func TestGetSigningContactOptions_FallsBackToEmailWhenPhoneMissing(t *testing.T) {
 	svc := newSignerResolutionService(&models.Client{ID: 303, ClientType: models.ClientTypeIndividual, Email: "client@example.com"})
-	options, err := svc.GetSigningContactOptions(101, 11, 1)
+	options, err := svc.GetSigningContactOptions(context.Background(), 101, 11, 1)
 	if err != nil {
 		t.Fatalf("GetSigningContactOptions returned error: %v", err)
 	}
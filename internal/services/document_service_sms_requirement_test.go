@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+)
+
+type smsConfirmationCheckerStub struct {
+	approved   bool
+	approvedAt time.Time
+	channel    string
+	since      time.Time
+}
+
+func (s *smsConfirmationCheckerStub) HasApprovedForDocument(_ context.Context, _ int64, channel string, since time.Time) (bool, error) {
+	s.channel = channel
+	s.since = since
+	return s.approved && !s.approvedAt.Before(since), nil
+}
+
+func TestMarkDocumentSigned_SMSRequirement(t *testing.T) {
+	cases := []struct {
+		name     string
+		docTypes []string
+		docType  string
+		approved bool
+		wantErr  error
+	}{
+		{name: "not configured", docTypes: nil, docType: "contract", wantErr: nil},
+		{name: "other doc type", docTypes: []string{"contract"}, docType: "invoice", wantErr: nil},
+		{name: "required, not confirmed", docTypes: []string{" Contract "}, docType: "contract", wantErr: ErrSMSSignatureRequired},
+		{name: "required, confirmed", docTypes: []string{"contract"}, docType: "contract", approved: true, wantErr: nil},
+		{name: "wildcard, not confirmed", docTypes: []string{"*"}, docType: "invoice", wantErr: ErrSMSSignatureRequired},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &signAuditDocRepoStub{docRepoStub: docRepoStub{doc: &models.Document{ID: 4, DocType: tc.docType, Status: "approved"}}}
+			checker := &smsConfirmationCheckerStub{approved: tc.approved}
+			svc := &DocumentService{DocRepo: repo}
+			svc.SetSMSSignatureRequirement(checker, tc.docTypes)
+
+			err := svc.MarkDocumentSigned(context.Background(), 4, "", nil, 1, authz.RoleManagement)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("MarkDocumentSigned() error = %v, want %v", err, tc.wantErr)
+			}
+			signed := !repo.signedAt.IsZero()
+			if signed != (tc.wantErr == nil) {
+				t.Fatalf("document signed = %v, want %v", signed, tc.wantErr == nil)
+			}
+			if tc.wantErr != nil && checker.channel != "sms" {
+				t.Fatalf("checked channel %q, want sms", checker.channel)
+			}
+		})
+	}
+}
+
+func TestSign_SMSRequiredWithoutCheckerFailsClosed(t *testing.T) {
+	repo := &signAuditDocRepoStub{docRepoStub: docRepoStub{doc: &models.Document{ID: 4, DocType: "contract", Status: "approved"}}}
+	svc := &DocumentService{DocRepo: repo}
+	svc.SetSMSSignatureRequirement(nil, []string{"contract"})
+
+	if err := svc.Sign(context.Background(), 4, 1, authz.RoleManagement); !errors.Is(err, ErrSMSSignatureRequired) {
+		t.Fatalf("Sign() error = %v, want ErrSMSSignatureRequired", err)
+	}
+}
+
+func TestSign_SMSConfirmationFromEarlierRoundIsIgnored(t *testing.T) {
+	created := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	reviewed := created.Add(48 * time.Hour)
+	cases := []struct {
+		name       string
+		reviewedAt *time.Time
+		approvedAt time.Time
+		wantSince  time.Time
+		wantErr    error
+	}{
+		{name: "approved before the last review", reviewedAt: &reviewed, approvedAt: reviewed.Add(-time.Hour), wantSince: reviewed, wantErr: ErrSMSSignatureRequired},
+		{name: "approved after the last review", reviewedAt: &reviewed, approvedAt: reviewed.Add(time.Hour), wantSince: reviewed},
+		{name: "never reviewed", approvedAt: created.Add(time.Hour), wantSince: created},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := &models.Document{ID: 4, DocType: "contract", Status: "approved", CreatedAt: created, ReviewedAt: tc.reviewedAt}
+			repo := &signAuditDocRepoStub{docRepoStub: docRepoStub{doc: doc}}
+			checker := &smsConfirmationCheckerStub{approved: true, approvedAt: tc.approvedAt}
+			svc := &DocumentService{DocRepo: repo}
+			svc.SetSMSSignatureRequirement(checker, []string{"contract"})
+
+			if err := svc.Sign(context.Background(), 4, 1, authz.RoleManagement); !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Sign() error = %v, want %v", err, tc.wantErr)
+			}
+			if !checker.since.Equal(tc.wantSince) {
+				t.Fatalf("checked approvals since %v, want %v", checker.since, tc.wantSince)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -14,42 +15,46 @@ type testDocumentRepo struct {
 	created []*models.Document
 }
 
-func (r *testDocumentRepo) Create(doc *models.Document) (int64, error) {
+func (r *testDocumentRepo) Create(_ context.Context, doc *models.Document) (int64, error) {
 	doc.ID = int64(len(r.created) + 1)
 	r.created = append(r.created, doc)
 	return doc.ID, nil
 }
-func (r *testDocumentRepo) GetByID(id int64) (*models.Document, error) { return nil, nil }
-func (r *testDocumentRepo) GetByIDWithArchiveScope(id int64, scope repositories.ArchiveScope) (*models.Document, error) {
+func (r *testDocumentRepo) GetByID(_ context.Context, id int64) (*models.Document, error) {
 	return nil, nil
 }
-func (r *testDocumentRepo) ListDocuments(limit, offset int) ([]*models.Document, error) {
+func (r *testDocumentRepo) GetByIDWithArchiveScope(_ context.Context, id int64, scope repositories.ArchiveScope) (*models.Document, error) {
 	return nil, nil
 }
-func (r *testDocumentRepo) ListDocumentsWithArchiveScope(limit, offset int, scope repositories.ArchiveScope) ([]*models.Document, error) {
+func (r *testDocumentRepo) ListDocuments(_ context.Context, limit, offset int) ([]*models.Document, error) {
 	return nil, nil
 }
-func (r *testDocumentRepo) ListDocumentsByDeal(dealID int64) ([]*models.Document, error) {
+func (r *testDocumentRepo) ListDocumentsWithArchiveScope(_ context.Context, limit, offset int, scope repositories.ArchiveScope) ([]*models.Document, error) {
 	return nil, nil
 }
-func (r *testDocumentRepo) ListDocumentsByDealWithArchiveScope(dealID int64, scope repositories.ArchiveScope) ([]*models.Document, error) {
+func (r *testDocumentRepo) ListDocumentsByDeal(_ context.Context, dealID int64) ([]*models.Document, error) {
 	return nil, nil
 }
-func (r *testDocumentRepo) Delete(id int64) error                                 { return nil }
-func (r *testDocumentRepo) Archive(id int64, archivedBy int, reason string) error { return nil }
-func (r *testDocumentRepo) Unarchive(id int64) error                              { return nil }
-func (r *testDocumentRepo) UpdateStatus(id int64, status string) error            { return nil }
-func (r *testDocumentRepo) MarkSigned(id int64, signedBy string, signedAt time.Time) error {
+func (r *testDocumentRepo) ListDocumentsByDealWithArchiveScope(_ context.Context, dealID int64, scope repositories.ArchiveScope) ([]*models.Document, error) {
+	return nil, nil
+}
+func (r *testDocumentRepo) Delete(_ context.Context, id int64) error { return nil }
+func (r *testDocumentRepo) Archive(_ context.Context, id int64, archivedBy int, reason string) error {
+	return nil
+}
+func (r *testDocumentRepo) Unarchive(_ context.Context, id int64) error                   { return nil }
+func (r *testDocumentRepo) UpdateStatus(_ context.Context, id int64, status string) error { return nil }
+func (r *testDocumentRepo) MarkSigned(_ context.Context, id int64, signedBy string, signedAt time.Time) error {
 	return nil
 }
-func (r *testDocumentRepo) Update(doc *models.Document) error { return nil }
-func (r *testDocumentRepo) UpdateSigningMeta(id int64, signMethod, signIP, signUserAgent, signMetadata string) error {
+func (r *testDocumentRepo) Update(_ context.Context, doc *models.Document) error { return nil }
+func (r *testDocumentRepo) UpdateSigningMeta(_ context.Context, id int64, signMethod, signIP, signUserAgent, signMetadata string) error {
 	return nil
 }
 
 type testLeadRepo struct{}
 
-func (r *testLeadRepo) GetByID(id int) (*models.Leads, error) { return nil, nil }
+func (r *testLeadRepo) GetByID(_ context.Context, id int) (*models.Leads, error) { return nil, nil }
 
 type testDealRepo struct {
 	byID               *models.Deals
@@ -59,12 +64,14 @@ type testDealRepo struct {
 	lastClientType     string
 }
 
-func (r *testDealRepo) GetByID(id int) (*models.Deals, error) { return r.byID, nil }
-func (r *testDealRepo) GetByLeadID(leadID int) (*models.Deals, error) {
+func (r *testDealRepo) GetByID(_ context.Context, id int) (*models.Deals, error) { return r.byID, nil }
+func (r *testDealRepo) GetByLeadID(_ context.Context, leadID int) (*models.Deals, error) {
 	return nil, nil
 }
-func (r *testDealRepo) GetLatestByClientID(clientID int) (*models.Deals, error) { return nil, nil }
-func (r *testDealRepo) GetLatestByClientRef(clientID int, clientType string) (*models.Deals, error) {
+func (r *testDealRepo) GetLatestByClientID(_ context.Context, clientID int) (*models.Deals, error) {
+	return nil, nil
+}
+func (r *testDealRepo) GetLatestByClientRef(_ context.Context, clientID int, clientType string) (*models.Deals, error) {
 	r.lastClientID = clientID
 	r.lastClientType = clientType
 	if r.latestByClientRefE != nil {
@@ -77,7 +84,9 @@ type testClientRepo struct {
 	client *models.Client
 }
 
-func (r *testClientRepo) GetByID(id int) (*models.Client, error) { return r.client, nil }
+func (r *testClientRepo) GetByID(_ context.Context, id int) (*models.Client, error) {
+	return r.client, nil
+}
 
 type testDocxGen struct{}
 
@@ -90,7 +99,7 @@ func (g *testDocxGen) GeneratePDF(templateName string, placeholders map[string]s
 
 func TestCreateDocumentFromClient_RequiresClientType(t *testing.T) {
 	svc := &DocumentService{DocxGen: &testDocxGen{}}
-	_, err := svc.CreateDocumentFromClient(1, "", 0, "contract_free_ru", 1, authz.RoleManagement, nil)
+	_, err := svc.CreateDocumentFromClient(context.Background(), 1, "", 0, "contract_free_ru", 1, authz.RoleManagement, nil)
 	if !errors.Is(err, ErrClientTypeRequired) {
 		t.Fatalf("expected ErrClientTypeRequired, got %v", err)
 	}
@@ -102,7 +111,7 @@ func TestCreateDocumentFromClient_WrongClientTypeFails(t *testing.T) {
 		DealRepo:   &testDealRepo{},
 		DocxGen:    &testDocxGen{},
 	}
-	_, err := svc.CreateDocumentFromClient(10, models.ClientTypeLegal, 0, "contract_free_ru", 1, authz.RoleManagement, nil)
+	_, err := svc.CreateDocumentFromClient(context.Background(), 10, models.ClientTypeLegal, 0, "contract_free_ru", 1, authz.RoleManagement, nil)
 	if !errors.Is(err, ErrClientTypeMismatch) {
 		t.Fatalf("expected ErrClientTypeMismatch, got %v", err)
 	}
@@ -114,7 +123,7 @@ func TestCreateDocumentFromClient_InvalidClientTypeFails(t *testing.T) {
 		DealRepo:   &testDealRepo{},
 		DocxGen:    &testDocxGen{},
 	}
-	_, err := svc.CreateDocumentFromClient(10, "partner", 0, "contract_free_ru", 1, authz.RoleManagement, nil)
+	_, err := svc.CreateDocumentFromClient(context.Background(), 10, "partner", 0, "contract_free_ru", 1, authz.RoleManagement, nil)
 	if err == nil {
 		t.Fatal("expected error for invalid client_type")
 	}
@@ -141,7 +150,7 @@ func TestCreateDocumentFromClient_UsesLatestDealByTypedRefWhenDealIDOmitted(t *t
 		DocxGen:    &testDocxGen{},
 	}
 
-	doc, err := svc.CreateDocumentFromClient(10, models.ClientTypeIndividual, 0, "contract_free_ru", 1, authz.RoleManagement, nil)
+	doc, err := svc.CreateDocumentFromClient(context.Background(), 10, models.ClientTypeIndividual, 0, "contract_free_ru", 1, authz.RoleManagement, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"testing"
 	"time"
+
+	"turcompany/internal/models"
 )
 
 type fakeSMSSender struct{}
@@ -52,3 +54,60 @@ func TestStartSigningByChannel_RoutesToEmail(t *testing.T) {
 		t.Fatalf("expected email channel result, got %+v", result.Channels)
 	}
 }
+
+type refreshingConfirmRepo struct {
+	fakeConfirmRepo
+	creates   int
+	refreshes int
+}
+
+func (r *refreshingConfirmRepo) CreatePending(ctx context.Context, documentID, userID int64, channel string, otpHash *string, tokenHash *string, expiresAt time.Time, meta []byte) (*models.SignatureConfirmation, error) {
+	r.creates++
+	return r.fakeConfirmRepo.CreatePending(ctx, documentID, userID, channel, otpHash, tokenHash, expiresAt, meta)
+}
+
+func (r *refreshingConfirmRepo) RefreshPending(_ context.Context, _, _ int64, _ string, otpHash *string, tokenHash *string, expiresAt time.Time, meta []byte) (*models.SignatureConfirmation, error) {
+	if r.item == nil || r.item.Status != "pending" {
+		return nil, nil
+	}
+	r.refreshes++
+	r.item.OTPHash = otpHash
+	r.item.TokenHash = tokenHash
+	r.item.ExpiresAt = expiresAt
+	r.item.Attempts = 0
+	r.item.Meta = meta
+	return r.item, nil
+}
+
+func TestStartSigningBySMS_ResendRefreshesPendingRow(t *testing.T) {
+	repo := &refreshingConfirmRepo{}
+	svc := NewDocumentSigningConfirmationService(
+		repo,
+		&fakeUserRepo{},
+		&fakeDocLookup{},
+		nil,
+		nil,
+		nil,
+		DocumentSigningConfirmationConfig{SMSTTL: 15 * time.Minute, SMSVerifyBaseURL: "http://localhost:4000"},
+		time.Now,
+	)
+	svc.SetSMSSender(&fakeSMSSender{})
+
+	if _, err := svc.StartSigningBySMS(context.Background(), 10, 20, "+77001234567", ""); err != nil {
+		t.Fatalf("first send: %v", err)
+	}
+	firstHash := *repo.item.OTPHash
+	if _, err := svc.StartSigningBySMS(context.Background(), 10, 20, "+77001234567", ""); err != nil {
+		t.Fatalf("resend: %v", err)
+	}
+
+	if repo.creates != 1 {
+		t.Fatalf("expected a single confirmation row, got %d creates", repo.creates)
+	}
+	if repo.refreshes != 1 {
+		t.Fatalf("expected resend to refresh the pending row, got %d refreshes", repo.refreshes)
+	}
+	if *repo.item.OTPHash == firstHash {
+		t.Fatal("expected resend to replace the OTP hash so only the new code confirms")
+	}
+}
@@ -77,7 +77,7 @@ type SigningTelegramSender interface {
 }
 
 type DocumentSigner interface {
-	FinalizeSigning(docID int64) error
+	FinalizeSigning(ctx context.Context, docID int64) error
 }
 
 type SignatureConfirmationStore interface {
@@ -95,8 +95,15 @@ type SignatureConfirmationStore interface {
 	UpdateMeta(ctx context.Context, id string, metaUpdate []byte) (*models.SignatureConfirmation, error)
 }
 
+// pendingConfirmationRefresher is an optional SignatureConfirmationStore
+// extension that reissues the latest pending confirmation instead of inserting
+// a new row.
+type pendingConfirmationRefresher interface {
+	RefreshPending(ctx context.Context, documentID, userID int64, channel string, otpHash *string, tokenHash *string, expiresAt time.Time, meta []byte) (*models.SignatureConfirmation, error)
+}
+
 type DocumentLookup interface {
-	GetByID(id int64) (*models.Document, error)
+	GetByID(ctx context.Context, id int64) (*models.Document, error)
 }
 
 type DocumentSigningConfirmationConfig struct {
@@ -317,7 +324,7 @@ func (s *DocumentSigningConfirmationService) StartSigning(ctx context.Context, d
 		return nil, errors.New("user not found")
 	}
 
-	doc, err := s.docRepo.GetByID(documentID)
+	doc, err := s.docRepo.GetByID(ctx, documentID)
 	if err != nil {
 		return nil, fmt.Errorf("get document: %w", err)
 	}
@@ -422,16 +429,13 @@ func (s *DocumentSigningConfirmationService) StartSigningBySMS(ctx context.Conte
 	if user == nil {
 		return nil, errors.New("user not found")
 	}
-	doc, err := s.docRepo.GetByID(documentID)
+	doc, err := s.docRepo.GetByID(ctx, documentID)
 	if err != nil {
 		return nil, fmt.Errorf("get document: %w", err)
 	}
 	if doc == nil {
 		return nil, errors.New("document not found")
 	}
-	if _, err := s.repo.CancelPrevious(ctx, documentID, userID, "sms"); err != nil {
-		return nil, err
-	}
 	now := s.now()
 	expiresAt := now.Add(s.smsTTL)
 	smsToken, smsTokenHash, err := generateConfirmToken(s.tokenPepper)
@@ -451,7 +455,7 @@ func (s *DocumentSigningConfirmationService) StartSigningBySMS(ctx context.Conte
 		"delivery_type": "sms",
 	}
 	metaBytes, _ := json.Marshal(meta)
-	confirmation, err := s.repo.CreatePending(ctx, documentID, userID, "sms", &otpHash, &smsTokenHash, expiresAt, metaBytes)
+	confirmation, err := s.issuePendingSMS(ctx, documentID, userID, &otpHash, &smsTokenHash, expiresAt, metaBytes)
 	if err != nil {
 		return nil, err
 	}
@@ -474,6 +478,32 @@ func (s *DocumentSigningConfirmationService) StartSigningBySMS(ctx context.Conte
 	return &SigningStartResult{DocumentID: documentID, UserID: userID, Policy: s.policy, Channels: channels}, nil
 }
 
+// issuePendingSMS stores the SMS confirmation for a (re)send. When the store
+// supports it, the latest pending row is refreshed in place so resends during a
+// flaky session do not pile up rows and only the newest code can be confirmed.
+func (s *DocumentSigningConfirmationService) issuePendingSMS(
+	ctx context.Context,
+	documentID, userID int64,
+	otpHash, tokenHash *string,
+	expiresAt time.Time,
+	meta []byte,
+) (*models.SignatureConfirmation, error) {
+	if refresher, ok := s.repo.(pendingConfirmationRefresher); ok {
+		confirmation, err := refresher.RefreshPending(ctx, documentID, userID, "sms", otpHash, tokenHash, expiresAt, meta)
+		if err != nil {
+			return nil, err
+		}
+		if confirmation != nil {
+			s.logConfirmState("refreshed", documentID, confirmation.ID, userID, expiresAt, int(s.smsTTL/time.Minute), "pending", "resend_sms")
+			return confirmation, nil
+		}
+	}
+	if _, err := s.repo.CancelPrevious(ctx, documentID, userID, "sms"); err != nil {
+		return nil, err
+	}
+	return s.repo.CreatePending(ctx, documentID, userID, "sms", otpHash, tokenHash, expiresAt, meta)
+}
+
 func (s *DocumentSigningConfirmationService) StartSigningByChannel(ctx context.Context, channel string, documentID, userID int64, signerPhone, signerEmail string) (*SigningStartResult, error) {
 	switch strings.ToLower(strings.TrimSpace(channel)) {
 	case "sms":
@@ -549,7 +579,7 @@ func (s *DocumentSigningConfirmationService) ConfirmByEmailToken(
 		return "", "", "", nil, ErrSignConfirmInvalidCode
 	}
 
-	documentHash, err := s.hashDocumentContent(pending.DocumentID)
+	documentHash, err := s.hashDocumentContent(ctx, pending.DocumentID)
 	if err != nil {
 		return "", "", "", pending, fmt.Errorf("hash document content: %w", err)
 	}
@@ -674,7 +704,7 @@ func (s *DocumentSigningConfirmationService) confirmByTokenAndChannel(
 		}
 		return "", "", "", nil, ErrSignConfirmInvalidCode
 	}
-	documentHash, err := s.hashDocumentContent(pending.DocumentID)
+	documentHash, err := s.hashDocumentContent(ctx, pending.DocumentID)
 	if err != nil {
 		return "", "", "", pending, fmt.Errorf("hash document content: %w", err)
 	}
@@ -823,7 +853,7 @@ func (s *DocumentSigningConfirmationService) ValidateEmailToken(
 		}
 	}
 
-	doc, err := s.docRepo.GetByID(pending.DocumentID)
+	doc, err := s.docRepo.GetByID(ctx, pending.DocumentID)
 	if err != nil {
 		return nil, fmt.Errorf("get document by id: %w", err)
 	}
@@ -839,7 +869,7 @@ func (s *DocumentSigningConfirmationService) ValidateEmailToken(
 	response.Document.Status = doc.Status
 	response.Document.FileName = documentFileName(doc)
 	response.Document.ContentType = documentContentType(response.Document.FileName)
-	if hash, hashErr := s.hashDocumentContent(pending.DocumentID); hashErr == nil && strings.TrimSpace(hash) != "" {
+	if hash, hashErr := s.hashDocumentContent(ctx, pending.DocumentID); hashErr == nil && strings.TrimSpace(hash) != "" {
 		response.Document.DocumentHashPreview = "sha256:" + hash
 	}
 	response.Confirmation.ExpiresAt = pending.ExpiresAt
@@ -903,7 +933,7 @@ func (s *DocumentSigningConfirmationService) PrepareEmailDocumentPreview(
 		return nil, ErrSignConfirmExpired
 	}
 
-	doc, relPath, err := s.getDocumentAndPreviewPath(pending.DocumentID)
+	doc, relPath, err := s.getDocumentAndPreviewPath(ctx, pending.DocumentID)
 	if err != nil {
 		return nil, err
 	}
@@ -917,7 +947,7 @@ func (s *DocumentSigningConfirmationService) PrepareEmailDocumentPreview(
 	}
 	fileName := documentFileName(doc)
 	documentHash := ""
-	if hash, hashErr := s.hashDocumentContent(doc.ID); hashErr == nil && strings.TrimSpace(hash) != "" {
+	if hash, hashErr := s.hashDocumentContent(ctx, doc.ID); hashErr == nil && strings.TrimSpace(hash) != "" {
 		documentHash = "sha256:" + hash
 	}
 	return &EmailDocumentPreview{
@@ -1230,9 +1260,9 @@ func (s *DocumentSigningConfirmationService) evaluatePolicy(ctx context.Context,
 	}
 	switch s.policy {
 	case SignConfirmPolicyAny:
-		return s.docSigner.FinalizeSigning(documentID)
+		return s.docSigner.FinalizeSigning(ctx, documentID)
 	case SignConfirmPolicyBoth:
-		return s.docSigner.FinalizeSigning(documentID)
+		return s.docSigner.FinalizeSigning(ctx, documentID)
 	default:
 		return fmt.Errorf("invalid sign confirm policy: %s", s.policy)
 	}
@@ -1408,7 +1438,7 @@ func (s *DocumentSigningConfirmationService) validateTokenByChannel(
 			return nil, fmt.Errorf("update confirmation meta: %w", err)
 		}
 	}
-	doc, err := s.docRepo.GetByID(pending.DocumentID)
+	doc, err := s.docRepo.GetByID(ctx, pending.DocumentID)
 	if err != nil || doc == nil {
 		return nil, ErrSignConfirmNotFound
 	}
@@ -1418,7 +1448,7 @@ func (s *DocumentSigningConfirmationService) validateTokenByChannel(
 	response.Document.Status = doc.Status
 	response.Document.FileName = documentFileName(doc)
 	response.Document.ContentType = documentContentType(response.Document.FileName)
-	if hash, hashErr := s.hashDocumentContent(pending.DocumentID); hashErr == nil && strings.TrimSpace(hash) != "" {
+	if hash, hashErr := s.hashDocumentContent(ctx, pending.DocumentID); hashErr == nil && strings.TrimSpace(hash) != "" {
 		response.Document.DocumentHashPreview = "sha256:" + hash
 	}
 	response.Confirmation.ExpiresAt = pending.ExpiresAt
@@ -1448,7 +1478,7 @@ func (s *DocumentSigningConfirmationService) prepareDocumentPreviewByChannel(
 		_ = s.repo.Expire(ctx, pending.ID)
 		return nil, ErrSignConfirmExpired
 	}
-	doc, relPath, err := s.getDocumentAndPreviewPath(pending.DocumentID)
+	doc, relPath, err := s.getDocumentAndPreviewPath(ctx, pending.DocumentID)
 	if err != nil {
 		return nil, err
 	}
@@ -1457,7 +1487,7 @@ func (s *DocumentSigningConfirmationService) prepareDocumentPreviewByChannel(
 		return nil, fmt.Errorf("open document: %w", err)
 	}
 	documentHash := ""
-	if hash, hashErr := s.hashDocumentContent(doc.ID); hashErr == nil && strings.TrimSpace(hash) != "" {
+	if hash, hashErr := s.hashDocumentContent(ctx, doc.ID); hashErr == nil && strings.TrimSpace(hash) != "" {
 		documentHash = "sha256:" + hash
 	}
 	return &EmailDocumentPreview{
@@ -1471,8 +1501,8 @@ func (s *DocumentSigningConfirmationService) prepareDocumentPreviewByChannel(
 	}, nil
 }
 
-func (s *DocumentSigningConfirmationService) hashDocumentContent(documentID int64) (string, error) {
-	_, rel, err := s.getDocumentAndPreviewPath(documentID)
+func (s *DocumentSigningConfirmationService) hashDocumentContent(ctx context.Context, documentID int64) (string, error) {
+	_, rel, err := s.getDocumentAndPreviewPath(ctx, documentID)
 	if err != nil {
 		return "", err
 	}
@@ -1493,11 +1523,11 @@ func (s *DocumentSigningConfirmationService) hashDocumentContent(documentID int6
 	return hex.EncodeToString(hasher.Sum(nil)), nil
 }
 
-func (s *DocumentSigningConfirmationService) getDocumentAndPreviewPath(documentID int64) (*models.Document, string, error) {
+func (s *DocumentSigningConfirmationService) getDocumentAndPreviewPath(ctx context.Context, documentID int64) (*models.Document, string, error) {
 	if s.docRepo == nil {
 		return nil, "", errors.New("document repo is nil")
 	}
-	doc, err := s.docRepo.GetByID(documentID)
+	doc, err := s.docRepo.GetByID(ctx, documentID)
 	if err != nil || doc == nil {
 		return nil, "", errors.New("document not found")
 	}
@@ -67,7 +67,7 @@ func (s *validateRepoStub) UpdateMeta(_ context.Context, _ string, metaUpdate []
 
 type validateDocRepoStub struct{ doc *models.Document }
 
-func (s *validateDocRepoStub) GetByID(id int64) (*models.Document, error) {
+func (s *validateDocRepoStub) GetByID(_ context.Context, id int64) (*models.Document, error) {
 	if s.doc != nil && s.doc.ID == id {
 		return s.doc, nil
 	}
@@ -76,7 +76,7 @@ func (s *validateDocRepoStub) GetByID(id int64) (*models.Document, error) {
 
 type validateSignerSpy struct{ calls int }
 
-func (s *validateSignerSpy) FinalizeSigning(int64) error {
+func (s *validateSignerSpy) FinalizeSigning(context.Context, int64) error {
 	s.calls++
 	return nil
 }
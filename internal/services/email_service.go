@@ -2,19 +2,26 @@ package services
 
 import (
 	"fmt"
+	"html"
 	"log"
 	"os"
 	"strings"
 	"time"
 
 	"gopkg.in/gomail.v2"
+
+	"turcompany/internal/models"
 )
 
 type EmailService interface {
 	SendWelcomeEmail(email, companyName string) error
-	SendPasswordResetEmail(email, resetURL string) error
+	SendPasswordResetEmail(email, resetURL, token string) error
+	SendEmailVerificationLink(email, link string) error
 	SendVerificationCode(toEmail, code string, ttlMinutes int) error
 	SendSigningConfirm(email string, data SigningEmailData) error
+	// SendTaskEmail is the email counterpart of the Telegram task
+	// notification; headline says what happened ("Вам назначена задача").
+	SendTaskEmail(email string, task *models.Task, headline string) error
 }
 
 type emailService struct {
@@ -42,11 +49,23 @@ func NewEmailService(smtpHost string, smtpPort int, smtpUser, smtpPassword, from
 }
 
 func (s *emailService) SendWelcomeEmail(email, companyName string) error {
+	if err := s.dialer.DialAndSend(s.welcomeMessage(email, companyName)); err != nil {
+		return fmt.Errorf("failed to send welcome email: %w", err)
+	}
+
+	return nil
+}
+
+func (s *emailService) welcomeMessage(email, companyName string) *gomail.Message {
 	m := gomail.NewMessage()
 	setFromHeader(m, s.from, s.fromName)
 	m.SetHeader("To", email)
 	m.SetHeader("Subject", "Welcome to TurCompany!")
 
+	text := fmt.Sprintf(
+		"Welcome to TurCompany, %s!\n\nThank you for registering with us. We're excited to have you on board.\nYour account has been successfully created.\n\nBest regards,\nThe TurCompany Team",
+		companyName,
+	)
 	body := fmt.Sprintf(`
 		<h2>Welcome to TurCompany, %s!</h2>
 		<p>Thank you for registering with us. We're excited to have you on board.</p>
@@ -54,37 +73,86 @@ func (s *emailService) SendWelcomeEmail(email, companyName string) error {
 		<p>Best regards,<br>The TurCompany Team</p>
 	`, companyName)
 
-	m.SetBody("text/html", body)
+	m.SetBody("text/plain", text)
+	m.AddAlternative("text/html", body)
+	return m
+}
 
-	if err := s.dialer.DialAndSend(m); err != nil {
-		return fmt.Errorf("failed to send welcome email: %w", err)
+func (s *emailService) SendPasswordResetEmail(email, resetURL, token string) error {
+	if err := s.dialer.DialAndSend(s.passwordResetMessage(email, resetURL, token)); err != nil {
+		return fmt.Errorf("failed to send password reset email: %w", err)
 	}
 
 	return nil
 }
-func (s *emailService) SendPasswordResetEmail(email, resetURL string) error {
+
+// passwordResetMessage builds the reset email around the clickable link; the
+// raw token is kept only as a fallback line for clients that mangle links or
+// when no frontend link could be built.
+func (s *emailService) passwordResetMessage(email, resetURL, token string) *gomail.Message {
 	m := gomail.NewMessage()
 	setFromHeader(m, s.from, s.fromName)
 	m.SetHeader("To", email)
 	m.SetHeader("Subject", "Password reset request")
 
-	body := fmt.Sprintf(`
+	resetURL = strings.TrimSpace(resetURL)
+	token = strings.TrimSpace(token)
+
+	var text, body strings.Builder
+	text.WriteString("Password reset requested\n\nWe received a request to reset the password for your account.\n")
+	body.WriteString(`
                 <h3>Password reset requested</h3>
                 <p>We received a request to reset the password for your account.</p>
-                <p>Use the following link to reset your password: <a href="%s">Reset password</a></p>
+`)
+	if resetURL != "" {
+		escapedURL := html.EscapeString(resetURL)
+		fmt.Fprintf(&text, "Open this link to reset your password: %s\n", resetURL)
+		fmt.Fprintf(&body, `                <p>Use the following link to reset your password: <a href="%s">Reset password</a></p>
                 <p>If the button doesn't work, copy and paste this URL into your browser: %s</p>
-                <p>If you did not request this change, you can ignore this email.</p>
-        `, resetURL, resetURL)
+`, escapedURL, escapedURL)
+	}
+	if token != "" {
+		fmt.Fprintf(&text, "If the link does not work, enter this reset code manually: %s\n", token)
+		fmt.Fprintf(&body, "                <p>If the link does not work, enter this reset code manually: <code>%s</code></p>\n", html.EscapeString(token))
+	}
+	text.WriteString("\nIf you did not request this change, you can ignore this email.")
+	body.WriteString("                <p>If you did not request this change, you can ignore this email.</p>\n")
 
-	m.SetBody("text/html", body)
+	m.SetBody("text/plain", text.String())
+	m.AddAlternative("text/html", body.String())
+	return m
+}
 
-	if err := s.dialer.DialAndSend(m); err != nil {
-		return fmt.Errorf("failed to send password reset email: %w", err)
+func (s *emailService) SendEmailVerificationLink(email, link string) error {
+	if err := s.dialer.DialAndSend(s.emailVerificationMessage(email, link)); err != nil {
+		return fmt.Errorf("failed to send email verification link: %w", err)
 	}
 
 	return nil
 }
 
+func (s *emailService) emailVerificationMessage(email, link string) *gomail.Message {
+	m := gomail.NewMessage()
+	setFromHeader(m, s.from, s.fromName)
+	m.SetHeader("To", email)
+	m.SetHeader("Subject", "Confirm your email address")
+
+	text := fmt.Sprintf(
+		"Confirm your email\n\nPlease confirm the email address for your account: %s\n\nIf you did not register, you can ignore this email.",
+		link,
+	)
+	body := fmt.Sprintf(`
+                <h3>Confirm your email</h3>
+                <p>Please confirm the email address for your account: <a href="%s">Confirm email</a></p>
+                <p>If the button doesn't work, copy and paste this URL into your browser: %s</p>
+                <p>If you did not register, you can ignore this email.</p>
+        `, link, link)
+
+	m.SetBody("text/plain", text)
+	m.AddAlternative("text/html", body)
+	return m
+}
+
 func (s *emailService) SendVerificationCode(toEmail, code string, ttlMinutes int) error {
 	if shouldLogVerificationCode() {
 		log.Printf("[DEV][email][verify] to=%s code=%s ttl=%d", toEmail, code, ttlMinutes)
@@ -155,6 +223,45 @@ func (s *emailService) SendSigningConfirm(email string, data SigningEmailData) e
 	return nil
 }
 
+func (s *emailService) SendTaskEmail(email string, task *models.Task, headline string) error {
+	if task == nil {
+		return nil
+	}
+	headline = strings.TrimSpace(headline)
+	if headline == "" {
+		headline = "Задача"
+	}
+	m := gomail.NewMessage()
+	setFromHeader(m, s.from, s.fromName)
+	m.SetHeader("To", email)
+	m.SetHeader("Subject", fmt.Sprintf("%s: %s", headline, task.Title))
+
+	due := "—"
+	if task.DueDate != nil {
+		due = task.DueDate.Format("02.01.2006 15:04")
+	}
+	text := fmt.Sprintf(
+		"%s\n%s\n\nСтатус: %s\nПриоритет: %s\nСрок: %s\nЗадача №%d",
+		headline, task.Title, task.Status, task.Priority, due, task.ID,
+	)
+	body := fmt.Sprintf(
+		`<h3>%s</h3><p><strong>%s</strong></p><p>Статус: %s<br>Приоритет: %s<br>Срок: %s</p><p>Задача №%d</p>`,
+		html.EscapeString(headline),
+		html.EscapeString(task.Title),
+		html.EscapeString(string(task.Status)),
+		html.EscapeString(string(task.Priority)),
+		html.EscapeString(due),
+		task.ID,
+	)
+	m.SetBody("text/plain", text)
+	m.AddAlternative("text/html", body)
+
+	if err := s.dialer.DialAndSend(m); err != nil {
+		return fmt.Errorf("failed to send task email: %w", err)
+	}
+	return nil
+}
+
 func setFromHeader(m *gomail.Message, from, fromName string) {
 	if strings.TrimSpace(fromName) == "" {
 		m.SetHeader("From", from)
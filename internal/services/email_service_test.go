@@ -0,0 +1,113 @@
+package services
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"gopkg.in/gomail.v2"
+)
+
+func renderMessage(t *testing.T, m *gomail.Message) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if _, err := m.WriteTo(&buf); err != nil {
+		t.Fatalf("write message: %v", err)
+	}
+	return buf.String()
+}
+
+func TestEmailService_MessagesIncludePlainTextAlternative(t *testing.T) {
+	s := &emailService{from: "noreply@example.com", fromName: "TurCompany"}
+
+	cases := map[string]*gomail.Message{
+		"welcome":        s.welcomeMessage("user@example.com", "Acme"),
+		"password_reset": s.passwordResetMessage("user@example.com", "https://example.com/reset-password?token=abc", "abc"),
+		"verification":   s.emailVerificationMessage("user@example.com", "https://example.com/verify?token=abc"),
+	}
+	for name, m := range cases {
+		t.Run(name, func(t *testing.T) {
+			raw := renderMessage(t, m)
+			if !strings.Contains(raw, "multipart/alternative") {
+				t.Fatalf("expected multipart/alternative message, got:\n%s", raw)
+			}
+			if !strings.Contains(raw, "Content-Type: text/plain") {
+				t.Fatalf("expected text/plain part, got:\n%s", raw)
+			}
+			if !strings.Contains(raw, "Content-Type: text/html") {
+				t.Fatalf("expected text/html part, got:\n%s", raw)
+			}
+		})
+	}
+}
+
+// messageParts decodes a rendered multipart message into its bodies keyed by
+// media type.
+func messageParts(t *testing.T, raw string) map[string]string {
+	t.Helper()
+	msg, err := mail.ReadMessage(strings.NewReader(raw))
+	if err != nil {
+		t.Fatalf("parse message: %v", err)
+	}
+	_, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("parse content type: %v", err)
+	}
+	parts := map[string]string{}
+	r := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		p, err := r.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("read part: %v", err)
+		}
+		mediaType, _, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		body, err := io.ReadAll(p)
+		if err != nil {
+			t.Fatalf("read part body: %v", err)
+		}
+		parts[mediaType] = string(body)
+	}
+	return parts
+}
+
+func plainTextPart(t *testing.T, raw string) string {
+	t.Helper()
+	plain, ok := messageParts(t, raw)["text/plain"]
+	if !ok {
+		t.Fatalf("text/plain part missing:\n%s", raw)
+	}
+	return plain
+}
+
+func TestEmailService_PasswordResetPlainTextContainsLinkAndTokenFallback(t *testing.T) {
+	s := &emailService{from: "noreply@example.com"}
+	raw := renderMessage(t, s.passwordResetMessage("user@example.com", "https://example.com/reset-password?token=tok123", "tok123"))
+	plain := plainTextPart(t, raw)
+	if !strings.Contains(plain, "https://example.com/reset-password?token=tok123") {
+		t.Fatalf("plain-text part must contain reset link, got:\n%s", plain)
+	}
+	if !strings.Contains(plain, "enter this reset code manually: tok123") {
+		t.Fatalf("plain-text part must keep the raw token as a fallback line, got:\n%s", plain)
+	}
+	if strings.Contains(plain, "<a ") {
+		t.Fatalf("plain-text part must not contain HTML, got:\n%s", plain)
+	}
+}
+
+func TestEmailService_PasswordResetWithoutLinkFallsBackToToken(t *testing.T) {
+	s := &emailService{from: "noreply@example.com"}
+	raw := renderMessage(t, s.passwordResetMessage("user@example.com", "", "tok123"))
+	if strings.Contains(messageParts(t, raw)["text/html"], "href=") {
+		t.Fatalf("no link must be rendered without a reset URL, got:\n%s", raw)
+	}
+	if !strings.Contains(plainTextPart(t, raw), "tok123") {
+		t.Fatalf("token fallback missing, got:\n%s", raw)
+	}
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"turcompany/internal/repositories"
+	"turcompany/internal/utils"
+)
+
+var (
+	ErrEmailVerificationNotFound = errors.New("email verification token not found")
+	ErrEmailVerificationExpired  = errors.New("email verification token expired")
+	ErrEmailVerificationUsed     = errors.New("email verification token already used")
+	// ErrEmailVerificationNotConfigured means no link can be sent: the public
+	// base URL or the mailer is missing, or the base URL is not on the
+	// redirect allowlist.
+	ErrEmailVerificationNotConfigured = errors.New("email verification is not configured")
+)
+
+const DefaultEmailVerificationTTL = 24 * time.Hour
+
+// EmailVerificationService sends one-time "confirm your email" links on
+// registration and marks the address verified when the link is opened. It runs
+// alongside the SMS/OTP flow in UserVerificationService.
+type EmailVerificationService struct {
+	repo    repositories.EmailVerificationRepository
+	emails  EmailService
+	baseURL string
+	TTL     time.Duration
+	now     func() time.Time
+}
+
+func NewEmailVerificationService(repo repositories.EmailVerificationRepository, emails EmailService, baseURL string, now func() time.Time) *EmailVerificationService {
+	if now == nil {
+		now = time.Now
+	}
+	return &EmailVerificationService{
+		repo:    repo,
+		emails:  emails,
+		baseURL: strings.TrimRight(strings.TrimSpace(baseURL), "/"),
+		TTL:     DefaultEmailVerificationTTL,
+		now:     now,
+	}
+}
+
+// Send stores a new token for the user and emails the confirmation link. It
+// returns the raw token so callers (and tests) can build the link themselves.
+func (s *EmailVerificationService) Send(userID int, email string) (string, error) {
+	if s.repo == nil {
+		return "", fmt.Errorf("email verification repo is nil")
+	}
+	email = strings.TrimSpace(email)
+	if userID <= 0 || email == "" {
+		return "", fmt.Errorf("user id and email are required")
+	}
+
+	if s.emails == nil || s.baseURL == "" {
+		return "", ErrEmailVerificationNotConfigured
+	}
+
+	token, err := utils.NewRefreshToken(32)
+	if err != nil {
+		return "", err
+	}
+	link := s.buildLink(token)
+	if link == "" {
+		return "", ErrEmailVerificationNotConfigured
+	}
+	ttl := s.TTL
+	if ttl <= 0 {
+		ttl = DefaultEmailVerificationTTL
+	}
+	if err := s.repo.Create(userID, hashEmailVerificationToken(token), s.now().Add(ttl)); err != nil {
+		return "", err
+	}
+
+	if err := s.emails.SendEmailVerificationLink(email, link); err != nil {
+		log.Printf("[email-verify] failed to send link user_id=%d: %v", userID, err)
+		return token, err
+	}
+	return token, nil
+}
+
+// Confirm validates the token and sets users.email_verified_at. It returns
+// the ID of the verified user.
+func (s *EmailVerificationService) Confirm(token string) (int, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return 0, ErrEmailVerificationNotFound
+	}
+	ev, err := s.repo.GetByTokenHash(hashEmailVerificationToken(token))
+	if err != nil {
+		return 0, err
+	}
+	if ev == nil {
+		return 0, ErrEmailVerificationNotFound
+	}
+	if ev.UsedAt != nil {
+		return 0, ErrEmailVerificationUsed
+	}
+	now := s.now()
+	if now.After(ev.ExpiresAt) {
+		return 0, ErrEmailVerificationExpired
+	}
+	if err := s.repo.Confirm(ev.ID, ev.UserID, now); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrEmailVerificationUsed
+		}
+		return 0, err
+	}
+	return ev.UserID, nil
+}
+
+func (s *EmailVerificationService) buildLink(token string) string {
+	if s.baseURL == "" {
+		return ""
+	}
+	link := fmt.Sprintf("%s/verify-email?token=%s", s.baseURL, url.QueryEscape(token))
+	if !utils.IsAllowedRedirect(link) {
+		log.Printf("[email-verify] base url %q is not on the redirect allowlist; link omitted", s.baseURL)
+		return ""
+	}
+	return link
+}
+
+func hashEmailVerificationToken(token string) string {
+	return hashPublicTokenWithPepper(token, "")
+}
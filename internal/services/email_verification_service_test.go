@@ -0,0 +1,150 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"turcompany/internal/models"
+	"turcompany/internal/utils"
+)
+
+type fakeEmailVerificationRepo struct {
+	rows       []*models.EmailVerification
+	verifiedAt map[int]time.Time
+}
+
+func (r *fakeEmailVerificationRepo) Create(userID int, tokenHash string, expiresAt time.Time) error {
+	r.rows = append(r.rows, &models.EmailVerification{ID: int64(len(r.rows) + 1), UserID: userID, TokenHash: tokenHash, ExpiresAt: expiresAt})
+	return nil
+}
+
+func (r *fakeEmailVerificationRepo) GetByTokenHash(tokenHash string) (*models.EmailVerification, error) {
+	for _, ev := range r.rows {
+		if ev.TokenHash == tokenHash {
+			return ev, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeEmailVerificationRepo) Confirm(id int64, userID int, at time.Time) error {
+	for _, ev := range r.rows {
+		if ev.ID == id {
+			ev.UsedAt = &at
+		}
+	}
+	if r.verifiedAt == nil {
+		r.verifiedAt = map[int]time.Time{}
+	}
+	r.verifiedAt[userID] = at
+	return nil
+}
+
+type linkCaptureMailer struct {
+	noopMailService
+	to, link string
+}
+
+func (m *linkCaptureMailer) SendEmailVerificationLink(email, link string) error {
+	m.to, m.link = email, link
+	return nil
+}
+
+func allowEmailVerificationOrigin(t *testing.T) {
+	t.Helper()
+	utils.SetAllowedRedirectOrigins([]string{"https://crm.example.com"})
+	t.Cleanup(func() { utils.SetAllowedRedirectOrigins(nil) })
+}
+
+func TestEmailVerificationSendStoresHashedTokenAndEmailsLink(t *testing.T) {
+	allowEmailVerificationOrigin(t)
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	repo := &fakeEmailVerificationRepo{}
+	mailer := &linkCaptureMailer{}
+	svc := NewEmailVerificationService(repo, mailer, "https://crm.example.com/", func() time.Time { return now })
+
+	token, err := svc.Send(7, "user@example.com")
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if len(repo.rows) != 1 {
+		t.Fatalf("expected one stored token, got %d", len(repo.rows))
+	}
+	row := repo.rows[0]
+	if row.UserID != 7 || row.TokenHash == token || row.TokenHash != hashEmailVerificationToken(token) {
+		t.Fatalf("unexpected stored row %+v", row)
+	}
+	if !row.ExpiresAt.Equal(now.Add(DefaultEmailVerificationTTL)) {
+		t.Fatalf("unexpected expiry %v", row.ExpiresAt)
+	}
+	if mailer.to != "user@example.com" || !strings.HasPrefix(mailer.link, "https://crm.example.com/verify-email?token=") {
+		t.Fatalf("unexpected email to=%q link=%q", mailer.to, mailer.link)
+	}
+}
+
+func TestEmailVerificationConfirmMarksUserVerified(t *testing.T) {
+	allowEmailVerificationOrigin(t)
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	repo := &fakeEmailVerificationRepo{}
+	svc := NewEmailVerificationService(repo, noopMailService{}, "https://crm.example.com", func() time.Time { return now })
+
+	token, err := svc.Send(7, "user@example.com")
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	userID, err := svc.Confirm(token)
+	if err != nil || userID != 7 {
+		t.Fatalf("confirm: user=%d err=%v", userID, err)
+	}
+	if at, ok := repo.verifiedAt[7]; !ok || !at.Equal(now) {
+		t.Fatalf("expected email_verified_at to be set, got %v", repo.verifiedAt)
+	}
+	if _, err := svc.Confirm(token); !errors.Is(err, ErrEmailVerificationUsed) {
+		t.Fatalf("expected used token error on replay, got %v", err)
+	}
+	if _, err := svc.Confirm("bogus"); !errors.Is(err, ErrEmailVerificationNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestEmailVerificationConfirmRejectsExpiredToken(t *testing.T) {
+	allowEmailVerificationOrigin(t)
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	repo := &fakeEmailVerificationRepo{}
+	svc := NewEmailVerificationService(repo, noopMailService{}, "https://crm.example.com", func() time.Time { return now })
+
+	token, err := svc.Send(7, "user@example.com")
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	now = now.Add(DefaultEmailVerificationTTL + time.Minute)
+	if _, err := svc.Confirm(token); !errors.Is(err, ErrEmailVerificationExpired) {
+		t.Fatalf("expected expired, got %v", err)
+	}
+	if len(repo.verifiedAt) != 0 {
+		t.Fatalf("expired token must not verify the user")
+	}
+}
+
+func TestEmailVerificationSendWithoutUsableLinkIsNotConfigured(t *testing.T) {
+	allowEmailVerificationOrigin(t)
+	for name, base := range map[string]string{
+		"empty base url":       "",
+		"base not allowlisted": "https://evil.example.net",
+	} {
+		t.Run(name, func(t *testing.T) {
+			repo := &fakeEmailVerificationRepo{}
+			mailer := &linkCaptureMailer{}
+			svc := NewEmailVerificationService(repo, mailer, base, nil)
+
+			if _, err := svc.Send(7, "user@example.com"); !errors.Is(err, ErrEmailVerificationNotConfigured) {
+				t.Fatalf("expected ErrEmailVerificationNotConfigured, got %v", err)
+			}
+			if len(repo.rows) != 0 || mailer.link != "" {
+				t.Fatalf("nothing may be stored or sent: rows=%d link=%q", len(repo.rows), mailer.link)
+			}
+		})
+	}
+}
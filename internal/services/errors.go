@@ -3,6 +3,8 @@ package services
 import (
 	"errors"
 	"fmt"
+
+	"turcompany/internal/repositories"
 )
 
 var (
@@ -13,7 +15,9 @@ var (
 	// It wraps ErrForbidden so existing 403 handler mappings keep working.
 	ErrClientEditNeedsApproval = fmt.Errorf("%w: client edits require admin approval via feed", ErrForbidden)
 	ErrReadOnly                = errors.New("read-only role")
-	ErrNotFound                  = errors.New("not found")
+	// ErrNotFound is shared with the repositories so lookups that report a
+	// missing row surface as 404 without re-mapping.
+	ErrNotFound                  = repositories.ErrNotFound
 	ErrNotChatMember             = errors.New("user is not a member of this chat")
 	ErrChatNotFound              = errors.New("chat not found")
 	ErrChatForbidden             = errors.New("chat action is forbidden")
@@ -29,8 +33,13 @@ var (
 	ErrLeadIDRequired                   = errors.New("lead_id is required")
 	ErrClientIDRequired                 = errors.New("client_id is required")
 	ErrAmountInvalid                    = errors.New("amount must be greater than 0")
+	ErrCurrencyRequired                 = errors.New("currency is required")
+	ErrInvalidCurrency                  = errors.New("invalid currency")
 	ErrDealNotFound                     = errors.New("deal not found")
 	ErrLeadNotFound                     = errors.New("lead not found")
+	ErrLeadNotConvertible               = errors.New("lead is not in a convertible status")
+	ErrInvalidLeadSource                = errors.New("invalid lead source")
+	ErrInvalidLeadScore                 = errors.New("score must be between 0 and 100")
 	ErrClientNotFound                   = errors.New("client not found")
 	ErrClientTypeRequired               = errors.New("client_type is required")
 	ErrInvalidClientType                = errors.New("invalid client_type")
@@ -42,10 +51,13 @@ var (
 	ErrEmailAlreadyUsed                 = errors.New("email already used")
 	ErrClientAlreadyExists              = errors.New("client already exists")
 	ErrRoleInUse                        = errors.New("role is in use")
+	ErrUnknownRole                      = errors.New("unknown role")
+	ErrInvalidCurrentPassword           = errors.New("current password is incorrect")
 	ErrIndividualIINExists              = errors.New("individual profile with this IIN already exists")
 	ErrLegalBINExists                   = errors.New("legal profile with this BIN already exists")
 	ErrClientFilePrimaryExists          = errors.New("primary file for this category already exists")
 	ErrClientInUse                      = errors.New("client has linked entities")
+	ErrClientMergeSameClient            = errors.New("primary and duplicate client must differ")
 	ErrPublicLinkAlreadyUsed            = errors.New("public link already used")
 	ErrResetTokenAlreadyUsed            = errors.New("password reset token already used")
 	ErrTelegramLinkAlreadyUsed          = errors.New("telegram link already used")
@@ -66,6 +78,45 @@ var (
 
 	ErrStageHasDeals          = errors.New("stage has deals, target stage required to reassign")
 	ErrInvalidStageTransition = errors.New("invalid stage transition")
+
+	// ErrTaskVersionConflict is returned when a task update carries a stale
+	// version (another user saved the task first).
+	ErrTaskVersionConflict = errors.New("task was modified by someone else")
+	// ErrInvalidTaskEntityType / ErrTaskEntityIDRequired reject task links
+	// that point to an unknown entity kind or to no entity at all.
+	ErrInvalidTaskEntityType = errors.New("invalid entity_type")
+	ErrTaskEntityIDRequired  = errors.New("entity_id is required when entity_type is set")
+	// ErrInvalidTaskAssignee rejects assignees that do not exist or are
+	// deactivated.
+	ErrInvalidTaskAssignee = errors.New("assignee must be an active user")
+
+	// ErrInvalidWebhookURL / ErrUnknownWebhookEvent reject webhook
+	// subscriptions that cannot be delivered or listen to nothing we emit.
+	ErrInvalidWebhookURL   = errors.New("webhook url must be an absolute http(s) url")
+	ErrUnknownWebhookEvent = errors.New("unknown webhook event type")
+
+	// Task attachment upload limits.
+	ErrTaskAttachmentTooLarge       = errors.New("attachment is too large")
+	ErrTaskAttachmentTypeNotAllowed = errors.New("attachment type not allowed")
+
+	// Document errors. Handlers map these with errors.Is; the messages are
+	// kept identical to the strings they replaced.
+	ErrInvalidStatus             = errors.New("invalid status")
+	ErrBadAction                 = errors.New("bad action")
+	ErrFileNotFound              = errors.New("file not found")
+	ErrBadFilePath               = errors.New("bad filepath")
+	ErrInvalidFilename           = errors.New("invalid filename")
+	ErrDocTypeRequired           = errors.New("doc_type is required")
+	ErrUnsupportedDocType        = errors.New("unsupported doc_type")
+	ErrUnsupportedDocTypeForLead = errors.New("unsupported_doc_type_for_lead_use_create_from_client")
+	ErrDocumentNotApproved       = errors.New("document must be approved before signature")
+	ErrDocumentAlreadySigned     = errors.New("document is already signed")
+	ErrDocumentNotRegenerable    = errors.New("only contracts and invoices generated from a lead can be regenerated")
+	ErrSMSSignatureRequired      = errors.New("document must be confirmed by sms before it is signed")
+	ErrDealClientMismatch        = errors.New("deal does not belong to client")
+	ErrTemplateNotFound          = errors.New("template_not_found")
+	ErrPDFConversionDisabled     = errors.New("pdf_conversion_disabled")
+	ErrPDFConversionFailed       = errors.New("pdf_conversion_failed")
 )
 
 type DealAlreadyExistsError struct {
@@ -20,20 +20,20 @@ var (
 // feedClientPatcher is the minimal interface the service needs to apply
 // an approved client-edit or client-delete request.
 type feedClientPatcher interface {
-	Patch(id int, updates map[string]any, userID, roleID int) (*models.Client, error)
-	Delete(id int, userID, roleID int) error
+	Patch(ctx context.Context, id int, updates map[string]any, userID, roleID int) (*models.Client, error)
+	Delete(ctx context.Context, id int, userID, roleID int) error
 }
 
 // feedLeadUpdater / feedDealUpdater are the minimal interfaces needed to apply
 // an approved lead/deal edit or delete request (executed with admin credentials).
 type feedLeadUpdater interface {
-	Update(lead *models.Leads, userID, roleID int) error
-	Delete(id int, userID, roleID int) error
+	Update(ctx context.Context, lead *models.Leads, userID, roleID int) error
+	Delete(ctx context.Context, id int, userID, roleID int) error
 }
 
 type feedDealUpdater interface {
-	Update(deal *models.Deals, userID, roleID int) error
-	Delete(id int, userID, roleID int) error
+	Update(ctx context.Context, deal *models.Deals, userID, roleID int) error
+	Delete(ctx context.Context, id int, userID, roleID int) error
 }
 
 // feedDocumentCreator applies an approved ОКК/HR document request. On approval
@@ -42,8 +42,8 @@ type feedDealUpdater interface {
 //   - DeleteDocument — для pending_delete_document (HR не может удалять документы
 //     напрямую — заявка уходит админу, который удаляет своими правами).
 type feedDocumentCreator interface {
-	CreateDocumentFromClient(clientID int, clientType string, dealID int, docType string, userID, roleID int, extra map[string]string) (*models.Document, error)
-	DeleteDocument(id int64, userID, roleID int) error
+	CreateDocumentFromClient(ctx context.Context, clientID int, clientType string, dealID int, docType string, userID, roleID int, extra map[string]string) (*models.Document, error)
+	DeleteDocument(ctx context.Context, id int64, userID, roleID int) error
 }
 
 // feedCreateDocumentPayload is the JSON shape stored for a
@@ -198,7 +198,7 @@ func (s *FeedEventService) applyEvent(ctx context.Context, e *models.FeedEvent,
 		if err := json.Unmarshal(e.Payload, &updates); err != nil {
 			return err
 		}
-		_, err := s.clientPatcher.Patch(*e.ResourceID, updates, reviewerID, authz.RoleSystemAdmin)
+		_, err := s.clientPatcher.Patch(ctx, *e.ResourceID, updates, reviewerID, authz.RoleSystemAdmin)
 		return err
 
 	case models.FeedEventTypePendingEditLead:
@@ -211,7 +211,7 @@ func (s *FeedEventService) applyEvent(ctx context.Context, e *models.FeedEvent,
 		}
 		lead.ID = *e.ResourceID
 		// Applied with admin credentials so scope/ownership checks pass.
-		return s.leadUpdater.Update(&lead, reviewerID, authz.RoleSystemAdmin)
+		return s.leadUpdater.Update(ctx, &lead, reviewerID, authz.RoleSystemAdmin)
 
 	case models.FeedEventTypePendingEditDeal:
 		if s.dealUpdater == nil || e.ResourceID == nil {
@@ -222,26 +222,26 @@ func (s *FeedEventService) applyEvent(ctx context.Context, e *models.FeedEvent,
 			return err
 		}
 		deal.ID = *e.ResourceID
-		return s.dealUpdater.Update(&deal, reviewerID, authz.RoleSystemAdmin)
+		return s.dealUpdater.Update(ctx, &deal, reviewerID, authz.RoleSystemAdmin)
 
 	case models.FeedEventTypePendingDeleteClient:
 		if s.clientPatcher == nil || e.ResourceID == nil {
 			return errors.New("cannot apply client delete: missing client patcher or resource_id")
 		}
 		// Applied with admin credentials so scope/ownership checks pass.
-		return s.clientPatcher.Delete(*e.ResourceID, reviewerID, authz.RoleSystemAdmin)
+		return s.clientPatcher.Delete(ctx, *e.ResourceID, reviewerID, authz.RoleSystemAdmin)
 
 	case models.FeedEventTypePendingDeleteLead:
 		if s.leadUpdater == nil || e.ResourceID == nil {
 			return errors.New("cannot apply lead delete: missing lead updater or resource_id")
 		}
-		return s.leadUpdater.Delete(*e.ResourceID, reviewerID, authz.RoleSystemAdmin)
+		return s.leadUpdater.Delete(ctx, *e.ResourceID, reviewerID, authz.RoleSystemAdmin)
 
 	case models.FeedEventTypePendingDeleteDeal:
 		if s.dealUpdater == nil || e.ResourceID == nil {
 			return errors.New("cannot apply deal delete: missing deal updater or resource_id")
 		}
-		return s.dealUpdater.Delete(*e.ResourceID, reviewerID, authz.RoleSystemAdmin)
+		return s.dealUpdater.Delete(ctx, *e.ResourceID, reviewerID, authz.RoleSystemAdmin)
 
 	case models.FeedEventTypePendingCreateDocument:
 		if s.docCreator == nil {
@@ -252,7 +252,7 @@ func (s *FeedEventService) applyEvent(ctx context.Context, e *models.FeedEvent,
 			return err
 		}
 		// Created with admin credentials so branch/scope checks pass.
-		_, err := s.docCreator.CreateDocumentFromClient(
+		_, err := s.docCreator.CreateDocumentFromClient(ctx,
 			p.ClientID, p.ClientType, p.DealID, p.DocType,
 			reviewerID, authz.RoleSystemAdmin, p.Extra,
 		)
@@ -263,7 +263,7 @@ func (s *FeedEventService) applyEvent(ctx context.Context, e *models.FeedEvent,
 			return errors.New("cannot apply document delete: missing document service or resource_id")
 		}
 		// Deleted with admin credentials (CanHardDeleteBusinessEntity).
-		return s.docCreator.DeleteDocument(int64(*e.ResourceID), reviewerID, authz.RoleSystemAdmin)
+		return s.docCreator.DeleteDocument(ctx, int64(*e.ResourceID), reviewerID, authz.RoleSystemAdmin)
 
 	default:
 		// For event types not wired to an apply action (create_lead, create_deal,
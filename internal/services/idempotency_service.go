@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"turcompany/internal/models"
+	"turcompany/internal/repositories"
+)
+
+const (
+	// DefaultIdempotencyTTL is how long a stored response is replayed for a
+	// repeated Idempotency-Key.
+	DefaultIdempotencyTTL = 24 * time.Hour
+	// DefaultIdempotencyInFlightTimeout is how long an unfinished request keeps
+	// its key before a retry may take it over (e.g. after a crash mid-request).
+	DefaultIdempotencyInFlightTimeout = 2 * time.Minute
+)
+
+// IdempotencyService backs the Idempotency-Key header on create endpoints:
+// the first request with a key reserves it, its successful response is
+// stored, and retries with the same key get that response back.
+type IdempotencyService struct {
+	repo            repositories.IdempotencyRepository
+	TTL             time.Duration
+	InFlightTimeout time.Duration
+	now             func() time.Time
+}
+
+func NewIdempotencyService(repo repositories.IdempotencyRepository, now func() time.Time) *IdempotencyService {
+	if now == nil {
+		now = time.Now
+	}
+	return &IdempotencyService{
+		repo:            repo,
+		TTL:             DefaultIdempotencyTTL,
+		InFlightTimeout: DefaultIdempotencyInFlightTimeout,
+		now:             now,
+	}
+}
+
+// Begin reserves key for the user within scope. When claimed is false the
+// returned record belongs to an earlier request: a zero ResponseStatus means
+// it is still running, otherwise its response should be replayed.
+func (s *IdempotencyService) Begin(ctx context.Context, userID int, scope, key string) (*models.IdempotencyKey, bool, error) {
+	if s == nil || s.repo == nil {
+		return nil, false, fmt.Errorf("idempotency repo is nil")
+	}
+	ttl := s.TTL
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyTTL
+	}
+	inFlight := s.InFlightTimeout
+	if inFlight <= 0 {
+		inFlight = DefaultIdempotencyInFlightTimeout
+	}
+	now := s.now()
+	return s.repo.Reserve(ctx, userID, scope, key, now, now.Add(-inFlight), now.Add(ttl))
+}
+
+// Complete stores the response of the request that owns the reservation.
+func (s *IdempotencyService) Complete(ctx context.Context, id int64, status int, body []byte) error {
+	return s.repo.Complete(ctx, id, status, body)
+}
+
+// Release drops an unfinished reservation so the client can retry with the
+// same key after a failed request.
+func (s *IdempotencyService) Release(ctx context.Context, id int64) error {
+	return s.repo.Release(ctx, id)
+}
+
+// PurgeExpired deletes keys past their TTL.
+func (s *IdempotencyService) PurgeExpired(ctx context.Context) (int64, error) {
+	return s.repo.DeleteExpired(ctx, s.now())
+}
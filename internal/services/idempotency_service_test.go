@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"turcompany/internal/middleware"
+	"turcompany/internal/models"
+)
+
+type memIdempotencyRepo struct {
+	mu     sync.Mutex
+	nextID int64
+	rows   map[string]*models.IdempotencyKey
+}
+
+func newMemIdempotencyRepo() *memIdempotencyRepo {
+	return &memIdempotencyRepo{rows: map[string]*models.IdempotencyKey{}}
+}
+
+func (r *memIdempotencyRepo) Reserve(_ context.Context, userID int, scope, key string, now, staleBefore, expiresAt time.Time) (*models.IdempotencyKey, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	k := fmt.Sprintf("%d|%s|%s", userID, scope, key)
+	if rec, ok := r.rows[k]; ok {
+		stale := rec.ResponseStatus == 0 && !rec.CreatedAt.After(staleBefore)
+		if rec.ExpiresAt.After(now) && !stale {
+			cp := *rec
+			return &cp, false, nil
+		}
+		rec.ResponseStatus, rec.ResponseBody = 0, nil
+		rec.CreatedAt, rec.ExpiresAt = now, expiresAt
+		cp := *rec
+		return &cp, true, nil
+	}
+	r.nextID++
+	rec := &models.IdempotencyKey{ID: r.nextID, UserID: userID, Scope: scope, Key: key, CreatedAt: now, ExpiresAt: expiresAt}
+	r.rows[k] = rec
+	cp := *rec
+	return &cp, true, nil
+}
+
+func (r *memIdempotencyRepo) Complete(_ context.Context, id int64, status int, body []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, rec := range r.rows {
+		if rec.ID == id {
+			rec.ResponseStatus, rec.ResponseBody = status, append([]byte(nil), body...)
+		}
+	}
+	return nil
+}
+
+func (r *memIdempotencyRepo) Release(_ context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for k, rec := range r.rows {
+		if rec.ID == id && rec.ResponseStatus == 0 {
+			delete(r.rows, k)
+		}
+	}
+	return nil
+}
+
+func (r *memIdempotencyRepo) DeleteExpired(_ context.Context, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int64
+	for k, rec := range r.rows {
+		if !rec.ExpiresAt.After(now) {
+			delete(r.rows, k)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// newIdempotentCreateRouter mounts a fake POST /tasks that inserts a row per
+// call, guarded by the idempotency middleware.
+func newIdempotentCreateRouter(svc *IdempotencyService, userID int, rows *[]int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Next()
+	})
+	r.POST("/tasks", middleware.Idempotency(svc), func(c *gin.Context) {
+		id := len(*rows) + 100
+		*rows = append(*rows, id)
+		c.JSON(http.StatusCreated, gin.H{"id": id})
+	})
+	return r
+}
+
+func postWithKey(t *testing.T, r *gin.Engine, key string) (*httptest.ResponseRecorder, int) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/tasks", nil)
+	if key != "" {
+		req.Header.Set(middleware.IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var body struct {
+		ID int `json:"id"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response %q: %v", w.Body.String(), err)
+	}
+	return w, body.ID
+}
+
+func TestIdempotency_SameKeyCreatesOneRowAndReturnsSameID(t *testing.T) {
+	svc := NewIdempotencyService(newMemIdempotencyRepo(), nil)
+	var rows []int
+	r := newIdempotentCreateRouter(svc, 7, &rows)
+
+	first, firstID := postWithKey(t, r, "retry-1")
+	second, secondID := postWithKey(t, r, "retry-1")
+
+	if len(rows) != 1 {
+		t.Fatalf("expected exactly one row, got %d", len(rows))
+	}
+	if firstID != secondID {
+		t.Fatalf("expected same id, got %d and %d", firstID, secondID)
+	}
+	if first.Code != http.StatusCreated || second.Code != http.StatusCreated {
+		t.Fatalf("expected 201 twice, got %d and %d", first.Code, second.Code)
+	}
+	if second.Header().Get(middleware.IdempotentReplayedHeader) != "true" {
+		t.Fatalf("expected replayed header on retry")
+	}
+}
+
+func TestIdempotency_WithoutKeyOrWithNewKeyCreatesAgain(t *testing.T) {
+	svc := NewIdempotencyService(newMemIdempotencyRepo(), nil)
+	var rows []int
+	r := newIdempotentCreateRouter(svc, 7, &rows)
+
+	postWithKey(t, r, "")
+	postWithKey(t, r, "")
+	postWithKey(t, r, "a")
+	postWithKey(t, r, "b")
+
+	if len(rows) != 4 {
+		t.Fatalf("expected 4 rows, got %d", len(rows))
+	}
+}
+
+func TestIdempotency_KeyExpiresAfterTTL(t *testing.T) {
+	now := time.Date(2026, time.May, 1, 12, 0, 0, 0, time.UTC)
+	svc := NewIdempotencyService(newMemIdempotencyRepo(), func() time.Time { return now })
+	var rows []int
+	r := newIdempotentCreateRouter(svc, 7, &rows)
+
+	_, firstID := postWithKey(t, r, "k")
+	now = now.Add(DefaultIdempotencyTTL + time.Second)
+	_, secondID := postWithKey(t, r, "k")
+
+	if len(rows) != 2 || firstID == secondID {
+		t.Fatalf("expected a new row after expiry, rows=%v ids=%d,%d", rows, firstID, secondID)
+	}
+}
@@ -11,6 +11,7 @@ package services
 //     real database.
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -29,12 +30,12 @@ type leadListRepoSpy struct {
 	calledAll    bool
 }
 
-func (r *leadListRepoSpy) ListByOwnerWithFilterAndArchiveScope(ownerID, _, _ int, _ repositories.LeadListFilter, _ repositories.ArchiveScope) ([]*models.Leads, error) {
+func (r *leadListRepoSpy) ListByOwnerWithFilterAndArchiveScope(_ context.Context, ownerID, _, _ int, _ repositories.LeadListFilter, _ repositories.ArchiveScope) ([]*models.Leads, error) {
 	r.calledOwner = &ownerID
 	return r.leads, nil
 }
 
-func (r *leadListRepoSpy) ListAllWithFilterAndArchiveScope(_, _ int, filter repositories.LeadListFilter, _ repositories.ArchiveScope) ([]*models.Leads, error) {
+func (r *leadListRepoSpy) ListAllWithFilterAndArchiveScope(_ context.Context, _, _ int, filter repositories.LeadListFilter, _ repositories.ArchiveScope) ([]*models.Leads, error) {
 	r.calledBranch = filter.BranchID
 	if filter.BranchID == nil {
 		r.calledAll = true
@@ -42,11 +43,11 @@ func (r *leadListRepoSpy) ListAllWithFilterAndArchiveScope(_, _ int, filter repo
 	return r.leads, nil
 }
 
-func (r *leadListRepoSpy) CountByOwnerWithFilterAndArchiveScope(_ int, _ repositories.LeadListFilter, _ repositories.ArchiveScope) (int, error) {
+func (r *leadListRepoSpy) CountByOwnerWithFilterAndArchiveScope(_ context.Context, _ int, _ repositories.LeadListFilter, _ repositories.ArchiveScope) (int, error) {
 	return len(r.leads), nil
 }
 
-func (r *leadListRepoSpy) CountAllWithFilterAndArchiveScope(_ repositories.LeadListFilter, _ repositories.ArchiveScope) (int, error) {
+func (r *leadListRepoSpy) CountAllWithFilterAndArchiveScope(_ context.Context, _ repositories.LeadListFilter, _ repositories.ArchiveScope) (int, error) {
 	return len(r.leads), nil
 }
 
@@ -58,12 +59,12 @@ type clientListRepoSpy struct {
 	calledAll    bool
 }
 
-func (r *clientListRepoSpy) ListByOwnerWithFilterAndArchiveScope(ownerID, _, _ int, _ repositories.ClientListFilter, _ repositories.ArchiveScope) ([]*models.Client, error) {
+func (r *clientListRepoSpy) ListByOwnerWithFilterAndArchiveScope(_ context.Context, ownerID, _, _ int, _ repositories.ClientListFilter, _ repositories.ArchiveScope) ([]*models.Client, error) {
 	r.calledOwner = &ownerID
 	return r.clients, nil
 }
 
-func (r *clientListRepoSpy) ListAllWithFilterAndArchiveScope(_, _ int, filter repositories.ClientListFilter, _ repositories.ArchiveScope) ([]*models.Client, error) {
+func (r *clientListRepoSpy) ListAllWithFilterAndArchiveScope(_ context.Context, _, _ int, filter repositories.ClientListFilter, _ repositories.ArchiveScope) ([]*models.Client, error) {
 	r.calledBranch = filter.BranchID
 	if filter.BranchID == nil {
 		r.calledAll = true
@@ -71,7 +72,7 @@ func (r *clientListRepoSpy) ListAllWithFilterAndArchiveScope(_, _ int, filter re
 	return r.clients, nil
 }
 
-func (r *clientListRepoSpy) CountWithFilterAndArchiveScope(_ *int, _ string, _ repositories.ClientListFilter, _ repositories.ArchiveScope) (int, error) {
+func (r *clientListRepoSpy) CountWithFilterAndArchiveScope(_ context.Context, _ *int, _ string, _ repositories.ClientListFilter, _ repositories.ArchiveScope) (int, error) {
 	return len(r.clients), nil
 }
 
@@ -88,7 +89,7 @@ func TestListLeadsForScope_PartnerRoutesToOwner(t *testing.T) {
 	spy := &leadListRepoSpy{}
 	scope := DataScope{Kind: ScopeKindOwn, UserID: partnerID}
 
-	leads, err := listLeadsForScope(spy, scope, 10, 0, repositories.LeadListFilter{}, activeOnly)
+	leads, err := listLeadsForScope(context.Background(), spy, scope, 10, 0, repositories.LeadListFilter{}, activeOnly)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -107,7 +108,7 @@ func TestListLeadsForScope_ManagementRoutesToAll(t *testing.T) {
 	spy := &leadListRepoSpy{}
 	scope := DataScope{Kind: ScopeKindAll}
 
-	_, err := listLeadsForScope(spy, scope, 10, 0, repositories.LeadListFilter{}, activeOnly)
+	_, err := listLeadsForScope(context.Background(), spy, scope, 10, 0, repositories.LeadListFilter{}, activeOnly)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -126,7 +127,7 @@ func TestListLeadsForScope_BranchScopePassesBranch(t *testing.T) {
 	spy := &leadListRepoSpy{}
 	scope := DataScope{Kind: ScopeKindBranch, BranchID: &branchID}
 
-	_, err := listLeadsForScope(spy, scope, 10, 0, repositories.LeadListFilter{}, activeOnly)
+	_, err := listLeadsForScope(context.Background(), spy, scope, 10, 0, repositories.LeadListFilter{}, activeOnly)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -158,7 +159,7 @@ func TestListClientsForScope_PartnerRoutesToOwner(t *testing.T) {
 	spy := &clientListRepoSpy{}
 	scope := DataScope{Kind: ScopeKindOwn, UserID: partnerID}
 
-	clients, err := listClientsForScope(spy, scope, 10, 0, repositories.ClientListFilter{}, activeOnly)
+	clients, err := listClientsForScope(context.Background(), spy, scope, 10, 0, repositories.ClientListFilter{}, activeOnly)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -177,7 +178,7 @@ func TestListClientsForScope_LegalRoutesToAll(t *testing.T) {
 	spy := &clientListRepoSpy{}
 	scope := DataScope{Kind: ScopeKindAll}
 
-	_, err := listClientsForScope(spy, scope, 10, 0, repositories.ClientListFilter{}, activeOnly)
+	_, err := listClientsForScope(context.Background(), spy, scope, 10, 0, repositories.ClientListFilter{}, activeOnly)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -196,7 +197,7 @@ func TestListClientsForScope_BranchScopePassesBranch(t *testing.T) {
 	spy := &clientListRepoSpy{}
 	scope := DataScope{Kind: ScopeKindBranch, BranchID: &branchID}
 
-	_, err := listClientsForScope(spy, scope, 10, 0, repositories.ClientListFilter{}, activeOnly)
+	_, err := listClientsForScope(context.Background(), spy, scope, 10, 0, repositories.ClientListFilter{}, activeOnly)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -216,7 +217,7 @@ func TestListClientsForScope_BranchScopePassesBranch(t *testing.T) {
 // TestLeadListForRole_HRForbidden verifies HR cannot list leads via ListForRole.
 func TestLeadListForRole_HRForbidden(t *testing.T) {
 	svc := &LeadService{} // nil Repo: function returns before repo access
-	_, err := svc.ListForRole(1, authz.RoleHR, 10, 0, activeOnly, repositories.LeadListFilter{})
+	_, err := svc.ListForRole(context.Background(), 1, authz.RoleHR, 10, 0, activeOnly, repositories.LeadListFilter{})
 	if !errors.Is(err, ErrForbidden) {
 		t.Errorf("hr ListForRole: want ErrForbidden, got %v", err)
 	}
@@ -226,7 +227,7 @@ func TestLeadListForRole_HRForbidden(t *testing.T) {
 // clients.view but NOT leads.view in the permission matrix).
 func TestLeadListForRole_LegalForbidden(t *testing.T) {
 	svc := &LeadService{}
-	_, err := svc.ListForRole(1, authz.RoleLegal, 10, 0, activeOnly, repositories.LeadListFilter{})
+	_, err := svc.ListForRole(context.Background(), 1, authz.RoleLegal, 10, 0, activeOnly, repositories.LeadListFilter{})
 	if !errors.Is(err, ErrForbidden) {
 		t.Errorf("legal ListForRole: want ErrForbidden, got %v", err)
 	}
@@ -260,7 +261,7 @@ func TestLeadListForRole_SalesResolvesToBranch(t *testing.T) {
 // TestLeadListForRole_UnknownRoleForbidden verifies an unknown role cannot list leads.
 func TestLeadListForRole_UnknownRoleForbidden(t *testing.T) {
 	svc := &LeadService{}
-	_, err := svc.ListForRole(1, 999, 10, 0, activeOnly, repositories.LeadListFilter{})
+	_, err := svc.ListForRole(context.Background(), 1, 999, 10, 0, activeOnly, repositories.LeadListFilter{})
 	if !errors.Is(err, ErrForbidden) {
 		t.Errorf("unknown role ListForRole: want ErrForbidden, got %v", err)
 	}
@@ -275,7 +276,7 @@ func TestLeadListForRole_PartnerNotForbidden(t *testing.T) {
 	spy := &leadListRepoSpy{leads: []*models.Leads{{ID: 1, OwnerID: partnerID}}}
 	// Call the helper directly with the scope that ListForRole would resolve for partner.
 	scope := DataScope{Kind: ScopeKindOwn, UserID: partnerID}
-	leads, err := listLeadsForScope(spy, scope, 10, 0, repositories.LeadListFilter{}, activeOnly)
+	leads, err := listLeadsForScope(context.Background(), spy, scope, 10, 0, repositories.LeadListFilter{}, activeOnly)
 	if err != nil {
 		t.Fatalf("partner must NOT get error from listLeadsForScope, got %v", err)
 	}
@@ -292,7 +293,7 @@ func TestLeadListForRole_PartnerNotForbidden(t *testing.T) {
 // TestClientListForRole_HRForbidden verifies HR cannot list clients via ListForRole.
 func TestClientListForRole_HRForbidden(t *testing.T) {
 	svc := &ClientService{}
-	_, err := svc.ListForRole(1, authz.RoleHR, 10, 0, repositories.ClientListFilter{}, activeOnly)
+	_, err := svc.ListForRole(context.Background(), 1, authz.RoleHR, 10, 0, repositories.ClientListFilter{}, activeOnly)
 	if !errors.Is(err, ErrForbidden) {
 		t.Errorf("hr client ListForRole: want ErrForbidden, got %v", err)
 	}
@@ -310,7 +311,7 @@ func TestClientListForRole_SalesSeesAll(t *testing.T) {
 		t.Fatalf("sales: expected ScopeKindAll (общая база), got %v", scope.Kind)
 	}
 	spy := &clientListRepoSpy{clients: []*models.Client{{ID: 1, OwnerID: 99}}}
-	clients, err := listClientsForScope(spy, scope, 10, 0, repositories.ClientListFilter{}, activeOnly)
+	clients, err := listClientsForScope(context.Background(), spy, scope, 10, 0, repositories.ClientListFilter{}, activeOnly)
 	if err != nil {
 		t.Fatalf("sales listClientsForScope: unexpected error %v", err)
 	}
@@ -325,7 +326,7 @@ func TestClientListForRole_SalesSeesAll(t *testing.T) {
 // TestClientListForRole_UnknownRoleForbidden verifies an unknown role cannot list clients.
 func TestClientListForRole_UnknownRoleForbidden(t *testing.T) {
 	svc := &ClientService{}
-	_, err := svc.ListForRole(1, 999, 10, 0, repositories.ClientListFilter{}, activeOnly)
+	_, err := svc.ListForRole(context.Background(), 1, 999, 10, 0, repositories.ClientListFilter{}, activeOnly)
 	if !errors.Is(err, ErrForbidden) {
 		t.Errorf("unknown role client ListForRole: want ErrForbidden, got %v", err)
 	}
@@ -338,7 +339,7 @@ func TestClientListForRole_LegalNotForbidden(t *testing.T) {
 	spy := &clientListRepoSpy{clients: []*models.Client{{ID: 1, OwnerID: 99}}}
 	// legal → ScopeKindAll, so listClientsForScope calls ListAll.
 	scope := DataScope{Kind: ScopeKindAll}
-	clients, err := listClientsForScope(spy, scope, 10, 0, repositories.ClientListFilter{}, activeOnly)
+	clients, err := listClientsForScope(context.Background(), spy, scope, 10, 0, repositories.ClientListFilter{}, activeOnly)
 	if err != nil {
 		t.Fatalf("legal must NOT get error from listClientsForScope, got %v", err)
 	}
@@ -356,7 +357,7 @@ func TestClientListForRole_PartnerNotForbidden(t *testing.T) {
 	const partnerID = 70
 	spy := &clientListRepoSpy{clients: []*models.Client{{ID: 5, OwnerID: partnerID}}}
 	scope := DataScope{Kind: ScopeKindOwn, UserID: partnerID}
-	clients, err := listClientsForScope(spy, scope, 10, 0, repositories.ClientListFilter{}, activeOnly)
+	clients, err := listClientsForScope(context.Background(), spy, scope, 10, 0, repositories.ClientListFilter{}, activeOnly)
 	if err != nil {
 		t.Fatalf("partner must NOT get error from listClientsForScope, got %v", err)
 	}
@@ -376,7 +377,7 @@ func TestCountLeadsForScope_PartnerUsesOwnerCount(t *testing.T) {
 	spy := &leadListRepoSpy{leads: make([]*models.Leads, 3)}
 	scope := DataScope{Kind: ScopeKindOwn, UserID: partnerID}
 
-	total, err := countLeadsForScope(spy, scope, repositories.LeadListFilter{}, activeOnly)
+	total, err := countLeadsForScope(context.Background(), spy, scope, repositories.LeadListFilter{}, activeOnly)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -390,7 +391,7 @@ func TestCountClientsForScope_LegalUsesAllCount(t *testing.T) {
 	spy := &clientListRepoSpy{clients: make([]*models.Client, 5)}
 	scope := DataScope{Kind: ScopeKindAll}
 
-	total, err := countClientsForScope(spy, scope, "", repositories.ClientListFilter{}, activeOnly)
+	total, err := countClientsForScope(context.Background(), spy, scope, "", repositories.ClientListFilter{}, activeOnly)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
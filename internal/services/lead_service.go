@@ -16,6 +16,8 @@ type LeadService struct {
 	DealRepo  *repositories.DealRepository
 	ClientSvc *ClientService
 	UserRepo  repositories.UserRepository
+
+	currencies currencySet
 }
 
 func NewLeadService(leadRepo *repositories.LeadRepository, dealRepo *repositories.DealRepository, clientRepo *repositories.ClientRepository, userRepo ...repositories.UserRepository) *LeadService {
@@ -33,7 +35,13 @@ func NewLeadService(leadRepo *repositories.LeadRepository, dealRepo *repositorie
 	return svc
 }
 
-func (s *LeadService) Create(lead *models.Leads, userID, roleID int) (int64, error) {
+// SetAllowedCurrencies replaces the currency whitelist applied when a lead
+// is converted; an empty list keeps DefaultDealCurrencies.
+func (s *LeadService) SetAllowedCurrencies(codes []string) {
+	s.currencies = newCurrencySet(codes)
+}
+
+func (s *LeadService) Create(ctx context.Context, lead *models.Leads, userID, roleID int) (int64, error) {
 	if authz.IsReadOnly(roleID) {
 		return 0, ErrReadOnly
 	}
@@ -63,19 +71,44 @@ func (s *LeadService) Create(lead *models.Leads, userID, roleID int) (int64, err
 	if lead.Status == "" {
 		lead.Status = "new"
 	}
-	return s.Repo.Create(lead)
+	source, err := normalizeLeadSource(lead.Source)
+	if err != nil {
+		return 0, err
+	}
+	lead.Source = source
+	if lead.Score != nil && !validLeadScore(*lead.Score) {
+		return 0, ErrInvalidLeadScore
+	}
+	return s.Repo.Create(ctx, lead)
+}
+
+func validLeadScore(score int) bool {
+	return score >= models.LeadScoreMin && score <= models.LeadScoreMax
 }
 
-func (s *LeadService) Update(lead *models.Leads, userID, roleID int) error {
+// normalizeLeadSource lower-cases and validates a lead source; an empty one
+// is "unknown".
+func normalizeLeadSource(source string) (string, error) {
+	source = strings.ToLower(strings.TrimSpace(source))
+	if source == "" {
+		source = models.LeadSourceUnknown
+	}
+	if !models.IsKnownLeadSource(source) {
+		return "", ErrInvalidLeadSource
+	}
+	return source, nil
+}
+
+func (s *LeadService) Update(ctx context.Context, lead *models.Leads, userID, roleID int) error {
 	if authz.IsReadOnly(roleID) {
 		return ErrReadOnly
 	}
-	current, err := s.Repo.GetByID(lead.ID)
+	current, err := s.Repo.GetByID(ctx, lead.ID)
 	if err != nil {
 		return err
 	}
 	if current == nil {
-		return errors.New("lead not found")
+		return ErrLeadNotFound
 	}
 	scope, err := resolveLeadScope(userID, roleID, s.UserRepo)
 	if err != nil {
@@ -105,26 +138,37 @@ func (s *LeadService) Update(lead *models.Leads, userID, roleID int) error {
 	if lead.Description == "" {
 		lead.Description = current.Description
 	}
-	return s.Repo.Update(lead)
+	// an omitted source keeps the stored one; legacy values are not re-validated
+	if strings.TrimSpace(lead.Source) == "" {
+		lead.Source = current.Source
+	} else if lead.Source, err = normalizeLeadSource(lead.Source); err != nil {
+		return err
+	}
+	if lead.Score == nil {
+		lead.Score = current.Score
+	} else if !validLeadScore(*lead.Score) {
+		return ErrInvalidLeadScore
+	}
+	return s.Repo.Update(ctx, lead)
 }
 
-func (s *LeadService) ListAll(limit, offset int) ([]*models.Leads, error) {
-	return s.Repo.ListAllWithFilterAndArchiveScope(limit, offset, repositories.LeadListFilter{}, repositories.ArchiveScopeActiveOnly)
+func (s *LeadService) ListAll(ctx context.Context, limit, offset int) ([]*models.Leads, error) {
+	return s.Repo.ListAllWithFilterAndArchiveScope(ctx, limit, offset, repositories.LeadListFilter{}, repositories.ArchiveScopeActiveOnly)
 }
 
-func (s *LeadService) ListAllWithArchiveScope(limit, offset int, scope repositories.ArchiveScope) ([]*models.Leads, error) {
-	return s.Repo.ListAllWithFilterAndArchiveScope(limit, offset, repositories.LeadListFilter{}, scope)
+func (s *LeadService) ListAllWithArchiveScope(ctx context.Context, limit, offset int, scope repositories.ArchiveScope) ([]*models.Leads, error) {
+	return s.Repo.ListAllWithFilterAndArchiveScope(ctx, limit, offset, repositories.LeadListFilter{}, scope)
 }
 
-func (s *LeadService) ListMy(ownerID, limit, offset int) ([]*models.Leads, error) {
-	return s.Repo.ListByOwnerWithFilterAndArchiveScope(ownerID, limit, offset, repositories.LeadListFilter{}, repositories.ArchiveScopeActiveOnly)
+func (s *LeadService) ListMy(ctx context.Context, ownerID, limit, offset int) ([]*models.Leads, error) {
+	return s.Repo.ListByOwnerWithFilterAndArchiveScope(ctx, ownerID, limit, offset, repositories.LeadListFilter{}, repositories.ArchiveScopeActiveOnly)
 }
 
-func (s *LeadService) ListMyWithArchiveScope(ownerID, limit, offset int, scope repositories.ArchiveScope) ([]*models.Leads, error) {
-	return s.Repo.ListByOwnerWithFilterAndArchiveScope(ownerID, limit, offset, repositories.LeadListFilter{}, scope)
+func (s *LeadService) ListMyWithArchiveScope(ctx context.Context, ownerID, limit, offset int, scope repositories.ArchiveScope) ([]*models.Leads, error) {
+	return s.Repo.ListByOwnerWithFilterAndArchiveScope(ctx, ownerID, limit, offset, repositories.LeadListFilter{}, scope)
 }
 
-func (s *LeadService) ListForRole(userID, roleID, limit, offset int, archiveScope repositories.ArchiveScope, filter repositories.LeadListFilter) ([]*models.Leads, error) {
+func (s *LeadService) ListForRole(ctx context.Context, userID, roleID, limit, offset int, archiveScope repositories.ArchiveScope, filter repositories.LeadListFilter) ([]*models.Leads, error) {
 	// sales/visa/partner resolve to ScopeKindBranch (свой отдел+филиал по воронкам)
 	// via resolveLeadScope — no hard guard here. Was previously blocked, which
 	// forced sales onto /leads/my (ListAll = все лиды), leaking every department.
@@ -132,17 +176,17 @@ func (s *LeadService) ListForRole(userID, roleID, limit, offset int, archiveScop
 	if err != nil {
 		return nil, err
 	}
-	return listLeadsForScope(s.Repo, dataScope, limit, offset, filter, archiveScope)
+	return listLeadsForScope(ctx, s.Repo, dataScope, limit, offset, filter, archiveScope)
 }
 
-func (s *LeadService) ListMyWithFilterAndArchiveScope(ownerID int, limit, offset int, scope repositories.ArchiveScope, filter repositories.LeadListFilter) ([]*models.Leads, error) {
+func (s *LeadService) ListMyWithFilterAndArchiveScope(ctx context.Context, ownerID int, limit, offset int, scope repositories.ArchiveScope, filter repositories.LeadListFilter) ([]*models.Leads, error) {
 	// "Мои" = лиды, где текущий пользователь является владельцем (owner_id).
 	// Ранее ошибочно вызывался ListAll → возвращались ВСЕ лиды компании.
-	return s.Repo.ListByOwnerWithFilterAndArchiveScope(ownerID, limit, offset, filter, scope)
+	return s.Repo.ListByOwnerWithFilterAndArchiveScope(ctx, ownerID, limit, offset, filter, scope)
 }
 
-func (s *LeadService) ListForRoleWithTotal(userID, roleID, limit, offset int, archiveScope repositories.ArchiveScope, filter repositories.LeadListFilter) ([]*models.Leads, int, error) {
-	items, err := s.ListForRole(userID, roleID, limit, offset, archiveScope, filter)
+func (s *LeadService) ListForRoleWithTotal(ctx context.Context, userID, roleID, limit, offset int, archiveScope repositories.ArchiveScope, filter repositories.LeadListFilter) ([]*models.Leads, int, error) {
+	items, err := s.ListForRole(ctx, userID, roleID, limit, offset, archiveScope, filter)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -150,28 +194,28 @@ func (s *LeadService) ListForRoleWithTotal(userID, roleID, limit, offset int, ar
 	if err != nil {
 		return nil, 0, err
 	}
-	total, err := countLeadsForScope(s.Repo, dataScope, filter, archiveScope)
+	total, err := countLeadsForScope(ctx, s.Repo, dataScope, filter, archiveScope)
 	if err != nil {
 		return nil, 0, err
 	}
 	return items, total, nil
 }
 
-func (s *LeadService) ListMyWithFilterAndArchiveScopeAndTotal(ownerID int, limit, offset int, scope repositories.ArchiveScope, filter repositories.LeadListFilter) ([]*models.Leads, int, error) {
+func (s *LeadService) ListMyWithFilterAndArchiveScopeAndTotal(ctx context.Context, ownerID int, limit, offset int, scope repositories.ArchiveScope, filter repositories.LeadListFilter) ([]*models.Leads, int, error) {
 	// "Мои" = лиды текущего владельца. Ранее возвращало ВСЕ лиды (ListAll).
-	items, err := s.Repo.ListByOwnerWithFilterAndArchiveScope(ownerID, limit, offset, filter, scope)
+	items, err := s.Repo.ListByOwnerWithFilterAndArchiveScope(ctx, ownerID, limit, offset, filter, scope)
 	if err != nil {
 		return nil, 0, err
 	}
-	total, err := s.Repo.CountByOwnerWithFilterAndArchiveScope(ownerID, filter, scope)
+	total, err := s.Repo.CountByOwnerWithFilterAndArchiveScope(ctx, ownerID, filter, scope)
 	if err != nil {
 		return nil, 0, err
 	}
 	return items, total, nil
 }
 
-func (s *LeadService) GetByID(id int, userID, roleID int) (*models.Leads, error) {
-	lead, err := s.Repo.GetByID(id)
+func (s *LeadService) GetByID(ctx context.Context, id int, userID, roleID int) (*models.Leads, error) {
+	lead, err := s.Repo.GetByID(ctx, id)
 	if err != nil || lead == nil {
 		return lead, err
 	}
@@ -185,8 +229,8 @@ func (s *LeadService) GetByID(id int, userID, roleID int) (*models.Leads, error)
 	return lead, nil
 }
 
-func (s *LeadService) GetByIDWithArchiveScope(id int, userID, roleID int, scope repositories.ArchiveScope) (*models.Leads, error) {
-	lead, err := s.Repo.GetByIDWithArchiveScope(id, scope)
+func (s *LeadService) GetByIDWithArchiveScope(ctx context.Context, id int, userID, roleID int, scope repositories.ArchiveScope) (*models.Leads, error) {
+	lead, err := s.Repo.GetByIDWithArchiveScope(ctx, id, scope)
 	if err != nil || lead == nil {
 		return lead, err
 	}
@@ -200,16 +244,16 @@ func (s *LeadService) GetByIDWithArchiveScope(id int, userID, roleID int, scope
 	return lead, nil
 }
 
-func (s *LeadService) Delete(id int, userID, roleID int) error {
+func (s *LeadService) Delete(ctx context.Context, id int, userID, roleID int) error {
 	if !authz.CanHardDeleteBusinessEntity(roleID) {
 		return ErrForbidden
 	}
-	lead, err := s.Repo.GetByIDWithArchiveScope(id, repositories.ArchiveScopeAll)
+	lead, err := s.Repo.GetByIDWithArchiveScope(ctx, id, repositories.ArchiveScopeAll)
 	if err != nil {
 		return err
 	}
 	if lead == nil {
-		return errors.New("lead not found")
+		return ErrLeadNotFound
 	}
 	scope, err := resolveLeadScope(userID, roleID, s.UserRepo)
 	if err != nil {
@@ -218,7 +262,7 @@ func (s *LeadService) Delete(id int, userID, roleID int) error {
 	if !leadMatchesScope(scope, lead) {
 		return ErrForbidden
 	}
-	return s.Repo.Delete(id)
+	return s.Repo.Delete(ctx, id)
 }
 
 // buildConvertedDeal assembles the Deal produced when converting a lead.
@@ -239,15 +283,16 @@ func buildConvertedDeal(leadID, clientID int, clientType string, ownerID int, am
 	}
 }
 
-func (s *LeadService) ConvertLeadToDeal(leadID int, amount float64, currency string, ownerID, userID, roleID int, clientID int, clientType string) (*models.Deals, error) {
+func (s *LeadService) ConvertLeadToDeal(ctx context.Context, leadID int, amount float64, currency string, ownerID, userID, roleID int, clientID int, clientType string) (*models.Deals, error) {
 	if authz.IsReadOnly(roleID) {
 		return nil, ErrReadOnly
 	}
 	if amount <= 0 {
 		return nil, ErrAmountInvalid
 	}
-	if strings.TrimSpace(currency) == "" {
-		return nil, errors.New("currency is required")
+	currency, err := s.currencies.normalize(currency)
+	if err != nil {
+		return nil, err
 	}
 	if clientID <= 0 {
 		return nil, ErrClientIDRequired
@@ -256,24 +301,14 @@ func (s *LeadService) ConvertLeadToDeal(leadID int, amount float64, currency str
 	if err != nil {
 		return nil, err
 	}
-	lead, err := s.Repo.GetByID(leadID)
-	if err != nil || lead == nil {
-		return nil, errors.New("lead not found")
-	}
-	scope, err := resolveLeadScope(userID, roleID, s.UserRepo)
+	lead, err := s.loadLeadForConversion(ctx, leadID, userID, roleID)
 	if err != nil {
 		return nil, err
 	}
-	if roleID == authz.RoleSales && lead.OwnerID != userID {
-		return nil, ErrForbidden
-	}
-	if !leadMatchesScope(scope, lead) {
-		return nil, ErrForbidden
-	}
 	if s.ClientSvc == nil {
 		return nil, errors.New("client repository not configured")
 	}
-	client, err := s.ClientSvc.GetByID(clientID, userID, roleID)
+	client, err := s.ClientSvc.GetByID(ctx, clientID, userID, roleID)
 	if err != nil {
 		return nil, err
 	}
@@ -284,7 +319,36 @@ func (s *LeadService) ConvertLeadToDeal(leadID int, amount float64, currency str
 		return nil, ErrClientTypeMismatch
 	}
 	deal := buildConvertedDeal(leadID, clientID, normalizedClientType, ownerID, amount, currency, lead, time.Now())
-	converted, err := s.Repo.ConvertToDeal(context.Background(), leadID, deal, client)
+	return s.convertInTx(ctx, leadID, deal, client)
+}
+
+// loadLeadForConversion fetches the lead and checks the caller may convert it.
+func (s *LeadService) loadLeadForConversion(ctx context.Context, leadID, userID, roleID int) (*models.Leads, error) {
+	lead, err := s.Repo.GetByID(ctx, leadID)
+	if err != nil {
+		return nil, err
+	}
+	if lead == nil {
+		return nil, ErrLeadNotFound
+	}
+	scope, err := resolveLeadScope(userID, roleID, s.UserRepo)
+	if err != nil {
+		return nil, err
+	}
+	if roleID == authz.RoleSales && lead.OwnerID != userID {
+		return nil, ErrForbidden
+	}
+	if !leadMatchesScope(scope, lead) {
+		return nil, ErrForbidden
+	}
+	return lead, nil
+}
+
+// convertInTx runs the conversion as one transaction: creating the client
+// (when client.ID is 0), inserting the deal and marking the lead converted
+// either all commit or all roll back.
+func (s *LeadService) convertInTx(ctx context.Context, leadID int, deal *models.Deals, client *models.Client) (*models.Deals, error) {
+	converted, err := s.Repo.ConvertToDeal(ctx, leadID, deal, client)
 	if err != nil {
 		if errors.Is(err, repositories.ErrClientNotFound) {
 			return nil, ErrClientNotFound
@@ -292,20 +356,33 @@ func (s *LeadService) ConvertLeadToDeal(leadID int, amount float64, currency str
 		if errors.Is(err, repositories.ErrDealAlreadyExists) {
 			return converted, ErrDealAlreadyExists
 		}
+		if errors.Is(err, repositories.ErrLeadNotConvertible) {
+			return nil, ErrLeadNotConvertible
+		}
+		if errors.Is(err, repositories.ErrLeadNotFound) {
+			return nil, ErrLeadNotFound
+		}
+		if errors.Is(err, repositories.ErrClientTypeRequired) {
+			return nil, ErrClientTypeRequired
+		}
+		if errors.Is(err, repositories.ErrClientTypeMismatch) {
+			return nil, ErrClientTypeMismatch
+		}
 		return nil, err
 	}
 	return converted, nil
 }
 
-func (s *LeadService) ConvertLeadToDealWithClientData(leadID int, amount float64, currency string, ownerID, userID, roleID int, clientData *models.Client) (*models.Deals, error) {
+func (s *LeadService) ConvertLeadToDealWithClientData(ctx context.Context, leadID int, amount float64, currency string, ownerID, userID, roleID int, clientData *models.Client) (*models.Deals, error) {
 	if authz.IsReadOnly(roleID) {
 		return nil, ErrReadOnly
 	}
 	if amount <= 0 {
 		return nil, ErrAmountInvalid
 	}
-	if strings.TrimSpace(currency) == "" {
-		return nil, errors.New("currency is required")
+	currency, err := s.currencies.normalize(currency)
+	if err != nil {
+		return nil, err
 	}
 	if clientData == nil {
 		return nil, errors.New("client data is required")
@@ -321,26 +398,36 @@ func (s *LeadService) ConvertLeadToDealWithClientData(leadID int, amount float64
 	if s.ClientSvc == nil {
 		return nil, errors.New("client repository not configured")
 	}
-	client, err := s.ClientSvc.GetOrCreateByBIN(clientData.BinIin, clientData, userID, roleID)
+	client, isNew, _, err := s.ClientSvc.findOrPrepareByBIN(ctx, clientData.BinIin, clientData, userID, roleID)
 	if err != nil {
 		return nil, err
 	}
 	if client == nil {
 		return nil, ErrClientNotFound
 	}
-	return s.ConvertLeadToDeal(leadID, amount, currency, ownerID, userID, roleID, client.ID, clientData.ClientType)
+	if !isNew {
+		return s.ConvertLeadToDeal(ctx, leadID, amount, currency, ownerID, userID, roleID, client.ID, clientData.ClientType)
+	}
+	// The client doesn't exist yet: it is inserted by the conversion
+	// transaction, so a failure anywhere leaves no orphaned client.
+	lead, err := s.loadLeadForConversion(ctx, leadID, userID, roleID)
+	if err != nil {
+		return nil, err
+	}
+	deal := buildConvertedDeal(leadID, 0, clientType, ownerID, amount, currency, lead, time.Now())
+	return s.convertInTx(ctx, leadID, deal, client)
 }
 
-func (s *LeadService) UpdateStatus(id int, to string, userID, roleID int) error {
+func (s *LeadService) UpdateStatus(ctx context.Context, id int, to string, userID, roleID int) error {
 	if authz.IsReadOnly(roleID) {
 		return ErrReadOnly
 	}
-	lead, err := s.Repo.GetByID(id)
+	lead, err := s.Repo.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
 	if lead == nil {
-		return errors.New("lead not found")
+		return ErrLeadNotFound
 	}
 	scope, err := resolveLeadScope(userID, roleID, s.UserRepo)
 	if err != nil {
@@ -355,14 +442,42 @@ func (s *LeadService) UpdateStatus(id int, to string, userID, roleID int) error
 	if !canTransition(lead.Status, to, LeadTransitions) {
 		return errors.New("invalid status transition")
 	}
-	return s.Repo.UpdateStatus(id, to)
+	return s.Repo.UpdateStatus(ctx, id, to)
+}
+
+// SetScore rates a lead from 0 (cold) to 100 (hot). Access is as for Update.
+func (s *LeadService) SetScore(ctx context.Context, id, score, userID, roleID int) error {
+	if authz.IsReadOnly(roleID) {
+		return ErrReadOnly
+	}
+	if !validLeadScore(score) {
+		return ErrInvalidLeadScore
+	}
+	lead, err := s.Repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if lead == nil {
+		return ErrLeadNotFound
+	}
+	scope, err := resolveLeadScope(userID, roleID, s.UserRepo)
+	if err != nil {
+		return err
+	}
+	if roleID == authz.RoleSales && lead.OwnerID != userID {
+		return ErrForbidden
+	}
+	if !leadMatchesScope(scope, lead) {
+		return ErrForbidden
+	}
+	return s.Repo.UpdateScore(ctx, id, score)
 }
 
-func (s *LeadService) ArchiveLead(id, userID, roleID int, reason string) error {
+func (s *LeadService) ArchiveLead(ctx context.Context, id, userID, roleID int, reason string) error {
 	if !authz.CanArchiveBusinessEntity(roleID) {
 		return ErrForbidden
 	}
-	lead, err := s.Repo.GetByIDWithArchiveScope(id, repositories.ArchiveScopeAll)
+	lead, err := s.Repo.GetByIDWithArchiveScope(ctx, id, repositories.ArchiveScopeAll)
 	if err != nil {
 		return err
 	}
@@ -382,14 +497,14 @@ func (s *LeadService) ArchiveLead(id, userID, roleID int, reason string) error {
 	if lead.IsArchived {
 		return nil
 	}
-	return s.Repo.Archive(id, userID, reason)
+	return s.Repo.Archive(ctx, id, userID, reason)
 }
 
-func (s *LeadService) UnarchiveLead(id, userID, roleID int) error {
+func (s *LeadService) UnarchiveLead(ctx context.Context, id, userID, roleID int) error {
 	if !authz.CanArchiveBusinessEntity(roleID) {
 		return ErrForbidden
 	}
-	lead, err := s.Repo.GetByIDWithArchiveScope(id, repositories.ArchiveScopeAll)
+	lead, err := s.Repo.GetByIDWithArchiveScope(ctx, id, repositories.ArchiveScopeAll)
 	if err != nil {
 		return err
 	}
@@ -409,12 +524,12 @@ func (s *LeadService) UnarchiveLead(id, userID, roleID int) error {
 	if !lead.IsArchived {
 		return ErrNotArchived
 	}
-	return s.Repo.Unarchive(id)
+	return s.Repo.Unarchive(ctx, id)
 }
 
-func (s *LeadService) AssignOwner(id, assigneeID, userID, roleID int) error {
+func (s *LeadService) AssignOwner(ctx context.Context, id, assigneeID, userID, roleID int) error {
 	if roleID != authz.RoleManagement && roleID != authz.RoleSystemAdmin {
 		return ErrForbidden
 	}
-	return s.Repo.UpdateOwner(id, assigneeID)
+	return s.Repo.UpdateOwner(ctx, id, assigneeID)
 }
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"turcompany/internal/authz"
+)
+
+func TestNormalizeLeadSource(t *testing.T) {
+	tests := []struct {
+		source, want string
+		err          error
+	}{
+		{source: " Referral ", want: "referral"},
+		{source: "cold_call", want: "cold_call"},
+		{source: "", want: "unknown"},
+		{source: "  ", want: "unknown"},
+		{source: "tiktok", err: ErrInvalidLeadSource},
+	}
+	for _, tc := range tests {
+		got, err := normalizeLeadSource(tc.source)
+		if !errors.Is(err, tc.err) || got != tc.want {
+			t.Fatalf("normalizeLeadSource(%q) = %q, %v; want %q, %v", tc.source, got, err, tc.want, tc.err)
+		}
+	}
+}
+
+func TestLeadService_SetScore_RejectsOutOfRange(t *testing.T) {
+	svc := &LeadService{}
+	for _, score := range []int{-1, 101, 1000} {
+		if err := svc.SetScore(context.Background(), 1, score, 1, authz.RoleManagement); !errors.Is(err, ErrInvalidLeadScore) {
+			t.Fatalf("score %d: expected ErrInvalidLeadScore, got %v", score, err)
+		}
+	}
+	for _, score := range []int{0, 55, 100} {
+		if !validLeadScore(score) {
+			t.Fatalf("score %d should be accepted", score)
+		}
+	}
+}
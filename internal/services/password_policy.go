@@ -0,0 +1,63 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrWeakPassword is matched by every PasswordPolicyError.
+var ErrWeakPassword = errors.New("weak password")
+
+// PasswordPolicyError carries the user-facing reason a password was rejected.
+type PasswordPolicyError struct {
+	Reason string
+}
+
+func (e *PasswordPolicyError) Error() string { return e.Reason }
+func (e *PasswordPolicyError) Unwrap() error { return ErrWeakPassword }
+
+// PasswordPolicy is applied wherever a user picks a password: registration,
+// reset and self-service change. Character classes are lowercase, uppercase,
+// digits and everything else.
+type PasswordPolicy struct {
+	MinLength  int
+	MinClasses int
+}
+
+var DefaultPasswordPolicy = PasswordPolicy{MinLength: 8, MinClasses: 3}
+
+func ValidatePassword(password string) error {
+	return DefaultPasswordPolicy.Validate(password)
+}
+
+func (p PasswordPolicy) Validate(password string) error {
+	if n := utf8.RuneCountInString(password); n < p.MinLength {
+		return &PasswordPolicyError{Reason: fmt.Sprintf("password must be at least %d characters", p.MinLength)}
+	}
+	var lower, upper, digit, other bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsSpace(r):
+		default:
+			other = true
+		}
+	}
+	classes := 0
+	for _, ok := range []bool{lower, upper, digit, other} {
+		if ok {
+			classes++
+		}
+	}
+	if classes < p.MinClasses {
+		return &PasswordPolicyError{Reason: fmt.Sprintf("password must contain at least %d of: lowercase letters, uppercase letters, digits, symbols", p.MinClasses)}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidatePasswordRejectsWeakPasswords(t *testing.T) {
+	cases := map[string]string{
+		"Ab1":         "at least 8 characters",
+		"password":    "at least 3 of",
+		"password123": "at least 3 of",
+		"PASSWORD!!":  "at least 3 of",
+	}
+	for pw, want := range cases {
+		err := ValidatePassword(pw)
+		if !errors.Is(err, ErrWeakPassword) {
+			t.Fatalf("%q: expected ErrWeakPassword, got %v", pw, err)
+		}
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("%q: expected message containing %q, got %q", pw, want, err.Error())
+		}
+	}
+}
+
+func TestValidatePasswordAcceptsStrongPasswords(t *testing.T) {
+	for _, pw := range []string{"Passw0rd", "correct-horse-42", "Пароль2026!"} {
+		if err := ValidatePassword(pw); err != nil {
+			t.Fatalf("%q: unexpected error %v", pw, err)
+		}
+	}
+}
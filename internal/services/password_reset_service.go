@@ -19,9 +19,17 @@ var (
 	ErrResetTokenUsed     = errors.New("reset token already used")
 )
 
+const (
+	// defaultPasswordResetTTL applies when NewPasswordResetService gets ttl <= 0.
+	defaultPasswordResetTTL = time.Hour
+	// passwordResetThrottle is the per-account window in which a repeated
+	// forgot-password request reuses the pending token and sends nothing.
+	passwordResetThrottle = 5 * time.Minute
+)
+
 type PasswordResetService interface {
-	RequestReset(email string) error
-	ResetPassword(token, newPassword string) error
+	RequestReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
 }
 
 type passwordResetService struct {
@@ -31,9 +39,14 @@ type passwordResetService struct {
 	sms          SMSSender
 	auth         AuthService
 	frontendHost string
+	ttl          time.Duration
+	now          func() time.Time
 }
 
-func NewPasswordResetService(userRepo repositories.UserRepository, repo repositories.PasswordResetRepository, emails EmailService, sms SMSSender, auth AuthService, frontendHost string) PasswordResetService {
+func NewPasswordResetService(userRepo repositories.UserRepository, repo repositories.PasswordResetRepository, emails EmailService, sms SMSSender, auth AuthService, frontendHost string, ttl time.Duration) PasswordResetService {
+	if ttl <= 0 {
+		ttl = defaultPasswordResetTTL
+	}
 	return &passwordResetService{
 		userRepo:     userRepo,
 		repo:         repo,
@@ -41,10 +54,12 @@ func NewPasswordResetService(userRepo repositories.UserRepository, repo reposito
 		sms:          sms,
 		auth:         auth,
 		frontendHost: strings.TrimSpace(frontendHost),
+		ttl:          ttl,
+		now:          time.Now,
 	}
 }
 
-func (s *passwordResetService) RequestReset(email string) error {
+func (s *passwordResetService) RequestReset(ctx context.Context, email string) error {
 	email = strings.TrimSpace(strings.ToLower(email))
 	if email == "" {
 		return fmt.Errorf("email is required")
@@ -60,18 +75,28 @@ func (s *passwordResetService) RequestReset(email string) error {
 		return nil
 	}
 
+	now := s.now()
+	pending, err := s.repo.GetLatestActiveByUser(ctx, user.ID, now)
+	if err != nil {
+		return err
+	}
+	if pending != nil && now.Sub(pending.CreatedAt) < passwordResetThrottle {
+		log.Printf("[password-reset] request for user id=%d throttled; pending token reused", user.ID)
+		return nil
+	}
+
 	token, err := utils.NewRefreshToken(32)
 	if err != nil {
 		return err
 	}
-	expires := time.Now().Add(1 * time.Hour)
-	if err := s.repo.Create(user.ID, token, expires); err != nil {
+	expires := now.Add(s.ttl)
+	if err := s.repo.Create(ctx, user.ID, token, expires); err != nil {
 		return err
 	}
 
 	resetURL := s.buildResetURL(token)
 	if s.emails != nil {
-		if err := s.emails.SendPasswordResetEmail(user.Email, resetURL); err != nil {
+		if err := s.emails.SendPasswordResetEmail(user.Email, resetURL, token); err != nil {
 			log.Printf("[password-reset] failed to send email to %s: %v", user.Email, err)
 		}
 	}
@@ -87,17 +112,17 @@ func (s *passwordResetService) RequestReset(email string) error {
 	return nil
 }
 
-func (s *passwordResetService) ResetPassword(token, newPassword string) error {
+func (s *passwordResetService) ResetPassword(ctx context.Context, token, newPassword string) error {
 	token = strings.TrimSpace(token)
 	newPassword = strings.TrimSpace(newPassword)
 	if token == "" || newPassword == "" {
 		return fmt.Errorf("token and password are required")
 	}
-	if len(newPassword) < 6 {
-		return fmt.Errorf("password must be at least 6 characters")
+	if err := ValidatePassword(newPassword); err != nil {
+		return err
 	}
 
-	pr, err := s.repo.GetByToken(token)
+	pr, err := s.repo.GetByToken(ctx, token)
 	if err != nil {
 		return err
 	}
@@ -107,7 +132,7 @@ func (s *passwordResetService) ResetPassword(token, newPassword string) error {
 	if pr.Used {
 		return ErrResetTokenUsed
 	}
-	if time.Now().After(pr.ExpiresAt) {
+	if s.now().After(pr.ExpiresAt) {
 		return ErrResetTokenExpired
 	}
 	user, err := s.userRepo.GetByID(pr.UserID)
@@ -122,10 +147,18 @@ func (s *passwordResetService) ResetPassword(token, newPassword string) error {
 	if err != nil {
 		return err
 	}
-	if err := s.userRepo.UpdatePassword(pr.UserID, hash); err != nil {
+	// Токен гасится до смены пароля: из двух параллельных запросов с одним
+	// токеном пройдёт только один, а сбой ниже не оставит токен рабочим.
+	consumed, err := s.repo.MarkUsed(ctx, pr.Token)
+	if err != nil {
 		return err
 	}
-	return s.repo.MarkUsed(pr.Token)
+	if !consumed {
+		return ErrResetTokenUsed
+	}
+	// UpdatePassword тем же запросом отзывает refresh-токены, так что украденная
+	// сессия не переживёт сброс.
+	return s.userRepo.UpdatePassword(pr.UserID, hash)
 }
 
 func (s *passwordResetService) buildResetURL(token string) string {
@@ -136,7 +169,12 @@ func (s *passwordResetService) buildResetURL(token string) string {
 
 	base = strings.TrimRight(base, "/")
 	escapedToken := url.QueryEscape(token)
-	return fmt.Sprintf("%s/reset-password?token=%s", base, escapedToken)
+	resetURL := fmt.Sprintf("%s/reset-password?token=%s", base, escapedToken)
+	if !utils.IsAllowedRedirect(resetURL) {
+		log.Printf("[password-reset] frontend host %q is not on the redirect allowlist; link omitted", base)
+		return ""
+	}
+	return resetURL
 }
 
 func BuildPasswordResetSMS(resetURL string) string {
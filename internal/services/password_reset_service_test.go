@@ -0,0 +1,288 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"turcompany/internal/models"
+	"turcompany/internal/utils"
+)
+
+func TestBuildResetURL_WellFormedWithTokenQuery(t *testing.T) {
+	utils.SetAllowedRedirectOrigins([]string{"https://crm.example.com"})
+	t.Cleanup(func() { utils.SetAllowedRedirectOrigins(nil) })
+
+	s := &passwordResetService{frontendHost: "https://crm.example.com/"}
+	token := "abc+/=&def"
+	raw := s.buildResetURL(token)
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("reset link %q does not parse: %v", raw, err)
+	}
+	if u.Scheme != "https" || u.Host != "crm.example.com" || u.Path != "/reset-password" {
+		t.Fatalf("unexpected reset link %q", raw)
+	}
+	if got := u.Query().Get("token"); got != token {
+		t.Fatalf("token query param = %q, want %q (link %q)", got, token, raw)
+	}
+}
+
+func TestBuildResetURL_OmittedWhenHostMissingOrNotAllowed(t *testing.T) {
+	utils.SetAllowedRedirectOrigins([]string{"https://crm.example.com"})
+	t.Cleanup(func() { utils.SetAllowedRedirectOrigins(nil) })
+
+	for _, host := range []string{"", "https://evil.example.org"} {
+		s := &passwordResetService{frontendHost: host}
+		if got := s.buildResetURL("tok"); got != "" {
+			t.Fatalf("host %q: expected no link, got %q", host, got)
+		}
+	}
+}
+
+// memoryResetRepo stores reset tokens the way the password_resets table does.
+type memoryResetRepo struct {
+	rows  map[string]*models.PasswordReset
+	last  string
+	clock func() time.Time
+}
+
+func (r *memoryResetRepo) Create(_ context.Context, userID int, token string, expiresAt time.Time) error {
+	if r.rows == nil {
+		r.rows = map[string]*models.PasswordReset{}
+	}
+	r.rows[token] = &models.PasswordReset{UserID: userID, Token: token, ExpiresAt: expiresAt, CreatedAt: r.clock()}
+	r.last = token
+	return nil
+}
+
+func (r *memoryResetRepo) GetLatestActiveByUser(_ context.Context, userID int, now time.Time) (*models.PasswordReset, error) {
+	var latest *models.PasswordReset
+	for _, pr := range r.rows {
+		if pr.UserID != userID || pr.Used || !pr.ExpiresAt.After(now) {
+			continue
+		}
+		if latest == nil || pr.CreatedAt.After(latest.CreatedAt) {
+			latest = pr
+		}
+	}
+	if latest == nil {
+		return nil, nil
+	}
+	cp := *latest
+	return &cp, nil
+}
+
+func (r *memoryResetRepo) GetByToken(_ context.Context, token string) (*models.PasswordReset, error) {
+	pr, ok := r.rows[token]
+	if !ok {
+		return nil, nil
+	}
+	cp := *pr
+	return &cp, nil
+}
+
+func (r *memoryResetRepo) MarkUsed(_ context.Context, token string) (bool, error) {
+	pr, ok := r.rows[token]
+	if !ok || pr.Used {
+		return false, nil
+	}
+	pr.Used = true
+	return true, nil
+}
+
+type resetUserRepo struct {
+	sessionUserRepo
+}
+
+func (r *resetUserRepo) GetByEmail(email string) (*models.User, error) {
+	if email != r.user.Email {
+		return nil, nil
+	}
+	u := r.user
+	return &u, nil
+}
+
+// countingResetMail records reset emails and the tokens they carried.
+type countingResetMail struct {
+	noopMailService
+	tokens []string
+}
+
+func (m *countingResetMail) SendPasswordResetEmail(_, _, token string) error {
+	m.tokens = append(m.tokens, token)
+	return nil
+}
+
+func newResetTestService(t *testing.T, ttl time.Duration, now time.Time) (*passwordResetService, *memoryResetRepo) {
+	svc, resets, _ := newResetTestServiceWithMail(t, ttl, now)
+	return svc, resets
+}
+
+func newResetTestServiceWithMail(t *testing.T, ttl time.Duration, now time.Time) (*passwordResetService, *memoryResetRepo, *countingResetMail) {
+	t.Helper()
+	auth := NewAuthService([]byte("01234567890123456789012345678901"), nil, 0, 0, 0, nil)
+	users := &resetUserRepo{sessionUserRepo: *newSessionTestRepo(t, auth)}
+	mail := &countingResetMail{}
+	svc := NewPasswordResetService(users, nil, mail, nil, auth, "", ttl).(*passwordResetService)
+	resets := &memoryResetRepo{clock: func() time.Time { return svc.now() }}
+	svc.repo = resets
+	svc.now = func() time.Time { return now }
+	return svc, resets, mail
+}
+
+func TestNewPasswordResetService_DefaultTTL(t *testing.T) {
+	svc := NewPasswordResetService(nil, nil, nil, nil, nil, "", 0).(*passwordResetService)
+	if svc.ttl != time.Hour {
+		t.Fatalf("default ttl = %v, want 1h", svc.ttl)
+	}
+}
+
+func TestRequestReset_UsesConfiguredTTL(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	svc, resets := newResetTestService(t, 15*time.Minute, now)
+
+	if err := svc.RequestReset(context.Background(), "u@example.com"); err != nil {
+		t.Fatalf("RequestReset: %v", err)
+	}
+	pr := resets.rows[resets.last]
+	if pr == nil {
+		t.Fatal("reset token was not stored")
+	}
+	if want := now.Add(15 * time.Minute); !pr.ExpiresAt.Equal(want) {
+		t.Fatalf("expires_at = %v, want %v", pr.ExpiresAt, want)
+	}
+}
+
+func TestResetPassword_WithinTTLSucceedsOnceThenRejectsReuse(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	svc, resets := newResetTestService(t, 15*time.Minute, now)
+	if err := svc.RequestReset(context.Background(), "u@example.com"); err != nil {
+		t.Fatalf("RequestReset: %v", err)
+	}
+	token := resets.last
+
+	svc.now = func() time.Time { return now.Add(14 * time.Minute) }
+	if err := svc.ResetPassword(context.Background(), token, "NewPassw0rd"); err != nil {
+		t.Fatalf("reset within ttl: %v", err)
+	}
+	if !resets.rows[token].Used {
+		t.Fatal("token must be marked used after a successful reset")
+	}
+
+	err := svc.ResetPassword(context.Background(), token, "OtherPassw0rd")
+	if !errors.Is(err, ErrResetTokenUsed) {
+		t.Fatalf("second use: got %v, want %v", err, ErrResetTokenUsed)
+	}
+	if err.Error() != "reset token already used" {
+		t.Fatalf("unexpected message %q", err.Error())
+	}
+}
+
+// staleResetRepo serves GetByToken from a snapshot taken before any reset, the
+// way a second concurrent request sees the row before the first commits.
+type staleResetRepo struct {
+	*memoryResetRepo
+	snapshot models.PasswordReset
+}
+
+func (r *staleResetRepo) GetByToken(context.Context, string) (*models.PasswordReset, error) {
+	pr := r.snapshot
+	return &pr, nil
+}
+
+func TestResetPassword_ConcurrentUseOfOneTokenChangesPasswordOnce(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	svc, resets := newResetTestService(t, 15*time.Minute, now)
+	if err := svc.RequestReset(context.Background(), "u@example.com"); err != nil {
+		t.Fatalf("RequestReset: %v", err)
+	}
+	token := resets.last
+	svc.repo = &staleResetRepo{memoryResetRepo: resets, snapshot: *resets.rows[token]}
+	users := svc.userRepo.(*resetUserRepo)
+
+	if err := svc.ResetPassword(context.Background(), token, "FirstPassw0rd"); err != nil {
+		t.Fatalf("first reset: %v", err)
+	}
+	hash := users.user.PasswordHash
+
+	if err := svc.ResetPassword(context.Background(), token, "SecondPassw0rd"); !errors.Is(err, ErrResetTokenUsed) {
+		t.Fatalf("racing reset: got %v, want %v", err, ErrResetTokenUsed)
+	}
+	if users.user.PasswordHash != hash {
+		t.Fatal("racing reset with an already consumed token must not change the password")
+	}
+}
+
+func TestResetPassword_PastExpiryFails(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	svc, resets := newResetTestService(t, 15*time.Minute, now)
+	if err := svc.RequestReset(context.Background(), "u@example.com"); err != nil {
+		t.Fatalf("RequestReset: %v", err)
+	}
+	token := resets.last
+
+	svc.now = func() time.Time { return now.Add(16 * time.Minute) }
+	err := svc.ResetPassword(context.Background(), token, "NewPassw0rd")
+	if !errors.Is(err, ErrResetTokenExpired) {
+		t.Fatalf("expired token: got %v, want %v", err, ErrResetTokenExpired)
+	}
+	if err.Error() != "reset token expired" {
+		t.Fatalf("unexpected message %q", err.Error())
+	}
+	if resets.rows[token].Used {
+		t.Fatal("expired token must not be marked used")
+	}
+}
+
+func TestRequestReset_ThrottlesRepeatedRequestsWithinWindow(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	svc, resets, mail := newResetTestServiceWithMail(t, time.Hour, now)
+	ctx := context.Background()
+
+	if err := svc.RequestReset(ctx, "u@example.com"); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+	first := resets.last
+
+	svc.now = func() time.Time { return now.Add(time.Minute) }
+	if err := svc.RequestReset(ctx, "U@example.com "); err != nil {
+		t.Fatalf("second request: %v", err)
+	}
+	if len(resets.rows) != 1 || resets.last != first {
+		t.Fatalf("second request within the window must reuse the pending token, rows=%d", len(resets.rows))
+	}
+	if len(mail.tokens) != 1 || mail.tokens[0] != first {
+		t.Fatalf("expected exactly one email with the first token, got %v", mail.tokens)
+	}
+
+	svc.now = func() time.Time { return now.Add(passwordResetThrottle) }
+	if err := svc.RequestReset(ctx, "u@example.com"); err != nil {
+		t.Fatalf("request after window: %v", err)
+	}
+	if len(mail.tokens) != 2 || resets.last == first {
+		t.Fatalf("request after the window must issue and send a new token, emails=%v", mail.tokens)
+	}
+}
+
+func TestRequestReset_UsedTokenDoesNotThrottle(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	svc, resets, mail := newResetTestServiceWithMail(t, time.Hour, now)
+	ctx := context.Background()
+
+	if err := svc.RequestReset(ctx, "u@example.com"); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+	if err := svc.ResetPassword(ctx, resets.last, "NewPassw0rd"); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+	if err := svc.RequestReset(ctx, "u@example.com"); err != nil {
+		t.Fatalf("second request: %v", err)
+	}
+	if len(mail.tokens) != 2 {
+		t.Fatalf("a consumed token must not block a new request, emails=%v", mail.tokens)
+	}
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"turcompany/internal/models"
+)
+
+// sessionUserRepo keeps a single user's password and refresh token the way
+// the users table does.
+type sessionUserRepo struct {
+	captureUserRepo
+	user    models.User
+	refresh string
+	revoked bool
+}
+
+func (r *sessionUserRepo) GetByID(id int) (*models.User, error) {
+	if id != r.user.ID {
+		return nil, ErrNotFound
+	}
+	u := r.user
+	return &u, nil
+}
+
+func (r *sessionUserRepo) UpdatePassword(_ int, hash string) error {
+	r.user.PasswordHash = hash
+	r.refresh, r.revoked = "", true
+	return nil
+}
+
+func (r *sessionUserRepo) UpdateRefresh(_ int, token string, _ time.Time) error {
+	r.refresh, r.revoked = token, false
+	return nil
+}
+
+func (r *sessionUserRepo) RotateRefresh(oldToken, newToken string, _ time.Time) (*models.User, error) {
+	if r.revoked || oldToken != r.refresh {
+		return nil, sql.ErrNoRows
+	}
+	r.refresh = newToken
+	u := r.user
+	return &u, nil
+}
+
+func (r *sessionUserRepo) ClearRefresh(int) error {
+	r.refresh, r.revoked = "", true
+	return nil
+}
+
+type singleResetRepo struct{ pr models.PasswordReset }
+
+func (r *singleResetRepo) Create(context.Context, int, string, time.Time) error { return nil }
+func (r *singleResetRepo) GetByToken(_ context.Context, token string) (*models.PasswordReset, error) {
+	if token != r.pr.Token {
+		return nil, nil
+	}
+	pr := r.pr
+	return &pr, nil
+}
+func (r *singleResetRepo) GetLatestActiveByUser(context.Context, int, time.Time) (*models.PasswordReset, error) {
+	return nil, nil
+}
+func (r *singleResetRepo) MarkUsed(context.Context, string) (bool, error) {
+	if r.pr.Used {
+		return false, nil
+	}
+	r.pr.Used = true
+	return true, nil
+}
+
+func newSessionTestRepo(t *testing.T, auth AuthService) *sessionUserRepo {
+	t.Helper()
+	hash, err := auth.HashPassword("OldPassw0rd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &sessionUserRepo{user: models.User{ID: 5, Email: "u@example.com", PasswordHash: hash, IsActive: true}}
+	if err := repo.UpdateRefresh(5, "stolen-refresh", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	return repo
+}
+
+func TestResetPassword_RevokesExistingRefreshTokens(t *testing.T) {
+	auth := NewAuthService([]byte("01234567890123456789012345678901"), nil, 0, 0, 0, nil)
+	repo := newSessionTestRepo(t, auth)
+	resets := &singleResetRepo{pr: models.PasswordReset{UserID: 5, Token: "reset-token", ExpiresAt: time.Now().Add(time.Hour)}}
+	svc := NewPasswordResetService(repo, resets, noopMailService{}, nil, auth, "", 0)
+
+	if err := svc.ResetPassword(context.Background(), "reset-token", "NewPassw0rd"); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+	if _, err := NewUserService(repo, nil, auth).RotateRefresh("stolen-refresh", "next", time.Now().Add(time.Hour)); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("refresh token issued before the reset must stop working, got %v", err)
+	}
+	if !resets.pr.Used {
+		t.Fatal("reset token must be marked used")
+	}
+}
+
+func TestChangePassword_RevokesExistingRefreshTokens(t *testing.T) {
+	auth := NewAuthService([]byte("01234567890123456789012345678901"), nil, 0, 0, 0, nil)
+	repo := newSessionTestRepo(t, auth)
+	svc := NewUserService(repo, nil, auth)
+
+	if err := svc.ChangePassword(5, "OldPassw0rd", "NewPassw0rd"); err != nil {
+		t.Fatalf("ChangePassword: %v", err)
+	}
+	if _, err := svc.RotateRefresh("stolen-refresh", "next", time.Now().Add(time.Hour)); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("refresh token issued before the change must stop working, got %v", err)
+	}
+}
@@ -94,7 +94,7 @@ func (s *PublicDocumentSigningService) GenerateSignLink(ctx context.Context, doc
 	if s.docService == nil || s.links == nil {
 		return "", time.Time{}, errors.New("service unavailable")
 	}
-	doc, err := s.docService.GetDocument(docID, userID, roleID)
+	doc, err := s.docService.GetDocument(ctx, docID, userID, roleID)
 	if err != nil || doc == nil {
 		return "", time.Time{}, repositories.ErrPublicLinkNotFound
 	}
@@ -138,7 +138,7 @@ func (s *PublicDocumentSigningService) GetPublicDocument(ctx context.Context, ra
 	if err != nil {
 		return nil, time.Time{}, err
 	}
-	doc, err := s.docRepo.GetByID(link.DocumentID)
+	doc, err := s.docRepo.GetByID(ctx, link.DocumentID)
 	if err != nil || doc == nil {
 		return nil, time.Time{}, repositories.ErrPublicLinkNotFound
 	}
@@ -180,7 +180,7 @@ func (s *PublicDocumentSigningService) SignPublicDocument(ctx context.Context, r
 	if err != nil {
 		return time.Time{}, "", 0, err
 	}
-	doc, err := s.docRepo.GetByID(link.DocumentID)
+	doc, err := s.docRepo.GetByID(ctx, link.DocumentID)
 	if err != nil || doc == nil {
 		return time.Time{}, "", 0, repositories.ErrPublicLinkNotFound
 	}
@@ -237,16 +237,16 @@ func (s *PublicDocumentSigningService) SignPublicDocument(ctx context.Context, r
 			log.Printf("[sign][signature_image] saved path=%s event=%s", savedPath, eventID)
 		}
 	}
-	if err := s.docService.FinalizeSigning(link.DocumentID); err != nil {
+	if err := s.docService.FinalizeSigning(ctx, link.DocumentID); err != nil {
 		return time.Time{}, "", 0, err
 	}
-	if err := s.docRepo.UpdateSigningMeta(link.DocumentID, "public_link", "", "", string(metaRaw)); err != nil {
+	if err := s.docRepo.UpdateSigningMeta(ctx, link.DocumentID, "public_link", "", "", string(metaRaw)); err != nil {
 		return time.Time{}, "", 0, err
 	}
 
 	// Stamp the signature image onto the PDF (best-effort; never fails the signing flow).
 	if isImageSig && precomputedImagePath != "" {
-		s.stampSignatureOnDocument(link.DocumentID, filepath.Join(s.uploadsDir, precomputedImagePath))
+		s.stampSignatureOnDocument(ctx, link.DocumentID, filepath.Join(s.uploadsDir, precomputedImagePath))
 	}
 
 	return signedAt, createdEventID, link.DocumentID, nil
@@ -254,7 +254,7 @@ func (s *PublicDocumentSigningService) SignPublicDocument(ctx context.Context, r
 
 // stampSignatureOnDocument overlays the client signature image onto the document PDF.
 // Errors are logged but do not propagate — the signing record is already committed.
-func (s *PublicDocumentSigningService) stampSignatureOnDocument(docID int64, imgAbsPath string) {
+func (s *PublicDocumentSigningService) stampSignatureOnDocument(ctx context.Context, docID int64, imgAbsPath string) {
 	log.Printf("[pdf_stamp] starting document_id=%d img=%s", docID, imgAbsPath)
 
 	if _, err := os.Stat(imgAbsPath); err != nil {
@@ -262,7 +262,7 @@ func (s *PublicDocumentSigningService) stampSignatureOnDocument(docID int64, img
 		return
 	}
 
-	doc, err := s.docRepo.GetByID(docID)
+	doc, err := s.docRepo.GetByID(ctx, docID)
 	if err != nil || doc == nil {
 		log.Printf("[pdf_stamp] document not found document_id=%d err=%v", docID, err)
 		return
@@ -2,26 +2,75 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
 	"time"
 
 	"turcompany/internal/authz"
+	"turcompany/internal/models"
 	"turcompany/internal/repositories"
 )
 
+// DefaultSummaryCacheTTL is how long a dashboard summary is served from memory
+// before it is recomputed.
+const DefaultSummaryCacheTTL = 30 * time.Second
+
+// ReportDealStats is the subset of DealRepository the reports aggregate over.
+type ReportDealStats interface {
+	GetDealsFunnelStats(ctx context.Context, from, to time.Time, ownerID *int, branchID *int) ([]models.FunnelRow, error)
+	GetDealsRevenueStats(ctx context.Context, from, to time.Time, ownerID *int, branchID *int) ([]models.RevenueRow, error)
+	GetTopClientsByRevenue(ctx context.Context, from, to time.Time, ownerID *int, branchID *int, limit int) ([]models.TopClientRow, error)
+	SumWonAmountByOwner(ctx context.Context, from, to time.Time, branchID *int) ([]models.OwnerRevenueRow, error)
+}
+
+// ReportLeadStats is the subset of LeadRepository the reports aggregate over.
+type ReportLeadStats interface {
+	GetLeadsSummaryStats(ctx context.Context, from, to time.Time, ownerID *int, branchID *int) ([]models.LeadSummaryRow, error)
+	GetLeadSourceStats(ctx context.Context, from, to time.Time, ownerID *int, branchID *int) ([]models.LeadSourceRow, error)
+}
+
 type ReportService struct {
-	LeadRepo *repositories.LeadRepository
-	DealRepo *repositories.DealRepository
+	LeadRepo ReportLeadStats
+	DealRepo ReportDealStats
 	UserRepo repositories.UserRepository
+
+	summaryTTL   time.Duration
+	summaryMu    sync.Mutex
+	summaryCache map[string]summaryCacheEntry
+	now          func() time.Time
+}
+
+type summaryCacheEntry struct {
+	report    *DashboardKPIReport
+	expiresAt time.Time
 }
 
-func NewReportService(leadRepo *repositories.LeadRepository, dealRepo *repositories.DealRepository, userRepo ...repositories.UserRepository) *ReportService {
-	s := &ReportService{LeadRepo: leadRepo, DealRepo: dealRepo}
+func NewReportService(leadRepo ReportLeadStats, dealRepo ReportDealStats, userRepo ...repositories.UserRepository) *ReportService {
+	s := &ReportService{LeadRepo: leadRepo, DealRepo: dealRepo, summaryTTL: DefaultSummaryCacheTTL}
 	if len(userRepo) > 0 {
 		s.UserRepo = userRepo[0]
 	}
 	return s
 }
 
+// SetSummaryCacheTTL overrides how long GetSummary results are reused.
+// A non-positive ttl disables caching.
+func (s *ReportService) SetSummaryCacheTTL(ttl time.Duration) {
+	s.summaryMu.Lock()
+	defer s.summaryMu.Unlock()
+	s.summaryTTL = ttl
+	s.summaryCache = nil
+}
+
+func (s *ReportService) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
+
 func (s *ReportService) resolveFilters(userID, roleID int, requestedBranchID *int) (ownerID *int, branchID *int, err error) {
 	switch roleID {
 	case authz.RoleSales:
@@ -102,6 +151,55 @@ func (s *ReportService) GetLeadsSummary(ctx context.Context, from, to time.Time,
 	return &LeadsSummaryReport{From: from, To: to, Items: items}, nil
 }
 
+type LeadSourceItem struct {
+	Source         string  `json:"source"`
+	Total          int64   `json:"total"`
+	Converted      int64   `json:"converted"`
+	ConversionRate float64 `json:"conversion_rate"`
+}
+type LeadSourceReport struct {
+	From           time.Time        `json:"from"`
+	To             time.Time        `json:"to"`
+	Items          []LeadSourceItem `json:"items"`
+	Total          int64            `json:"total"`
+	Converted      int64            `json:"converted"`
+	ConversionRate float64          `json:"conversion_rate"`
+}
+
+// GetLeadsBySource counts the leads created in the period per source and the
+// share of them converted to a deal, as a percentage like the dashboard
+// conversion_rate. Sales see their own leads; branch roles their branch.
+func (s *ReportService) GetLeadsBySource(ctx context.Context, from, to time.Time, userID, roleID int, requestedBranchID *int) (*LeadSourceReport, error) {
+	ownerID, branchID, err := s.resolveFilters(userID, roleID, requestedBranchID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.LeadRepo.GetLeadSourceStats(ctx, from, to, ownerID, branchID)
+	if err != nil {
+		return nil, err
+	}
+	report := &LeadSourceReport{From: from, To: to, Items: make([]LeadSourceItem, 0, len(rows))}
+	for _, row := range rows {
+		report.Items = append(report.Items, LeadSourceItem{
+			Source:         row.Source,
+			Total:          row.Total,
+			Converted:      row.Converted,
+			ConversionRate: conversionPercent(row.Converted, row.Total),
+		})
+		report.Total += row.Total
+		report.Converted += row.Converted
+	}
+	report.ConversionRate = conversionPercent(report.Converted, report.Total)
+	return report, nil
+}
+
+func conversionPercent(converted, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(converted)/float64(total)*10000) / 100
+}
+
 type RevenueItem struct {
 	Period      string  `json:"period"`
 	TotalAmount float64 `json:"total_amount"`
@@ -149,6 +247,60 @@ func (s *ReportService) GetRevenueStats(ctx context.Context, from, to time.Time,
 	return &RevenueReport{From: from, To: to, Period: period, Items: items, TopClients: topItems}, nil
 }
 
+type OwnerRevenueItem struct {
+	OwnerID     int     `json:"owner_id"`
+	OwnerName   string  `json:"owner_name"`
+	TotalAmount float64 `json:"total_amount"`
+	Currency    string  `json:"currency"`
+}
+type OwnerRevenueReport struct {
+	From    time.Time          `json:"from"`
+	To      time.Time          `json:"to"`
+	GroupBy string             `json:"group_by"`
+	Items   []OwnerRevenueItem `json:"items"`
+}
+
+// GetRevenueByOwner returns the won-deal amount per owner and currency. It is
+// only available to quality control, management and admins, since it compares
+// individual sales reps.
+func (s *ReportService) GetRevenueByOwner(ctx context.Context, from, to time.Time, userID, roleID int, requestedBranchID *int) (*OwnerRevenueReport, error) {
+	switch roleID {
+	case authz.RoleControl, authz.RoleManagement, authz.RoleSystemAdmin:
+	default:
+		return nil, ErrForbidden
+	}
+	_, branchID, err := s.resolveFilters(userID, roleID, requestedBranchID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.DealRepo.SumWonAmountByOwner(ctx, from, to, branchID)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[int]string)
+	items := make([]OwnerRevenueItem, 0, len(rows))
+	for _, row := range rows {
+		name, ok := names[row.OwnerID]
+		if !ok {
+			name = s.ownerName(row.OwnerID)
+			names[row.OwnerID] = name
+		}
+		items = append(items, OwnerRevenueItem{OwnerID: row.OwnerID, OwnerName: name, TotalAmount: row.TotalAmount, Currency: row.Currency})
+	}
+	return &OwnerRevenueReport{From: from, To: to, GroupBy: "owner", Items: items}, nil
+}
+
+func (s *ReportService) ownerName(ownerID int) string {
+	if s.UserRepo == nil {
+		return ""
+	}
+	u, err := s.UserRepo.GetByID(ownerID)
+	if err != nil || u == nil {
+		return ""
+	}
+	return strings.TrimSpace(u.FirstName + " " + u.LastName)
+}
+
 type DashboardKPI struct {
 	Key          string  `json:"key"`
 	Value        float64 `json:"value"`
@@ -165,6 +317,10 @@ func (s *ReportService) GetDashboardKPI(ctx context.Context, from, to time.Time,
 	if err != nil {
 		return nil, err
 	}
+	return s.buildDashboardKPI(ctx, from, to, ownerID, branchID)
+}
+
+func (s *ReportService) buildDashboardKPI(ctx context.Context, from, to time.Time, ownerID, branchID *int) (*DashboardKPIReport, error) {
 	funnelRows, err := s.DealRepo.GetDealsFunnelStats(ctx, from, to, ownerID, branchID)
 	if err != nil {
 		return nil, err
@@ -195,3 +351,68 @@ func (s *ReportService) GetDashboardKPI(ctx context.Context, from, to time.Time,
 	items := []DashboardKPI{{Key: "total_revenue", Value: totalRevenue}, {Key: "new_clients_count", Value: float64(len(topClients))}, {Key: "closed_deals_count", Value: float64(wonCount)}, {Key: "conversion_rate", Value: conversionRate}}
 	return &DashboardKPIReport{From: from, To: to, Items: items}, nil
 }
+
+// GetSummary returns the dashboard KPI summary, serving a cached copy for
+// identical filters until the cache TTL passes. fresh=true skips the cache and
+// stores the recomputed result for subsequent callers.
+func (s *ReportService) GetSummary(ctx context.Context, from, to time.Time, userID, roleID int, requestedBranchID *int, fresh bool) (*DashboardKPIReport, error) {
+	ownerID, branchID, err := s.resolveFilters(userID, roleID, requestedBranchID)
+	if err != nil {
+		return nil, err
+	}
+	key := summaryCacheKey(from, to, ownerID, branchID)
+	if !fresh {
+		if cached := s.cachedSummary(key); cached != nil {
+			return cached, nil
+		}
+	}
+	report, err := s.buildDashboardKPI(ctx, from, to, ownerID, branchID)
+	if err != nil {
+		return nil, err
+	}
+	s.storeSummary(key, report)
+	return report, nil
+}
+
+func summaryCacheKey(from, to time.Time, ownerID, branchID *int) string {
+	owner, branch := 0, 0
+	if ownerID != nil {
+		owner = *ownerID
+	}
+	if branchID != nil {
+		branch = *branchID
+	}
+	return fmt.Sprintf("%d:%d:%d:%d", from.Unix(), to.Unix(), owner, branch)
+}
+
+func (s *ReportService) cachedSummary(key string) *DashboardKPIReport {
+	s.summaryMu.Lock()
+	defer s.summaryMu.Unlock()
+	entry, ok := s.summaryCache[key]
+	if !ok {
+		return nil
+	}
+	if !s.clock().Before(entry.expiresAt) {
+		delete(s.summaryCache, key)
+		return nil
+	}
+	return entry.report
+}
+
+func (s *ReportService) storeSummary(key string, report *DashboardKPIReport) {
+	s.summaryMu.Lock()
+	defer s.summaryMu.Unlock()
+	if s.summaryTTL <= 0 {
+		return
+	}
+	now := s.clock()
+	if s.summaryCache == nil {
+		s.summaryCache = make(map[string]summaryCacheEntry)
+	}
+	for k, entry := range s.summaryCache {
+		if !now.Before(entry.expiresAt) {
+			delete(s.summaryCache, k)
+		}
+	}
+	s.summaryCache[key] = summaryCacheEntry{report: report, expiresAt: now.Add(s.summaryTTL)}
+}
@@ -8,6 +8,7 @@ import (
 
 	"turcompany/internal/authz"
 	"turcompany/internal/models"
+	"turcompany/internal/repositories"
 )
 
 type reportTestUserRepo struct {
@@ -27,6 +28,11 @@ func (r *reportTestUserRepo) GetByID(id int) (*models.User, error) {
 }
 func (r *reportTestUserRepo) Update(user *models.User) error { return nil }
 func (r *reportTestUserRepo) Delete(id int) error            { return nil }
+func (r *reportTestUserRepo) Reactivate(int) error { return nil }
+func (r *reportTestUserRepo) Search(repositories.UserSearchFilter, int, int) ([]*models.User, error) {
+	return nil, nil
+}
+func (r *reportTestUserRepo) CountSearch(repositories.UserSearchFilter) (int, error) { return 0, nil }
 func (r *reportTestUserRepo) List(limit, offset int) ([]*models.User, error) {
 	return nil, nil
 }
@@ -142,3 +148,223 @@ func TestResolveFilters_DeniesWhenBranchContextMissing(t *testing.T) {
 		t.Fatalf("control without branch context must be forbidden, got %v", err)
 	}
 }
+
+type countingDealStats struct {
+	funnelCalls int
+}
+
+func (r *countingDealStats) GetDealsFunnelStats(context.Context, time.Time, time.Time, *int, *int) ([]models.FunnelRow, error) {
+	r.funnelCalls++
+	return []models.FunnelRow{{Status: "won", Count: 2}, {Status: "new", Count: 2}}, nil
+}
+func (r *countingDealStats) GetDealsRevenueStats(context.Context, time.Time, time.Time, *int, *int) ([]models.RevenueRow, error) {
+	return []models.RevenueRow{{Period: "2026-01", TotalAmount: 100, Currency: "KZT"}}, nil
+}
+func (r *countingDealStats) GetTopClientsByRevenue(context.Context, time.Time, time.Time, *int, *int, int) ([]models.TopClientRow, error) {
+	return nil, nil
+}
+func (r *countingDealStats) SumWonAmountByOwner(context.Context, time.Time, time.Time, *int) ([]models.OwnerRevenueRow, error) {
+	return nil, nil
+}
+
+func TestGetSummary_CachesUntilFreshRequested(t *testing.T) {
+	deals := &countingDealStats{}
+	svc := NewReportService(nil, deals)
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	first, err := svc.GetSummary(context.Background(), from, to, 1, authz.RoleManagement, nil, false)
+	if err != nil {
+		t.Fatalf("first summary: %v", err)
+	}
+	second, err := svc.GetSummary(context.Background(), from, to, 1, authz.RoleManagement, nil, false)
+	if err != nil {
+		t.Fatalf("second summary: %v", err)
+	}
+	if deals.funnelCalls != 1 {
+		t.Fatalf("expected cached second call, repo hit %d times", deals.funnelCalls)
+	}
+	if first != second {
+		t.Fatal("expected the cached report to be returned")
+	}
+
+	if _, err := svc.GetSummary(context.Background(), from, to, 1, authz.RoleManagement, nil, true); err != nil {
+		t.Fatalf("fresh summary: %v", err)
+	}
+	if deals.funnelCalls != 2 {
+		t.Fatalf("expected fresh=true to recompute, repo hit %d times", deals.funnelCalls)
+	}
+}
+
+func TestGetSummary_RecomputesAfterTTL(t *testing.T) {
+	deals := &countingDealStats{}
+	svc := NewReportService(nil, deals)
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	if _, err := svc.GetSummary(context.Background(), from, to, 1, authz.RoleSystemAdmin, nil, false); err != nil {
+		t.Fatalf("summary: %v", err)
+	}
+	now = now.Add(DefaultSummaryCacheTTL)
+	if _, err := svc.GetSummary(context.Background(), from, to, 1, authz.RoleSystemAdmin, nil, false); err != nil {
+		t.Fatalf("summary after ttl: %v", err)
+	}
+	if deals.funnelCalls != 2 {
+		t.Fatalf("expected expired entry to be recomputed, repo hit %d times", deals.funnelCalls)
+	}
+}
+
+// wonDealsByOwnerStats returns fixed SumWonAmountByOwner rows; the won-only
+// SQL itself is covered in the repositories package.
+type wonDealsByOwnerStats struct {
+	countingDealStats
+	rows []models.OwnerRevenueRow
+}
+
+func (r *wonDealsByOwnerStats) SumWonAmountByOwner(context.Context, time.Time, time.Time, *int) ([]models.OwnerRevenueRow, error) {
+	return r.rows, nil
+}
+
+type ownerNameUserRepo struct {
+	reportTestUserRepo
+	users map[int]*models.User
+}
+
+func (r *ownerNameUserRepo) GetByID(id int) (*models.User, error) { return r.users[id], nil }
+
+func TestGetRevenueByOwner_AttachesOwnerNames(t *testing.T) {
+	deals := &wonDealsByOwnerStats{rows: []models.OwnerRevenueRow{
+		{OwnerID: 7, TotalAmount: 350.5, Currency: "KZT"},
+		{OwnerID: 7, TotalAmount: 40, Currency: "USD"},
+		{OwnerID: 9, TotalAmount: 300, Currency: "KZT"},
+	}}
+	users := &ownerNameUserRepo{users: map[int]*models.User{
+		7: {ID: 7, FirstName: "Aida", LastName: "Serik"},
+		9: {ID: 9, FirstName: "Timur", LastName: "Ospan"},
+	}}
+	svc := NewReportService(nil, deals, users)
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	report, err := svc.GetRevenueByOwner(context.Background(), from, from.AddDate(0, 1, 0), 1, authz.RoleManagement, nil)
+	if err != nil {
+		t.Fatalf("GetRevenueByOwner: %v", err)
+	}
+	want := []OwnerRevenueItem{
+		{OwnerID: 7, OwnerName: "Aida Serik", TotalAmount: 350.5, Currency: "KZT"},
+		{OwnerID: 7, OwnerName: "Aida Serik", TotalAmount: 40, Currency: "USD"},
+		{OwnerID: 9, OwnerName: "Timur Ospan", TotalAmount: 300, Currency: "KZT"},
+	}
+	if len(report.Items) != len(want) {
+		t.Fatalf("expected %d rows, got %+v", len(want), report.Items)
+	}
+	for i := range want {
+		if report.Items[i] != want[i] {
+			t.Fatalf("row %d: got %+v, want %+v", i, report.Items[i], want[i])
+		}
+	}
+}
+
+func TestGetRevenueByOwner_ForbiddenForSales(t *testing.T) {
+	svc := NewReportService(nil, &wonDealsByOwnerStats{}, &reportTestUserRepo{user: &models.User{ID: 1, BranchID: intPtr(1)}})
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := svc.GetRevenueByOwner(context.Background(), from, from.AddDate(0, 1, 0), 1, authz.RoleSales, nil)
+	if !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for sales, got %v", err)
+	}
+}
+
+// leadSourceStats aggregates fixed leads the way GetLeadSourceStats does.
+type leadSourceStats struct {
+	leads   []models.Leads
+	ownerID *int
+}
+
+func (r *leadSourceStats) GetLeadsSummaryStats(context.Context, time.Time, time.Time, *int, *int) ([]models.LeadSummaryRow, error) {
+	return nil, nil
+}
+
+func (r *leadSourceStats) GetLeadSourceStats(_ context.Context, _, _ time.Time, ownerID *int, _ *int) ([]models.LeadSourceRow, error) {
+	r.ownerID = ownerID
+	var rows []models.LeadSourceRow
+	index := map[string]int{}
+	for _, lead := range r.leads {
+		if ownerID != nil && lead.OwnerID != *ownerID {
+			continue
+		}
+		source := lead.Source
+		if source == "" {
+			source = models.LeadSourceUnknown
+		}
+		i, ok := index[source]
+		if !ok {
+			i = len(rows)
+			index[source] = i
+			rows = append(rows, models.LeadSourceRow{Source: source})
+		}
+		rows[i].Total++
+		if lead.Status == "converted" {
+			rows[i].Converted++
+		}
+	}
+	return rows, nil
+}
+
+func TestGetLeadsBySource_AttributesConversionsToSourceBuckets(t *testing.T) {
+	leads := &leadSourceStats{leads: []models.Leads{
+		{OwnerID: 1, Source: "web", Status: "converted"},
+		{OwnerID: 1, Source: "web", Status: "new"},
+		{OwnerID: 1, Source: "web", Status: "cancelled"},
+		{OwnerID: 2, Source: "web", Status: "new"},
+		{OwnerID: 2, Source: "referral", Status: "converted"},
+		{OwnerID: 1, Source: "", Status: "converted"},
+		{OwnerID: 2, Source: "cold_call", Status: "in_progress"},
+	}}
+	svc := NewReportService(leads, &countingDealStats{})
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	report, err := svc.GetLeadsBySource(context.Background(), from, from.AddDate(0, 1, 0), 1, authz.RoleManagement, nil)
+	if err != nil {
+		t.Fatalf("GetLeadsBySource: %v", err)
+	}
+	got := map[string]LeadSourceItem{}
+	for _, item := range report.Items {
+		got[item.Source] = item
+	}
+	want := map[string]LeadSourceItem{
+		"web":       {Source: "web", Total: 4, Converted: 1, ConversionRate: 25},
+		"referral":  {Source: "referral", Total: 1, Converted: 1, ConversionRate: 100},
+		"unknown":   {Source: "unknown", Total: 1, Converted: 1, ConversionRate: 100},
+		"cold_call": {Source: "cold_call", Total: 1, Converted: 0, ConversionRate: 0},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d buckets, got %+v", len(want), report.Items)
+	}
+	for source, item := range want {
+		if got[source] != item {
+			t.Fatalf("bucket %s: expected %+v, got %+v", source, item, got[source])
+		}
+	}
+	if report.Total != 7 || report.Converted != 3 || report.ConversionRate != 42.86 {
+		t.Fatalf("unexpected totals: total=%d converted=%d rate=%v", report.Total, report.Converted, report.ConversionRate)
+	}
+}
+
+func TestGetLeadsBySource_SalesSeesOwnLeads(t *testing.T) {
+	leads := &leadSourceStats{leads: []models.Leads{
+		{OwnerID: 1, Source: "web", Status: "converted"},
+		{OwnerID: 2, Source: "web", Status: "converted"},
+	}}
+	svc := NewReportService(leads, &countingDealStats{}, &reportTestUserRepo{user: &models.User{ID: 1, BranchID: intPtr(1)}})
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	report, err := svc.GetLeadsBySource(context.Background(), from, from.AddDate(0, 1, 0), 1, authz.RoleSales, nil)
+	if err != nil {
+		t.Fatalf("GetLeadsBySource: %v", err)
+	}
+	if leads.ownerID == nil || *leads.ownerID != 1 || report.Total != 1 {
+		t.Fatalf("expected sales report limited to own leads, got owner=%v total=%d", leads.ownerID, report.Total)
+	}
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 
 	"turcompany/internal/models"
 	"turcompany/internal/repositories"
@@ -18,12 +19,32 @@ type RoleService interface {
 	GetRolesWithUserCounts() ([]map[string]interface{}, error)
 }
 
+// RoleInUseError is returned when a role still has active users. It matches
+// ErrRoleInUse via errors.Is.
+type RoleInUseError struct {
+	Users int
+}
+
+func (e *RoleInUseError) Error() string {
+	return fmt.Sprintf("role is assigned to %d user(s)", e.Users)
+}
+
+func (e *RoleInUseError) Unwrap() error { return ErrRoleInUse }
+
+// roleUserCounter is the part of UserRepository DeleteRole needs.
+type roleUserCounter interface {
+	GetCountByRole(roleID int) (int, error)
+}
+
 type roleService struct {
-	repo repositories.RoleRepository
+	repo  repositories.RoleRepository
+	users roleUserCounter
 }
 
-func NewRoleService(repo repositories.RoleRepository) RoleService {
-	return &roleService{repo: repo}
+// NewRoleService builds the role service; users may be nil, in which case
+// only the foreign key guards role deletion.
+func NewRoleService(repo repositories.RoleRepository, users roleUserCounter) RoleService {
+	return &roleService{repo: repo, users: users}
 }
 
 func (s *roleService) CreateRole(role *models.Role) error {
@@ -44,7 +65,18 @@ func (s *roleService) UpdateRole(role *models.Role) error {
 	return nil
 }
 
+// DeleteRole refuses to delete a role that active users still have. The
+// foreign key stays as the last guard for deactivated users.
 func (s *roleService) DeleteRole(id int) error {
+	if s.users != nil {
+		n, err := s.users.GetCountByRole(id)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return &RoleInUseError{Users: n}
+		}
+	}
 	if err := s.repo.Delete(id); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return ErrNotFound
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"turcompany/internal/repositories"
+)
+
+type roleDeleteRepoStub struct {
+	repositories.RoleRepository
+	deleted []int
+}
+
+func (r *roleDeleteRepoStub) Delete(id int) error {
+	r.deleted = append(r.deleted, id)
+	return nil
+}
+
+type roleUserCounterStub map[int]int
+
+func (s roleUserCounterStub) GetCountByRole(roleID int) (int, error) { return s[roleID], nil }
+
+func TestRoleServiceDeleteRole_RefusesRoleWithUsers(t *testing.T) {
+	repo := &roleDeleteRepoStub{}
+	svc := NewRoleService(repo, roleUserCounterStub{10: 3})
+
+	err := svc.DeleteRole(10)
+	var inUse *RoleInUseError
+	if !errors.As(err, &inUse) || inUse.Users != 3 {
+		t.Fatalf("expected RoleInUseError with 3 users, got %v", err)
+	}
+	if !errors.Is(err, ErrRoleInUse) {
+		t.Fatalf("expected error to match ErrRoleInUse, got %v", err)
+	}
+	if len(repo.deleted) != 0 {
+		t.Fatalf("role must not be deleted, got %v", repo.deleted)
+	}
+}
+
+func TestRoleServiceDeleteRole_DeletesEmptyRole(t *testing.T) {
+	repo := &roleDeleteRepoStub{}
+	svc := NewRoleService(repo, roleUserCounterStub{10: 3})
+
+	if err := svc.DeleteRole(11); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.deleted) != 1 || repo.deleted[0] != 11 {
+		t.Fatalf("expected role 11 to be deleted, got %v", repo.deleted)
+	}
+}
@@ -1,6 +1,9 @@
 package services
 
 import (
+	"context"
+	"strings"
+
 	"turcompany/internal/authz"
 	"turcompany/internal/models"
 	"turcompany/internal/repositories"
@@ -116,19 +119,25 @@ func resolveClientScope(userID, roleID int, userRepo repositories.UserRepository
 // LeadRepository that are needed for scope-based access control.
 // *repositories.LeadRepository satisfies this interface (duck typing).
 type leadListRepo interface {
-	ListByOwnerWithFilterAndArchiveScope(ownerID, limit, offset int, filter repositories.LeadListFilter, scope repositories.ArchiveScope) ([]*models.Leads, error)
-	ListAllWithFilterAndArchiveScope(limit, offset int, filter repositories.LeadListFilter, scope repositories.ArchiveScope) ([]*models.Leads, error)
-	CountByOwnerWithFilterAndArchiveScope(ownerID int, filter repositories.LeadListFilter, scope repositories.ArchiveScope) (int, error)
-	CountAllWithFilterAndArchiveScope(filter repositories.LeadListFilter, scope repositories.ArchiveScope) (int, error)
+	ListByOwnerWithFilterAndArchiveScope(ctx context.Context, ownerID, limit, offset int, filter repositories.LeadListFilter, scope repositories.ArchiveScope) ([]*models.Leads, error)
+	ListAllWithFilterAndArchiveScope(ctx context.Context, limit, offset int, filter repositories.LeadListFilter, scope repositories.ArchiveScope) ([]*models.Leads, error)
+	CountByOwnerWithFilterAndArchiveScope(ctx context.Context, ownerID int, filter repositories.LeadListFilter, scope repositories.ArchiveScope) (int, error)
+	CountAllWithFilterAndArchiveScope(ctx context.Context, filter repositories.LeadListFilter, scope repositories.ArchiveScope) (int, error)
 }
 
 // clientListRepo is the minimal interface covering the listing methods of
 // ClientRepository that are needed for scope-based access control.
 // *repositories.ClientRepository satisfies this interface (duck typing).
 type clientListRepo interface {
-	ListByOwnerWithFilterAndArchiveScope(ownerID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, error)
-	ListAllWithFilterAndArchiveScope(limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, error)
-	CountWithFilterAndArchiveScope(ownerID *int, forcedType string, filter repositories.ClientListFilter, scope repositories.ArchiveScope) (int, error)
+	ListByOwnerWithFilterAndArchiveScope(ctx context.Context, ownerID, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, error)
+	ListAllWithFilterAndArchiveScope(ctx context.Context, limit, offset int, filter repositories.ClientListFilter, scope repositories.ArchiveScope) ([]*models.Client, error)
+	CountWithFilterAndArchiveScope(ctx context.Context, ownerID *int, forcedType string, filter repositories.ClientListFilter, scope repositories.ArchiveScope) (int, error)
+}
+
+// clientSearchRepo covers the query behind client search.
+// *repositories.ClientRepository satisfies this interface (duck typing).
+type clientSearchRepo interface {
+	SearchByNameOrBIN(ctx context.Context, q string, ownerID, branchID *int, limit, offset int) ([]*models.Client, int, error)
 }
 
 // ─── Scope-based routing helpers ─────────────────────────────────────────────
@@ -136,17 +145,17 @@ type clientListRepo interface {
 // listLeadsForScope executes the appropriate repository call based on the
 // resolved DataScope. Branch and owner filters are applied here so that every
 // call-site stays in sync.
-func listLeadsForScope(repo leadListRepo, scope DataScope, limit, offset int, filter repositories.LeadListFilter, archiveScope repositories.ArchiveScope) ([]*models.Leads, error) {
+func listLeadsForScope(ctx context.Context, repo leadListRepo, scope DataScope, limit, offset int, filter repositories.LeadListFilter, archiveScope repositories.ArchiveScope) ([]*models.Leads, error) {
 	switch scope.Kind {
 	case ScopeKindOwn:
-		return repo.ListByOwnerWithFilterAndArchiveScope(scope.UserID, limit, offset, filter, archiveScope)
+		return repo.ListByOwnerWithFilterAndArchiveScope(ctx, scope.UserID, limit, offset, filter, archiveScope)
 	case ScopeKindBranch:
 		filter.BranchID = scope.BranchID
 		filter.DepartmentID = scope.DepartmentID
 		filter.ScopeUserID = scopeOwnerForDept(scope)
-		return repo.ListAllWithFilterAndArchiveScope(limit, offset, filter, archiveScope)
+		return repo.ListAllWithFilterAndArchiveScope(ctx, limit, offset, filter, archiveScope)
 	default: // ScopeKindAll
-		return repo.ListAllWithFilterAndArchiveScope(limit, offset, filter, archiveScope)
+		return repo.ListAllWithFilterAndArchiveScope(ctx, limit, offset, filter, archiveScope)
 	}
 }
 
@@ -164,48 +173,73 @@ func scopeOwnerForDept(scope DataScope) *int {
 
 // countLeadsForScope executes the appropriate count query based on the
 // resolved DataScope.
-func countLeadsForScope(repo leadListRepo, scope DataScope, filter repositories.LeadListFilter, archiveScope repositories.ArchiveScope) (int, error) {
+func countLeadsForScope(ctx context.Context, repo leadListRepo, scope DataScope, filter repositories.LeadListFilter, archiveScope repositories.ArchiveScope) (int, error) {
 	switch scope.Kind {
 	case ScopeKindOwn:
-		return repo.CountByOwnerWithFilterAndArchiveScope(scope.UserID, filter, archiveScope)
+		return repo.CountByOwnerWithFilterAndArchiveScope(ctx, scope.UserID, filter, archiveScope)
 	case ScopeKindBranch:
 		filter.BranchID = scope.BranchID
 		filter.DepartmentID = scope.DepartmentID
 		filter.ScopeUserID = scopeOwnerForDept(scope)
-		return repo.CountAllWithFilterAndArchiveScope(filter, archiveScope)
+		return repo.CountAllWithFilterAndArchiveScope(ctx, filter, archiveScope)
 	default: // ScopeKindAll
-		return repo.CountAllWithFilterAndArchiveScope(filter, archiveScope)
+		return repo.CountAllWithFilterAndArchiveScope(ctx, filter, archiveScope)
 	}
 }
 
 // listClientsForScope executes the appropriate repository call based on the
 // resolved DataScope. Callers must set filter.ClientType before calling when
 // they need type-filtered results (e.g. individuals / companies).
-func listClientsForScope(repo clientListRepo, scope DataScope, limit, offset int, filter repositories.ClientListFilter, archiveScope repositories.ArchiveScope) ([]*models.Client, error) {
+func listClientsForScope(ctx context.Context, repo clientListRepo, scope DataScope, limit, offset int, filter repositories.ClientListFilter, archiveScope repositories.ArchiveScope) ([]*models.Client, error) {
 	switch scope.Kind {
 	case ScopeKindOwn:
-		return repo.ListByOwnerWithFilterAndArchiveScope(scope.UserID, limit, offset, filter, archiveScope)
+		return repo.ListByOwnerWithFilterAndArchiveScope(ctx, scope.UserID, limit, offset, filter, archiveScope)
 	case ScopeKindBranch:
 		filter.BranchID = scope.BranchID
-		return repo.ListAllWithFilterAndArchiveScope(limit, offset, filter, archiveScope)
+		return repo.ListAllWithFilterAndArchiveScope(ctx, limit, offset, filter, archiveScope)
 	default: // ScopeKindAll
-		return repo.ListAllWithFilterAndArchiveScope(limit, offset, filter, archiveScope)
+		return repo.ListAllWithFilterAndArchiveScope(ctx, limit, offset, filter, archiveScope)
+	}
+}
+
+// searchClientsForScope matches q against the exact BIN and a name substring,
+// keeps active clients visible in scope and returns the requested window plus
+// the total number of matches. Exact BIN hits are listed first.
+func searchClientsForScope(ctx context.Context, repo clientSearchRepo, scope DataScope, q string, limit, offset int) ([]*models.Client, int, error) {
+	q = strings.TrimSpace(q)
+	if q == "" {
+		return []*models.Client{}, 0, nil
+	}
+	var ownerID, branchID *int
+	switch scope.Kind {
+	case ScopeKindAll:
+	case ScopeKindOwn:
+		ownerID = &scope.UserID
+	case ScopeKindBranch:
+		// fail-closed, as in clientMatchesScope
+		if scope.BranchID == nil {
+			return []*models.Client{}, 0, nil
+		}
+		branchID = scope.BranchID
+	default:
+		return []*models.Client{}, 0, nil
 	}
+	return repo.SearchByNameOrBIN(ctx, q, ownerID, branchID, limit, offset)
 }
 
 // countClientsForScope executes the appropriate count query. forcedType (e.g.
 // models.ClientTypeIndividual) is forwarded to the repository as-is; pass ""
 // for all types.
-func countClientsForScope(repo clientListRepo, scope DataScope, forcedType string, filter repositories.ClientListFilter, archiveScope repositories.ArchiveScope) (int, error) {
+func countClientsForScope(ctx context.Context, repo clientListRepo, scope DataScope, forcedType string, filter repositories.ClientListFilter, archiveScope repositories.ArchiveScope) (int, error) {
 	switch scope.Kind {
 	case ScopeKindOwn:
 		ownerID := scope.UserID
-		return repo.CountWithFilterAndArchiveScope(&ownerID, forcedType, filter, archiveScope)
+		return repo.CountWithFilterAndArchiveScope(ctx, &ownerID, forcedType, filter, archiveScope)
 	case ScopeKindBranch:
 		filter.BranchID = scope.BranchID
-		return repo.CountWithFilterAndArchiveScope(nil, forcedType, filter, archiveScope)
+		return repo.CountWithFilterAndArchiveScope(ctx, nil, forcedType, filter, archiveScope)
 	default: // ScopeKindAll
-		return repo.CountWithFilterAndArchiveScope(nil, forcedType, filter, archiveScope)
+		return repo.CountWithFilterAndArchiveScope(ctx, nil, forcedType, filter, archiveScope)
 	}
 }
 
@@ -270,8 +304,8 @@ func clientMatchesScope(scope DataScope, client *models.Client) bool {
 // DealRepository needed for scope-based access control.
 // *repositories.DealRepository satisfies this interface (duck typing).
 type dealListRepo interface {
-	ListAllWithFilterAndArchiveScope(limit, offset int, filter repositories.DealListFilter, scope repositories.ArchiveScope) ([]*models.Deals, error)
-	CountAllWithFilterAndArchiveScope(filter repositories.DealListFilter, scope repositories.ArchiveScope) (int, error)
+	ListAllWithFilterAndArchiveScope(ctx context.Context, limit, offset int, filter repositories.DealListFilter, scope repositories.ArchiveScope) ([]*models.Deals, error)
+	CountAllWithFilterAndArchiveScope(ctx context.Context, filter repositories.DealListFilter, scope repositories.ArchiveScope) (int, error)
 }
 
 // ─── Deal scope ───────────────────────────────────────────────────────────────
@@ -326,22 +360,32 @@ func dealMatchesScope(scope DataScope, deal *models.Deals) bool {
 	}
 }
 
+// dealFilterForRole pins sales to their own deals on top of the branch
+// scope, overriding any owner the caller asked for.
+func dealFilterForRole(userID, roleID int, filter repositories.DealListFilter) repositories.DealListFilter {
+	if roleID == authz.RoleSales {
+		ownerID := userID
+		filter.OwnerID = &ownerID
+	}
+	return filter
+}
+
 // listDealsForScope executes the appropriate repository call based on the
 // resolved DataScope. BranchID is injected into the filter for Branch scope.
-func listDealsForScope(repo dealListRepo, scope DataScope, limit, offset int, filter repositories.DealListFilter, archiveScope repositories.ArchiveScope) ([]*models.Deals, error) {
+func listDealsForScope(ctx context.Context, repo dealListRepo, scope DataScope, limit, offset int, filter repositories.DealListFilter, archiveScope repositories.ArchiveScope) ([]*models.Deals, error) {
 	if scope.Kind == ScopeKindBranch {
 		filter.BranchID = scope.BranchID
 		filter.DepartmentID = scope.DepartmentID
 	}
-	return repo.ListAllWithFilterAndArchiveScope(limit, offset, filter, archiveScope)
+	return repo.ListAllWithFilterAndArchiveScope(ctx, limit, offset, filter, archiveScope)
 }
 
 // countDealsForScope executes the appropriate count query based on the
 // resolved DataScope.
-func countDealsForScope(repo dealListRepo, scope DataScope, filter repositories.DealListFilter, archiveScope repositories.ArchiveScope) (int, error) {
+func countDealsForScope(ctx context.Context, repo dealListRepo, scope DataScope, filter repositories.DealListFilter, archiveScope repositories.ArchiveScope) (int, error) {
 	if scope.Kind == ScopeKindBranch {
 		filter.BranchID = scope.BranchID
 		filter.DepartmentID = scope.DepartmentID
 	}
-	return repo.CountAllWithFilterAndArchiveScope(filter, archiveScope)
+	return repo.CountAllWithFilterAndArchiveScope(ctx, filter, archiveScope)
 }
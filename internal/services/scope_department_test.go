@@ -34,6 +34,11 @@ func (r *deptScopeUserRepoStub) ApplyUserPatch(int, *models.UserApprovalUpdatePa
 }
 func (r *deptScopeUserRepoStub) Update(*models.User) error                   { return nil }
 func (r *deptScopeUserRepoStub) Delete(int) error                            { return nil }
+func (r *deptScopeUserRepoStub) Reactivate(int) error { return nil }
+func (r *deptScopeUserRepoStub) Search(repositories.UserSearchFilter, int, int) ([]*models.User, error) {
+	return nil, nil
+}
+func (r *deptScopeUserRepoStub) CountSearch(repositories.UserSearchFilter) (int, error) { return 0, nil }
 func (r *deptScopeUserRepoStub) List(int, int) ([]*models.User, error)       { return nil, nil }
 func (r *deptScopeUserRepoStub) GetByEmail(string) (*models.User, error)     { return nil, nil }
 func (r *deptScopeUserRepoStub) GetAuthByEmail(string) (*models.User, error) { return nil, nil }
@@ -277,19 +282,19 @@ type captureLeadRepo struct {
 	capturedFilter repositories.LeadListFilter
 }
 
-func (r *captureLeadRepo) ListAllWithFilterAndArchiveScope(_ int, _ int, f repositories.LeadListFilter, _ repositories.ArchiveScope) ([]*models.Leads, error) {
+func (r *captureLeadRepo) ListAllWithFilterAndArchiveScope(_ context.Context, _ int, _ int, f repositories.LeadListFilter, _ repositories.ArchiveScope) ([]*models.Leads, error) {
 	r.capturedFilter = f
 	return nil, nil
 }
-func (r *captureLeadRepo) ListByOwnerWithFilterAndArchiveScope(_ int, _ int, _ int, f repositories.LeadListFilter, _ repositories.ArchiveScope) ([]*models.Leads, error) {
+func (r *captureLeadRepo) ListByOwnerWithFilterAndArchiveScope(_ context.Context, _ int, _ int, _ int, f repositories.LeadListFilter, _ repositories.ArchiveScope) ([]*models.Leads, error) {
 	r.capturedFilter = f
 	return nil, nil
 }
-func (r *captureLeadRepo) CountAllWithFilterAndArchiveScope(f repositories.LeadListFilter, _ repositories.ArchiveScope) (int, error) {
+func (r *captureLeadRepo) CountAllWithFilterAndArchiveScope(_ context.Context, f repositories.LeadListFilter, _ repositories.ArchiveScope) (int, error) {
 	r.capturedFilter = f
 	return 0, nil
 }
-func (r *captureLeadRepo) CountByOwnerWithFilterAndArchiveScope(_ int, f repositories.LeadListFilter, _ repositories.ArchiveScope) (int, error) {
+func (r *captureLeadRepo) CountByOwnerWithFilterAndArchiveScope(_ context.Context, _ int, f repositories.LeadListFilter, _ repositories.ArchiveScope) (int, error) {
 	r.capturedFilter = f
 	return 0, nil
 }
@@ -297,7 +302,7 @@ func (r *captureLeadRepo) CountByOwnerWithFilterAndArchiveScope(_ int, f reposit
 func TestListLeadsForScope_BranchScope_InjectsDepartmentID(t *testing.T) {
 	repo := &captureLeadRepo{}
 	scope := DataScope{Kind: ScopeKindBranch, BranchID: intPtr(1), DepartmentID: intPtr(2)}
-	_, _ = listLeadsForScope(repo, scope, 10, 0, repositories.LeadListFilter{}, repositories.ArchiveScopeActiveOnly)
+	_, _ = listLeadsForScope(context.Background(), repo, scope, 10, 0, repositories.LeadListFilter{}, repositories.ArchiveScopeActiveOnly)
 	if repo.capturedFilter.DepartmentID == nil || *repo.capturedFilter.DepartmentID != 2 {
 		t.Errorf("filter.DepartmentID: want 2, got %v", repo.capturedFilter.DepartmentID)
 	}
@@ -306,7 +311,7 @@ func TestListLeadsForScope_BranchScope_InjectsDepartmentID(t *testing.T) {
 func TestListLeadsForScope_AllScope_NoDepartmentID(t *testing.T) {
 	repo := &captureLeadRepo{}
 	scope := DataScope{Kind: ScopeKindAll}
-	_, _ = listLeadsForScope(repo, scope, 10, 0, repositories.LeadListFilter{}, repositories.ArchiveScopeActiveOnly)
+	_, _ = listLeadsForScope(context.Background(), repo, scope, 10, 0, repositories.LeadListFilter{}, repositories.ArchiveScopeActiveOnly)
 	if repo.capturedFilter.DepartmentID != nil {
 		t.Errorf("All scope must not inject DepartmentID, got %v", repo.capturedFilter.DepartmentID)
 	}
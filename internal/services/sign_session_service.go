@@ -64,10 +64,10 @@ type SignSessionConfig struct {
 }
 
 type SignDocumentService interface {
-	EnsureSigningAllowed(docID int64, userID, roleID int) error
-	FinalizeSigning(docID int64) error
-	FinalizeSignedArtifact(session *models.SignSession) error
-	StampSessionSignature(docID int64, signatureImageBase64 string) error
+	EnsureSigningAllowed(ctx context.Context, docID int64, userID, roleID int) error
+	FinalizeSigning(ctx context.Context, docID int64) error
+	FinalizeSignedArtifact(ctx context.Context, session *models.SignSession) error
+	StampSessionSignature(ctx context.Context, docID int64, signatureImageBase64 string) error
 }
 
 type SignSessionService struct {
@@ -126,13 +126,13 @@ func (s *SignSessionService) Create(ctx context.Context, documentID int64, phone
 	if s.docService == nil {
 		return "", "", nil, errors.New("document service unavailable")
 	}
-	if err := s.docService.EnsureSigningAllowed(documentID, userID, roleID); err != nil {
-		switch err.Error() {
-		case "not found":
+	if err := s.docService.EnsureSigningAllowed(ctx, documentID, userID, roleID); err != nil {
+		switch {
+		case errors.Is(err, ErrNotFound):
 			return "", "", nil, ErrSignSessionDocNotFound
-		case "forbidden":
+		case errors.Is(err, ErrForbidden):
 			return "", "", nil, ErrSignSessionForbidden
-		case "invalid status":
+		case errors.Is(err, ErrInvalidStatus):
 			return "", "", nil, ErrSignSessionInvalidStatus
 		default:
 			return "", "", nil, err
@@ -319,14 +319,14 @@ func (s *SignSessionService) Sign(ctx context.Context, token, ip, userAgent stri
 	session.SignedIP = ip
 	session.SignedUserAgent = userAgent
 
-	if err := s.docService.FinalizeSignedArtifact(session); err != nil {
+	if err := s.docService.FinalizeSignedArtifact(ctx, session); err != nil {
 		if errors.Is(err, ErrDocumentChangedAfterOTP) {
 			s.expireSession(ctx, session, "document_changed_after_otp")
 		}
 		return nil, err
 	}
 
-	if err := s.docService.FinalizeSigning(session.DocumentID); err != nil {
+	if err := s.docService.FinalizeSigning(ctx, session.DocumentID); err != nil {
 		return nil, err
 	}
 	session.Status = "signed"
@@ -363,17 +363,17 @@ func (s *SignSessionService) SignByID(ctx context.Context, sessionID int64, toke
 	if s.docService == nil {
 		return nil, errors.New("document service unavailable")
 	}
-	if err := s.docService.FinalizeSignedArtifact(session); err != nil {
+	if err := s.docService.FinalizeSignedArtifact(ctx, session); err != nil {
 		if errors.Is(err, ErrDocumentChangedAfterOTP) {
 			s.expireSession(ctx, session, "document_changed_after_otp")
 		}
 		return nil, err
 	}
-	if err := s.docService.FinalizeSigning(session.DocumentID); err != nil {
-		switch err.Error() {
-		case "not found":
+	if err := s.docService.FinalizeSigning(ctx, session.DocumentID); err != nil {
+		switch {
+		case errors.Is(err, ErrNotFound):
 			return nil, ErrSignSessionDocNotFound
-		case "invalid status":
+		case errors.Is(err, ErrInvalidStatus):
 			return nil, ErrSignSessionInvalidStatus
 		default:
 			return nil, err
@@ -387,7 +387,7 @@ func (s *SignSessionService) SignByID(ctx context.Context, sessionID int64, toke
 	s.logSessionState("signed", session, "signed_success")
 
 	if strings.TrimSpace(signatureImage) != "" {
-		if stampErr := s.docService.StampSessionSignature(session.DocumentID, signatureImage); stampErr != nil {
+		if stampErr := s.docService.StampSessionSignature(ctx, session.DocumentID, signatureImage); stampErr != nil {
 			log.Printf("[sign][session][stamp] document_id=%d err=%v", session.DocumentID, stampErr)
 		}
 	}
@@ -66,16 +66,16 @@ type fakeDocService struct {
 	finalizeArtifactCalls int
 }
 
-func (f *fakeDocService) EnsureSigningAllowed(int64, int, int) error { return nil }
-func (f *fakeDocService) FinalizeSigning(int64) error {
+func (f *fakeDocService) EnsureSigningAllowed(context.Context, int64, int, int) error { return nil }
+func (f *fakeDocService) FinalizeSigning(context.Context, int64) error {
 	f.finalizeCalls++
 	return nil
 }
-func (f *fakeDocService) FinalizeSignedArtifact(*models.SignSession) error {
+func (f *fakeDocService) FinalizeSignedArtifact(context.Context, *models.SignSession) error {
 	f.finalizeArtifactCalls++
 	return nil
 }
-func (f *fakeDocService) StampSessionSignature(int64, string) error { return nil }
+func (f *fakeDocService) StampSessionSignature(context.Context, int64, string) error { return nil }
 
 type fakeConfirmRepo struct {
 	createdExpiresAt time.Time
@@ -133,6 +133,11 @@ func (f *fakeUserRepo) GetByID(id int) (*models.User, error) {
 }
 func (f *fakeUserRepo) Update(*models.User) error                      { return nil }
 func (f *fakeUserRepo) Delete(int) error                               { return nil }
+func (f *fakeUserRepo) Reactivate(int) error { return nil }
+func (f *fakeUserRepo) Search(repositories.UserSearchFilter, int, int) ([]*models.User, error) {
+	return nil, nil
+}
+func (f *fakeUserRepo) CountSearch(repositories.UserSearchFilter) (int, error) { return 0, nil }
 func (f *fakeUserRepo) List(limit, offset int) ([]*models.User, error) { return nil, nil }
 func (f *fakeUserRepo) GetByEmail(string) (*models.User, error)        { return nil, nil }
 func (f *fakeUserRepo) GetAuthByEmail(string) (*models.User, error)    { return nil, nil }
@@ -160,7 +165,7 @@ func (f *fakeUserRepo) GetDepartmentIDByCode(string) (*int, error)
 
 type fakeDocLookup struct{}
 
-func (f *fakeDocLookup) GetByID(id int64) (*models.Document, error) {
+func (f *fakeDocLookup) GetByID(_ context.Context, id int64) (*models.Document, error) {
 	return &models.Document{ID: id, DocType: "contract"}, nil
 }
 
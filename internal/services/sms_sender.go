@@ -23,11 +23,22 @@ var (
 	ErrSMSEmptyText       = errors.New("sms text is empty")
 	ErrSMSTimeout         = errors.New("sms request timeout")
 	ErrSMSProviderFailure = errors.New("sms provider returned error")
+	ErrSMSStatusNotFound  = errors.New("sms status not found")
 )
 
 const (
 	defaultMobizonAPIURL     = "https://api.mobizon.kz/service"
 	defaultMobizonRequestURI = "/message/sendsmsmessage"
+	mobizonStatusRequestURI  = "/message/getsmsstatus"
+	mobizonDryRunMessageID   = "dry-run"
+)
+
+// Normalized SMS delivery statuses returned by SMSStatusChecker.
+const (
+	SMSStatusPending   = "pending"
+	SMSStatusDelivered = "delivered"
+	SMSStatusFailed    = "failed"
+	SMSStatusUnknown   = "unknown"
 )
 
 type SMSMessage struct {
@@ -44,13 +55,29 @@ type SMSSender interface {
 	Send(ctx context.Context, msg SMSMessage) (*SMSResult, error)
 }
 
+// SMSDeliveryStatus is the provider's view of a previously sent message.
+type SMSDeliveryStatus struct {
+	ProviderMessageID string `json:"provider_message_id"`
+	Provider          string `json:"provider"`
+	Status            string `json:"status"`
+	ProviderStatus    string `json:"provider_status,omitempty"`
+}
+
+// SMSStatusChecker looks up the delivery status of a sent message by the
+// provider message id stored from SMSResult.
+type SMSStatusChecker interface {
+	GetStatus(ctx context.Context, messageID string) (*SMSDeliveryStatus, error)
+}
+
 type MobizonSMSConfig struct {
 	Enabled      bool
 	APIKey       string
 	BaseURL      string
 	From         string
+	TextPrefix   string // prepended to every message, e.g. the sender name
 	Timeout      time.Duration
 	Retries      int
+	RetryDelay   time.Duration
 	DryRun       bool
 	ProviderName string
 	RequestPath  string
@@ -77,6 +104,9 @@ func NewMobizonSMSClient(cfg MobizonSMSConfig) *MobizonSMSClient {
 	if cfg.Retries < 0 {
 		cfg.Retries = 0
 	}
+	if cfg.RetryDelay <= 0 {
+		cfg.RetryDelay = 300 * time.Millisecond
+	}
 	return &MobizonSMSClient{httpClient: &http.Client{Timeout: cfg.Timeout}, cfg: cfg}
 }
 
@@ -96,9 +126,10 @@ func (m *MobizonSMSClient) Send(ctx context.Context, msg SMSMessage) (*SMSResult
 	if text == "" {
 		return nil, ErrSMSEmptyText
 	}
+	text = m.composeText(text)
 	if m.cfg.DryRun {
 		log.Printf("[sms][%s][dry_run] to=%s text_len=%d", m.cfg.ProviderName, redactPhoneForLog(to), len(text))
-		return &SMSResult{Provider: m.cfg.ProviderName, ProviderMessageID: "dry-run"}, nil
+		return &SMSResult{Provider: m.cfg.ProviderName, ProviderMessageID: mobizonDryRunMessageID}, nil
 	}
 	apiKey := strings.TrimSpace(m.cfg.APIKey)
 	if apiKey == "" {
@@ -117,55 +148,189 @@ func (m *MobizonSMSClient) Send(ctx context.Context, msg SMSMessage) (*SMSResult
 	}
 	requestBody := form.Encode()
 
-	var lastErr error
-	for attempt := 0; attempt <= m.cfg.Retries; attempt++ {
-		req, reqErr := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(requestBody))
-		if reqErr != nil {
-			return nil, fmt.Errorf("%w: build request: %v", ErrSMSSendFailed, reqErr)
+	delay := m.cfg.RetryDelay
+	var lastErr *SMSSendError
+	for attempt := 1; attempt <= m.cfg.Retries+1; attempt++ {
+		providerID, sendErr := m.sendOnce(ctx, endpoint, requestBody)
+		if sendErr == nil {
+			log.Printf("[sms][%s][send] status=ok to=%s provider_message_id=%s text_len=%d attempts=%d", m.cfg.ProviderName, redactPhoneForLog(to), providerID, len(text), attempt)
+			return &SMSResult{Provider: m.cfg.ProviderName, ProviderMessageID: providerID}, nil
 		}
-		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-		req.Header.Set("Cache-Control", "no-cache")
-
-		resp, doErr := m.httpClient.Do(req)
-		if doErr != nil {
-			if isTimeoutError(doErr) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
-				lastErr = fmt.Errorf("%w: %v", ErrSMSTimeout, doErr)
-			} else {
-				lastErr = fmt.Errorf("%w: request failed: %v", ErrSMSSendFailed, doErr)
-			}
-			if attempt < m.cfg.Retries {
-				continue
-			}
+		sendErr.Attempts = attempt
+		lastErr = sendErr
+		if !sendErr.Transient || attempt > m.cfg.Retries {
 			break
 		}
-		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
-		_ = resp.Body.Close()
-		if resp.StatusCode >= http.StatusBadRequest {
-			lastErr = fmt.Errorf("%w: http %d", ErrSMSProviderFailure, resp.StatusCode)
-			if attempt < m.cfg.Retries && shouldRetrySMSStatus(resp.StatusCode) {
-				continue
-			}
-			break
+		select {
+		case <-ctx.Done():
+		case <-time.After(delay):
 		}
-		providerID, providerErr := parseMobizonResponse(body)
-		if providerErr != nil {
-			lastErr = providerErr
+		if ctx.Err() != nil {
 			break
 		}
-		log.Printf("[sms][%s][send] status=ok to=%s provider_message_id=%s text_len=%d", m.cfg.ProviderName, redactPhoneForLog(to), providerID, len(text))
-		return &SMSResult{Provider: m.cfg.ProviderName, ProviderMessageID: providerID}, nil
+		delay *= 2
 	}
-	log.Printf("[sms][%s][send] status=failed to=%s attempts=%d err=%v", m.cfg.ProviderName, redactPhoneForLog(to), m.cfg.Retries+1, lastErr)
+	log.Printf("[sms][%s][send] status=failed to=%s attempts=%d transient=%v err=%v", m.cfg.ProviderName, redactPhoneForLog(to), lastErr.Attempts, lastErr.Transient, lastErr.Err)
 	return nil, lastErr
 }
 
+// GetStatus asks Mobizon for the delivery status of messageID. Messages sent in
+// dry-run mode never reach the provider and are reported as delivered.
+func (m *MobizonSMSClient) GetStatus(ctx context.Context, messageID string) (*SMSDeliveryStatus, error) {
+	if m == nil || !m.cfg.Enabled {
+		return nil, ErrSMSSendDisabled
+	}
+	messageID = strings.TrimSpace(messageID)
+	if messageID == "" {
+		return nil, ErrSMSStatusNotFound
+	}
+	if m.cfg.DryRun || messageID == mobizonDryRunMessageID {
+		return &SMSDeliveryStatus{
+			ProviderMessageID: messageID,
+			Provider:          m.cfg.ProviderName,
+			Status:            SMSStatusDelivered,
+		}, nil
+	}
+	apiKey := strings.TrimSpace(m.cfg.APIKey)
+	if apiKey == "" {
+		return nil, ErrSMSAPIKeyMissing
+	}
+	endpoint, err := mobizonEndpointURL(m.cfg.BaseURL, mobizonStatusRequestURI, apiKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid api url: %v", ErrSMSSendFailed, err)
+	}
+	form := url.Values{}
+	form.Set("ids[0]", messageID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("%w: build request: %v", ErrSMSSendFailed, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Cache-Control", "no-cache")
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		if isTimeoutError(err) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w: %v", ErrSMSTimeout, err)
+		}
+		return nil, fmt.Errorf("%w: request failed: %v", ErrSMSSendFailed, err)
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	_ = resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("%w: http %d", ErrSMSProviderFailure, resp.StatusCode)
+	}
+	providerStatus, err := parseMobizonStatusResponse(body, messageID)
+	if err != nil {
+		return nil, err
+	}
+	return &SMSDeliveryStatus{
+		ProviderMessageID: messageID,
+		Provider:          m.cfg.ProviderName,
+		Status:            normalizeMobizonStatus(providerStatus),
+		ProviderStatus:    providerStatus,
+	}, nil
+}
+
+// composeText returns the text sent to the provider: the caller's text as is,
+// preceded by the configured prefix when one is set.
+func (m *MobizonSMSClient) composeText(text string) string {
+	prefix := strings.TrimSpace(m.cfg.TextPrefix)
+	if prefix == "" {
+		return text
+	}
+	return prefix + " " + text
+}
+
+// sendOnce performs a single Mobizon request and classifies its failure.
+func (m *MobizonSMSClient) sendOnce(ctx context.Context, endpoint, requestBody string) (string, *SMSSendError) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(requestBody))
+	if err != nil {
+		return "", &SMSSendError{Err: fmt.Errorf("%w: build request: %v", ErrSMSSendFailed, err)}
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Cache-Control", "no-cache")
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		if isTimeoutError(err) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", &SMSSendError{Transient: true, Err: fmt.Errorf("%w: %v", ErrSMSTimeout, err)}
+		}
+		return "", &SMSSendError{Transient: true, Err: fmt.Errorf("%w: request failed: %v", ErrSMSSendFailed, err)}
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	_ = resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return "", &SMSSendError{
+			Transient:  shouldRetrySMSStatus(resp.StatusCode),
+			StatusCode: resp.StatusCode,
+			Err:        fmt.Errorf("%w: http %d", ErrSMSProviderFailure, resp.StatusCode),
+		}
+	}
+	providerID, code, err := parseMobizonResponse(body)
+	if err != nil {
+		return "", &SMSSendError{Transient: mobizonTransientCodes[code], StatusCode: resp.StatusCode, Code: code, Err: err}
+	}
+	return providerID, nil
+}
+
+// SMSSendError is the final error of MobizonSMSClient.Send once the request
+// reached the network. Transient tells the caller whether queuing the message
+// for a later attempt may succeed; the wrapped error is one of the ErrSMS*
+// sentinels, so errors.Is keeps working.
+type SMSSendError struct {
+	Transient  bool
+	StatusCode int // HTTP status, 0 when no response arrived
+	Code       int // Mobizon response code, 0 when not reached
+	Attempts   int
+	Err        error
+}
+
+func (e *SMSSendError) Error() string { return e.Err.Error() }
+
+func (e *SMSSendError) Unwrap() error { return e.Err }
+
+// IsTransientSMSError reports whether a failed send is worth retrying later.
+func IsTransientSMSError(err error) bool {
+	var sendErr *SMSSendError
+	return errors.As(err, &sendErr) && sendErr.Transient
+}
+
+// mobizonTransientCodes are the Mobizon response codes treated as
+// provider-side (retryable) failures; every other non-zero code, such as
+// validation, auth or balance errors, permanently rejects the message.
+var mobizonTransientCodes = map[int]bool{
+	99:  true,
+	999: true,
+}
+
 type mobizonSendResponse struct {
 	Code    int             `json:"code"`
 	Message string          `json:"message"`
 	Data    json.RawMessage `json:"data"`
 }
 
-func parseMobizonResponse(body []byte) (string, error) {
+func parseMobizonResponse(body []byte) (string, int, error) {
+	if len(body) == 0 {
+		return "", 0, fmt.Errorf("%w: empty response", ErrSMSProviderFailure)
+	}
+	var payload mobizonSendResponse
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", 0, fmt.Errorf("%w: invalid json response", ErrSMSProviderFailure)
+	}
+	if payload.Code != 0 {
+		msg := strings.TrimSpace(payload.Message)
+		if msg == "" {
+			msg = "provider rejected sms"
+		}
+		return "", payload.Code, fmt.Errorf("%w: code=%d message=%s", ErrSMSProviderFailure, payload.Code, msg)
+	}
+	return parseMobizonMessageID(payload.Data), 0, nil
+}
+
+// parseMobizonStatusResponse extracts the raw status of messageID from a
+// getsmsstatus response, whose data is a list of {id, status} entries.
+func parseMobizonStatusResponse(body []byte, messageID string) (string, error) {
 	if len(body) == 0 {
 		return "", fmt.Errorf("%w: empty response", ErrSMSProviderFailure)
 	}
@@ -176,11 +341,37 @@ func parseMobizonResponse(body []byte) (string, error) {
 	if payload.Code != 0 {
 		msg := strings.TrimSpace(payload.Message)
 		if msg == "" {
-			msg = "provider rejected sms"
+			msg = "provider rejected status request"
 		}
 		return "", fmt.Errorf("%w: code=%d message=%s", ErrSMSProviderFailure, payload.Code, msg)
 	}
-	return parseMobizonMessageID(payload.Data), nil
+	var entries []struct {
+		ID     json.Number `json:"id"`
+		Status string      `json:"status"`
+	}
+	if err := json.Unmarshal(payload.Data, &entries); err != nil {
+		return "", fmt.Errorf("%w: invalid status data", ErrSMSProviderFailure)
+	}
+	for _, entry := range entries {
+		if strings.TrimSpace(entry.ID.String()) == messageID {
+			return strings.ToUpper(strings.TrimSpace(entry.Status)), nil
+		}
+	}
+	return "", ErrSMSStatusNotFound
+}
+
+// normalizeMobizonStatus maps Mobizon SMPP statuses onto SMSStatus* values.
+func normalizeMobizonStatus(status string) string {
+	switch status {
+	case "DELIVRD":
+		return SMSStatusDelivered
+	case "NEW", "ENQUEUD", "ACCEPTD", "PDLIVRD":
+		return SMSStatusPending
+	case "UNDELIV", "REJECTD", "EXPIRED", "DELETED":
+		return SMSStatusFailed
+	default:
+		return SMSStatusUnknown
+	}
 }
 
 func parseMobizonMessageID(body []byte) string {
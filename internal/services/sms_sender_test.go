@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -133,3 +134,165 @@ func TestMobizonSMSClientMapsTimeout(t *testing.T) {
 		t.Fatalf("expected timeout, got %v", err)
 	}
 }
+
+func TestMobizonSMSClientRetriesAfterTimeout(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			time.Sleep(100 * time.Millisecond)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"code":0,"data":{"messageId":"77"}}`))
+	}))
+	defer ts.Close()
+
+	client := NewMobizonSMSClient(MobizonSMSConfig{
+		Enabled: true, APIKey: "k", BaseURL: ts.URL,
+		Timeout: 20 * time.Millisecond, Retries: 2, RetryDelay: time.Millisecond,
+	})
+	res, err := client.Send(context.Background(), SMSMessage{To: "77001234567", Text: "test"})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if res.ProviderMessageID != "77" {
+		t.Fatalf("message id = %q", res.ProviderMessageID)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("calls = %d, want 2", got)
+	}
+}
+
+func TestMobizonSMSClientPermanentCodeNotRetried(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_, _ = w.Write([]byte(`{"code":1,"message":"bad recipient"}`))
+	}))
+	defer ts.Close()
+
+	client := NewMobizonSMSClient(MobizonSMSConfig{Enabled: true, APIKey: "k", BaseURL: ts.URL, Timeout: time.Second, Retries: 2, RetryDelay: time.Millisecond})
+	_, err := client.Send(context.Background(), SMSMessage{To: "77001234567", Text: "test"})
+	var sendErr *SMSSendError
+	if !errors.As(err, &sendErr) || sendErr.Code != 1 || sendErr.Attempts != 1 {
+		t.Fatalf("expected permanent SMSSendError after one attempt, got %#v", err)
+	}
+	if IsTransientSMSError(err) {
+		t.Fatalf("code 1 must not be transient")
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("calls = %d, want 1", got)
+	}
+}
+
+func TestMobizonSMSClientTransientErrorAfterRetries(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	client := NewMobizonSMSClient(MobizonSMSConfig{Enabled: true, APIKey: "k", BaseURL: ts.URL, Timeout: time.Second, Retries: 1, RetryDelay: time.Millisecond})
+	_, err := client.Send(context.Background(), SMSMessage{To: "77001234567", Text: "test"})
+	if !IsTransientSMSError(err) || !errors.Is(err, ErrSMSProviderFailure) {
+		t.Fatalf("expected transient provider failure, got %v", err)
+	}
+}
+
+func TestMobizonSMSClientSendsProvidedTextWithOptionalPrefix(t *testing.T) {
+	const text = "Код подтверждения подписи: 123456. Никому не сообщайте."
+	cases := []struct {
+		name   string
+		prefix string
+		want   string
+	}{
+		{name: "no prefix", want: text},
+		{name: "prefix", prefix: " KUB ", want: "KUB " + text},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got string
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := r.ParseForm(); err != nil {
+					t.Fatalf("ParseForm: %v", err)
+				}
+				got = r.Form.Get("text")
+				_, _ = w.Write([]byte(`{"code":0,"data":{"messageId":1}}`))
+			}))
+			defer ts.Close()
+
+			client := NewMobizonSMSClient(MobizonSMSConfig{
+				Enabled:    true,
+				APIKey:     "secret-key",
+				BaseURL:    ts.URL + "/service",
+				TextPrefix: tc.prefix,
+				Timeout:    time.Second,
+			})
+			if _, err := client.Send(context.Background(), SMSMessage{To: "+77001234567", Text: text}); err != nil {
+				t.Fatalf("Send() error = %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("text = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMobizonSMSClientGetStatusParsesProviderPayload(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/service/message/getsmsstatus" {
+			t.Fatalf("path = %s", r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("ParseForm: %v", err)
+		}
+		if got := r.Form.Get("ids[0]"); got == "" {
+			t.Fatal("ids[0] is empty")
+		}
+		_, _ = w.Write([]byte(`{"code":0,"data":[{"id":"122","status":"UNDELIV"},{"id":"123","status":"DELIVRD","segNum":"1"}],"message":""}`))
+	}))
+	defer ts.Close()
+
+	client := NewMobizonSMSClient(MobizonSMSConfig{
+		Enabled: true,
+		APIKey:  "secret-key",
+		BaseURL: ts.URL + "/service",
+		Timeout: time.Second,
+	})
+	status, err := client.GetStatus(context.Background(), "123")
+	if err != nil {
+		t.Fatalf("GetStatus() error = %v", err)
+	}
+	if status.Status != SMSStatusDelivered || status.ProviderStatus != "DELIVRD" || status.ProviderMessageID != "123" {
+		t.Fatalf("unexpected status: %#v", status)
+	}
+
+	if _, err := client.GetStatus(context.Background(), "999"); !errors.Is(err, ErrSMSStatusNotFound) {
+		t.Fatalf("GetStatus(unknown id) error = %v, want ErrSMSStatusNotFound", err)
+	}
+}
+
+func TestMobizonSMSClientGetStatusDryRunIsDelivered(t *testing.T) {
+	client := NewMobizonSMSClient(MobizonSMSConfig{Enabled: true, DryRun: true})
+	status, err := client.GetStatus(context.Background(), "dry-run")
+	if err != nil {
+		t.Fatalf("GetStatus() error = %v", err)
+	}
+	if status.Status != SMSStatusDelivered {
+		t.Fatalf("status = %q, want delivered", status.Status)
+	}
+}
+
+func TestNormalizeMobizonStatus(t *testing.T) {
+	cases := map[string]string{
+		"DELIVRD": SMSStatusDelivered,
+		"ENQUEUD": SMSStatusPending,
+		"UNDELIV": SMSStatusFailed,
+		"EXPIRED": SMSStatusFailed,
+		"WHATEVR": SMSStatusUnknown,
+	}
+	for in, want := range cases {
+		if got := normalizeMobizonStatus(in); got != want {
+			t.Fatalf("normalizeMobizonStatus(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"turcompany/internal/models"
+)
+
+// TaskAgenda groups open tasks by due date for GET /tasks/agenda.
+type TaskAgenda struct {
+	Overdue  []models.Task `json:"overdue"`
+	Today    []models.Task `json:"today"`
+	ThisWeek []models.Task `json:"this_week"`
+	Later    []models.Task `json:"later"`
+}
+
+// BuildTaskAgenda buckets tasks by calendar day in now's location, the same
+// way the Telegram digest labels them: a task due earlier today is still
+// "today", this_week runs up to Sunday, and tasks without a due date go to
+// later. Done and cancelled tasks are dropped; input order is kept.
+func BuildTaskAgenda(tasks []models.Task, now time.Time) TaskAgenda {
+	agenda := TaskAgenda{
+		Overdue:  []models.Task{},
+		Today:    []models.Task{},
+		ThisWeek: []models.Task{},
+		Later:    []models.Task{},
+	}
+	// Дней до воскресенья включительно: неделя начинается с понедельника.
+	weekLeft := 6 - (int(now.Weekday())+6)%7
+	for _, tsk := range activeDigestTasks(tasks) {
+		if tsk.DueDate == nil {
+			agenda.Later = append(agenda.Later, tsk)
+			continue
+		}
+		switch days := taskDueDays(*tsk.DueDate, now); {
+		case days < 0:
+			agenda.Overdue = append(agenda.Overdue, tsk)
+		case days == 0:
+			agenda.Today = append(agenda.Today, tsk)
+		case days <= weekLeft:
+			agenda.ThisWeek = append(agenda.ThisWeek, tsk)
+		default:
+			agenda.Later = append(agenda.Later, tsk)
+		}
+	}
+	return agenda
+}
+
+// taskDueDays counts calendar days from now to due in now's location;
+// negative when the due day has passed.
+func taskDueDays(due, now time.Time) int {
+	d := due.In(now.Location())
+	// Сравниваем даты в UTC, чтобы переход на летнее время не давал 23-часовых суток.
+	dueDay := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return int(dueDay.Sub(today).Hours() / 24)
+}
+
+// TaskSummary is the home-screen view for GET /users/me/tasks/summary.
+type TaskSummary struct {
+	Open     int                       `json:"open"`
+	Overdue  int                       `json:"overdue"`
+	ByStatus map[models.TaskStatus]int `json:"by_status"`
+	NextDue  []models.Task             `json:"next_due"`
+}
+
+// BuildTaskSummary counts open tasks per status and picks the limit soonest
+// due ones. Due dates in NextDue are moved to now's location so clients get
+// them in the server timezone; tasks without a due date are only counted.
+func BuildTaskSummary(tasks []models.Task, now time.Time, limit int) TaskSummary {
+	summary := TaskSummary{
+		ByStatus: map[models.TaskStatus]int{
+			models.StatusNew:        0,
+			models.StatusInProgress: 0,
+		},
+		NextDue: []models.Task{},
+	}
+	dated := make([]models.Task, 0, len(tasks))
+	for _, tsk := range activeDigestTasks(tasks) {
+		summary.Open++
+		summary.ByStatus[tsk.Status]++
+		if tsk.DueDate == nil {
+			continue
+		}
+		if tsk.DueDate.Before(now) {
+			summary.Overdue++
+		}
+		due := tsk.DueDate.In(now.Location())
+		tsk.DueDate = &due
+		dated = append(dated, tsk)
+	}
+	sort.SliceStable(dated, func(i, j int) bool {
+		return dated[i].DueDate.Before(*dated[j].DueDate)
+	})
+	if len(dated) > limit {
+		dated = dated[:limit]
+	}
+	summary.NextDue = append(summary.NextDue, dated...)
+	return summary
+}
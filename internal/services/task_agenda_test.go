@@ -0,0 +1,102 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"turcompany/internal/models"
+)
+
+func TestBuildTaskAgenda_BucketsAroundDayBoundaries(t *testing.T) {
+	loc := time.FixedZone("UTC+5", 5*60*60)
+	// Среда, 11.03.2026 10:00 по местному времени.
+	now := time.Date(2026, 3, 11, 10, 0, 0, 0, loc)
+	due := func(v time.Time) *time.Time { return &v }
+	local := func(day, hour, min int) *time.Time { return due(time.Date(2026, 3, day, hour, min, 0, 0, loc)) }
+
+	tasks := []models.Task{
+		{ID: 1, Status: models.StatusNew, DueDate: local(10, 23, 59)},                                   // вчера
+		{ID: 2, Status: models.StatusInProgress, DueDate: local(11, 0, 0)},                              // сегодня, уже прошло
+		{ID: 3, Status: models.StatusNew, DueDate: local(11, 23, 59)},                                   // сегодня
+		{ID: 4, Status: models.StatusNew, DueDate: due(time.Date(2026, 3, 11, 19, 30, 0, 0, time.UTC))}, // 00:30 четверга по местному
+		{ID: 5, Status: models.StatusNew, DueDate: local(15, 23, 59)},                                   // воскресенье
+		{ID: 6, Status: models.StatusNew, DueDate: local(16, 0, 0)},                                     // следующий понедельник
+		{ID: 7, Status: models.StatusNew},                                                               // без срока
+		{ID: 8, Status: models.StatusDone, DueDate: local(10, 12, 0)},                                   // закрыта
+		{ID: 9, Status: models.StatusNew, DueDate: due(time.Date(2026, 3, 10, 19, 30, 0, 0, time.UTC))}, // 00:30 среды по местному
+	}
+
+	agenda := BuildTaskAgenda(tasks, now)
+	assertIDs := func(name string, got []models.Task, want ...int64) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("%s: got %d tasks %v, want %v", name, len(got), taskIDs(got), want)
+		}
+		for i := range want {
+			if got[i].ID != want[i] {
+				t.Fatalf("%s: got %v, want %v", name, taskIDs(got), want)
+			}
+		}
+	}
+	assertIDs("overdue", agenda.Overdue, 1)
+	assertIDs("today", agenda.Today, 2, 3, 9)
+	assertIDs("this_week", agenda.ThisWeek, 4, 5)
+	assertIDs("later", agenda.Later, 6, 7)
+}
+
+func TestBuildTaskAgenda_SundayEndsTheWeek(t *testing.T) {
+	loc := time.FixedZone("UTC+5", 5*60*60)
+	now := time.Date(2026, 3, 15, 22, 0, 0, 0, loc) // воскресенье
+	monday := time.Date(2026, 3, 16, 0, 0, 0, 0, loc)
+
+	agenda := BuildTaskAgenda([]models.Task{{ID: 1, Status: models.StatusNew, DueDate: &monday}}, now)
+	if len(agenda.Later) != 1 || len(agenda.ThisWeek) != 0 {
+		t.Fatalf("monday must fall into later on sunday, got this_week=%v later=%v", taskIDs(agenda.ThisWeek), taskIDs(agenda.Later))
+	}
+	if agenda.Overdue == nil || agenda.Today == nil || agenda.ThisWeek == nil {
+		t.Fatal("empty buckets must be non-nil so they render as []")
+	}
+}
+
+func taskIDs(tasks []models.Task) []int64 {
+	ids := make([]int64, 0, len(tasks))
+	for _, tsk := range tasks {
+		ids = append(ids, tsk.ID)
+	}
+	return ids
+}
+
+func TestBuildTaskSummary_CountsAndOrdersNextDue(t *testing.T) {
+	loc := time.FixedZone("UTC+5", 5*60*60)
+	now := time.Date(2026, 3, 11, 10, 0, 0, 0, loc)
+	due := func(v time.Time) *time.Time { return &v }
+
+	tasks := []models.Task{
+		{ID: 1, Status: models.StatusNew, DueDate: due(time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC))},
+		{ID: 2, Status: models.StatusInProgress, DueDate: due(time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC))}, // просрочена
+		{ID: 3, Status: models.StatusNew}, // без срока
+		{ID: 4, Status: models.StatusInProgress, DueDate: due(time.Date(2026, 3, 12, 9, 0, 0, 0, time.UTC))},
+		{ID: 5, Status: models.StatusDone, DueDate: due(time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC))}, // закрыта
+		{ID: 6, Status: models.StatusNew, DueDate: due(time.Date(2026, 3, 20, 9, 0, 0, 0, time.UTC))},
+	}
+
+	summary := BuildTaskSummary(tasks, now, 3)
+	if summary.Open != 5 || summary.Overdue != 1 {
+		t.Fatalf("expected open=5 overdue=1, got open=%d overdue=%d", summary.Open, summary.Overdue)
+	}
+	if summary.ByStatus[models.StatusNew] != 3 || summary.ByStatus[models.StatusInProgress] != 2 {
+		t.Fatalf("unexpected by_status: %v", summary.ByStatus)
+	}
+	if _, ok := summary.ByStatus[models.StatusDone]; ok {
+		t.Fatalf("closed statuses must not be counted: %v", summary.ByStatus)
+	}
+	if got := taskIDs(summary.NextDue); len(got) != 3 || got[0] != 2 || got[1] != 4 || got[2] != 1 {
+		t.Fatalf("expected next_due [2 4 1], got %v", got)
+	}
+	if summary.NextDue[0].DueDate.Location() != loc {
+		t.Fatalf("expected due date in server timezone, got %v", summary.NextDue[0].DueDate.Location())
+	}
+	if tasks[1].DueDate.Location() != time.UTC {
+		t.Fatal("input tasks must not be modified")
+	}
+}
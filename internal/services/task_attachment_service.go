@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"time"
+
+	"turcompany/internal/models"
+	"turcompany/internal/repositories"
+	"turcompany/internal/storage"
+)
+
+// MaxTaskAttachmentSize caps a single task attachment upload.
+const MaxTaskAttachmentSize = 10 << 20
+
+// TaskAttachmentService stores files attached to tasks under tasks/<id>/ in
+// the file storage. Access to the task itself is checked by the caller.
+type TaskAttachmentService struct {
+	repo  repositories.TaskAttachmentRepository
+	store storage.Storage
+}
+
+func NewTaskAttachmentService(repo repositories.TaskAttachmentRepository, store storage.Storage) *TaskAttachmentService {
+	return &TaskAttachmentService{repo: repo, store: store}
+}
+
+// Upload validates the file (size, name, type) and saves it for taskID.
+func (s *TaskAttachmentService) Upload(ctx context.Context, taskID int64, userID int, file *multipart.FileHeader) (*models.TaskAttachment, error) {
+	if file == nil {
+		return nil, ErrFileRequired
+	}
+	if file.Size > MaxTaskAttachmentSize {
+		return nil, ErrTaskAttachmentTooLarge
+	}
+	safeName, ext, err := sanitizeAttachmentName(file.Filename)
+	if err != nil {
+		return nil, ErrInvalidFilename
+	}
+	mimeType, ok := allowedAttachmentTypes()[ext]
+	if !ok {
+		return nil, ErrTaskAttachmentTypeNotAllowed
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open upload file: %w", err)
+	}
+	defer src.Close()
+
+	key := fmt.Sprintf("tasks/%d/%d_%s", taskID, time.Now().UnixNano(), safeName)
+	if err := s.store.Save(ctx, src, key); err != nil {
+		return nil, fmt.Errorf("save task attachment: %w", err)
+	}
+
+	uploadedBy := int64(userID)
+	att := &models.TaskAttachment{
+		TaskID:     taskID,
+		FileName:   safeName,
+		MimeType:   mimeType,
+		SizeBytes:  file.Size,
+		StorageKey: key,
+		UploadedBy: &uploadedBy,
+	}
+	if err := s.repo.Create(ctx, att); err != nil {
+		_ = s.store.Delete(ctx, key)
+		return nil, err
+	}
+	return att, nil
+}
+
+func (s *TaskAttachmentService) List(ctx context.Context, taskID int64) ([]models.TaskAttachment, error) {
+	return s.repo.ListByTask(ctx, taskID)
+}
+
+// Open returns the attachment and its content. An attachment that belongs
+// to another task is reported as ErrFileNotFound.
+func (s *TaskAttachmentService) Open(ctx context.Context, taskID, attachmentID int64) (*models.TaskAttachment, io.ReadSeekCloser, error) {
+	att, err := s.repo.GetByID(ctx, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if att == nil || att.TaskID != taskID {
+		return nil, nil, ErrFileNotFound
+	}
+	r, _, err := s.store.Open(ctx, att.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return att, r, nil
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"log"
+	"strings"
 	"time"
 
 	"turcompany/internal/authz"
@@ -25,22 +26,70 @@ type TaskService interface {
 	UnarchiveTask(ctx context.Context, id int64, userID int64, roleID int) (*models.Task, error)
 
 	// NEW:
-	UpdateStatus(ctx context.Context, id int64, to models.TaskStatus) (*models.Task, error)
-	UpdateAssignee(ctx context.Context, id int64, assigneeID int64) (*models.Task, error)
+	UpdateStatus(ctx context.Context, id int64, to models.TaskStatus, actorID int64) (*models.Task, error)
+	// UpdateStatusBatch applies already-validated transitions; see
+	// repositories.TaskRepository.UpdateStatusBatch for the semantics.
+	UpdateStatusBatch(ctx context.Context, changes []repositories.TaskStatusChange, to models.TaskStatus, actorID int64) ([]*models.Task, map[int64]error, error)
+	UpdateAssignee(ctx context.Context, id int64, assigneeID int64, actorID int64) (*models.Task, error)
+	// ReassignOpen moves every open task of fromUser to toUser and returns
+	// the ids of the moved tasks.
+	ReassignOpen(ctx context.Context, fromUser, toUser int64) ([]int64, error)
 }
 
+// taskService only stores tasks; notifications about them are sent by
+// TaskHandler in the background once the write has committed.
 type taskService struct {
 	repo  repositories.TaskRepository
 	users repositories.UserRepository
-	tg    *TelegramService
 }
 
 // NewTaskService creates a new instance of TaskService.
-func NewTaskService(repo repositories.TaskRepository, users repositories.UserRepository, tg *TelegramService) TaskService {
-	return &taskService{repo: repo, users: users, tg: tg}
+func NewTaskService(repo repositories.TaskRepository, users repositories.UserRepository) TaskService {
+	return &taskService{repo: repo, users: users}
+}
+
+// taskEntityTypes is the set of entities a task may be linked to.
+var taskEntityTypes = map[string]struct{}{
+	"lead":     {},
+	"deal":     {},
+	"client":   {},
+	"document": {},
+}
+
+// normalizeTaskEntity trims and lower-cases entity_type and checks it against
+// taskEntityTypes. Every task is linked to an entity (the columns are NOT
+// NULL), so an empty type is rejected as well.
+func normalizeTaskEntity(task *models.Task) error {
+	task.EntityType = strings.ToLower(strings.TrimSpace(task.EntityType))
+	if _, ok := taskEntityTypes[task.EntityType]; !ok {
+		return ErrInvalidTaskEntityType
+	}
+	if task.EntityID <= 0 {
+		return ErrTaskEntityIDRequired
+	}
+	return nil
+}
+
+// deriveReminder keeps an offset-based reminder in step with the due date:
+// ReminderAt becomes DueDate minus the offset, or nil once the due date is
+// cleared. Tasks without an offset keep their explicit ReminderAt.
+func deriveReminder(task *models.Task) {
+	if task.ReminderOffset == nil {
+		return
+	}
+	if task.DueDate == nil {
+		task.ReminderAt = nil
+		return
+	}
+	at := task.DueDate.Add(-time.Duration(*task.ReminderOffset) * time.Second)
+	task.ReminderAt = &at
 }
 
 func (s *taskService) Create(ctx context.Context, task *models.Task) (*models.Task, error) {
+	if err := normalizeTaskEntity(task); err != nil {
+		return nil, err
+	}
+	deriveReminder(task)
 	if task.Status == "" {
 		task.Status = models.StatusNew
 	}
@@ -53,6 +102,13 @@ func (s *taskService) Create(ctx context.Context, task *models.Task) (*models.Ta
 	if task.AssigneeID == 0 {
 		task.AssigneeID = task.CreatorID
 	}
+	assignees := task.AssigneeIDs
+	if len(assignees) == 0 {
+		assignees = []int64{task.AssigneeID}
+	}
+	if err := s.ensureActiveAssignees(assignees...); err != nil {
+		return nil, err
+	}
 	if task.BranchID == nil && s.users != nil {
 		if u, err := s.users.GetByID(int(task.CreatorID)); err == nil && u != nil && u.BranchID != nil {
 			b := int64(*u.BranchID)
@@ -64,7 +120,6 @@ func (s *taskService) Create(ctx context.Context, task *models.Task) (*models.Ta
 		return nil, err
 	}
 
-	s.notifyTaskCreated(ctx, task)
 	return task, nil
 }
 
@@ -101,24 +156,80 @@ func (s *taskService) Update(ctx context.Context, id int64, updateData *models.T
 		return nil, nil
 	}
 
-	// Прокидываем все поля, которые реально обновляет repo.Update
-	existingTask.AssigneeID = updateData.AssigneeID
-	existingTask.AssigneeIDs = updateData.AssigneeIDs
-	existingTask.Title = updateData.Title
-	existingTask.Description = updateData.Description
-	existingTask.DueDate = updateData.DueDate
-	existingTask.ReminderAt = updateData.ReminderAt
-	existingTask.Priority = updateData.Priority
-	existingTask.Status = updateData.Status
+	// Only newly added assignees are checked, so a task whose current
+	// assignee was deactivated can still be edited.
+	if err := s.ensureActiveAssignees(addedAssignees(existingTask, updateData)...); err != nil {
+		return nil, err
+	}
+	if err := applyTaskUpdate(existingTask, updateData); err != nil {
+		return nil, err
+	}
+	// The caller's version (from If-Match or the payload) wins over the one we
+	// just read, so an edit based on a stale copy is rejected.
+	if updateData.Version > 0 {
+		existingTask.Version = updateData.Version
+	}
 
 	existingTask.UpdatedAt = time.Now()
 
 	if err := s.repo.Update(ctx, existingTask); err != nil {
+		if errors.Is(err, repositories.ErrTaskVersionConflict) {
+			return nil, ErrTaskVersionConflict
+		}
 		return nil, err
 	}
 	return existingTask, nil
 }
 
+// applyTaskUpdate copies the mutable fields of src onto dst. The set matches
+// the columns taskRepository.Update writes; creator, branch, creation time
+// and archive state are immutable here and keep their stored values.
+// src.LastModifiedBy carries the acting user.
+func applyTaskUpdate(dst, src *models.Task) error {
+	dst.LastModifiedBy = src.LastModifiedBy
+	dst.AssigneeID = src.AssigneeID
+	dst.AssigneeIDs = src.AssigneeIDs
+	dst.Title = src.Title
+	dst.Description = src.Description
+	dst.DueDate = src.DueDate
+	dst.ReminderAt = src.ReminderAt
+	dst.ReminderOffset = src.ReminderOffset
+	deriveReminder(dst)
+	dst.Priority = src.Priority
+	dst.Status = src.Status
+	// Only a changed link is validated, so tasks saved before the check
+	// existed can still be edited.
+	if src.EntityType != dst.EntityType || src.EntityID != dst.EntityID {
+		dst.EntityType = src.EntityType
+		dst.EntityID = src.EntityID
+		if err := normalizeTaskEntity(dst); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addedAssignees returns the assignees of next that current does not have.
+func addedAssignees(current, next *models.Task) []int64 {
+	had := make(map[int64]bool, len(current.AssigneeIDs)+1)
+	had[current.AssigneeID] = true
+	for _, id := range current.AssigneeIDs {
+		had[id] = true
+	}
+	ids := next.AssigneeIDs
+	if len(ids) == 0 && next.AssigneeID != 0 {
+		ids = []int64{next.AssigneeID}
+	}
+	var added []int64
+	for _, id := range ids {
+		if !had[id] {
+			had[id] = true
+			added = append(added, id)
+		}
+	}
+	return added
+}
+
 func (s *taskService) Delete(ctx context.Context, id int64, userID int64, roleID int) error {
 	if !authz.CanHardDeleteBusinessEntity(roleID) {
 		return ErrForbidden
@@ -128,7 +239,7 @@ func (s *taskService) Delete(ctx context.Context, id int64, userID int64, roleID
 		return err
 	}
 	if task == nil {
-		return errors.New("task not found")
+		return ErrNotFound
 	}
 	return s.repo.Delete(ctx, id)
 }
@@ -142,7 +253,7 @@ func (s *taskService) ArchiveTask(ctx context.Context, id int64, userID int64, r
 		return nil, err
 	}
 	if task == nil {
-		return nil, errors.New("task not found")
+		return nil, ErrNotFound
 	}
 	if task.IsArchived {
 		return task, nil
@@ -162,7 +273,7 @@ func (s *taskService) UnarchiveTask(ctx context.Context, id int64, userID int64,
 		return nil, err
 	}
 	if task == nil {
-		return nil, errors.New("task not found")
+		return nil, ErrNotFound
 	}
 	if !task.IsArchived {
 		return nil, ErrNotArchived
@@ -173,84 +284,86 @@ func (s *taskService) UnarchiveTask(ctx context.Context, id int64, userID int64,
 	return s.repo.FindByID(ctx, id)
 }
 
-func (s *taskService) UpdateStatus(ctx context.Context, id int64, to models.TaskStatus) (*models.Task, error) {
+func (s *taskService) UpdateStatus(ctx context.Context, id int64, to models.TaskStatus, actorID int64) (*models.Task, error) {
 	// (валидацию переходов делает handler; сервис просто пишет)
-	if err := s.repo.UpdateStatus(ctx, id, to); err != nil {
-		return nil, err
-	}
-	updated, err := s.repo.FindByID(ctx, id)
-	if err != nil || updated == nil {
-		return updated, err
-	}
-	if to == models.StatusDone {
-		s.notifyTaskCompleted(ctx, updated)
-	}
-	return updated, nil
-}
-
-func (s *taskService) UpdateAssignee(ctx context.Context, id int64, assigneeID int64) (*models.Task, error) {
-	if err := s.repo.UpdateAssignee(ctx, id, assigneeID); err != nil {
+	if err := s.repo.UpdateStatus(ctx, id, to, actorID); err != nil {
 		return nil, err
 	}
 	return s.repo.FindByID(ctx, id)
 }
 
-const dueSoonThreshold = 24 * time.Hour
-
-func (s *taskService) notifyTaskCreated(ctx context.Context, task *models.Task) {
-	if s.tg == nil || s.users == nil || task == nil {
-		return
-	}
-	if task.DueDate == nil {
-		return
+func (s *taskService) UpdateStatusBatch(ctx context.Context, changes []repositories.TaskStatusChange, to models.TaskStatus, actorID int64) ([]*models.Task, map[int64]error, error) {
+	failed, err := s.repo.UpdateStatusBatch(ctx, changes, to, actorID)
+	if err != nil {
+		return nil, nil, err
 	}
-
-	if d := time.Until(*task.DueDate); d < 0 || d > dueSoonThreshold {
-		return
+	// Перечитываем только применённые задачи; уведомления шлёт handler в фоне.
+	updated := make([]*models.Task, 0, len(changes))
+	for _, ch := range changes {
+		if failed[ch.ID] != nil {
+			continue
+		}
+		task, err := s.repo.FindByID(ctx, ch.ID)
+		if err != nil || task == nil {
+			// статус уже сохранён; без перечитанной задачи пропускаем только уведомление
+			log.Printf("[task][batch_status] reload id=%d after commit: %v", ch.ID, err)
+			continue
+		}
+		updated = append(updated, task)
 	}
-	s.sendNotification(ctx, task, "📌 Новая задача")
+	return updated, failed, nil
 }
 
-func (s *taskService) notifyTaskCompleted(ctx context.Context, task *models.Task) {
-	if s.tg == nil || s.users == nil || task == nil {
-		return
+func (s *taskService) UpdateAssignee(ctx context.Context, id int64, assigneeID int64, actorID int64) (*models.Task, error) {
+	if err := s.ensureActiveAssignees(assigneeID); err != nil {
+		return nil, err
 	}
-	s.sendNotification(ctx, task, "✅ Задача выполнена")
+	if err := s.repo.UpdateAssignee(ctx, id, assigneeID, actorID); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, id)
 }
 
-func (s *taskService) sendNotification(ctx context.Context, task *models.Task, prefix string) {
-	if s.tg == nil || s.users == nil || task == nil {
-		return
-	}
-	msg := prefix + "\n" + s.tg.FormatTaskNotification(task)
-	for _, assigneeID := range taskAssigneeRecipients(task) {
-		chatID, notify, err := s.users.GetTelegramSettings(ctx, assigneeID)
-		if err != nil {
-			log.Printf("[task][notify] failed to get telegram settings for assignee=%d: %v", assigneeID, err)
-			continue
+// ensureActiveAssignees returns ErrInvalidTaskAssignee unless every id is an
+// existing, active user. Without a user repository nothing is checked.
+func (s *taskService) ensureActiveAssignees(ids ...int64) error {
+	if s.users == nil {
+		return nil
+	}
+	for _, id := range ids {
+		u, err := s.users.GetByID(int(id))
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrInvalidTaskAssignee
 		}
-		if !notify || chatID == 0 {
-			continue
+		if err != nil {
+			return err
 		}
-		if err := s.tg.SendMessage(chatID, msg); err != nil {
-			log.Printf("[task][notify] telegram send error: %v", err)
+		if u == nil || !u.IsActive {
+			return ErrInvalidTaskAssignee
 		}
 	}
+	return nil
 }
 
-// taskAssigneeRecipients returns the de-duplicated set of assignee user ids to
-// notify, falling back to the legacy single assignee when the list is empty.
-func taskAssigneeRecipients(task *models.Task) []int64 {
-	seen := map[int64]bool{}
-	out := []int64{}
-	for _, id := range task.AssigneeIDs {
-		if id != 0 && !seen[id] {
-			seen[id] = true
-			out = append(out, id)
+// ReassignOpen covers the tasks fromUser is assigned to that are still new or
+// in progress; done, cancelled and archived ones stay with fromUser.
+func (s *taskService) ReassignOpen(ctx context.Context, fromUser, toUser int64) ([]int64, error) {
+	if err := s.ensureActiveAssignees(toUser); err != nil {
+		return nil, err
+	}
+	tasks, err := s.repo.FindAll(ctx, models.TaskFilter{AssigneeID: &fromUser})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, 0, len(tasks))
+	for _, t := range tasks {
+		if t.Status == models.StatusDone || t.Status == models.StatusCancelled {
+			continue
 		}
+		ids = append(ids, t.ID)
 	}
-	if len(out) == 0 && task.AssigneeID != 0 {
-		out = append(out, task.AssigneeID)
+	if len(ids) == 0 {
+		return nil, nil
 	}
-	return out
+	return s.repo.ReassignTasks(ctx, ids, fromUser, toUser)
 }
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"turcompany/internal/models"
+	"turcompany/internal/repositories"
+)
+
+type taskAssigneeUsersStub struct {
+	repositories.UserRepository
+	users map[int]*models.User
+}
+
+func (s taskAssigneeUsersStub) GetByID(id int) (*models.User, error) {
+	if u, ok := s.users[id]; ok {
+		return u, nil
+	}
+	return nil, fmt.Errorf("user %d: %w", id, repositories.ErrNotFound)
+}
+
+type taskAssigneeRepoStub struct {
+	taskEntityRepoStub
+	assignee int64
+}
+
+func (r *taskAssigneeRepoStub) UpdateAssignee(_ context.Context, _ int64, assigneeID, _ int64) error {
+	r.assignee = assigneeID
+	return nil
+}
+
+func TestTaskService_ValidatesAssignees(t *testing.T) {
+	users := taskAssigneeUsersStub{users: map[int]*models.User{
+		1: {ID: 1, IsActive: true},
+		2: {ID: 2, IsActive: true},
+		3: {ID: 3, IsActive: false},
+	}}
+	repo := &taskAssigneeRepoStub{taskEntityRepoStub: taskEntityRepoStub{current: &models.Task{ID: 1}}}
+	svc := NewTaskService(repo, users)
+	ctx := context.Background()
+
+	if _, err := svc.Create(ctx, &models.Task{CreatorID: 1, Title: "call", EntityType: "deal", EntityID: 5, AssigneeID: 2, AssigneeIDs: []int64{2}}); err != nil {
+		t.Fatalf("valid assignee: %v", err)
+	}
+	if repo.stored == nil || repo.stored.AssigneeID != 2 {
+		t.Fatalf("expected task stored for assignee 2, got %+v", repo.stored)
+	}
+
+	repo.stored = nil
+	for _, ids := range [][]int64{{99}, {2, 3}} {
+		_, err := svc.Create(ctx, &models.Task{CreatorID: 1, Title: "call", EntityType: "deal", EntityID: 5, AssigneeID: ids[0], AssigneeIDs: ids})
+		if !errors.Is(err, ErrInvalidTaskAssignee) {
+			t.Fatalf("assignees %v: expected ErrInvalidTaskAssignee, got %v", ids, err)
+		}
+	}
+	if repo.stored != nil {
+		t.Fatal("task with an invalid assignee must not be stored")
+	}
+
+	if _, err := svc.UpdateAssignee(ctx, 1, 2, 1); err != nil || repo.assignee != 2 {
+		t.Fatalf("valid reassignment: err=%v assignee=%d", err, repo.assignee)
+	}
+	if _, err := svc.UpdateAssignee(ctx, 1, 99, 1); !errors.Is(err, ErrInvalidTaskAssignee) {
+		t.Fatalf("unknown user: expected ErrInvalidTaskAssignee, got %v", err)
+	}
+	if _, err := svc.UpdateAssignee(ctx, 1, 3, 1); !errors.Is(err, ErrInvalidTaskAssignee) || repo.assignee != 2 {
+		t.Fatalf("deactivated user: expected ErrInvalidTaskAssignee and no write, got %v assignee=%d", err, repo.assignee)
+	}
+}
+
+func TestTaskServiceUpdate_ValidatesAddedAssignees(t *testing.T) {
+	users := taskAssigneeUsersStub{users: map[int]*models.User{
+		2: {ID: 2, IsActive: true},
+		3: {ID: 3, IsActive: false},
+	}}
+	current := &models.Task{ID: 1, Title: "call", EntityType: "deal", EntityID: 5, AssigneeID: 3, AssigneeIDs: []int64{3}, Version: 1}
+	repo := &taskAssigneeRepoStub{taskEntityRepoStub: taskEntityRepoStub{current: current}}
+	svc := NewTaskService(repo, users)
+	ctx := context.Background()
+
+	// The deactivated assignee already on the task does not block other edits.
+	keep := *current
+	keep.Title = "call back"
+	if _, err := svc.Update(ctx, 1, &keep); err != nil {
+		t.Fatalf("edit with unchanged assignees: %v", err)
+	}
+
+	for _, ids := range [][]int64{{3, 99}, {2, 4}} {
+		repo.updated = nil
+		change := *current
+		change.AssigneeID, change.AssigneeIDs = ids[0], ids
+		if _, err := svc.Update(ctx, 1, &change); !errors.Is(err, ErrInvalidTaskAssignee) {
+			t.Fatalf("assignees %v: expected ErrInvalidTaskAssignee, got %v", ids, err)
+		}
+		if repo.updated != nil {
+			t.Fatalf("assignees %v: task must not be saved", ids)
+		}
+	}
+
+	change := *current
+	change.AssigneeID, change.AssigneeIDs = 2, []int64{2}
+	if _, err := svc.Update(ctx, 1, &change); err != nil {
+		t.Fatalf("valid new assignee: %v", err)
+	}
+}
+
+func TestTaskServiceReassignOpen_RejectsInactiveTarget(t *testing.T) {
+	users := taskAssigneeUsersStub{users: map[int]*models.User{3: {ID: 3, IsActive: false}}}
+	repo := &reassignTaskRepo{tasks: map[int64]*models.Task{1: {ID: 1, AssigneeID: 7, Status: models.StatusNew}}}
+	svc := NewTaskService(repo, users)
+
+	for _, to := range []int64{3, 99} {
+		if _, err := svc.ReassignOpen(context.Background(), 7, to); !errors.Is(err, ErrInvalidTaskAssignee) {
+			t.Fatalf("to_user %d: expected ErrInvalidTaskAssignee, got %v", to, err)
+		}
+	}
+	if repo.tasks[1].AssigneeID != 7 {
+		t.Fatal("tasks must stay with the original assignee")
+	}
+}
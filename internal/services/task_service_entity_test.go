@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"turcompany/internal/models"
+	"turcompany/internal/repositories"
+)
+
+type taskEntityRepoStub struct {
+	repositories.TaskRepository
+	stored  *models.Task
+	current *models.Task
+	updated *models.Task
+}
+
+func (r *taskEntityRepoStub) Store(_ context.Context, task *models.Task) error {
+	task.ID = 1
+	r.stored = task
+	return nil
+}
+
+func (r *taskEntityRepoStub) FindByID(context.Context, int64) (*models.Task, error) {
+	cp := *r.current
+	return &cp, nil
+}
+
+func (r *taskEntityRepoStub) Update(_ context.Context, task *models.Task) error {
+	r.updated = task
+	return nil
+}
+
+func TestTaskServiceCreate_RejectsUnknownEntityType(t *testing.T) {
+	repo := &taskEntityRepoStub{}
+	svc := NewTaskService(repo, nil)
+
+	_, err := svc.Create(context.Background(), &models.Task{CreatorID: 1, Title: "call", EntityType: "deel", EntityID: 5})
+	if !errors.Is(err, ErrInvalidTaskEntityType) {
+		t.Fatalf("expected ErrInvalidTaskEntityType, got %v", err)
+	}
+	if repo.stored != nil {
+		t.Fatalf("task must not be stored")
+	}
+}
+
+func TestTaskServiceCreate_RejectsEmptyEntityType(t *testing.T) {
+	repo := &taskEntityRepoStub{}
+	svc := NewTaskService(repo, nil)
+
+	_, err := svc.Create(context.Background(), &models.Task{CreatorID: 1, Title: "call", EntityType: "  "})
+	if !errors.Is(err, ErrInvalidTaskEntityType) {
+		t.Fatalf("expected ErrInvalidTaskEntityType, got %v", err)
+	}
+	if repo.stored != nil {
+		t.Fatalf("task must not be stored")
+	}
+}
+
+func TestTaskServiceCreate_RequiresEntityIDForType(t *testing.T) {
+	repo := &taskEntityRepoStub{}
+	svc := NewTaskService(repo, nil)
+
+	_, err := svc.Create(context.Background(), &models.Task{CreatorID: 1, Title: "call", EntityType: "deal"})
+	if !errors.Is(err, ErrTaskEntityIDRequired) {
+		t.Fatalf("expected ErrTaskEntityIDRequired, got %v", err)
+	}
+}
+
+func TestTaskServiceCreate_NormalizesEntityType(t *testing.T) {
+	repo := &taskEntityRepoStub{}
+	svc := NewTaskService(repo, nil)
+
+	if _, err := svc.Create(context.Background(), &models.Task{CreatorID: 1, Title: "call", EntityType: " Deal ", EntityID: 5}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.stored.EntityType != "deal" {
+		t.Fatalf("expected normalized entity_type, got %q", repo.stored.EntityType)
+	}
+}
+
+func TestTaskServiceCreate_AcceptsDocumentLink(t *testing.T) {
+	repo := &taskEntityRepoStub{}
+	svc := NewTaskService(repo, nil)
+
+	if _, err := svc.Create(context.Background(), &models.Task{CreatorID: 1, Title: "review contract", EntityType: "document", EntityID: 8}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.stored.EntityType != "document" || repo.stored.EntityID != 8 {
+		t.Fatalf("expected document link, got %q/%d", repo.stored.EntityType, repo.stored.EntityID)
+	}
+}
+
+func TestTaskServiceUpdate_ValidatesChangedEntity(t *testing.T) {
+	repo := &taskEntityRepoStub{current: &models.Task{ID: 1, Title: "call", EntityType: "legacy", EntityID: 3, Version: 1}}
+	svc := NewTaskService(repo, nil)
+
+	// An untouched legacy link does not block other edits.
+	unchanged := *repo.current
+	unchanged.Title = "call back"
+	if _, err := svc.Update(context.Background(), 1, &unchanged); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	changed := *repo.current
+	changed.EntityType = "client"
+	changed.EntityID = 0
+	if _, err := svc.Update(context.Background(), 1, &changed); !errors.Is(err, ErrTaskEntityIDRequired) {
+		t.Fatalf("expected ErrTaskEntityIDRequired, got %v", err)
+	}
+
+	unlinked := *repo.current
+	unlinked.EntityType = ""
+	unlinked.EntityID = 0
+	if _, err := svc.Update(context.Background(), 1, &unlinked); !errors.Is(err, ErrInvalidTaskEntityType) {
+		t.Fatalf("expected ErrInvalidTaskEntityType for an empty entity_type, got %v", err)
+	}
+}
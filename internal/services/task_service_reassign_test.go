@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"turcompany/internal/models"
+	"turcompany/internal/repositories"
+)
+
+// reassignTaskRepo keeps tasks in memory; FindAll honours the assignee
+// filter and skips archived tasks like the default list scope does.
+type reassignTaskRepo struct {
+	repositories.TaskRepository
+	tasks map[int64]*models.Task
+}
+
+func (r *reassignTaskRepo) FindAll(_ context.Context, f models.TaskFilter) ([]models.Task, error) {
+	var out []models.Task
+	for _, t := range r.tasks {
+		if t.IsArchived || (f.AssigneeID != nil && t.AssigneeID != *f.AssigneeID) {
+			continue
+		}
+		out = append(out, *t)
+	}
+	return out, nil
+}
+
+func (r *reassignTaskRepo) ReassignTasks(_ context.Context, ids []int64, from, to int64) ([]int64, error) {
+	var moved []int64
+	for _, id := range ids {
+		if t := r.tasks[id]; t != nil && t.AssigneeID == from {
+			t.AssigneeID = to
+			moved = append(moved, id)
+		}
+	}
+	return moved, nil
+}
+
+func TestTaskServiceReassignOpen_MovesOnlyActiveTasks(t *testing.T) {
+	seed := func(id, assignee int64, st models.TaskStatus) *models.Task {
+		return &models.Task{ID: id, AssigneeID: assignee, Status: st}
+	}
+	repo := &reassignTaskRepo{tasks: map[int64]*models.Task{
+		1: seed(1, 7, models.StatusNew),
+		2: seed(2, 7, models.StatusInProgress),
+		3: seed(3, 7, models.StatusDone),
+		4: seed(4, 7, models.StatusCancelled),
+		5: seed(5, 8, models.StatusNew),
+		6: seed(6, 7, models.StatusNew),
+	}}
+	repo.tasks[6].IsArchived = true
+
+	moved, err := NewTaskService(repo, nil).ReassignOpen(context.Background(), 7, 9)
+	if err != nil {
+		t.Fatalf("ReassignOpen: %v", err)
+	}
+	sort.Slice(moved, func(i, j int) bool { return moved[i] < moved[j] })
+	if len(moved) != 2 || moved[0] != 1 || moved[1] != 2 {
+		t.Fatalf("expected tasks 1 and 2 to be reported as moved, got %v", moved)
+	}
+	var nowWith9 []int64
+	for id, task := range repo.tasks {
+		if task.AssigneeID == 9 {
+			nowWith9 = append(nowWith9, id)
+		}
+	}
+	sort.Slice(nowWith9, func(i, j int) bool { return nowWith9[i] < nowWith9[j] })
+	if len(nowWith9) != 2 || nowWith9[0] != 1 || nowWith9[1] != 2 {
+		t.Fatalf("only open tasks 1 and 2 must move, got %v", nowWith9)
+	}
+	if repo.tasks[5].AssigneeID != 8 {
+		t.Fatal("another user's task must stay untouched")
+	}
+
+	if moved, err := NewTaskService(repo, nil).ReassignOpen(context.Background(), 7, 9); err != nil || len(moved) != 0 {
+		t.Fatalf("second run must find nothing to move, got (%v, %v)", moved, err)
+	}
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"turcompany/internal/models"
+)
+
+func TestTaskServiceCreate_DerivesReminderFromOffset(t *testing.T) {
+	repo := &taskEntityRepoStub{}
+	svc := NewTaskService(repo, nil)
+	due := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	offset := int64(24 * 3600)
+
+	if _, err := svc.Create(context.Background(), &models.Task{CreatorID: 1, Title: "call", EntityType: "deal", EntityID: 5, DueDate: &due, ReminderOffset: &offset}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := due.Add(-24 * time.Hour)
+	if repo.stored.ReminderAt == nil || !repo.stored.ReminderAt.Equal(want) {
+		t.Fatalf("expected reminder_at %s, got %v", want, repo.stored.ReminderAt)
+	}
+}
+
+func TestTaskServiceUpdate_DerivedReminderFollowsDueDate(t *testing.T) {
+	due := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	rem := due.Add(-time.Hour)
+	offset := int64(3600)
+	repo := &taskEntityRepoStub{current: &models.Task{ID: 1, Title: "call", DueDate: &due, ReminderAt: &rem, ReminderOffset: &offset, Version: 1}}
+	svc := NewTaskService(repo, nil)
+
+	moved := *repo.current
+	newDue := due.Add(48 * time.Hour)
+	moved.DueDate = &newDue
+	if _, err := svc.Update(context.Background(), 1, &moved); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := newDue.Add(-time.Hour); repo.updated.ReminderAt == nil || !repo.updated.ReminderAt.Equal(want) {
+		t.Fatalf("expected reminder moved to %s, got %v", want, repo.updated.ReminderAt)
+	}
+
+	cleared := *repo.current
+	cleared.DueDate = nil
+	if _, err := svc.Update(context.Background(), 1, &cleared); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.updated.ReminderAt != nil {
+		t.Fatalf("clearing due date must clear a derived reminder, got %v", repo.updated.ReminderAt)
+	}
+}
+
+func TestTaskServiceUpdate_ExplicitReminderKeptWithoutDueDate(t *testing.T) {
+	due := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	rem := due.Add(-time.Hour)
+	repo := &taskEntityRepoStub{current: &models.Task{ID: 1, Title: "call", DueDate: &due, ReminderAt: &rem, Version: 1}}
+	svc := NewTaskService(repo, nil)
+
+	cleared := *repo.current
+	cleared.DueDate = nil
+	if _, err := svc.Update(context.Background(), 1, &cleared); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.updated.ReminderAt == nil || !repo.updated.ReminderAt.Equal(rem) {
+		t.Fatalf("explicit reminder must survive clearing the due date, got %v", repo.updated.ReminderAt)
+	}
+}
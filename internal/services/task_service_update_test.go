@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"turcompany/internal/models"
+	"turcompany/internal/repositories"
+)
+
+// taskRoundTripRepoStub keeps a single stored row and, like
+// taskRepository.Update, persists only the mutable columns.
+type taskRoundTripRepoStub struct {
+	repositories.TaskRepository
+	row models.Task
+}
+
+func (r *taskRoundTripRepoStub) FindByID(context.Context, int64) (*models.Task, error) {
+	cp := r.row
+	return &cp, nil
+}
+
+func (r *taskRoundTripRepoStub) Update(_ context.Context, task *models.Task) error {
+	r.row.AssigneeID = task.AssigneeID
+	r.row.AssigneeIDs = task.AssigneeIDs
+	r.row.Title = task.Title
+	r.row.Description = task.Description
+	r.row.DueDate = task.DueDate
+	r.row.ReminderAt = task.ReminderAt
+	r.row.Priority = task.Priority
+	r.row.Status = task.Status
+	r.row.UpdatedAt = task.UpdatedAt
+	r.row.EntityID = task.EntityID
+	r.row.EntityType = task.EntityType
+	r.row.ReminderOffset = task.ReminderOffset
+	r.row.LastModifiedBy = task.LastModifiedBy
+	r.row.Version++
+	task.Version = r.row.Version
+	return nil
+}
+
+func TestTaskServiceUpdate_RoundTripChangesOnlyIntendedFields(t *testing.T) {
+	due := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	branch := int64(3)
+	archivedBy := int64(8)
+	original := models.Task{
+		ID:            1,
+		CreatorID:     7,
+		AssigneeID:    9,
+		AssigneeIDs:   []int64{9},
+		BranchID:      &branch,
+		EntityID:      4,
+		EntityType:    "deal",
+		Title:         "call",
+		Description:   "first call",
+		DueDate:       &due,
+		Priority:      models.PriorityNormal,
+		Status:        models.StatusNew,
+		CreatedAt:     due.Add(-48 * time.Hour),
+		UpdatedAt:     due.Add(-48 * time.Hour),
+		Version:       2,
+		ArchivedBy:    &archivedBy,
+		ArchiveReason: "kept",
+	}
+	repo := &taskRoundTripRepoStub{row: original}
+	svc := NewTaskService(repo, nil)
+
+	// The payload is a pre-merged copy, as the handler builds it, carrying a
+	// new title plus values for fields that must stay immutable.
+	otherBranch := int64(99)
+	payload := original
+	payload.Title = "call back"
+	payload.CreatorID = 42
+	payload.BranchID = &otherBranch
+	payload.CreatedAt = time.Now()
+	payload.ArchiveReason = "changed"
+
+	returned, err := svc.Update(context.Background(), 1, &payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stored, _ := repo.FindByID(context.Background(), 1)
+	if !reflect.DeepEqual(returned, stored) {
+		t.Fatalf("service result diverges from stored row:\nreturned=%+v\nstored=%+v", returned, stored)
+	}
+
+	want := original
+	want.Title = "call back"
+	want.Version = 3
+	want.UpdatedAt = stored.UpdatedAt
+	if !stored.UpdatedAt.After(original.UpdatedAt) {
+		t.Fatalf("expected updated_at to advance, got %v", stored.UpdatedAt)
+	}
+	if !reflect.DeepEqual(*stored, want) {
+		t.Fatalf("unexpected stored task:\ngot=%+v\nwant=%+v", *stored, want)
+	}
+}
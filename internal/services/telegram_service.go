@@ -12,8 +12,10 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf16"
 
 	"turcompany/internal/models"
 	"turcompany/internal/repositories"
@@ -23,6 +25,8 @@ type TelegramService struct {
 	token      string
 	baseURL    string
 	client     *http.Client
+	retries    int
+	retryDelay time.Duration
 	linkRepo   repositories.TelegramLinkRepository
 	usersRepo  repositories.UserRepository
 	taskSvc    TaskService
@@ -45,8 +49,32 @@ type tgResp struct {
 	Ok          bool            `json:"ok"`
 	Description string          `json:"description"`
 	Result      json.RawMessage `json:"result"`
+	Parameters  struct {
+		RetryAfter int `json:"retry_after"`
+	} `json:"parameters"`
 }
 
+// tgMaxRetryAfter caps how long a single Retry-After may hold the caller.
+const tgMaxRetryAfter = 30 * time.Second
+
+// TelegramSendError is returned by SendMessage/SendSigningConfirm once the
+// Bot API call has failed for good. StatusCode is 0 when no response arrived.
+type TelegramSendError struct {
+	StatusCode  int
+	Description string
+	Attempts    int
+	Err         error
+}
+
+func (e *TelegramSendError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("telegram sendMessage failed after %d attempt(s): %v", e.Attempts, e.Err)
+	}
+	return fmt.Sprintf("telegram sendMessage failed after %d attempt(s): status=%d desc=%s", e.Attempts, e.StatusCode, e.Description)
+}
+
+func (e *TelegramSendError) Unwrap() error { return e.Err }
+
 // linkPrefix is used when building the /integrations/telegram/link?code=... URL.
 func NewTelegramService(botToken string, linkRepo repositories.TelegramLinkRepository, usersRepo repositories.UserRepository, taskSvc TaskService, linkPrefix string) *TelegramService {
 	if botToken == "" {
@@ -56,6 +84,8 @@ func NewTelegramService(botToken string, linkRepo repositories.TelegramLinkRepos
 		token:      botToken,
 		baseURL:    fmt.Sprintf("https://api.telegram.org/bot%s", botToken),
 		client:     &http.Client{Timeout: 10 * time.Second},
+		retries:    2,
+		retryDelay: 500 * time.Millisecond,
 		linkRepo:   linkRepo,
 		usersRepo:  usersRepo,
 		taskSvc:    taskSvc,
@@ -70,6 +100,24 @@ func (t *TelegramService) SetTaskService(s TaskService) {
 	}
 }
 
+// SetHTTPOptions sets the Bot API request timeout and how many times a
+// failed send (network error, 5xx or 429) is retried, starting at retryDelay
+// and doubling after each attempt.
+func (t *TelegramService) SetHTTPOptions(timeout time.Duration, retries int, retryDelay time.Duration) {
+	if t == nil {
+		return
+	}
+	if timeout > 0 {
+		t.client = &http.Client{Timeout: timeout}
+	}
+	if retries >= 0 {
+		t.retries = retries
+	}
+	if retryDelay > 0 {
+		t.retryDelay = retryDelay
+	}
+}
+
 func (t *TelegramService) SendMessage(chatID int64, text string) error {
 	if t == nil || t.token == "" || chatID == 0 {
 		log.Printf("[tg][skip] token or chatID empty (token? %v chatID=%d)", t != nil && t.token != "", chatID)
@@ -119,24 +167,67 @@ func (t *TelegramService) sendMessage(chatID int64, text string, replyMarkup any
 	}
 	b, _ := json.Marshal(body)
 	url := t.baseURL + "/sendMessage"
-	req, _ := http.NewRequest("POST", url, bytes.NewReader(b))
+
+	delay := t.retryDelay
+	var lastErr *TelegramSendError
+	for attempt := 1; attempt <= t.retries+1; attempt++ {
+		retryAfter, err := t.postSendMessage(url, b, attempt)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !isRetryableTelegramError(err) || attempt > t.retries {
+			break
+		}
+		wait := delay
+		if retryAfter > 0 {
+			wait = retryAfter
+		}
+		log.Printf("[tg][send][retry] attempt=%d status=%d wait=%s", attempt, err.StatusCode, wait)
+		time.Sleep(wait)
+		delay *= 2
+	}
+	return lastErr
+}
+
+// postSendMessage makes one sendMessage call. On failure it also returns the
+// delay Telegram asked for via Retry-After (header or parameters.retry_after).
+func (t *TelegramService) postSendMessage(url string, payload []byte, attempt int) (time.Duration, *TelegramSendError) {
+	req, _ := http.NewRequest("POST", url, bytes.NewReader(payload))
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := t.client.Do(req)
 	if err != nil {
 		log.Printf("[tg][send][err] http: %v", err)
-		return err
+		return 0, &TelegramSendError{Attempts: attempt, Err: err}
 	}
 	defer resp.Body.Close()
 
 	respBody, _ := io.ReadAll(resp.Body)
 	var api tgResp
 	_ = json.Unmarshal(respBody, &api)
-	if resp.StatusCode != 200 || !api.Ok {
-		log.Printf("[tg][send] http_status=%d body=%s", resp.StatusCode, string(respBody))
-		return fmt.Errorf("telegram sendMessage failed: status=%d ok=%v desc=%s", resp.StatusCode, api.Ok, api.Description)
+	if resp.StatusCode == http.StatusOK && api.Ok {
+		return 0, nil
 	}
-	return nil
+	log.Printf("[tg][send] http_status=%d body=%s", resp.StatusCode, string(respBody))
+
+	retryAfter := time.Duration(api.Parameters.RetryAfter) * time.Second
+	if v, err := strconv.Atoi(strings.TrimSpace(resp.Header.Get("Retry-After"))); err == nil && v >= 0 {
+		retryAfter = time.Duration(v) * time.Second
+	}
+	if retryAfter > tgMaxRetryAfter {
+		retryAfter = tgMaxRetryAfter
+	}
+	return retryAfter, &TelegramSendError{StatusCode: resp.StatusCode, Description: api.Description, Attempts: attempt}
+}
+
+// isRetryableTelegramError reports whether a failed send is worth retrying:
+// transport errors, rate limiting and server-side failures.
+func isRetryableTelegramError(err *TelegramSendError) bool {
+	if err.Err != nil {
+		return true
+	}
+	return err.StatusCode == http.StatusTooManyRequests || err.StatusCode >= 500
 }
 
 func (t *TelegramService) SetWebhook(url string) error {
@@ -240,7 +331,7 @@ func (t *TelegramService) handleTasks(chatID int64) error {
 		return t.SendMessage(chatID, "⚠️ Не удалось получить список задач.")
 	}
 
-	return t.SendMessage(chatID, t.FormatTasksList(tasks))
+	return t.SendTasksDigest(chatID, "", tasks)
 }
 
 func (t *TelegramService) FormatHelpMessage() string {
@@ -294,100 +385,183 @@ func (t *TelegramService) FormatStartMessage(code string) string {
 	return msg
 }
 
-func (t *TelegramService) FormatTasksList(tasks []models.Task) string {
-	now := time.Now()
-	var b strings.Builder
-
-	// header
-	b.WriteString("📋 <b>Ваши актуальные задачи</b> • <i>" + now.Format("02.01.2006 15:04") + "</i>\n\n")
+const (
+	// tgMessageLimit is Telegram's maximum message length; longer text is rejected.
+	tgMessageLimit = 4096
+	// tgDigestMaxTasks caps how many tasks the /tasks digest lists.
+	tgDigestMaxTasks = 30
+	// tgDigestTitleMax keeps a single digest entry well under tgMessageLimit.
+	tgDigestTitleMax = 300
+)
 
-	active := make([]models.Task, 0, len(tasks))
-	for _, tsk := range tasks {
-		if tsk.Status == models.StatusDone || tsk.Status == models.StatusCancelled {
-			continue
+// SendTasksDigest sends the active-task digest, split into as many messages
+// as needed. prefix, if any, opens the first message.
+func (t *TelegramService) SendTasksDigest(chatID int64, prefix string, tasks []models.Task) error {
+	for _, msg := range t.formatTasksDigest(tasks, time.Now(), prefix) {
+		if err := t.SendMessage(chatID, msg); err != nil {
+			return err
 		}
-		active = append(active, tsk)
 	}
+	return nil
+}
+
+// FormatTasksDigest renders the active tasks as one or more messages, each
+// within tgMessageLimit. At most tgDigestMaxTasks are listed; the rest are
+// summarised as "…и ещё N".
+func (t *TelegramService) FormatTasksDigest(tasks []models.Task) []string {
+	return t.formatTasksDigest(tasks, time.Now(), "")
+}
 
+func (t *TelegramService) formatTasksDigest(tasks []models.Task, now time.Time, prefix string) []string {
+	active := activeDigestTasks(tasks)
 	if len(active) == 0 {
-		return "✅ <b>Задач нет</b>\nВсе актуальные задачи закрыты.\n\nКоманды: /tasks /help"
+		return []string{prefix + "✅ <b>Задач нет</b>\nВсе актуальные задачи закрыты.\n\nКоманды: /tasks /help"}
 	}
 
-	for i, tsk := range active {
-		title := html.EscapeString(tsk.Title)
-
-		statusStr := string(tsk.Status)
-		priorityStr := string(tsk.Priority)
+	shown := active
+	if len(shown) > tgDigestMaxTasks {
+		shown = shown[:tgDigestMaxTasks]
+	}
+	footer := ""
+	if rest := len(active) - len(shown); rest > 0 {
+		footer = fmt.Sprintf("…и ещё %d\n\n", rest)
+	}
+	footer += "Команды: /tasks /help"
 
-		statusEmoji := map[string]string{
-			"new":         "🆕",
-			"in_progress": "🟡",
-			"confirmed":   "✅",
-			"done":        "✅",
-			"cancelled":   "⛔",
-		}[statusStr]
-		if statusEmoji == "" {
-			statusEmoji = "📌"
+	var messages []string
+	cur := prefix + "📋 <b>Ваши актуальные задачи</b> • <i>" + now.Format("02.01.2006 15:04") + "</i>\n\n"
+	for i, tsk := range shown {
+		entry := t.formatDigestEntry(i+1, tsk, now)
+		if tgTextLen(cur)+tgTextLen(entry) > tgMessageLimit {
+			messages = append(messages, strings.TrimRight(cur, "\n"))
+			cur = ""
 		}
+		cur += entry
+	}
+	if tgTextLen(cur)+tgTextLen(footer) > tgMessageLimit {
+		messages = append(messages, strings.TrimRight(cur, "\n"))
+		cur = ""
+	}
+	return append(messages, cur+footer)
+}
 
-		priEmoji := map[string]string{
-			"high":   "🔴",
-			"medium": "🟠",
-			"low":    "🟢",
-		}[priorityStr]
-
-		// due
-		dueLine := "—"
-		overdue := false
-		if tsk.DueDate != nil {
-			dueLine = tsk.DueDate.Format("02.01.2006 15:04")
-			if tsk.DueDate.Before(now) {
-				overdue = true
-			}
+// activeDigestTasks drops done and cancelled tasks.
+func activeDigestTasks(tasks []models.Task) []models.Task {
+	active := make([]models.Task, 0, len(tasks))
+	for _, tsk := range tasks {
+		if tsk.Status == models.StatusDone || tsk.Status == models.StatusCancelled {
+			continue
 		}
+		active = append(active, tsk)
+	}
+	return active
+}
 
-		// related entity (deal/lead/etc)
-		related := ""
-		if tsk.EntityType != "" && tsk.EntityID > 0 {
-			et := strings.ToLower(tsk.EntityType)
-			switch et {
-			case "deal", "deals":
-				if t.linkPrefix != "" {
-					related = fmt.Sprintf("<a href=\"%s/deals/%d\">deal#%d</a>", html.EscapeString(t.linkPrefix), tsk.EntityID, tsk.EntityID)
-				} else {
-					related = fmt.Sprintf("deal#%d", tsk.EntityID)
-				}
-			case "lead", "leads":
-				if t.linkPrefix != "" {
-					related = fmt.Sprintf("<a href=\"%s/leads/%d\">lead#%d</a>", html.EscapeString(t.linkPrefix), tsk.EntityID, tsk.EntityID)
-				} else {
-					related = fmt.Sprintf("lead#%d", tsk.EntityID)
-				}
-			default:
-				related = html.EscapeString(tsk.EntityType) + "#" + fmt.Sprintf("%d", tsk.EntityID)
-			}
+// formatDigestEntry renders task number n of the digest.
+func (t *TelegramService) formatDigestEntry(n int, tsk models.Task, now time.Time) string {
+	var b strings.Builder
+	title := html.EscapeString(truncateRunes(tsk.Title, tgDigestTitleMax))
+
+	statusStr := string(tsk.Status)
+	priorityStr := string(tsk.Priority)
+
+	statusEmoji := map[string]string{
+		"new":         "🆕",
+		"in_progress": "🟡",
+		"confirmed":   "✅",
+		"done":        "✅",
+		"cancelled":   "⛔",
+	}[statusStr]
+	if statusEmoji == "" {
+		statusEmoji = "📌"
+	}
+
+	priEmoji := map[string]string{
+		"high":   "🔴",
+		"medium": "🟠",
+		"low":    "🟢",
+	}[priorityStr]
+
+	// due
+	dueLine := "—"
+	overdue := false
+	if tsk.DueDate != nil {
+		dueLine = tsk.DueDate.Format("02.01.2006 15:04") + " · " + daysLeftStr(tsk.DueDate, now)
+		if tsk.DueDate.Before(now) {
+			overdue = true
 		}
+	}
 
-		b.WriteString(fmt.Sprintf("%d) %s %s <b>%s</b>\n", i+1, statusEmoji, priEmoji, title))
-		b.WriteString("   • Статус: <code>" + html.EscapeString(statusStr) + "</code>\n")
-		if priorityStr != "" {
-			b.WriteString("   • Приоритет: <code>" + html.EscapeString(priorityStr) + "</code>\n")
-		}
-		if overdue {
-			b.WriteString("   • Срок: <b>" + html.EscapeString(dueLine) + "</b> ⚠️ <b>просрочено</b>\n")
-		} else {
-			b.WriteString("   • Срок: <b>" + html.EscapeString(dueLine) + "</b>\n")
-		}
-		if related != "" {
-			b.WriteString("   • Связано: " + related + "\n")
+	// related entity (deal/lead/etc)
+	related := ""
+	if tsk.EntityType != "" && tsk.EntityID > 0 {
+		et := strings.ToLower(tsk.EntityType)
+		switch et {
+		case "deal", "deals":
+			if t.linkPrefix != "" {
+				related = fmt.Sprintf("<a href=\"%s/deals/%d\">deal#%d</a>", html.EscapeString(t.linkPrefix), tsk.EntityID, tsk.EntityID)
+			} else {
+				related = fmt.Sprintf("deal#%d", tsk.EntityID)
+			}
+		case "lead", "leads":
+			if t.linkPrefix != "" {
+				related = fmt.Sprintf("<a href=\"%s/leads/%d\">lead#%d</a>", html.EscapeString(t.linkPrefix), tsk.EntityID, tsk.EntityID)
+			} else {
+				related = fmt.Sprintf("lead#%d", tsk.EntityID)
+			}
+		default:
+			related = html.EscapeString(tsk.EntityType) + "#" + fmt.Sprintf("%d", tsk.EntityID)
 		}
-		b.WriteString("\n")
 	}
 
-	b.WriteString("Команды: /tasks /help")
+	b.WriteString(fmt.Sprintf("%d) %s %s <b>%s</b>\n", n, statusEmoji, priEmoji, title))
+	b.WriteString("   • Статус: <code>" + html.EscapeString(statusStr) + "</code>\n")
+	if priorityStr != "" {
+		b.WriteString("   • Приоритет: <code>" + html.EscapeString(priorityStr) + "</code>\n")
+	}
+	if overdue {
+		b.WriteString("   • Срок: <b>" + html.EscapeString(dueLine) + "</b> ⚠️ <b>просрочено</b>\n")
+	} else {
+		b.WriteString("   • Срок: <b>" + html.EscapeString(dueLine) + "</b>\n")
+	}
+	if related != "" {
+		b.WriteString("   • Связано: " + related + "\n")
+	}
+	b.WriteString("\n")
 	return b.String()
 }
 
+// tgTextLen measures text the way Telegram does, in UTF-16 code units. Markup
+// is counted too, which only makes the split more conservative.
+func tgTextLen(s string) int {
+	return len(utf16.Encode([]rune(s)))
+}
+
+func truncateRunes(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max]) + "…"
+}
+
+// daysLeftStr buckets a due date by calendar days relative to now.
+func daysLeftStr(due *time.Time, now time.Time) string {
+	if due == nil {
+		return "Без срока"
+	}
+	switch days := taskDueDays(*due, now); {
+	case days < 0:
+		return fmt.Sprintf("Просрочено (%d дн.)", -days)
+	case days == 0:
+		return "Сегодня"
+	case days == 1:
+		return "Завтра"
+	default:
+		return fmt.Sprintf("Через %d дн.", days)
+	}
+}
+
 func (t *TelegramService) FormatTaskNotification(task *models.Task) string {
 	if task == nil {
 		return ""
@@ -407,26 +581,7 @@ func (t *TelegramService) FormatTaskNotification(task *models.Task) string {
 		}
 	}
 
-	related := ""
-	if task.EntityType != "" && task.EntityID > 0 {
-		et := strings.ToLower(task.EntityType)
-		switch et {
-		case "deal", "deals":
-			if t.linkPrefix != "" {
-				related = fmt.Sprintf("<a href=\"%s/deals/%d\">deal#%d</a>", html.EscapeString(t.linkPrefix), task.EntityID, task.EntityID)
-			} else {
-				related = fmt.Sprintf("deal#%d", task.EntityID)
-			}
-		case "lead", "leads":
-			if t.linkPrefix != "" {
-				related = fmt.Sprintf("<a href=\"%s/leads/%d\">lead#%d</a>", html.EscapeString(t.linkPrefix), task.EntityID, task.EntityID)
-			} else {
-				related = fmt.Sprintf("lead#%d", task.EntityID)
-			}
-		default:
-			related = html.EscapeString(task.EntityType) + "#" + fmt.Sprintf("%d", task.EntityID)
-		}
-	}
+	related := t.taskRelatedLink(task)
 
 	msg := "📌 <b>Новая задача</b>\n<b>" + title + "</b>\n\n" +
 		"• Статус: <code>" + html.EscapeString(statusStr) + "</code>\n" +
@@ -446,6 +601,54 @@ func (t *TelegramService) FormatTaskNotification(task *models.Task) string {
 	return msg
 }
 
+// taskRelatedLink renders the task's linked entity (deal#N, lead#N, ...) or
+// "" when the task is not linked.
+func (t *TelegramService) taskRelatedLink(task *models.Task) string {
+	if task.EntityType == "" || task.EntityID <= 0 {
+		return ""
+	}
+	switch strings.ToLower(task.EntityType) {
+	case "deal", "deals":
+		if t.linkPrefix != "" {
+			return fmt.Sprintf("<a href=\"%s/deals/%d\">deal#%d</a>", html.EscapeString(t.linkPrefix), task.EntityID, task.EntityID)
+		}
+		return fmt.Sprintf("deal#%d", task.EntityID)
+	case "lead", "leads":
+		if t.linkPrefix != "" {
+			return fmt.Sprintf("<a href=\"%s/leads/%d\">lead#%d</a>", html.EscapeString(t.linkPrefix), task.EntityID, task.EntityID)
+		}
+		return fmt.Sprintf("lead#%d", task.EntityID)
+	default:
+		return html.EscapeString(task.EntityType) + "#" + fmt.Sprintf("%d", task.EntityID)
+	}
+}
+
+// FormatTaskDeletedNotification is the short message sent when a task is
+// deleted: only the title and linked entity, since status, priority and due
+// date no longer matter.
+func (t *TelegramService) FormatTaskDeletedNotification(task *models.Task) string {
+	if task == nil {
+		return ""
+	}
+	msg := "🗑️ <b>Задача удалена</b>\n<b>" + html.EscapeString(task.Title) + "</b>\n"
+	if related := t.taskRelatedLink(task); related != "" {
+		msg += "\n• Связано: " + related + "\n"
+	}
+	return msg
+}
+
+// FormatTasksReassignedNotification is the single summary sent to a user who
+// took over another user's open tasks in bulk.
+func (t *TelegramService) FormatTasksReassignedNotification(count int, fromName string) string {
+	msg := "👤 <b>Вам переданы задачи</b>\n"
+	if fromName != "" {
+		msg += "• От: <b>" + html.EscapeString(fromName) + "</b>\n"
+	}
+	msg += fmt.Sprintf("• Количество: <b>%d</b>\n", count)
+	msg += "\nКоманды: /tasks /help"
+	return msg
+}
+
 func (t *TelegramService) generateLinkCode() (string, error) {
 	buf := make([]byte, 16)
 	if _, err := rand.Read(buf); err != nil {
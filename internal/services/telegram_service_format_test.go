@@ -0,0 +1,110 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"turcompany/internal/models"
+)
+
+func TestDaysLeftStr(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time { v := now.Add(d); return &v }
+
+	tests := []struct {
+		name string
+		due  *time.Time
+		want string
+	}{
+		{"no due date", nil, "Без срока"},
+		{"overdue three days", at(-72 * time.Hour), "Просрочено (3 дн.)"},
+		{"overdue earlier today is still today", at(-2 * time.Hour), "Сегодня"},
+		{"later today", at(3 * time.Hour), "Сегодня"},
+		{"tomorrow morning", at(10 * time.Hour), "Завтра"},
+		{"in five days", at(5 * 24 * time.Hour), "Через 5 дн."},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := daysLeftStr(tc.due, now); got != tc.want {
+				t.Fatalf("daysLeftStr() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFormatTasksDigestShowsDaysLeft(t *testing.T) {
+	due := time.Now().Add(-49 * time.Hour)
+	svc := &TelegramService{}
+	out := svc.FormatTasksDigest([]models.Task{{ID: 1, Title: "Позвонить", Status: models.StatusNew, DueDate: &due}})[0]
+	if !strings.Contains(out, "Просрочено (") || strings.Contains(out, "%") {
+		t.Fatalf("expected overdue day count in digest, got %q", out)
+	}
+}
+
+func TestSendTasksDigestSplitsLargeTaskSet(t *testing.T) {
+	var texts []string
+	svc := newTestTelegramService(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Text string `json:"text"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		texts = append(texts, body.Text)
+		_, _ = w.Write([]byte(`{"ok":true,"result":{}}`))
+	})
+
+	tasks := make([]models.Task, 200)
+	for i := range tasks {
+		tasks[i] = models.Task{ID: int64(i + 1), Title: strings.Repeat("Задача ", 40), Status: models.StatusNew, EntityType: "deal", EntityID: int64(i + 1)}
+	}
+	if err := svc.SendTasksDigest(42, "Привет\n\n", tasks); err != nil {
+		t.Fatalf("SendTasksDigest: %v", err)
+	}
+
+	if len(texts) < 2 {
+		t.Fatalf("expected the digest to be split, got %d message(s)", len(texts))
+	}
+	listed := 0
+	for i, text := range texts {
+		if n := tgTextLen(text); n > tgMessageLimit {
+			t.Fatalf("message %d is %d units long, limit %d", i, n, tgMessageLimit)
+		}
+		listed += strings.Count(text, "• Статус:")
+	}
+	if listed != tgDigestMaxTasks {
+		t.Fatalf("expected %d tasks listed, got %d", tgDigestMaxTasks, listed)
+	}
+	if !strings.HasPrefix(texts[0], "Привет") {
+		t.Fatalf("prefix must open the first message")
+	}
+	if last := texts[len(texts)-1]; !strings.Contains(last, "…и ещё 170") {
+		t.Fatalf("expected remainder footer in last message, got %q", last)
+	}
+}
+
+func TestFormatTaskDeletedNotification(t *testing.T) {
+	due := time.Now().Add(-24 * time.Hour)
+	svc := &TelegramService{linkPrefix: "https://crm.example.kz"}
+	task := &models.Task{
+		ID: 7, Title: "Позвонить <клиенту>", Status: models.StatusNew, Priority: "urgent",
+		DueDate: &due, EntityType: "deal", EntityID: 12,
+	}
+
+	got := svc.FormatTaskDeletedNotification(task)
+	want := "🗑️ <b>Задача удалена</b>\n<b>Позвонить &lt;клиенту&gt;</b>\n" +
+		"\n• Связано: <a href=\"https://crm.example.kz/deals/12\">deal#12</a>\n"
+	if got != want {
+		t.Fatalf("unexpected deletion message:\n got %q\nwant %q", got, want)
+	}
+	for _, stale := range []string{"Новая задача", "Статус", "Приоритет", "Срок", "просрочено"} {
+		if strings.Contains(got, stale) {
+			t.Fatalf("deletion message must not contain %q: %q", stale, got)
+		}
+	}
+
+	if got := svc.FormatTaskDeletedNotification(&models.Task{Title: "Без связи"}); got != "🗑️ <b>Задача удалена</b>\n<b>Без связи</b>\n" {
+		t.Fatalf("unexpected message for unlinked task: %q", got)
+	}
+}
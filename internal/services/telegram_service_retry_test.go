@@ -0,0 +1,81 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestTelegramService(t *testing.T, handler http.HandlerFunc) *TelegramService {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	svc := NewTelegramService("test-token", nil, nil, nil, "")
+	svc.baseURL = srv.URL
+	svc.SetHTTPOptions(time.Second, 2, time.Millisecond)
+	return svc
+}
+
+func TestTelegramSendMessage_RetriesUntilSuccess(t *testing.T) {
+	var calls int32
+	svc := newTestTelegramService(t, func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			w.WriteHeader(http.StatusBadGateway)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"ok":false,"description":"Too Many Requests"}`))
+		default:
+			_, _ = w.Write([]byte(`{"ok":true,"result":{}}`))
+		}
+	})
+
+	if err := svc.SendMessage(42, "hello"); err != nil {
+		t.Fatalf("expected success after retries, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Fatalf("expected 3 calls, got %d", got)
+	}
+}
+
+func TestTelegramSendMessage_TypedErrorAfterRetriesExhausted(t *testing.T) {
+	var calls int32
+	svc := newTestTelegramService(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	err := svc.SendMessage(42, "hello")
+	var sendErr *TelegramSendError
+	if !errors.As(err, &sendErr) {
+		t.Fatalf("expected *TelegramSendError, got %v", err)
+	}
+	if sendErr.StatusCode != http.StatusServiceUnavailable || sendErr.Attempts != 3 {
+		t.Fatalf("unexpected error details: %+v", sendErr)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Fatalf("expected 3 calls, got %d", got)
+	}
+}
+
+func TestTelegramSendMessage_ClientErrorNotRetried(t *testing.T) {
+	var calls int32
+	svc := newTestTelegramService(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"ok":false,"description":"Bad Request: chat not found"}`))
+	})
+
+	err := svc.SendMessage(42, "hello")
+	var sendErr *TelegramSendError
+	if !errors.As(err, &sendErr) || sendErr.Description != "Bad Request: chat not found" {
+		t.Fatalf("expected typed 400 error, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("expected a single call, got %d", got)
+	}
+}
@@ -30,7 +30,7 @@ type userBranchLookup interface {
 // which applies resolveLeadScope + leadMatchesScope and returns ErrForbidden
 // (or a nil lead) when the caller has no access.
 type leadAccessChecker interface {
-	GetByID(id, userID, roleID int) (*models.Leads, error)
+	GetByID(ctx context.Context, id, userID, roleID int) (*models.Leads, error)
 }
 
 // TelephonyService handles business logic for telephony / Binotel integration.
@@ -352,7 +352,7 @@ func (s *TelephonyService) GetCall(ctx context.Context, userID, roleID int, id i
 // This blocks an own-scoped role (partner) — or any role — from reading the call
 // history of a client they don't own/can't see by guessing the client_id.
 func (s *TelephonyService) ListClientCalls(ctx context.Context, userID, roleID int, clientID int64, limit, offset int) ([]*models.TelephonyCallResponse, int, error) {
-	if err := s.ensureClientAccess(userID, roleID, clientID); err != nil {
+	if err := s.ensureClientAccess(ctx, userID, roleID, clientID); err != nil {
 		return nil, 0, err
 	}
 
@@ -379,7 +379,7 @@ func (s *TelephonyService) ListClientCalls(ctx context.Context, userID, roleID i
 // Ownership is enforced FIRST via the canonical lead-scope rules (same check as
 // GET /leads/:id) — see ListClientCalls.
 func (s *TelephonyService) ListLeadCalls(ctx context.Context, userID, roleID int, leadID int64, limit, offset int) ([]*models.TelephonyCallResponse, int, error) {
-	if err := s.ensureLeadAccess(userID, roleID, leadID); err != nil {
+	if err := s.ensureLeadAccess(ctx, userID, roleID, leadID); err != nil {
 		return nil, 0, err
 	}
 
@@ -405,11 +405,11 @@ func (s *TelephonyService) ListLeadCalls(ctx context.Context, userID, roleID int
 // under the canonical client-scope rules. Fails closed: a missing checker, an
 // out-of-scope client (nil), or ErrForbidden all deny access. A non-scope error
 // (e.g. DB failure) is propagated as-is.
-func (s *TelephonyService) ensureClientAccess(userID, roleID int, clientID int64) error {
+func (s *TelephonyService) ensureClientAccess(ctx context.Context, userID, roleID int, clientID int64) error {
 	if s.clientAccess == nil {
 		return ErrForbidden
 	}
-	client, err := s.clientAccess.GetByID(int(clientID), userID, roleID)
+	client, err := s.clientAccess.GetByID(ctx, int(clientID), userID, roleID)
 	if err != nil {
 		return err
 	}
@@ -420,11 +420,11 @@ func (s *TelephonyService) ensureClientAccess(userID, roleID int, clientID int64
 }
 
 // ensureLeadAccess is the lead counterpart of ensureClientAccess.
-func (s *TelephonyService) ensureLeadAccess(userID, roleID int, leadID int64) error {
+func (s *TelephonyService) ensureLeadAccess(ctx context.Context, userID, roleID int, leadID int64) error {
 	if s.leadAccess == nil {
 		return ErrForbidden
 	}
-	lead, err := s.leadAccess.GetByID(int(leadID), userID, roleID)
+	lead, err := s.leadAccess.GetByID(ctx, int(leadID), userID, roleID)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// TokenRevocationList remembers revoked access tokens by jti until the token
+// would have expired anyway, so the list never outgrows the set of tokens
+// that are still otherwise valid. Entries live in memory only: they do not
+// survive a restart and are not shared between instances.
+type TokenRevocationList struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	revoked map[string]time.Time
+}
+
+func NewTokenRevocationList() *TokenRevocationList {
+	return &TokenRevocationList{now: time.Now, revoked: map[string]time.Time{}}
+}
+
+// Revoke blacklists jti until expiresAt. Already expired tokens are ignored.
+func (l *TokenRevocationList) Revoke(jti string, expiresAt time.Time) {
+	if jti == "" {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	// Заодно выбрасываем истёкшие записи: такие токены отклонит проверка exp.
+	for k, exp := range l.revoked {
+		if !now.Before(exp) {
+			delete(l.revoked, k)
+		}
+	}
+	if now.Before(expiresAt) {
+		l.revoked[jti] = expiresAt
+	}
+}
+
+// IsRevoked implements middleware.RevocationChecker.
+func (l *TokenRevocationList) IsRevoked(jti string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	exp, ok := l.revoked[jti]
+	if !ok {
+		return false
+	}
+	if !l.now().Before(exp) {
+		delete(l.revoked, jti)
+		return false
+	}
+	return true
+}
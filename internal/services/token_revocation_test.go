@@ -0,0 +1,30 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenRevocationList_ForgetsEntriesAfterExpiry(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	l := NewTokenRevocationList()
+	l.now = func() time.Time { return now }
+
+	l.Revoke("a", now.Add(time.Hour))
+	l.Revoke("stale", now.Add(-time.Minute))
+	if !l.IsRevoked("a") {
+		t.Fatal("revoked jti must be reported")
+	}
+	if l.IsRevoked("b") || l.IsRevoked("stale") {
+		t.Fatal("unknown and already expired jti must not be reported")
+	}
+
+	now = now.Add(2 * time.Hour)
+	l.Revoke("c", now.Add(time.Hour))
+	if l.IsRevoked("a") {
+		t.Fatal("entry must be dropped once the token has expired")
+	}
+	if len(l.revoked) != 1 {
+		t.Fatalf("expired entries must be pruned, left %v", l.revoked)
+	}
+}
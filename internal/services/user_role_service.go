@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"turcompany/internal/authz"
+	"turcompany/internal/models"
+	"turcompany/internal/repositories"
+)
+
+// UserRoleService changes a user's role on its own, leaving the rest of the
+// profile untouched, and keeps an audit trail of every change.
+type UserRoleService struct {
+	repo repositories.UserRoleEventRepository
+}
+
+func NewUserRoleService(repo repositories.UserRoleEventRepository) *UserRoleService {
+	return &UserRoleService{repo: repo}
+}
+
+// ChangeRole is restricted to system admins. It returns a nil event when the
+// user already has newRoleID.
+func (s *UserRoleService) ChangeRole(ctx context.Context, actorID, actorRoleID, userID, newRoleID int) (*models.UserRoleEvent, error) {
+	if !authz.CanAssignRoles(actorRoleID) {
+		return nil, ErrForbidden
+	}
+	if !authz.IsKnownRole(newRoleID) {
+		return nil, ErrUnknownRole
+	}
+	event, err := s.repo.ChangeRole(ctx, userID, actorID, newRoleID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return event, err
+}
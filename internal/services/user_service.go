@@ -1,6 +1,8 @@
 package services
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -16,7 +18,10 @@ type UserService interface {
 	UpdateUser(user *models.User) error
 	ApplyUpdatePatch(userID int, patch *models.UserApprovalUpdatePayload) error
 	DeleteUser(id int) error
+	ReactivateUser(id int) error
 	ListUsers(limit, offset int) ([]*models.User, error)
+	SearchUsers(filter repositories.UserSearchFilter, limit, offset int) ([]*models.User, error)
+	CountUsers(filter repositories.UserSearchFilter) (int, error)
 	GetUserByEmail(email string) (*models.User, error)
 	GetAuthUserByEmail(email string) (*models.User, error)
 	GetUserCount() (int, error)
@@ -30,11 +35,13 @@ type UserService interface {
 	UpdateRefresh(userID int, token string, expiresAt time.Time) error
 	GetByRefreshToken(token string) (*models.User, error)
 	RotateRefresh(oldToken, newToken string, newExpiresAt time.Time) (*models.User, error)
+	ClearRefresh(userID int) error
 
 	// verification
 	VerifyUser(userID int) error
 
 	AdminChangePassword(userID int, newPassword string) error
+	ChangePassword(userID int, currentPassword, newPassword string) error
 }
 
 type userService struct {
@@ -51,6 +58,15 @@ func NewUserService(repo repositories.UserRepository, emailService EmailService,
 	}
 }
 
+// welcomeName is how the welcome email addresses the user: full name, or the
+// company name when no personal name is set.
+func welcomeName(user *models.User) string {
+	if name := strings.TrimSpace(user.FirstName + " " + user.LastName); name != "" {
+		return name
+	}
+	return user.CompanyName
+}
+
 // CreateUserWithPassword - preferred: pass plain password here
 func (s *userService) CreateUserWithPassword(user *models.User, plainPassword string) error {
 	if strings.TrimSpace(plainPassword) == "" {
@@ -69,11 +85,7 @@ func (s *userService) CreateUserWithPassword(user *models.User, plainPassword st
 	}
 
 	if s.emailService != nil {
-		welcomeName := strings.TrimSpace(user.FirstName + " " + user.LastName)
-		if welcomeName == "" {
-			welcomeName = user.CompanyName
-		}
-		if err := s.emailService.SendWelcomeEmail(user.Email, welcomeName); err != nil {
+		if err := s.emailService.SendWelcomeEmail(user.Email, welcomeName(user)); err != nil {
 			log.Printf("CreateUserWithPassword: warning: failed to send welcome email to %s: %v", user.Email, err)
 		}
 	}
@@ -102,11 +114,7 @@ func (s *userService) CreateUser(user *models.User) error {
 	}
 
 	if s.emailService != nil {
-		welcomeName := strings.TrimSpace(user.FirstName + " " + user.LastName)
-		if welcomeName == "" {
-			welcomeName = user.CompanyName
-		}
-		if err := s.emailService.SendWelcomeEmail(user.Email, welcomeName); err != nil {
+		if err := s.emailService.SendWelcomeEmail(user.Email, welcomeName(user)); err != nil {
 			log.Printf("CreateUser: warning: failed to send welcome email to %s: %v", user.Email, err)
 		}
 	}
@@ -124,6 +132,36 @@ func (s *userService) AdminChangePassword(userID int, newPassword string) error
 	return s.repo.UpdatePassword(userID, hashed)
 }
 
+// ChangePassword lets a user replace their own password. The current password
+// must match and the new one must satisfy the password policy. Existing
+// refresh tokens are revoked, so other sessions have to log in again.
+func (s *userService) ChangePassword(userID int, currentPassword, newPassword string) error {
+	user, err := s.repo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrNotFound
+	}
+	if !s.authService.VerifyPassword(user.PasswordHash, currentPassword) {
+		return ErrInvalidCurrentPassword
+	}
+	if err := ValidatePassword(newPassword); err != nil {
+		return err
+	}
+	hashed, err := s.authService.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.UpdatePassword(userID, hashed); err != nil {
+		return err
+	}
+	if err := s.repo.ClearRefresh(userID); err != nil {
+		return fmt.Errorf("revoke sessions: %w", err)
+	}
+	return nil
+}
+
 func (s *userService) GetUserByID(id int) (*models.User, error) {
 	return s.repo.GetByID(id)
 }
@@ -140,10 +178,28 @@ func (s *userService) DeleteUser(id int) error {
 	return s.repo.Delete(id)
 }
 
+// ReactivateUser undoes DeleteUser. It fails with ErrEmailAlreadyUsed when the
+// original email has since been taken by another account.
+func (s *userService) ReactivateUser(id int) error {
+	err := s.repo.Reactivate(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	return normalizeUserCreateError(err)
+}
+
 func (s *userService) ListUsers(limit, offset int) ([]*models.User, error) {
 	return s.repo.List(limit, offset)
 }
 
+func (s *userService) SearchUsers(filter repositories.UserSearchFilter, limit, offset int) ([]*models.User, error) {
+	return s.repo.Search(filter, limit, offset)
+}
+
+func (s *userService) CountUsers(filter repositories.UserSearchFilter) (int, error) {
+	return s.repo.CountSearch(filter)
+}
+
 func (s *userService) GetUserByEmail(email string) (*models.User, error) {
 	return s.repo.GetByEmail(email)
 }
@@ -180,6 +236,10 @@ func (s *userService) UpdateRefresh(userID int, token string, expiresAt time.Tim
 	return s.repo.UpdateRefresh(userID, token, expiresAt)
 }
 
+func (s *userService) ClearRefresh(userID int) error {
+	return s.repo.ClearRefresh(userID)
+}
+
 func (s *userService) GetByRefreshToken(token string) (*models.User, error) {
 	return s.repo.GetByRefreshToken(token)
 }
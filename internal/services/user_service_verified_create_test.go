@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"turcompany/internal/models"
+	"turcompany/internal/repositories"
 )
 
 type captureUserRepo struct {
@@ -24,6 +25,11 @@ func (r *captureUserRepo) ApplyUserPatch(int, *models.UserApprovalUpdatePayload)
 func (r *captureUserRepo) GetByID(int) (*models.User, error)           { return nil, nil }
 func (r *captureUserRepo) Update(*models.User) error                   { return nil }
 func (r *captureUserRepo) Delete(int) error                            { return nil }
+func (r *captureUserRepo) Reactivate(int) error { return nil }
+func (r *captureUserRepo) Search(repositories.UserSearchFilter, int, int) ([]*models.User, error) {
+	return nil, nil
+}
+func (r *captureUserRepo) CountSearch(repositories.UserSearchFilter) (int, error) { return 0, nil }
 func (r *captureUserRepo) List(int, int) ([]*models.User, error)       { return nil, nil }
 func (r *captureUserRepo) GetByEmail(string) (*models.User, error)     { return nil, nil }
 func (r *captureUserRepo) GetAuthByEmail(string) (*models.User, error) { return nil, nil }
@@ -52,13 +58,15 @@ func (r *captureUserRepo) GetDepartmentIDByCode(string) (*int, error)
 type noopMailService struct{}
 
 func (noopMailService) SendWelcomeEmail(string, string) error             { return nil }
-func (noopMailService) SendPasswordResetEmail(string, string) error       { return nil }
+func (noopMailService) SendPasswordResetEmail(string, string, string) error { return nil }
+func (noopMailService) SendEmailVerificationLink(string, string) error    { return nil }
 func (noopMailService) SendVerificationCode(string, string, int) error    { return nil }
 func (noopMailService) SendSigningConfirm(string, SigningEmailData) error { return nil }
+func (noopMailService) SendTaskEmail(string, *models.Task, string) error { return nil }
 
 func TestCreateUserWithPassword_DefaultUnverifiedKeepsLegacyBehavior(t *testing.T) {
 	repo := &captureUserRepo{}
-	auth := NewAuthService([]byte("01234567890123456789012345678901"), nil, 0, 0, nil)
+	auth := NewAuthService([]byte("01234567890123456789012345678901"), nil, 0, 0, 0, nil)
 	svc := NewUserService(repo, noopMailService{}, auth)
 
 	u := &models.User{CompanyName: "Acme", Email: "u@example.com", RoleID: 10, Phone: "+7700"}
@@ -78,7 +86,7 @@ func TestCreateUserWithPassword_DefaultUnverifiedKeepsLegacyBehavior(t *testing.
 
 func TestCreateUserWithPassword_VerifiedUserGetsVerifiedAt(t *testing.T) {
 	repo := &captureUserRepo{}
-	auth := NewAuthService([]byte("01234567890123456789012345678901"), nil, 0, 0, nil)
+	auth := NewAuthService([]byte("01234567890123456789012345678901"), nil, 0, 0, 0, nil)
 	svc := NewUserService(repo, noopMailService{}, auth)
 
 	u := &models.User{CompanyName: "Acme", Email: "u@example.com", RoleID: 10, Phone: "+7700", IsVerified: true}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"turcompany/internal/models"
+)
+
+type memVerificationRepo struct {
+	rows        []*models.UserVerification
+	lockedUntil map[int]time.Time
+	now         func() time.Time
+}
+
+func (r *memVerificationRepo) CountRecentSends(userID int, since time.Time) (int, error) {
+	return 0, nil
+}
+
+func (r *memVerificationRepo) Create(userID int, codeHash string, sentAt, expiresAt time.Time) (int64, error) {
+	id := int64(len(r.rows) + 1)
+	r.rows = append(r.rows, &models.UserVerification{ID: id, UserID: userID, CodeHash: codeHash, SentAt: sentAt, ExpiresAt: expiresAt})
+	return id, nil
+}
+
+func (r *memVerificationRepo) GetLatestByUserID(userID int) (*models.UserVerification, error) {
+	for i := len(r.rows) - 1; i >= 0; i-- {
+		if r.rows[i].UserID == userID {
+			return r.rows[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memVerificationRepo) GetLatestPendingByUserID(userID int, now time.Time) (*models.UserVerification, error) {
+	v, _ := r.GetLatestByUserID(userID)
+	if v == nil || v.Confirmed || !v.ExpiresAt.After(now) {
+		return nil, nil
+	}
+	return v, nil
+}
+
+func (r *memVerificationRepo) byID(id int64) *models.UserVerification {
+	for _, v := range r.rows {
+		if v.ID == id {
+			return v
+		}
+	}
+	return nil
+}
+
+func (r *memVerificationRepo) IncrementAttempts(id int64) (int, error) {
+	v := r.byID(id)
+	v.Attempts++
+	return v.Attempts, nil
+}
+
+func (r *memVerificationRepo) ExpireNow(id int64) error {
+	r.byID(id).ExpiresAt = r.now()
+	return nil
+}
+
+func (r *memVerificationRepo) MarkConfirmed(id int64) error {
+	r.byID(id).Confirmed = true
+	return nil
+}
+
+func (r *memVerificationRepo) Update(v *models.UserVerification) error { return nil }
+
+func (r *memVerificationRepo) CountExhaustedSince(userID int, since time.Time, maxAttempts int) (int, error) {
+	n := 0
+	for _, v := range r.rows {
+		if v.UserID == userID && !v.SentAt.Before(since) && v.Attempts >= maxAttempts && !v.Confirmed {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *memVerificationRepo) GetLockedUntil(userID int) (*time.Time, error) {
+	if t, ok := r.lockedUntil[userID]; ok {
+		return &t, nil
+	}
+	return nil, nil
+}
+
+func (r *memVerificationRepo) LockUser(userID int, until time.Time) error {
+	r.lockedUntil[userID] = until
+	return nil
+}
+
+type lockoutUserService struct {
+	UserService
+	user *models.User
+}
+
+func (s lockoutUserService) GetUserByID(int) (*models.User, error) { return s.user, nil }
+
+func TestUserVerificationLocksAfterRepeatedExhaustedCodes(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	repo := &memVerificationRepo{lockedUntil: map[int]time.Time{}, now: clock}
+	user := &models.User{ID: 9, Email: "u@example.com"}
+	svc := NewUserVerificationService(repo, lockoutUserService{user: user}, noopMailService{}, clock)
+
+	if err := svc.Send(user.ID, user.Email); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	// Seven digits never match a generated six-digit code.
+	const wrongCode = "1234567"
+	burn := func() error {
+		var err error
+		for i := 0; i < MaxConfirmAttempts; i++ {
+			_, err = svc.Confirm(user.ID, wrongCode)
+		}
+		return err
+	}
+
+	for round := 1; round < UserLockoutThreshold; round++ {
+		if err := burn(); !errors.Is(err, ErrTooManyAttempts) {
+			t.Fatalf("round %d: expected too many attempts, got %v", round, err)
+		}
+		now = now.Add(time.Second)
+		if err := svc.Resend(user.ID); err != nil {
+			t.Fatalf("round %d: resend: %v", round, err)
+		}
+	}
+
+	err := burn()
+	var locked *AccountLockedError
+	if !errors.As(err, &locked) || !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("expected lockout on threshold, got %v", err)
+	}
+	if !locked.Until.Equal(now.Add(UserLockoutDuration)) {
+		t.Fatalf("unexpected lock expiry %v", locked.Until)
+	}
+	now = now.Add(time.Second)
+	if err := svc.Resend(user.ID); !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("resend while locked: expected ErrAccountLocked, got %v", err)
+	}
+	if _, err := svc.Confirm(user.ID, wrongCode); !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("confirm while locked: expected ErrAccountLocked, got %v", err)
+	}
+
+	now = now.Add(UserLockoutDuration)
+	if err := svc.Resend(user.ID); err != nil {
+		t.Fatalf("resend after lock expired: %v", err)
+	}
+	if _, err := svc.Confirm(user.ID, wrongCode); !errors.Is(err, ErrCodeInvalid) {
+		t.Fatalf("confirm after lock expired: expected ErrCodeInvalid, got %v", err)
+	}
+}
@@ -110,6 +110,10 @@ func (s *UserVerificationService) Resend(userID int) error {
 		log.Printf("[verify][resend] user_id=%d record=false reason=already_verified", userID)
 		return ErrAlreadyVerified
 	}
+	if err := s.checkLocked(user.ID); err != nil {
+		log.Printf("[verify][resend] user_id=%d reason=locked", userID)
+		return err
+	}
 	if strings.TrimSpace(user.Email) == "" {
 		log.Printf("[verify][resend] user_id=%d record=false reason=missing_email", userID)
 		return ErrNoPendingVerification
@@ -204,6 +208,10 @@ func (s *UserVerificationService) Confirm(userID int, code string) (bool, error)
 		log.Printf("[verify][confirm] user_id=%d record=false reason=already_verified", userID)
 		return false, ErrAlreadyVerified
 	}
+	if err := s.checkLocked(user.ID); err != nil {
+		log.Printf("[verify][confirm] user_id=%d reason=locked", userID)
+		return false, err
+	}
 
 	now := s.now()
 	v, err := s.Repo.GetLatestPendingByUserID(user.ID, now)
@@ -255,6 +263,9 @@ func (s *UserVerificationService) Confirm(userID int, code string) (bool, error)
 				v.ExpiresAt.Format(time.RFC3339),
 				attempts,
 			)
+			if err := s.lockIfExhausted(user.ID, now); err != nil {
+				return false, err
+			}
 			return false, ErrTooManyAttempts
 		}
 		log.Printf(
@@ -277,6 +288,36 @@ func (s *UserVerificationService) Confirm(userID int, code string) (bool, error)
 	return true, nil
 }
 
+func (s *UserVerificationService) checkLocked(userID int) error {
+	until, err := s.Repo.GetLockedUntil(userID)
+	if err != nil {
+		return err
+	}
+	if until != nil && s.now().Before(*until) {
+		return &AccountLockedError{Until: *until}
+	}
+	return nil
+}
+
+// lockIfExhausted locks the user once UserLockoutThreshold codes have been
+// burned within UserLockoutWindow, so resending cannot be used to keep
+// guessing.
+func (s *UserVerificationService) lockIfExhausted(userID int, now time.Time) error {
+	exhausted, err := s.Repo.CountExhaustedSince(userID, now.Add(-UserLockoutWindow), MaxConfirmAttempts)
+	if err != nil {
+		return err
+	}
+	if exhausted < UserLockoutThreshold {
+		return nil
+	}
+	until := now.Add(UserLockoutDuration)
+	if err := s.Repo.LockUser(userID, until); err != nil {
+		return err
+	}
+	log.Printf("[verify][confirm] user_id=%d exhausted=%d locked_until=%s reason=lockout", userID, exhausted, until.Format(time.RFC3339))
+	return &AccountLockedError{Until: until}
+}
+
 // Latest returns the most recent verification record for debugging.
 func (s *UserVerificationService) Latest(userID int) (*models.UserVerification, *models.User, error) {
 	if s.UserSvc == nil {
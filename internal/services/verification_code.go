@@ -17,6 +17,12 @@ const (
 	MaxConfirmAttempts     = 5
 	UserResendCooldown     = time.Minute
 	UserMaxResends         = 5
+	// UserLockoutThreshold codes burned by MaxConfirmAttempts within
+	// UserLockoutWindow lock the user out of confirm and resend for
+	// UserLockoutDuration.
+	UserLockoutThreshold = 3
+	UserLockoutWindow    = 30 * time.Minute
+	UserLockoutDuration  = 30 * time.Minute
 )
 
 // GenerateVerificationCode returns a 6-digit numeric OTP.
@@ -14,8 +14,21 @@ var (
 	ErrCodeInvalid           = errors.New("code invalid")
 	ErrNoPendingVerification = errors.New("no pending verification")
 	ErrAlreadyVerified       = errors.New("already verified")
+	ErrAccountLocked         = errors.New("account locked")
 )
 
+// AccountLockedError is returned while a user is locked out of verification.
+// It matches ErrAccountLocked via errors.Is.
+type AccountLockedError struct {
+	Until time.Time
+}
+
+func (e *AccountLockedError) Error() string {
+	return "account locked until " + e.Until.Format(time.RFC3339)
+}
+
+func (e *AccountLockedError) Unwrap() error { return ErrAccountLocked }
+
 type UserVerificationRepo interface {
 	CountRecentSends(userID int, since time.Time) (int, error)
 	Create(userID int, codeHash string, sentAt, expiresAt time.Time) (int64, error)
@@ -25,4 +38,7 @@ type UserVerificationRepo interface {
 	ExpireNow(id int64) error
 	MarkConfirmed(id int64) error
 	Update(v *models.UserVerification) error
+	CountExhaustedSince(userID int, since time.Time, maxAttempts int) (int, error)
+	GetLockedUntil(userID int) (*time.Time, error)
+	LockUser(userID int, until time.Time) error
 }
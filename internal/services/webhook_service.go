@@ -0,0 +1,301 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"turcompany/internal/models"
+	"turcompany/internal/repositories"
+)
+
+// Task events delivered to webhook subscribers.
+const (
+	TaskEventCreated       = "task.created"
+	TaskEventStatusChanged = "task.status_changed"
+	TaskEventAssigned      = "task.assigned"
+	TaskEventDeleted       = "task.deleted"
+)
+
+const (
+	// WebhookSignatureHeader carries "sha256=<hex>" — the HMAC-SHA256 of the
+	// raw request body keyed with the subscription secret.
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookEventHeader     = "X-Webhook-Event"
+
+	DefaultWebhookRetries    = 3
+	DefaultWebhookRetryDelay = time.Second
+	defaultWebhookTimeout    = 10 * time.Second
+)
+
+var webhookEventTypes = map[string]bool{
+	TaskEventCreated:       true,
+	TaskEventStatusChanged: true,
+	TaskEventAssigned:      true,
+	TaskEventDeleted:       true,
+}
+
+// WebhookEvent is the JSON body POSTed to subscribers.
+type WebhookEvent struct {
+	Event      string    `json:"event"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+// WebhookService manages webhook subscriptions and delivers events to them.
+// Publish never blocks the caller: each delivery runs in its own goroutine,
+// is retried with exponential backoff and lands in webhook_dead_letters when
+// every attempt failed.
+type WebhookService struct {
+	repo       repositories.WebhookRepository
+	client     *http.Client
+	Retries    int
+	RetryDelay time.Duration
+	now        func() time.Time
+	inflight   sync.WaitGroup
+}
+
+// NewWebhookService builds the service. The default client refuses to dial
+// loopback, private and link-local addresses; redirects are never followed,
+// whichever client is used.
+func NewWebhookService(repo repositories.WebhookRepository, client *http.Client) *WebhookService {
+	if client == nil {
+		client = &http.Client{
+			Timeout: defaultWebhookTimeout,
+			Transport: &http.Transport{
+				DialContext: (&net.Dialer{Timeout: defaultWebhookTimeout, Control: rejectInternalWebhookDial}).DialContext,
+			},
+		}
+	}
+	noRedirects := *client
+	noRedirects.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	return &WebhookService{
+		repo:       repo,
+		client:     &noRedirects,
+		Retries:    DefaultWebhookRetries,
+		RetryDelay: DefaultWebhookRetryDelay,
+		now:        time.Now,
+	}
+}
+
+// Register validates and stores a subscription. A blank secret is replaced
+// by a random one; the returned subscription is the only place it is shown.
+func (s *WebhookService) Register(ctx context.Context, rawURL, secret string, eventTypes []string, createdBy int) (*models.WebhookSubscription, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, ErrInvalidWebhookURL
+	}
+	if err := checkWebhookTarget(u); err != nil {
+		return nil, err
+	}
+	events := make([]string, 0, len(eventTypes))
+	seen := make(map[string]bool, len(eventTypes))
+	for _, e := range eventTypes {
+		e = strings.TrimSpace(e)
+		if !webhookEventTypes[e] {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownWebhookEvent, e)
+		}
+		if !seen[e] {
+			seen[e] = true
+			events = append(events, e)
+		}
+	}
+	secret = strings.TrimSpace(secret)
+	if secret == "" {
+		buf := make([]byte, 32)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		secret = hex.EncodeToString(buf)
+	}
+
+	sub := &models.WebhookSubscription{
+		URL:        u.String(),
+		Secret:     secret,
+		EventTypes: events,
+		IsActive:   true,
+	}
+	if createdBy > 0 {
+		sub.CreatedBy = &createdBy
+	}
+	if err := s.repo.Create(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// List returns all subscriptions with their secrets stripped.
+func (s *WebhookService) List(ctx context.Context) ([]models.WebhookSubscription, error) {
+	subs, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range subs {
+		subs[i].Secret = ""
+	}
+	return subs, nil
+}
+
+func (s *WebhookService) Delete(ctx context.Context, id int64) error {
+	ok, err := s.repo.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Publish fans event out to every active subscription listening to it.
+// It returns immediately; delivery errors are only logged.
+func (s *WebhookService) Publish(event string, data any) {
+	body, err := json.Marshal(WebhookEvent{Event: event, OccurredAt: s.now().UTC(), Data: data})
+	if err != nil {
+		log.Printf("[webhook][publish] marshal event=%s: %v", event, err)
+		return
+	}
+
+	s.inflight.Add(1)
+	go func() {
+		defer s.inflight.Done()
+		subs, err := s.repo.ListActiveForEvent(context.Background(), event)
+		if err != nil {
+			log.Printf("[webhook][publish] list subscriptions event=%s: %v", event, err)
+			return
+		}
+		for _, sub := range subs {
+			s.inflight.Add(1)
+			go func(sub models.WebhookSubscription) {
+				defer s.inflight.Done()
+				s.deliver(sub, event, body)
+			}(sub)
+		}
+	}()
+}
+
+// Wait blocks until every in-flight delivery has finished (or been
+// dead-lettered).
+func (s *WebhookService) Wait() {
+	s.inflight.Wait()
+}
+
+// Shutdown waits for in-flight deliveries like Wait, but gives up when ctx
+// is done. The app calls it after the HTTP server has stopped.
+func (s *WebhookService) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *WebhookService) deliver(sub models.WebhookSubscription, event string, body []byte) {
+	attempts := s.Retries + 1
+	if attempts < 1 {
+		attempts = 1
+	}
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(s.RetryDelay << (attempt - 2))
+		}
+		if lastErr = s.post(sub, event, body); lastErr == nil {
+			return
+		}
+		log.Printf("[webhook][deliver] subscription=%d event=%s attempt=%d/%d: %v", sub.ID, event, attempt, attempts, lastErr)
+	}
+
+	dl := &models.WebhookDeadLetter{
+		SubscriptionID: sub.ID,
+		EventType:      event,
+		Payload:        body,
+		Attempts:       attempts,
+		LastError:      lastErr.Error(),
+	}
+	if err := s.repo.InsertDeadLetter(context.Background(), dl); err != nil {
+		log.Printf("[webhook][deliver] dead letter subscription=%d event=%s: %v", sub.ID, event, err)
+	}
+}
+
+func (s *WebhookService) post(sub models.WebhookSubscription, event string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	// Subscriptions stored before the target check existed are checked here.
+	if err := checkWebhookTarget(req.URL); err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, event)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(sub.Secret, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SignWebhookPayload returns the X-Webhook-Signature value for body.
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// checkWebhookTarget rejects subscriber URLs that point at this host or the
+// internal network by name or IP literal. Names resolving to such addresses
+// are stopped at dial time by rejectInternalWebhookDial.
+func checkWebhookTarget(u *url.URL) error {
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: internal address", ErrInvalidWebhookURL)
+	}
+	if ip := net.ParseIP(host); ip != nil && isInternalWebhookIP(ip) {
+		return fmt.Errorf("%w: internal address", ErrInvalidWebhookURL)
+	}
+	return nil
+}
+
+func rejectInternalWebhookDial(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || isInternalWebhookIP(ip) {
+		return fmt.Errorf("webhook: refusing to dial internal address %s", host)
+	}
+	return nil
+}
+
+func isInternalWebhookIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast()
+}
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"turcompany/internal/models"
+)
+
+type webhookRepoStub struct {
+	mu          sync.Mutex
+	subs        []models.WebhookSubscription
+	deadLetters []models.WebhookDeadLetter
+}
+
+func (r *webhookRepoStub) Create(_ context.Context, sub *models.WebhookSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sub.ID = int64(len(r.subs) + 1)
+	r.subs = append(r.subs, *sub)
+	return nil
+}
+
+func (r *webhookRepoStub) List(context.Context) ([]models.WebhookSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]models.WebhookSubscription(nil), r.subs...), nil
+}
+
+func (r *webhookRepoStub) Delete(_ context.Context, id int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, s := range r.subs {
+		if s.ID == id {
+			r.subs = append(r.subs[:i], r.subs[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *webhookRepoStub) ListActiveForEvent(_ context.Context, event string) ([]models.WebhookSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []models.WebhookSubscription
+	for _, s := range r.subs {
+		if !s.IsActive {
+			continue
+		}
+		match := len(s.EventTypes) == 0
+		for _, e := range s.EventTypes {
+			match = match || e == event
+		}
+		if match {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func (r *webhookRepoStub) InsertDeadLetter(_ context.Context, dl *models.WebhookDeadLetter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deadLetters = append(r.deadLetters, *dl)
+	return nil
+}
+
+// webhookTestTarget serves h and returns a public-looking subscriber URL with
+// a client that dials the test server whatever the host, since loopback
+// targets are rejected on registration.
+func webhookTestTarget(t *testing.T, h http.Handler) (string, *http.Client) {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	addr := srv.Listener.Addr().String()
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	}}
+	return "http://hooks.example.com", client
+}
+
+func TestWebhookService_PublishSignsPayload(t *testing.T) {
+	const secret = "s3cret"
+	var (
+		gotSig, gotEvent string
+		gotBody          []byte
+		calls            int32
+	)
+	target, client := webhookTestTarget(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		gotSig = r.Header.Get(WebhookSignatureHeader)
+		gotEvent = r.Header.Get(WebhookEventHeader)
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	repo := &webhookRepoStub{}
+	svc := NewWebhookService(repo, client)
+	if _, err := svc.Register(context.Background(), target, secret, []string{TaskEventStatusChanged}, 1); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	svc.Publish(TaskEventCreated, &models.Task{ID: 1})
+	svc.Publish(TaskEventStatusChanged, &models.Task{ID: 7, Status: models.StatusDone})
+	svc.Wait()
+
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Fatalf("expected only the subscribed event to be delivered, got %d calls", n)
+	}
+	if gotEvent != TaskEventStatusChanged {
+		t.Fatalf("event header = %q", gotEvent)
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(gotBody)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); gotSig != want {
+		t.Fatalf("signature = %q, want %q", gotSig, want)
+	}
+	var ev struct {
+		Event string      `json:"event"`
+		Data  models.Task `json:"data"`
+	}
+	if err := json.Unmarshal(gotBody, &ev); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if ev.Event != TaskEventStatusChanged || ev.Data.ID != 7 || ev.Data.Status != models.StatusDone {
+		t.Fatalf("unexpected payload: %s", gotBody)
+	}
+}
+
+func TestWebhookService_DeadLettersAfterRetries(t *testing.T) {
+	var calls int32
+	target, client := webhookTestTarget(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+
+	repo := &webhookRepoStub{}
+	svc := NewWebhookService(repo, client)
+	svc.Retries = 2
+	svc.RetryDelay = 0
+	if _, err := svc.Register(context.Background(), target, "", nil, 0); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	svc.Publish(TaskEventDeleted, &models.Task{ID: 3})
+	svc.Wait()
+
+	if n := atomic.LoadInt32(&calls); n != 3 {
+		t.Fatalf("expected 3 attempts, got %d", n)
+	}
+	if len(repo.deadLetters) != 1 {
+		t.Fatalf("expected one dead letter, got %d", len(repo.deadLetters))
+	}
+	dl := repo.deadLetters[0]
+	if dl.EventType != TaskEventDeleted || dl.Attempts != 3 || dl.LastError == "" {
+		t.Fatalf("unexpected dead letter: %+v", dl)
+	}
+}
+
+func TestWebhookService_RegisterValidates(t *testing.T) {
+	svc := NewWebhookService(&webhookRepoStub{}, nil)
+	ctx := context.Background()
+
+	for _, raw := range []string{
+		"ftp://example.com/hook",
+		"http://localhost:8080/hook",
+		"http://127.0.0.1/hook",
+		"http://10.0.0.5/hook",
+		"http://192.168.1.10/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hook",
+		"http://0.0.0.0/hook",
+	} {
+		if _, err := svc.Register(ctx, raw, "", nil, 1); !errors.Is(err, ErrInvalidWebhookURL) {
+			t.Fatalf("%s: expected ErrInvalidWebhookURL, got %v", raw, err)
+		}
+	}
+	if _, err := svc.Register(ctx, "https://example.com/hook", "", []string{"deal.created"}, 1); !errors.Is(err, ErrUnknownWebhookEvent) {
+		t.Fatalf("expected ErrUnknownWebhookEvent, got %v", err)
+	}
+
+	sub, err := svc.Register(ctx, "https://example.com/hook", "", []string{TaskEventAssigned, TaskEventAssigned}, 1)
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if sub.Secret == "" {
+		t.Fatal("expected a generated secret")
+	}
+	if len(sub.EventTypes) != 1 {
+		t.Fatalf("expected duplicate events to collapse, got %v", sub.EventTypes)
+	}
+
+	subs, err := svc.List(ctx)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(subs) != 1 || subs[0].Secret != "" {
+		t.Fatalf("list must hide secrets: %+v", subs)
+	}
+	if err := svc.Delete(ctx, 42); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestWebhookService_DoesNotFollowRedirects(t *testing.T) {
+	var calls, followed int32
+	target, client := webhookTestTarget(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/internal" {
+			atomic.AddInt32(&followed, 1)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		atomic.AddInt32(&calls, 1)
+		http.Redirect(w, r, "/internal", http.StatusFound)
+	}))
+
+	repo := &webhookRepoStub{}
+	svc := NewWebhookService(repo, client)
+	svc.Retries = 0
+	if _, err := svc.Register(context.Background(), target+"/hook", "", nil, 0); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	svc.Publish(TaskEventCreated, &models.Task{ID: 1})
+	if err := svc.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	if atomic.LoadInt32(&calls) != 1 || atomic.LoadInt32(&followed) != 0 {
+		t.Fatalf("redirect must not be followed: calls=%d followed=%d", calls, followed)
+	}
+	if len(repo.deadLetters) != 1 {
+		t.Fatalf("a redirect is a failed delivery, got %d dead letters", len(repo.deadLetters))
+	}
+}
+
+func TestWebhookService_DefaultClientRefusesInternalAddresses(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	svc := NewWebhookService(&webhookRepoStub{}, nil)
+	// A name resolving to loopback passes Register; the dialer still refuses
+	// the address, as it does for the raw loopback URL here.
+	resp, err := svc.client.Post(srv.URL+"/hook", "application/json", nil)
+	if err == nil {
+		resp.Body.Close()
+	}
+	if err == nil || atomic.LoadInt32(&calls) != 0 {
+		t.Fatalf("expected the internal target to be refused, err=%v calls=%d", err, calls)
+	}
+}
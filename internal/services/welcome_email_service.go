@@ -0,0 +1,90 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"turcompany/internal/models"
+)
+
+var ErrWelcomeResendThrottled = errors.New("welcome email resend throttled")
+
+// DefaultWelcomeResendInterval is the minimum time between two resends of
+// the welcome email to the same user.
+const DefaultWelcomeResendInterval = 5 * time.Minute
+
+type welcomeUserLookup interface {
+	GetByID(id int) (*models.User, error)
+}
+
+// WelcomeEmailService resends the welcome email that user creation sends
+// once, for when the original bounced or was lost. Resends are throttled
+// per user in memory.
+type WelcomeEmailService struct {
+	users    welcomeUserLookup
+	emails   EmailService
+	Interval time.Duration
+	now      func() time.Time
+
+	mu       sync.Mutex
+	lastSent map[int]time.Time
+}
+
+func NewWelcomeEmailService(users welcomeUserLookup, emails EmailService, now func() time.Time) *WelcomeEmailService {
+	if now == nil {
+		now = time.Now
+	}
+	return &WelcomeEmailService{
+		users:    users,
+		emails:   emails,
+		Interval: DefaultWelcomeResendInterval,
+		now:      now,
+		lastSent: map[int]time.Time{},
+	}
+}
+
+// Resend queues the welcome email for userID and returns once it is
+// accepted; the email itself is sent in the background and failures are
+// only logged. A second call within Interval returns
+// ErrWelcomeResendThrottled.
+func (s *WelcomeEmailService) Resend(userID int) error {
+	if s.emails == nil {
+		return fmt.Errorf("email service is not configured")
+	}
+	user, err := s.users.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrNotFound
+	}
+	email := strings.TrimSpace(user.Email)
+	if email == "" {
+		return ErrInvalidEmail
+	}
+	if !s.allow(userID) {
+		return ErrWelcomeResendThrottled
+	}
+	name := welcomeName(user)
+	go func() {
+		if err := s.emails.SendWelcomeEmail(email, name); err != nil {
+			log.Printf("[welcome][resend] send to user=%d failed: %v", userID, err)
+		}
+	}()
+	return nil
+}
+
+func (s *WelcomeEmailService) allow(userID int) bool {
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if last, ok := s.lastSent[userID]; ok && now.Sub(last) < s.Interval {
+		return false
+	}
+	s.lastSent[userID] = now
+	return true
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+)
+
+var (
+	allowedRedirectMu      sync.RWMutex
+	allowedRedirectOrigins = map[string]struct{}{}
+)
+
+// SetAllowedRedirectOrigins replaces the allowlist used by IsAllowedRedirect.
+// Entries are origins ("https://kubcrm.kz", "http://localhost:3000"); any path
+// part is ignored. Invalid entries are skipped.
+func SetAllowedRedirectOrigins(origins []string) {
+	next := make(map[string]struct{}, len(origins))
+	for _, raw := range origins {
+		if origin, ok := redirectOrigin(raw); ok {
+			next[origin] = struct{}{}
+		}
+	}
+	allowedRedirectMu.Lock()
+	allowedRedirectOrigins = next
+	allowedRedirectMu.Unlock()
+}
+
+// IsAllowedRedirect reports whether raw is safe to embed in an email or to
+// honor as a return URL: either a same-origin path ("/reset-password?...") or
+// an absolute http(s) URL whose origin is on the configured allowlist.
+func IsAllowedRedirect(raw string) bool {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || strings.ContainsAny(raw, "\\\r\n\t") {
+		return false
+	}
+	if strings.HasPrefix(raw, "/") {
+		// "//evil.com" is protocol-relative and leaves the origin.
+		return !strings.HasPrefix(raw, "//")
+	}
+	origin, ok := redirectOrigin(raw)
+	if !ok {
+		return false
+	}
+	allowedRedirectMu.RLock()
+	defer allowedRedirectMu.RUnlock()
+	_, allowed := allowedRedirectOrigins[origin]
+	return allowed
+}
+
+func redirectOrigin(raw string) (string, bool) {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || parsed.Host == "" || parsed.User != nil {
+		return "", false
+	}
+	scheme := strings.ToLower(parsed.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return "", false
+	}
+	return scheme + "://" + strings.ToLower(parsed.Host), true
+}
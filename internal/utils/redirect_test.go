@@ -0,0 +1,29 @@
+package utils
+
+import "testing"
+
+func TestIsAllowedRedirect(t *testing.T) {
+	SetAllowedRedirectOrigins([]string{"https://kubcrm.kz", "http://localhost:3000/", "not a url"})
+	t.Cleanup(func() { SetAllowedRedirectOrigins(nil) })
+
+	cases := map[string]bool{
+		"https://kubcrm.kz/reset-password?token=abc": true,
+		"HTTPS://KUBCRM.KZ/":                         true,
+		"http://localhost:3000/sign":                 true,
+		"/reset-password?token=abc":                  true,
+		"":                                           false,
+		"https://evil.com/reset-password":            false,
+		"https://kubcrm.kz.evil.com/":                false,
+		"https://kubcrm.kz@evil.com/":                false,
+		"http://kubcrm.kz/":                          false,
+		"//evil.com/path":                            false,
+		"/\\evil.com":                                false,
+		"javascript:alert(1)":                        false,
+		"https://localhost:3001/":                    false,
+	}
+	for raw, want := range cases {
+		if got := IsAllowedRedirect(raw); got != want {
+			t.Errorf("IsAllowedRedirect(%q) = %v, want %v", raw, got, want)
+		}
+	}
+}